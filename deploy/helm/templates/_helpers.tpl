@@ -58,3 +58,88 @@ Create the name of the service account to use.
 {{- default "default" .Values.serviceAccount.name }}
 {{- end }}
 {{- end }}
+
+{{/*
+Environment of the metrics endpoints, shared by the operator and the
+compliance workers.
+*/}}
+{{- define "audicia.metricsEnv" -}}
+{{- with .Values.operator.metrics }}
+{{- if .secure }}
+- name: METRICS_SECURE
+  value: "true"
+{{- end }}
+{{- if .clientCASecretName }}
+- name: METRICS_CLIENT_CA_FILE
+  value: /etc/audicia/metrics-client-ca/ca.crt
+{{- end }}
+{{- if .tokenSecretName }}
+- name: METRICS_TOKEN_FILE
+  value: /etc/audicia/metrics-token/token
+{{- end }}
+{{- if .reportPort }}
+- name: REPORT_METRICS_BIND_ADDRESS
+  value: {{ printf ":%v" .reportPort | quote }}
+{{- end }}
+{{- end }}
+{{- end }}
+
+{{/*
+Container ports of the metrics endpoints.
+*/}}
+{{- define "audicia.metricsPorts" -}}
+- name: metrics
+  containerPort: 8080
+  protocol: TCP
+{{- if .Values.operator.metrics.reportPort }}
+- name: report-metrics
+  containerPort: {{ .Values.operator.metrics.reportPort }}
+  protocol: TCP
+{{- end }}
+{{- end }}
+
+{{/*
+Volume mounts of the metrics endpoints' certificate, client CA and token.
+*/}}
+{{- define "audicia.metricsVolumeMounts" -}}
+{{- with .Values.operator.metrics }}
+{{- if .tlsSecretName }}
+- name: metrics-tls
+  mountPath: /etc/audicia/metrics-tls
+  readOnly: true
+{{- end }}
+{{- if .clientCASecretName }}
+- name: metrics-client-ca
+  mountPath: /etc/audicia/metrics-client-ca
+  readOnly: true
+{{- end }}
+{{- if .tokenSecretName }}
+- name: metrics-token
+  mountPath: /etc/audicia/metrics-token
+  readOnly: true
+{{- end }}
+{{- end }}
+{{- end }}
+
+{{/*
+Volumes of the metrics endpoints' certificate, client CA and token.
+*/}}
+{{- define "audicia.metricsVolumes" -}}
+{{- with .Values.operator.metrics }}
+{{- if .tlsSecretName }}
+- name: metrics-tls
+  secret:
+    secretName: {{ .tlsSecretName }}
+{{- end }}
+{{- if .clientCASecretName }}
+- name: metrics-client-ca
+  secret:
+    secretName: {{ .clientCASecretName }}
+{{- end }}
+{{- if .tokenSecretName }}
+- name: metrics-token
+  secret:
+    secretName: {{ .tokenSecretName }}
+{{- end }}
+{{- end }}
+{{- end }}
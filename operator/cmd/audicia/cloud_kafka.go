@@ -0,0 +1,8 @@
+//go:build kafka
+
+package main
+
+// Register the Kafka adapter. The init() function in the kafka package
+// calls cloud.RegisterAdapter(), making the Kafka provider available to the
+// cloud ingestor.
+import _ "github.com/felixnotka/audicia/operator/pkg/ingestor/cloud/kafka"
@@ -2,13 +2,24 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
+	// Embedded zone data for filter time windows; the image has none.
+	_ "time/tzdata"
 
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/felixnotka/audicia/operator/pkg/agent"
+	"github.com/felixnotka/audicia/operator/pkg/cli"
+	"github.com/felixnotka/audicia/operator/pkg/controller/audiciasource"
 	"github.com/felixnotka/audicia/operator/pkg/operator"
 )
 
@@ -29,6 +40,40 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
+	// CLI subcommands (export/apply/rules/limits/install) operate on the cluster and exit.
+	if len(os.Args) > 1 && cli.IsSubcommand(os.Args[1]) {
+		if err := cli.Run(ctx, os.Args[1:], os.Stdout, cli.DefaultClient); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Agent mode runs only the ingestion pipeline and forwards summaries to a
+	// central operator; it starts no controller manager.
+	if len(os.Args) > 1 && os.Args[1] == "agent" {
+		if err := runAgent(ctx, os.Args[2:]); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Load-test mode runs a pipeline on a synthetic source against an
+	// in-memory API server and prints resource usage for sizing. It is not
+	// listed in the usage; see docs/guides/sizing.md.
+	if len(os.Args) > 1 && os.Args[1] == "--load-test" {
+		sustained, err := runLoadTest(ctx, os.Args[2:])
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		if !sustained {
+			os.Exit(2)
+		}
+		return
+	}
+
 	buildInfo := operator.BuildInfo{
 		Version: version,
 		Commit:  commit,
@@ -43,17 +88,86 @@ func main() {
 	}
 }
 
+func runAgent(ctx context.Context, args []string) error {
+	config, err := agent.ParseFlags(args, os.Stderr)
+	if err != nil {
+		return err
+	}
+	ctrl.SetLogger(zap.New(zap.UseDevMode(envInt("LOG_LEVEL", 0) > 0)))
+	a, err := agent.New(config)
+	if err != nil {
+		return err
+	}
+	return a.Run(ctx)
+}
+
+func runLoadTest(ctx context.Context, args []string) (bool, error) {
+	fs := flag.NewFlagSet("audicia --load-test", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	opts := audiciasource.LoadTestOptions{}
+	fs.IntVar(&opts.Rate, "rate", 1000, "synthetic audit events per second")
+	fs.DurationVar(&opts.Duration, "duration", 2*time.Minute, "how long to generate events, including the warmup")
+	fs.DurationVar(&opts.Warmup, "warmup", 30*time.Second, "initial period left out of the steady-state summary")
+	fs.DurationVar(&opts.SampleInterval, "interval", 10*time.Second, "interval of the progress lines")
+	fs.IntVar(&opts.Subjects, "subjects", 200, "number of distinct subjects the events are spread over")
+	fs.IntVar(&opts.Namespaces, "namespaces", 20, "number of namespaces the subjects act in")
+	fs.DurationVar(&opts.FlushInterval, "flush-interval", 30*time.Second, "checkpoint interval at which reports are flushed")
+	fs.IntVar(&opts.PipelineWorkers, "workers", envInt("PIPELINE_WORKERS", 1), "event workers of the pipeline")
+	fs.IntVar(&opts.FlushConcurrency, "flush-concurrency", envInt("FLUSH_CONCURRENCY", 4), "subjects flushed in parallel")
+	fs.IntVar(&opts.FlushQPS, "flush-qps", envInt("FLUSH_QPS", 20), "subject flushes per second, 0 for no limit")
+	if err := fs.Parse(args); err != nil {
+		return false, err
+	}
+
+	if envInt("LOG_LEVEL", 0) > 0 {
+		ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+	} else {
+		ctrl.SetLogger(logr.Discard())
+	}
+	res, err := audiciasource.RunLoadTest(ctx, opts, os.Stdout)
+	if err != nil {
+		return false, err
+	}
+	return res.Sustained(), nil
+}
+
 // loadConfig reads operator configuration from environment variables with defaults.
 func loadConfig() operator.Config {
 	return operator.Config{
-		MetricsBindAddress:      envString("METRICS_BIND_ADDRESS", ":8080"),
-		HealthProbeBindAddress:  envString("HEALTH_PROBE_BIND_ADDRESS", ":8081"),
-		LeaderElectionEnabled:   envBool("LEADER_ELECTION_ENABLED", true),
-		LeaderElectionID:        envString("LEADER_ELECTION_ID", "audicia-operator-lock"),
-		LeaderElectionNamespace: envString("LEADER_ELECTION_NAMESPACE", "audicia-system"),
-		ConcurrentReconciles:    envInt("CONCURRENT_RECONCILES", 1),
-		LogLevel:                envInt("LOG_LEVEL", 0),
-		SyncPeriod:              envDuration("SYNC_PERIOD", 10*time.Minute),
+		MetricsBindAddress:       envString("METRICS_BIND_ADDRESS", ":8080"),
+		MetricsSecure:            envBool("METRICS_SECURE", false),
+		MetricsCertDir:           envString("METRICS_CERT_DIR", "/etc/audicia/metrics-tls"),
+		MetricsClientCAFile:      envString("METRICS_CLIENT_CA_FILE", ""),
+		MetricsTokenFile:         envString("METRICS_TOKEN_FILE", ""),
+		ReportMetricsBindAddress: envString("REPORT_METRICS_BIND_ADDRESS", ""),
+		HealthProbeBindAddress:   envString("HEALTH_PROBE_BIND_ADDRESS", ":8081"),
+		LeaderElectionEnabled:    envBool("LEADER_ELECTION_ENABLED", true),
+		LeaderElectionID:         envString("LEADER_ELECTION_ID", "audicia-operator-lock"),
+		LeaderElectionNamespace:  envString("LEADER_ELECTION_NAMESPACE", "audicia-system"),
+		ConcurrentReconciles:     envInt("CONCURRENT_RECONCILES", 1),
+		PipelineWorkers:          envInt("PIPELINE_WORKERS", 1),
+		FlushConcurrency:         envInt("FLUSH_CONCURRENCY", 4),
+		FlushQPS:                 envInt("FLUSH_QPS", 20),
+		StorageEstimateInterval:  envDuration("STORAGE_ESTIMATE_INTERVAL", 10*time.Minute),
+		VerbDiscoveryInterval:    envDuration("VERB_DISCOVERY_INTERVAL", 10*time.Minute),
+		AutodiscoveryConfigMap:   envString("AUTODISCOVERY_CONFIGMAP", ""),
+		PolicySinkGitURLs:        envList("POLICY_SINK_GIT_URLS"),
+		LogLevel:                 envInt("LOG_LEVEL", 0),
+		SyncPeriod:               envDuration("SYNC_PERIOD", 10*time.Minute),
+		Role:                     envString("OPERATOR_ROLE", operator.RoleAll),
+		ComplianceShards:         envInt("COMPLIANCE_SHARDS", 1),
+		ComplianceImplicitGroups: envBool("COMPLIANCE_IMPLICIT_GROUPS", true),
+		PodName:                  envString("POD_NAME", ""),
+		PolicyPlansEnabled:       envBool("POLICY_PLANS_ENABLED", false),
+		LocalIngestionEnabled:    envBool("LOCAL_INGESTION_ENABLED", false),
+		NotifyWebhookURL:         envString("NOTIFY_WEBHOOK_URL", ""),
+		NotifySlackWebhookURL:    envString("NOTIFY_SLACK_WEBHOOK_URL", ""),
+		RuleStreamBindAddress:    envString("RULE_STREAM_BIND_ADDRESS", ""),
+		RuleStreamCertDir:        envString("RULE_STREAM_CERT_DIR", "/etc/audicia/rulestream-tls"),
+		RuleStreamTokenFile:      envString("RULE_STREAM_TOKEN_FILE", "/etc/audicia/rulestream-token/token"),
+		EventTapDir:              envString("EVENT_TAP_DIR", ""),
+		EventTapMaxBytes:         envInt("EVENT_TAP_MAX_BYTES", 100<<20),
+		EventTapMaxFiles:         envInt("EVENT_TAP_MAX_FILES", 10),
 	}
 }
 
@@ -64,6 +178,17 @@ func envString(key, defaultVal string) string {
 	return defaultVal
 }
 
+// envList returns the comma-separated, non-empty values of key.
+func envList(key string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
 func envBool(key string, defaultVal bool) bool {
 	if v := os.Getenv(key); v != "" {
 		b, err := strconv.ParseBool(v)
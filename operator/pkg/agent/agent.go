@@ -0,0 +1,413 @@
+// Package agent implements `audicia agent`, a lightweight mode for edge
+// clusters. It tails the audit log without a controller manager, CRDs or
+// Kubernetes API access, folds the events into summaries, and forwards them
+// to a Webhook AudiciaSource of a central operator over HTTPS. Each summary
+// is an audit event carrying the number of events it stands for in
+// EventCountAnnotation, so the central pipeline filters, normalizes and
+// counts it like the events themselves.
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/felixnotka/audicia/operator/pkg/ingestor"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+)
+
+// EventCountAnnotation is the audit annotation in which a summary carries
+// the number of events it stands for.
+const EventCountAnnotation = "audicia.io/event-count"
+
+// MaxEventCount is the largest EventCountAnnotation the central pipeline
+// honors; larger counts are clamped to it.
+const MaxEventCount = 1000000
+
+// authorizationDecisionAnnotation is the only audit annotation the central
+// pipeline reads; summaries keep it and drop the others.
+const authorizationDecisionAnnotation = "authorization.k8s.io/decision"
+
+var log = ctrl.Log.WithName("agent")
+
+// Config configures an agent.
+type Config struct {
+	// AuditLogPath is the kube-apiserver audit log to tail.
+	AuditLogPath string
+
+	// RotatedFilePattern is a glob for rotated copies of AuditLogPath,
+	// whose unread tail is read before the new file.
+	RotatedFilePattern string
+
+	// CheckpointFile persists the audit log position after each successful
+	// forward, so a restarted agent resumes instead of re-reading the log.
+	// Empty keeps the position in memory only.
+	CheckpointFile string
+
+	// URL is the central Webhook source's endpoint.
+	URL string
+
+	// TokenFile holds the bearer token for the source's authTokenSecretName.
+	// It is re-read on every forward, so a rotated token takes effect
+	// without a restart.
+	TokenFile string
+
+	// CAFile verifies the central operator's certificate. Empty uses the
+	// system roots.
+	CAFile string
+
+	// CertFile and KeyFile are a client certificate for sources verifying
+	// clients with clientCASecretName.
+	CertFile string
+	KeyFile  string
+
+	// Interval is the time between forwards.
+	Interval time.Duration
+
+	// BatchSize is the maximum number of summaries per request, to stay
+	// below the source's maxRequestBodyBytes.
+	BatchSize int
+
+	// MaxSummaries forwards early once this many distinct summaries are
+	// pending, bounding the agent's memory. A summary reaching MaxEventCount
+	// also forwards early, as the central pipeline counts no more.
+	MaxSummaries int
+
+	// IgnoreSystemUsers drops events of system:* users other than service
+	// accounts before they are summarized.
+	IgnoreSystemUsers bool
+}
+
+// summaryKey identifies the events one summary stands for: the fields the
+// central pipeline reads, with the request URI reduced to its path and the
+// dryRun parameter, so list and watch queries don't split summaries.
+type summaryKey struct {
+	stage       auditv1.Stage
+	username    string
+	groups      string
+	verb        string
+	requestURI  string
+	apiGroup    string
+	apiVersion  string
+	resource    string
+	subresource string
+	namespace   string
+	name        string
+	code        int32
+	message     string
+	decision    string
+}
+
+// summary is a pending summary.
+type summary struct {
+	groups   []string
+	count    int64
+	lastSeen time.Time
+}
+
+// Agent folds audit events into summaries and forwards them.
+type Agent struct {
+	config  Config
+	client  *http.Client
+	pending map[summaryKey]*summary
+}
+
+// New returns an agent for config.
+func New(config Config) (*Agent, error) {
+	if config.AuditLogPath == "" || config.URL == "" {
+		return nil, errors.New("audit log path and central URL are required")
+	}
+	if config.Interval <= 0 {
+		config.Interval = 30 * time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 500
+	}
+	if config.MaxSummaries <= 0 {
+		config.MaxSummaries = 50000
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.CAFile != "" {
+		data, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates in %s", config.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if config.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return &Agent{
+		config: config,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		pending: make(map[summaryKey]*summary),
+	}, nil
+}
+
+// Run tails the audit log and forwards summaries until ctx is cancelled.
+// Summaries that fail to forward are kept and retried with the next ones.
+func (a *Agent) Run(ctx context.Context) error {
+	start, err := a.loadCheckpoint()
+	if err != nil {
+		return err
+	}
+	ing := ingestor.NewFileIngestor(a.config.AuditLogPath, start, 0)
+	ing.RotatedFilePattern = a.config.RotatedFilePattern
+	events, err := ing.Start(ctx)
+	if err != nil {
+		return fmt.Errorf("starting audit log ingestion: %w", err)
+	}
+	log.Info("agent started", "path", a.config.AuditLogPath, "url", a.config.URL)
+
+	ticker := time.NewTicker(a.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if a.add(event) < MaxEventCount && len(a.pending) < a.config.MaxSummaries {
+				continue
+			}
+		case <-ticker.C:
+		}
+		a.flush(ctx, ing.Checkpoint())
+	}
+}
+
+// flush forwards the pending summaries and, once all were accepted,
+// persists pos.
+func (a *Agent) flush(ctx context.Context, pos ingestor.Position) {
+	if len(a.pending) == 0 {
+		return
+	}
+	sent, err := a.forward(ctx)
+	if err != nil {
+		log.Error(err, "failed to forward summaries", "pending", len(a.pending), "sent", sent)
+		return
+	}
+	if err := a.saveCheckpoint(pos); err != nil {
+		log.Error(err, "failed to save checkpoint")
+	}
+}
+
+// add folds event into its summary and returns the summary's count, or 0 if
+// the event was dropped. Events whose stage the central pipeline drops by
+// default (RequestReceived) and, with IgnoreSystemUsers, events of system
+// users are dropped here already.
+func (a *Agent) add(event auditv1.Event) int64 {
+	if event.Stage == auditv1.StageRequestReceived {
+		return 0
+	}
+	if a.config.IgnoreSystemUsers {
+		if _, include := normalizer.NormalizeSubject(event.User.Username, true); !include {
+			return 0
+		}
+	}
+	key := keyFor(event)
+	s := a.pending[key]
+	if s == nil {
+		s = &summary{groups: event.User.Groups}
+		a.pending[key] = s
+	}
+	s.count++
+	if t := event.RequestReceivedTimestamp.Time; t.After(s.lastSeen) {
+		s.lastSeen = t
+	}
+	return s.count
+}
+
+func keyFor(event auditv1.Event) summaryKey {
+	groups := slices.Clone(event.User.Groups)
+	slices.Sort(groups)
+	key := summaryKey{
+		stage:      event.Stage,
+		username:   event.User.Username,
+		groups:     strings.Join(groups, "\n"),
+		verb:       event.Verb,
+		requestURI: trimRequestURI(event.RequestURI),
+		decision:   event.Annotations[authorizationDecisionAnnotation],
+	}
+	if ref := event.ObjectRef; ref != nil {
+		key.apiGroup, key.apiVersion = ref.APIGroup, ref.APIVersion
+		key.resource, key.subresource = ref.Resource, ref.Subresource
+		key.namespace, key.name = ref.Namespace, ref.Name
+	}
+	if status := event.ResponseStatus; status != nil {
+		key.code = status.Code
+		// Only failures are classified by their message.
+		if status.Code >= http.StatusBadRequest {
+			key.message = status.Message
+		}
+	}
+	return key
+}
+
+// trimRequestURI keeps the path of uri and its dryRun parameter.
+func trimRequestURI(uri string) string {
+	path, query, _ := strings.Cut(uri, "?")
+	for query != "" {
+		var param string
+		param, query, _ = strings.Cut(query, "&")
+		if strings.HasPrefix(param, "dryRun=") {
+			return path + "?" + param
+		}
+	}
+	return path
+}
+
+// forward sends the pending summaries in batches, removing each accepted
+// batch. It returns the number of summaries sent.
+func (a *Agent) forward(ctx context.Context) (int, error) {
+	token := ""
+	if a.config.TokenFile != "" {
+		data, err := os.ReadFile(a.config.TokenFile)
+		if err != nil {
+			return 0, fmt.Errorf("reading token: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	keys := make([]summaryKey, 0, len(a.pending))
+	for k := range a.pending {
+		keys = append(keys, k)
+	}
+	sent := 0
+	for batch := range slices.Chunk(keys, a.config.BatchSize) {
+		list := auditv1.EventList{TypeMeta: metav1.TypeMeta{APIVersion: "audit.k8s.io/v1", Kind: "EventList"}}
+		for _, k := range batch {
+			list.Items = append(list.Items, summaryEvent(k, a.pending[k]))
+		}
+		if err := a.post(ctx, token, list); err != nil {
+			return sent, err
+		}
+		for _, k := range batch {
+			delete(a.pending, k)
+		}
+		sent += len(batch)
+	}
+	return sent, nil
+}
+
+// summaryEvent renders a summary as the audit event it stands for.
+func summaryEvent(k summaryKey, s *summary) auditv1.Event {
+	event := auditv1.Event{
+		TypeMeta:                 metav1.TypeMeta{APIVersion: "audit.k8s.io/v1", Kind: "Event"},
+		Level:                    auditv1.LevelMetadata,
+		AuditID:                  types.UID(uuid.NewUUID()),
+		Stage:                    k.stage,
+		RequestURI:               k.requestURI,
+		Verb:                     k.verb,
+		RequestReceivedTimestamp: metav1.NewMicroTime(s.lastSeen),
+		StageTimestamp:           metav1.NewMicroTime(s.lastSeen),
+		Annotations:              map[string]string{EventCountAnnotation: strconv.FormatInt(s.count, 10)},
+	}
+	event.User.Username = k.username
+	event.User.Groups = s.groups
+	if k.decision != "" {
+		event.Annotations[authorizationDecisionAnnotation] = k.decision
+	}
+	if k.resource != "" {
+		event.ObjectRef = &auditv1.ObjectReference{
+			APIGroup:    k.apiGroup,
+			APIVersion:  k.apiVersion,
+			Resource:    k.resource,
+			Subresource: k.subresource,
+			Namespace:   k.namespace,
+			Name:        k.name,
+		}
+	}
+	if k.code != 0 {
+		event.ResponseStatus = &metav1.Status{Code: k.code, Message: k.message}
+	}
+	return event
+}
+
+func (a *Agent) post(ctx context.Context, token string, list auditv1.EventList) error {
+	body, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("central operator responded %s", resp.Status)
+	}
+	return nil
+}
+
+func (a *Agent) loadCheckpoint() (ingestor.Position, error) {
+	var pos ingestor.Position
+	if a.config.CheckpointFile == "" {
+		return pos, nil
+	}
+	data, err := os.ReadFile(a.config.CheckpointFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return pos, nil
+	}
+	if err != nil {
+		return pos, fmt.Errorf("reading checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, &pos); err != nil {
+		return pos, fmt.Errorf("parsing checkpoint %s: %w", a.config.CheckpointFile, err)
+	}
+	return pos, nil
+}
+
+// saveCheckpoint writes pos through a temporary file, so a crash never
+// leaves a truncated checkpoint.
+func (a *Agent) saveCheckpoint(pos ingestor.Position) error {
+	if a.config.CheckpointFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(pos)
+	if err != nil {
+		return err
+	}
+	tmp := a.config.CheckpointFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, a.config.CheckpointFile)
+}
@@ -0,0 +1,129 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	"github.com/felixnotka/audicia/operator/pkg/ingestor"
+)
+
+func listEvent(user, uri string, at time.Time) auditv1.Event {
+	return auditv1.Event{
+		Stage:                    auditv1.StageResponseComplete,
+		Verb:                     "list",
+		RequestURI:               uri,
+		User:                     authnv1.UserInfo{Username: user, Groups: []string{"b", "a"}},
+		ObjectRef:                &auditv1.ObjectReference{Resource: "pods", Namespace: "default", APIVersion: "v1"},
+		ResponseStatus:           &metav1.Status{Code: 200},
+		RequestReceivedTimestamp: metav1.NewMicroTime(at),
+	}
+}
+
+func newTestAgent(t *testing.T, url string) *Agent {
+	t.Helper()
+	a, err := New(Config{AuditLogPath: "audit.log", URL: url, BatchSize: 1, IgnoreSystemUsers: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestAdd_FoldsEventsIntoSummaries(t *testing.T) {
+	a := newTestAgent(t, "https://central")
+	t0 := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	a.add(listEvent("alice", "/api/v1/namespaces/default/pods?labelSelector=app%3Dx", t0))
+	a.add(listEvent("alice", "/api/v1/namespaces/default/pods?limit=500", t0.Add(time.Minute)))
+	a.add(listEvent("alice", "/api/v1/namespaces/default/pods?dryRun=All", t0))
+	a.add(listEvent("bob", "/api/v1/namespaces/default/pods", t0))
+	a.add(listEvent("system:kube-scheduler", "/api/v1/namespaces/default/pods", t0))
+	received := listEvent("alice", "/api/v1/namespaces/default/pods", t0)
+	received.Stage = auditv1.StageRequestReceived
+	a.add(received)
+
+	if len(a.pending) != 3 {
+		t.Fatalf("got %d summaries, want 3 (alice, alice dry run, bob)", len(a.pending))
+	}
+	key := keyFor(listEvent("alice", "/api/v1/namespaces/default/pods", t0))
+	s := a.pending[key]
+	if s == nil || s.count != 2 || !s.lastSeen.Equal(t0.Add(time.Minute)) {
+		t.Fatalf("alice summary = %+v, want count 2 last seen at the later event", s)
+	}
+
+	event := summaryEvent(key, s)
+	if event.Annotations[EventCountAnnotation] != "2" || event.RequestURI != "/api/v1/namespaces/default/pods" ||
+		event.ObjectRef.Resource != "pods" || event.User.Username != "alice" || len(event.User.Groups) != 2 {
+		t.Errorf("summary event = %+v", event)
+	}
+}
+
+func TestForward_SendsBatchesWithToken(t *testing.T) {
+	var batches [][]auditv1.Event
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var list auditv1.EventList
+		if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		batches = append(batches, list.Items)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a := newTestAgent(t, server.URL)
+	a.client = server.Client()
+	a.config.TokenFile = tokenFile
+	a.config.CheckpointFile = filepath.Join(dir, "checkpoint")
+
+	a.add(listEvent("alice", "/api/v1/namespaces/default/pods", time.Now()))
+	a.add(listEvent("bob", "/api/v1/namespaces/default/pods", time.Now()))
+	a.flush(context.Background(), ingestor.Position{FileOffset: 42, Inode: 7})
+
+	if len(batches) != 2 || len(batches[0]) != 1 {
+		t.Fatalf("got batches %v, want 2 of 1 summary", batches)
+	}
+	if len(a.pending) != 0 {
+		t.Errorf("%d summaries still pending after a successful forward", len(a.pending))
+	}
+	pos, err := a.loadCheckpoint()
+	if err != nil || pos.FileOffset != 42 || pos.Inode != 7 {
+		t.Errorf("checkpoint = %+v, %v", pos, err)
+	}
+
+	// A rejected forward keeps the summaries and the old checkpoint.
+	if err := os.WriteFile(tokenFile, []byte("wrong"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a.add(listEvent("alice", "/api/v1/namespaces/default/pods", time.Now()))
+	a.flush(context.Background(), ingestor.Position{FileOffset: 99})
+	if len(a.pending) != 1 {
+		t.Errorf("%d summaries pending after a failed forward, want 1", len(a.pending))
+	}
+	if pos, _ := a.loadCheckpoint(); pos.FileOffset != 42 {
+		t.Errorf("checkpoint advanced to %d after a failed forward", pos.FileOffset)
+	}
+}
+
+func TestNew_RequiresPathAndURL(t *testing.T) {
+	if _, err := New(Config{AuditLogPath: "audit.log"}); err == nil {
+		t.Error("expected an error without a URL")
+	}
+}
@@ -0,0 +1,28 @@
+package agent
+
+import (
+	"flag"
+	"io"
+	"time"
+)
+
+// ParseFlags parses the flags of `audicia agent` into a Config.
+func ParseFlags(args []string, output io.Writer) (Config, error) {
+	var c Config
+	fs := flag.NewFlagSet("audicia agent", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&c.AuditLogPath, "audit-log", "/var/log/kubernetes/audit/audit.log", "kube-apiserver audit log to tail.")
+	fs.StringVar(&c.RotatedFilePattern, "rotated-files", "", "Glob for rotated copies of the audit log, read before the new file.")
+	fs.StringVar(&c.CheckpointFile, "checkpoint-file", "", "File persisting the audit log position across restarts (default: in memory).")
+	fs.StringVar(&c.URL, "url", "", "Endpoint of the central operator's Webhook source (required).")
+	fs.StringVar(&c.TokenFile, "token-file", "", "File with the bearer token of the source's authTokenSecretName.")
+	fs.StringVar(&c.CAFile, "ca-file", "", "CA bundle verifying the central operator (default: system roots).")
+	fs.StringVar(&c.CertFile, "cert-file", "", "Client certificate for sources with clientCASecretName.")
+	fs.StringVar(&c.KeyFile, "key-file", "", "Key of -cert-file.")
+	fs.DurationVar(&c.Interval, "interval", 30*time.Second, "Time between forwards.")
+	fs.IntVar(&c.BatchSize, "batch-size", 500, "Maximum summaries per request.")
+	fs.IntVar(&c.MaxSummaries, "max-summaries", 50000, "Forward early once this many distinct summaries are pending.")
+	fs.BoolVar(&c.IgnoreSystemUsers, "ignore-system-users", true, "Drop events of system:* users other than service accounts.")
+	err := fs.Parse(args)
+	return c, err
+}
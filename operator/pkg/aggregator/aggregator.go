@@ -1,6 +1,7 @@
 package aggregator
 
 import (
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -10,6 +11,20 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// MaxResourceNames is the number of distinct object names tracked per rule.
+// A rule observed on more objects is treated as a collection-wide rule.
+const MaxResourceNames = 5
+
+// namedVerbs are the verbs RBAC can restrict with resourceNames. Requests
+// with other verbs (create, list, watch, deletecollection) are authorized
+// without a name, so their rules never carry one.
+var namedVerbs = map[string]bool{
+	"get":    true,
+	"update": true,
+	"patch":  true,
+	"delete": true,
+}
+
 // ruleKey is the deduplication key for observed rules.
 type ruleKey struct {
 	APIGroup       string
@@ -17,6 +32,7 @@ type ruleKey struct {
 	Verb           string
 	NonResourceURL string
 	Namespace      string
+	Preset         string
 }
 
 // Aggregator deduplicates and merges observed rules per subject.
@@ -24,45 +40,118 @@ type Aggregator struct {
 	mu    sync.RWMutex
 	rules map[ruleKey]*audiciav1alpha1.ObservedRule
 	count int64
+
+	// unnamed marks rules that were observed without a resource name or on
+	// too many names, so their ResourceNames stay empty for good.
+	unnamed map[ruleKey]bool
+
+	// denied aggregates denied requests apart from the observed rules. It
+	// is created on the first denied request.
+	denied *Aggregator
+
+	// byHour and byDay count events by the hour and weekday (Monday first)
+	// of their timestamp, in the timestamp's location.
+	byHour [24]int64
+	byDay  [7]int64
+
+	// touched holds the rules observed since the last Attribute call,
+	// mapped to whether Add created them.
+	touched map[ruleKey]bool
+
+	// version counts the observations added, denied ones included.
+	version uint64
 }
 
 // New creates a new Aggregator.
 func New() *Aggregator {
 	return &Aggregator{
-		rules: make(map[ruleKey]*audiciav1alpha1.ObservedRule),
+		rules:   make(map[ruleKey]*audiciav1alpha1.ObservedRule),
+		unnamed: make(map[ruleKey]bool),
+		touched: make(map[ruleKey]bool),
 	}
 }
 
 // Add records a canonical rule observation. For duplicate keys, Count is
-// incremented and LastSeen is unconditionally overwritten with the given
-// timestamp (callers are expected to supply events in chronological order).
+// incremented and FirstSeen and LastSeen widen to include the timestamp, so
+// events may arrive out of order, as when a backfill runs beside live
+// ingestion. Timestamps of rules marked TimestampFallback give way to the
+// first event timestamp (see mergeSeen). Denied rules are recorded apart,
+// see DeniedRules, and are not counted as processed events.
 func (a *Aggregator) Add(rule normalizer.CanonicalRule, timestamp time.Time) {
+	a.AddWeighted(rule, timestamp, 1)
+}
+
+// AddWeighted records a sampled observation standing for weight events, so
+// counts and activity estimate the full traffic.
+func (a *Aggregator) AddWeighted(rule normalizer.CanonicalRule, timestamp time.Time, weight int64) {
+	if rule.Denied {
+		a.mu.Lock()
+		if a.denied == nil {
+			a.denied = New()
+		}
+		denied := a.denied
+		a.version++
+		a.mu.Unlock()
+
+		rule.Denied = false
+		denied.AddWeighted(rule, timestamp, weight)
+		return
+	}
+
 	key := ruleKey{
 		APIGroup:       rule.APIGroup,
 		Resource:       rule.Resource,
 		Verb:           rule.Verb,
 		NonResourceURL: rule.NonResourceURL,
 		Namespace:      rule.Namespace,
+		Preset:         rule.Preset,
+	}
+	// Preset rules collapse all their verbs into a single entry.
+	preset := rule.Preset != ""
+	if preset {
+		key.Verb = ""
 	}
 
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	a.count++
+	a.version++
+	a.count += weight
+	a.byHour[timestamp.Hour()] += weight
+	a.byDay[(timestamp.Weekday()+6)%7] += weight
 	now := metav1.NewTime(timestamp)
 
 	if existing, ok := a.rules[key]; ok {
-		existing.Count++
-		existing.LastSeen = now
+		if _, ok := a.touched[key]; !ok {
+			a.touched[key] = false
+		}
+		existing.Count += weight
+		mergeSeen(existing, now, now, rule.TimestampFallback)
+		// One completed request is enough to drop the incomplete mark.
+		existing.Incomplete = existing.Incomplete && rule.Incomplete
+		if rule.AdmissionDenied {
+			existing.AdmissionDenied += weight
+		}
+		if preset {
+			existing.Verbs = addVerb(existing.Verbs, rule.Verb)
+		} else {
+			a.trackResourceName(key, existing, rule.ResourceName)
+		}
 		return
 	}
 
 	observed := &audiciav1alpha1.ObservedRule{
-		Verbs:     []string{rule.Verb},
-		Namespace: rule.Namespace,
-		FirstSeen: now,
-		LastSeen:  now,
-		Count:     1,
+		Verbs:      []string{rule.Verb},
+		Namespace:  rule.Namespace,
+		FirstSeen:  now,
+		LastSeen:   now,
+		Count:      weight,
+		Incomplete: rule.Incomplete,
+
+		TimestampFallback: rule.TimestampFallback,
+	}
+	if rule.AdmissionDenied {
+		observed.AdmissionDenied = weight
 	}
 
 	if rule.NonResourceURL != "" {
@@ -73,8 +162,163 @@ func (a *Aggregator) Add(rule normalizer.CanonicalRule, timestamp time.Time) {
 		observed.APIGroups = []string{rule.APIGroup}
 		observed.Resources = []string{rule.Resource}
 	}
+	if preset {
+		observed.Preset = rule.Preset
+	} else {
+		a.trackResourceName(key, observed, rule.ResourceName)
+	}
 
 	a.rules[key] = observed
+	a.touched[key] = true
+}
+
+// Seed merges previously observed rules, such as those of an imported report,
+// into the aggregator. Rules must be atomic (one API group, resource or URL,
+// and verb each), as produced by Rules; rules consolidated by
+// ConsolidateSubresources are split into their resources. A rule already
+// present keeps the earlier FirstSeen, the later LastSeen and the higher
+// Count, so seeding the same rules again changes nothing. Seeded rules do not
+// count as processed events.
+func (a *Aggregator) Seed(rules []audiciav1alpha1.ObservedRule) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, seeded := range rules {
+		for _, rule := range expandRule(seeded) {
+			a.seed(rule)
+		}
+	}
+}
+
+// seed merges one atomic rule. The caller holds a.mu.
+func (a *Aggregator) seed(rule audiciav1alpha1.ObservedRule) {
+	key := ruleKey{
+		APIGroup:       firstElem(rule.APIGroups),
+		Resource:       firstElem(rule.Resources),
+		Verb:           firstElem(rule.Verbs),
+		NonResourceURL: firstElem(rule.NonResourceURLs),
+		Namespace:      rule.Namespace,
+		Preset:         rule.Preset,
+	}
+	if rule.Preset != "" {
+		key.Verb = ""
+	}
+
+	existing, ok := a.rules[key]
+	if !ok {
+		seeded := rule
+		seeded.ResourceNames = slices.Clone(rule.ResourceNames)
+		if len(seeded.ResourceNames) == 0 {
+			a.unnamed[key] = true
+		}
+		a.rules[key] = &seeded
+		return
+	}
+	mergeSeen(existing, rule.FirstSeen, rule.LastSeen, rule.TimestampFallback)
+	for _, verb := range rule.Verbs {
+		existing.Verbs = addVerb(existing.Verbs, verb)
+	}
+	existing.Count = max(existing.Count, rule.Count)
+	existing.AdmissionDenied = max(existing.AdmissionDenied, rule.AdmissionDenied)
+	existing.Incomplete = existing.Incomplete && rule.Incomplete
+}
+
+// addVerb returns verbs, sorted, with verb added. A new slice is returned
+// when verb is added, as Rules hands out copies sharing the verbs.
+func addVerb(verbs []string, verb string) []string {
+	i, found := slices.BinarySearch(verbs, verb)
+	if found {
+		return verbs
+	}
+	return slices.Insert(slices.Clip(verbs), i, verb)
+}
+
+// mergeSeen widens the FirstSeen and LastSeen of rule to include first and
+// last. Times from event timestamps take precedence over fallback ones: a
+// fallback observation of a rule with event timestamps leaves its times
+// alone, and the first event timestamp of a fallback rule replaces them.
+func mergeSeen(rule *audiciav1alpha1.ObservedRule, first, last metav1.Time, fallback bool) {
+	switch {
+	case fallback && !rule.TimestampFallback:
+		return
+	case !fallback && rule.TimestampFallback:
+		rule.FirstSeen, rule.LastSeen, rule.TimestampFallback = first, last, false
+		return
+	}
+	if first.Before(&rule.FirstSeen) {
+		rule.FirstSeen = first
+	}
+	if rule.LastSeen.Before(&last) {
+		rule.LastSeen = last
+	}
+}
+
+// Attribute records span as the provenance of the rules observed since the
+// previous call: the last sighting of each, and the first of rules Add
+// created since. Seeded rules keep the first sighting they carry, if any.
+// Denied rules are attributed alike.
+func (a *Aggregator) Attribute(span audiciav1alpha1.CheckpointRange) {
+	a.mu.Lock()
+	for key, created := range a.touched {
+		rule := a.rules[key]
+		// Rules hands out copies sharing the provenance, so it is replaced
+		// rather than updated.
+		p := &audiciav1alpha1.RuleProvenance{LastSeen: span}
+		switch {
+		case rule.Provenance != nil:
+			p.FirstSeen = rule.Provenance.FirstSeen
+		case created:
+			first := span
+			p.FirstSeen = &first
+		}
+		rule.Provenance = p
+	}
+	clear(a.touched)
+	denied := a.denied
+	a.mu.Unlock()
+
+	if denied != nil {
+		denied.Attribute(span)
+	}
+}
+
+// SeedDenied merges previously recorded denied rules like Seed.
+func (a *Aggregator) SeedDenied(rules []audiciav1alpha1.ObservedRule) {
+	if len(rules) == 0 {
+		return
+	}
+	a.mu.Lock()
+	if a.denied == nil {
+		a.denied = New()
+	}
+	denied := a.denied
+	a.mu.Unlock()
+
+	denied.Seed(rules)
+}
+
+// trackResourceName records the object name of one observation. The rule's
+// names are dropped for good once an observation has no name or the rule has
+// been seen on more than MaxResourceNames objects.
+func (a *Aggregator) trackResourceName(key ruleKey, observed *audiciav1alpha1.ObservedRule, name string) {
+	if a.unnamed[key] {
+		return
+	}
+	if name == "" || !namedVerbs[key.Verb] {
+		a.unnamed[key] = true
+		observed.ResourceNames = nil
+		return
+	}
+	if slices.Contains(observed.ResourceNames, name) {
+		return
+	}
+	if len(observed.ResourceNames) >= MaxResourceNames {
+		a.unnamed[key] = true
+		observed.ResourceNames = nil
+		return
+	}
+	observed.ResourceNames = append(observed.ResourceNames, name)
+	sort.Strings(observed.ResourceNames)
 }
 
 // Rules returns the current aggregated rules as a deterministically sorted slice.
@@ -85,7 +329,9 @@ func (a *Aggregator) Rules() []audiciav1alpha1.ObservedRule {
 
 	result := make([]audiciav1alpha1.ObservedRule, 0, len(a.rules))
 	for _, rule := range a.rules {
-		result = append(result, *rule)
+		r := *rule
+		r.ResourceNames = slices.Clone(rule.ResourceNames)
+		result = append(result, r)
 	}
 
 	sort.Slice(result, func(i, j int) bool {
@@ -95,6 +341,19 @@ func (a *Aggregator) Rules() []audiciav1alpha1.ObservedRule {
 	return result
 }
 
+// DeniedRules returns the aggregated denied rules, sorted like Rules, or nil
+// if no request was denied.
+func (a *Aggregator) DeniedRules() []audiciav1alpha1.ObservedRule {
+	a.mu.RLock()
+	denied := a.denied
+	a.mu.RUnlock()
+
+	if denied == nil {
+		return nil
+	}
+	return denied.Rules()
+}
+
 // ruleIsLess compares two ObservedRules for deterministic sorting.
 // Order: Namespace, APIGroup, Resource, Verb.
 func ruleIsLess(a, b audiciav1alpha1.ObservedRule) bool {
@@ -118,6 +377,30 @@ func firstElem(s []string) string {
 	return ""
 }
 
+// Activity returns the events counted by hour of day and day of week, in the
+// location of the timestamps passed to Add, or nil before the first event.
+func (a *Aggregator) Activity() *audiciav1alpha1.ActivitySummary {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.count == 0 {
+		return nil
+	}
+	return &audiciav1alpha1.ActivitySummary{
+		ByHour: slices.Clone(a.byHour[:]),
+		ByDay:  slices.Clone(a.byDay[:]),
+	}
+}
+
+// Version returns a counter that advances with every observation added, so
+// callers can tell whether the aggregator changed since they last read it.
+// Seeding does not advance it.
+func (a *Aggregator) Version() uint64 {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.version
+}
+
 // EventsProcessed returns the total number of events aggregated.
 func (a *Aggregator) EventsProcessed() int64 {
 	a.mu.RLock()
@@ -1,12 +1,14 @@
 package aggregator
 
 import (
+	"slices"
 	"sync"
 	"testing"
 	"time"
 
 	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
 	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestNew(t *testing.T) {
@@ -22,6 +24,25 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestAddWeighted(t *testing.T) {
+	agg := New()
+	now := time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC)
+	rule := normalizer.CanonicalRule{Resource: "pods", Verb: "get", Namespace: "default"}
+	agg.AddWeighted(rule, now, 10)
+	agg.AddWeighted(rule, now.Add(time.Minute), 5)
+
+	rules := agg.Rules()
+	if len(rules) != 1 || rules[0].Count != 15 {
+		t.Fatalf("rules = %+v, want one rule with Count 15", rules)
+	}
+	if agg.EventsProcessed() != 15 {
+		t.Errorf("EventsProcessed() = %d, want 15", agg.EventsProcessed())
+	}
+	if got := agg.Activity().ByHour[14]; got != 15 {
+		t.Errorf("ByHour[14] = %d, want 15", got)
+	}
+}
+
 func TestAdd_SingleRule(t *testing.T) {
 	agg := New()
 	now := time.Now()
@@ -350,9 +371,334 @@ func TestAdd_LastSeen_UpdatesCorrectly(t *testing.T) {
 	agg.Add(rule, t2)
 
 	rules := agg.Rules()
-	// LastSeen should be the MOST RECENT timestamp passed to Add, not the
-	// chronologically latest — it always overwrites.
-	if !rules[0].LastSeen.Time.Equal(t2) {
-		t.Errorf("LastSeen = %v, want %v (always overwrites with latest Add call)", rules[0].LastSeen.Time, t2)
+	// Out-of-order events (a backfill beside live ingestion) widen the
+	// range instead of moving LastSeen back.
+	if !rules[0].LastSeen.Time.Equal(t1) || !rules[0].FirstSeen.Time.Equal(t2) {
+		t.Errorf("FirstSeen, LastSeen = %v, %v, want %v, %v", rules[0].FirstSeen.Time, rules[0].LastSeen.Time, t2, t1)
+	}
+}
+
+func TestAdd_TimestampFallback(t *testing.T) {
+	agg := New()
+	processed := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	requested := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	rule := normalizer.CanonicalRule{Resource: "pods", Verb: "get", Namespace: "default"}
+	untimed := rule
+	untimed.TimestampFallback = true
+
+	agg.Add(untimed, processed)
+	if r := agg.Rules()[0]; !r.TimestampFallback || !r.LastSeen.Time.Equal(processed) {
+		t.Fatalf("untimed rule = %+v, want processing time marked as fallback", r)
+	}
+
+	// The first event timestamp replaces the fallback times.
+	agg.Add(rule, requested)
+	r := agg.Rules()[0]
+	if r.TimestampFallback || !r.FirstSeen.Time.Equal(requested) || !r.LastSeen.Time.Equal(requested) {
+		t.Fatalf("rule = %+v, want event timestamps only", r)
+	}
+
+	// Later untimed events are counted but leave the times alone.
+	agg.Add(untimed, processed.Add(time.Hour))
+	r = agg.Rules()[0]
+	if r.TimestampFallback || !r.LastSeen.Time.Equal(requested) || r.Count != 3 {
+		t.Errorf("rule = %+v, want LastSeen %v and count 3", r, requested)
+	}
+
+	// Seeded fallback times give way to event timestamps as well.
+	seeded := New()
+	seeded.Seed([]audiciav1alpha1.ObservedRule{{
+		APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}, Namespace: "default",
+		FirstSeen: metav1.NewTime(processed), LastSeen: metav1.NewTime(processed), Count: 1, TimestampFallback: true,
+	}})
+	seeded.Seed(agg.Rules())
+	if r := seeded.Rules()[0]; r.TimestampFallback || !r.LastSeen.Time.Equal(requested) {
+		t.Errorf("seeded rule = %+v, want the event timestamps", r)
+	}
+}
+
+func TestAdd_PresetRulesCollapseVerbs(t *testing.T) {
+	agg := New()
+	now := time.Now()
+
+	for _, verb := range []string{"create", "patch", "create"} {
+		agg.Add(normalizer.CollapseHousekeeping(normalizer.CanonicalRule{Resource: "events", Verb: verb, Namespace: "default"}), now)
+	}
+	agg.Add(normalizer.CanonicalRule{Resource: "events", Verb: "list", Namespace: "default"}, now)
+
+	rules := agg.Rules()
+	if len(rules) != 2 {
+		t.Fatalf("got %d rules, want 2 (one preset rule, one regular list rule)", len(rules))
+	}
+	var preset *audiciav1alpha1.ObservedRule
+	for i := range rules {
+		if rules[i].Preset != "" {
+			preset = &rules[i]
+		}
+	}
+	if preset == nil {
+		t.Fatal("expected a preset rule")
+	}
+	if preset.Preset != normalizer.PresetEvents || preset.Count != 3 {
+		t.Errorf("preset rule = %+v, want events preset with count 3", preset)
+	}
+	if !slices.Equal(preset.Verbs, []string{"create", "patch"}) {
+		t.Errorf("preset verbs = %v, want the observed create and patch", preset.Verbs)
+	}
+	if agg.EventsProcessed() != 4 {
+		t.Errorf("EventsProcessed = %d, want 4", agg.EventsProcessed())
+	}
+}
+
+func TestAdd_PresetRuleKeepsSingleObservedVerb(t *testing.T) {
+	agg := New()
+	lease := normalizer.CanonicalRule{APIGroup: "coordination.k8s.io", Resource: "leases", Verb: "get", Namespace: "kube-system"}
+	for range 3 {
+		agg.Add(normalizer.CollapseHousekeeping(lease), time.Now())
+	}
+
+	rules := agg.Rules()
+	if len(rules) != 1 || rules[0].Preset != normalizer.PresetLeaderElection {
+		t.Fatalf("rules = %+v, want one leader-election preset rule", rules)
+	}
+	if !slices.Equal(rules[0].Verbs, []string{"get"}) || rules[0].Count != 3 {
+		t.Errorf("preset rule = %+v, want only get, counted 3 times", rules[0])
+	}
+}
+
+func TestAdd_ResourceNames(t *testing.T) {
+	cm := func(verb, name string) normalizer.CanonicalRule {
+		return normalizer.CanonicalRule{Resource: "configmaps", Verb: verb, Namespace: "default", ResourceName: name}
+	}
+	tests := []struct {
+		name  string
+		rules []normalizer.CanonicalRule
+		want  []string
+	}{
+		{"single name", []normalizer.CanonicalRule{cm("get", "app-config"), cm("get", "app-config")}, []string{"app-config"}},
+		{"names sorted", []normalizer.CanonicalRule{cm("get", "b"), cm("get", "a")}, []string{"a", "b"}},
+		{"verb without names", []normalizer.CanonicalRule{cm("create", "a")}, nil},
+		{"unnamed observation clears names", []normalizer.CanonicalRule{cm("get", "a"), cm("get", ""), cm("get", "b")}, nil},
+		{"too many names", func() []normalizer.CanonicalRule {
+			var rules []normalizer.CanonicalRule
+			for i := 0; i <= MaxResourceNames; i++ {
+				rules = append(rules, cm("get", string(rune('a'+i))))
+			}
+			return rules
+		}(), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agg := New()
+			for _, r := range tt.rules {
+				agg.Add(r, time.Now())
+			}
+			rules := agg.Rules()
+			if len(rules) != 1 {
+				t.Fatalf("got %d rules, want 1", len(rules))
+			}
+			if !slices.Equal(rules[0].ResourceNames, tt.want) {
+				t.Errorf("ResourceNames = %v, want %v", rules[0].ResourceNames, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdd_IncompleteClearedByCompletedRequest(t *testing.T) {
+	watch := normalizer.CanonicalRule{Resource: "pods", Verb: "watch", Namespace: "default", Incomplete: true}
+
+	agg := New()
+	agg.Add(watch, time.Now())
+	if rules := agg.Rules(); !rules[0].Incomplete {
+		t.Fatal("expected rule observed at ResponseStarted to be incomplete")
+	}
+
+	agg.Add(watch, time.Now())
+	if rules := agg.Rules(); !rules[0].Incomplete {
+		t.Error("expected rule to stay incomplete")
+	}
+
+	watch.Incomplete = false
+	agg.Add(watch, time.Now())
+	agg.Add(normalizer.CanonicalRule{Resource: "pods", Verb: "watch", Namespace: "default", Incomplete: true}, time.Now())
+	if rules := agg.Rules(); rules[0].Incomplete {
+		t.Error("expected completed request to clear the incomplete mark")
+	}
+}
+
+func TestAdd_DeniedRulesAreKeptApart(t *testing.T) {
+	agg := New()
+	if rules := agg.DeniedRules(); rules != nil {
+		t.Fatalf("expected no denied rules, got %+v", rules)
+	}
+
+	secrets := normalizer.CanonicalRule{Resource: "secrets", Verb: "get", Namespace: "default", Denied: true}
+	agg.Add(secrets, time.Now())
+	agg.Add(secrets, time.Now())
+	agg.Add(normalizer.CanonicalRule{Resource: "pods", Verb: "get", Namespace: "default"}, time.Now())
+
+	if rules := agg.Rules(); len(rules) != 1 || rules[0].Resources[0] != "pods" {
+		t.Errorf("observed rules = %+v, want only pods", rules)
+	}
+	denied := agg.DeniedRules()
+	if len(denied) != 1 || denied[0].Resources[0] != "secrets" || denied[0].Count != 2 {
+		t.Errorf("denied rules = %+v, want secrets counted twice", denied)
+	}
+	if agg.EventsProcessed() != 1 {
+		t.Errorf("EventsProcessed = %d, want 1", agg.EventsProcessed())
+	}
+}
+
+func TestAdd_AdmissionDeniedCounted(t *testing.T) {
+	pods := normalizer.CanonicalRule{Resource: "pods", Verb: "create", Namespace: "default"}
+	rejected := pods
+	rejected.AdmissionDenied = true
+
+	agg := New()
+	agg.Add(rejected, time.Now())
+	agg.Add(pods, time.Now())
+	agg.Add(rejected, time.Now())
+
+	rules := agg.Rules()
+	if len(rules) != 1 || rules[0].Count != 3 || rules[0].AdmissionDenied != 2 {
+		t.Fatalf("rules = %+v, want one rule counted three times, twice denied by admission", rules)
+	}
+
+	// Seeding takes the larger count, like Count.
+	seed := rules[0]
+	seed.AdmissionDenied = 5
+	agg.Seed([]audiciav1alpha1.ObservedRule{seed})
+	if got := agg.Rules()[0].AdmissionDenied; got != 5 {
+		t.Errorf("AdmissionDenied after seeding = %d, want 5", got)
+	}
+}
+
+func TestActivity(t *testing.T) {
+	agg := New()
+	if agg.Activity() != nil {
+		t.Fatal("expected no activity before the first event")
+	}
+
+	pods := normalizer.CanonicalRule{Resource: "pods", Verb: "get", Namespace: "default"}
+	// 2026-03-01 is a Sunday.
+	agg.Add(pods, time.Date(2026, 3, 1, 2, 15, 0, 0, time.UTC))
+	agg.Add(pods, time.Date(2026, 3, 1, 2, 45, 0, 0, time.UTC))
+	agg.Add(pods, time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC))
+	// Denied requests are not activity.
+	agg.Add(normalizer.CanonicalRule{Resource: "secrets", Verb: "get", Denied: true}, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+
+	a := agg.Activity()
+	if len(a.ByHour) != 24 || a.ByHour[2] != 2 || a.ByHour[14] != 1 || a.ByHour[9] != 0 {
+		t.Errorf("byHour = %v", a.ByHour)
+	}
+	if len(a.ByDay) != 7 || a.ByDay[0] != 1 || a.ByDay[6] != 2 {
+		t.Errorf("byDay = %v, want Monday 1 and Sunday 2", a.ByDay)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	old := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	seed := []audiciav1alpha1.ObservedRule{
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}, Namespace: "team",
+			FirstSeen: metav1.NewTime(old), LastSeen: metav1.NewTime(old), Count: 40},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"list"}, Namespace: "team",
+			FirstSeen: metav1.NewTime(old), LastSeen: metav1.NewTime(old), Count: 3},
+	}
+
+	agg := New()
+	agg.Add(normalizer.CanonicalRule{Resource: "pods", Verb: "get", Namespace: "team", ResourceName: "web-0"}, recent)
+	agg.Seed(seed)
+	agg.Seed(seed)
+
+	rules := agg.Rules()
+	if len(rules) != 2 {
+		t.Fatalf("got %d rules, want 2", len(rules))
+	}
+	pods := rules[0]
+	if !pods.FirstSeen.Time.Equal(old) || !pods.LastSeen.Time.Equal(recent) || pods.Count != 40 {
+		t.Errorf("pods rule = first %v, last %v, count %d; want %v, %v, 40", pods.FirstSeen, pods.LastSeen, pods.Count, old, recent)
+	}
+	if rules[1].Count != 3 {
+		t.Errorf("secrets count = %d, want 3 after seeding twice", rules[1].Count)
+	}
+	if agg.EventsProcessed() != 1 {
+		t.Errorf("EventsProcessed = %d, want 1 (seeded rules are not events)", agg.EventsProcessed())
+	}
+
+	// A seeded rule without names stays collection-wide.
+	agg.Add(normalizer.CanonicalRule{Resource: "secrets", Verb: "list", Namespace: "team", ResourceName: "x"}, recent)
+	if names := agg.Rules()[1].ResourceNames; len(names) != 0 {
+		t.Errorf("expected no resource names, got %v", names)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	agg := New()
+	agg.Add(normalizer.CanonicalRule{Resource: "pods", Verb: "get"}, time.Now())
+	v := agg.Version()
+	agg.Seed([]audiciav1alpha1.ObservedRule{{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"list"}}})
+	if agg.Version() != v {
+		t.Error("seeding advanced the version")
+	}
+	agg.Add(normalizer.CanonicalRule{Resource: "pods", Verb: "delete", Denied: true}, time.Now())
+	if agg.Version() == v {
+		t.Error("a denied observation did not advance the version")
+	}
+}
+
+func TestAttribute(t *testing.T) {
+	span := func(from, to int64) audiciav1alpha1.CheckpointRange {
+		return audiciav1alpha1.CheckpointRange{
+			From: audiciav1alpha1.IngestionPosition{FileOffset: from},
+			To:   audiciav1alpha1.IngestionPosition{FileOffset: to},
+		}
+	}
+	now := time.Now()
+	pods := normalizer.CanonicalRule{Resource: "pods", Verb: "get", Namespace: "default"}
+	secrets := normalizer.CanonicalRule{Resource: "secrets", Verb: "get", Namespace: "default"}
+
+	agg := New()
+	agg.Seed([]audiciav1alpha1.ObservedRule{{
+		APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"list"},
+		Namespace: "default", FirstSeen: metav1.NewTime(now), LastSeen: metav1.NewTime(now), Count: 3,
+	}})
+	agg.Add(pods, now)
+	agg.Attribute(span(0, 100))
+
+	agg.Add(pods, now)
+	agg.Add(secrets, now)
+	agg.Add(normalizer.CanonicalRule{Resource: "configmaps", Verb: "list", Namespace: "default"}, now)
+	rulesBefore := agg.Rules()
+	agg.Attribute(span(100, 250))
+
+	byResource := map[string]*audiciav1alpha1.RuleProvenance{}
+	for _, r := range agg.Rules() {
+		byResource[r.Resources[0]] = r.Provenance
+	}
+	if p := byResource["pods"]; p == nil || p.FirstSeen == nil || p.FirstSeen.To.FileOffset != 100 || p.LastSeen.To.FileOffset != 250 {
+		t.Errorf("pods provenance = %+v", p)
+	}
+	if p := byResource["secrets"]; p == nil || p.FirstSeen == nil || p.FirstSeen.From.FileOffset != 100 {
+		t.Errorf("secrets provenance = %+v", p)
+	}
+	// Seeded before provenance: the first sighting is unknown.
+	if p := byResource["configmaps"]; p == nil || p.FirstSeen != nil || p.LastSeen.To.FileOffset != 250 {
+		t.Errorf("configmaps provenance = %+v", p)
+	}
+	// Copies handed out earlier are not changed.
+	for _, r := range rulesBefore {
+		if r.Resources[0] == "pods" && r.Provenance.LastSeen.To.FileOffset != 100 {
+			t.Errorf("earlier copy changed: %+v", r.Provenance)
+		}
+	}
+
+	// Rules not observed again keep their provenance.
+	agg.Add(secrets, now)
+	agg.Attribute(span(250, 300))
+	for _, r := range agg.Rules() {
+		if r.Resources[0] == "pods" && r.Provenance.LastSeen.To.FileOffset != 250 {
+			t.Errorf("pods provenance moved without observations: %+v", r.Provenance)
+		}
 	}
 }
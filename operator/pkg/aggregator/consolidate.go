@@ -0,0 +1,140 @@
+package aggregator
+
+import (
+	"slices"
+	"strings"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// consolidationKey identifies the rules a subresource rule may merge into:
+// those of its parent resource with the same verb, scope and names.
+type consolidationKey struct {
+	Namespace     string
+	APIGroup      string
+	Resource      string
+	Verb          string
+	ResourceNames string
+	Incomplete    bool
+}
+
+// ConsolidateSubresources merges each subresource rule into the rule of its
+// parent resource observed with the same verb, in the same namespace and API
+// group and on the same resource names: pods get and pods/status get become
+// one rule on [pods, pods/status] get. The merged rule grants exactly what
+// its parts did; it counts their observations together and spans their first
+// and last sightings. Preset rules and rules with admission denials are kept
+// apart. It returns the rules and the number merged away; the input slice is
+// not modified.
+func ConsolidateSubresources(rules []audiciav1alpha1.ObservedRule) ([]audiciav1alpha1.ObservedRule, int) {
+	parents := make(map[consolidationKey]int)
+	for i, rule := range rules {
+		if key, ok := consolidationKeyFor(rule); ok && !strings.Contains(key.Resource, "/") {
+			parents[key] = i
+		}
+	}
+
+	merged := make(map[int]*audiciav1alpha1.ObservedRule)
+	mergedAway := make([]bool, len(rules))
+	consolidated := 0
+	for i, rule := range rules {
+		key, ok := consolidationKeyFor(rule)
+		if !ok {
+			continue
+		}
+		parent, sub, found := strings.Cut(key.Resource, "/")
+		if !found || sub == "" {
+			continue
+		}
+		key.Resource = parent
+		p, ok := parents[key]
+		if !ok {
+			continue
+		}
+		m := merged[p]
+		if m == nil {
+			copied := rules[p]
+			copied.Resources = slices.Clone(copied.Resources)
+			m = &copied
+			merged[p] = m
+		}
+		mergeObserved(m, rule)
+		mergedAway[i] = true
+		consolidated++
+	}
+	if consolidated == 0 {
+		return rules, 0
+	}
+
+	result := make([]audiciav1alpha1.ObservedRule, 0, len(rules)-consolidated)
+	for i, rule := range rules {
+		switch {
+		case mergedAway[i]:
+		case merged[i] != nil:
+			m := merged[i]
+			slices.Sort(m.Resources[1:])
+			result = append(result, *m)
+		default:
+			result = append(result, rule)
+		}
+	}
+	return result, consolidated
+}
+
+// consolidationKeyFor returns the key of an atomic resource rule, or false
+// if the rule is not eligible for consolidation.
+func consolidationKeyFor(rule audiciav1alpha1.ObservedRule) (consolidationKey, bool) {
+	if len(rule.APIGroups) != 1 || len(rule.Resources) != 1 || len(rule.Verbs) != 1 ||
+		len(rule.NonResourceURLs) > 0 || rule.Preset != "" || rule.AdmissionDenied > 0 {
+		return consolidationKey{}, false
+	}
+	return consolidationKey{
+		Namespace:     rule.Namespace,
+		APIGroup:      rule.APIGroups[0],
+		Resource:      rule.Resources[0],
+		Verb:          rule.Verbs[0],
+		ResourceNames: strings.Join(rule.ResourceNames, ","),
+		Incomplete:    rule.Incomplete,
+	}, true
+}
+
+// mergeObserved folds the subresource rule sub into m.
+func mergeObserved(m *audiciav1alpha1.ObservedRule, sub audiciav1alpha1.ObservedRule) {
+	m.Resources = append(m.Resources, sub.Resources[0])
+	m.Count += sub.Count
+	if sub.Provenance != nil {
+		// Rules hands out copies sharing the provenance, so it is replaced
+		// rather than updated.
+		p := &audiciav1alpha1.RuleProvenance{LastSeen: sub.Provenance.LastSeen, FirstSeen: sub.Provenance.FirstSeen}
+		if m.Provenance != nil {
+			if !m.LastSeen.Before(&sub.LastSeen) {
+				p.LastSeen = m.Provenance.LastSeen
+			}
+			if !sub.FirstSeen.Before(&m.FirstSeen) && m.Provenance.FirstSeen != nil {
+				p.FirstSeen = m.Provenance.FirstSeen
+			}
+		}
+		m.Provenance = p
+	}
+	mergeSeen(m, sub.FirstSeen, sub.LastSeen, sub.TimestampFallback)
+}
+
+// expandRule splits a rule consolidated by ConsolidateSubresources into one
+// rule per resource. The split of its count between them is not recorded, so
+// the parent takes all but one observation per subresource; consolidating
+// the parts again restores the rule.
+func expandRule(rule audiciav1alpha1.ObservedRule) []audiciav1alpha1.ObservedRule {
+	if len(rule.Resources) <= 1 {
+		return []audiciav1alpha1.ObservedRule{rule}
+	}
+	parts := make([]audiciav1alpha1.ObservedRule, len(rule.Resources))
+	for i, resource := range rule.Resources {
+		part := rule
+		part.Resources = []string{resource}
+		part.ResourceNames = slices.Clone(rule.ResourceNames)
+		part.Count = 1
+		parts[i] = part
+	}
+	parts[0].Count = max(rule.Count-int64(len(rule.Resources)-1), 1)
+	return parts
+}
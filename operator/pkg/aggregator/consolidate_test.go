@@ -0,0 +1,68 @@
+package aggregator
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConsolidateSubresources(t *testing.T) {
+	early := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	late := early.Add(24 * time.Hour)
+	rule := func(resource, verb string, seen time.Time, count int64) audiciav1alpha1.ObservedRule {
+		return audiciav1alpha1.ObservedRule{APIGroups: []string{""}, Resources: []string{resource}, Verbs: []string{verb},
+			Namespace: "team", FirstSeen: metav1.NewTime(seen), LastSeen: metav1.NewTime(seen), Count: count}
+	}
+	rules := []audiciav1alpha1.ObservedRule{
+		rule("pods", "get", late, 5),
+		rule("pods/status", "get", early, 2),
+		rule("pods/log", "get", late, 1),
+		rule("pods", "list", late, 4),
+		rule("pods/status", "patch", late, 3), // no pods patch to merge into
+		rule("deployments/scale", "get", late, 1),
+	}
+
+	got, consolidated := ConsolidateSubresources(rules)
+	if consolidated != 2 || len(got) != 4 {
+		t.Fatalf("got %d rules, %d consolidated; want 4 and 2: %+v", len(got), consolidated, got)
+	}
+	pods := got[0]
+	if !slices.Equal(pods.Resources, []string{"pods", "pods/log", "pods/status"}) || !slices.Equal(pods.Verbs, []string{"get"}) {
+		t.Errorf("merged rule = %v %v, want get on pods, pods/log and pods/status", pods.Verbs, pods.Resources)
+	}
+	if pods.Count != 8 || !pods.FirstSeen.Time.Equal(early) || !pods.LastSeen.Time.Equal(late) {
+		t.Errorf("merged rule count %d, first %v, last %v; want 8, %v, %v", pods.Count, pods.FirstSeen, pods.LastSeen, early, late)
+	}
+	if len(rules[0].Resources) != 1 {
+		t.Error("ConsolidateSubresources modified its input")
+	}
+
+	// Seeding the merged rule back restores it on the next consolidation.
+	agg := New()
+	agg.Seed(got)
+	again, _ := ConsolidateSubresources(agg.Rules())
+	if len(again) != 4 {
+		t.Fatalf("got %d rules after seeding, want 4", len(again))
+	}
+	for _, r := range again {
+		if len(r.Resources) == 3 && r.Count != 8 {
+			t.Errorf("merged rule count after seeding = %d, want 8", r.Count)
+		}
+	}
+}
+
+func TestCompact_ConsolidateSubresources(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rules := []audiciav1alpha1.ObservedRule{
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}, LastSeen: metav1.NewTime(now)},
+		{APIGroups: []string{""}, Resources: []string{"pods/status"}, Verbs: []string{"get"}, LastSeen: metav1.NewTime(now)},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}, LastSeen: metav1.NewTime(now.Add(-time.Hour))},
+	}
+	got := Compact(rules, audiciav1alpha1.LimitsConfig{MaxRulesPerReport: 2, ConsolidateSubresources: true}, now)
+	if got.Consolidated != 1 || got.Truncated != 0 || len(got.Rules) != 2 {
+		t.Errorf("consolidated=%d truncated=%d kept=%d, want 1/0/2", got.Consolidated, got.Truncated, len(got.Rules))
+	}
+}
@@ -0,0 +1,120 @@
+package aggregator
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultMaxRulesPerReport applies when spec.limits.maxRulesPerReport is unset.
+	DefaultMaxRulesPerReport = 200
+	// DefaultRetentionDays applies when spec.limits.retentionDays is unset.
+	DefaultRetentionDays = 30
+)
+
+// CompactionResult is the outcome of applying limits to a rule set.
+type CompactionResult struct {
+	// Rules are the retained rules, most recently seen first.
+	Rules []audiciav1alpha1.ObservedRule
+	// Expired is the number of rules not seen within the retention window.
+	Expired int
+	// Truncated is the number of rules dropped to stay within the rule limit.
+	Truncated int
+	// Consolidated is the number of subresource rules merged into the rule
+	// of their parent resource (spec.limits.consolidateSubresources). They
+	// are not dropped.
+	Consolidated int
+}
+
+// Dropped returns the total number of rules removed.
+func (c CompactionResult) Dropped() int {
+	return c.Expired + c.Truncated
+}
+
+// Compact applies retention and truncation limits to observed rules as of
+// now. Rules last seen before their retention window, that of the first
+// matching retention policy or else RetentionDays, are dropped first, then
+// subresource rules are consolidated if the limits say so; if more than the
+// maximum remain, the least recently seen are truncated. Zero limits fall
+// back to the defaults. The input slice is not modified.
+func Compact(rules []audiciav1alpha1.ObservedRule, limits audiciav1alpha1.LimitsConfig, now time.Time) CompactionResult {
+	retentionDays := int(limits.RetentionDays)
+	if retentionDays <= 0 {
+		retentionDays = DefaultRetentionDays
+	}
+	cutoff := retentionCutoff(now, retentionDays)
+	policyCutoffs := make([]metav1.Time, len(limits.RetentionPolicies))
+	for i, p := range limits.RetentionPolicies {
+		policyCutoffs[i] = retentionCutoff(now, int(p.RetentionDays))
+	}
+
+	var result CompactionResult
+	retained := make([]audiciav1alpha1.ObservedRule, 0, len(rules))
+	for _, rule := range rules {
+		ruleCutoff := cutoff
+		if i := matchRetentionPolicy(limits.RetentionPolicies, rule); i >= 0 {
+			ruleCutoff = policyCutoffs[i]
+		}
+		if rule.LastSeen.Before(&ruleCutoff) {
+			result.Expired++
+			continue
+		}
+		retained = append(retained, rule)
+	}
+	if limits.ConsolidateSubresources {
+		retained, result.Consolidated = ConsolidateSubresources(retained)
+	}
+
+	// Sort by LastSeen descending for truncation (keep most recent).
+	sort.Slice(retained, func(i, j int) bool {
+		return retained[j].LastSeen.Before(&retained[i].LastSeen)
+	})
+
+	maxRules := int(limits.MaxRulesPerReport)
+	if maxRules <= 0 {
+		maxRules = DefaultMaxRulesPerReport
+	}
+	if len(retained) > maxRules {
+		result.Truncated = len(retained) - maxRules
+		retained = retained[:maxRules]
+	}
+	result.Rules = retained
+	return result
+}
+
+// retentionCutoff returns the time before which rules retained for days
+// expire.
+func retentionCutoff(now time.Time, days int) metav1.Time {
+	return metav1.NewTime(now.Add(-time.Duration(days) * 24 * time.Hour))
+}
+
+// matchRetentionPolicy returns the index of the first policy matching rule,
+// or -1. Non-resource rules match no policy.
+func matchRetentionPolicy(policies []audiciav1alpha1.RetentionPolicy, rule audiciav1alpha1.ObservedRule) int {
+	for i, p := range policies {
+		if len(p.APIGroups) > 0 && !slices.ContainsFunc(rule.APIGroups, func(g string) bool { return slices.Contains(p.APIGroups, g) }) {
+			continue
+		}
+		for _, resource := range rule.Resources {
+			if slices.ContainsFunc(p.Resources, func(pattern string) bool { return matchResource(pattern, resource) }) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// matchResource reports whether a retention policy resource pattern covers
+// resource: "*" covers all, "pods/*" the subresources of pods.
+func matchResource(pattern, resource string) bool {
+	if pattern == "*" || pattern == resource {
+		return true
+	}
+	parent, ok := strings.CutSuffix(pattern, "/*")
+	return ok && strings.HasPrefix(resource, parent+"/")
+}
@@ -0,0 +1,92 @@
+package aggregator
+
+import (
+	"testing"
+	"time"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCompact(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rule := func(resource string, age time.Duration) audiciav1alpha1.ObservedRule {
+		return audiciav1alpha1.ObservedRule{Resources: []string{resource}, Verbs: []string{"get"}, LastSeen: metav1.NewTime(now.Add(-age))}
+	}
+	day := 24 * time.Hour
+	rules := []audiciav1alpha1.ObservedRule{
+		rule("pods", time.Hour),
+		rule("secrets", 40*day),
+		rule("configmaps", 2*day),
+		rule("services", 10*day),
+	}
+
+	tests := []struct {
+		name          string
+		limits        audiciav1alpha1.LimitsConfig
+		wantExpired   int
+		wantTruncated int
+		wantFirst     string
+	}{
+		{"defaults", audiciav1alpha1.LimitsConfig{}, 1, 0, "pods"},
+		{"shorter retention", audiciav1alpha1.LimitsConfig{RetentionDays: 7}, 2, 0, "pods"},
+		{"truncation after retention", audiciav1alpha1.LimitsConfig{RetentionDays: 30, MaxRulesPerReport: 1}, 1, 2, "pods"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compact(rules, tt.limits, now)
+			if got.Expired != tt.wantExpired || got.Truncated != tt.wantTruncated {
+				t.Errorf("expired=%d truncated=%d, want %d/%d", got.Expired, got.Truncated, tt.wantExpired, tt.wantTruncated)
+			}
+			if len(got.Rules) != len(rules)-got.Dropped() {
+				t.Errorf("kept %d rules, want %d", len(got.Rules), len(rules)-got.Dropped())
+			}
+			if got.Rules[0].Resources[0] != tt.wantFirst {
+				t.Errorf("first rule = %s, want %s", got.Rules[0].Resources[0], tt.wantFirst)
+			}
+		})
+	}
+	if rules[1].Resources[0] != "secrets" {
+		t.Error("Compact modified its input")
+	}
+}
+
+func TestCompact_RetentionPolicies(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	rule := func(group, resource string, age time.Duration) audiciav1alpha1.ObservedRule {
+		return audiciav1alpha1.ObservedRule{
+			APIGroups: []string{group}, Resources: []string{resource}, Verbs: []string{"get"},
+			LastSeen: metav1.NewTime(now.Add(-age)),
+		}
+	}
+	limits := audiciav1alpha1.LimitsConfig{
+		RetentionDays: 30,
+		RetentionPolicies: []audiciav1alpha1.RetentionPolicy{
+			{Name: "evidence", Resources: []string{"secrets", "pods/*"}, RetentionDays: 90},
+			{Name: "events", APIGroups: []string{"", "events.k8s.io"}, Resources: []string{"events"}, RetentionDays: 7},
+		},
+	}
+	rules := []audiciav1alpha1.ObservedRule{
+		rule("", "secrets", 60*day),        // kept by evidence
+		rule("", "pods/exec", 60*day),      // kept by evidence
+		rule("", "pods", 60*day),           // RetentionDays
+		rule("", "events", 10*day),         // dropped by events
+		rule("other.io", "events", 10*day), // another group: RetentionDays
+		rule("", "configmaps", 10*day),     // RetentionDays
+	}
+
+	got := Compact(rules, limits, now)
+	if got.Expired != 2 {
+		t.Errorf("expired = %d, want 2", got.Expired)
+	}
+	kept := map[string]bool{}
+	for _, r := range got.Rules {
+		kept[r.APIGroups[0]+"/"+r.Resources[0]] = true
+	}
+	for _, want := range []string{"/secrets", "/pods/exec", "other.io/events", "/configmaps"} {
+		if !kept[want] {
+			t.Errorf("rule %s dropped, want kept (kept %v)", want, kept)
+		}
+	}
+}
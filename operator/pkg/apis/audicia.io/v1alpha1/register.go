@@ -33,6 +33,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&AudiciaReportList{},
 		&AudiciaPolicy{},
 		&AudiciaPolicyList{},
+		&AudiciaPolicyPlan{},
+		&AudiciaPolicyPlanList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -49,6 +49,10 @@ type AudiciaPolicyStatus struct {
 	// +optional
 	ApprovedTime *metav1.Time `json:"approvedTime,omitempty"`
 
+	// GeneratedBy is the operator build that last rendered the manifests.
+	// +optional
+	GeneratedBy *GeneratorInfo `json:"generatedBy,omitempty"`
+
 	// Conditions represent the latest available observations of the policy's state.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
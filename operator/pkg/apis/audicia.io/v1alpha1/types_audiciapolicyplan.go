@@ -0,0 +1,131 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PlanPhase summarizes where an AudiciaPolicyPlan is in its lifecycle.
+// +kubebuilder:validation:Enum=AwaitingApproval;Applied;Drifted;Failed
+type PlanPhase string
+
+const (
+	// PlanPhaseAwaitingApproval means the current revision has not been approved.
+	PlanPhaseAwaitingApproval PlanPhase = "AwaitingApproval"
+	// PlanPhaseApplied means the approved revision is applied and in sync.
+	PlanPhaseApplied PlanPhase = "Applied"
+	// PlanPhaseDrifted means applied objects were changed or deleted out of band.
+	PlanPhaseDrifted PlanPhase = "Drifted"
+	// PlanPhaseFailed means the approved revision could not be applied.
+	PlanPhaseFailed PlanPhase = "Failed"
+)
+
+// AudiciaPolicyPlanSpec selects suggested policies and gates their application.
+type AudiciaPolicyPlanSpec struct {
+	// PolicyRefs names the AudiciaPolicies, in the plan's namespace, whose
+	// manifests this plan applies.
+	// +kubebuilder:validation:MinItems=1
+	PolicyRefs []string `json:"policyRefs"`
+
+	// ApprovedRevision approves applying one exact set of manifests. Set it to
+	// the value of status.revision after reviewing the plan. When the referenced
+	// policies change, status.revision changes too and nothing further is
+	// applied until the new revision is approved.
+	// +optional
+	ApprovedRevision string `json:"approvedRevision,omitempty"`
+}
+
+// PlanObject is one RBAC object managed by a plan.
+type PlanObject struct {
+	// Kind is Role, ClusterRole, RoleBinding or ClusterRoleBinding.
+	Kind string `json:"kind"`
+
+	// Namespace is empty for cluster-scoped objects.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the object name.
+	Name string `json:"name"`
+
+	// Policy is the AudiciaPolicy the object's manifest came from.
+	Policy string `json:"policy"`
+
+	// Drifted is true when the live object no longer matches the manifest.
+	// +optional
+	Drifted bool `json:"drifted,omitempty"`
+}
+
+// PlanApplication records one application of a plan.
+type PlanApplication struct {
+	// Revision is the manifest revision that was applied.
+	Revision string `json:"revision"`
+
+	// Time is when the revision was applied.
+	Time metav1.Time `json:"time"`
+
+	// Objects is the number of RBAC objects written.
+	Objects int32 `json:"objects"`
+}
+
+// AudiciaPolicyPlanStatus reports the plan revision, applied objects and drift.
+type AudiciaPolicyPlanStatus struct {
+	// Phase summarizes the plan state.
+	// +optional
+	Phase PlanPhase `json:"phase,omitempty"`
+
+	// Revision identifies the current manifests of the referenced policies.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// AppliedRevision is the revision most recently applied to the cluster.
+	// +optional
+	AppliedRevision string `json:"appliedRevision,omitempty"`
+
+	// Objects lists the RBAC objects written for AppliedRevision.
+	// +optional
+	Objects []PlanObject `json:"objects,omitempty"`
+
+	// History is the audit trail of applications, newest last (max 20).
+	// +optional
+	History []PlanApplication `json:"history,omitempty"`
+
+	// LastDriftCheckTime is when the applied objects were last compared
+	// against their manifests.
+	// +optional
+	LastDriftCheckTime *metav1.Time `json:"lastDriftCheckTime,omitempty"`
+
+	// ObservedGeneration is the spec generation the status reflects.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the plan's state.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName={app,aplan}
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Revision",type=string,JSONPath=`.status.revision`
+// +kubebuilder:printcolumn:name="Applied",type=string,JSONPath=`.status.appliedRevision`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AudiciaPolicyPlan applies the suggested manifests of selected
+// AudiciaPolicies once a reviewer approves an exact revision, and tracks the
+// applied objects for drift.
+type AudiciaPolicyPlan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AudiciaPolicyPlanSpec   `json:"spec,omitempty"`
+	Status AudiciaPolicyPlanStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AudiciaPolicyPlanList contains a list of AudiciaPolicyPlan resources.
+type AudiciaPolicyPlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AudiciaPolicyPlan `json:"items"`
+}
@@ -12,12 +12,48 @@ type AudiciaReportSpec struct {
 	Subject Subject `json:"subject"`
 }
 
+// ReportSource is one AudiciaSource merged into a report.
+type ReportSource struct {
+	// Name is the AudiciaSource as "namespace/name".
+	Name string `json:"name"`
+
+	// EventsProcessed is the number of events the source processed for the
+	// subject.
+	EventsProcessed int64 `json:"eventsProcessed"`
+
+	// LastFlushTime is when the source last wrote the report.
+	LastFlushTime metav1.Time `json:"lastFlushTime"`
+
+	// ConfigurationHash is the hash of the source's filter and strategy
+	// configuration at its last flush (see ReportConfiguration).
+	// +optional
+	ConfigurationHash string `json:"configurationHash,omitempty"`
+}
+
 // AudiciaReportStatus contains compliance scoring and observed RBAC usage.
 type AudiciaReportStatus struct {
 	// ObservedRules is the structured list of observed RBAC rules for this subject.
 	// +optional
 	ObservedRules []ObservedRule `json:"observedRules,omitempty"`
 
+	// DeniedRules lists what the subject attempted but was not authorized to
+	// do (403 Forbidden). They are not part of ObservedRules or the suggested
+	// policy; they show why a workload fails and whether the suggested role
+	// should grant more. Only recorded when the source sets
+	// spec.includeDenied.
+	// +optional
+	DeniedRules []ObservedRule `json:"deniedRules,omitempty"`
+
+	// Ignored lists observed verbs left out of the suggested policy, e.g.
+	// "proxy" or custom verbs, verbs API discovery reports the resource does
+	// not support, or rules below the source's minCount and minDays, so a
+	// permission missing from the suggestion can be explained. Most frequent
+	// first.
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=50
+	Ignored []IgnoredVerb `json:"ignored,omitempty"`
+
 	// Compliance contains the RBAC drift analysis comparing observed usage
 	// against the subject's effective permissions in the cluster.
 	// +optional
@@ -27,15 +63,156 @@ type AudiciaReportStatus struct {
 	// +optional
 	EventsProcessed int64 `json:"eventsProcessed,omitempty"`
 
+	// Activity summarizes when the subject is active, by hour of day and day
+	// of week.
+	// +optional
+	Activity *ActivitySummary `json:"activity,omitempty"`
+
 	// LastProcessedTime is the timestamp of the last processed event for this subject.
 	// +optional
 	LastProcessedTime *metav1.Time `json:"lastProcessedTime,omitempty"`
 
+	// GeneratedBy is the operator build that last wrote ObservedRules.
+	// +optional
+	GeneratedBy *GeneratorInfo `json:"generatedBy,omitempty"`
+
+	// Configuration identifies the filter and strategy configuration of the
+	// source that wrote ObservedRules. Not set when sources merge reports
+	// (spec.reportMerge: Union); each entry of Sources carries its own hash.
+	// +optional
+	Configuration *ReportConfiguration `json:"configuration,omitempty"`
+
+	// Integrity is the hash chain over ObservedRules, kept when the source
+	// sets spec.integrity.
+	// +optional
+	Integrity *IntegrityStatus `json:"integrity,omitempty"`
+
+	// Sources lists the AudiciaSources merged into this report when they set
+	// spec.reportMerge to Union.
+	// +optional
+	Sources []ReportSource `json:"sources,omitempty"`
+
 	// Conditions represent the latest available observations of the report's state.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// IgnoredVerbReason explains why an observed verb is not suggested.
+// +kubebuilder:validation:Enum=NonStandardVerb;UnsupportedVerb;BelowThreshold
+type IgnoredVerbReason string
+
+const (
+	// IgnoredVerbNonStandard is a verb outside the standard Kubernetes API
+	// verbs, such as "proxy", "impersonate" or a custom verb.
+	IgnoredVerbNonStandard IgnoredVerbReason = "NonStandardVerb"
+
+	// IgnoredVerbUnsupported is a standard verb the resource does not serve
+	// according to API discovery.
+	IgnoredVerbUnsupported IgnoredVerbReason = "UnsupportedVerb"
+
+	// IgnoredVerbBelowThreshold is a rule observed fewer times, or over a
+	// shorter span, than spec.policyStrategy.minCount and minDays require.
+	IgnoredVerbBelowThreshold IgnoredVerbReason = "BelowThreshold"
+)
+
+// IgnoredVerb is an observed verb and resource combination left out of the
+// suggested policy.
+type IgnoredVerb struct {
+	// APIGroups of the observed requests.
+	// +optional
+	APIGroups []string `json:"apiGroups,omitempty"`
+
+	// Resources of the observed requests.
+	// +optional
+	Resources []string `json:"resources,omitempty"`
+
+	// NonResourceURLs of the observed requests.
+	// +optional
+	NonResourceURLs []string `json:"nonResourceURLs,omitempty"`
+
+	// Verb is the observed verb.
+	Verb string `json:"verb"`
+
+	// Reason is why the verb is not suggested.
+	Reason IgnoredVerbReason `json:"reason"`
+
+	// Count is the number of requests observed with the verb, across
+	// namespaces.
+	Count int64 `json:"count"`
+
+	// LastSeen is when the verb was last observed.
+	// +optional
+	LastSeen *metav1.Time `json:"lastSeen,omitempty"`
+}
+
+// ReportConfiguration records which configuration produced a report, so
+// reviewers can tell whether its history is comparable to current settings.
+type ReportConfiguration struct {
+	// Hash is a hash of everything in the source that decides which
+	// requests become rules and how they are turned into a policy: filters,
+	// subject aliases, ignoreSystemUsers, collapseHousekeeping and the
+	// built-in presets, stages, includeDenied, excludeDryRun, sampling and
+	// policyStrategy.
+	Hash string `json:"hash"`
+
+	// PreviousHash is the hash before the configuration last changed.
+	// +optional
+	PreviousHash string `json:"previousHash,omitempty"`
+
+	// ChangedTime is when the configuration with Hash took effect. Rules last
+	// observed before it are marked priorConfiguration.
+	// +optional
+	ChangedTime *metav1.Time `json:"changedTime,omitempty"`
+}
+
+// ActivitySummary counts a subject's events by local time of day and day of
+// week, so reviewers can see when it is active.
+type ActivitySummary struct {
+	// TimeZone is the IANA time zone the events are bucketed in.
+	TimeZone string `json:"timeZone"`
+
+	// ByHour counts events per hour of day, 00:00-00:59 first.
+	// +kubebuilder:validation:MinItems=24
+	// +kubebuilder:validation:MaxItems=24
+	ByHour []int64 `json:"byHour"`
+
+	// ByDay counts events per day of week, Monday first.
+	// +kubebuilder:validation:MinItems=7
+	// +kubebuilder:validation:MaxItems=7
+	ByDay []int64 `json:"byDay"`
+}
+
+// IntegrityStatus is a hash chain over the ObservedRules a report has held.
+type IntegrityStatus struct {
+	// Entries are the chain entries, oldest first.
+	// +optional
+	Entries []IntegrityEntry `json:"entries,omitempty"`
+}
+
+// IntegrityEntry records one flush that changed ObservedRules.
+type IntegrityEntry struct {
+	// Time is when the flush happened.
+	Time metav1.Time `json:"time"`
+
+	// RulesHash is the hex SHA-256 of the ObservedRules written.
+	RulesHash string `json:"rulesHash"`
+
+	// PreviousHash is the Hash of the entry before, empty for the first.
+	// +optional
+	PreviousHash string `json:"previousHash,omitempty"`
+
+	// Hash is the hex SHA-256 over PreviousHash, Time and RulesHash.
+	Hash string `json:"hash"`
+
+	// Added is the number of rules the flush added.
+	// +optional
+	Added int32 `json:"added,omitempty"`
+
+	// Removed is the number of rules the flush removed.
+	// +optional
+	Removed int32 `json:"removed,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName={ar,areport}
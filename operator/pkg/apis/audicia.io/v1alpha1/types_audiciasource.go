@@ -5,13 +5,25 @@ import (
 )
 
 // SourceType defines the type of audit log source.
-// +kubebuilder:validation:Enum=K8sAuditLog;Webhook;CloudAuditLog
+// +kubebuilder:validation:Enum=K8sAuditLog;Webhook;CloudAuditLog;Local;Forward;Grpc
 type SourceType string
 
 const (
 	SourceTypeK8sAuditLog   SourceType = "K8sAuditLog"
 	SourceTypeWebhook       SourceType = "Webhook"
 	SourceTypeCloudAuditLog SourceType = "CloudAuditLog"
+	// SourceTypeLocal receives webhook payloads over a UNIX socket or plain
+	// HTTP on localhost. Intended for kind clusters and e2e tests only; the
+	// operator rejects it unless local ingestion is enabled.
+	SourceTypeLocal SourceType = "Local"
+	// SourceTypeForward receives audit log lines pushed by node log shippers
+	// (Fluent Bit, Fluentd, rsyslog) over the Fluentd forward protocol or
+	// RFC 5424 syslog, so the operator needs no hostPath mount.
+	SourceTypeForward SourceType = "Forward"
+	// SourceTypeGrpc receives audit events pushed by external collectors
+	// over the EventPush gRPC service, so any pipeline can feed a source
+	// without a provider-specific adapter.
+	SourceTypeGrpc SourceType = "Grpc"
 )
 
 // ScopeMode controls whether ClusterRoles are generated.
@@ -41,6 +53,37 @@ const (
 	WildcardModeSafe      WildcardMode = "Safe"
 )
 
+// ResourceNamesMode controls resourceNames generation.
+// +kubebuilder:validation:Enum=Omit;Explicit
+type ResourceNamesMode string
+
+const (
+	ResourceNamesOmit     ResourceNamesMode = "Omit"
+	ResourceNamesExplicit ResourceNamesMode = "Explicit"
+)
+
+// ExpiredReportAction is what happens to a report past spec.limits.reportTTLDays.
+// +kubebuilder:validation:Enum=Delete;MarkStale
+type ExpiredReportAction string
+
+const (
+	ExpiredReportDelete    ExpiredReportAction = "Delete"
+	ExpiredReportMarkStale ExpiredReportAction = "MarkStale"
+)
+
+// ReportMergeMode controls how sources share the reports of a subject.
+// +kubebuilder:validation:Enum=Exclusive;Union
+type ReportMergeMode string
+
+const (
+	// ReportMergeExclusive lets one source at a time write a report, holding
+	// a write lease.
+	ReportMergeExclusive ReportMergeMode = "Exclusive"
+	// ReportMergeUnion merges the rules of every Union source writing a
+	// report.
+	ReportMergeUnion ReportMergeMode = "Union"
+)
+
 // FilterAction defines whether a filter allows or denies.
 // +kubebuilder:validation:Enum=Allow;Deny
 type FilterAction string
@@ -50,9 +93,21 @@ const (
 	FilterActionDeny  FilterAction = "Deny"
 )
 
+// AuditStage is a stage of request handling at which the API server logs an
+// audit event.
+// +kubebuilder:validation:Enum=RequestReceived;ResponseStarted;ResponseComplete;Panic
+type AuditStage string
+
+const (
+	AuditStageRequestReceived  AuditStage = "RequestReceived"
+	AuditStageResponseStarted  AuditStage = "ResponseStarted"
+	AuditStageResponseComplete AuditStage = "ResponseComplete"
+	AuditStagePanic            AuditStage = "Panic"
+)
+
 // AudiciaSourceSpec defines the desired state of an AudiciaSource.
 type AudiciaSourceSpec struct {
-	// SourceType is the type of audit log source (K8sAuditLog or Webhook).
+	// SourceType is the type of audit log source.
 	// +kubebuilder:validation:Required
 	SourceType SourceType `json:"sourceType"`
 
@@ -68,6 +123,18 @@ type AudiciaSourceSpec struct {
 	// +optional
 	Cloud *CloudConfig `json:"cloud,omitempty"`
 
+	// Local configures the developer-mode Local source.
+	// +optional
+	Local *LocalConfig `json:"local,omitempty"`
+
+	// Forward configures the log-shipper Forward source.
+	// +optional
+	Forward *ForwardConfig `json:"forward,omitempty"`
+
+	// Grpc configures the gRPC push source.
+	// +optional
+	Grpc *GrpcConfig `json:"grpc,omitempty"`
+
 	// PolicyStrategy configures how policies are generated.
 	// +optional
 	PolicyStrategy PolicyStrategy `json:"policyStrategy,omitempty"`
@@ -76,11 +143,80 @@ type AudiciaSourceSpec struct {
 	// +optional
 	Filters []Filter `json:"filters,omitempty"`
 
+	// SubjectAliases maps raw audit identities onto logical subjects, so that
+	// equivalent identities (e.g., the same ServiceAccount seen through several
+	// clusters or forwarders) aggregate into one report. First match wins.
+	// +optional
+	SubjectAliases []SubjectAlias `json:"subjectAliases,omitempty"`
+
 	// IgnoreSystemUsers filters out known system users (e.g., system:kube-controller-manager).
 	// +optional
 	// +kubebuilder:default=true
 	IgnoreSystemUsers bool `json:"ignoreSystemUsers,omitempty"`
 
+	// CollapseHousekeeping summarises routine controller traffic (event
+	// writes, leader-election leases) into one well-known preset rule per
+	// namespace instead of listing every observed verb. Events are still
+	// counted.
+	// +optional
+	CollapseHousekeeping bool `json:"collapseHousekeeping,omitempty"`
+
+	// Stages lists the audit event stages that are processed. The API server
+	// logs one event per stage of a request, so processing several stages
+	// counts each request several times. Defaults to ResponseComplete only.
+	// Events without a stage are always processed.
+	// +optional
+	Stages []AuditStage `json:"stages,omitempty"`
+
+	// CaptureIncompleteStages also processes events at the ResponseStarted
+	// and Panic stages, in addition to spec.stages. Long-running watches
+	// only reach ResponseComplete when they end, so on some clusters they are
+	// otherwise never observed. Rules seen only at these stages are marked
+	// incomplete in the report.
+	// +optional
+	CaptureIncompleteStages bool `json:"captureIncompleteStages,omitempty"`
+
+	// ActivityTimeZone is the IANA time zone the activity summary in each
+	// report (status.activity) is bucketed in. Defaults to UTC.
+	// +optional
+	ActivityTimeZone string `json:"activityTimeZone,omitempty"`
+
+	// IncludeDenied keeps requests the API server rejected with 403. They
+	// never contribute to ObservedRules, which would suggest permissions the
+	// subject was never granted; instead they are listed as DeniedRules in
+	// the report. By default they are dropped. Unauthenticated requests (401)
+	// are always dropped.
+	// +optional
+	IncludeDenied bool `json:"includeDenied,omitempty"`
+
+	// ExcludeDryRun drops dry-run requests (kubectl --dry-run=server,
+	// server-side apply with dryRun). The API server authorizes them like
+	// real requests, so by default they count as used permissions.
+	// +optional
+	ExcludeDryRun bool `json:"excludeDryRun,omitempty"`
+
+	// RuleProvenance records on each observed rule the checkpoint ranges of
+	// the flushes that first and last saw it: the file offsets or partition
+	// offsets ingested since the previous flush. A rule can then be traced
+	// back to the raw audit log during forensic review. Sources without
+	// checkpoints (Webhook, Local) have nothing to record.
+	// +optional
+	RuleProvenance bool `json:"ruleProvenance,omitempty"`
+
+	// ReportMerge controls reports other sources write for the same subject.
+	// Exclusive (the default) gives one source at a time a write lease on
+	// each report. Union merges the rules of all Union sources into the
+	// report, e.g. while migrating from a file to a webhook source; every
+	// source writing the report must then use Union.
+	// +optional
+	// +kubebuilder:default=Exclusive
+	ReportMerge ReportMergeMode `json:"reportMerge,omitempty"`
+
+	// SubjectTracking attributes events to subjects beyond the requesting
+	// user, such as the groups the user authenticated with.
+	// +optional
+	SubjectTracking *SubjectTrackingConfig `json:"subjectTracking,omitempty"`
+
 	// Checkpoint configures processing checkpoint behavior.
 	// +optional
 	Checkpoint CheckpointConfig `json:"checkpoint,omitempty"`
@@ -88,17 +224,299 @@ type AudiciaSourceSpec struct {
 	// Limits configures object size and retention limits.
 	// +optional
 	Limits LimitsConfig `json:"limits,omitempty"`
+
+	// PendingReports creates placeholder AudiciaReports for ServiceAccounts
+	// that have not been observed yet. Omit to disable.
+	// +optional
+	PendingReports *PendingReportsConfig `json:"pendingReports,omitempty"`
+
+	// Metadata holds labels and annotations stamped onto every AudiciaReport,
+	// AudiciaPolicy and suggested RBAC manifest produced by this source (e.g.,
+	// env=prod, cluster=eu-1), so findings can be grouped across clusters.
+	// +optional
+	Metadata *OutputMetadata `json:"metadata,omitempty"`
+
+	// GapDetection records stretches of time with no ingested audit events,
+	// which usually mean missed data (rotation misses, cloud retention
+	// expiry, webhook downtime). Omit to disable.
+	// +optional
+	GapDetection *GapDetectionConfig `json:"gapDetection,omitempty"`
+
+	// Deduplication drops audit events whose auditID and stage were already
+	// seen within a bounded window, so a request delivered more than once
+	// (several API server replicas behind one shipper, webhook fan-in,
+	// redelivery after a restart) is counted once. Enabled by default.
+	// +optional
+	Deduplication *DeduplicationConfig `json:"deduplication,omitempty"`
+
+	// Sampling processes a fraction of the audit events, for clusters that
+	// produce more than the operator keeps up with. Each kept event counts
+	// for the events it stands in for, so rule counts and activity remain
+	// estimates of the full traffic; rules observed only a few times may be
+	// missed. Omit to process every event.
+	// +optional
+	Sampling *SamplingConfig `json:"sampling,omitempty"`
+
+	// FilteredEventTracking counts the users and namespaces whose events
+	// spec.filters deny and lists the most frequent in status, so a Deny
+	// pattern that matches more than intended shows up there instead of as
+	// missing reports. Omit to disable.
+	// +optional
+	FilteredEventTracking *FilteredEventTrackingConfig `json:"filteredEventTracking,omitempty"`
+
+	// Integrity chains a hash of each report's ObservedRules to the previous
+	// one on every flush that changes them, so manual edits between reviews
+	// can be detected with `audicia verify`. Omit to disable.
+	// +optional
+	Integrity *IntegrityConfig `json:"integrity,omitempty"`
+
+	// BreakGlass names emergency-access identities. Their reports are
+	// written to a dedicated namespace, no policy is suggested for them, and
+	// their activity raises a warning, so emergency access never mixes into
+	// the steady-state suggestions. Omit to disable.
+	// +optional
+	BreakGlass *BreakGlassConfig `json:"breakGlass,omitempty"`
+
+	// PolicySink publishes the suggested policies outside the cluster, so
+	// they are reviewed and applied through GitOps. Omit to disable.
+	// +optional
+	PolicySink *PolicySinkConfig `json:"policySink,omitempty"`
+
+	// Notifications sends the source's reports to external systems whenever
+	// they materially change, for ticketing or CMDB sync without polling.
+	// Omit to disable.
+	// +optional
+	Notifications *SourceNotifications `json:"notifications,omitempty"`
+}
+
+// SourceNotifications configures the notifications of a source's reports.
+type SourceNotifications struct {
+	// Webhooks receive a POST for every material change of a report the
+	// source writes.
+	// +kubebuilder:validation:MaxItems=8
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Webhooks []ReportWebhook `json:"webhooks,omitempty"`
+}
+
+// ReportWebhookPayload selects what a report webhook receives.
+// +kubebuilder:validation:Enum=Full;Diff
+type ReportWebhookPayload string
+
+const (
+	// ReportWebhookPayloadFull sends the changes and the whole updated
+	// report.
+	ReportWebhookPayloadFull ReportWebhookPayload = "Full"
+	// ReportWebhookPayloadDiff sends only the changes.
+	ReportWebhookPayloadDiff ReportWebhookPayload = "Diff"
+)
+
+// ReportWebhook is an external receiver of report changes.
+type ReportWebhook struct {
+	// Name identifies the webhook in logs and metrics.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// URL receives the JSON POSTs.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// Payload selects the body: Full sends the changes and the updated
+	// report, Diff only the changes.
+	// +kubebuilder:default=Full
+	Payload ReportWebhookPayload `json:"payload,omitempty"`
+
+	// SigningKey names the HMAC key deliveries are signed with. It is read
+	// from the key <namespace>_<source>_<signingKey> of the report webhook
+	// Secret the Helm chart mounts (notifications.reportWebhookSecretName),
+	// so each source only reaches its own keys. Omit to send unsigned
+	// deliveries.
+	// +optional
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]+$`
+	SigningKey string `json:"signingKey,omitempty"`
+}
+
+// PolicySinkConfig configures where suggested policies are published.
+type PolicySinkConfig struct {
+	// Git commits the manifests of the source's policies to a repository.
+	// +kubebuilder:validation:Required
+	Git *GitSinkConfig `json:"git"`
+}
+
+// GitSinkConfig configures the Git policy sink.
+type GitSinkConfig struct {
+	// URL is the repository, over HTTPS or SSH.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// Branch receives the commits. It is created if missing.
+	// +kubebuilder:default="main"
+	// +optional
+	Branch string `json:"branch,omitempty"`
+
+	// Path is the directory the source owns in the repository; one file per
+	// policy is written to <path>/<namespace>/<policy>.yaml. Defaults to
+	// audicia/<source namespace>/<source name>.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// SecretName is the Secret with the credentials: username and password
+	// (an access token) for HTTPS, or ssh-privatekey and known_hosts for
+	// SSH. The Helm chart mounts it at /etc/audicia/git-credentials, and the
+	// credentials are only sent to the URLs in its
+	// policySink.git.allowedURLs.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// BreakGlassConfig configures the handling of break-glass identities.
+type BreakGlassConfig struct {
+	// Subjects are the break-glass identities, matched by kind, name and,
+	// for service accounts, namespace.
+	// +kubebuilder:validation:MinItems=1
+	Subjects []Subject `json:"subjects"`
+
+	// Namespace receives the reports of break-glass identities.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+}
+
+// SubjectTrackingConfig configures additional subjects derived from events.
+type SubjectTrackingConfig struct {
+	// Groups also aggregates every event under each group listed in the
+	// event's user.groups, so Group-bound roles can be suggested.
+	// system:authenticated and system:unauthenticated are never tracked, as
+	// every request carries one of them; other system: groups are skipped
+	// while ignoreSystemUsers is set.
+	// +optional
+	Groups bool `json:"groups,omitempty"`
+
+	// GroupPatterns restricts group tracking to groups matching at least one
+	// of these regexes (e.g., "^oidc:"). Empty tracks every group.
+	// +optional
+	GroupPatterns []string `json:"groupPatterns,omitempty"`
+}
+
+// GapDetectionConfig configures audit stream continuity tracking.
+type GapDetectionConfig struct {
+	// ThresholdSeconds is the longest silence between consecutive audit
+	// event timestamps that is still considered continuous. The API server's
+	// own lease renewals normally produce events every few seconds, so a
+	// longer silence indicates missing data.
+	// +kubebuilder:default=300
+	// +kubebuilder:validation:Minimum=10
+	ThresholdSeconds int32 `json:"thresholdSeconds,omitempty"`
+}
+
+// DeduplicationConfig configures the event deduplication window.
+type DeduplicationConfig struct {
+	// Disabled processes every event, including duplicates.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// WindowSize is the number of recent auditID and stage pairs
+	// remembered. The least recently seen pair is forgotten first, so a
+	// duplicate is only dropped while fewer events than this arrive in
+	// between.
+	// +kubebuilder:default=10000
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=1000000
+	// +optional
+	WindowSize int32 `json:"windowSize,omitempty"`
+}
+
+// SamplingConfig configures event sampling. Sampling applies after
+// spec.filters, so events the filters drop do not use up a subject's budget.
+type SamplingConfig struct {
+	// Rate is the fraction of events kept, a decimal between 0 and 1
+	// (e.g., "0.1" keeps one event in ten, each counted ten times).
+	// +kubebuilder:default="1"
+	// +kubebuilder:validation:Pattern=`^(0?\.[0-9]*[1-9][0-9]*|1(\.0*)?)$`
+	// +optional
+	Rate string `json:"rate,omitempty"`
+
+	// PerSubjectMax caps the events kept per user and second. A user who
+	// sent more in the previous second is sampled with the probability
+	// perSubjectMax divided by that count, so one chatty controller cannot
+	// starve the other subjects. 0 disables the cap.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PerSubjectMax int32 `json:"perSubjectMax,omitempty"`
+}
+
+// IntegrityConfig configures the hash chain in report status.integrity.
+type IntegrityConfig struct {
+	// HistoryLimit is how many chain entries each report keeps. Older
+	// entries are dropped; verification starts at the oldest kept entry.
+	// +kubebuilder:default=20
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	HistoryLimit int32 `json:"historyLimit,omitempty"`
+}
+
+// FilteredEventTrackingConfig configures status.filteredEvents.
+type FilteredEventTrackingConfig struct {
+	// TopN is how many users and how many namespaces are listed.
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=50
+	// +optional
+	TopN int32 `json:"topN,omitempty"`
+}
+
+// OutputMetadata is propagated to generated objects and manifests.
+// Keys are added or updated on each flush; removing a key here does not
+// remove it from objects that already carry it.
+type OutputMetadata struct {
+	// Labels are added to every generated object and manifest.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to every generated object and manifest.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// PendingReportsConfig configures placeholder reports for unobserved ServiceAccounts.
+// Placeholders carry a NoActivityObserved condition, so consumers can tell
+// "not yet observed" apart from a missing or misconfigured source. They are
+// created at pipeline start and whenever the audit stream shows a Namespace
+// or ServiceAccount being created.
+type PendingReportsConfig struct {
+	// NamespaceSelector selects the namespaces whose ServiceAccounts get
+	// placeholder reports. An empty selector matches all namespaces.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 }
 
 // FileLocation configures file-based audit log ingestion.
 type FileLocation struct {
-	// Path is the filesystem path to the audit log file.
+	// Path is the filesystem path to the audit log file. It may be a glob
+	// (e.g. "/var/log/kubernetes/audit/*.log"), in which case every matching
+	// file is tailed concurrently with its own checkpoint in status.files.
+	// The glob is re-evaluated periodically to pick up new files.
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
 	Path string `json:"path"`
+
+	// RotatedFilePattern is a glob matching rotated copies of the audit log,
+	// e.g. "audit.log.*" or "audit-*.log.gz". Relative patterns are resolved
+	// against the directory of Path. When set, a rotation that happened while
+	// the operator was not reading (restart, slow poll) is caught up by reading
+	// the remainder of the rotated file, decompressing ".gz" archives.
+	// +optional
+	RotatedFilePattern string `json:"rotatedFilePattern,omitempty"`
 }
 
 // WebhookConfig configures webhook-based audit event ingestion.
+// +kubebuilder:validation:XValidation:rule="!has(self.agentSummaries) || !self.agentSummaries || has(self.clientCASecretName) || has(self.authTokenSecretName) || (has(self.authentication) && self.authentication.mode == 'TokenReview')",message="agentSummaries requires clientCASecretName, authTokenSecretName or TokenReview authentication"
 type WebhookConfig struct {
 	// Port is the HTTPS port for the webhook receiver.
 	// +kubebuilder:default=8443
@@ -106,6 +524,14 @@ type WebhookConfig struct {
 	// +kubebuilder:validation:Maximum=65535
 	Port int32 `json:"port,omitempty"`
 
+	// BindAddress is the IP address the receiver listens on. Empty listens
+	// on all interfaces, over IPv6 and IPv4 where the node has both. An IPv4
+	// address such as "0.0.0.0" listens over IPv4 only, and an IPv6 address
+	// such as "::" over IPv6 only. Hostnames are not accepted.
+	// +optional
+	// +kubebuilder:validation:MaxLength=64
+	BindAddress string `json:"bindAddress,omitempty"`
+
 	// TLSSecretName is the name of the Secret containing TLS cert and key.
 	// +kubebuilder:validation:Required
 	TLSSecretName string `json:"tlsSecretName"`
@@ -115,15 +541,172 @@ type WebhookConfig struct {
 	// +optional
 	ClientCASecretName string `json:"clientCASecretName,omitempty"`
 
+	// AuthTokenSecretName is the name of the Secret containing a static
+	// bearer token (key "token"). When set, requests must carry a matching
+	// "Authorization: Bearer" header. Suits apiserver webhook backends that
+	// are easier to configure with a token than a client certificate.
+	// Mutually exclusive with authentication.mode TokenReview.
+	// +optional
+	AuthTokenSecretName string `json:"authTokenSecretName,omitempty"`
+
 	// RateLimitPerSecond is the maximum number of requests per second.
 	// +kubebuilder:default=100
 	// +kubebuilder:validation:Minimum=1
 	RateLimitPerSecond int32 `json:"rateLimitPerSecond,omitempty"`
 
+	// PerClientRateLimitPerSecond is the maximum number of requests per
+	// second of a single client, identified by the common name of its
+	// client certificate or else its source IP. A client over its own limit
+	// receives 429 without using up rateLimitPerSecond, so one misconfigured
+	// API server cannot starve the others. Unset applies only the global
+	// limit.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PerClientRateLimitPerSecond int32 `json:"perClientRateLimitPerSecond,omitempty"`
+
 	// MaxRequestBodyBytes is the maximum size of a request body in bytes.
 	// +kubebuilder:default=1048576
 	// +kubebuilder:validation:Minimum=1024
 	MaxRequestBodyBytes int64 `json:"maxRequestBodyBytes,omitempty"`
+
+	// Authentication configures bearer token authentication for webhook
+	// callers, in addition to (or instead of) mTLS client certificates.
+	// +optional
+	Authentication *WebhookAuthentication `json:"authentication,omitempty"`
+
+	// AgentSummaries marks the callers of this source as edge agents
+	// (`audicia agent`): an event with an audicia.io/event-count annotation
+	// counts as that many events, at most 1000000. Otherwise the annotation
+	// is ignored. Requires authenticated callers, so that only agents
+	// holding the source's credentials can inflate counts.
+	// +optional
+	AgentSummaries bool `json:"agentSummaries,omitempty"`
+}
+
+// LocalConfig configures the developer-mode Local source. It accepts the same
+// EventList payloads as the webhook, without TLS or authentication, on either
+// a UNIX domain socket or a plain-HTTP port bound to 127.0.0.1.
+type LocalConfig struct {
+	// SocketPath is the UNIX domain socket to listen on. A stale socket file
+	// at this path is removed on start.
+	// +optional
+	SocketPath string `json:"socketPath,omitempty"`
+
+	// Port is the plain-HTTP port to listen on at 127.0.0.1. Used when
+	// SocketPath is empty.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// ForwardProtocol selects the wire protocol of a Forward source.
+// +kubebuilder:validation:Enum=Fluentd;Syslog
+type ForwardProtocol string
+
+const (
+	// ForwardProtocolFluentd is the Fluentd forward protocol (msgpack over
+	// TCP), spoken by Fluent Bit's and Fluentd's forward outputs.
+	ForwardProtocolFluentd ForwardProtocol = "Fluentd"
+	// ForwardProtocolSyslog is RFC 5424 syslog over TCP (RFC 6587 framing)
+	// whose message body is an audit log line.
+	ForwardProtocolSyslog ForwardProtocol = "Syslog"
+)
+
+// ForwardConfig configures the Forward source, which accepts audit log lines
+// from log-shipping agents instead of reading the audit log file.
+type ForwardConfig struct {
+	// Protocol is the wire protocol agents use.
+	// +kubebuilder:default=Fluentd
+	Protocol ForwardProtocol `json:"protocol,omitempty"`
+
+	// Port is the TCP port to listen on. Defaults to 24224 for Fluentd and
+	// 6514 for Syslog.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// TLSSecretName is the name of the Secret containing a TLS cert and key.
+	// When set, the listener only accepts TLS connections.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// ClientCASecretName is the name of the Secret containing the CA bundle
+	// agents' client certificates must chain to. Requires TLSSecretName.
+	// +optional
+	ClientCASecretName string `json:"clientCASecretName,omitempty"`
+
+	// MaxMessageBytes is the maximum size of one forward message or syslog
+	// frame.
+	// +kubebuilder:default=8388608
+	// +kubebuilder:validation:Minimum=1024
+	MaxMessageBytes int64 `json:"maxMessageBytes,omitempty"`
+}
+
+// GrpcConfig configures the Grpc source, which accepts batches of audit
+// events pushed over the EventPush gRPC service
+// (operator/pkg/ingestor/push/v1/push.proto).
+type GrpcConfig struct {
+	// Port is the TCP port to listen on.
+	// +kubebuilder:default=50051
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+
+	// TLSSecretName is the name of the Secret containing a TLS cert and key.
+	// When set, the server only accepts TLS connections.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// ClientCASecretName is the name of the Secret containing the CA bundle
+	// collectors' client certificates must chain to. Requires TLSSecretName.
+	// +optional
+	ClientCASecretName string `json:"clientCASecretName,omitempty"`
+
+	// AuthTokenSecretName is the name of the Secret containing a static
+	// bearer token (key "token"). When set, streams must carry a matching
+	// "authorization: Bearer" metadata entry. Mutually exclusive with
+	// authentication.mode TokenReview.
+	// +optional
+	AuthTokenSecretName string `json:"authTokenSecretName,omitempty"`
+
+	// Authentication configures bearer token authentication with
+	// TokenReview, as for the webhook: collectors present a ServiceAccount
+	// token that must hold "create" on audiciasources/ingest.
+	// +optional
+	Authentication *WebhookAuthentication `json:"authentication,omitempty"`
+
+	// MaxMessageBytes is the maximum size of one PushEventsRequest.
+	// +kubebuilder:default=4194304
+	// +kubebuilder:validation:Minimum=1024
+	MaxMessageBytes int32 `json:"maxMessageBytes,omitempty"`
+}
+
+// WebhookAuthMode selects how webhook callers present credentials.
+// +kubebuilder:validation:Enum=None;TokenReview
+type WebhookAuthMode string
+
+const (
+	// WebhookAuthModeNone accepts any caller that completes the TLS handshake.
+	WebhookAuthModeNone WebhookAuthMode = "None"
+	// WebhookAuthModeTokenReview requires a bearer token that the local
+	// cluster accepts via TokenReview, and a SubjectAccessReview granting the
+	// caller "create" on audiciasources/ingest for this source.
+	WebhookAuthModeTokenReview WebhookAuthMode = "TokenReview"
+)
+
+// WebhookAuthentication configures bearer token authentication for the
+// webhook receiver.
+type WebhookAuthentication struct {
+	// Mode selects the authentication mechanism.
+	// +kubebuilder:default=None
+	Mode WebhookAuthMode `json:"mode,omitempty"`
+
+	// Audiences restricts accepted tokens to these audiences. Empty means the
+	// API server's default audiences.
+	// +optional
+	Audiences []string `json:"audiences,omitempty"`
 }
 
 // PolicyStrategy configures how RBAC policies are generated.
@@ -141,14 +724,130 @@ type PolicyStrategy struct {
 	Wildcards WildcardMode `json:"wildcards,omitempty"`
 
 	// ResourceNames controls whether resourceNames are included in rules.
-	// "Explicit" includes observed resource names; default omits them.
+	// "Explicit" restricts rules to the observed resource names when every
+	// observation of the rule targeted a small set of named objects; default
+	// omits them.
 	// +optional
-	// +kubebuilder:validation:Enum=Omit;Explicit
 	// +kubebuilder:default=Omit
-	ResourceNames string `json:"resourceNames,omitempty"`
+	ResourceNames ResourceNamesMode `json:"resourceNames,omitempty"`
+
+	// RBACAPIVersion overrides the apiVersion of rendered RBAC manifests.
+	// By default the newest RBAC version served by the cluster that Audicia
+	// can render is used (currently rbac.authorization.k8s.io/v1). Intended
+	// for forward-compatibility testing; the manifests keep the v1 schema.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^rbac\.authorization\.k8s\.io/v[0-9]+((alpha|beta)[0-9]+)?$`
+	RBACAPIVersion string `json:"rbacAPIVersion,omitempty"`
+
+	// BaselineRules are organisation-wide rules merged into every suggested
+	// policy, regardless of observed traffic (e.g., leader-election leases).
+	// Resource rules are granted in the subject's own namespace (ServiceAccounts)
+	// or alongside observed rules (Users/Groups). Rendered roles list the
+	// baseline rules they contain in the audicia.io/baseline-rules annotation.
+	// +optional
+	BaselineRules []BaselineRule `json:"baselineRules,omitempty"`
+
+	// MinCount is the number of requests a rule must have been observed in
+	// before it enters the suggested policy, so one-off manual calls do not
+	// become permanent permissions. Rules below the threshold stay in the
+	// report and are listed in its status.ignored.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinCount int64 `json:"minCount,omitempty"`
+
+	// MinDays is the number of days between the first and last observation
+	// of a rule before it enters the suggested policy. With minCount also
+	// set, a rule meeting either threshold enters.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=365
+	// +optional
+	MinDays int32 `json:"minDays,omitempty"`
+
+	// RoleConsolidation renders a single ClusterRole, bound by a RoleBinding
+	// in each namespace, for subjects observed in many namespaces, instead
+	// of one nearly identical Role per namespace. The ClusterRole holds the
+	// rules of all those namespaces, so each binding grants the union.
+	// +optional
+	RoleConsolidation *RoleConsolidation `json:"roleConsolidation,omitempty"`
+
+	// Naming customizes the names, labels and annotations of the suggested
+	// Roles, ClusterRoles and bindings, e.g. to meet naming conventions or
+	// carry the ownership labels admission policies require.
+	// +optional
+	Naming *PolicyNaming `json:"naming,omitempty"`
+}
+
+// PolicyNaming customizes the metadata of suggested RBAC manifests. Names are
+// the prefix, the subject, "-role" or "-binding", and the suffix, e.g.
+// "suggested-alice-role".
+type PolicyNaming struct {
+	// Prefix starts every generated name. Set it to "" for no prefix.
+	// +kubebuilder:default="suggested-"
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^([a-z0-9][-a-z0-9.]*)?$`
+	// +optional
+	Prefix *string `json:"prefix,omitempty"`
+
+	// Suffix ends every generated name.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^([-a-z0-9.]*[a-z0-9])?$`
+	// +optional
+	Suffix string `json:"suffix,omitempty"`
+
+	// Labels are added to every suggested manifest, over the labels of
+	// spec.metadata.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to every suggested manifest, over the
+	// annotations of spec.metadata.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// RoleConsolidation configures when namespaced Roles are consolidated.
+type RoleConsolidation struct {
+	// MinNamespaces is the number of namespaces a subject's namespaced rules
+	// must span before they are consolidated.
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=2
+	// +optional
+	MinNamespaces int32 `json:"minNamespaces,omitempty"`
+}
+
+// BaselineRule is a user-provided RBAC rule injected into suggested policies.
+type BaselineRule struct {
+	// APIGroups is the list of API groups for this rule.
+	// +optional
+	APIGroups []string `json:"apiGroups,omitempty"`
+
+	// Resources is the list of resources (including subresources like "pods/log").
+	// +optional
+	Resources []string `json:"resources,omitempty"`
+
+	// NonResourceURLs is the list of non-resource URLs (e.g., "/healthz").
+	// Mutually exclusive with APIGroups/Resources.
+	// +optional
+	NonResourceURLs []string `json:"nonResourceURLs,omitempty"`
+
+	// Verbs is the list of verbs granted by this rule.
+	// +kubebuilder:validation:MinItems=1
+	Verbs []string `json:"verbs"`
+
+	// SubjectKinds restricts which subject kinds receive this rule.
+	// Empty means all kinds.
+	// +optional
+	SubjectKinds []SubjectKind `json:"subjectKinds,omitempty"`
 }
 
-// Filter defines a single allow/deny filter rule.
+// Weekday is a day of the week.
+// +kubebuilder:validation:Enum=Mon;Tue;Wed;Thu;Fri;Sat;Sun
+type Weekday string
+
+// Filter defines a single allow/deny filter rule. The rule matches when
+// either the user or the namespace pattern matches (if any is set) and every
+// request condition (verb, resource, API group, time window) that is set
+// matches.
 type Filter struct {
 	// Action is whether this filter allows or denies matching events.
 	// +kubebuilder:validation:Required
@@ -161,6 +860,51 @@ type Filter struct {
 	// NamespacePattern is a regex matched against the event namespace.
 	// +optional
 	NamespacePattern string `json:"namespacePattern,omitempty"`
+
+	// VerbPattern is a regex matched against the event verb.
+	// +optional
+	VerbPattern string `json:"verbPattern,omitempty"`
+
+	// ResourcePattern is a regex matched against the event resource,
+	// including the subresource (e.g., "pods/log").
+	// +optional
+	ResourcePattern string `json:"resourcePattern,omitempty"`
+
+	// APIGroupPattern is a regex matched against the event API group. The
+	// core group is the empty string.
+	// +optional
+	APIGroupPattern string `json:"apiGroupPattern,omitempty"`
+
+	// ActiveHours limits the rule to events received within a daily window,
+	// "HH:MM-HH:MM" in TimeZone. The start is inclusive, the end exclusive;
+	// a window whose end is before its start spans midnight.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]-([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// +optional
+	ActiveHours string `json:"activeHours,omitempty"`
+
+	// ActiveDays limits the rule to events received on these days in
+	// TimeZone.
+	// +optional
+	ActiveDays []Weekday `json:"activeDays,omitempty"`
+
+	// TimeZone is the IANA time zone ActiveHours and ActiveDays are
+	// evaluated in. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// SubjectAlias maps audit usernames matching a pattern onto a logical subject.
+type SubjectAlias struct {
+	// UserPattern is a regex matched against the event username.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	UserPattern string `json:"userPattern"`
+
+	// Subject is the logical subject matching identities are aggregated under.
+	// Name and Namespace may reference UserPattern capture groups (e.g., "$1"
+	// or "${name}").
+	// +kubebuilder:validation:Required
+	Subject Subject `json:"subject"`
 }
 
 // CheckpointConfig configures processing checkpoint behavior.
@@ -187,19 +931,143 @@ type LimitsConfig struct {
 	// +kubebuilder:default=30
 	// +kubebuilder:validation:Minimum=1
 	RetentionDays int32 `json:"retentionDays,omitempty"`
+
+	// RetentionPolicies override RetentionDays for the rules of some
+	// resources, e.g. to keep evidence of secrets access for 90 days but
+	// event writes for only 7. The first policy matching a rule applies;
+	// rules matching none use RetentionDays.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	RetentionPolicies []RetentionPolicy `json:"retentionPolicies,omitempty"`
+
+	// ConsolidateSubresources merges the rule of a subresource into the rule
+	// of its parent resource when both were observed with the same verb in the
+	// same namespace, e.g. pods get and pods/status get into one rule on
+	// [pods, pods/status]. The merged rule grants exactly what its parts did
+	// but counts once against MaxRulesPerReport.
+	// +optional
+	ConsolidateSubresources bool `json:"consolidateSubresources,omitempty"`
+
+	// GracePeriodHours delays changes to these limits that would drop rules.
+	// The previous limits stay in force for this many hours after the change
+	// is first observed, while status.limits previews the rules the new limits
+	// would drop. Zero applies changes at the next flush.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	GracePeriodHours int32 `json:"gracePeriodHours,omitempty"`
+
+	// ReportTTLDays expires the reports of subjects without activity for this
+	// many days. Zero keeps reports until the source is deleted.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ReportTTLDays int32 `json:"reportTTLDays,omitempty"`
+
+	// ExpiredReportAction is what happens to an expired report: Delete removes
+	// it with its AudiciaPolicy, MarkStale keeps it with a Stale condition.
+	// Defaults to Delete.
+	// +optional
+	ExpiredReportAction ExpiredReportAction `json:"expiredReportAction,omitempty"`
+
+	// MaxSubjectsPerSource caps the number of subjects this source writes
+	// reports for. The most active subjects, by events processed, are written
+	// first; the rest are listed in status.excludedSubjects. Zero is unlimited.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxSubjectsPerSource int32 `json:"maxSubjectsPerSource,omitempty"`
+
+	// MaxSubjects caps the number of subjects the pipeline aggregates in
+	// memory. A new subject beyond the cap evicts the least recently active
+	// one; its report is kept, but activity not yet flushed is lost. Evictions
+	// are reported by the SubjectsEvicted condition. Zero is unlimited.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxSubjects int32 `json:"maxSubjects,omitempty"`
+}
+
+// RetentionPolicy is the retention of the rules on a class of resources.
+type RetentionPolicy struct {
+	// Name identifies the policy.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// APIGroups restricts the policy to these API groups ("" is the core
+	// group). Empty matches every group.
+	// +optional
+	APIGroups []string `json:"apiGroups,omitempty"`
+
+	// Resources the policy applies to: a resource ("secrets"), a
+	// subresource ("pods/exec"), every subresource of a resource
+	// ("pods/*"), or "*" for all. A rule matches if any of its resources
+	// does.
+	// +kubebuilder:validation:MinItems=1
+	Resources []string `json:"resources"`
+
+	// RetentionDays is the number of days to retain matching rules that
+	// haven't been seen.
+	// +kubebuilder:validation:Minimum=1
+	RetentionDays int32 `json:"retentionDays"`
+}
+
+// LimitsStatus records the limits in force and any change waiting out
+// spec.limits.gracePeriodHours.
+type LimitsStatus struct {
+	// Applied are the limits the last flush compacted reports with.
+	Applied LimitsConfig `json:"applied"`
+
+	// Pending are the limits from spec.limits waiting out the grace period.
+	// Empty when spec.limits is in force.
+	// +optional
+	Pending *LimitsConfig `json:"pending,omitempty"`
+
+	// PendingSince is when the pending limits were first observed.
+	// +optional
+	PendingSince *metav1.Time `json:"pendingSince,omitempty"`
+
+	// PendingDroppedRules is the number of additional rules the pending
+	// limits would have dropped at the last flush.
+	// +optional
+	PendingDroppedRules int32 `json:"pendingDroppedRules,omitempty"`
+
+	// PendingAffectedSubjects is the number of subjects that would lose rules
+	// under the pending limits.
+	// +optional
+	PendingAffectedSubjects int32 `json:"pendingAffectedSubjects,omitempty"`
 }
 
 // CloudProvider defines supported cloud providers for audit log ingestion.
-// +kubebuilder:validation:Enum=AzureEventHub;AWSCloudWatch;GCPPubSub
+// +kubebuilder:validation:Enum=AzureEventHub;AWSCloudWatch;AWSS3;GCPPubSub;Kafka
 type CloudProvider string
 
 const (
 	CloudProviderAzureEventHub CloudProvider = "AzureEventHub"
 	CloudProviderAWSCloudWatch CloudProvider = "AWSCloudWatch"
+	CloudProviderAWSS3         CloudProvider = "AWSS3"
 	CloudProviderGCPPubSub     CloudProvider = "GCPPubSub"
+	CloudProviderKafka         CloudProvider = "Kafka"
+)
+
+// ClusterIdentityEnforcement defines what happens to cloud events whose
+// cluster identity does not match spec.cloud.clusterIdentity.
+// +kubebuilder:validation:Enum=Permissive;Strict
+type ClusterIdentityEnforcement string
+
+const (
+	// ClusterIdentityEnforcementPermissive ingests every event and only logs
+	// those that cannot be attributed to the cluster.
+	ClusterIdentityEnforcementPermissive ClusterIdentityEnforcement = "Permissive"
+
+	// ClusterIdentityEnforcementStrict drops events that name a different
+	// cluster or carry no cluster identity.
+	ClusterIdentityEnforcementStrict ClusterIdentityEnforcement = "Strict"
 )
 
-// CloudConfig configures cloud-based audit log ingestion.
+// CloudConfig configures cloud-based audit log ingestion. Only the settings
+// block of the selected provider may be set.
+// +kubebuilder:validation:XValidation:rule="(!has(self.azure) || self.provider == 'AzureEventHub') && (!has(self.aws) || self.provider == 'AWSCloudWatch') && (!has(self.s3) || self.provider == 'AWSS3') && (!has(self.gcp) || self.provider == 'GCPPubSub') && (!has(self.kafka) || self.provider == 'Kafka')",message="only the settings block of the selected provider may be set"
 type CloudConfig struct {
 	// Provider specifies the cloud platform.
 	// +kubebuilder:validation:Required
@@ -211,6 +1079,18 @@ type CloudConfig struct {
 	// +kubebuilder:validation:Required
 	ClusterIdentity string `json:"clusterIdentity"`
 
+	// ClusterIdentityEnforcement controls events that cannot be attributed
+	// to ClusterIdentity. Permissive ingests them; Strict drops events from
+	// another cluster and events without a cluster identity, so clusters
+	// sharing an Event Hub or Pub/Sub topic do not contaminate each other's
+	// policies. The identity is read from the AKS Diagnostic Settings
+	// resourceId or the GKE log entry's resource labels and must equal
+	// ClusterIdentity, ignoring case; AWS providers carry none and do not
+	// support Strict.
+	// +kubebuilder:default=Permissive
+	// +optional
+	ClusterIdentityEnforcement ClusterIdentityEnforcement `json:"clusterIdentityEnforcement,omitempty"`
+
 	// Azure contains Azure Event Hub-specific configuration.
 	// +optional
 	Azure *AzureEventHubConfig `json:"azure,omitempty"`
@@ -219,9 +1099,17 @@ type CloudConfig struct {
 	// +optional
 	AWS *AWSCloudWatchConfig `json:"aws,omitempty"`
 
+	// S3 contains AWS S3-specific configuration.
+	// +optional
+	S3 *AWSS3Config `json:"s3,omitempty"`
+
 	// GCP contains GCP Pub/Sub-specific configuration.
 	// +optional
 	GCP *GCPPubSubConfig `json:"gcp,omitempty"`
+
+	// Kafka contains Kafka-specific configuration.
+	// +optional
+	Kafka *KafkaConfig `json:"kafka,omitempty"`
 }
 
 // AzureEventHubConfig configures Azure Event Hub-based ingestion.
@@ -249,6 +1137,19 @@ type AzureEventHubConfig struct {
 	// StorageContainerName is the blob container name for checkpoints.
 	// +optional
 	StorageContainerName string `json:"storageContainerName,omitempty"`
+
+	// ClientID is the client ID of the Azure managed identity (or app
+	// registration) this source authenticates as via Workload Identity
+	// federation. Each source with a ClientID gets its own credential, so
+	// several sources can consume Event Hubs owned by different identities.
+	// If empty, the process-wide default credential chain is used.
+	// +optional
+	ClientID string `json:"clientID,omitempty"`
+
+	// TenantID is the Azure AD tenant of ClientID. If empty, AZURE_TENANT_ID
+	// from the environment (set by the Workload Identity webhook) is used.
+	// +optional
+	TenantID string `json:"tenantID,omitempty"`
 }
 
 // AWSCloudWatchConfig configures AWS CloudWatch-based ingestion.
@@ -259,12 +1160,60 @@ type AWSCloudWatchConfig struct {
 	Region string `json:"region,omitempty"`
 
 	// LogGroupName is the CloudWatch Logs group containing audit logs.
-	// +kubebuilder:validation:Required
-	LogGroupName string `json:"logGroupName"`
+	// Either it or LogGroupNames must be set.
+	// +optional
+	LogGroupName string `json:"logGroupName,omitempty"`
+
+	// LogGroupNames are further log groups read by the same source, e.g. the
+	// groups of several EKS clusters sharing one operator. Each group is
+	// polled in turn and checkpointed on its own.
+	// +optional
+	// +listType=set
+	LogGroupNames []string `json:"logGroupNames,omitempty"`
 
 	// LogStreamPrefix is an optional stream name prefix filter.
 	// +optional
 	LogStreamPrefix string `json:"logStreamPrefix,omitempty"`
+
+	// FilterPattern is a CloudWatch Logs filter pattern applied by
+	// CloudWatch before events are returned, so events the source would drop
+	// are not pulled and paid for (e.g., { $.stage = "ResponseComplete" }).
+	// +optional
+	FilterPattern string `json:"filterPattern,omitempty"`
+
+	// RoleARN is an IAM role assumed via STS for this source. The operator's
+	// base identity (e.g., IRSA) must be allowed to assume it. Each source
+	// assumes its own role in an isolated session, so sources reading log
+	// groups in different accounts can run side by side. If empty, the base
+	// identity is used directly.
+	// +optional
+	RoleARN string `json:"roleARN,omitempty"`
+}
+
+// AWSS3Config configures ingestion of EKS audit logs archived to S3, e.g. by
+// a CloudWatch Logs subscription through Kinesis Data Firehose or by log
+// export tasks. Objects are read in key order, so keys must sort
+// chronologically (as date-based prefixes do).
+type AWSS3Config struct {
+	// Region is the AWS region of the bucket.
+	// If empty, uses AWS_REGION from environment (set by IRSA).
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Bucket is the S3 bucket containing audit log objects.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=3
+	Bucket string `json:"bucket"`
+
+	// Prefix restricts ingestion to keys under this prefix
+	// (e.g., "AWSLogs/123456789012/eks/eu-west-1/prod/audit/").
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// RoleARN is an IAM role assumed via STS for this source. If empty, the
+	// operator's base identity is used directly.
+	// +optional
+	RoleARN string `json:"roleARN,omitempty"`
 }
 
 // GCPPubSubConfig configures GCP Pub/Sub-based ingestion (placeholder).
@@ -276,6 +1225,72 @@ type GCPPubSubConfig struct {
 	// SubscriptionID is the Pub/Sub subscription name.
 	// +kubebuilder:validation:Required
 	SubscriptionID string `json:"subscriptionID"`
+
+	// ImpersonateServiceAccount is the email of a GCP service account this
+	// source impersonates. The operator's base identity (e.g., GKE Workload
+	// Identity) needs roles/iam.serviceAccountTokenCreator on it. If empty,
+	// Application Default Credentials are used directly.
+	// +optional
+	ImpersonateServiceAccount string `json:"impersonateServiceAccount,omitempty"`
+}
+
+// KafkaSASLMechanism is a SASL mechanism supported for Kafka authentication.
+// +kubebuilder:validation:Enum=PLAIN;SCRAM-SHA-256;SCRAM-SHA-512
+type KafkaSASLMechanism string
+
+const (
+	KafkaSASLPlain       KafkaSASLMechanism = "PLAIN"
+	KafkaSASLScramSHA256 KafkaSASLMechanism = "SCRAM-SHA-256"
+	KafkaSASLScramSHA512 KafkaSASLMechanism = "SCRAM-SHA-512"
+)
+
+// KafkaConfig configures ingestion from a Kafka topic carrying Kubernetes
+// audit events, one event, event array or EventList per record.
+type KafkaConfig struct {
+	// Brokers are the seed brokers as host:port.
+	// +kubebuilder:validation:MinItems=1
+	Brokers []string `json:"brokers"`
+
+	// Topic is the topic audit events are published to.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Topic string `json:"topic"`
+
+	// ConsumerGroup is the consumer group used to share partitions between
+	// replicas and to commit offsets.
+	// +kubebuilder:default="audicia"
+	// +optional
+	ConsumerGroup string `json:"consumerGroup,omitempty"`
+
+	// TLS enables TLS to the brokers.
+	// +optional
+	TLS *KafkaTLSConfig `json:"tls,omitempty"`
+
+	// SASL enables SASL authentication.
+	// +optional
+	SASL *KafkaSASLConfig `json:"sasl,omitempty"`
+}
+
+// KafkaTLSConfig configures TLS to the Kafka brokers.
+type KafkaTLSConfig struct {
+	// SecretName is an optional Secret with ca.crt to verify the brokers and,
+	// for mutual TLS, tls.crt and tls.key. The Helm chart mounts it at
+	// /etc/audicia/kafka-tls. If empty, the system roots are used.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// KafkaSASLConfig configures SASL authentication to the Kafka brokers.
+type KafkaSASLConfig struct {
+	// Mechanism is the SASL mechanism.
+	// +kubebuilder:default="SCRAM-SHA-512"
+	// +optional
+	Mechanism KafkaSASLMechanism `json:"mechanism,omitempty"`
+
+	// SecretName is the Secret with the username and password keys. The Helm
+	// chart mounts it at /etc/audicia/kafka-sasl.
+	// +kubebuilder:validation:Required
+	SecretName string `json:"secretName"`
 }
 
 // CloudCheckpointStatus stores cloud-specific checkpoint data.
@@ -286,6 +1301,228 @@ type CloudCheckpointStatus struct {
 	PartitionOffsets map[string]string `json:"partitionOffsets,omitempty"`
 }
 
+// FileCheckpointStatus is the checkpoint of one file matched by a glob path.
+type FileCheckpointStatus struct {
+	// Path is the matched file.
+	Path string `json:"path"`
+
+	// FileOffset is the byte offset of the last processed position.
+	// +optional
+	FileOffset int64 `json:"fileOffset,omitempty"`
+
+	// Inode is the inode number of the file (for rotation detection).
+	// +optional
+	Inode uint64 `json:"inode,omitempty"`
+}
+
+// FlushStatus summarises a report flush across all subjects.
+type FlushStatus struct {
+	// Time is when the flush finished.
+	Time metav1.Time `json:"time"`
+
+	// Succeeded is the number of subjects whose report and policy were written.
+	Succeeded int32 `json:"succeeded"`
+
+	// Failed is the number of subjects that failed to flush.
+	Failed int32 `json:"failed"`
+
+	// PendingRetry is the number of subjects queued for retry with backoff.
+	// +optional
+	PendingRetry int32 `json:"pendingRetry,omitempty"`
+
+	// Unchanged is the number of subjects skipped because nothing was
+	// observed for them since their report was last written.
+	// +optional
+	Unchanged int32 `json:"unchanged,omitempty"`
+
+	// Deferred is the number of changed subjects left for the next flush
+	// to stay within the operator's flush rate limit.
+	// +optional
+	Deferred int32 `json:"deferred,omitempty"`
+}
+
+// GapKind classifies where an ingestion gap was detected.
+// +kubebuilder:validation:Enum=Downtime;Stream
+type GapKind string
+
+const (
+	// GapKindDowntime is a gap spanning a pipeline restart, e.g. webhook
+	// downtime or a cloud checkpoint resumed past the provider's retention.
+	GapKindDowntime GapKind = "Downtime"
+	// GapKindStream is a gap inside a running pipeline, e.g. a missed log
+	// rotation or a stalled forwarder.
+	GapKindStream GapKind = "Stream"
+)
+
+// IngestionGap is a period for which no audit events were ingested.
+type IngestionGap struct {
+	// Kind classifies where the gap was detected.
+	Kind GapKind `json:"kind"`
+
+	// Start is the timestamp of the last event before the gap.
+	Start metav1.Time `json:"start"`
+
+	// End is the timestamp of the first event after the gap.
+	End metav1.Time `json:"end"`
+
+	// MissedSeconds is the estimated duration of missing observation.
+	MissedSeconds int64 `json:"missedSeconds"`
+}
+
+// GapStatus summarises detected ingestion gaps.
+type GapStatus struct {
+	// LastEventTime is the newest audit event timestamp seen. It carries
+	// continuity tracking across pipeline restarts.
+	// +optional
+	LastEventTime *metav1.Time `json:"lastEventTime,omitempty"`
+
+	// Count is the total number of gaps detected.
+	Count int32 `json:"count"`
+
+	// TotalMissedSeconds is the estimated total time without observation.
+	TotalMissedSeconds int64 `json:"totalMissedSeconds"`
+
+	// Recent lists the most recent gaps, oldest first (at most 10).
+	// +optional
+	Recent []IngestionGap `json:"recent,omitempty"`
+}
+
+// FilteredEventCount is the number of denied events for one user or namespace.
+type FilteredEventCount struct {
+	// Name is the username or namespace.
+	Name string `json:"name"`
+
+	// Count is the number of events denied. Counts are approximate once more
+	// distinct names were denied than the tracker holds.
+	Count int64 `json:"count"`
+}
+
+// FilteredEventsStatus lists who spec.filters deny most often.
+type FilteredEventsStatus struct {
+	// Since is when counting started. Counts carry over pipeline restarts.
+	Since metav1.Time `json:"since"`
+
+	// Total is the number of events denied since then.
+	Total int64 `json:"total"`
+
+	// Users lists the most frequently denied usernames, highest count first.
+	// +optional
+	Users []FilteredEventCount `json:"users,omitempty"`
+
+	// Namespaces lists the most frequently denied namespaces, highest count
+	// first. Cluster-scoped requests are not counted here.
+	// +optional
+	Namespaces []FilteredEventCount `json:"namespaces,omitempty"`
+}
+
+// EventTimestampsStatus counts the events that carried no timestamp, whose
+// rules fall back to the time the operator processed them.
+type EventTimestampsStatus struct {
+	// Events is the number of recent events. Both counts are halved once
+	// Events exceeds 100000, so they follow recent traffic and a fixed
+	// audit pipeline clears the EventTimestamps condition.
+	Events int64 `json:"events"`
+
+	// Missing is the number of those events without a requestReceivedTimestamp
+	// or stageTimestamp.
+	Missing int64 `json:"missing"`
+
+	// LastMissingTime is when the last event without a timestamp was
+	// processed.
+	// +optional
+	LastMissingTime *metav1.Time `json:"lastMissingTime,omitempty"`
+}
+
+// ExcludedSubject is a subject without a report because of
+// spec.limits.maxSubjectsPerSource.
+type ExcludedSubject struct {
+	Subject Subject `json:"subject"`
+
+	// EventsProcessed is the number of events observed for the subject.
+	EventsProcessed int64 `json:"eventsProcessed"`
+}
+
+// ExcludedSubjectsStatus lists the subjects spec.limits.maxSubjectsPerSource
+// keeps from getting reports.
+type ExcludedSubjectsStatus struct {
+	// Total is the number of excluded subjects.
+	Total int32 `json:"total"`
+
+	// Subjects lists the most active excluded subjects, most active first.
+	// +optional
+	Subjects []ExcludedSubject `json:"subjects,omitempty"`
+}
+
+// PolicySinkStatus records the last successful publication of the source's
+// policies.
+type PolicySinkStatus struct {
+	// LastSyncTime is when the sink last held the current policies.
+	LastSyncTime metav1.Time `json:"lastSyncTime"`
+
+	// LastCommit is the most recent commit pushed by the Git sink.
+	// +optional
+	LastCommit string `json:"lastCommit,omitempty"`
+
+	// Policies is the number of policies published.
+	Policies int32 `json:"policies"`
+}
+
+// StorageEstimate is the estimated etcd storage used by Audicia objects: the
+// size of their JSON serialization, managed fields included, which is how
+// the API server stores custom resources.
+type StorageEstimate struct {
+	// Reports is the number of AudiciaReports counted.
+	Reports int32 `json:"reports"`
+
+	// Policies is the number of AudiciaPolicies counted.
+	Policies int32 `json:"policies"`
+
+	// Bytes is the estimated size of the source, its reports and its
+	// policies.
+	Bytes int64 `json:"bytes"`
+
+	// EstimatedTime is when the estimate was taken.
+	EstimatedTime metav1.Time `json:"estimatedTime"`
+}
+
+// PipelineErrorCategory classifies the pipeline stage an error occurred in.
+// +kubebuilder:validation:Enum=Ingestion;Flush;Checkpoint;Expiry;PolicySink
+type PipelineErrorCategory string
+
+const (
+	// PipelineErrorIngestion is an error reading from the audit source,
+	// e.g. cloud API throttling or a file read racing a rotation.
+	PipelineErrorIngestion PipelineErrorCategory = "Ingestion"
+	// PipelineErrorFlush is an error writing a report or policy.
+	PipelineErrorFlush PipelineErrorCategory = "Flush"
+	// PipelineErrorCheckpoint is an error persisting the checkpoint.
+	PipelineErrorCheckpoint PipelineErrorCategory = "Checkpoint"
+	// PipelineErrorExpiry is an error deleting expired reports.
+	PipelineErrorExpiry PipelineErrorCategory = "Expiry"
+	// PipelineErrorPolicySink is an error publishing policies to
+	// spec.policySink.
+	PipelineErrorPolicySink PipelineErrorCategory = "PolicySink"
+)
+
+// PipelineError is an error the pipeline recovered from. Consecutive
+// occurrences of the same error are collapsed into one entry.
+type PipelineError struct {
+	// Category is the pipeline stage the error occurred in.
+	Category PipelineErrorCategory `json:"category"`
+
+	// Message is the error message, truncated to 512 characters.
+	Message string `json:"message"`
+
+	// FirstSeen is when the error first occurred.
+	FirstSeen metav1.Time `json:"firstSeen"`
+
+	// LastSeen is when the error last occurred.
+	LastSeen metav1.Time `json:"lastSeen"`
+
+	// Count is the number of consecutive occurrences.
+	Count int32 `json:"count"`
+}
+
 // AudiciaSourceStatus defines the observed state of an AudiciaSource.
 type AudiciaSourceStatus struct {
 	// FileOffset is the byte offset of the last processed position in the audit log file.
@@ -300,10 +1537,72 @@ type AudiciaSourceStatus struct {
 	// +optional
 	Inode uint64 `json:"inode,omitempty"`
 
+	// Files stores per-file checkpoints when spec.location.path is a glob.
+	// FileOffset and Inode are unused in that case.
+	// +optional
+	// +listType=map
+	// +listMapKey=path
+	Files []FileCheckpointStatus `json:"files,omitempty"`
+
 	// CloudCheckpoint stores resumption state for cloud audit log sources.
 	// +optional
 	CloudCheckpoint *CloudCheckpointStatus `json:"cloudCheckpoint,omitempty"`
 
+	// LastCheckpointTime is when the ingestion checkpoint was last persisted successfully.
+	// If it falls far behind, a restart will replay events since this time.
+	// +optional
+	LastCheckpointTime *metav1.Time `json:"lastCheckpointTime,omitempty"`
+
+	// LastFlush summarises the most recent report flush attempt.
+	// +optional
+	LastFlush *FlushStatus `json:"lastFlush,omitempty"`
+
+	// Gaps summarises detected audit stream gaps (spec.gapDetection).
+	// +optional
+	Gaps *GapStatus `json:"gaps,omitempty"`
+
+	// FilteredEvents lists the users and namespaces most often denied by
+	// spec.filters (spec.filteredEventTracking).
+	// +optional
+	FilteredEvents *FilteredEventsStatus `json:"filteredEvents,omitempty"`
+
+	// EventTimestamps counts the events that carried no timestamp. While
+	// their share is significant, the EventTimestamps condition is False.
+	// +optional
+	EventTimestamps *EventTimestampsStatus `json:"eventTimestamps,omitempty"`
+
+	// Limits records the retention limits in force and any pending change.
+	// +optional
+	Limits *LimitsStatus `json:"limits,omitempty"`
+
+	// ExcludedSubjects lists the observed subjects without reports because
+	// of spec.limits.maxSubjectsPerSource.
+	// +optional
+	ExcludedSubjects *ExcludedSubjectsStatus `json:"excludedSubjects,omitempty"`
+
+	// PolicySink records the last successful publication of the source's
+	// policies (spec.policySink).
+	// +optional
+	PolicySink *PolicySinkStatus `json:"policySink,omitempty"`
+
+	// RecentErrors holds the last errors the pipeline recovered from,
+	// oldest first, so intermittent failures can be diagnosed after the
+	// fact without long-term log retention.
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=10
+	RecentErrors []PipelineError `json:"recentErrors,omitempty"`
+
+	// ListenAddress is the address the receiver of a Webhook source listens
+	// on, as host:port, e.g. "[::]:8443" when bound to all interfaces.
+	// +optional
+	ListenAddress string `json:"listenAddress,omitempty"`
+
+	// Storage estimates the etcd storage used by the source and the reports
+	// and policies it writes. It is refreshed periodically by the operator.
+	// +optional
+	Storage *StorageEstimate `json:"storage,omitempty"`
+
 	// Conditions represent the latest available observations of the source's state.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
@@ -46,6 +46,13 @@ type ObservedRule struct {
 	// +optional
 	NonResourceURLs []string `json:"nonResourceURLs,omitempty"`
 
+	// ResourceNames lists the named objects this rule was observed on. It is
+	// only set while every observation targeted a named object and at most a
+	// handful of distinct names were seen; collection requests (list, watch,
+	// create) clear it.
+	// +optional
+	ResourceNames []string `json:"resourceNames,omitempty"`
+
 	// Namespace is the namespace where this rule was observed.
 	// Empty for cluster-scoped resources or non-resource URLs.
 	// +optional
@@ -60,6 +67,90 @@ type ObservedRule struct {
 	// Count is the number of times this rule was observed.
 	// +kubebuilder:validation:Minimum=1
 	Count int64 `json:"count"`
+
+	// Preset names the built-in housekeeping summary this rule stands for
+	// (e.g., "events", "leader-election"). Preset rules carry the observed
+	// verbs of the preset in a single rule.
+	// +optional
+	Preset string `json:"preset,omitempty"`
+
+	// Incomplete is true while the rule has only been observed from requests
+	// that had not completed (ResponseStarted) or that panicked. Set only
+	// when the source processes those stages (spec.captureIncompleteStages
+	// or spec.stages).
+	// +optional
+	Incomplete bool `json:"incomplete,omitempty"`
+
+	// TimestampFallback is true when no event of the rule carried a
+	// timestamp, so FirstSeen and LastSeen are the times the operator
+	// processed the events rather than when the requests were made. The
+	// first event with a timestamp replaces both and clears the mark.
+	// +optional
+	TimestampFallback bool `json:"timestampFallback,omitempty"`
+
+	// PriorConfiguration is true when the rule was last observed before the
+	// source's filter or strategy configuration changed
+	// (status.configuration.changedTime of the report). The rule may not be
+	// observed, or not in this form, under the current configuration.
+	// +optional
+	PriorConfiguration bool `json:"priorConfiguration,omitempty"`
+
+	// AdmissionDenied counts the observations that RBAC allowed but admission
+	// control (a validating webhook, a ValidatingAdmissionPolicy or a quota)
+	// rejected. The subject holds the permission, so the rule stays in the
+	// suggested policy; the rejections are an admission problem, not an RBAC
+	// gap.
+	// +optional
+	AdmissionDenied int64 `json:"admissionDenied,omitempty"`
+
+	// Provenance locates the first and last observation in the ingested
+	// audit stream (spec.ruleProvenance).
+	// +optional
+	Provenance *RuleProvenance `json:"provenance,omitempty"`
+}
+
+// RuleProvenance locates a rule's observations in the ingested audit stream.
+type RuleProvenance struct {
+	// FirstSeen is the range of the flush that first recorded the rule. It is
+	// unset for rules observed before provenance was enabled.
+	// +optional
+	FirstSeen *CheckpointRange `json:"firstSeen,omitempty"`
+
+	// LastSeen is the range of the flush that last recorded the rule.
+	LastSeen CheckpointRange `json:"lastSeen"`
+}
+
+// CheckpointRange is the part of the audit stream ingested between two
+// checkpoints. Events an ingestor has read but not yet handed over at a
+// checkpoint belong to the next range, so a range may start up to one read
+// batch after its first event.
+type CheckpointRange struct {
+	// From is the checkpoint the range starts at.
+	From IngestionPosition `json:"from"`
+
+	// To is the checkpoint the range ends at.
+	To IngestionPosition `json:"to"`
+}
+
+// IngestionPosition is a checkpoint of the ingested audit stream. Glob
+// sources and cloud sources only list the files and partitions that moved
+// within the range.
+type IngestionPosition struct {
+	// FileOffset is the byte offset in the audit log file.
+	// +optional
+	FileOffset int64 `json:"fileOffset,omitempty"`
+
+	// Inode is the inode of the audit log file.
+	// +optional
+	Inode uint64 `json:"inode,omitempty"`
+
+	// Files holds per-file offsets when spec.location.path is a glob.
+	// +optional
+	Files []FileCheckpointStatus `json:"files,omitempty"`
+
+	// PartitionOffsets maps partitions to sequence numbers for cloud sources.
+	// +optional
+	PartitionOffsets map[string]string `json:"partitionOffsets,omitempty"`
 }
 
 // ComplianceSeverity represents the compliance level.
@@ -113,6 +204,15 @@ type ComplianceReport struct {
 	// +optional
 	UncoveredRules []ComplianceRule `json:"uncoveredRules,omitempty"`
 
+	// Remediation lists the changes to existing bindings and roles that take
+	// the excess rules away from the subject, binding by binding. Steps that
+	// would affect other subjects replace the binding with the suggested
+	// policy instead of editing a shared role.
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=50
+	Remediation []RemediationStep `json:"remediation,omitempty"`
+
 	// LastEvaluatedTime is when the compliance check was last run.
 	LastEvaluatedTime metav1.Time `json:"lastEvaluatedTime"`
 }
@@ -137,3 +237,83 @@ type ComplianceRule struct {
 	// +optional
 	Namespace string `json:"namespace,omitempty"`
 }
+
+// RemediationAction is the change a remediation step makes.
+// +kubebuilder:validation:Enum=DeleteBinding;RemoveSubject;ReplaceBinding;RemoveRules;DeleteRole
+type RemediationAction string
+
+const (
+	// RemediationDeleteBinding deletes a binding of the subject alone whose
+	// rules were never used.
+	RemediationDeleteBinding RemediationAction = "DeleteBinding"
+
+	// RemediationRemoveSubject removes the subject from a binding with other
+	// subjects whose rules the subject never used.
+	RemediationRemoveSubject RemediationAction = "RemoveSubject"
+
+	// RemediationReplaceBinding moves the subject from a binding to a role
+	// shared with other subjects, or reconciled by Kubernetes, onto the
+	// suggested policy: apply the policy, then delete the binding or apply
+	// the patch removing the subject.
+	RemediationReplaceBinding RemediationAction = "ReplaceBinding"
+
+	// RemediationRemoveRules removes unused rules from a role only the
+	// subject is bound to.
+	RemediationRemoveRules RemediationAction = "RemoveRules"
+
+	// RemediationDeleteRole deletes a role only the subject was bound to,
+	// after its binding is deleted.
+	RemediationDeleteRole RemediationAction = "DeleteRole"
+)
+
+// RemediationTarget identifies the RBAC object a remediation step changes.
+type RemediationTarget struct {
+	// Kind is Role, ClusterRole, RoleBinding or ClusterRoleBinding.
+	// +kubebuilder:validation:Enum=Role;ClusterRole;RoleBinding;ClusterRoleBinding
+	Kind string `json:"kind"`
+
+	// Namespace of the object. Empty for cluster-scoped objects.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the object.
+	Name string `json:"name"`
+}
+
+// RemediationStep is a change to an existing binding or role that removes
+// excess permissions from the subject.
+type RemediationStep struct {
+	// Action is the change to make.
+	Action RemediationAction `json:"action"`
+
+	// Target is the object to change.
+	Target RemediationTarget `json:"target"`
+
+	// Rules lists the excess rules the step takes away from the subject.
+	// +optional
+	Rules []ComplianceRule `json:"rules,omitempty"`
+
+	// Patch is a JSON patch (RFC 6902) making the change, for
+	// `kubectl patch --type=json`. Its test operations fail the patch if the
+	// object changed since the evaluation. Empty for deletions.
+	// +optional
+	Patch string `json:"patch,omitempty"`
+
+	// Manifest is the role with the unused rules removed, as YAML, for
+	// RemoveRules steps.
+	// +optional
+	Manifest string `json:"manifest,omitempty"`
+}
+
+// GeneratorInfo identifies the operator build that produced an artifact, so
+// artifacts rendered by a version with a known bug can be found and
+// regenerated.
+type GeneratorInfo struct {
+	// Version is the operator release version.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Commit is the source commit the operator was built from.
+	// +optional
+	Commit string `json:"commit,omitempty"`
+}
@@ -28,6 +28,11 @@ import (
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSCloudWatchConfig) DeepCopyInto(out *AWSCloudWatchConfig) {
 	*out = *in
+	if in.LogGroupNames != nil {
+		in, out := &in.LogGroupNames, &out.LogGroupNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSCloudWatchConfig.
@@ -40,6 +45,46 @@ func (in *AWSCloudWatchConfig) DeepCopy() *AWSCloudWatchConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSS3Config) DeepCopyInto(out *AWSS3Config) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSS3Config.
+func (in *AWSS3Config) DeepCopy() *AWSS3Config {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSS3Config)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActivitySummary) DeepCopyInto(out *ActivitySummary) {
+	*out = *in
+	if in.ByHour != nil {
+		in, out := &in.ByHour, &out.ByHour
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.ByDay != nil {
+		in, out := &in.ByDay, &out.ByDay
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivitySummary.
+func (in *ActivitySummary) DeepCopy() *ActivitySummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ActivitySummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AudiciaPolicy) DeepCopyInto(out *AudiciaPolicy) {
 	*out = *in
@@ -99,6 +144,123 @@ func (in *AudiciaPolicyList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AudiciaPolicyPlan) DeepCopyInto(out *AudiciaPolicyPlan) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AudiciaPolicyPlan.
+func (in *AudiciaPolicyPlan) DeepCopy() *AudiciaPolicyPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(AudiciaPolicyPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AudiciaPolicyPlan) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AudiciaPolicyPlanList) DeepCopyInto(out *AudiciaPolicyPlanList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AudiciaPolicyPlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AudiciaPolicyPlanList.
+func (in *AudiciaPolicyPlanList) DeepCopy() *AudiciaPolicyPlanList {
+	if in == nil {
+		return nil
+	}
+	out := new(AudiciaPolicyPlanList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AudiciaPolicyPlanList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AudiciaPolicyPlanSpec) DeepCopyInto(out *AudiciaPolicyPlanSpec) {
+	*out = *in
+	if in.PolicyRefs != nil {
+		in, out := &in.PolicyRefs, &out.PolicyRefs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AudiciaPolicyPlanSpec.
+func (in *AudiciaPolicyPlanSpec) DeepCopy() *AudiciaPolicyPlanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AudiciaPolicyPlanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AudiciaPolicyPlanStatus) DeepCopyInto(out *AudiciaPolicyPlanStatus) {
+	*out = *in
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]PlanObject, len(*in))
+		copy(*out, *in)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]PlanApplication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastDriftCheckTime != nil {
+		in, out := &in.LastDriftCheckTime, &out.LastDriftCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AudiciaPolicyPlanStatus.
+func (in *AudiciaPolicyPlanStatus) DeepCopy() *AudiciaPolicyPlanStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AudiciaPolicyPlanStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AudiciaPolicySpec) DeepCopyInto(out *AudiciaPolicySpec) {
 	*out = *in
@@ -127,6 +289,11 @@ func (in *AudiciaPolicyStatus) DeepCopyInto(out *AudiciaPolicyStatus) {
 		in, out := &in.ApprovedTime, &out.ApprovedTime
 		*out = (*in).DeepCopy()
 	}
+	if in.GeneratedBy != nil {
+		in, out := &in.GeneratedBy, &out.GeneratedBy
+		*out = new(GeneratorInfo)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -231,15 +398,56 @@ func (in *AudiciaReportStatus) DeepCopyInto(out *AudiciaReportStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DeniedRules != nil {
+		in, out := &in.DeniedRules, &out.DeniedRules
+		*out = make([]ObservedRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ignored != nil {
+		in, out := &in.Ignored, &out.Ignored
+		*out = make([]IgnoredVerb, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Compliance != nil {
 		in, out := &in.Compliance, &out.Compliance
 		*out = new(ComplianceReport)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Activity != nil {
+		in, out := &in.Activity, &out.Activity
+		*out = new(ActivitySummary)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.LastProcessedTime != nil {
 		in, out := &in.LastProcessedTime, &out.LastProcessedTime
 		*out = (*in).DeepCopy()
 	}
+	if in.GeneratedBy != nil {
+		in, out := &in.GeneratedBy, &out.GeneratedBy
+		*out = new(GeneratorInfo)
+		**out = **in
+	}
+	if in.Configuration != nil {
+		in, out := &in.Configuration, &out.Configuration
+		*out = new(ReportConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Integrity != nil {
+		in, out := &in.Integrity, &out.Integrity
+		*out = new(IntegrityStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]ReportSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -329,21 +537,103 @@ func (in *AudiciaSourceSpec) DeepCopyInto(out *AudiciaSourceSpec) {
 	if in.Webhook != nil {
 		in, out := &in.Webhook, &out.Webhook
 		*out = new(WebhookConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Cloud != nil {
 		in, out := &in.Cloud, &out.Cloud
 		*out = new(CloudConfig)
 		(*in).DeepCopyInto(*out)
 	}
-	out.PolicyStrategy = in.PolicyStrategy
+	if in.Local != nil {
+		in, out := &in.Local, &out.Local
+		*out = new(LocalConfig)
+		**out = **in
+	}
+	if in.Forward != nil {
+		in, out := &in.Forward, &out.Forward
+		*out = new(ForwardConfig)
+		**out = **in
+	}
+	if in.Grpc != nil {
+		in, out := &in.Grpc, &out.Grpc
+		*out = new(GrpcConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	in.PolicyStrategy.DeepCopyInto(&out.PolicyStrategy)
 	if in.Filters != nil {
 		in, out := &in.Filters, &out.Filters
 		*out = make([]Filter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SubjectAliases != nil {
+		in, out := &in.SubjectAliases, &out.SubjectAliases
+		*out = make([]SubjectAlias, len(*in))
 		copy(*out, *in)
 	}
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]AuditStage, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubjectTracking != nil {
+		in, out := &in.SubjectTracking, &out.SubjectTracking
+		*out = new(SubjectTrackingConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	out.Checkpoint = in.Checkpoint
-	out.Limits = in.Limits
+	in.Limits.DeepCopyInto(&out.Limits)
+	if in.PendingReports != nil {
+		in, out := &in.PendingReports, &out.PendingReports
+		*out = new(PendingReportsConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(OutputMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GapDetection != nil {
+		in, out := &in.GapDetection, &out.GapDetection
+		*out = new(GapDetectionConfig)
+		**out = **in
+	}
+	if in.Deduplication != nil {
+		in, out := &in.Deduplication, &out.Deduplication
+		*out = new(DeduplicationConfig)
+		**out = **in
+	}
+	if in.Sampling != nil {
+		in, out := &in.Sampling, &out.Sampling
+		*out = new(SamplingConfig)
+		**out = **in
+	}
+	if in.FilteredEventTracking != nil {
+		in, out := &in.FilteredEventTracking, &out.FilteredEventTracking
+		*out = new(FilteredEventTrackingConfig)
+		**out = **in
+	}
+	if in.Integrity != nil {
+		in, out := &in.Integrity, &out.Integrity
+		*out = new(IntegrityConfig)
+		**out = **in
+	}
+	if in.BreakGlass != nil {
+		in, out := &in.BreakGlass, &out.BreakGlass
+		*out = new(BreakGlassConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PolicySink != nil {
+		in, out := &in.PolicySink, &out.PolicySink
+		*out = new(PolicySinkConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(SourceNotifications)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AudiciaSourceSpec.
@@ -363,11 +653,67 @@ func (in *AudiciaSourceStatus) DeepCopyInto(out *AudiciaSourceStatus) {
 		in, out := &in.LastTimestamp, &out.LastTimestamp
 		*out = (*in).DeepCopy()
 	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]FileCheckpointStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.CloudCheckpoint != nil {
 		in, out := &in.CloudCheckpoint, &out.CloudCheckpoint
 		*out = new(CloudCheckpointStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LastCheckpointTime != nil {
+		in, out := &in.LastCheckpointTime, &out.LastCheckpointTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFlush != nil {
+		in, out := &in.LastFlush, &out.LastFlush
+		*out = new(FlushStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gaps != nil {
+		in, out := &in.Gaps, &out.Gaps
+		*out = new(GapStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FilteredEvents != nil {
+		in, out := &in.FilteredEvents, &out.FilteredEvents
+		*out = new(FilteredEventsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EventTimestamps != nil {
+		in, out := &in.EventTimestamps, &out.EventTimestamps
+		*out = new(EventTimestampsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(LimitsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExcludedSubjects != nil {
+		in, out := &in.ExcludedSubjects, &out.ExcludedSubjects
+		*out = new(ExcludedSubjectsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PolicySink != nil {
+		in, out := &in.PolicySink, &out.PolicySink
+		*out = new(PolicySinkStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RecentErrors != nil {
+		in, out := &in.RecentErrors, &out.RecentErrors
+		*out = make([]PipelineError, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageEstimate)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -403,64 +749,151 @@ func (in *AzureEventHubConfig) DeepCopy() *AzureEventHubConfig {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CheckpointConfig) DeepCopyInto(out *CheckpointConfig) {
+func (in *BaselineRule) DeepCopyInto(out *BaselineRule) {
 	*out = *in
+	if in.APIGroups != nil {
+		in, out := &in.APIGroups, &out.APIGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NonResourceURLs != nil {
+		in, out := &in.NonResourceURLs, &out.NonResourceURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Verbs != nil {
+		in, out := &in.Verbs, &out.Verbs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubjectKinds != nil {
+		in, out := &in.SubjectKinds, &out.SubjectKinds
+		*out = make([]SubjectKind, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckpointConfig.
-func (in *CheckpointConfig) DeepCopy() *CheckpointConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaselineRule.
+func (in *BaselineRule) DeepCopy() *BaselineRule {
 	if in == nil {
 		return nil
 	}
-	out := new(CheckpointConfig)
+	out := new(BaselineRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CloudCheckpointStatus) DeepCopyInto(out *CloudCheckpointStatus) {
+func (in *BreakGlassConfig) DeepCopyInto(out *BreakGlassConfig) {
 	*out = *in
-	if in.PartitionOffsets != nil {
-		in, out := &in.PartitionOffsets, &out.PartitionOffsets
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Subjects != nil {
+		in, out := &in.Subjects, &out.Subjects
+		*out = make([]Subject, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudCheckpointStatus.
-func (in *CloudCheckpointStatus) DeepCopy() *CloudCheckpointStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BreakGlassConfig.
+func (in *BreakGlassConfig) DeepCopy() *BreakGlassConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(CloudCheckpointStatus)
+	out := new(BreakGlassConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CloudConfig) DeepCopyInto(out *CloudConfig) {
+func (in *CheckpointConfig) DeepCopyInto(out *CheckpointConfig) {
 	*out = *in
-	if in.Azure != nil {
-		in, out := &in.Azure, &out.Azure
-		*out = new(AzureEventHubConfig)
-		**out = **in
-	}
-	if in.AWS != nil {
-		in, out := &in.AWS, &out.AWS
-		*out = new(AWSCloudWatchConfig)
-		**out = **in
-	}
-	if in.GCP != nil {
-		in, out := &in.GCP, &out.GCP
-		*out = new(GCPPubSubConfig)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudConfig.
-func (in *CloudConfig) DeepCopy() *CloudConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckpointConfig.
+func (in *CheckpointConfig) DeepCopy() *CheckpointConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CheckpointConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheckpointRange) DeepCopyInto(out *CheckpointRange) {
+	*out = *in
+	in.From.DeepCopyInto(&out.From)
+	in.To.DeepCopyInto(&out.To)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckpointRange.
+func (in *CheckpointRange) DeepCopy() *CheckpointRange {
+	if in == nil {
+		return nil
+	}
+	out := new(CheckpointRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudCheckpointStatus) DeepCopyInto(out *CloudCheckpointStatus) {
+	*out = *in
+	if in.PartitionOffsets != nil {
+		in, out := &in.PartitionOffsets, &out.PartitionOffsets
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudCheckpointStatus.
+func (in *CloudCheckpointStatus) DeepCopy() *CloudCheckpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudCheckpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudConfig) DeepCopyInto(out *CloudConfig) {
+	*out = *in
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(AzureEventHubConfig)
+		**out = **in
+	}
+	if in.AWS != nil {
+		in, out := &in.AWS, &out.AWS
+		*out = new(AWSCloudWatchConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(AWSS3Config)
+		**out = **in
+	}
+	if in.GCP != nil {
+		in, out := &in.GCP, &out.GCP
+		*out = new(GCPPubSubConfig)
+		**out = **in
+	}
+	if in.Kafka != nil {
+		in, out := &in.Kafka, &out.Kafka
+		*out = new(KafkaConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudConfig.
+func (in *CloudConfig) DeepCopy() *CloudConfig {
 	if in == nil {
 		return nil
 	}
@@ -491,6 +924,13 @@ func (in *ComplianceReport) DeepCopyInto(out *ComplianceReport) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = make([]RemediationStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.LastEvaluatedTime.DeepCopyInto(&out.LastEvaluatedTime)
 }
 
@@ -539,6 +979,91 @@ func (in *ComplianceRule) DeepCopy() *ComplianceRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeduplicationConfig) DeepCopyInto(out *DeduplicationConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeduplicationConfig.
+func (in *DeduplicationConfig) DeepCopy() *DeduplicationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DeduplicationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventTimestampsStatus) DeepCopyInto(out *EventTimestampsStatus) {
+	*out = *in
+	if in.LastMissingTime != nil {
+		in, out := &in.LastMissingTime, &out.LastMissingTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTimestampsStatus.
+func (in *EventTimestampsStatus) DeepCopy() *EventTimestampsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EventTimestampsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExcludedSubject) DeepCopyInto(out *ExcludedSubject) {
+	*out = *in
+	out.Subject = in.Subject
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExcludedSubject.
+func (in *ExcludedSubject) DeepCopy() *ExcludedSubject {
+	if in == nil {
+		return nil
+	}
+	out := new(ExcludedSubject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExcludedSubjectsStatus) DeepCopyInto(out *ExcludedSubjectsStatus) {
+	*out = *in
+	if in.Subjects != nil {
+		in, out := &in.Subjects, &out.Subjects
+		*out = make([]ExcludedSubject, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExcludedSubjectsStatus.
+func (in *ExcludedSubjectsStatus) DeepCopy() *ExcludedSubjectsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExcludedSubjectsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileCheckpointStatus) DeepCopyInto(out *FileCheckpointStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileCheckpointStatus.
+func (in *FileCheckpointStatus) DeepCopy() *FileCheckpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FileCheckpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FileLocation) DeepCopyInto(out *FileLocation) {
 	*out = *in
@@ -557,6 +1082,11 @@ func (in *FileLocation) DeepCopy() *FileLocation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Filter) DeepCopyInto(out *Filter) {
 	*out = *in
+	if in.ActiveDays != nil {
+		in, out := &in.ActiveDays, &out.ActiveDays
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Filter.
@@ -569,6 +1099,93 @@ func (in *Filter) DeepCopy() *Filter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilteredEventCount) DeepCopyInto(out *FilteredEventCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilteredEventCount.
+func (in *FilteredEventCount) DeepCopy() *FilteredEventCount {
+	if in == nil {
+		return nil
+	}
+	out := new(FilteredEventCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilteredEventTrackingConfig) DeepCopyInto(out *FilteredEventTrackingConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilteredEventTrackingConfig.
+func (in *FilteredEventTrackingConfig) DeepCopy() *FilteredEventTrackingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FilteredEventTrackingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilteredEventsStatus) DeepCopyInto(out *FilteredEventsStatus) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]FilteredEventCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]FilteredEventCount, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilteredEventsStatus.
+func (in *FilteredEventsStatus) DeepCopy() *FilteredEventsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FilteredEventsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlushStatus) DeepCopyInto(out *FlushStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlushStatus.
+func (in *FlushStatus) DeepCopy() *FlushStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FlushStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForwardConfig) DeepCopyInto(out *ForwardConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForwardConfig.
+func (in *ForwardConfig) DeepCopy() *ForwardConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ForwardConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GCPPubSubConfig) DeepCopyInto(out *GCPPubSubConfig) {
 	*out = *in
@@ -585,90 +1202,874 @@ func (in *GCPPubSubConfig) DeepCopy() *GCPPubSubConfig {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LimitsConfig) DeepCopyInto(out *LimitsConfig) {
+func (in *GapDetectionConfig) DeepCopyInto(out *GapDetectionConfig) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LimitsConfig.
-func (in *LimitsConfig) DeepCopy() *LimitsConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GapDetectionConfig.
+func (in *GapDetectionConfig) DeepCopy() *GapDetectionConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(LimitsConfig)
+	out := new(GapDetectionConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObservedRule) DeepCopyInto(out *ObservedRule) {
+func (in *GapStatus) DeepCopyInto(out *GapStatus) {
 	*out = *in
-	if in.APIGroups != nil {
-		in, out := &in.APIGroups, &out.APIGroups
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.LastEventTime != nil {
+		in, out := &in.LastEventTime, &out.LastEventTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Verbs != nil {
-		in, out := &in.Verbs, &out.Verbs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Recent != nil {
+		in, out := &in.Recent, &out.Recent
+		*out = make([]IngestionGap, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.NonResourceURLs != nil {
-		in, out := &in.NonResourceURLs, &out.NonResourceURLs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GapStatus.
+func (in *GapStatus) DeepCopy() *GapStatus {
+	if in == nil {
+		return nil
 	}
-	in.FirstSeen.DeepCopyInto(&out.FirstSeen)
-	in.LastSeen.DeepCopyInto(&out.LastSeen)
+	out := new(GapStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedRule.
-func (in *ObservedRule) DeepCopy() *ObservedRule {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratorInfo) DeepCopyInto(out *GeneratorInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratorInfo.
+func (in *GeneratorInfo) DeepCopy() *GeneratorInfo {
 	if in == nil {
 		return nil
 	}
-	out := new(ObservedRule)
+	out := new(GeneratorInfo)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PolicyStrategy) DeepCopyInto(out *PolicyStrategy) {
+func (in *GitSinkConfig) DeepCopyInto(out *GitSinkConfig) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyStrategy.
-func (in *PolicyStrategy) DeepCopy() *PolicyStrategy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitSinkConfig.
+func (in *GitSinkConfig) DeepCopy() *GitSinkConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(PolicyStrategy)
+	out := new(GitSinkConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Subject) DeepCopyInto(out *Subject) {
+func (in *GrpcConfig) DeepCopyInto(out *GrpcConfig) {
 	*out = *in
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(WebhookAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subject.
-func (in *Subject) DeepCopy() *Subject {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcConfig.
+func (in *GrpcConfig) DeepCopy() *GrpcConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(Subject)
+	out := new(GrpcConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *WebhookConfig) DeepCopyInto(out *WebhookConfig) {
+func (in *IgnoredVerb) DeepCopyInto(out *IgnoredVerb) {
 	*out = *in
+	if in.APIGroups != nil {
+		in, out := &in.APIGroups, &out.APIGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NonResourceURLs != nil {
+		in, out := &in.NonResourceURLs, &out.NonResourceURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSeen != nil {
+		in, out := &in.LastSeen, &out.LastSeen
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IgnoredVerb.
+func (in *IgnoredVerb) DeepCopy() *IgnoredVerb {
+	if in == nil {
+		return nil
+	}
+	out := new(IgnoredVerb)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngestionGap) DeepCopyInto(out *IngestionGap) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngestionGap.
+func (in *IngestionGap) DeepCopy() *IngestionGap {
+	if in == nil {
+		return nil
+	}
+	out := new(IngestionGap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngestionPosition) DeepCopyInto(out *IngestionPosition) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]FileCheckpointStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.PartitionOffsets != nil {
+		in, out := &in.PartitionOffsets, &out.PartitionOffsets
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngestionPosition.
+func (in *IngestionPosition) DeepCopy() *IngestionPosition {
+	if in == nil {
+		return nil
+	}
+	out := new(IngestionPosition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrityConfig) DeepCopyInto(out *IntegrityConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrityConfig.
+func (in *IntegrityConfig) DeepCopy() *IntegrityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrityEntry) DeepCopyInto(out *IntegrityEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrityEntry.
+func (in *IntegrityEntry) DeepCopy() *IntegrityEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrityEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrityStatus) DeepCopyInto(out *IntegrityStatus) {
+	*out = *in
+	if in.Entries != nil {
+		in, out := &in.Entries, &out.Entries
+		*out = make([]IntegrityEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrityStatus.
+func (in *IntegrityStatus) DeepCopy() *IntegrityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaConfig) DeepCopyInto(out *KafkaConfig) {
+	*out = *in
+	if in.Brokers != nil {
+		in, out := &in.Brokers, &out.Brokers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(KafkaTLSConfig)
+		**out = **in
+	}
+	if in.SASL != nil {
+		in, out := &in.SASL, &out.SASL
+		*out = new(KafkaSASLConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaConfig.
+func (in *KafkaConfig) DeepCopy() *KafkaConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaSASLConfig) DeepCopyInto(out *KafkaSASLConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSASLConfig.
+func (in *KafkaSASLConfig) DeepCopy() *KafkaSASLConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaSASLConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaTLSConfig) DeepCopyInto(out *KafkaTLSConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaTLSConfig.
+func (in *KafkaTLSConfig) DeepCopy() *KafkaTLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaTLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LimitsConfig) DeepCopyInto(out *LimitsConfig) {
+	*out = *in
+	if in.RetentionPolicies != nil {
+		in, out := &in.RetentionPolicies, &out.RetentionPolicies
+		*out = make([]RetentionPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LimitsConfig.
+func (in *LimitsConfig) DeepCopy() *LimitsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LimitsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LimitsStatus) DeepCopyInto(out *LimitsStatus) {
+	*out = *in
+	in.Applied.DeepCopyInto(&out.Applied)
+	if in.Pending != nil {
+		in, out := &in.Pending, &out.Pending
+		*out = new(LimitsConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingSince != nil {
+		in, out := &in.PendingSince, &out.PendingSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LimitsStatus.
+func (in *LimitsStatus) DeepCopy() *LimitsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LimitsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalConfig) DeepCopyInto(out *LocalConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalConfig.
+func (in *LocalConfig) DeepCopy() *LocalConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservedRule) DeepCopyInto(out *ObservedRule) {
+	*out = *in
+	if in.APIGroups != nil {
+		in, out := &in.APIGroups, &out.APIGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Verbs != nil {
+		in, out := &in.Verbs, &out.Verbs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NonResourceURLs != nil {
+		in, out := &in.NonResourceURLs, &out.NonResourceURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceNames != nil {
+		in, out := &in.ResourceNames, &out.ResourceNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.FirstSeen.DeepCopyInto(&out.FirstSeen)
+	in.LastSeen.DeepCopyInto(&out.LastSeen)
+	if in.Provenance != nil {
+		in, out := &in.Provenance, &out.Provenance
+		*out = new(RuleProvenance)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedRule.
+func (in *ObservedRule) DeepCopy() *ObservedRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservedRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputMetadata) DeepCopyInto(out *OutputMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputMetadata.
+func (in *OutputMetadata) DeepCopy() *OutputMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingReportsConfig) DeepCopyInto(out *PendingReportsConfig) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingReportsConfig.
+func (in *PendingReportsConfig) DeepCopy() *PendingReportsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingReportsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineError) DeepCopyInto(out *PipelineError) {
+	*out = *in
+	in.FirstSeen.DeepCopyInto(&out.FirstSeen)
+	in.LastSeen.DeepCopyInto(&out.LastSeen)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineError.
+func (in *PipelineError) DeepCopy() *PipelineError {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlanApplication) DeepCopyInto(out *PlanApplication) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanApplication.
+func (in *PlanApplication) DeepCopy() *PlanApplication {
+	if in == nil {
+		return nil
+	}
+	out := new(PlanApplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlanObject) DeepCopyInto(out *PlanObject) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanObject.
+func (in *PlanObject) DeepCopy() *PlanObject {
+	if in == nil {
+		return nil
+	}
+	out := new(PlanObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyNaming) DeepCopyInto(out *PolicyNaming) {
+	*out = *in
+	if in.Prefix != nil {
+		in, out := &in.Prefix, &out.Prefix
+		*out = new(string)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyNaming.
+func (in *PolicyNaming) DeepCopy() *PolicyNaming {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyNaming)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicySinkConfig) DeepCopyInto(out *PolicySinkConfig) {
+	*out = *in
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitSinkConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicySinkConfig.
+func (in *PolicySinkConfig) DeepCopy() *PolicySinkConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicySinkConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicySinkStatus) DeepCopyInto(out *PolicySinkStatus) {
+	*out = *in
+	in.LastSyncTime.DeepCopyInto(&out.LastSyncTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicySinkStatus.
+func (in *PolicySinkStatus) DeepCopy() *PolicySinkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicySinkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyStrategy) DeepCopyInto(out *PolicyStrategy) {
+	*out = *in
+	if in.BaselineRules != nil {
+		in, out := &in.BaselineRules, &out.BaselineRules
+		*out = make([]BaselineRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RoleConsolidation != nil {
+		in, out := &in.RoleConsolidation, &out.RoleConsolidation
+		*out = new(RoleConsolidation)
+		**out = **in
+	}
+	if in.Naming != nil {
+		in, out := &in.Naming, &out.Naming
+		*out = new(PolicyNaming)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyStrategy.
+func (in *PolicyStrategy) DeepCopy() *PolicyStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationStep) DeepCopyInto(out *RemediationStep) {
+	*out = *in
+	out.Target = in.Target
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]ComplianceRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationStep.
+func (in *RemediationStep) DeepCopy() *RemediationStep {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationTarget) DeepCopyInto(out *RemediationTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationTarget.
+func (in *RemediationTarget) DeepCopy() *RemediationTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportConfiguration) DeepCopyInto(out *ReportConfiguration) {
+	*out = *in
+	if in.ChangedTime != nil {
+		in, out := &in.ChangedTime, &out.ChangedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportConfiguration.
+func (in *ReportConfiguration) DeepCopy() *ReportConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportSource) DeepCopyInto(out *ReportSource) {
+	*out = *in
+	in.LastFlushTime.DeepCopyInto(&out.LastFlushTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportSource.
+func (in *ReportSource) DeepCopy() *ReportSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportWebhook) DeepCopyInto(out *ReportWebhook) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportWebhook.
+func (in *ReportWebhook) DeepCopy() *ReportWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionPolicy) DeepCopyInto(out *RetentionPolicy) {
+	*out = *in
+	if in.APIGroups != nil {
+		in, out := &in.APIGroups, &out.APIGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionPolicy.
+func (in *RetentionPolicy) DeepCopy() *RetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleConsolidation) DeepCopyInto(out *RoleConsolidation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleConsolidation.
+func (in *RoleConsolidation) DeepCopy() *RoleConsolidation {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleConsolidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleProvenance) DeepCopyInto(out *RuleProvenance) {
+	*out = *in
+	if in.FirstSeen != nil {
+		in, out := &in.FirstSeen, &out.FirstSeen
+		*out = new(CheckpointRange)
+		(*in).DeepCopyInto(*out)
+	}
+	in.LastSeen.DeepCopyInto(&out.LastSeen)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleProvenance.
+func (in *RuleProvenance) DeepCopy() *RuleProvenance {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleProvenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SamplingConfig) DeepCopyInto(out *SamplingConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SamplingConfig.
+func (in *SamplingConfig) DeepCopy() *SamplingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SamplingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceNotifications) DeepCopyInto(out *SourceNotifications) {
+	*out = *in
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]ReportWebhook, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceNotifications.
+func (in *SourceNotifications) DeepCopy() *SourceNotifications {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceNotifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageEstimate) DeepCopyInto(out *StorageEstimate) {
+	*out = *in
+	in.EstimatedTime.DeepCopyInto(&out.EstimatedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageEstimate.
+func (in *StorageEstimate) DeepCopy() *StorageEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Subject) DeepCopyInto(out *Subject) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subject.
+func (in *Subject) DeepCopy() *Subject {
+	if in == nil {
+		return nil
+	}
+	out := new(Subject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubjectAlias) DeepCopyInto(out *SubjectAlias) {
+	*out = *in
+	out.Subject = in.Subject
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubjectAlias.
+func (in *SubjectAlias) DeepCopy() *SubjectAlias {
+	if in == nil {
+		return nil
+	}
+	out := new(SubjectAlias)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubjectTrackingConfig) DeepCopyInto(out *SubjectTrackingConfig) {
+	*out = *in
+	if in.GroupPatterns != nil {
+		in, out := &in.GroupPatterns, &out.GroupPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubjectTrackingConfig.
+func (in *SubjectTrackingConfig) DeepCopy() *SubjectTrackingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SubjectTrackingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookAuthentication) DeepCopyInto(out *WebhookAuthentication) {
+	*out = *in
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookAuthentication.
+func (in *WebhookAuthentication) DeepCopy() *WebhookAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookConfig) DeepCopyInto(out *WebhookConfig) {
+	*out = *in
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(WebhookAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookConfig.
@@ -0,0 +1,355 @@
+// Package autodiscovery inspects the environment the operator runs in for
+// audit log sources: audit log files on a control plane node, and the cloud
+// metadata services of EKS, GKE and AKS nodes. It suggests an AudiciaSource
+// spec with notes on what was found, so a first source does not have to be
+// written by guesswork.
+package autodiscovery
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// Default cloud metadata endpoints.
+const (
+	DefaultAWSMetadataURL   = "http://169.254.169.254"
+	DefaultGCPMetadataURL   = "http://metadata.google.internal"
+	DefaultAzureMetadataURL = "http://169.254.169.254"
+)
+
+// metadataTimeout bounds each cloud metadata request. Off the cloud, the
+// link-local address does not answer and the request times out.
+const metadataTimeout = 2 * time.Second
+
+// auditLogCandidate is an audit log location of a self-managed distribution.
+type auditLogCandidate struct {
+	platform string
+	path     string
+}
+
+// auditLogCandidates are checked in order; the first readable one is
+// suggested.
+var auditLogCandidates = []auditLogCandidate{
+	{"kubeadm", "/var/log/kubernetes/audit/audit.log"},
+	{"openshift", "/var/log/kube-apiserver/audit.log"},
+	{"openshift", "/var/log/openshift-apiserver/audit.log"},
+}
+
+// auditLogDir holds the kubeadm audit logs. If audit.log is missing but
+// other logs are there, a glob over them is suggested instead.
+const auditLogDir = "/var/log/kubernetes/audit"
+
+// Finding is the outcome of one check.
+type Finding struct {
+	// Check names what was inspected, e.g. a path or a metadata service.
+	Check string
+
+	// Found is true when the check found an audit source or platform.
+	Found bool
+
+	// Detail explains the outcome.
+	Detail string
+}
+
+// Result is the outcome of a discovery.
+type Result struct {
+	// Platform is the detected platform: kubeadm, openshift, eks, gke, aks
+	// or unknown.
+	Platform string
+
+	// Findings lists every check in the order it ran.
+	Findings []Finding
+
+	// Source is the suggested AudiciaSource spec, nil when no source was
+	// found.
+	Source *audiciav1alpha1.AudiciaSourceSpec
+
+	// Notes explain the suggestion and what must be completed by hand.
+	Notes []string
+}
+
+// Discoverer inspects the environment. The zero value checks the real
+// filesystem and the default metadata endpoints.
+type Discoverer struct {
+	// Root is prepended to every path checked; tests point it at a
+	// temporary directory.
+	Root string
+
+	// HTTPClient queries the metadata services; nil uses a client with a
+	// short timeout.
+	HTTPClient *http.Client
+
+	// AWSMetadataURL, GCPMetadataURL and AzureMetadataURL override the
+	// default metadata endpoints.
+	AWSMetadataURL, GCPMetadataURL, AzureMetadataURL string
+}
+
+// Discover runs every check: audit log files first, since a readable file
+// needs no cloud setup, then the cloud metadata services.
+func (d *Discoverer) Discover(ctx context.Context) Result {
+	res := Result{Platform: "unknown"}
+	if d.discoverFiles(&res) {
+		return res
+	}
+	for _, discover := range []func(context.Context, *Result) bool{d.discoverEKS, d.discoverGKE, d.discoverAKS} {
+		if discover(ctx, &res) {
+			return res
+		}
+	}
+	res.Notes = append(res.Notes,
+		"No audit log file and no managed control plane were found.",
+		"If the operator runs off the control plane nodes, consider a Webhook source: the kube-apiserver sends events to the operator over HTTPS (see docs/guides/webhook-setup.md).",
+		"If the audit log exists but was not found, check that the Helm chart mounts it (auditLog.enabled and auditLog.hostPath) and that auditing is enabled (--audit-log-path on the kube-apiserver).")
+	return res
+}
+
+// discoverFiles checks the audit log candidates and reports whether a
+// readable one was found.
+func (d *Discoverer) discoverFiles(res *Result) bool {
+	var unreadable []string
+	for _, c := range auditLogCandidates {
+		err := readable(filepath.Join(d.Root, c.path))
+		switch {
+		case err == nil:
+			res.Findings = append(res.Findings, Finding{Check: c.path, Found: true, Detail: "readable audit log (" + c.platform + ")"})
+			res.Platform = c.platform
+			res.Source = fileSource(c.path)
+			res.Notes = append(res.Notes, fmt.Sprintf("%s is readable from the operator pod, so a K8sAuditLog source can tail it directly.", c.path))
+			return true
+		case errors.Is(err, fs.ErrNotExist):
+			res.Findings = append(res.Findings, Finding{Check: c.path, Detail: "not found"})
+		default:
+			res.Findings = append(res.Findings, Finding{Check: c.path, Detail: err.Error()})
+			unreadable = append(unreadable, c.path)
+		}
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(d.Root, auditLogDir, "*.log"))
+	if len(matches) > 0 {
+		pattern := auditLogDir + "/*.log"
+		res.Findings = append(res.Findings, Finding{Check: pattern, Found: true, Detail: fmt.Sprintf("%d audit log files", len(matches))})
+		res.Platform = "kubeadm"
+		res.Source = fileSource(pattern)
+		res.Notes = append(res.Notes, fmt.Sprintf("%s holds audit logs under other names; the suggested source reads all of them, one per kube-apiserver.", auditLogDir))
+		return true
+	}
+
+	if len(unreadable) > 0 {
+		res.Notes = append(res.Notes, fmt.Sprintf(
+			"%s exists but the operator cannot read it. File mode needs to run as root (runAsUser: 0) on a control plane node.",
+			strings.Join(unreadable, ", ")))
+	}
+	return false
+}
+
+// readable checks that path can be opened for reading.
+func readable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// fileSource is a K8sAuditLog source reading path, with the filters of the
+// file mode example.
+func fileSource(path string) *audiciav1alpha1.AudiciaSourceSpec {
+	return &audiciav1alpha1.AudiciaSourceSpec{
+		SourceType: audiciav1alpha1.SourceTypeK8sAuditLog,
+		Location:   &audiciav1alpha1.FileLocation{Path: path},
+		Filters: []audiciav1alpha1.Filter{
+			{Action: audiciav1alpha1.FilterActionDeny, UserPattern: "^system:node:.*"},
+			{Action: audiciav1alpha1.FilterActionDeny, UserPattern: "^system:kube-.*"},
+			{Action: audiciav1alpha1.FilterActionDeny, UserPattern: "^system:apiserver$"},
+		},
+	}
+}
+
+// discoverEKS queries the EC2 instance metadata service (IMDSv2) for the
+// node's account and region.
+func (d *Discoverer) discoverEKS(ctx context.Context, res *Result) bool {
+	base := cmp.Or(d.AWSMetadataURL, DefaultAWSMetadataURL)
+	token, err := d.fetch(ctx, http.MethodPut, base+"/latest/api/token",
+		map[string]string{"X-aws-ec2-metadata-token-ttl-seconds": "60"})
+	if err != nil {
+		res.Findings = append(res.Findings, Finding{Check: "AWS instance metadata", Detail: err.Error()})
+		return false
+	}
+	body, err := d.fetch(ctx, http.MethodGet, base+"/latest/dynamic/instance-identity/document",
+		map[string]string{"X-aws-ec2-metadata-token": string(token)})
+	if err != nil {
+		res.Findings = append(res.Findings, Finding{Check: "AWS instance metadata", Detail: err.Error()})
+		return false
+	}
+	var doc struct {
+		AccountID string `json:"accountId"`
+		Region    string `json:"region"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		res.Findings = append(res.Findings, Finding{Check: "AWS instance metadata", Detail: fmt.Sprintf("parsing identity document: %v", err)})
+		return false
+	}
+	res.Findings = append(res.Findings, Finding{Check: "AWS instance metadata", Found: true,
+		Detail: fmt.Sprintf("EC2 instance in account %s, region %s", doc.AccountID, doc.Region)})
+	res.Platform = "eks"
+	res.Source = &audiciav1alpha1.AudiciaSourceSpec{
+		SourceType: audiciav1alpha1.SourceTypeCloudAuditLog,
+		Cloud: &audiciav1alpha1.CloudConfig{
+			Provider:        audiciav1alpha1.CloudProviderAWSCloudWatch,
+			ClusterIdentity: fmt.Sprintf("arn:aws:eks:%s:%s:cluster/<CLUSTER_NAME>", doc.Region, doc.AccountID),
+			AWS: &audiciav1alpha1.AWSCloudWatchConfig{
+				Region:       doc.Region,
+				LogGroupName: "/aws/eks/<CLUSTER_NAME>/cluster",
+			},
+		},
+	}
+	res.Notes = append(res.Notes,
+		"The node runs on EC2, so the control plane is likely EKS; its audit logs are only available from CloudWatch Logs.",
+		"Replace <CLUSTER_NAME> with the EKS cluster name, enable the audit control plane log type, and grant the operator's IRSA role logs:FilterLogEvents (see docs/guides/eks-setup.md).")
+	return true
+}
+
+// discoverGKE queries the GCE metadata server for the cluster's name,
+// location and project.
+func (d *Discoverer) discoverGKE(ctx context.Context, res *Result) bool {
+	base := cmp.Or(d.GCPMetadataURL, DefaultGCPMetadataURL) + "/computeMetadata/v1"
+	header := map[string]string{"Metadata-Flavor": "Google"}
+	values := make(map[string]string, 3)
+	for key, path := range map[string]string{
+		"project":  "/project/project-id",
+		"cluster":  "/instance/attributes/cluster-name",
+		"location": "/instance/attributes/cluster-location",
+	} {
+		body, err := d.fetch(ctx, http.MethodGet, base+path, header)
+		if err != nil {
+			res.Findings = append(res.Findings, Finding{Check: "GCP metadata server", Detail: err.Error()})
+			return false
+		}
+		values[key] = strings.TrimSpace(string(body))
+	}
+	res.Findings = append(res.Findings, Finding{Check: "GCP metadata server", Found: true,
+		Detail: fmt.Sprintf("GKE cluster %s in %s, project %s", values["cluster"], values["location"], values["project"])})
+	res.Platform = "gke"
+	res.Source = &audiciav1alpha1.AudiciaSourceSpec{
+		SourceType: audiciav1alpha1.SourceTypeCloudAuditLog,
+		Cloud: &audiciav1alpha1.CloudConfig{
+			Provider:        audiciav1alpha1.CloudProviderGCPPubSub,
+			ClusterIdentity: fmt.Sprintf("projects/%s/locations/%s/clusters/%s", values["project"], values["location"], values["cluster"]),
+			GCP: &audiciav1alpha1.GCPPubSubConfig{
+				ProjectID:      values["project"],
+				SubscriptionID: "<SUBSCRIPTION_ID>",
+			},
+		},
+	}
+	res.Notes = append(res.Notes,
+		"The node runs on GKE; its audit logs are only available from Cloud Logging.",
+		"Route them to Pub/Sub with a Log Router sink, replace <SUBSCRIPTION_ID> with the sink topic's subscription, and grant the operator's Workload Identity roles/pubsub.subscriber (see docs/guides/gke-setup.md).")
+	return true
+}
+
+// discoverAKS queries the Azure Instance Metadata Service for the node's
+// subscription and resource group. AKS puts nodes in the resource group
+// MC_<group>_<cluster>_<location>, from which the cluster is derived.
+func (d *Discoverer) discoverAKS(ctx context.Context, res *Result) bool {
+	base := cmp.Or(d.AzureMetadataURL, DefaultAzureMetadataURL)
+	body, err := d.fetch(ctx, http.MethodGet, base+"/metadata/instance/compute?api-version=2021-02-01&format=json",
+		map[string]string{"Metadata": "true"})
+	if err != nil {
+		res.Findings = append(res.Findings, Finding{Check: "Azure instance metadata", Detail: err.Error()})
+		return false
+	}
+	var compute struct {
+		SubscriptionID    string `json:"subscriptionId"`
+		ResourceGroupName string `json:"resourceGroupName"`
+		Location          string `json:"location"`
+	}
+	if err := json.Unmarshal(body, &compute); err != nil {
+		res.Findings = append(res.Findings, Finding{Check: "Azure instance metadata", Detail: fmt.Sprintf("parsing compute metadata: %v", err)})
+		return false
+	}
+	group, cluster := "<RESOURCE_GROUP>", "<CLUSTER_NAME>"
+	if g, c, ok := parseNodeResourceGroup(compute.ResourceGroupName, compute.Location); ok {
+		group, cluster = g, c
+	}
+	res.Findings = append(res.Findings, Finding{Check: "Azure instance metadata", Found: true,
+		Detail: fmt.Sprintf("Azure VM in subscription %s, resource group %s", compute.SubscriptionID, compute.ResourceGroupName)})
+	res.Platform = "aks"
+	res.Source = &audiciav1alpha1.AudiciaSourceSpec{
+		SourceType: audiciav1alpha1.SourceTypeCloudAuditLog,
+		Cloud: &audiciav1alpha1.CloudConfig{
+			Provider: audiciav1alpha1.CloudProviderAzureEventHub,
+			ClusterIdentity: fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ContainerService/managedClusters/%s",
+				compute.SubscriptionID, group, cluster),
+			Azure: &audiciav1alpha1.AzureEventHubConfig{
+				EventHubNamespace: "<NAMESPACE>.servicebus.windows.net",
+				EventHubName:      "<EVENT_HUB>",
+			},
+		},
+	}
+	res.Notes = append(res.Notes,
+		"The node runs on Azure, so the control plane is likely AKS; its audit logs are only available through diagnostic settings.",
+		"Stream the kube-audit category to an Event Hub, fill in its namespace and name, and grant the operator's Workload Identity the Azure Event Hubs Data Receiver role (see docs/guides/aks-setup.md).")
+	return true
+}
+
+// parseNodeResourceGroup splits an AKS node resource group,
+// MC_<group>_<cluster>_<location>, into the cluster's resource group and
+// name. Groups and clusters may contain underscores, so the split is only
+// made when it is unambiguous.
+func parseNodeResourceGroup(nodeGroup, location string) (group, cluster string, ok bool) {
+	rest, found := strings.CutPrefix(nodeGroup, "MC_")
+	if !found {
+		return "", "", false
+	}
+	rest, found = strings.CutSuffix(rest, "_"+location)
+	if !found {
+		return "", "", false
+	}
+	parts := strings.Split(rest, "_")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// fetch sends a metadata request and returns the body of a 200 response.
+func (d *Discoverer) fetch(ctx context.Context, method, url string, header map[string]string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	c := d.HTTPClient
+	if c == nil {
+		c = &http.Client{Timeout: metadataTimeout}
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("not reachable: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: HTTP %d", method, req.URL.Path, resp.StatusCode)
+	}
+	return body, nil
+}
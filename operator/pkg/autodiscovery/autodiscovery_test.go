@@ -0,0 +1,197 @@
+package autodiscovery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// metadataServer serves the given paths and 404 for everything else.
+func metadataServer(t *testing.T, routes map[string]string) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.Method+" "+r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// offCloud returns a Discoverer with root and no metadata service.
+func offCloud(t *testing.T, root string) *Discoverer {
+	url := metadataServer(t, nil)
+	return &Discoverer{Root: root, AWSMetadataURL: url, GCPMetadataURL: url, AzureMetadataURL: url}
+}
+
+func writeFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscover_AuditLogFile(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "/var/log/kube-apiserver/audit.log"))
+
+	res := offCloud(t, root).Discover(context.Background())
+	if res.Platform != "openshift" {
+		t.Errorf("Platform = %q, want openshift", res.Platform)
+	}
+	if res.Source == nil || res.Source.Location == nil || res.Source.Location.Path != "/var/log/kube-apiserver/audit.log" {
+		t.Fatalf("Source = %+v, want the OpenShift audit log", res.Source)
+	}
+	if res.Findings[0].Found || res.Findings[0].Detail != "not found" {
+		t.Errorf("kubeadm finding = %+v, want not found", res.Findings[0])
+	}
+}
+
+func TestDiscover_AuditLogGlob(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "/var/log/kubernetes/audit/audit-1.log"))
+	writeFile(t, filepath.Join(root, "/var/log/kubernetes/audit/audit-2.log"))
+
+	res := offCloud(t, root).Discover(context.Background())
+	if res.Platform != "kubeadm" || res.Source == nil || res.Source.Location.Path != "/var/log/kubernetes/audit/*.log" {
+		t.Errorf("Platform = %q, Source = %+v, want a kubeadm glob", res.Platform, res.Source)
+	}
+}
+
+func TestDiscover_Nothing(t *testing.T) {
+	res := offCloud(t, t.TempDir()).Discover(context.Background())
+	if res.Platform != "unknown" || res.Source != nil {
+		t.Errorf("Platform = %q, Source = %+v, want nothing", res.Platform, res.Source)
+	}
+	if len(res.Findings) != len(auditLogCandidates)+3 {
+		t.Errorf("got %d findings, want one per path and metadata service", len(res.Findings))
+	}
+	if !strings.Contains(strings.Join(res.Notes, " "), "Webhook") {
+		t.Errorf("Notes = %q, want a Webhook suggestion", res.Notes)
+	}
+}
+
+func TestDiscover_EKS(t *testing.T) {
+	d := offCloud(t, t.TempDir())
+	d.AWSMetadataURL = metadataServer(t, map[string]string{
+		"PUT /latest/api/token":                          "token",
+		"GET /latest/dynamic/instance-identity/document": `{"accountId":"123456789012","region":"eu-west-1"}`,
+	})
+
+	res := d.Discover(context.Background())
+	if res.Platform != "eks" || res.Source == nil || res.Source.Cloud == nil {
+		t.Fatalf("Platform = %q, Source = %+v, want an EKS source", res.Platform, res.Source)
+	}
+	if got, want := res.Source.Cloud.ClusterIdentity, "arn:aws:eks:eu-west-1:123456789012:cluster/<CLUSTER_NAME>"; got != want {
+		t.Errorf("ClusterIdentity = %q, want %q", got, want)
+	}
+	if res.Source.Cloud.AWS.Region != "eu-west-1" {
+		t.Errorf("Region = %q, want eu-west-1", res.Source.Cloud.AWS.Region)
+	}
+}
+
+func TestDiscover_GKE(t *testing.T) {
+	d := offCloud(t, t.TempDir())
+	d.GCPMetadataURL = metadataServer(t, map[string]string{
+		"GET /computeMetadata/v1/project/project-id":                   "my-project",
+		"GET /computeMetadata/v1/instance/attributes/cluster-name":     "prod",
+		"GET /computeMetadata/v1/instance/attributes/cluster-location": "europe-west1",
+	})
+
+	res := d.Discover(context.Background())
+	if res.Platform != "gke" || res.Source == nil || res.Source.Cloud == nil {
+		t.Fatalf("Platform = %q, Source = %+v, want a GKE source", res.Platform, res.Source)
+	}
+	if got, want := res.Source.Cloud.ClusterIdentity, "projects/my-project/locations/europe-west1/clusters/prod"; got != want {
+		t.Errorf("ClusterIdentity = %q, want %q", got, want)
+	}
+}
+
+func TestDiscover_AKS(t *testing.T) {
+	d := offCloud(t, t.TempDir())
+	d.AzureMetadataURL = metadataServer(t, map[string]string{
+		"GET /metadata/instance/compute": `{"subscriptionId":"sub","resourceGroupName":"MC_rg_prod_westeurope","location":"westeurope"}`,
+	})
+
+	res := d.Discover(context.Background())
+	if res.Platform != "aks" || res.Source == nil || res.Source.Cloud == nil {
+		t.Fatalf("Platform = %q, Source = %+v, want an AKS source", res.Platform, res.Source)
+	}
+	want := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/prod"
+	if got := res.Source.Cloud.ClusterIdentity; got != want {
+		t.Errorf("ClusterIdentity = %q, want %q", got, want)
+	}
+}
+
+func TestParseNodeResourceGroup(t *testing.T) {
+	tests := []struct {
+		nodeGroup, group, cluster string
+		ok                        bool
+	}{
+		{"MC_rg_prod_westeurope", "rg", "prod", true},
+		{"MC_my_rg_prod_westeurope", "", "", false}, // ambiguous
+		{"custom-node-rg", "", "", false},
+		{"MC_rg_prod_eastus", "", "", false}, // other location
+	}
+	for _, tt := range tests {
+		group, cluster, ok := parseNodeResourceGroup(tt.nodeGroup, "westeurope")
+		if group != tt.group || cluster != tt.cluster || ok != tt.ok {
+			t.Errorf("parseNodeResourceGroup(%q) = %q, %q, %v, want %q, %q, %v",
+				tt.nodeGroup, group, cluster, ok, tt.group, tt.cluster, tt.ok)
+		}
+	}
+}
+
+func TestPublisher(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	p := &Publisher{Client: c, Namespace: "audicia-system", Name: "audicia-autodiscovery"}
+
+	res := Result{
+		Platform: "kubeadm",
+		Findings: []Finding{{Check: "/var/log/kubernetes/audit/audit.log", Found: true, Detail: "readable audit log (kubeadm)"}},
+		Source:   fileSource("/var/log/kubernetes/audit/audit.log"),
+	}
+	if err := p.Publish(context.Background(), res); err != nil {
+		t.Fatal(err)
+	}
+
+	var cm corev1.ConfigMap
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "audicia-system", Name: "audicia-autodiscovery"}, &cm); err != nil {
+		t.Fatal(err)
+	}
+	if cm.Data[PlatformKey] != "kubeadm" {
+		t.Errorf("platform = %q, want kubeadm", cm.Data[PlatformKey])
+	}
+	if !strings.Contains(cm.Data[NotesKey], "- [x] /var/log/kubernetes/audit/audit.log") {
+		t.Errorf("notes = %q, want the finding", cm.Data[NotesKey])
+	}
+	var source audiciav1alpha1.AudiciaSource
+	if err := yaml.UnmarshalStrict([]byte(cm.Data[SourceKey]), &source); err != nil {
+		t.Fatalf("suggested source does not parse: %v\n%s", err, cm.Data[SourceKey])
+	}
+	if source.Kind != "AudiciaSource" || source.Namespace != "audicia-system" ||
+		source.Spec.Location == nil || source.Spec.Location.Path != "/var/log/kubernetes/audit/audit.log" {
+		t.Errorf("suggested source = %+v", source)
+	}
+}
@@ -0,0 +1,113 @@
+package autodiscovery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+var log = ctrl.Log.WithName("autodiscovery")
+
+// fieldOwner is the server-side apply field manager of the ConfigMap.
+const fieldOwner = "audicia-operator"
+
+// ConfigMap keys written by a Publisher.
+const (
+	PlatformKey = "platform"
+	SourceKey   = "audiciasource.yaml"
+	NotesKey    = "NOTES.md"
+)
+
+// Publisher is a manager runnable that runs a discovery once the operator
+// starts and writes the result to a ConfigMap: the detected platform, the
+// suggested AudiciaSource as a manifest, and notes on every check.
+type Publisher struct {
+	client.Client
+
+	// Namespace and Name locate the ConfigMap.
+	Namespace, Name string
+
+	Discoverer *Discoverer
+}
+
+// Start implements manager.Runnable. A failed write is logged, not
+// returned: discovery only assists, so it must not stop the operator.
+func (p *Publisher) Start(ctx context.Context) error {
+	d := p.Discoverer
+	if d == nil {
+		d = &Discoverer{}
+	}
+	res := d.Discover(ctx)
+	if err := p.Publish(ctx, res); err != nil {
+		log.Error(err, "unable to write autodiscovery ConfigMap", "namespace", p.Namespace, "name", p.Name)
+		return nil
+	}
+	log.Info("wrote autodiscovery ConfigMap", "namespace", p.Namespace, "name", p.Name, "platform", res.Platform)
+	return nil
+}
+
+// Publish server-side applies the ConfigMap for res. Applying rather than
+// reading first keeps the manager from caching every ConfigMap.
+func (p *Publisher) Publish(ctx context.Context, res Result) error {
+	data := map[string]string{
+		PlatformKey: res.Platform,
+		NotesKey:    notes(res),
+	}
+	if res.Source != nil {
+		manifest, err := sourceManifest(p.Namespace, res.Source)
+		if err != nil {
+			return err
+		}
+		data[SourceKey] = manifest
+	}
+	cm := corev1ac.ConfigMap(p.Name, p.Namespace).
+		WithLabels(map[string]string{"app.kubernetes.io/managed-by": "audicia"}).
+		WithData(data)
+	return p.Apply(ctx, cm, client.FieldOwner(fieldOwner), client.ForceOwnership)
+}
+
+// sourceManifest renders spec as an AudiciaSource manifest that can be
+// applied once its placeholders are filled in.
+func sourceManifest(namespace string, spec *audiciav1alpha1.AudiciaSourceSpec) (string, error) {
+	out, err := yaml.Marshal(map[string]any{
+		"apiVersion": audiciav1alpha1.SchemeGroupVersion.String(),
+		"kind":       "AudiciaSource",
+		"metadata":   map[string]string{"name": "audit", "namespace": namespace},
+		"spec":       spec,
+	})
+	if err != nil {
+		return "", fmt.Errorf("rendering AudiciaSource: %w", err)
+	}
+	return string(out), nil
+}
+
+// notes renders the findings and notes of res as Markdown.
+func notes(res Result) string {
+	var b strings.Builder
+	b.WriteString("# Audit source autodiscovery\n\n")
+	fmt.Fprintf(&b, "Platform: %s\n\n## Checks\n\n", res.Platform)
+	for _, f := range res.Findings {
+		mark := " "
+		if f.Found {
+			mark = "x"
+		}
+		fmt.Fprintf(&b, "- [%s] %s: %s\n", mark, f.Check, f.Detail)
+	}
+	if len(res.Notes) > 0 {
+		b.WriteString("\n## Notes\n\n")
+		for _, n := range res.Notes {
+			fmt.Fprintf(&b, "- %s\n", n)
+		}
+	}
+	if res.Source != nil {
+		fmt.Fprintf(&b, "\nThe suggested source is in %s. Review it, replace any <PLACEHOLDERS>, and apply it with kubectl.\n", SourceKey)
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+// Package bearer checks static bearer tokens read from mounted files, as
+// the webhook receiver, the rule stream and the metrics endpoints accept.
+package bearer
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Equal reports whether token equals the expected one. An empty token never
+// does. Digests are compared, so the comparison time leaks neither the
+// token nor its length.
+func Equal(token, expected string) bool {
+	if token == "" {
+		return false
+	}
+	got, want := sha256.Sum256([]byte(token)), sha256.Sum256([]byte(expected))
+	return subtle.ConstantTimeCompare(got[:], want[:]) == 1
+}
+
+// TokenFile holds the token in a file, such as a mounted Secret key. The
+// token is re-read only when the file's modification time or size changes,
+// so a rotated token takes effect without reading the file per request.
+type TokenFile struct {
+	Path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	token   string
+}
+
+// NewTokenFile returns the token file at path.
+func NewTokenFile(path string) *TokenFile {
+	return &TokenFile{Path: path}
+}
+
+// Token returns the token without surrounding whitespace. A missing or
+// empty file is an error.
+func (f *TokenFile) Token() (string, error) {
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.token == "" || !info.ModTime().Equal(f.modTime) || info.Size() != f.size {
+		data, err := os.ReadFile(f.Path)
+		if err != nil {
+			return "", err
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("token file %s is empty", f.Path)
+		}
+		f.token, f.modTime, f.size = token, info.ModTime(), info.Size()
+	}
+	return f.token, nil
+}
@@ -0,0 +1,59 @@
+package bearer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		token, expected string
+		want            bool
+	}{
+		{"s3cret", "s3cret", true},
+		{"s3cret", "s3cre", false},
+		{"other", "s3cret", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got := Equal(tt.token, tt.expected); got != tt.want {
+			t.Errorf("Equal(%q, %q) = %v, want %v", tt.token, tt.expected, got, tt.want)
+		}
+	}
+}
+
+func TestTokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	f := NewTokenFile(path)
+	if _, err := f.Token(); err == nil {
+		t.Error("missing token file accepted")
+	}
+
+	if err := os.WriteFile(path, []byte("first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := f.Token(); err != nil || token != "first" {
+		t.Fatalf("Token() = %q, %v, want first", token, err)
+	}
+
+	// A rotated token is picked up once the file changes.
+	if err := os.WriteFile(path, []byte("rotated"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := f.Token(); err != nil || token != "rotated" {
+		t.Errorf("Token() = %q, %v, want the rotated token", token, err)
+	}
+
+	if err := os.WriteFile(path, []byte(" \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Token(); err == nil {
+		t.Error("empty token file accepted")
+	}
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// ApplyOptions configures `audicia apply`.
+type ApplyOptions struct {
+	selector
+
+	// DryRun submits manifests with server-side dry run and leaves policy
+	// state untouched.
+	DryRun bool
+}
+
+// Apply server-side applies the suggested manifests of matching
+// AudiciaPolicies and marks each fully applied policy as Applied. A policy
+// whose manifests fail to apply is reported and left in its current state.
+func Apply(ctx context.Context, c client.Client, opts ApplyOptions, out io.Writer) error {
+	policies, err := listPolicies(ctx, c, opts.selector)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for i := range policies {
+		p := &policies[i]
+		if err := applyPolicy(ctx, c, p, opts.DryRun, out); err != nil {
+			_, _ = fmt.Fprintf(out, "error: %s/%s: %v\n", p.Namespace, p.Name, err)
+			failed++
+			continue
+		}
+		if opts.DryRun {
+			continue
+		}
+		if err := markApplied(ctx, c, p); err != nil {
+			_, _ = fmt.Fprintf(out, "error: %s/%s: marking Applied: %v\n", p.Namespace, p.Name, err)
+			failed++
+		}
+	}
+
+	_, _ = fmt.Fprintf(out, "applied %d of %d policies\n", len(policies)-failed, len(policies))
+	if failed > 0 {
+		return fmt.Errorf("%d policies failed to apply", failed)
+	}
+	return nil
+}
+
+// applyPolicy applies each manifest of one policy in order (Roles before
+// their Bindings, as rendered).
+func applyPolicy(ctx context.Context, c client.Client, p *audiciav1alpha1.AudiciaPolicy, dryRun bool, out io.Writer) error {
+	for _, m := range p.Spec.Manifests {
+		u := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(m), &u.Object); err != nil {
+			return fmt.Errorf("parsing manifest: %w", err)
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+
+		opts := []client.ApplyOption{client.FieldOwner(fieldOwner), client.ForceOwnership}
+		if dryRun {
+			opts = append(opts, client.DryRunAll)
+		}
+		if err := c.Apply(ctx, client.ApplyConfigurationFromUnstructured(u), opts...); err != nil {
+			return fmt.Errorf("applying %s %s: %w", u.GetKind(), u.GetName(), err)
+		}
+
+		suffix := ""
+		if dryRun {
+			suffix = " (dry run)"
+		}
+		_, _ = fmt.Fprintf(out, "%s/%s applied%s\n", u.GetKind(), qualifiedName(u), suffix)
+	}
+	return nil
+}
+
+// markApplied transitions a policy to the Applied state.
+func markApplied(ctx context.Context, c client.Client, p *audiciav1alpha1.AudiciaPolicy) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var current audiciav1alpha1.AudiciaPolicy
+		if err := c.Get(ctx, client.ObjectKeyFromObject(p), &current); err != nil {
+			return err
+		}
+		current.Status.State = audiciav1alpha1.PolicyStateApplied
+		return c.Status().Update(ctx, &current)
+	})
+}
+
+func qualifiedName(u *unstructured.Unstructured) string {
+	if u.GetNamespace() == "" {
+		return u.GetName()
+	}
+	return u.GetNamespace() + "/" + u.GetName()
+}
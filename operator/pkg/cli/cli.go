@@ -0,0 +1,311 @@
+// Package cli implements the audicia command-line subcommands that operate on
+// Audicia resources from outside the cluster (export, apply, rules, limits,
+// import, groups, compare, replay, verify, schema, install).
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// fieldOwner is the server-side apply field manager used by `audicia apply`.
+const fieldOwner = "audicia-cli"
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(audiciav1alpha1.AddToScheme(scheme))
+}
+
+// ClientFactory builds the Kubernetes client used by a subcommand.
+type ClientFactory func() (client.Client, error)
+
+// DefaultClient builds a client from KUBECONFIG, ~/.kube/config or the
+// in-cluster configuration, in that order.
+func DefaultClient() (client.Client, error) {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	return client.New(cfg, client.Options{Scheme: scheme})
+}
+
+// IsSubcommand reports whether name is a CLI subcommand handled by Run.
+func IsSubcommand(name string) bool {
+	return name == "export" || name == "apply" || name == "rules" || name == "limits" || name == "import" ||
+		name == "groups" || name == "compare" || name == "replay" || name == "verify" || name == "schema" || name == "install"
+}
+
+// Run executes the subcommand named by args[0].
+func Run(ctx context.Context, args []string, stdout io.Writer, newClient ClientFactory) error {
+	if len(args) == 0 || !IsSubcommand(args[0]) {
+		return fmt.Errorf("usage: audicia <export|apply|rules|limits|import|groups|compare|replay|verify|schema|install> [flags]")
+	}
+
+	fs := flag.NewFlagSet("audicia "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	var sel selector
+	fs.StringVar(&sel.namespace, "n", "", "Only read resources from this namespace (default: all namespaces).")
+	fs.StringVar(&sel.subject, "subject", "", "Only include this subject name.")
+
+	switch args[0] {
+	case "export":
+		opts := ExportOptions{}
+		fs.StringVar(&sel.state, "state", "", "Only include policies in this state (Pending, Approved, Applied, Outdated).")
+		fs.StringVar(&opts.OutputDir, "o", "-", "Directory to write one YAML file per policy, or - for stdout.")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		opts.selector = sel
+		return Export(ctx, c, opts, stdout)
+	case "rules":
+		opts := RulesOptions{}
+		fs.StringVar(&opts.Output, "o", "-", "CSV file to write, or - for stdout.")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		opts.selector = sel
+		return ExportRules(ctx, c, opts, stdout)
+	case "limits":
+		opts := LimitsOptions{}
+		var maxRules, retentionDays int
+		fs.IntVar(&maxRules, "max-rules", aggregator.DefaultMaxRulesPerReport, "Proposed spec.limits.maxRulesPerReport.")
+		fs.IntVar(&retentionDays, "retention-days", aggregator.DefaultRetentionDays, "Proposed spec.limits.retentionDays.")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		opts.selector = sel
+		opts.Limits = audiciav1alpha1.LimitsConfig{MaxRulesPerReport: int32(maxRules), RetentionDays: int32(retentionDays)}
+		return PreviewLimits(ctx, c, opts, stdout)
+	case "import":
+		opts := ImportOptions{}
+		var file, user, serviceAccount, observedAt string
+		fs.StringVar(&file, "f", "-", "audit2rbac output to import, or - for stdin.")
+		fs.StringVar(&user, "user", "", "Subject of roles without a binding, as passed to audit2rbac --user.")
+		fs.StringVar(&serviceAccount, "serviceaccount", "", "Subject of roles without a binding as namespace:name, as passed to audit2rbac --serviceaccount.")
+		fs.StringVar(&observedAt, "observed-at", "", "RFC 3339 time recorded as first and last seen of imported rules (default: now).")
+		fs.BoolVar(&opts.DryRun, "dry-run", false, "Print what would be imported without writing reports.")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		subject, err := ParseImportSubject(user, serviceAccount)
+		if err != nil {
+			return err
+		}
+		opts.Subject = subject
+		if observedAt != "" {
+			if opts.ObservedAt, err = time.Parse(time.RFC3339, observedAt); err != nil {
+				return fmt.Errorf("invalid -observed-at: %w", err)
+			}
+		}
+		in := io.Reader(os.Stdin)
+		if file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			in = f
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		opts.selector = sel
+		return ImportAudit2RBAC(ctx, c, opts, in, stdout)
+	case "groups":
+		opts := GroupsOptions{}
+		fs.Float64Var(&opts.MinSimilarity, "min-similarity", DefaultMinSimilarity, "Jaccard similarity of observed rules every pair of members must reach (0-1).")
+		fs.IntVar(&opts.MinGroupSize, "min-users", DefaultMinGroupSize, "Smallest group to suggest.")
+		fs.BoolVar(&opts.Manifests, "manifests", false, "Print the proposed group Roles and bindings as YAML.")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if opts.MinSimilarity > 1 {
+			return fmt.Errorf("invalid -min-similarity %v: must be at most 1", opts.MinSimilarity)
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		opts.selector = sel
+		return SuggestGroups(ctx, c, opts, stdout)
+	case "compare":
+		opts := CompareOptions{}
+		var base, target string
+		fs.StringVar(&base, "base", "", "Label selector of the reference environment's reports, e.g. env=staging (required).")
+		fs.StringVar(&target, "target", "", "Label selector of the reports compared against -base, e.g. env=prod (required).")
+		fs.BoolVar(&opts.FailOnExtra, "fail-on-extra", false, "Fail if a subject needs permissions in -target not observed in -base.")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if base == "" || target == "" {
+			return fmt.Errorf("usage: audicia compare -base <selector> -target <selector> [flags]")
+		}
+		var err error
+		if opts.Base, err = labels.Parse(base); err != nil {
+			return fmt.Errorf("invalid -base: %w", err)
+		}
+		if opts.Target, err = labels.Parse(target); err != nil {
+			return fmt.Errorf("invalid -target: %w", err)
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		opts.selector = sel
+		return CompareEnvironments(ctx, c, opts, stdout)
+	case "replay":
+		opts := ReplayOptions{}
+		var file string
+		fs.StringVar(&file, "f", "-", "Candidate Roles, ClusterRoles and bindings to replay the observed rules against, or - for stdin.")
+		fs.IntVar(&opts.MinCoverage, "min-coverage", 100, "Fail if the candidate allows less than this percentage of a report's observed rules.")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if opts.MinCoverage < 0 || opts.MinCoverage > 100 {
+			return fmt.Errorf("invalid -min-coverage %d: must be between 0 and 100", opts.MinCoverage)
+		}
+		in := io.Reader(os.Stdin)
+		if file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			in = f
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		opts.selector = sel
+		return ReplayCandidate(ctx, c, opts, in, stdout)
+	case "verify":
+		opts := VerifyOptions{}
+		fs.StringVar(&opts.Anchor, "anchor", "", "Chain hash recorded at an earlier review that must still be in the chain.")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		opts.selector = sel
+		return VerifyReports(ctx, c, opts, stdout)
+	case "schema":
+		version := fs.String("version", "", "Only match this API version (default: any).")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() > 1 {
+			return fmt.Errorf("usage: audicia schema [flags] [resource]")
+		}
+		return PrintSchema(fs.Arg(0), *version, stdout)
+	case "install":
+		opts := InstallOptions{}
+		var platform string
+		fs.StringVar(&opts.Name, "name", "audicia-operator", "Name of the operator's ServiceAccount, ClusterRole and ClusterRoleBinding.")
+		fs.StringVar(&opts.SourceName, "source", "audit-logs", "Name of the default AudiciaSource.")
+		fs.StringVar(&platform, "platform", string(PlatformAuto), "Platform to tailor the default AudiciaSource to (auto, kubeadm, openshift, eks, gke, aks).")
+		fs.BoolVar(&opts.DryRun, "dry-run", false, "Submit objects with server-side dry run; nothing is persisted.")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		switch opts.Platform = Platform(platform); opts.Platform {
+		case PlatformAuto, PlatformKubeadm, PlatformOpenShift, PlatformEKS, PlatformGKE, PlatformAKS:
+		default:
+			return fmt.Errorf("invalid -platform %q", platform)
+		}
+		opts.Namespace = sel.namespace
+		if opts.Namespace == "" {
+			opts.Namespace = defaultInstallNamespace
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		return Install(ctx, c, opts, stdout)
+	default:
+		opts := ApplyOptions{}
+		fs.StringVar(&sel.state, "state", string(audiciav1alpha1.PolicyStateApproved), "Only apply policies in this state; empty applies every state.")
+		fs.BoolVar(&opts.DryRun, "dry-run", false, "Submit manifests with server-side dry run; nothing is persisted.")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		opts.selector = sel
+		return Apply(ctx, c, opts, stdout)
+	}
+}
+
+// selector narrows the AudiciaPolicies a subcommand operates on.
+type selector struct {
+	namespace string
+	state     string
+	subject   string
+
+	// labels narrows AudiciaReports by their labels; nil matches all.
+	labels labels.Selector
+}
+
+// listPolicies returns matching AudiciaPolicies sorted by namespace and name.
+func listPolicies(ctx context.Context, c client.Reader, sel selector) ([]audiciav1alpha1.AudiciaPolicy, error) {
+	var list audiciav1alpha1.AudiciaPolicyList
+	var opts []client.ListOption
+	if sel.namespace != "" {
+		opts = append(opts, client.InNamespace(sel.namespace))
+	}
+	if err := c.List(ctx, &list, opts...); err != nil {
+		return nil, fmt.Errorf("listing AudiciaPolicies: %w", err)
+	}
+
+	var out []audiciav1alpha1.AudiciaPolicy
+	for _, p := range list.Items {
+		if sel.state != "" && string(p.Status.State) != sel.state {
+			continue
+		}
+		if sel.subject != "" && p.Spec.Subject.Name != sel.subject {
+			continue
+		}
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
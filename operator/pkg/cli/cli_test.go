@@ -0,0 +1,812 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/controller/audiciasource"
+	"github.com/felixnotka/audicia/operator/pkg/integrity"
+)
+
+const testRole = `apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: suggested-backend-role
+  namespace: prod
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - pods
+  verbs:
+  - get
+`
+
+const testBinding = `apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: suggested-backend-binding
+  namespace: prod
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: suggested-backend-role
+subjects:
+- kind: ServiceAccount
+  name: backend
+  namespace: prod
+`
+
+func testPolicy(namespace, name, subject string, state audiciav1alpha1.PolicyState, manifests ...string) *audiciav1alpha1.AudiciaPolicy {
+	return &audiciav1alpha1.AudiciaPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: audiciav1alpha1.AudiciaPolicySpec{
+			Subject:   audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: subject, Namespace: namespace},
+			SourceRef: "src",
+			Manifests: manifests,
+		},
+		Status: audiciav1alpha1.AudiciaPolicyStatus{State: state},
+	}
+}
+
+func newFakeClient(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&audiciav1alpha1.AudiciaPolicy{}, &audiciav1alpha1.AudiciaReport{}).
+		Build()
+}
+
+func TestExport_Stdout(t *testing.T) {
+	c := newFakeClient(
+		testPolicy("prod", "policy-backend", "backend", audiciav1alpha1.PolicyStateApproved, testRole, testBinding),
+		testPolicy("dev", "policy-tool", "tool", audiciav1alpha1.PolicyStatePending, testRole),
+	)
+
+	var out bytes.Buffer
+	opts := ExportOptions{selector: selector{state: "Approved"}}
+	if err := Export(context.Background(), c, opts, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	if strings.Count(got, "---\n") != 2 {
+		t.Errorf("expected 2 YAML documents, got:\n%s", got)
+	}
+	if !strings.Contains(got, "# AudiciaPolicy prod/policy-backend") || strings.Contains(got, "policy-tool") {
+		t.Errorf("state filter not applied:\n%s", got)
+	}
+}
+
+func TestExport_Directory(t *testing.T) {
+	c := newFakeClient(
+		testPolicy("prod", "policy-backend", "backend", audiciav1alpha1.PolicyStatePending, testRole, testBinding),
+		testPolicy("dev", "policy-tool", "tool", audiciav1alpha1.PolicyStatePending, testRole),
+	)
+	dir := filepath.Join(t.TempDir(), "out")
+
+	var out bytes.Buffer
+	if err := Export(context.Background(), c, ExportOptions{OutputDir: dir}, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "prod_policy-backend.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "kind: RoleBinding") {
+		t.Errorf("exported file missing binding:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "dev_policy-tool.yaml")); err != nil {
+		t.Errorf("expected second policy file: %v", err)
+	}
+}
+
+func TestApply_AppliesApprovedAndMarksApplied(t *testing.T) {
+	c := newFakeClient(
+		testPolicy("prod", "policy-backend", "backend", audiciav1alpha1.PolicyStateApproved, testRole, testBinding),
+		testPolicy("prod", "policy-other", "other", audiciav1alpha1.PolicyStatePending, testRole),
+	)
+
+	var out bytes.Buffer
+	opts := ApplyOptions{selector: selector{state: "Approved"}}
+	if err := Apply(context.Background(), c, opts, &out); err != nil {
+		t.Fatalf("Apply() error = %v\n%s", err, out.String())
+	}
+
+	var role rbacv1.Role
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "prod", Name: "suggested-backend-role"}, &role); err != nil {
+		t.Fatalf("role not applied: %v", err)
+	}
+	var binding rbacv1.RoleBinding
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "prod", Name: "suggested-backend-binding"}, &binding); err != nil {
+		t.Fatalf("binding not applied: %v", err)
+	}
+
+	var policy audiciav1alpha1.AudiciaPolicy
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "prod", Name: "policy-backend"}, &policy); err != nil {
+		t.Fatal(err)
+	}
+	if policy.Status.State != audiciav1alpha1.PolicyStateApplied {
+		t.Errorf("state = %s, want Applied", policy.Status.State)
+	}
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "prod", Name: "policy-other"}, &policy); err != nil {
+		t.Fatal(err)
+	}
+	if policy.Status.State != audiciav1alpha1.PolicyStatePending {
+		t.Errorf("pending policy state = %s, want untouched", policy.Status.State)
+	}
+}
+
+func TestApply_DryRunLeavesStateAlone(t *testing.T) {
+	c := newFakeClient(testPolicy("prod", "policy-backend", "backend", audiciav1alpha1.PolicyStateApproved, testRole))
+
+	var out bytes.Buffer
+	opts := ApplyOptions{selector: selector{state: "Approved"}, DryRun: true}
+	if err := Apply(context.Background(), c, opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "(dry run)") {
+		t.Errorf("expected dry-run output, got:\n%s", out.String())
+	}
+
+	var policy audiciav1alpha1.AudiciaPolicy
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "prod", Name: "policy-backend"}, &policy); err != nil {
+		t.Fatal(err)
+	}
+	if policy.Status.State != audiciav1alpha1.PolicyStateApproved {
+		t.Errorf("state = %s, want Approved after dry run", policy.Status.State)
+	}
+}
+
+func TestApply_InvalidManifestReportsFailure(t *testing.T) {
+	c := newFakeClient(testPolicy("prod", "policy-bad", "bad", audiciav1alpha1.PolicyStateApproved, "kind: [unterminated"))
+
+	var out bytes.Buffer
+	err := Apply(context.Background(), c, ApplyOptions{selector: selector{state: "Approved"}}, &out)
+	if err == nil {
+		t.Fatal("expected error for unparsable manifest")
+	}
+	if !strings.Contains(out.String(), "prod/policy-bad") {
+		t.Errorf("expected failing policy in output, got:\n%s", out.String())
+	}
+}
+
+func TestRun_ParsesFlags(t *testing.T) {
+	c := newFakeClient(
+		testPolicy("prod", "policy-backend", "backend", audiciav1alpha1.PolicyStatePending, testRole),
+		testPolicy("dev", "policy-tool", "tool", audiciav1alpha1.PolicyStatePending, testRole),
+	)
+	factory := func() (client.Client, error) { return c, nil }
+
+	var out bytes.Buffer
+	if err := Run(context.Background(), []string{"export", "-n", "dev"}, &out, factory); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "dev/policy-tool") || strings.Contains(out.String(), "prod/") {
+		t.Errorf("namespace flag not applied:\n%s", out.String())
+	}
+
+	if err := Run(context.Background(), []string{"frobnicate"}, &out, factory); err == nil {
+		t.Error("expected usage error for unknown subcommand")
+	}
+}
+
+func TestExportRules_CSV(t *testing.T) {
+	seen := metav1.NewTime(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	report := &audiciav1alpha1.AudiciaReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "report-backend", Namespace: "prod"},
+		Spec: audiciav1alpha1.AudiciaReportSpec{
+			Subject: audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "backend", Namespace: "prod"},
+		},
+		Status: audiciav1alpha1.AudiciaReportStatus{
+			ObservedRules: []audiciav1alpha1.ObservedRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list"}, Namespace: "prod", FirstSeen: seen, LastSeen: seen, Count: 7},
+				{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"patch"}, Namespace: "prod", FirstSeen: seen, LastSeen: seen, Count: 1},
+			},
+			Compliance: &audiciav1alpha1.ComplianceReport{
+				UncoveredRules: []audiciav1alpha1.ComplianceRule{
+					{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"patch"}, Namespace: "prod"},
+				},
+				ExcessRules: []audiciav1alpha1.ComplianceRule{
+					{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}, Namespace: "prod"},
+				},
+			},
+		},
+	}
+	c := newFakeClient(report)
+
+	var out bytes.Buffer
+	if err := ExportRules(context.Background(), c, RulesOptions{}, &out); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"subject,kind,ns,apiGroup,resource,verbs,count,firstSeen,lastSeen,covered",
+		"prod/backend,ServiceAccount,prod,,pods,get list,7,2026-03-01T12:00:00Z,2026-03-01T12:00:00Z,true",
+		"prod/backend,ServiceAccount,prod,apps,deployments,patch,1,2026-03-01T12:00:00Z,2026-03-01T12:00:00Z,false",
+		"prod/backend,ServiceAccount,prod,,secrets,get,0,,,true",
+	}, "\n") + "\n"
+	if out.String() != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestExportRules_UnevaluatedComplianceLeavesCoveredEmpty(t *testing.T) {
+	report := &audiciav1alpha1.AudiciaReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "report-alice", Namespace: "ops"},
+		Spec:       audiciav1alpha1.AudiciaReportSpec{Subject: audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "alice"}},
+		Status: audiciav1alpha1.AudiciaReportStatus{
+			ObservedRules: []audiciav1alpha1.ObservedRule{
+				{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}, Count: 2},
+			},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "rules.csv")
+
+	var out bytes.Buffer
+	if err := ExportRules(context.Background(), newFakeClient(report), RulesOptions{Output: path}, &out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(data), "alice,User,,,/metrics,get,2,,,\n") {
+		t.Errorf("unexpected CSV:\n%s", data)
+	}
+	if !strings.Contains(out.String(), "1 rules from 1 reports") {
+		t.Errorf("unexpected summary %q", out.String())
+	}
+}
+
+func TestPreviewLimits(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	seen := func(age time.Duration) metav1.Time { return metav1.NewTime(now.Add(-age)) }
+	report := func(name string, ages ...time.Duration) *audiciav1alpha1.AudiciaReport {
+		r := &audiciav1alpha1.AudiciaReport{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "prod"},
+			Spec:       audiciav1alpha1.AudiciaReportSpec{Subject: audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: name}},
+		}
+		for _, age := range ages {
+			r.Status.ObservedRules = append(r.Status.ObservedRules,
+				audiciav1alpha1.ObservedRule{Resources: []string{"pods"}, Verbs: []string{"get"}, LastSeen: seen(age)})
+		}
+		return r
+	}
+	day := 24 * time.Hour
+	c := newFakeClient(
+		report("report-a", time.Hour, 2*day, 10*day),
+		report("report-b", time.Hour),
+	)
+
+	var out bytes.Buffer
+	opts := LimitsOptions{Limits: audiciav1alpha1.LimitsConfig{MaxRulesPerReport: 1, RetentionDays: 7}, Now: now}
+	if err := PreviewLimits(context.Background(), c, opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "REPORT         RULES  EXPIRED  TRUNCATED  KEPT\n" +
+		"prod/report-a  3      1        1          1\n" +
+		"would drop 2 of 4 rules in 1 of 2 reports\n"
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestSuggestGroups(t *testing.T) {
+	report := func(kind audiciav1alpha1.SubjectKind, name string, resources ...string) *audiciav1alpha1.AudiciaReport {
+		r := &audiciav1alpha1.AudiciaReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "report-" + name, Namespace: "prod"},
+			Spec:       audiciav1alpha1.AudiciaReportSpec{Subject: audiciav1alpha1.Subject{Kind: kind, Name: name}},
+		}
+		for _, res := range resources {
+			r.Status.ObservedRules = append(r.Status.ObservedRules, audiciav1alpha1.ObservedRule{
+				APIGroups: []string{""}, Resources: []string{res}, Verbs: []string{"get", "list"}, Namespace: "prod",
+			})
+		}
+		return r
+	}
+	user := audiciav1alpha1.SubjectKindUser
+	c := newFakeClient(
+		report(user, "alice", "pods", "services", "configmaps", "endpoints", "events"),
+		report(user, "bob", "pods", "services", "configmaps", "endpoints", "events"),
+		// 8 of 12 permissions shared with alice and bob: similarity 0.67.
+		report(user, "carol", "pods", "services", "configmaps", "endpoints", "secrets"),
+		report(user, "dave", "nodes"),
+		report(audiciav1alpha1.SubjectKindServiceAccount, "backend", "pods", "services", "configmaps", "endpoints", "events"),
+	)
+
+	var out bytes.Buffer
+	if err := SuggestGroups(context.Background(), c, GroupsOptions{}, &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "GROUP              USERS  SHARED  SIMILARITY  MEMBERS\n" +
+		"suggested-group-1  2      10      1.00        alice,bob\n" +
+		"2 of 4 users fit 1 suggested groups\n"
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	opts := GroupsOptions{MinSimilarity: 0.6, MinGroupSize: 3, Manifests: true}
+	if err := SuggestGroups(context.Background(), c, opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "suggested-group-1  3      8       0.67        alice,bob,carol") {
+		t.Errorf("expected alice, bob and carol grouped at 0.6, got\n%s", got)
+	}
+	if !strings.Contains(got, "kind: Group") || !strings.Contains(got, "name: suggested-group-1") || strings.Contains(got, "secrets") {
+		t.Errorf("expected a Role for the shared rules bound to the group, got\n%s", got)
+	}
+}
+
+func TestVerifyReports(t *testing.T) {
+	rules := []audiciav1alpha1.ObservedRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}, Count: 1}}
+	chain, err := integrity.Append(nil, nil, rules, time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := func(name string, rules []audiciav1alpha1.ObservedRule, chain *audiciav1alpha1.IntegrityStatus) *audiciav1alpha1.AudiciaReport {
+		return &audiciav1alpha1.AudiciaReport{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "prod"},
+			Status:     audiciav1alpha1.AudiciaReportStatus{ObservedRules: rules, Integrity: chain},
+		}
+	}
+	edited := append(slices.Clone(rules), audiciav1alpha1.ObservedRule{Resources: []string{"secrets"}, Verbs: []string{"get"}})
+
+	var out bytes.Buffer
+	c := newFakeClient(report("report-a", rules, chain), report("report-b", rules, nil))
+	if err := VerifyReports(context.Background(), c, VerifyOptions{}, &out); err != nil {
+		t.Fatalf("VerifyReports() = %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "1 of 2 reports verified, 0 failed, 1 without a chain") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	c = newFakeClient(report("report-a", edited, chain))
+	if err := VerifyReports(context.Background(), c, VerifyOptions{}, &out); err == nil {
+		t.Errorf("expected edited report to fail verification:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "FAILED: observed rules were changed") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+const testAudit2RBAC = `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: audit2rbac:alice
+rules:
+- apiGroups: [""]
+  resources: ["nodes"]
+  verbs: ["get", "list"]
+- nonResourceURLs: ["/healthz"]
+  verbs: ["get"]
+- apiGroups: ["*"]
+  resources: ["*"]
+  verbs: ["get"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: audit2rbac:alice
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: audit2rbac:alice
+subjects:
+- apiGroup: rbac.authorization.k8s.io
+  kind: User
+  name: alice
+---
+` + testRole + `---
+` + testBinding
+
+func TestImportAudit2RBAC(t *testing.T) {
+	observedAt := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	earlier := metav1.NewTime(observedAt.Add(-24 * time.Hour))
+	// backend already has a report written by the source.
+	existing := &audiciav1alpha1.AudiciaReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "report-backend", Namespace: "prod"},
+		Spec: audiciav1alpha1.AudiciaReportSpec{Subject: audiciav1alpha1.Subject{
+			Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "backend", Namespace: "prod"}},
+		Status: audiciav1alpha1.AudiciaReportStatus{ObservedRules: []audiciav1alpha1.ObservedRule{{
+			APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}, Namespace: "prod",
+			FirstSeen: earlier, LastSeen: earlier, Count: 5,
+		}}},
+	}
+	c := newFakeClient(existing)
+
+	var out bytes.Buffer
+	opts := ImportOptions{selector: selector{namespace: "audicia-system"}, ObservedAt: observedAt}
+	if err := ImportAudit2RBAC(context.Background(), c, opts, strings.NewReader(testAudit2RBAC), &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "skipped 1 wildcard rule entries (not observations)\n" +
+		"REPORT                       SUBJECT                 IMPORTED  RULES\n" +
+		"prod/report-backend          ServiceAccount backend  1         1\n" +
+		"audicia-system/report-alice  User alice              3         3\n" +
+		"imported 2 reports\n"
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	var alice audiciav1alpha1.AudiciaReport
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "audicia-system", Name: "report-alice"}, &alice); err != nil {
+		t.Fatal(err)
+	}
+	if alice.Annotations[audiciasource.ImportedFromAnnotation] != "audit2rbac" {
+		t.Errorf("annotations = %v, want imported-from audit2rbac", alice.Annotations)
+	}
+	if alice.Spec.Subject.Kind != audiciav1alpha1.SubjectKindUser || alice.Spec.Subject.Name != "alice" {
+		t.Errorf("subject = %+v, want User alice", alice.Spec.Subject)
+	}
+	if n := len(alice.Status.ObservedRules); n != 3 {
+		t.Fatalf("alice has %d rules, want 3", n)
+	}
+	for _, r := range alice.Status.ObservedRules {
+		if r.Namespace != "" || !r.LastSeen.Time.Equal(observedAt) {
+			t.Errorf("rule %+v: want cluster scope, last seen %v", r, observedAt)
+		}
+	}
+
+	// The overlapping rule keeps the earlier FirstSeen and the higher count.
+	var backend audiciav1alpha1.AudiciaReport
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "prod", Name: "report-backend"}, &backend); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(backend.Status.ObservedRules); n != 1 {
+		t.Fatalf("backend has %d rules, want 1", n)
+	}
+	r := backend.Status.ObservedRules[0]
+	if !r.FirstSeen.Equal(&earlier) || !r.LastSeen.Time.Equal(observedAt) || r.Count != 5 {
+		t.Errorf("merged rule = %+v", r)
+	}
+}
+
+func TestRun_ImportUnboundRoleAndDryRun(t *testing.T) {
+	c := newFakeClient()
+	factory := func() (client.Client, error) { return c, nil }
+	file := filepath.Join(t.TempDir(), "role.yaml")
+	if err := os.WriteFile(file, []byte(testRole), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	args := []string{"import", "-n", "audicia-system", "-f", file, "-serviceaccount", "prod:backend", "-dry-run"}
+	if err := Run(context.Background(), args, &out, factory); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "prod/report-backend") || !strings.Contains(out.String(), "(dry run)") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+	var list audiciav1alpha1.AudiciaReportList
+	if err := c.List(context.Background(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 0 {
+		t.Errorf("dry run created %d reports", len(list.Items))
+	}
+
+	if err := Run(context.Background(), []string{"import", "-f", file}, &out, factory); err == nil {
+		t.Error("expected error without -n")
+	}
+	if err := Run(context.Background(), []string{"import", "-n", "x", "-user", "a", "-serviceaccount", "b:c"}, &out, factory); err == nil {
+		t.Error("expected error for -user with -serviceaccount")
+	}
+}
+
+func TestRun_Schema(t *testing.T) {
+	noClient := func() (client.Client, error) {
+		t.Fatal("schema must not need a cluster")
+		return nil, nil
+	}
+
+	var out bytes.Buffer
+	if err := Run(context.Background(), []string{"schema"}, &out, noClient); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "/schemas/audicia.io/v1alpha1/audiciareports.json") {
+		t.Errorf("index lacks the report schema:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := Run(context.Background(), []string{"schema", "-version", "v1alpha1", "AudiciaSource"}, &out, noClient); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"maxSubjectsPerSource"`) {
+		t.Error("AudiciaSource schema lacks spec.limits fields")
+	}
+
+	if err := Run(context.Background(), []string{"schema", "pods"}, &out, noClient); err == nil {
+		t.Error("expected an error for a non-Audicia resource")
+	}
+}
+
+func testNode(name string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestDetectPlatform(t *testing.T) {
+	for _, tc := range []struct {
+		nodes []client.Object
+		want  Platform
+	}{
+		{[]client.Object{testNode("cp", map[string]string{controlPlaneLabel: ""})}, PlatformKubeadm},
+		{[]client.Object{testNode("cp", map[string]string{controlPlaneLabel: "", "node.openshift.io/os_id": "rhcos"})}, PlatformOpenShift},
+		{[]client.Object{testNode("w", map[string]string{"eks.amazonaws.com/nodegroup": "ng"})}, PlatformEKS},
+		{[]client.Object{testNode("w", map[string]string{"cloud.google.com/gke-nodepool": "pool"})}, PlatformGKE},
+		{[]client.Object{testNode("w", map[string]string{"kubernetes.azure.com/cluster": "mc"})}, PlatformAKS},
+		{[]client.Object{testNode("w", nil)}, PlatformUnknown},
+	} {
+		got, err := DetectPlatform(context.Background(), newFakeClient(tc.nodes...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("DetectPlatform(%v) = %s, want %s", tc.nodes[0].GetLabels(), got, tc.want)
+		}
+	}
+}
+
+func TestInstall_Kubeadm(t *testing.T) {
+	c := newFakeClient(testNode("cp", map[string]string{controlPlaneLabel: ""}))
+	factory := func() (client.Client, error) { return c, nil }
+
+	var out bytes.Buffer
+	if err := Run(context.Background(), []string{"install"}, &out, factory); err != nil {
+		t.Fatalf("install: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "CustomResourceDefinition/audiciasources.audicia.io applied") {
+		t.Errorf("CRDs not applied:\n%s", out.String())
+	}
+
+	var binding rbacv1.ClusterRoleBinding
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "audicia-operator"}, &binding); err != nil {
+		t.Fatalf("binding not applied: %v", err)
+	}
+	if s := binding.Subjects[0]; s.Name != "audicia-operator" || s.Namespace != defaultInstallNamespace {
+		t.Errorf("binding subject = %+v", s)
+	}
+	var source audiciav1alpha1.AudiciaSource
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: defaultInstallNamespace, Name: "audit-logs"}, &source); err != nil {
+		t.Fatalf("source not applied: %v", err)
+	}
+	if source.Spec.Location == nil || source.Spec.Location.Path != auditLogPaths[PlatformKubeadm] {
+		t.Errorf("source location = %+v", source.Spec.Location)
+	}
+}
+
+func TestInstall_ManagedPlatformSkipsSource(t *testing.T) {
+	c := newFakeClient()
+	factory := func() (client.Client, error) { return c, nil }
+
+	var out bytes.Buffer
+	if err := Run(context.Background(), []string{"install", "-n", "audit", "-platform", "eks"}, &out, factory); err != nil {
+		t.Fatalf("install: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "docs/guides/eks-setup.md") {
+		t.Errorf("missing setup guide hint:\n%s", out.String())
+	}
+	var sources audiciav1alpha1.AudiciaSourceList
+	if err := c.List(context.Background(), &sources); err != nil {
+		t.Fatal(err)
+	}
+	if len(sources.Items) != 0 {
+		t.Errorf("managed platform got %d sources", len(sources.Items))
+	}
+	var sa corev1.ServiceAccount
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "audit", Name: "audicia-operator"}, &sa); err != nil {
+		t.Errorf("service account not applied: %v", err)
+	}
+
+	if err := Run(context.Background(), []string{"install", "-platform", "minikube"}, &out, factory); err == nil {
+		t.Error("expected an error for an unknown platform")
+	}
+}
+
+func TestCompareEnvironments(t *testing.T) {
+	report := func(name, env string, subject audiciav1alpha1.Subject, verbs ...string) *audiciav1alpha1.AudiciaReport {
+		return &audiciav1alpha1.AudiciaReport{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: map[string]string{"env": env}},
+			Spec:       audiciav1alpha1.AudiciaReportSpec{Subject: subject},
+			Status: audiciav1alpha1.AudiciaReportStatus{ObservedRules: []audiciav1alpha1.ObservedRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: verbs, Namespace: "shop"},
+			}},
+		}
+	}
+	backend := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "backend", Namespace: "shop"}
+	worker := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "worker", Namespace: "shop"}
+	c := newFakeClient(
+		report("staging-backend", "staging", backend, "get", "list"),
+		report("prod-backend", "prod", backend, "get", "delete"),
+		report("staging-worker", "staging", worker, "get", "list", "watch"),
+		report("prod-worker", "prod", worker, "get"),
+	)
+
+	var out bytes.Buffer
+	err := Run(context.Background(), []string{"compare", "-base", "env=staging", "-target", "env=prod"}, &out,
+		func() (client.Client, error) { return c, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SUBJECT                      BASE  TARGET  EXTRA  UNUSED\n" +
+		"ServiceAccount/shop/backend  2     2       1      1\n" +
+		"ServiceAccount/shop/worker   3     1       0      2\n" +
+		"\nServiceAccount/shop/backend needs 1 permission(s) not observed in env=staging:\n" +
+		"  + delete pods in shop\n" +
+		"\n1 of 2 subjects need permissions in env=prod not observed in env=staging\n"
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	err = Run(context.Background(), []string{"compare", "-base", "env=staging", "-target", "env=prod", "-fail-on-extra"}, &out,
+		func() (client.Client, error) { return c, nil })
+	if err == nil {
+		t.Error("expected -fail-on-extra to fail when prod needs more than staging")
+	}
+	if err := Run(context.Background(), []string{"compare", "-base", "env=staging"}, &out, nil); err == nil {
+		t.Error("expected an error without -target")
+	}
+}
+
+func TestReplayCandidate(t *testing.T) {
+	c := newFakeClient(&audiciav1alpha1.AudiciaReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "shop"},
+		Spec: audiciav1alpha1.AudiciaReportSpec{Subject: audiciav1alpha1.Subject{
+			Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "backend", Namespace: "shop"}},
+		Status: audiciav1alpha1.AudiciaReportStatus{ObservedRules: []audiciav1alpha1.ObservedRule{
+			{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list"}, Namespace: "shop"},
+			{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}, Namespace: "shop"},
+		}},
+	})
+	candidate := `apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: backend
+  namespace: shop
+rules:
+- apiGroups: [""]
+  resources: ["pods"]
+  verbs: ["get", "list"]
+- apiGroups: ["apps"]
+  resources: ["deployments"]
+  verbs: ["get"]
+`
+	file := filepath.Join(t.TempDir(), "candidate.yaml")
+	if err := os.WriteFile(file, []byte(candidate), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	newClient := func() (client.Client, error) { return c, nil }
+
+	var out bytes.Buffer
+	err := Run(context.Background(), []string{"replay", "-f", file}, &out, newClient)
+	if err == nil {
+		t.Error("expected a candidate denying secrets to fail at -min-coverage 100")
+	}
+	want := "REPORT        SUBJECT                      OBSERVED  COVERED  COVERAGE  UNUSED\n" +
+		"shop/backend  ServiceAccount/shop/backend  2         1        50%       1\n" +
+		"\nshop/backend: denied get secrets in shop" +
+		"\nshop/backend: unused get deployments.apps in shop" +
+		"\n1 of 1 reports below 100% coverage\n"
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	if err := Run(context.Background(), []string{"replay", "-f", file, "-min-coverage", "50"}, &out, newClient); err != nil {
+		t.Errorf("replay at -min-coverage 50: %v", err)
+	}
+
+	// A ClusterRole bound by a RoleBinding only applies in its namespace.
+	rules, err := parseCandidate(strings.NewReader(`kind: ClusterRole
+metadata:
+  name: reader
+rules:
+- apiGroups: [""]
+  resources: ["secrets"]
+  verbs: ["get"]
+---
+kind: RoleBinding
+metadata:
+  name: reader
+  namespace: shop
+roleRef:
+  kind: ClusterRole
+  name: reader
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 1 || rules[0].Namespace != "shop" {
+		t.Errorf("rules = %+v, want the ClusterRole's rule scoped to shop", rules)
+	}
+}
+
+// chartRules returns the rules of a Helm chart RBAC template, rendered with
+// the default release name and without the blocks of the optional features
+// named in skip, such as policyPlans.
+func chartRules(t *testing.T, template string, skip ...string) []rbacv1.PolicyRule {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("../../../deploy/helm/templates/rbac", template))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replacer := strings.NewReplacer(
+		`{{ printf "%s-autodiscovery" (include "audicia.fullname" .) | quote }}`, `"audicia-operator-autodiscovery"`,
+		`{{ include "audicia.fullname" . }}`, "audicia-operator",
+		`{{ include "audicia.serviceAccountName" . }}`, "audicia-operator",
+		`{{ .Release.Namespace }}`, defaultInstallNamespace,
+	)
+	var rendered []string
+	skipping := false
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "{{- if "):
+			skipping = slices.ContainsFunc(skip, func(feature string) bool { return strings.Contains(trimmed, ".Values."+feature+".") })
+		case trimmed == "{{- end }}":
+			skipping = false
+		case skipping, strings.Contains(trimmed, `include "audicia.labels"`):
+		default:
+			rendered = append(rendered, replacer.Replace(line))
+		}
+	}
+	doc, _, _ := strings.Cut(strings.Join(rendered, "\n"), "\n---")
+	var role rbacv1.ClusterRole
+	if err := yaml.Unmarshal([]byte(doc), &role); err != nil {
+		t.Fatalf("parsing %s: %v", template, err)
+	}
+	return role.Rules
+}
+
+// permissions flattens rules to one entry per group, resource, name and
+// verb, so rules compare regardless of how they are grouped.
+func permissions(rules []rbacv1.PolicyRule) []string {
+	var perms []string
+	for _, r := range rules {
+		names := r.ResourceNames
+		if len(names) == 0 {
+			names = []string{"*"}
+		}
+		for _, g := range r.APIGroups {
+			for _, res := range r.Resources {
+				for _, n := range names {
+					for _, v := range r.Verbs {
+						perms = append(perms, g+"/"+res+"/"+n+":"+v)
+					}
+				}
+			}
+		}
+	}
+	slices.Sort(perms)
+	return slices.Compact(perms)
+}
+
+func TestInstall_RulesMatchChart(t *testing.T) {
+	if got, want := permissions(operatorRules()), permissions(chartRules(t, "clusterrole.yaml", "policyPlans")); !slices.Equal(got, want) {
+		t.Errorf("operatorRules() differ from the chart's ClusterRole:\n got %v\nwant %v", got, want)
+	}
+	opts := InstallOptions{Name: "audicia-operator", Namespace: defaultInstallNamespace}
+	got := permissions(autodiscoveryRules(autodiscoveryConfigMap(opts)))
+	if want := permissions(chartRules(t, "autodiscovery-role.yaml")); !slices.Equal(got, want) {
+		t.Errorf("autodiscoveryRules() differ from the chart's autodiscovery Role:\n got %v\nwant %v", got, want)
+	}
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// CompareOptions configures `audicia compare`.
+type CompareOptions struct {
+	selector
+
+	// Base selects the reports of the reference environment, e.g.
+	// env=staging.
+	Base labels.Selector
+
+	// Target selects the reports of the environment checked against Base,
+	// e.g. env=prod.
+	Target labels.Selector
+
+	// FailOnExtra fails the comparison when a subject needs permissions in
+	// Target that were not observed in Base.
+	FailOnExtra bool
+}
+
+// SubjectDiff is the difference between the observed permissions of one
+// subject in two environments.
+type SubjectDiff struct {
+	Subject audiciav1alpha1.Subject
+
+	// Base and Target are the numbers of permissions observed in each
+	// environment.
+	Base, Target int
+
+	// Extra are the permissions observed in Target but not in Base.
+	Extra []string
+
+	// Unused are the permissions observed in Base but not in Target.
+	Unused []string
+}
+
+// CompareReports diffs the observed rules of the reports matching two label
+// selectors, subject by subject. Reports of the same subject from several
+// sources or namespaces are merged within each environment. Rules are
+// compared per verb and resource; namespaces are compared as they are, so
+// subjects should use the same namespaces in both environments. Subjects
+// observed in only one environment are included.
+func CompareReports(base, target []audiciav1alpha1.AudiciaReport) []SubjectDiff {
+	type observed struct {
+		subject      audiciav1alpha1.Subject
+		base, target map[permission]bool
+	}
+	bySubject := make(map[string]*observed)
+	collect := func(reports []audiciav1alpha1.AudiciaReport, inBase bool) {
+		for _, r := range reports {
+			key := subjectString(r.Spec.Subject)
+			o := bySubject[key]
+			if o == nil {
+				o = &observed{subject: r.Spec.Subject, base: make(map[permission]bool), target: make(map[permission]bool)}
+				bySubject[key] = o
+			}
+			if inBase {
+				addPermissions(o.base, r.Status.ObservedRules)
+			} else {
+				addPermissions(o.target, r.Status.ObservedRules)
+			}
+		}
+	}
+	collect(base, true)
+	collect(target, false)
+
+	diffs := make([]SubjectDiff, 0, len(bySubject))
+	for _, o := range bySubject {
+		diffs = append(diffs, SubjectDiff{
+			Subject: o.subject,
+			Base:    len(o.base),
+			Target:  len(o.target),
+			Extra:   missingPermissions(o.target, o.base),
+			Unused:  missingPermissions(o.base, o.target),
+		})
+	}
+	slices.SortFunc(diffs, func(a, b SubjectDiff) int {
+		return strings.Compare(subjectString(a.Subject), subjectString(b.Subject))
+	})
+	return diffs
+}
+
+// CompareEnvironments prints the per-subject difference between the
+// observed rules of the reports matching opts.Base and opts.Target, and the
+// permissions each subject needs in Target beyond Base. With FailOnExtra it
+// returns an error if any subject needs such permissions, as a CI gate that
+// production never needs more than staging exercised.
+func CompareEnvironments(ctx context.Context, c client.Reader, opts CompareOptions, out io.Writer) error {
+	baseSel, targetSel := opts.selector, opts.selector
+	baseSel.labels, targetSel.labels = opts.Base, opts.Target
+	base, err := listReports(ctx, c, baseSel)
+	if err != nil {
+		return err
+	}
+	target, err := listReports(ctx, c, targetSel)
+	if err != nil {
+		return err
+	}
+	diffs := CompareReports(base, target)
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "SUBJECT\tBASE\tTARGET\tEXTRA\tUNUSED")
+	for _, d := range diffs {
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\n", subjectString(d.Subject), d.Base, d.Target, len(d.Extra), len(d.Unused))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	exceeding := 0
+	for _, d := range diffs {
+		if len(d.Extra) == 0 {
+			continue
+		}
+		exceeding++
+		_, _ = fmt.Fprintf(out, "\n%s needs %d permission(s) not observed in %s:\n", subjectString(d.Subject), len(d.Extra), opts.Base)
+		for _, p := range d.Extra {
+			_, _ = fmt.Fprintf(out, "  + %s\n", p)
+		}
+	}
+	_, _ = fmt.Fprintf(out, "\n%d of %d subjects need permissions in %s not observed in %s\n",
+		exceeding, len(diffs), opts.Target, opts.Base)
+	if opts.FailOnExtra && exceeding > 0 {
+		return fmt.Errorf("%d subjects need permissions in %s not observed in %s", exceeding, opts.Target, opts.Base)
+	}
+	return nil
+}
+
+// missingPermissions returns the permissions of a not in b, formatted and
+// sorted.
+func missingPermissions(a, b map[permission]bool) []string {
+	var missing []permission
+	for p := range a {
+		if !b[p] {
+			missing = append(missing, p)
+		}
+	}
+	slices.SortFunc(missing, comparePermissions)
+	out := make([]string, len(missing))
+	for i, p := range missing {
+		out[i] = p.String()
+	}
+	return out
+}
+
+// String formats p as "verb resource.group in namespace" or "verb url".
+func (p permission) String() string {
+	if p.nonResourceURL != "" {
+		return p.verb + " " + p.nonResourceURL
+	}
+	s := p.verb + " " + p.resource
+	if p.apiGroup != "" {
+		s += "." + p.apiGroup
+	}
+	if p.namespace != "" {
+		s += " in " + p.namespace
+	}
+	return s
+}
+
+// subjectString formats a subject as Kind/namespace/name, or Kind/name
+// without a namespace.
+func subjectString(s audiciav1alpha1.Subject) string {
+	if s.Namespace != "" {
+		return string(s.Kind) + "/" + s.Namespace + "/" + s.Name
+	}
+	return string(s.Kind) + "/" + s.Name
+}
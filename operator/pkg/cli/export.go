@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/felixnotka/audicia/operator/pkg/reportapi"
+)
+
+// ExportOptions configures `audicia export`.
+type ExportOptions struct {
+	selector
+
+	// OutputDir receives one <namespace>_<name>.yaml file per policy.
+	// "-" or empty writes a single multi-document stream to the writer.
+	OutputDir string
+}
+
+// Export writes the suggested manifests of matching AudiciaPolicies either to
+// out as one multi-document YAML stream or to one file per policy.
+func Export(ctx context.Context, c client.Reader, opts ExportOptions, out io.Writer) error {
+	policies, err := listPolicies(ctx, c, opts.selector)
+	if err != nil {
+		return err
+	}
+
+	toStdout := opts.OutputDir == "" || opts.OutputDir == "-"
+	if !toStdout {
+		if err := os.MkdirAll(opts.OutputDir, 0o755); err != nil {
+			return fmt.Errorf("creating output directory: %w", err)
+		}
+	}
+
+	for _, p := range policies {
+		doc := reportapi.RenderPolicy(p)
+		if toStdout {
+			if _, err := io.WriteString(out, doc); err != nil {
+				return err
+			}
+			continue
+		}
+		path := filepath.Join(opts.OutputDir, fmt.Sprintf("%s_%s.yaml", p.Namespace, p.Name))
+		if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+		_, _ = fmt.Fprintf(out, "wrote %s (%d manifests)\n", path, len(p.Spec.Manifests))
+	}
+	if !toStdout {
+		_, _ = fmt.Fprintf(out, "exported %d policies\n", len(policies))
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/strategy"
+)
+
+const (
+	// DefaultMinSimilarity is the Jaccard similarity every pair of users in a
+	// suggested group must reach.
+	DefaultMinSimilarity = 0.8
+
+	// DefaultMinGroupSize is the smallest suggested group.
+	DefaultMinGroupSize = 2
+)
+
+// GroupsOptions configures `audicia groups`.
+type GroupsOptions struct {
+	selector
+
+	// MinSimilarity is the Jaccard similarity of observed rule sets every
+	// pair of members must reach. Zero uses DefaultMinSimilarity.
+	MinSimilarity float64
+
+	// MinGroupSize is the smallest group suggested. Zero uses
+	// DefaultMinGroupSize.
+	MinGroupSize int
+
+	// Manifests prints the proposed group Roles and bindings as YAML.
+	Manifests bool
+}
+
+// permission is one verb on one resource or non-resource URL, the unit
+// observed rule sets are compared in.
+type permission struct {
+	namespace      string
+	apiGroup       string
+	resource       string
+	nonResourceURL string
+	verb           string
+}
+
+func comparePermissions(a, b permission) int {
+	return cmp.Or(
+		cmp.Compare(a.namespace, b.namespace),
+		cmp.Compare(a.apiGroup, b.apiGroup),
+		cmp.Compare(a.resource, b.resource),
+		cmp.Compare(a.nonResourceURL, b.nonResourceURL),
+		cmp.Compare(a.verb, b.verb),
+	)
+}
+
+// groupSuggestion is a set of users with similar observed rules.
+type groupSuggestion struct {
+	name       string
+	members    []string
+	shared     []permission
+	similarity float64
+}
+
+// SuggestGroups clusters the users of matching AudiciaReports by the
+// similarity of their observed rules and suggests a Group for each cluster,
+// with a role granting the rules all members share. Each member then only
+// needs a personal role for the rest. The output is advisory; nothing is
+// changed.
+func SuggestGroups(ctx context.Context, c client.Reader, opts GroupsOptions, out io.Writer) error {
+	reports, err := listReports(ctx, c, opts.selector)
+	if err != nil {
+		return err
+	}
+	minSimilarity := opts.MinSimilarity
+	if minSimilarity <= 0 {
+		minSimilarity = DefaultMinSimilarity
+	}
+	minSize := opts.MinGroupSize
+	if minSize <= 0 {
+		minSize = DefaultMinGroupSize
+	}
+
+	users := userPermissions(reports)
+	suggestions := clusterUsers(users, minSimilarity, minSize)
+	if len(suggestions) == 0 {
+		_, _ = fmt.Fprintf(out, "no group of %d or more users with similarity >= %.2f among %d users\n",
+			minSize, minSimilarity, len(users))
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "GROUP\tUSERS\tSHARED\tSIMILARITY\tMEMBERS")
+	grouped := 0
+	for _, s := range suggestions {
+		grouped += len(s.members)
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%s\n",
+			s.name, len(s.members), len(s.shared), s.similarity, strings.Join(s.members, ","))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "%d of %d users fit %d suggested groups\n", grouped, len(users), len(suggestions))
+
+	if !opts.Manifests {
+		return nil
+	}
+	engine := strategy.NewEngine(audiciav1alpha1.PolicyStrategy{})
+	for _, s := range suggestions {
+		manifests, err := engine.GenerateManifests(
+			audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindGroup, Name: s.name},
+			permissionRules(s.shared),
+		)
+		if err != nil {
+			return fmt.Errorf("generating manifests for %s: %w", s.name, err)
+		}
+		for _, m := range manifests {
+			_, _ = fmt.Fprintf(out, "---\n%s", m)
+		}
+	}
+	return nil
+}
+
+// userPermissions collects the observed permissions of every User subject.
+// Reports of the same user from several namespaces are merged.
+func userPermissions(reports []audiciav1alpha1.AudiciaReport) map[string]map[permission]bool {
+	users := make(map[string]map[permission]bool)
+	for _, r := range reports {
+		if r.Spec.Subject.Kind != audiciav1alpha1.SubjectKindUser {
+			continue
+		}
+		perms := users[r.Spec.Subject.Name]
+		if perms == nil {
+			perms = make(map[permission]bool)
+			users[r.Spec.Subject.Name] = perms
+		}
+		addPermissions(perms, r.Status.ObservedRules)
+	}
+	return users
+}
+
+// addPermissions adds the permissions of observed rules to perms.
+func addPermissions(perms map[permission]bool, rules []audiciav1alpha1.ObservedRule) {
+	for _, o := range rules {
+		for _, verb := range o.Verbs {
+			for _, url := range o.NonResourceURLs {
+				perms[permission{nonResourceURL: url, verb: verb}] = true
+			}
+			for _, group := range o.APIGroups {
+				for _, resource := range o.Resources {
+					perms[permission{namespace: o.Namespace, apiGroup: group, resource: resource, verb: verb}] = true
+				}
+			}
+		}
+	}
+}
+
+// clusterUsers greedily groups users, in name order, with every other
+// ungrouped user whose rule set is at least minSimilarity similar to each
+// member's (complete linkage), so a group never chains dissimilar users.
+func clusterUsers(users map[string]map[permission]bool, minSimilarity float64, minSize int) []groupSuggestion {
+	names := make([]string, 0, len(users))
+	for name, perms := range users {
+		if len(perms) > 0 {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	grouped := make(map[string]bool, len(names))
+	var suggestions []groupSuggestion
+	for i, seed := range names {
+		if grouped[seed] {
+			continue
+		}
+		members := []string{seed}
+		similarity := 1.0
+		for _, candidate := range names[i+1:] {
+			if grouped[candidate] {
+				continue
+			}
+			lowest := 1.0
+			for _, m := range members {
+				lowest = min(lowest, jaccard(users[m], users[candidate]))
+			}
+			if lowest >= minSimilarity {
+				members = append(members, candidate)
+				similarity = min(similarity, lowest)
+			}
+		}
+		if len(members) < minSize {
+			continue
+		}
+		for _, m := range members {
+			grouped[m] = true
+		}
+		suggestions = append(suggestions, groupSuggestion{
+			name:       fmt.Sprintf("suggested-group-%d", len(suggestions)+1),
+			members:    members,
+			shared:     sharedPermissions(users, members),
+			similarity: similarity,
+		})
+	}
+	return suggestions
+}
+
+// jaccard is the size of the intersection of two sets over their union.
+func jaccard(a, b map[permission]bool) float64 {
+	shared := 0
+	for p := range a {
+		if b[p] {
+			shared++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// sharedPermissions returns the permissions every member has, sorted.
+func sharedPermissions(users map[string]map[permission]bool, members []string) []permission {
+	var shared []permission
+	for p := range users[members[0]] {
+		if !slices.ContainsFunc(members[1:], func(m string) bool { return !users[m][p] }) {
+			shared = append(shared, p)
+		}
+	}
+	slices.SortFunc(shared, comparePermissions)
+	return shared
+}
+
+// permissionRules converts permissions into one observed rule each, as input
+// for the strategy engine.
+func permissionRules(perms []permission) []audiciav1alpha1.ObservedRule {
+	rules := make([]audiciav1alpha1.ObservedRule, 0, len(perms))
+	for _, p := range perms {
+		rule := audiciav1alpha1.ObservedRule{Namespace: p.namespace, Verbs: []string{p.verb}}
+		if p.nonResourceURL != "" {
+			rule.NonResourceURLs = []string{p.nonResourceURL}
+		} else {
+			rule.APIGroups = []string{p.apiGroup}
+			rule.Resources = []string{p.resource}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
@@ -0,0 +1,329 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/controller/audiciasource"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+)
+
+// audit2rbacSource is the ImportedFromAnnotation value for audit2rbac imports.
+const audit2rbacSource = "audit2rbac"
+
+// ImportOptions configures `audicia import`.
+type ImportOptions struct {
+	selector
+
+	// Subject attributes roles that no binding in the input references, as
+	// audit2rbac's --user or --serviceaccount flag did when it generated
+	// them. Nil skips such roles.
+	Subject *audiciav1alpha1.Subject
+
+	// ObservedAt is recorded as FirstSeen and LastSeen of imported rules,
+	// since audit2rbac output carries no timestamps. Zero means now.
+	ObservedAt time.Time
+
+	// DryRun prints what would be imported without writing reports.
+	DryRun bool
+}
+
+// importedRole is a Role or ClusterRole from the input.
+type importedRole struct {
+	namespace string
+	rules     []rbacv1.PolicyRule
+	bound     bool
+}
+
+// ImportAudit2RBAC converts audit2rbac output (a YAML stream of Roles,
+// ClusterRoles and their bindings) into observed rules and merges them into
+// the AudiciaReports that an AudiciaSource in opts.namespace writes. The
+// reports are marked with the audicia.io/imported-from annotation, so the
+// operator keeps the imported rules when it next updates them.
+func ImportAudit2RBAC(ctx context.Context, c client.Client, opts ImportOptions, in io.Reader, out io.Writer) error {
+	if opts.namespace == "" {
+		return fmt.Errorf("-n is required: the namespace of the AudiciaSource that will maintain the reports")
+	}
+	observedAt := opts.ObservedAt
+	if observedAt.IsZero() {
+		observedAt = time.Now()
+	}
+
+	imported, skipped, err := parseAudit2RBAC(in, opts.Subject, metav1.NewTime(observedAt))
+	if err != nil {
+		return err
+	}
+	if skipped > 0 {
+		_, _ = fmt.Fprintf(out, "skipped %d wildcard rule entries (not observations)\n", skipped)
+	}
+
+	keys := make([]string, 0, len(imported))
+	for k := range imported {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "REPORT\tSUBJECT\tIMPORTED\tRULES")
+	var written int
+	for _, k := range keys {
+		subjectRules := imported[k]
+		if opts.subject != "" && subjectRules.subject.Name != opts.subject {
+			continue
+		}
+		key := audiciasource.ReportKey(opts.namespace, subjectRules.subject)
+		total, err := mergeImport(ctx, c, key, subjectRules.subject, subjectRules.rules, opts.DryRun)
+		if err != nil {
+			_ = tw.Flush()
+			return fmt.Errorf("importing %s %s: %w", subjectRules.subject.Kind, subjectRules.subject.Name, err)
+		}
+		written++
+		_, _ = fmt.Fprintf(tw, "%s/%s\t%s %s\t%d\t%d\n", key.Namespace, key.Name,
+			subjectRules.subject.Kind, subjectRules.subject.Name, len(subjectRules.rules), total)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	suffix := ""
+	if opts.DryRun {
+		suffix = " (dry run)"
+	}
+	_, _ = fmt.Fprintf(out, "imported %d reports%s\n", written, suffix)
+	return nil
+}
+
+// subjectImport holds the rules imported for one subject.
+type subjectImport struct {
+	subject audiciav1alpha1.Subject
+	rules   []audiciav1alpha1.ObservedRule
+}
+
+// parseAudit2RBAC reads the YAML stream and returns the atomic observed rules
+// per subject, keyed by kind/namespace/name, and the number of wildcard entries
+// skipped. Rules are scoped like the binding that grants them: a RoleBinding
+// to its namespace, a ClusterRoleBinding cluster-wide.
+func parseAudit2RBAC(in io.Reader, fallback *audiciav1alpha1.Subject, observedAt metav1.Time) (map[string]*subjectImport, int, error) {
+	roles := make(map[string]*importedRole)
+	var bindings []rbacv1.RoleBinding
+
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(in))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("reading input: %w", err)
+		}
+		var meta metav1.TypeMeta
+		if err := yaml.Unmarshal(doc, &meta); err != nil {
+			return nil, 0, fmt.Errorf("parsing input: %w", err)
+		}
+		switch meta.Kind {
+		case "Role", "ClusterRole":
+			var role rbacv1.ClusterRole // Roles and ClusterRoles share their fields.
+			if err := yaml.Unmarshal(doc, &role); err != nil {
+				return nil, 0, fmt.Errorf("parsing %s: %w", meta.Kind, err)
+			}
+			roles[roleKey(meta.Kind, role.Namespace, role.Name)] = &importedRole{namespace: role.Namespace, rules: role.Rules}
+		case "RoleBinding", "ClusterRoleBinding":
+			var binding rbacv1.RoleBinding
+			if err := yaml.Unmarshal(doc, &binding); err != nil {
+				return nil, 0, fmt.Errorf("parsing %s: %w", meta.Kind, err)
+			}
+			if meta.Kind == "ClusterRoleBinding" {
+				binding.Namespace = ""
+			}
+			bindings = append(bindings, binding)
+		}
+	}
+
+	result := make(map[string]*subjectImport)
+	var skipped int
+	add := func(subject audiciav1alpha1.Subject, namespace string, rules []rbacv1.PolicyRule) {
+		k := string(subject.Kind) + "/" + subject.Namespace + "/" + subject.Name
+		if result[k] == nil {
+			result[k] = &subjectImport{subject: subject}
+		}
+		atoms, wildcards := atomicRules(rules, namespace, observedAt)
+		result[k].rules = append(result[k].rules, atoms...)
+		skipped += wildcards
+	}
+
+	for _, b := range bindings {
+		roleNamespace := ""
+		if b.RoleRef.Kind == "Role" {
+			roleNamespace = b.Namespace
+		}
+		role, ok := roles[roleKey(b.RoleRef.Kind, roleNamespace, b.RoleRef.Name)]
+		if !ok {
+			continue
+		}
+		role.bound = true
+		for _, s := range b.Subjects {
+			subject, ok := importSubject(s)
+			if !ok {
+				continue
+			}
+			add(subject, b.Namespace, role.rules)
+		}
+	}
+	if fallback != nil {
+		for _, role := range roles {
+			if !role.bound {
+				add(*fallback, role.namespace, role.rules)
+			}
+		}
+	}
+	return result, skipped, nil
+}
+
+func roleKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// importSubject converts a binding subject into an Audicia subject.
+func importSubject(s rbacv1.Subject) (audiciav1alpha1.Subject, bool) {
+	switch s.Kind {
+	case rbacv1.ServiceAccountKind:
+		return audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: s.Name, Namespace: s.Namespace}, s.Name != ""
+	case rbacv1.UserKind:
+		return audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: s.Name}, s.Name != ""
+	case rbacv1.GroupKind:
+		return audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindGroup, Name: s.Name}, s.Name != ""
+	}
+	return audiciav1alpha1.Subject{}, false
+}
+
+// ParseImportSubject converts audit2rbac's --user or --serviceaccount value
+// into a subject. Exactly one of user and serviceAccount may be set; both
+// empty returns nil.
+func ParseImportSubject(user, serviceAccount string) (*audiciav1alpha1.Subject, error) {
+	switch {
+	case user != "" && serviceAccount != "":
+		return nil, fmt.Errorf("-user and -serviceaccount are mutually exclusive")
+	case serviceAccount != "":
+		ns, name, ok := strings.Cut(serviceAccount, ":")
+		if !ok || ns == "" || name == "" {
+			return nil, fmt.Errorf("-serviceaccount must be namespace:name, got %q", serviceAccount)
+		}
+		return &audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Namespace: ns, Name: name}, nil
+	case user != "":
+		subject, ok := normalizer.NormalizeSubject(user, false)
+		if !ok {
+			return nil, fmt.Errorf("invalid -user %q", user)
+		}
+		return &subject, nil
+	}
+	return nil, nil
+}
+
+// atomicRules splits policy rules into one observed rule per API group,
+// resource (or non-resource URL) and verb, the shape the aggregator produces.
+// Entries with a "*" are skipped and counted: they grant, but never record,
+// access.
+func atomicRules(rules []rbacv1.PolicyRule, namespace string, observedAt metav1.Time) ([]audiciav1alpha1.ObservedRule, int) {
+	var out []audiciav1alpha1.ObservedRule
+	var skipped int
+	rule := func(verb string) audiciav1alpha1.ObservedRule {
+		return audiciav1alpha1.ObservedRule{
+			Verbs:     []string{verb},
+			FirstSeen: observedAt,
+			LastSeen:  observedAt,
+			Count:     1,
+		}
+	}
+	for _, pr := range rules {
+		for _, verb := range pr.Verbs {
+			if verb == rbacv1.VerbAll {
+				skipped++
+				continue
+			}
+			for _, url := range pr.NonResourceURLs {
+				if strings.Contains(url, "*") {
+					skipped++
+					continue
+				}
+				r := rule(verb)
+				r.APIGroups, r.Resources, r.NonResourceURLs = []string{}, []string{}, []string{url}
+				out = append(out, r)
+			}
+			for _, group := range pr.APIGroups {
+				for _, resource := range pr.Resources {
+					if group == rbacv1.APIGroupAll || resource == rbacv1.ResourceAll {
+						skipped++
+						continue
+					}
+					r := rule(verb)
+					r.APIGroups, r.Resources = []string{group}, []string{resource}
+					r.Namespace = namespace
+					r.ResourceNames = slices.Clone(pr.ResourceNames)
+					out = append(out, r)
+				}
+			}
+		}
+	}
+	return out, skipped
+}
+
+// mergeImport merges rules into the subject's report, creating it if needed,
+// and returns the report's resulting rule count.
+func mergeImport(ctx context.Context, c client.Client, key client.ObjectKey, subject audiciav1alpha1.Subject, rules []audiciav1alpha1.ObservedRule, dryRun bool) (int, error) {
+	var total int
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		report := &audiciav1alpha1.AudiciaReport{}
+		err := c.Get(ctx, key, report)
+		switch {
+		case apierrors.IsNotFound(err):
+			report = &audiciav1alpha1.AudiciaReport{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec:       audiciav1alpha1.AudiciaReportSpec{Subject: subject},
+			}
+		case err != nil:
+			return err
+		}
+
+		agg := aggregator.New()
+		agg.Seed(report.Status.ObservedRules)
+		agg.Seed(rules)
+		merged := agg.Rules()
+		total = len(merged)
+		if dryRun {
+			return nil
+		}
+
+		if report.Annotations == nil {
+			report.Annotations = map[string]string{}
+		}
+		report.Annotations[audiciasource.ImportedFromAnnotation] = audit2rbacSource
+		if report.ResourceVersion == "" {
+			if err := c.Create(ctx, report); err != nil {
+				return err
+			}
+		} else if err := c.Update(ctx, report); err != nil {
+			return err
+		}
+		report.Status.ObservedRules = merged
+		return c.Status().Update(ctx, report)
+	})
+	return total, err
+}
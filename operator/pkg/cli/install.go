@@ -0,0 +1,298 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/schema"
+)
+
+// Platform is the Kubernetes distribution `audicia install` tailors the
+// default AudiciaSource to.
+type Platform string
+
+const (
+	// PlatformAuto detects the platform from the cluster's nodes.
+	PlatformAuto Platform = "auto"
+	// PlatformKubeadm is a self-managed cluster with the kubeadm audit log
+	// path on visible control plane nodes.
+	PlatformKubeadm Platform = "kubeadm"
+	// PlatformOpenShift writes the kube-apiserver audit log to its own path
+	// on the control plane nodes.
+	PlatformOpenShift Platform = "openshift"
+	// PlatformEKS, PlatformGKE and PlatformAKS are managed control planes
+	// whose audit logs are only available from the cloud provider.
+	PlatformEKS Platform = "eks"
+	PlatformGKE Platform = "gke"
+	PlatformAKS Platform = "aks"
+	// PlatformUnknown is a cluster none of the above was detected on.
+	PlatformUnknown Platform = "unknown"
+)
+
+// auditLogPaths are the kube-apiserver audit log paths of the platforms
+// whose control plane nodes the operator can read.
+var auditLogPaths = map[Platform]string{
+	PlatformKubeadm:   "/var/log/kubernetes/audit/audit.log",
+	PlatformOpenShift: "/var/log/kube-apiserver/audit.log",
+}
+
+// managedSetupGuides are the setup guides of managed platforms, whose
+// sources need cloud credentials the installer cannot create.
+var managedSetupGuides = map[Platform]string{
+	PlatformEKS: "docs/guides/eks-setup.md",
+	PlatformGKE: "docs/guides/gke-setup.md",
+	PlatformAKS: "docs/guides/aks-setup.md",
+}
+
+// platformLabelPrefixes identify nodes of a platform by a label key prefix.
+var platformLabelPrefixes = []struct {
+	prefix   string
+	platform Platform
+}{
+	{"node.openshift.io/", PlatformOpenShift},
+	{"eks.amazonaws.com/", PlatformEKS},
+	{"cloud.google.com/gke-", PlatformGKE},
+	{"kubernetes.azure.com/", PlatformAKS},
+}
+
+// defaultInstallNamespace is the namespace `audicia install` uses without
+// -n, the same as the Helm chart's documented release namespace.
+const defaultInstallNamespace = "audicia-system"
+
+// controlPlaneLabel marks control plane nodes on kubeadm clusters.
+const controlPlaneLabel = "node-role.kubernetes.io/control-plane"
+
+// InstallOptions configures `audicia install`.
+type InstallOptions struct {
+	// Namespace receives the operator's ServiceAccount and the default
+	// AudiciaSource. It is created if missing.
+	Namespace string
+
+	// Name names the ServiceAccount, ClusterRole and ClusterRoleBinding.
+	Name string
+
+	// SourceName names the default AudiciaSource.
+	SourceName string
+
+	// Platform selects the default AudiciaSource; PlatformAuto detects it.
+	Platform Platform
+
+	// DryRun submits every object with server-side dry run.
+	DryRun bool
+}
+
+// Install bootstraps Audicia without the Helm chart: it server-side applies
+// the CRDs, a Namespace, a ServiceAccount bound to the minimal ClusterRole
+// the operator needs and to the Role of its autodiscovery ConfigMap, and a
+// default AudiciaSource reading the audit log path
+// of the detected platform. On managed platforms, whose audit logs are not
+// on any node, it skips the source and points to the platform's setup guide.
+func Install(ctx context.Context, c client.Client, opts InstallOptions, out io.Writer) error {
+	platform := opts.Platform
+	if platform == PlatformAuto || platform == "" {
+		var err error
+		if platform, err = DetectPlatform(ctx, c); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "detected platform: %s\n", platform)
+	}
+
+	crds, err := schema.CRDs()
+	if err != nil {
+		return fmt.Errorf("reading CRDs: %w", err)
+	}
+	for _, data := range crds {
+		u := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(data, &u.Object); err != nil {
+			return fmt.Errorf("parsing CRD: %w", err)
+		}
+		if err := applyUnstructured(ctx, c, u, opts.DryRun, out); err != nil {
+			return err
+		}
+	}
+
+	objs := installObjects(opts)
+	if path, ok := auditLogPaths[platform]; ok {
+		objs = append(objs, defaultSource(opts, path))
+	}
+	for _, obj := range objs {
+		if err := applyTyped(ctx, c, obj, opts.DryRun, out); err != nil {
+			return err
+		}
+	}
+
+	switch guide, managed := managedSetupGuides[platform]; {
+	case managed:
+		_, _ = fmt.Fprintf(out, "no AudiciaSource created: %s audit logs are only available from the cloud provider; see %s\n", platform, guide)
+	case auditLogPaths[platform] == "":
+		_, _ = fmt.Fprintln(out, "no AudiciaSource created: platform not recognized; rerun with -platform or create a source (see docs/examples)")
+	default:
+		_, _ = fmt.Fprintf(out, "run the operator as ServiceAccount %s/%s on a control plane node with %s mounted\n",
+			opts.Namespace, opts.Name, auditLogPaths[platform])
+	}
+	return nil
+}
+
+// DetectPlatform infers the platform from node labels: distribution and
+// managed node labels first, then kubeadm's control plane label.
+func DetectPlatform(ctx context.Context, c client.Reader) (Platform, error) {
+	var nodes corev1.NodeList
+	if err := c.List(ctx, &nodes); err != nil {
+		return "", fmt.Errorf("listing nodes: %w", err)
+	}
+	controlPlane := false
+	for _, n := range nodes.Items {
+		for key := range n.Labels {
+			for _, p := range platformLabelPrefixes {
+				if strings.HasPrefix(key, p.prefix) {
+					return p.platform, nil
+				}
+			}
+			if key == controlPlaneLabel {
+				controlPlane = true
+			}
+		}
+	}
+	if controlPlane {
+		return PlatformKubeadm, nil
+	}
+	return PlatformUnknown, nil
+}
+
+// installObjects returns the Namespace, ServiceAccount and RBAC of the
+// operator.
+func installObjects(opts InstallOptions) []client.Object {
+	autodiscovery := autodiscoveryConfigMap(opts)
+	return []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: opts.Namespace}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: opts.Name, Namespace: opts.Namespace}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: opts.Name}, Rules: operatorRules()},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: opts.Name},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: opts.Name},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: opts.Name, Namespace: opts.Namespace}},
+		},
+		&rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: autodiscovery, Namespace: opts.Namespace},
+			Rules:      autodiscoveryRules(autodiscovery),
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: autodiscovery, Namespace: opts.Namespace},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: autodiscovery},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: opts.Name, Namespace: opts.Namespace}},
+		},
+	}
+}
+
+// autodiscoveryConfigMap names the ConfigMap autodiscovery writes, as the
+// Helm chart does: AUTODISCOVERY_CONFIGMAP=<name>-autodiscovery.
+func autodiscoveryConfigMap(opts InstallOptions) string {
+	return opts.Name + "-autodiscovery"
+}
+
+// autodiscoveryRules are the rules of the Helm chart's autodiscovery Role,
+// which let the operator write the ConfigMap of that name.
+func autodiscoveryRules(name string) []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"create"}},
+		{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{name}, Verbs: []string{"get", "patch"}},
+	}
+}
+
+// operatorRules are the rules of the Helm chart's ClusterRole without
+// optional features (policy plan application).
+func operatorRules() []rbacv1.PolicyRule {
+	read := []string{"get", "list", "watch"}
+	crud := []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+	status := []string{"get", "update", "patch"}
+	return []rbacv1.PolicyRule{
+		{APIGroups: []string{audiciav1alpha1.Group}, Resources: []string{"audiciasources", "audiciapolicyplans"}, Verbs: read},
+		{APIGroups: []string{audiciav1alpha1.Group}, Resources: []string{"audiciareports", "audiciapolicies"}, Verbs: crud},
+		{
+			APIGroups: []string{audiciav1alpha1.Group},
+			Resources: []string{"audiciasources/status", "audiciareports/status", "audiciapolicies/status", "audiciapolicyplans/status"},
+			Verbs:     status,
+		},
+		{
+			APIGroups: []string{"apiextensions.k8s.io"},
+			Resources: []string{"customresourcedefinitions"},
+			ResourceNames: []string{
+				"audiciasources." + audiciav1alpha1.Group,
+				"audiciareports." + audiciav1alpha1.Group,
+				"audiciapolicies." + audiciav1alpha1.Group,
+				"audiciapolicyplans." + audiciav1alpha1.Group,
+			},
+			Verbs: []string{"get"},
+		},
+		{APIGroups: []string{rbacv1.GroupName}, Resources: []string{"clusterroles", "clusterrolebindings", "roles", "rolebindings"}, Verbs: read},
+		{APIGroups: []string{""}, Resources: []string{"namespaces", "serviceaccounts"}, Verbs: read},
+		{APIGroups: []string{"authentication.k8s.io"}, Resources: []string{"tokenreviews"}, Verbs: []string{"create"}},
+		{APIGroups: []string{"authorization.k8s.io"}, Resources: []string{"subjectaccessreviews"}, Verbs: []string{"create"}},
+		{APIGroups: []string{""}, Resources: []string{"events"}, Verbs: []string{"create", "patch"}},
+		{APIGroups: []string{"coordination.k8s.io"}, Resources: []string{"leases"}, Verbs: crud},
+	}
+}
+
+// defaultSource returns a file-based AudiciaSource reading path, with the
+// filters of the file mode example.
+func defaultSource(opts InstallOptions, path string) *audiciav1alpha1.AudiciaSource {
+	return &audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: opts.SourceName, Namespace: opts.Namespace},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			SourceType: audiciav1alpha1.SourceTypeK8sAuditLog,
+			Location:   &audiciav1alpha1.FileLocation{Path: path},
+			Filters: []audiciav1alpha1.Filter{
+				{Action: audiciav1alpha1.FilterActionDeny, UserPattern: "^system:node:.*"},
+				{Action: audiciav1alpha1.FilterActionDeny, UserPattern: "^system:kube-.*"},
+				{Action: audiciav1alpha1.FilterActionDeny, UserPattern: "^system:apiserver$"},
+			},
+		},
+	}
+}
+
+// applyTyped server-side applies a typed object.
+func applyTyped(ctx context.Context, c client.Client, obj client.Object, dryRun bool, out io.Writer) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return fmt.Errorf("converting %s %s: %w", gvk.Kind, obj.GetName(), err)
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "status")
+	return applyUnstructured(ctx, c, u, dryRun, out)
+}
+
+// applyUnstructured server-side applies u, taking ownership of conflicting
+// fields.
+func applyUnstructured(ctx context.Context, c client.Client, u *unstructured.Unstructured, dryRun bool, out io.Writer) error {
+	opts := []client.ApplyOption{client.FieldOwner(fieldOwner), client.ForceOwnership}
+	if dryRun {
+		opts = append(opts, client.DryRunAll)
+	}
+	if err := c.Apply(ctx, client.ApplyConfigurationFromUnstructured(u), opts...); err != nil {
+		return fmt.Errorf("applying %s %s: %w", u.GetKind(), u.GetName(), err)
+	}
+	suffix := ""
+	if dryRun {
+		suffix = " (dry run)"
+	}
+	_, _ = fmt.Fprintf(out, "%s/%s applied%s\n", u.GetKind(), qualifiedName(u), suffix)
+	return nil
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// LimitsOptions configures `audicia limits`.
+type LimitsOptions struct {
+	selector
+
+	// Limits are the proposed spec.limits values to preview.
+	Limits audiciav1alpha1.LimitsConfig
+
+	// Now is the time retention is evaluated at. Zero means the current time.
+	Now time.Time
+}
+
+// PreviewLimits reports how many observed rules of each matching
+// AudiciaReport the proposed limits would drop at the next compaction. It
+// changes nothing. Reports are already compacted with the limits in force,
+// so only changes that tighten the limits show drops.
+func PreviewLimits(ctx context.Context, c client.Reader, opts LimitsOptions, out io.Writer) error {
+	reports, err := listReports(ctx, c, opts.selector)
+	if err != nil {
+		return err
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "REPORT\tRULES\tEXPIRED\tTRUNCATED\tKEPT")
+	var total, dropped, affected int
+	for _, r := range reports {
+		rules := r.Status.ObservedRules
+		result := aggregator.Compact(rules, opts.Limits, now)
+		total += len(rules)
+		if result.Dropped() == 0 {
+			continue
+		}
+		dropped += result.Dropped()
+		affected++
+		_, _ = fmt.Fprintf(tw, "%s/%s\t%d\t%d\t%d\t%d\n",
+			r.Namespace, r.Name, len(rules), result.Expired, result.Truncated, len(result.Rules))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "would drop %d of %d rules in %d of %d reports\n", dropped, total, affected, len(reports))
+	return nil
+}
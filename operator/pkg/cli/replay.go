@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/diff"
+	"github.com/felixnotka/audicia/operator/pkg/rbac"
+)
+
+// ReplayOptions configures `audicia replay`.
+type ReplayOptions struct {
+	selector
+
+	// MinCoverage is the percentage of observed rules the candidate must
+	// allow for every report; below it, the replay fails.
+	MinCoverage int
+}
+
+// ReplayResult is the outcome of replaying one report against a candidate.
+type ReplayResult struct {
+	Report  string
+	Subject audiciav1alpha1.Subject
+
+	// Observed and Covered are the numbers of observed rules and of those
+	// the candidate allows.
+	Observed, Covered int
+
+	// Coverage is Covered as a percentage of Observed, 100 without
+	// observed rules.
+	Coverage int
+
+	// Uncovered are the observed rules the candidate denies.
+	Uncovered []string
+
+	// Unused are the candidate rules no observed rule exercised.
+	Unused []string
+}
+
+// ReplayReport replays the observed rules of report against the candidate
+// rules, using the same matching as the compliance score. candidate must not
+// be empty.
+func ReplayReport(report audiciav1alpha1.AudiciaReport, candidate []rbac.ScopedRule) ReplayResult {
+	res := ReplayResult{
+		Report:   report.Namespace + "/" + report.Name,
+		Subject:  report.Spec.Subject,
+		Observed: len(report.Status.ObservedRules),
+		Covered:  len(report.Status.ObservedRules),
+		Coverage: 100,
+	}
+	// candidate is never empty, so Evaluate always returns a report.
+	compliance := diff.Evaluate(report.Status.ObservedRules, candidate)
+	res.Covered -= int(compliance.UncoveredCount)
+	if res.Observed > 0 {
+		res.Coverage = res.Covered * 100 / res.Observed
+	}
+	for _, r := range compliance.UncoveredRules {
+		res.Uncovered = append(res.Uncovered, complianceRuleString(r))
+	}
+	for _, r := range compliance.ExcessRules {
+		res.Unused = append(res.Unused, complianceRuleString(r))
+	}
+	return res
+}
+
+// ReplayCandidate replays the observed rules of the matching reports
+// against the Roles and ClusterRoles of a candidate manifest, and prints how
+// much of the recorded traffic the candidate would still allow. It returns
+// an error if any report's coverage is below opts.MinCoverage, so a
+// hand-tightened role can be tested before it replaces the one in use.
+func ReplayCandidate(ctx context.Context, c client.Reader, opts ReplayOptions, manifest io.Reader, out io.Writer) error {
+	candidate, err := parseCandidate(manifest)
+	if err != nil {
+		return err
+	}
+	if len(candidate) == 0 {
+		return fmt.Errorf("the candidate manifest contains no Role or ClusterRole rules")
+	}
+	reports, err := listReports(ctx, c, opts.selector)
+	if err != nil {
+		return err
+	}
+	if len(reports) == 0 {
+		return fmt.Errorf("no AudiciaReports match")
+	}
+
+	results := make([]ReplayResult, len(reports))
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "REPORT\tSUBJECT\tOBSERVED\tCOVERED\tCOVERAGE\tUNUSED")
+	for i, r := range reports {
+		res := ReplayReport(r, candidate)
+		results[i] = res
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d%%\t%d\n", res.Report, subjectString(res.Subject), res.Observed, res.Covered, res.Coverage, len(res.Unused))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, res := range results {
+		for _, rule := range res.Uncovered {
+			_, _ = fmt.Fprintf(out, "\n%s: denied %s", res.Report, rule)
+		}
+		for _, rule := range res.Unused {
+			_, _ = fmt.Fprintf(out, "\n%s: unused %s", res.Report, rule)
+		}
+		if res.Coverage < opts.MinCoverage {
+			failed++
+		}
+	}
+	_, _ = fmt.Fprintf(out, "\n%d of %d reports below %d%% coverage\n", failed, len(results), opts.MinCoverage)
+	if failed > 0 {
+		return fmt.Errorf("the candidate covers less than %d%% of the observed rules of %d reports", opts.MinCoverage, failed)
+	}
+	return nil
+}
+
+// parseCandidate returns the rules of the Roles and ClusterRoles in a YAML
+// stream, scoped like Kubernetes would grant them: a Role's rules to its
+// namespace, a ClusterRole's to the namespaces of the RoleBindings in the
+// stream that refer to it, and cluster-wide if a ClusterRoleBinding refers
+// to it or no binding does.
+func parseCandidate(in io.Reader) ([]rbac.ScopedRule, error) {
+	type candidateRole struct {
+		kind, namespace, name string
+		rules                 []rbacv1.PolicyRule
+	}
+	var roles []candidateRole
+	bound := make(map[string][]string) // ClusterRole name → binding namespaces, "" for cluster-wide
+
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(in))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading candidate: %w", err)
+		}
+		var meta metav1.TypeMeta
+		if err := yaml.Unmarshal(doc, &meta); err != nil {
+			return nil, fmt.Errorf("parsing candidate: %w", err)
+		}
+		switch meta.Kind {
+		case "Role", "ClusterRole":
+			var role rbacv1.ClusterRole // Roles and ClusterRoles share their fields.
+			if err := yaml.Unmarshal(doc, &role); err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", meta.Kind, err)
+			}
+			if meta.Kind == "Role" && role.Namespace == "" {
+				return nil, fmt.Errorf("role %s has no namespace", role.Name)
+			}
+			roles = append(roles, candidateRole{kind: meta.Kind, namespace: role.Namespace, name: role.Name, rules: role.Rules})
+		case "RoleBinding", "ClusterRoleBinding":
+			var binding rbacv1.RoleBinding
+			if err := yaml.Unmarshal(doc, &binding); err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", meta.Kind, err)
+			}
+			if binding.RoleRef.Kind != "ClusterRole" {
+				continue
+			}
+			namespace := binding.Namespace
+			if meta.Kind == "ClusterRoleBinding" {
+				namespace = ""
+			}
+			bound[binding.RoleRef.Name] = append(bound[binding.RoleRef.Name], namespace)
+		}
+	}
+
+	var rules []rbac.ScopedRule
+	for _, role := range roles {
+		namespaces := []string{role.namespace}
+		if role.kind == "ClusterRole" {
+			namespaces = bound[role.name]
+			if len(namespaces) == 0 {
+				namespaces = []string{""}
+			}
+		}
+		for _, ns := range namespaces {
+			for _, rule := range role.rules {
+				rules = append(rules, rbac.ScopedRule{PolicyRule: rule, Namespace: ns})
+			}
+		}
+	}
+	return rules, nil
+}
+
+// complianceRuleString formats r as "verbs resources.group in namespace" or
+// "verbs urls".
+func complianceRuleString(r audiciav1alpha1.ComplianceRule) string {
+	verbs := strings.Join(r.Verbs, ",")
+	if len(r.NonResourceURLs) > 0 {
+		return verbs + " " + strings.Join(r.NonResourceURLs, ",")
+	}
+	resources := make([]string, 0, len(r.Resources)*max(len(r.APIGroups), 1))
+	for _, group := range r.APIGroups {
+		for _, res := range r.Resources {
+			if group != "" {
+				res += "." + group
+			}
+			resources = append(resources, res)
+		}
+	}
+	s := verbs + " " + strings.Join(resources, ",")
+	if r.Namespace != "" {
+		s += " in " + r.Namespace
+	}
+	return s
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// rulesHeader is the CSV header written by `audicia rules`.
+var rulesHeader = []string{
+	"subject", "kind", "ns", "apiGroup", "resource", "verbs", "count", "firstSeen", "lastSeen", "covered",
+}
+
+// RulesOptions configures `audicia rules`.
+type RulesOptions struct {
+	selector
+
+	// Output is the CSV file to write. "-" or empty writes to the writer.
+	Output string
+}
+
+// ExportRules flattens the observed rules and compliance findings of matching
+// AudiciaReports into CSV, one row per rule. Excess grants (granted but never
+// observed) are included as rows with a count of 0.
+func ExportRules(ctx context.Context, c client.Reader, opts RulesOptions, out io.Writer) error {
+	reports, err := listReports(ctx, c, opts.selector)
+	if err != nil {
+		return err
+	}
+
+	w := out
+	if opts.Output != "" && opts.Output != "-" {
+		f, err := os.Create(opts.Output)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", opts.Output, err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(rulesHeader); err != nil {
+		return err
+	}
+	rows := 0
+	for _, r := range reports {
+		for _, row := range reportRows(r) {
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+			rows++
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("writing CSV: %w", err)
+	}
+	if w != out {
+		_, _ = fmt.Fprintf(out, "wrote %s (%d rules from %d reports)\n", opts.Output, rows, len(reports))
+	}
+	return nil
+}
+
+// reportRows flattens one report. The covered column is "true" or "false"
+// once compliance has been evaluated and empty before that.
+func reportRows(r audiciav1alpha1.AudiciaReport) [][]string {
+	subject := r.Spec.Subject
+	name := subject.Name
+	if subject.Kind == audiciav1alpha1.SubjectKindServiceAccount && subject.Namespace != "" {
+		name = subject.Namespace + "/" + subject.Name
+	}
+	compliance := r.Status.Compliance
+
+	var rows [][]string
+	for _, o := range r.Status.ObservedRules {
+		covered := ""
+		if compliance != nil {
+			covered = strconv.FormatBool(!isUncovered(o, compliance.UncoveredRules))
+		}
+		rows = append(rows, ruleRow(name, subject.Kind, o.Namespace, o.APIGroups, o.Resources, o.NonResourceURLs, o.Verbs,
+			strconv.FormatInt(o.Count, 10), formatTime(o.FirstSeen), formatTime(o.LastSeen), covered))
+	}
+	if compliance != nil {
+		for _, e := range compliance.ExcessRules {
+			rows = append(rows, ruleRow(name, subject.Kind, e.Namespace, e.APIGroups, e.Resources, e.NonResourceURLs, e.Verbs,
+				"0", "", "", "true"))
+		}
+	}
+	return rows
+}
+
+func ruleRow(subject string, kind audiciav1alpha1.SubjectKind, ns string, apiGroups, resources, urls, verbs []string, count, firstSeen, lastSeen, covered string) []string {
+	if len(urls) > 0 {
+		resources = urls
+	}
+	return []string{
+		subject, string(kind), ns,
+		strings.Join(apiGroups, " "), strings.Join(resources, " "), strings.Join(verbs, " "),
+		count, firstSeen, lastSeen, covered,
+	}
+}
+
+// isUncovered reports whether the compliance evaluation listed the observed
+// rule as not covered by any RBAC grant.
+func isUncovered(o audiciav1alpha1.ObservedRule, uncovered []audiciav1alpha1.ComplianceRule) bool {
+	for _, u := range uncovered {
+		if u.Namespace == o.Namespace &&
+			sameStrings(u.APIGroups, o.APIGroups) &&
+			sameStrings(u.Resources, o.Resources) &&
+			sameStrings(u.Verbs, o.Verbs) &&
+			sameStrings(u.NonResourceURLs, o.NonResourceURLs) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameStrings compares two lists, treating nil and empty as equal.
+func sameStrings(a, b []string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func formatTime(t metav1.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// listReports returns matching AudiciaReports sorted by namespace and name.
+// The state filter does not apply to reports.
+func listReports(ctx context.Context, c client.Reader, sel selector) ([]audiciav1alpha1.AudiciaReport, error) {
+	var list audiciav1alpha1.AudiciaReportList
+	var opts []client.ListOption
+	if sel.namespace != "" {
+		opts = append(opts, client.InNamespace(sel.namespace))
+	}
+	if sel.labels != nil {
+		opts = append(opts, client.MatchingLabelsSelector{Selector: sel.labels})
+	}
+	if err := c.List(ctx, &list, opts...); err != nil {
+		return nil, fmt.Errorf("listing AudiciaReports: %w", err)
+	}
+
+	var out []audiciav1alpha1.AudiciaReport
+	for _, r := range list.Items {
+		if sel.subject != "" && r.Spec.Subject.Name != sel.subject {
+			continue
+		}
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/felixnotka/audicia/operator/pkg/schema"
+)
+
+// PrintSchema writes the OpenAPI v3 schema of an Audicia resource, named by
+// kind, plural or short name, as indented JSON. Without a resource it lists
+// the published schemas. No cluster access is needed.
+func PrintSchema(resource, version string, out io.Writer) error {
+	if resource == "" {
+		all, err := schema.All()
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "KIND\tVERSION\tPATH")
+		for _, s := range all {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Kind, s.Version, schema.HandlerPath+s.Path())
+		}
+		return tw.Flush()
+	}
+
+	s, err := schema.Lookup(resource, version)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, s.OpenAPIV3, "", "  "); err != nil {
+		return fmt.Errorf("formatting schema of %s: %w", s.Kind, err)
+	}
+	buf.WriteByte('\n')
+	_, err = buf.WriteTo(out)
+	return err
+}
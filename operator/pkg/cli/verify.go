@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/felixnotka/audicia/operator/pkg/integrity"
+)
+
+// VerifyOptions configures `audicia verify`.
+type VerifyOptions struct {
+	selector
+
+	// Anchor is a chain hash recorded at an earlier review. When set, it
+	// must still be in each report's chain.
+	Anchor string
+}
+
+// VerifyReports checks the integrity chain of matching AudiciaReports and
+// fails if any report's observed rules were edited outside the operator.
+// Reports without a chain are listed but do not fail the check.
+func VerifyReports(ctx context.Context, c client.Reader, opts VerifyOptions, out io.Writer) error {
+	reports, err := listReports(ctx, c, opts.selector)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "REPORT\tENTRIES\tHEAD\tRESULT")
+	var failed, unchained int
+	for _, r := range reports {
+		head, entries := "-", 0
+		if chain := r.Status.Integrity; chain != nil && len(chain.Entries) > 0 {
+			entries = len(chain.Entries)
+			head = chain.Entries[entries-1].Hash
+		}
+		result := "ok"
+		switch err := integrity.Verify(r.Status.ObservedRules, r.Status.Integrity, opts.Anchor); {
+		case errors.Is(err, integrity.ErrNoChain):
+			result = "no chain"
+			unchained++
+		case err != nil:
+			result = "FAILED: " + err.Error()
+			failed++
+		}
+		_, _ = fmt.Fprintf(tw, "%s/%s\t%d\t%s\t%s\n", r.Namespace, r.Name, entries, head, result)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "%d of %d reports verified, %d failed, %d without a chain\n",
+		len(reports)-failed-unchained, len(reports), failed, unchained)
+	if failed > 0 {
+		return fmt.Errorf("integrity check failed for %d reports", failed)
+	}
+	return nil
+}
@@ -0,0 +1,428 @@
+// Package audiciapolicyplan implements the controller that applies approved
+// AudiciaPolicyPlans and tracks the applied RBAC objects for drift.
+package audiciapolicyplan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+const (
+	// fieldOwner is the server-side apply field manager for plan objects.
+	fieldOwner = "audicia-policy-plan"
+
+	// driftCheckInterval is how often applied objects are compared against
+	// their manifests.
+	driftCheckInterval = 5 * time.Minute
+
+	// maxHistory bounds status.history.
+	maxHistory = 20
+
+	// PlanAnnotation and RevisionAnnotation are stamped onto applied objects
+	// so they can be traced back to the plan that wrote them.
+	PlanAnnotation     = "audicia.io/plan"
+	RevisionAnnotation = "audicia.io/plan-revision"
+)
+
+// allowedKinds are the only kinds a plan will apply. AudiciaPolicy manifests
+// are plain strings, so anything else is rejected rather than trusted.
+var allowedKinds = map[string]bool{
+	"Role":               true,
+	"ClusterRole":        true,
+	"RoleBinding":        true,
+	"ClusterRoleBinding": true,
+}
+
+// errInvalidManifest marks manifests that cannot be parsed or are not RBAC objects.
+var errInvalidManifest = errors.New("invalid manifest")
+
+// Reconciler reconciles AudiciaPolicyPlan objects.
+type Reconciler struct {
+	client.Client
+	Recorder events.EventRecorder
+}
+
+// SetupWithManager registers the AudiciaPolicyPlan controller with the manager.
+func SetupWithManager(mgr ctrl.Manager, maxConcurrent int) error {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	r := &Reconciler{
+		Client:   mgr.GetClient(),
+		Recorder: mgr.GetEventRecorder("audicia-policy-plan"),
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&audiciav1alpha1.AudiciaPolicyPlan{}).
+		Watches(&audiciav1alpha1.AudiciaPolicy{}, handler.EnqueueRequestsFromMapFunc(r.plansForPolicy)).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrent}).
+		Complete(r)
+}
+
+// plansForPolicy enqueues the plans in a policy's namespace that reference it.
+func (r *Reconciler) plansForPolicy(ctx context.Context, obj client.Object) []reconcile.Request {
+	var plans audiciav1alpha1.AudiciaPolicyPlanList
+	if err := r.List(ctx, &plans, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "listing AudiciaPolicyPlans", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var reqs []reconcile.Request
+	for _, p := range plans.Items {
+		if slices.Contains(p.Spec.PolicyRefs, obj.GetName()) {
+			reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&p)})
+		}
+	}
+	return reqs
+}
+
+// desiredObject is one manifest from a referenced policy.
+type desiredObject struct {
+	policy string
+	obj    *unstructured.Unstructured
+}
+
+// Reconcile computes the plan revision, applies it once approved and checks
+// applied objects for drift.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var plan audiciav1alpha1.AudiciaPolicyPlan
+	if err := r.Get(ctx, req.NamespacedName, &plan); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	desired, revision, err := r.loadManifests(ctx, &plan)
+	if err != nil {
+		if !apierrors.IsNotFound(err) && !errors.Is(err, errInvalidManifest) {
+			return ctrl.Result{}, err
+		}
+		// Missing or invalid policies are a user error; the policy watch
+		// requeues the plan once they change.
+		plan.Status.Phase = audiciav1alpha1.PlanPhaseFailed
+		meta.SetStatusCondition(&plan.Status.Conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidPolicies",
+			Message: err.Error(),
+		})
+		plan.Status.ObservedGeneration = plan.Generation
+		return ctrl.Result{}, r.Status().Update(ctx, &plan)
+	}
+	meta.SetStatusCondition(&plan.Status.Conditions, metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionTrue,
+		Reason:  "PoliciesResolved",
+		Message: fmt.Sprintf("Plan covers %d manifests from %d policies.", len(desired), len(plan.Spec.PolicyRefs)),
+	})
+	plan.Status.Revision = revision
+
+	approved := plan.Spec.ApprovedRevision == revision
+	reapply := plan.Status.AppliedRevision != revision || plan.Status.ObservedGeneration != plan.Generation
+	switch {
+	case approved && reapply:
+		if err := r.applyPlan(ctx, &plan, desired); err != nil {
+			plan.Status.Phase = audiciav1alpha1.PlanPhaseFailed
+			meta.SetStatusCondition(&plan.Status.Conditions, metav1.Condition{
+				Type:    "Applied",
+				Status:  metav1.ConditionFalse,
+				Reason:  "ApplyFailed",
+				Message: err.Error(),
+			})
+			r.Recorder.Eventf(&plan, nil, corev1.EventTypeWarning, "ApplyFailed", "Apply",
+				"Failed to apply revision %s: %v", revision, err)
+			plan.Status.ObservedGeneration = plan.Generation
+			if updateErr := r.Status().Update(ctx, &plan); updateErr != nil {
+				logger.Error(updateErr, "failed to record apply failure")
+			}
+			return ctrl.Result{}, err
+		}
+		logger.Info("plan applied", "plan", req.NamespacedName, "revision", revision, "objects", len(plan.Status.Objects))
+	case !approved:
+		msg := fmt.Sprintf("Set spec.approvedRevision to %q to apply this plan.", revision)
+		reason := "AwaitingApproval"
+		if plan.Spec.ApprovedRevision != "" {
+			reason = "RevisionChanged"
+			msg = fmt.Sprintf("Policies changed since revision %q was approved. %s", plan.Spec.ApprovedRevision, msg)
+		}
+		meta.SetStatusCondition(&plan.Status.Conditions, metav1.Condition{
+			Type:    "Approved",
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: msg,
+		})
+	}
+
+	// Drift is measured against the manifests that were applied, which are
+	// only known while the current revision is the applied one.
+	if plan.Status.AppliedRevision == revision {
+		r.checkDrift(ctx, &plan, desired)
+	}
+	plan.Status.Phase = phaseFor(&plan, approved)
+	plan.Status.ObservedGeneration = plan.Generation
+
+	if err := r.Status().Update(ctx, &plan); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: driftCheckInterval}, nil
+}
+
+// loadManifests reads the referenced policies and returns their manifests in
+// order together with a revision hash over their contents.
+func (r *Reconciler) loadManifests(ctx context.Context, plan *audiciav1alpha1.AudiciaPolicyPlan) ([]desiredObject, string, error) {
+	h := sha256.New()
+	var out []desiredObject
+	for _, name := range plan.Spec.PolicyRefs {
+		var policy audiciav1alpha1.AudiciaPolicy
+		if err := r.Get(ctx, types.NamespacedName{Namespace: plan.Namespace, Name: name}, &policy); err != nil {
+			return nil, "", fmt.Errorf("AudiciaPolicy %s: %w", name, err)
+		}
+		_, _ = fmt.Fprintf(h, "policy:%s\n", name)
+		for _, m := range policy.Spec.Manifests {
+			_, _ = fmt.Fprintf(h, "%s\n---\n", m)
+			u := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal([]byte(m), &u.Object); err != nil {
+				return nil, "", fmt.Errorf("AudiciaPolicy %s: %w: %v", name, errInvalidManifest, err)
+			}
+			if len(u.Object) == 0 {
+				continue
+			}
+			if u.GroupVersionKind().Group != rbacv1.GroupName || !allowedKinds[u.GetKind()] {
+				return nil, "", fmt.Errorf("AudiciaPolicy %s: %w: %s %s %q is not an RBAC object", name, errInvalidManifest, u.GetAPIVersion(), u.GetKind(), u.GetName())
+			}
+			out = append(out, desiredObject{policy: name, obj: u})
+		}
+	}
+	return out, hex.EncodeToString(h.Sum(nil))[:12], nil
+}
+
+// applyPlan server-side applies every manifest and records the application.
+func (r *Reconciler) applyPlan(ctx context.Context, plan *audiciav1alpha1.AudiciaPolicyPlan, desired []desiredObject) error {
+	revision := plan.Status.Revision
+	objects := make([]audiciav1alpha1.PlanObject, 0, len(desired))
+	for _, d := range desired {
+		u := d.obj.DeepCopy()
+		annotations := u.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[PlanAnnotation] = plan.Namespace + "/" + plan.Name
+		annotations[RevisionAnnotation] = revision
+		u.SetAnnotations(annotations)
+
+		if err := r.Apply(ctx, client.ApplyConfigurationFromUnstructured(u), client.FieldOwner(fieldOwner), client.ForceOwnership); err != nil {
+			return fmt.Errorf("applying %s %s: %w", u.GetKind(), u.GetName(), err)
+		}
+		objects = append(objects, audiciav1alpha1.PlanObject{
+			Kind:      u.GetKind(),
+			Namespace: u.GetNamespace(),
+			Name:      u.GetName(),
+			Policy:    d.policy,
+		})
+	}
+
+	now := metav1.Now()
+	plan.Status.AppliedRevision = revision
+	plan.Status.Objects = objects
+	plan.Status.History = append(plan.Status.History, audiciav1alpha1.PlanApplication{
+		Revision: revision,
+		Time:     now,
+		Objects:  int32(len(objects)),
+	})
+	if len(plan.Status.History) > maxHistory {
+		plan.Status.History = plan.Status.History[len(plan.Status.History)-maxHistory:]
+	}
+	meta.SetStatusCondition(&plan.Status.Conditions, metav1.Condition{
+		Type:    "Approved",
+		Status:  metav1.ConditionTrue,
+		Reason:  "RevisionApproved",
+		Message: fmt.Sprintf("Revision %q is approved.", revision),
+	})
+	meta.SetStatusCondition(&plan.Status.Conditions, metav1.Condition{
+		Type:    "Applied",
+		Status:  metav1.ConditionTrue,
+		Reason:  "Applied",
+		Message: fmt.Sprintf("Applied %d RBAC objects from revision %q.", len(objects), revision),
+	})
+	r.Recorder.Eventf(plan, nil, corev1.EventTypeNormal, "PlanApplied", "Apply",
+		"Applied revision %s (%d RBAC objects)", revision, len(objects))
+
+	for _, name := range plan.Spec.PolicyRefs {
+		if err := r.markPolicyApplied(ctx, types.NamespacedName{Namespace: plan.Namespace, Name: name}); err != nil {
+			log.FromContext(ctx).Error(err, "failed to mark policy Applied", "policy", name)
+		}
+	}
+	return nil
+}
+
+// markPolicyApplied transitions a policy to the Applied state.
+func (r *Reconciler) markPolicyApplied(ctx context.Context, key types.NamespacedName) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var policy audiciav1alpha1.AudiciaPolicy
+		if err := r.Get(ctx, key, &policy); err != nil {
+			return err
+		}
+		if policy.Status.State == audiciav1alpha1.PolicyStateApplied {
+			return nil
+		}
+		policy.Status.State = audiciav1alpha1.PolicyStateApplied
+		return r.Status().Update(ctx, &policy)
+	})
+}
+
+// checkDrift compares each applied object against its manifest and records
+// the result in the plan status.
+func (r *Reconciler) checkDrift(ctx context.Context, plan *audiciav1alpha1.AudiciaPolicyPlan, desired []desiredObject) {
+	wasDrifted := meta.IsStatusConditionTrue(plan.Status.Conditions, "Drifted")
+
+	var drifted []string
+	for i := range plan.Status.Objects {
+		o := &plan.Status.Objects[i]
+		o.Drifted = false
+		for _, d := range desired {
+			if d.obj.GetKind() != o.Kind || d.obj.GetNamespace() != o.Namespace || d.obj.GetName() != o.Name {
+				continue
+			}
+			match, err := r.matches(ctx, d.obj)
+			if err != nil {
+				log.FromContext(ctx).Error(err, "drift check failed", "kind", o.Kind, "name", o.Name)
+				break
+			}
+			o.Drifted = !match
+			break
+		}
+		if o.Drifted {
+			drifted = append(drifted, o.Kind+"/"+qualifiedName(o.Namespace, o.Name))
+		}
+	}
+
+	now := metav1.Now()
+	plan.Status.LastDriftCheckTime = &now
+	if len(drifted) == 0 {
+		meta.SetStatusCondition(&plan.Status.Conditions, metav1.Condition{
+			Type:    "Drifted",
+			Status:  metav1.ConditionFalse,
+			Reason:  "InSync",
+			Message: "All applied objects match their manifests.",
+		})
+		return
+	}
+	meta.SetStatusCondition(&plan.Status.Conditions, metav1.Condition{
+		Type:    "Drifted",
+		Status:  metav1.ConditionTrue,
+		Reason:  "ObjectsChanged",
+		Message: fmt.Sprintf("%d applied objects were modified or deleted: %v", len(drifted), drifted),
+	})
+	if !wasDrifted {
+		r.Recorder.Eventf(plan, nil, corev1.EventTypeWarning, "DriftDetected", "DriftCheck",
+			"%d applied objects no longer match revision %s: %v", len(drifted), plan.Status.AppliedRevision, drifted)
+	}
+}
+
+// matches reports whether the live object still grants what the manifest
+// specifies. Only rules, role references and subjects are compared; labels
+// and annotations added by other tools are not drift. A missing object never
+// matches.
+func (r *Reconciler) matches(ctx context.Context, u *unstructured.Unstructured) (bool, error) {
+	switch u.GetKind() {
+	case "Role":
+		var want, live rbacv1.Role
+		if found, err := r.load(ctx, u, &want, &live); !found || err != nil {
+			return false, err
+		}
+		return equality.Semantic.DeepEqual(want.Rules, live.Rules), nil
+	case "ClusterRole":
+		var want, live rbacv1.ClusterRole
+		if found, err := r.load(ctx, u, &want, &live); !found || err != nil {
+			return false, err
+		}
+		return equality.Semantic.DeepEqual(want.Rules, live.Rules), nil
+	case "RoleBinding":
+		var want, live rbacv1.RoleBinding
+		if found, err := r.load(ctx, u, &want, &live); !found || err != nil {
+			return false, err
+		}
+		return want.RoleRef == live.RoleRef && subjectsEqual(want.Subjects, live.Subjects), nil
+	default:
+		var want, live rbacv1.ClusterRoleBinding
+		if found, err := r.load(ctx, u, &want, &live); !found || err != nil {
+			return false, err
+		}
+		return want.RoleRef == live.RoleRef && subjectsEqual(want.Subjects, live.Subjects), nil
+	}
+}
+
+// load decodes the manifest into want and reads the live object into live.
+func (r *Reconciler) load(ctx context.Context, u *unstructured.Unstructured, want, live client.Object) (bool, error) {
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, want); err != nil {
+		return false, err
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(u), live); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return true, nil
+}
+
+// subjectsEqual compares binding subjects after applying the API server's
+// apiGroup defaulting for User and Group subjects.
+func subjectsEqual(a, b []rbacv1.Subject) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if defaultSubject(a[i]) != defaultSubject(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func defaultSubject(s rbacv1.Subject) rbacv1.Subject {
+	if s.APIGroup == "" && (s.Kind == rbacv1.UserKind || s.Kind == rbacv1.GroupKind) {
+		s.APIGroup = rbacv1.GroupName
+	}
+	return s
+}
+
+// phaseFor derives the plan phase from its status.
+func phaseFor(plan *audiciav1alpha1.AudiciaPolicyPlan, approved bool) audiciav1alpha1.PlanPhase {
+	switch {
+	case !approved:
+		return audiciav1alpha1.PlanPhaseAwaitingApproval
+	case meta.IsStatusConditionTrue(plan.Status.Conditions, "Drifted"):
+		return audiciav1alpha1.PlanPhaseDrifted
+	default:
+		return audiciav1alpha1.PlanPhaseApplied
+	}
+}
+
+func qualifiedName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
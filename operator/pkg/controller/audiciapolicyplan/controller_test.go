@@ -0,0 +1,288 @@
+package audiciapolicyplan
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+const testRole = `apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: suggested-backend-role
+  namespace: prod
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - pods
+  verbs:
+  - get
+`
+
+const testBinding = `apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: suggested-backend-binding
+  namespace: prod
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: suggested-backend-role
+subjects:
+- kind: ServiceAccount
+  name: backend
+  namespace: prod
+`
+
+var planKey = types.NamespacedName{Name: "backend-plan", Namespace: "prod"}
+
+func testPolicy(manifests ...string) *audiciav1alpha1.AudiciaPolicy {
+	return &audiciav1alpha1.AudiciaPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy-backend", Namespace: "prod"},
+		Spec: audiciav1alpha1.AudiciaPolicySpec{
+			Subject:   audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "backend", Namespace: "prod"},
+			SourceRef: "src",
+			Manifests: manifests,
+		},
+		Status: audiciav1alpha1.AudiciaPolicyStatus{State: audiciav1alpha1.PolicyStatePending},
+	}
+}
+
+func testPlan() *audiciav1alpha1.AudiciaPolicyPlan {
+	return &audiciav1alpha1.AudiciaPolicyPlan{
+		ObjectMeta: metav1.ObjectMeta{Name: planKey.Name, Namespace: planKey.Namespace, Generation: 1},
+		Spec:       audiciav1alpha1.AudiciaPolicyPlanSpec{PolicyRefs: []string{"policy-backend"}},
+	}
+}
+
+func newTestReconciler(objs ...client.Object) (*Reconciler, *events.FakeRecorder) {
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = audiciav1alpha1.AddToScheme(s)
+	rec := events.NewFakeRecorder(10)
+	return &Reconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(s).
+			WithObjects(objs...).
+			WithStatusSubresource(&audiciav1alpha1.AudiciaPolicyPlan{}, &audiciav1alpha1.AudiciaPolicy{}).
+			Build(),
+		Recorder: rec,
+	}, rec
+}
+
+func reconcilePlan(t *testing.T, r *Reconciler) *audiciav1alpha1.AudiciaPolicyPlan {
+	t.Helper()
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: planKey}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var plan audiciav1alpha1.AudiciaPolicyPlan
+	if err := r.Get(context.Background(), planKey, &plan); err != nil {
+		t.Fatal(err)
+	}
+	return &plan
+}
+
+// approve sets spec.approvedRevision to the plan's current revision.
+func approve(t *testing.T, r *Reconciler, plan *audiciav1alpha1.AudiciaPolicyPlan) {
+	t.Helper()
+	plan.Spec.ApprovedRevision = plan.Status.Revision
+	if err := r.Update(context.Background(), plan); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func drain(rec *events.FakeRecorder) string {
+	var out []string
+	for {
+		select {
+		case e := <-rec.Events:
+			out = append(out, e)
+		default:
+			return strings.Join(out, "\n")
+		}
+	}
+}
+
+func TestReconcile_AwaitsApproval(t *testing.T) {
+	r, _ := newTestReconciler(testPolicy(testRole, testBinding), testPlan())
+
+	plan := reconcilePlan(t, r)
+	if plan.Status.Phase != audiciav1alpha1.PlanPhaseAwaitingApproval {
+		t.Errorf("phase = %s, want AwaitingApproval", plan.Status.Phase)
+	}
+	if plan.Status.Revision == "" {
+		t.Fatal("expected revision to be computed")
+	}
+
+	var role rbacv1.Role
+	err := r.Get(context.Background(), types.NamespacedName{Namespace: "prod", Name: "suggested-backend-role"}, &role)
+	if err == nil {
+		t.Error("role must not be applied before approval")
+	}
+}
+
+func TestReconcile_AppliesApprovedRevision(t *testing.T) {
+	r, rec := newTestReconciler(testPolicy(testRole, testBinding), testPlan())
+	plan := reconcilePlan(t, r)
+	approve(t, r, plan)
+
+	plan = reconcilePlan(t, r)
+	if plan.Status.Phase != audiciav1alpha1.PlanPhaseApplied {
+		t.Fatalf("phase = %s, want Applied; conditions %+v", plan.Status.Phase, plan.Status.Conditions)
+	}
+	if plan.Status.AppliedRevision != plan.Status.Revision {
+		t.Errorf("AppliedRevision = %q, want %q", plan.Status.AppliedRevision, plan.Status.Revision)
+	}
+	if len(plan.Status.Objects) != 2 || len(plan.Status.History) != 1 {
+		t.Errorf("objects = %d, history = %d, want 2 and 1", len(plan.Status.Objects), len(plan.Status.History))
+	}
+	if !strings.Contains(drain(rec), "PlanApplied") {
+		t.Error("expected PlanApplied event")
+	}
+
+	var role rbacv1.Role
+	if err := r.Get(context.Background(), types.NamespacedName{Namespace: "prod", Name: "suggested-backend-role"}, &role); err != nil {
+		t.Fatalf("role not applied: %v", err)
+	}
+	if role.Annotations[PlanAnnotation] != "prod/backend-plan" || role.Annotations[RevisionAnnotation] != plan.Status.Revision {
+		t.Errorf("unexpected annotations %v", role.Annotations)
+	}
+
+	var policy audiciav1alpha1.AudiciaPolicy
+	if err := r.Get(context.Background(), types.NamespacedName{Namespace: "prod", Name: "policy-backend"}, &policy); err != nil {
+		t.Fatal(err)
+	}
+	if policy.Status.State != audiciav1alpha1.PolicyStateApplied {
+		t.Errorf("policy state = %s, want Applied", policy.Status.State)
+	}
+
+	// A further reconcile with nothing changed must not apply again.
+	plan = reconcilePlan(t, r)
+	if len(plan.Status.History) != 1 {
+		t.Errorf("history = %d after no-op reconcile, want 1", len(plan.Status.History))
+	}
+}
+
+func TestReconcile_DetectsDrift(t *testing.T) {
+	r, rec := newTestReconciler(testPolicy(testRole, testBinding), testPlan())
+	plan := reconcilePlan(t, r)
+	approve(t, r, plan)
+	reconcilePlan(t, r)
+	drain(rec)
+
+	var role rbacv1.Role
+	key := types.NamespacedName{Namespace: "prod", Name: "suggested-backend-role"}
+	if err := r.Get(context.Background(), key, &role); err != nil {
+		t.Fatal(err)
+	}
+	role.Rules[0].Verbs = append(role.Rules[0].Verbs, "delete")
+	if err := r.Update(context.Background(), &role); err != nil {
+		t.Fatal(err)
+	}
+
+	plan = reconcilePlan(t, r)
+	if plan.Status.Phase != audiciav1alpha1.PlanPhaseDrifted {
+		t.Fatalf("phase = %s, want Drifted", plan.Status.Phase)
+	}
+	cond := meta.FindStatusCondition(plan.Status.Conditions, "Drifted")
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Errorf("Drifted condition = %+v", cond)
+	}
+	for _, o := range plan.Status.Objects {
+		if o.Drifted != (o.Kind == "Role") {
+			t.Errorf("%s drifted = %v", o.Kind, o.Drifted)
+		}
+	}
+	if !strings.Contains(drain(rec), "DriftDetected") {
+		t.Error("expected DriftDetected event")
+	}
+}
+
+func TestReconcile_RevisionChangeNeedsReapproval(t *testing.T) {
+	r, _ := newTestReconciler(testPolicy(testRole, testBinding), testPlan())
+	plan := reconcilePlan(t, r)
+	approve(t, r, plan)
+	plan = reconcilePlan(t, r)
+	applied := plan.Status.AppliedRevision
+
+	var policy audiciav1alpha1.AudiciaPolicy
+	if err := r.Get(context.Background(), types.NamespacedName{Namespace: "prod", Name: "policy-backend"}, &policy); err != nil {
+		t.Fatal(err)
+	}
+	policy.Spec.Manifests[0] = strings.Replace(testRole, "- get", "- get\n  - list", 1)
+	if err := r.Update(context.Background(), &policy); err != nil {
+		t.Fatal(err)
+	}
+
+	plan = reconcilePlan(t, r)
+	if plan.Status.Revision == applied {
+		t.Fatal("expected revision to change with the manifests")
+	}
+	if plan.Status.Phase != audiciav1alpha1.PlanPhaseAwaitingApproval || plan.Status.AppliedRevision != applied {
+		t.Errorf("phase = %s, applied = %s; want AwaitingApproval, %s", plan.Status.Phase, plan.Status.AppliedRevision, applied)
+	}
+	cond := meta.FindStatusCondition(plan.Status.Conditions, "Approved")
+	if cond == nil || cond.Reason != "RevisionChanged" {
+		t.Errorf("Approved condition = %+v, want RevisionChanged", cond)
+	}
+
+	var role rbacv1.Role
+	if err := r.Get(context.Background(), types.NamespacedName{Namespace: "prod", Name: "suggested-backend-role"}, &role); err != nil {
+		t.Fatal(err)
+	}
+	if len(role.Rules[0].Verbs) != 1 {
+		t.Errorf("unapproved revision was applied: %v", role.Rules[0].Verbs)
+	}
+}
+
+func TestReconcile_RejectsNonRBACManifest(t *testing.T) {
+	deployment := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: sneaky\n  namespace: prod\n"
+	r, _ := newTestReconciler(testPolicy(testRole, deployment), testPlan())
+
+	plan := reconcilePlan(t, r)
+	if plan.Status.Phase != audiciav1alpha1.PlanPhaseFailed {
+		t.Errorf("phase = %s, want Failed", plan.Status.Phase)
+	}
+	cond := meta.FindStatusCondition(plan.Status.Conditions, "Ready")
+	if cond == nil || cond.Reason != "InvalidPolicies" {
+		t.Errorf("Ready condition = %+v, want InvalidPolicies", cond)
+	}
+}
+
+func TestPlansForPolicy(t *testing.T) {
+	other := testPlan()
+	other.Name = "other-plan"
+	other.Spec.PolicyRefs = []string{"policy-other"}
+	r, _ := newTestReconciler(testPlan(), other)
+
+	reqs := r.plansForPolicy(context.Background(), testPolicy())
+	if len(reqs) != 1 || reqs[0].NamespacedName != planKey {
+		t.Errorf("plansForPolicy() = %v, want [%s]", reqs, planKey)
+	}
+}
+
+func TestSubjectsEqual_DefaultsAPIGroup(t *testing.T) {
+	manifest := []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "alice"}}
+	live := []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "alice", APIGroup: rbacv1.GroupName}}
+	if !subjectsEqual(manifest, live) {
+		t.Error("expected defaulted apiGroup to compare equal")
+	}
+	live[0].Name = "bob"
+	if subjectsEqual(manifest, live) {
+		t.Error("expected different subjects to differ")
+	}
+}
@@ -0,0 +1,124 @@
+package audiciasource
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// maxListedExcludedSubjects caps status.excludedSubjects.subjects.
+const maxListedExcludedSubjects = 20
+
+// subjectAdmission decides which subjects get reports under
+// spec.limits.maxSubjectsPerSource. Free slots go to the most active waiting
+// subjects, and admitted subjects keep their slot for the lifetime of the
+// pipeline, so reports don't flap as traffic shifts. It is owned by a single
+// pipeline goroutine and is not safe for concurrent use.
+type subjectAdmission struct {
+	max      int
+	admitted map[subjectKey]bool
+	excluded *audiciav1alpha1.ExcludedSubjectsStatus
+	recorded *audiciav1alpha1.ExcludedSubjectsStatus
+}
+
+// newSubjectAdmission starts with no subject admitted. The status already in
+// status.excludedSubjects counts as recorded, so an unchanged list is not
+// written again.
+func newSubjectAdmission(source audiciav1alpha1.AudiciaSource) *subjectAdmission {
+	return &subjectAdmission{
+		max:      int(source.Spec.Limits.MaxSubjectsPerSource),
+		admitted: make(map[subjectKey]bool),
+		recorded: source.Status.ExcludedSubjects,
+	}
+}
+
+// filter admits waiting subjects into free slots and returns the aggregators
+// of the admitted subjects, the ones to flush. Without a limit, all
+// aggregators are returned.
+func (a *subjectAdmission) filter(
+	aggregators map[subjectKey]*aggregator.Aggregator,
+	subjects map[subjectKey]audiciav1alpha1.Subject,
+) map[subjectKey]*aggregator.Aggregator {
+	if a.max <= 0 {
+		a.excluded = nil
+		return aggregators
+	}
+
+	// Subjects that left the pipeline (e.g. expired) free their slot.
+	for sk := range a.admitted {
+		if _, ok := aggregators[sk]; !ok {
+			delete(a.admitted, sk)
+		}
+	}
+
+	var waiting []subjectKey
+	for sk := range aggregators {
+		if !a.admitted[sk] {
+			waiting = append(waiting, sk)
+		}
+	}
+	slices.SortFunc(waiting, func(x, y subjectKey) int {
+		return cmp.Or(
+			cmp.Compare(aggregators[y].EventsProcessed(), aggregators[x].EventsProcessed()),
+			cmp.Compare(x.String(), y.String()),
+		)
+	})
+	free := min(max(a.max-len(a.admitted), 0), len(waiting))
+	for _, sk := range waiting[:free] {
+		a.admitted[sk] = true
+	}
+	waiting = waiting[free:]
+
+	a.excluded = nil
+	if len(waiting) > 0 {
+		a.excluded = &audiciav1alpha1.ExcludedSubjectsStatus{Total: int32(len(waiting))}
+		for _, sk := range waiting[:min(len(waiting), maxListedExcludedSubjects)] {
+			a.excluded.Subjects = append(a.excluded.Subjects, audiciav1alpha1.ExcludedSubject{
+				Subject:         subjects[sk],
+				EventsProcessed: aggregators[sk].EventsProcessed(),
+			})
+		}
+	}
+
+	admitted := make(map[subjectKey]*aggregator.Aggregator, len(a.admitted))
+	for sk := range a.admitted {
+		admitted[sk] = aggregators[sk]
+	}
+	return admitted
+}
+
+// recordExcludedSubjects persists status.excludedSubjects when it changed
+// since it was last written.
+func (r *Reconciler) recordExcludedSubjects(ctx context.Context, key types.NamespacedName, a *subjectAdmission) {
+	if equality.Semantic.DeepEqual(a.excluded, a.recorded) {
+		return
+	}
+	logger := ctrl.Log.WithName("pipeline").WithValues("source", key)
+	status := a.excluded
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var source audiciav1alpha1.AudiciaSource
+		if err := r.Get(ctx, key, &source); err != nil {
+			return err
+		}
+		source.Status.ExcludedSubjects = status
+		return r.Status().Update(ctx, &source)
+	})
+	switch {
+	case err == nil:
+		a.recorded = status
+		if status != nil {
+			logger.V(1).Info("subjects excluded by maxSubjectsPerSource", "excluded", status.Total)
+		}
+	case !errors.IsNotFound(err):
+		logger.Error(err, "failed to record excluded subjects")
+	}
+}
@@ -0,0 +1,89 @@
+package audiciasource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+)
+
+func TestSubjectAdmission(t *testing.T) {
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+	observe := func(user string, events int) subjectKey {
+		subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: user}
+		sk := keyFor(subject)
+		agg := aggregator.New()
+		for range events {
+			agg.Add(normalizer.CanonicalRule{Resource: "pods", Verb: "get"}, time.Now())
+		}
+		aggregators[sk], subjects[sk] = agg, subject
+		return sk
+	}
+	busy, medium, quiet := observe("busy", 5), observe("medium", 3), observe("quiet", 1)
+
+	source := audiciav1alpha1.AudiciaSource{}
+	source.Spec.Limits.MaxSubjectsPerSource = 2
+	a := newSubjectAdmission(source)
+
+	got := a.filter(aggregators, subjects)
+	if len(got) != 2 || got[busy] == nil || got[medium] == nil {
+		t.Fatalf("admitted %v, want busy and medium", got)
+	}
+	if a.excluded == nil || a.excluded.Total != 1 || a.excluded.Subjects[0].Subject.Name != "quiet" ||
+		a.excluded.Subjects[0].EventsProcessed != 1 {
+		t.Errorf("excluded = %+v, want quiet with 1 event", a.excluded)
+	}
+
+	// A busier newcomer waits for a free slot instead of displacing a report.
+	newcomer := observe("newcomer", 10)
+	if got := a.filter(aggregators, subjects); got[newcomer] != nil {
+		t.Error("newcomer must not displace an admitted subject")
+	}
+
+	// A subject leaving the pipeline frees its slot for the most active waiting subject.
+	delete(aggregators, medium)
+	got = a.filter(aggregators, subjects)
+	if got[newcomer] == nil || got[quiet] != nil {
+		t.Errorf("admitted %v, want busy and newcomer", got)
+	}
+
+	unlimited := newSubjectAdmission(audiciav1alpha1.AudiciaSource{})
+	if got := unlimited.filter(aggregators, subjects); len(got) != len(aggregators) || unlimited.excluded != nil {
+		t.Errorf("unlimited admission filtered subjects: %v", got)
+	}
+}
+
+func TestRecordExcludedSubjects(t *testing.T) {
+	source := &audiciav1alpha1.AudiciaSource{ObjectMeta: metav1.ObjectMeta{Name: "src", Namespace: "default"}}
+	r := newTestReconciler(source)
+	key := types.NamespacedName{Name: "src", Namespace: "default"}
+
+	a := newSubjectAdmission(*source)
+	a.excluded = &audiciav1alpha1.ExcludedSubjectsStatus{Total: 3}
+	r.recordExcludedSubjects(context.Background(), key, a)
+
+	var got audiciav1alpha1.AudiciaSource
+	if err := r.Get(context.Background(), key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.ExcludedSubjects == nil || got.Status.ExcludedSubjects.Total != 3 {
+		t.Fatalf("status.excludedSubjects = %+v", got.Status.ExcludedSubjects)
+	}
+
+	// Lifting the limit clears the list.
+	a.excluded = nil
+	r.recordExcludedSubjects(context.Background(), key, a)
+	if err := r.Get(context.Background(), key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.ExcludedSubjects != nil {
+		t.Errorf("expected status.excludedSubjects to be cleared, got %+v", got.Status.ExcludedSubjects)
+	}
+}
@@ -0,0 +1,59 @@
+package audiciasource
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+)
+
+// BreakGlassLabel is set to "true" on the reports of break-glass identities
+// (spec.breakGlass), so they can be selected apart from the rest.
+const BreakGlassLabel = "audicia.io/break-glass"
+
+// isBreakGlass reports whether subject is one of the source's break-glass
+// identities.
+func isBreakGlass(source audiciav1alpha1.AudiciaSource, subject audiciav1alpha1.Subject) bool {
+	if source.Spec.BreakGlass == nil {
+		return false
+	}
+	for _, s := range source.Spec.BreakGlass.Subjects {
+		if keyFor(s) == keyFor(subject) {
+			return true
+		}
+	}
+	return false
+}
+
+// alertBreakGlass raises a warning on the report and the source when a
+// flush of a break-glass identity's report carries activity newer than the
+// report held before.
+func (r *Reconciler) alertBreakGlass(
+	source audiciav1alpha1.AudiciaSource,
+	report *audiciav1alpha1.AudiciaReport,
+	subject audiciav1alpha1.Subject,
+	previousSeen time.Time,
+	rules []audiciav1alpha1.ObservedRule,
+) {
+	if !isBreakGlass(source, subject) {
+		return
+	}
+	// The report stores lastSeen with second precision.
+	last := latestSeen(rules).Truncate(time.Second)
+	if !last.After(previousSeen) {
+		return
+	}
+	metrics.BreakGlassActivityTotal.WithLabelValues(keyFor(subject).String()).Inc()
+	since := "first use"
+	if !previousSeen.IsZero() {
+		since = "since " + previousSeen.UTC().Format(time.RFC3339)
+	}
+	r.Recorder.Eventf(report, nil, corev1.EventTypeWarning, "BreakGlassActivity", "Observe",
+		"Break-glass identity %s %s was active (%s), last at %s",
+		subject.Kind, subject.Name, since, last.UTC().Format(time.RFC3339))
+	r.Recorder.Eventf(&source, nil, corev1.EventTypeWarning, "BreakGlassActivity", "Observe",
+		"Break-glass identity %s %s was active, see report %s/%s",
+		subject.Kind, subject.Name, report.Namespace, report.Name)
+}
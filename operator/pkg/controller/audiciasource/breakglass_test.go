@@ -0,0 +1,96 @@
+package audiciasource
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+	"github.com/felixnotka/audicia/operator/pkg/strategy"
+)
+
+func TestIsBreakGlass(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{Spec: audiciav1alpha1.AudiciaSourceSpec{
+		BreakGlass: &audiciav1alpha1.BreakGlassConfig{
+			Namespace: "break-glass",
+			Subjects: []audiciav1alpha1.Subject{
+				{Kind: audiciav1alpha1.SubjectKindUser, Name: "emergency-admin"},
+				{Kind: audiciav1alpha1.SubjectKindServiceAccount, Namespace: "ops", Name: "breakglass"},
+			},
+		},
+	}}
+	tests := []struct {
+		subject audiciav1alpha1.Subject
+		want    bool
+	}{
+		{audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "emergency-admin"}, true},
+		{audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindGroup, Name: "emergency-admin"}, false},
+		{audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Namespace: "ops", Name: "breakglass"}, true},
+		{audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Namespace: "dev", Name: "breakglass"}, false},
+	}
+	for _, tt := range tests {
+		if got := isBreakGlass(source, tt.subject); got != tt.want {
+			t.Errorf("isBreakGlass(%v) = %v, want %v", tt.subject, got, tt.want)
+		}
+	}
+	if isBreakGlass(audiciav1alpha1.AudiciaSource{}, tests[0].subject) {
+		t.Error("expected no break-glass identities without spec.breakGlass")
+	}
+}
+
+func TestFlushSubject_BreakGlass(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "audit", Namespace: "default", UID: "audit"},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{BreakGlass: &audiciav1alpha1.BreakGlassConfig{
+			Namespace: "break-glass",
+			Subjects:  []audiciav1alpha1.Subject{{Kind: audiciav1alpha1.SubjectKindUser, Name: "emergency-admin"}},
+		}},
+	}
+	r := newTestReconciler(&source)
+	recorder := r.Recorder.(*events.FakeRecorder)
+	engine := strategy.NewEngine(audiciav1alpha1.PolicyStrategy{})
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "emergency-admin"}
+
+	agg := aggregator.New()
+	agg.Add(normalizer.CanonicalRule{Resource: "secrets", Verb: "get", Namespace: "kube-system"}, time.Now())
+	for range 2 {
+		if err := r.flushSubject(context.Background(), source, engine, subject, agg, true, logr.Discard()); err != nil {
+			t.Fatalf("flushSubject: %v", err)
+		}
+	}
+
+	var report audiciav1alpha1.AudiciaReport
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "report-emergency-admin", Namespace: "break-glass"}, &report); err != nil {
+		t.Fatalf("expected the report in the break-glass namespace: %v", err)
+	}
+	if report.Labels[BreakGlassLabel] != "true" {
+		t.Errorf("labels = %v, want %s", report.Labels, BreakGlassLabel)
+	}
+	var policy audiciav1alpha1.AudiciaPolicy
+	err := r.Get(context.Background(), types.NamespacedName{Name: "policy-emergency-admin", Namespace: "break-glass"}, &policy)
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected no policy for a break-glass identity, got %v", err)
+	}
+
+	// One alert on the report and one on the source: the second flush
+	// carries no new activity.
+	close(recorder.Events)
+	alerts := 0
+	for e := range recorder.Events {
+		if strings.Contains(e, "BreakGlassActivity") {
+			alerts++
+		}
+	}
+	if alerts != 2 {
+		t.Errorf("got %d BreakGlassActivity events, want 2", alerts)
+	}
+}
@@ -0,0 +1,337 @@
+package audiciasource
+
+import (
+	"strconv"
+	"time"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	"github.com/felixnotka/audicia/operator/pkg/agent"
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/filter"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+)
+
+// phase orders the processors of an event bus. Processors run phase by
+// phase, and in registration order within a phase.
+type phase int
+
+const (
+	// phaseFilter drops events before any normalization.
+	phaseFilter phase = iota
+	// phaseNormalize resolves the subjects and the canonical rule.
+	phaseNormalize
+	// phaseEnrich adjusts or annotates the normalized event.
+	phaseEnrich
+	// phaseAggregate folds the rule into the subjects' aggregators.
+	phaseAggregate
+	// phaseExport hands the aggregated rule to consumers outside the
+	// pipeline.
+	phaseExport
+
+	numPhases
+)
+
+// busEvent is an audit event in flight through an event bus. Processors
+// fill in the fields of their phase for the later ones.
+type busEvent struct {
+	audit     auditv1.Event
+	username  string
+	namespace string
+
+	// time is the request time, in the activity time zone after phaseEnrich.
+	// For events without timestamps it is the processing time, and
+	// rule.TimestampFallback is set.
+	time time.Time
+
+	// subject is the requesting user, aggregated when include is set.
+	subject audiciav1alpha1.Subject
+	include bool
+
+	// groups are the user's tracked groups, aggregated in addition.
+	groups []audiciav1alpha1.Subject
+
+	rule normalizer.CanonicalRule
+
+	// weight is the number of events the event stands for: an agent's
+	// summary count, times the spec.sampling weight.
+	weight int64
+}
+
+// subjects returns the subjects the event's rule is attributed to.
+func (e *busEvent) subjects() []audiciav1alpha1.Subject {
+	if !e.include {
+		return e.groups
+	}
+	return append([]audiciav1alpha1.Subject{e.subject}, e.groups...)
+}
+
+// processor is one pluggable step of a source's event pipeline. It returns
+// the filter_rule label under which it dropped the event, or "" to pass it
+// on to the next processor.
+type processor func(e *busEvent) (dropped string)
+
+// eventBus runs each audit event of a source through its processors, phase
+// by phase. New pipeline features register processors instead of growing
+// eventLoop. It is owned by the pipeline goroutine or one of its event
+// workers and not safe for concurrent use.
+type eventBus struct {
+	phases [numPhases][]processor
+}
+
+// register appends processors to a phase.
+func (b *eventBus) register(p phase, procs ...processor) {
+	b.phases[p] = append(b.phases[p], procs...)
+}
+
+// publish runs event through the processors. It returns the filter_rule
+// label of the processor that dropped the event, or "" if the event passed
+// all of them.
+func (b *eventBus) publish(event auditv1.Event) string {
+	e := &busEvent{audit: event, username: event.User.Username, namespace: eventNamespace(event), weight: 1}
+	for _, procs := range b.phases {
+		for _, proc := range procs {
+			if dropped := proc(e); dropped != "" {
+				metrics.EventsFilteredTotal.WithLabelValues(dropped).Inc()
+				return dropped
+			}
+		}
+	}
+	return ""
+}
+
+// newEventBus registers the standard processors of a source: the request
+// filters, sampling, subject and rule normalization, the activity time zone,
+// aggregate and the export to the rule stream.
+func (r *Reconciler) newEventBus(
+	source audiciav1alpha1.AudiciaSource,
+	filterChain *filter.Chain,
+	aliases *normalizer.SubjectAliases,
+	groups *normalizer.GroupTracker,
+	aggregate processor,
+) *eventBus {
+	b := &eventBus{}
+	if acceptsAgentSummaries(source) {
+		b.register(phaseFilter, weighSummary)
+	}
+	b.register(phaseFilter,
+		countTraffic(source),
+		filterStage(source),
+		filterDenied(source),
+		filterDryRun(source),
+		applyFilters(filterChain),
+	)
+	if sampler := newEventSampler(source); sampler != nil {
+		b.register(phaseFilter, sampleEvent(sampler))
+	}
+	b.register(phaseNormalize,
+		normalizeSubject(source, aliases, groups),
+		normalizeRule(source),
+	)
+	if tz := source.Spec.ActivityTimeZone; tz != "" {
+		b.register(phaseEnrich, localizeTime(tz))
+	}
+	b.register(phaseAggregate, aggregate)
+	b.register(phaseExport,
+		r.exportRule(source),
+		countAccepted(source),
+	)
+	return b
+}
+
+// acceptsAgentSummaries reports whether the source's callers are edge agents
+// whose event counts are honored: a Webhook source with agentSummaries and
+// authenticated callers. Anyone able to send events to other sources could
+// otherwise inflate counts with the annotation.
+func acceptsAgentSummaries(source audiciav1alpha1.AudiciaSource) bool {
+	wh := source.Spec.Webhook
+	if source.Spec.SourceType != audiciav1alpha1.SourceTypeWebhook || wh == nil || !wh.AgentSummaries {
+		return false
+	}
+	tokenReview := wh.Authentication != nil && wh.Authentication.Mode == audiciav1alpha1.WebhookAuthModeTokenReview
+	return wh.ClientCASecretName != "" || wh.AuthTokenSecretName != "" || tokenReview
+}
+
+// weighSummary weights the summaries an edge agent forwards by the number of
+// events each stands for, up to agent.MaxEventCount.
+func weighSummary(e *busEvent) string {
+	if v, ok := e.audit.Annotations[agent.EventCountAnnotation]; ok {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			e.weight = min(n, agent.MaxEventCount)
+		}
+	}
+	return ""
+}
+
+// countTraffic counts every event before filtering, so the metrics reflect
+// the audit policy's output rather than what Audicia keeps.
+func countTraffic(source audiciav1alpha1.AudiciaSource) processor {
+	return func(e *busEvent) string {
+		if ref := e.audit.ObjectRef; ref != nil {
+			metrics.ObserveEventTraffic(string(source.Spec.SourceType), e.audit.Verb, ref.Resource, ref.APIGroup)
+		} else {
+			metrics.ObserveEventTraffic(string(source.Spec.SourceType), e.audit.Verb, "", "")
+		}
+		return ""
+	}
+}
+
+// filterStage keeps only the configured stages, so each request is counted
+// once. Rules from requests that have not completed (or panicked) are marked
+// incomplete.
+func filterStage(source audiciav1alpha1.AudiciaSource) processor {
+	return func(e *busEvent) string {
+		if !stageAllowed(source.Spec, e.audit.Stage) {
+			return "stage"
+		}
+		e.rule.Incomplete = e.audit.Stage == auditv1.StageResponseStarted || e.audit.Stage == auditv1.StagePanic
+		return ""
+	}
+}
+
+// filterDenied drops denied requests, which never widen the suggested
+// policy, unless spec.includeDenied keeps them apart as denied rules.
+// Unauthenticated requests carry no usable subject and are always dropped.
+func filterDenied(source audiciav1alpha1.AudiciaSource) processor {
+	return func(e *busEvent) string {
+		denied := isForbidden(e.audit)
+		if isUnauthenticated(e.audit) || denied && !source.Spec.IncludeDenied {
+			return "denied"
+		}
+		e.rule.Denied = denied
+		e.rule.AdmissionDenied = !denied && isAdmissionDenied(e.audit)
+		return ""
+	}
+}
+
+// filterDryRun drops dry-run requests with spec.excludeDryRun. They are
+// authorized like real ones, so they count as used permissions otherwise.
+func filterDryRun(source audiciav1alpha1.AudiciaSource) processor {
+	return func(e *busEvent) string {
+		if source.Spec.ExcludeDryRun && isDryRun(e.audit) {
+			return "dry_run"
+		}
+		return ""
+	}
+}
+
+// applyFilters stamps the event with its request time (see requestTime) and
+// applies spec.filters.
+func applyFilters(filterChain *filter.Chain) processor {
+	return func(e *busEvent) string {
+		var ok bool
+		if e.time, ok = requestTime(e.audit); !ok {
+			e.time = time.Now()
+			e.rule.TimestampFallback = true
+		}
+		if !filterChain.AllowRequest(e.username, e.namespace, filterRequest(e.audit, e.time)) {
+			return filterRuleDeny
+		}
+		return ""
+	}
+}
+
+// sampleEvent applies spec.sampling to the events the filters kept.
+func sampleEvent(sampler *eventSampler) processor {
+	return func(e *busEvent) string {
+		w := sampler.sample(e.username)
+		if w == 0 {
+			return "sampled"
+		}
+		e.weight *= w
+		return ""
+	}
+}
+
+// normalizeSubject resolves the requesting subject. Explicit aliases take
+// precedence over the built-in username parsing, including the system user
+// check. An excluded user's tracked groups are still aggregated.
+func normalizeSubject(source audiciav1alpha1.AudiciaSource, aliases *normalizer.SubjectAliases, groups *normalizer.GroupTracker) processor {
+	return func(e *busEvent) string {
+		e.subject, e.include = aliases.Resolve(e.username)
+		if !e.include {
+			e.subject, e.include = normalizer.NormalizeSubject(e.username, source.Spec.IgnoreSystemUsers)
+		}
+		e.groups = groups.Subjects(e.audit.User.Groups)
+		if !e.include && len(e.groups) == 0 {
+			return "system_user"
+		}
+		return ""
+	}
+}
+
+// normalizeRule converts the event into a canonical rule.
+func normalizeRule(source audiciav1alpha1.AudiciaSource) processor {
+	return func(e *busEvent) string {
+		var resource, subresource, apiGroup string
+		ref := e.audit.ObjectRef
+		if ref != nil {
+			resource, subresource, apiGroup = ref.Resource, ref.Subresource, ref.APIGroup
+		}
+		rule := normalizer.NormalizeEvent(resource, subresource, apiGroup, e.audit.Verb, e.namespace, e.audit.RequestURI, ref != nil)
+		if ref != nil {
+			rule.ResourceName = ref.Name
+		}
+		rule.Incomplete, rule.Denied, rule.AdmissionDenied = e.rule.Incomplete, e.rule.Denied, e.rule.AdmissionDenied
+		rule.TimestampFallback = e.rule.TimestampFallback
+
+		if source.Spec.CollapseHousekeeping {
+			rule = normalizer.CollapseHousekeeping(rule)
+			if rule.Preset != "" {
+				metrics.EventsCollapsedTotal.WithLabelValues(rule.Preset).Inc()
+			}
+		}
+
+		// Skip events that resolved to neither a resource nor a non-resource
+		// URL (e.g., no objectRef and empty requestURI). These produce empty
+		// apiGroups/resources which fail CRD validation.
+		if rule.Resource == "" && rule.NonResourceURL == "" {
+			return "unresolvable"
+		}
+		e.rule = rule
+		return ""
+	}
+}
+
+// localizeTime moves the event time to spec.activityTimeZone, which the
+// aggregators bucket activity in.
+func localizeTime(tz string) processor {
+	return func(e *busEvent) string {
+		if loc, err := activityLocation(tz); err == nil {
+			e.time = e.time.In(loc)
+		}
+		return ""
+	}
+}
+
+// aggregateRule adds the rule, weighted, to the aggregator of each subject.
+// lru may be nil.
+func aggregateRule(aggregators map[subjectKey]*aggregator.Aggregator, subjects map[subjectKey]audiciav1alpha1.Subject, lru *subjectLRU) processor {
+	return func(e *busEvent) string {
+		for _, s := range e.subjects() {
+			lru.touch(keyFor(s), aggregators, subjects)
+			aggregate(aggregators, subjects, s, e.rule, e.time, e.weight)
+		}
+		return ""
+	}
+}
+
+// exportRule publishes the rule of each subject to the rule stream.
+func (r *Reconciler) exportRule(source audiciav1alpha1.AudiciaSource) processor {
+	return func(e *busEvent) string {
+		for _, s := range e.subjects() {
+			r.streamRule(source, s, e.rule, e.time)
+		}
+		return ""
+	}
+}
+
+// countAccepted counts the events that passed all processors.
+func countAccepted(source audiciav1alpha1.AudiciaSource) processor {
+	return func(*busEvent) string {
+		metrics.EventsProcessedTotal.WithLabelValues(string(source.Spec.SourceType), "accepted").Inc()
+		return ""
+	}
+}
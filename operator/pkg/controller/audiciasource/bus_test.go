@@ -0,0 +1,128 @@
+package audiciasource
+
+import (
+	"slices"
+	"testing"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	"github.com/felixnotka/audicia/operator/pkg/agent"
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/filter"
+)
+
+func TestEventBus_RunsPhasesInOrder(t *testing.T) {
+	var order []string
+	step := func(name, dropped string) processor {
+		return func(*busEvent) string {
+			order = append(order, name)
+			return dropped
+		}
+	}
+	b := &eventBus{}
+	b.register(phaseExport, step("export", ""))
+	b.register(phaseFilter, step("filter-1", ""), step("filter-2", ""))
+	b.register(phaseEnrich, step("enrich", ""))
+
+	if dropped := b.publish(auditv1.Event{}); dropped != "" {
+		t.Fatalf("publish() = %q, want the event to pass", dropped)
+	}
+	if want := []string{"filter-1", "filter-2", "enrich", "export"}; !slices.Equal(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+
+	order = nil
+	b.register(phaseNormalize, step("normalize", "custom"))
+	if dropped := b.publish(auditv1.Event{}); dropped != "custom" {
+		t.Errorf("publish() = %q, want custom", dropped)
+	}
+	if want := []string{"filter-1", "filter-2", "normalize"}; !slices.Equal(order, want) {
+		t.Errorf("order = %v, want the bus to stop at the dropping processor", order)
+	}
+}
+
+func TestEventBus_ExtraProcessorSeesNormalizedEvent(t *testing.T) {
+	r := newTestReconciler()
+	source := audiciav1alpha1.AudiciaSource{Spec: audiciav1alpha1.AudiciaSourceSpec{SourceType: audiciav1alpha1.SourceTypeK8sAuditLog}}
+	chain, _ := filter.NewChain(nil)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+
+	b := r.newEventBus(source, chain, nil, nil, aggregateRule(aggregators, subjects, nil))
+	var seen *busEvent
+	b.register(phaseEnrich, func(e *busEvent) string {
+		seen = e
+		if e.rule.Verb == "delete" {
+			return "enrichment"
+		}
+		return ""
+	})
+
+	event := auditv1.Event{
+		Verb:      "get",
+		User:      authnv1.UserInfo{Username: "alice"},
+		ObjectRef: &auditv1.ObjectReference{Resource: "pods", Namespace: "default"},
+	}
+	if dropped := b.publish(event); dropped != "" {
+		t.Fatalf("publish() = %q", dropped)
+	}
+	if seen == nil || seen.subject.Name != "alice" || seen.rule.Resource != "pods" {
+		t.Fatalf("enrichment saw %+v, want the normalized subject and rule", seen)
+	}
+	if len(aggregators) != 1 {
+		t.Errorf("aggregators = %d, want 1", len(aggregators))
+	}
+
+	event.Verb = "delete"
+	if dropped := b.publish(event); dropped != "enrichment" {
+		t.Errorf("publish() = %q, want the enrichment processor to drop the event", dropped)
+	}
+	agg := aggregators[keyFor(seen.subject)]
+	if rules := agg.Rules(); len(rules) != 1 {
+		t.Errorf("rules = %d, want the dropped event not to be aggregated", len(rules))
+	}
+}
+
+func TestEventBus_WeighsAgentSummaries(t *testing.T) {
+	r := newTestReconciler()
+	chain, _ := filter.NewChain(nil)
+	event := auditv1.Event{
+		Stage:     auditv1.StageResponseComplete,
+		Verb:      "get",
+		User:      authnv1.UserInfo{Username: "alice"},
+		ObjectRef: &auditv1.ObjectReference{Resource: "pods", Namespace: "default", APIVersion: "v1"},
+	}
+	agentWebhook := &audiciav1alpha1.WebhookConfig{AgentSummaries: true, AuthTokenSecretName: "edge-token"}
+
+	tests := []struct {
+		name  string
+		spec  audiciav1alpha1.AudiciaSourceSpec
+		count string
+		want  int64
+	}{
+		{"agent webhook", audiciav1alpha1.AudiciaSourceSpec{SourceType: audiciav1alpha1.SourceTypeWebhook, Webhook: agentWebhook}, "25", 25},
+		{"agent webhook clamps", audiciav1alpha1.AudiciaSourceSpec{SourceType: audiciav1alpha1.SourceTypeWebhook, Webhook: agentWebhook}, "9223372036854775807", agent.MaxEventCount},
+		{"webhook without agentSummaries", audiciav1alpha1.AudiciaSourceSpec{SourceType: audiciav1alpha1.SourceTypeWebhook, Webhook: &audiciav1alpha1.WebhookConfig{AuthTokenSecretName: "edge-token"}}, "25", 1},
+		{"unauthenticated agent webhook", audiciav1alpha1.AudiciaSourceSpec{SourceType: audiciav1alpha1.SourceTypeWebhook, Webhook: &audiciav1alpha1.WebhookConfig{AgentSummaries: true}}, "25", 1},
+		{"local", audiciav1alpha1.AudiciaSourceSpec{SourceType: audiciav1alpha1.SourceTypeLocal}, "25", 1},
+		{"audit log", audiciav1alpha1.AudiciaSourceSpec{SourceType: audiciav1alpha1.SourceTypeK8sAuditLog}, "25", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event.Annotations = map[string]string{agent.EventCountAnnotation: tt.count}
+			source := audiciav1alpha1.AudiciaSource{Spec: tt.spec}
+			aggregators := make(map[subjectKey]*aggregator.Aggregator)
+			subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+			if dropped := r.processEvent(event, source, chain, nil, nil, aggregators, subjects); dropped != "" {
+				t.Fatalf("event dropped by %q", dropped)
+			}
+			for _, agg := range aggregators {
+				if got := agg.Rules()[0].Count; got != tt.want {
+					t.Errorf("count = %d, want %d", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package audiciasource
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/diff"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+	"github.com/felixnotka/audicia/operator/pkg/notify"
+	"github.com/felixnotka/audicia/operator/pkg/rbac"
+)
+
+// complianceCondition tracks whether a report's compliance reflects its
+// latest observed rules. Ingest-only operators set it to False on every
+// flush; compliance workers evaluate the report and set it back to True.
+const complianceCondition = "ComplianceEvaluated"
+
+// markCompliancePending queues a report for evaluation by a compliance worker.
+func markCompliancePending(report *audiciav1alpha1.AudiciaReport) {
+	meta.SetStatusCondition(&report.Status.Conditions, metav1.Condition{
+		Type:    complianceCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "EvaluationPending",
+		Message: "Waiting for a compliance worker to evaluate observed rules.",
+	})
+}
+
+// markComplianceEvaluated records that the report's compliance is current.
+func markComplianceEvaluated(report *audiciav1alpha1.AudiciaReport) {
+	meta.SetStatusCondition(&report.Status.Conditions, metav1.Condition{
+		Type:    complianceCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Evaluated",
+		Message: fmt.Sprintf("Evaluated %d observed rules.", len(report.Status.ObservedRules)),
+	})
+}
+
+// compliancePending reports whether the report is queued for evaluation.
+func compliancePending(report *audiciav1alpha1.AudiciaReport) bool {
+	cond := meta.FindStatusCondition(report.Status.Conditions, complianceCondition)
+	return cond != nil && cond.Status == metav1.ConditionFalse
+}
+
+// ComplianceWorker evaluates compliance for AudiciaReports queued by an
+// ingest-only operator. The pending reports themselves form the work queue,
+// so workers need no coordination beyond a stable shard assignment.
+type ComplianceWorker struct {
+	client.Client
+	Resolver *rbac.Resolver
+	Recorder events.EventRecorder
+
+	// Shard and Shards partition reports across worker replicas. A worker
+	// only evaluates reports whose name hashes to its shard.
+	Shard  int
+	Shards int
+
+	// Notifier sends the compliance changes found by the worker. Nil when no
+	// notification sinks are configured.
+	Notifier *notify.Dispatcher
+}
+
+// SetupComplianceWorkerWithManager registers the compliance worker with the
+// manager. notifier may be nil, and resolver nil uses the manager's client
+// without implicit groups.
+func SetupComplianceWorkerWithManager(mgr ctrl.Manager, maxConcurrent, shard, shards int, notifier *notify.Dispatcher, resolver *rbac.Resolver) error {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	if resolver == nil {
+		resolver = rbac.NewResolver(mgr.GetClient())
+	}
+	if shards < 1 {
+		shards = 1
+	}
+	w := &ComplianceWorker{
+		Client:   mgr.GetClient(),
+		Resolver: resolver,
+		Recorder: mgr.GetEventRecorder("audicia-compliance-worker"),
+		Shard:    shard,
+		Shards:   shards,
+		Notifier: notifier,
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("compliance-worker").
+		For(&audiciav1alpha1.AudiciaReport{}, builder.WithPredicates(predicate.NewPredicateFuncs(w.accepts))).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrent}).
+		Complete(w)
+}
+
+// accepts filters the watch down to pending reports in this worker's shard.
+func (w *ComplianceWorker) accepts(obj client.Object) bool {
+	report, ok := obj.(*audiciav1alpha1.AudiciaReport)
+	if !ok {
+		return false
+	}
+	return compliancePending(report) && shardFor(report.Namespace, report.Name, w.Shards) == w.Shard
+}
+
+// Reconcile evaluates one pending report.
+func (w *ComplianceWorker) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var report audiciav1alpha1.AudiciaReport
+	if err := w.Get(ctx, req.NamespacedName, &report); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !compliancePending(&report) {
+		return ctrl.Result{}, nil
+	}
+
+	subject := report.Spec.Subject
+	effective, err := w.Resolver.EffectiveRules(ctx, subject)
+	if err != nil {
+		// Leave the report pending; the controller requeues with backoff.
+		metrics.ComplianceEvaluationsTotal.WithLabelValues("error").Inc()
+		return ctrl.Result{}, fmt.Errorf("resolving effective rules for %s: %w", subject.Name, err)
+	}
+
+	prevSeverity := currentSeverity(&report)
+	prevCompliance := report.Status.Compliance.DeepCopy()
+	report.Status.Compliance = diff.Evaluate(report.Status.ObservedRules, effective)
+	markComplianceEvaluated(&report)
+
+	// A conflict means the ingest operator flushed again; the resulting watch
+	// event requeues the report with the newer rules.
+	if err := w.Status().Update(ctx, &report); err != nil {
+		metrics.ComplianceEvaluationsTotal.WithLabelValues("error").Inc()
+		return ctrl.Result{}, err
+	}
+	metrics.ComplianceEvaluationsTotal.WithLabelValues("success").Inc()
+	observeCompliance(&report)
+
+	emitDriftEvent(w.Recorder, &report, prevSeverity)
+	w.Notifier.Notify(notify.Changes(&report, prevCompliance)...)
+	logger.V(1).Info("compliance evaluated", "report", req.NamespacedName, "severity", report.Status.Compliance.Severity)
+	return ctrl.Result{}, nil
+}
+
+// shardFor maps a report to one of n shards.
+func shardFor(namespace, name string, n int) int {
+	if n <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(namespace + "/" + name))
+	return int(h.Sum32() % uint32(n))
+}
@@ -0,0 +1,196 @@
+package audiciasource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/notify"
+	"github.com/felixnotka/audicia/operator/pkg/rbac"
+)
+
+func newTestComplianceWorker(objs ...client.Object) *ComplianceWorker {
+	s := newTestScheme()
+	_ = rbacv1.AddToScheme(s)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(objs...).
+		WithStatusSubresource(&audiciav1alpha1.AudiciaReport{}).
+		Build()
+	return &ComplianceWorker{
+		Client:   fakeClient,
+		Resolver: rbac.NewResolver(fakeClient),
+		Recorder: events.NewFakeRecorder(10),
+		Shards:   1,
+	}
+}
+
+func TestPopulateReportStatus_DeferComplianceMarksPending(t *testing.T) {
+	r := newTestReconciler()
+	r.DeferCompliance = true
+
+	report := &audiciav1alpha1.AudiciaReport{}
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "sa", Namespace: "default"}
+	rules := []audiciav1alpha1.ObservedRule{makeObservedRule("pods", "get", "default", time.Now())}
+
+	r.populateReportStatus(context.Background(), report, subject, rules, nil, nil, 1, logr.Discard())
+
+	if report.Status.Compliance != nil {
+		t.Error("expected compliance to be left to the worker")
+	}
+	if !compliancePending(report) {
+		t.Error("expected report to be marked ComplianceEvaluated=False")
+	}
+}
+
+func TestComplianceWorker_EvaluatesPendingReport(t *testing.T) {
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "reader", Namespace: "default"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}},
+			{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}},
+		},
+	}
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "reader", Namespace: "default"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "Role", Name: "reader"},
+		Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: "sa", Namespace: "default"}},
+	}
+	report := &audiciav1alpha1.AudiciaReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "report-sa", Namespace: "default"},
+		Spec: audiciav1alpha1.AudiciaReportSpec{
+			Subject: audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "sa", Namespace: "default"},
+		},
+		Status: audiciav1alpha1.AudiciaReportStatus{
+			ObservedRules: []audiciav1alpha1.ObservedRule{makeObservedRule("pods", "get", "default", time.Now())},
+		},
+	}
+	markCompliancePending(report)
+
+	w := newTestComplianceWorker(role, binding, report)
+	key := types.NamespacedName{Name: "report-sa", Namespace: "default"}
+	if _, err := w.Reconcile(context.Background(), ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	var got audiciav1alpha1.AudiciaReport
+	if err := w.Get(context.Background(), key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.Compliance == nil {
+		t.Fatal("expected compliance to be evaluated")
+	}
+	if got.Status.Compliance.ExcessCount != 1 {
+		t.Errorf("ExcessCount = %d, want 1 (secrets unused)", got.Status.Compliance.ExcessCount)
+	}
+	cond := meta.FindStatusCondition(got.Status.Conditions, complianceCondition)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != "Evaluated" {
+		t.Errorf("ComplianceEvaluated condition = %+v, want True/Evaluated", cond)
+	}
+}
+
+func TestComplianceWorker_AcceptsOnlyPendingReportsInShard(t *testing.T) {
+	pending := &audiciav1alpha1.AudiciaReport{ObjectMeta: metav1.ObjectMeta{Name: "r", Namespace: "ns"}}
+	markCompliancePending(pending)
+	evaluated := pending.DeepCopy()
+	markComplianceEvaluated(evaluated)
+
+	single := &ComplianceWorker{Shards: 1}
+	if !single.accepts(pending) {
+		t.Error("expected single-shard worker to accept pending report")
+	}
+	if single.accepts(evaluated) {
+		t.Error("expected evaluated report to be ignored")
+	}
+
+	const shards = 3
+	owner := shardFor("ns", "r", shards)
+	var accepted int
+	for i := 0; i < shards; i++ {
+		if (&ComplianceWorker{Shard: i, Shards: shards}).accepts(pending) {
+			accepted++
+			if i != owner {
+				t.Errorf("shard %d accepted report owned by shard %d", i, owner)
+			}
+		}
+	}
+	if accepted != 1 {
+		t.Errorf("report accepted by %d shards, want exactly 1", accepted)
+	}
+}
+
+type recordingSink struct {
+	sent chan notify.Notification
+}
+
+func (s *recordingSink) Name() string { return "test" }
+
+func (s *recordingSink) Send(_ context.Context, n notify.Notification) error {
+	s.sent <- n
+	return nil
+}
+
+func TestComplianceWorker_NotifiesSeverityChange(t *testing.T) {
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin", Namespace: "default"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}},
+			{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}},
+		},
+	}
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin", Namespace: "default"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "Role", Name: "admin"},
+		Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: "sa", Namespace: "default"}},
+	}
+	report := &audiciav1alpha1.AudiciaReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "report-sa", Namespace: "default"},
+		Spec: audiciav1alpha1.AudiciaReportSpec{
+			Subject: audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "sa", Namespace: "default"},
+		},
+		Status: audiciav1alpha1.AudiciaReportStatus{
+			ObservedRules: []audiciav1alpha1.ObservedRule{makeObservedRule("pods", "get", "default", time.Now())},
+			Compliance:    &audiciav1alpha1.ComplianceReport{Score: 100, Severity: audiciav1alpha1.ComplianceSeverityGreen},
+		},
+	}
+	markCompliancePending(report)
+
+	sink := &recordingSink{sent: make(chan notify.Notification, 2)}
+	w := newTestComplianceWorker(role, binding, report)
+	w.Notifier = notify.NewDispatcher(sink)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = w.Notifier.Start(ctx) }()
+
+	key := types.NamespacedName{Name: "report-sa", Namespace: "default"}
+	if _, err := w.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	var got []notify.Kind
+	for range 2 {
+		select {
+		case n := <-sink.sent:
+			got = append(got, n.Kind)
+			if n.Kind == notify.KindSeverityChanged && (n.PreviousSeverity != audiciav1alpha1.ComplianceSeverityGreen || n.Severity != audiciav1alpha1.ComplianceSeverityYellow) {
+				t.Errorf("transition = %s -> %s, want Green -> Yellow", n.PreviousSeverity, n.Severity)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %v, want a severity change and sensitive excess", got)
+		}
+	}
+	if got[0] != notify.KindSeverityChanged || got[1] != notify.KindSensitiveExcess {
+		t.Errorf("notifications = %v", got)
+	}
+}
@@ -0,0 +1,94 @@
+package audiciasource
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+)
+
+// configurationHashLength is the number of hex digits of a configuration
+// hash kept on reports.
+const configurationHashLength = 16
+
+// configurationHash returns the hash of the parts of spec that decide which
+// requests become rules and how rules become a policy. Settings that only
+// affect where events come from or how reports are stored, such as the
+// ingestor, checkpoints or limits, are left out, so changing them keeps
+// reports comparable.
+func configurationHash(spec audiciav1alpha1.AudiciaSourceSpec) string {
+	effective := struct {
+		Filters                 []audiciav1alpha1.Filter        `json:"filters"`
+		SubjectAliases          []audiciav1alpha1.SubjectAlias  `json:"subjectAliases"`
+		IgnoreSystemUsers       bool                            `json:"ignoreSystemUsers"`
+		CollapseHousekeeping    bool                            `json:"collapseHousekeeping"`
+		Presets                 string                          `json:"presets,omitempty"`
+		Stages                  []audiciav1alpha1.AuditStage    `json:"stages"`
+		CaptureIncompleteStages bool                            `json:"captureIncompleteStages"`
+		IncludeDenied           bool                            `json:"includeDenied"`
+		ExcludeDryRun           bool                            `json:"excludeDryRun"`
+		Sampling                *audiciav1alpha1.SamplingConfig `json:"sampling"`
+		PolicyStrategy          audiciav1alpha1.PolicyStrategy  `json:"policyStrategy"`
+	}{
+		Filters:                 spec.Filters,
+		SubjectAliases:          spec.SubjectAliases,
+		IgnoreSystemUsers:       spec.IgnoreSystemUsers,
+		CollapseHousekeeping:    spec.CollapseHousekeeping,
+		Stages:                  spec.Stages,
+		CaptureIncompleteStages: spec.CaptureIncompleteStages,
+		IncludeDenied:           spec.IncludeDenied,
+		ExcludeDryRun:           spec.ExcludeDryRun,
+		Sampling:                spec.Sampling,
+		PolicyStrategy:          spec.PolicyStrategy,
+	}
+	if spec.CollapseHousekeeping {
+		effective.Presets = normalizer.PresetsFingerprint()
+	}
+	// The spec only holds JSON-encodable values.
+	data, _ := json.Marshal(effective)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:configurationHashLength]
+}
+
+// pipelineStartTime returns when the pipeline of key started, or now if it
+// is not running.
+func (r *Reconciler) pipelineStartTime(key types.NamespacedName) time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ps, ok := r.pipelines[key]; ok && !ps.startedAt.IsZero() {
+		return ps.startedAt
+	}
+	return time.Now()
+}
+
+// recordConfiguration stamps report with the configuration hash and marks
+// the rules last observed before the configuration took effect. A change of
+// the hash is dated to since, the start of the pipeline running the new
+// configuration. A report stamped for the first time has no known previous
+// configuration, so none of its rules are marked.
+func recordConfiguration(report *audiciav1alpha1.AudiciaReport, hash string, since time.Time) {
+	cfg := report.Status.Configuration
+	switch {
+	case cfg == nil:
+		cfg = &audiciav1alpha1.ReportConfiguration{Hash: hash}
+	case cfg.Hash != hash:
+		changed := metav1.NewTime(since.UTC().Truncate(time.Second))
+		cfg = &audiciav1alpha1.ReportConfiguration{Hash: hash, PreviousHash: cfg.Hash, ChangedTime: &changed}
+	}
+	report.Status.Configuration = cfg
+	markPriorConfiguration(report.Status.ObservedRules, cfg.ChangedTime)
+	markPriorConfiguration(report.Status.DeniedRules, cfg.ChangedTime)
+}
+
+// markPriorConfiguration marks the rules last seen before changed.
+func markPriorConfiguration(rules []audiciav1alpha1.ObservedRule, changed *metav1.Time) {
+	for i := range rules {
+		rules[i].PriorConfiguration = changed != nil && rules[i].LastSeen.Before(changed)
+	}
+}
@@ -0,0 +1,141 @@
+package audiciasource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+	"github.com/felixnotka/audicia/operator/pkg/strategy"
+)
+
+func TestConfigurationHash(t *testing.T) {
+	base := audiciav1alpha1.AudiciaSourceSpec{
+		SourceType: audiciav1alpha1.SourceTypeK8sAuditLog,
+		Filters:    []audiciav1alpha1.Filter{{Action: audiciav1alpha1.FilterActionDeny, UserPattern: "^system:"}},
+	}
+	hash := configurationHash(base)
+	if len(hash) != configurationHashLength {
+		t.Fatalf("hash %q has %d digits, want %d", hash, len(hash), configurationHashLength)
+	}
+	if again := configurationHash(*base.DeepCopy()); again != hash {
+		t.Errorf("hash of an equal spec = %q, want %q", again, hash)
+	}
+
+	unrelated := base.DeepCopy()
+	unrelated.Checkpoint.IntervalSeconds = 60
+	unrelated.Limits.MaxRulesPerReport = 10
+	if got := configurationHash(*unrelated); got != hash {
+		t.Errorf("checkpoint and limits changed the hash to %q", got)
+	}
+
+	for name, change := range map[string]func(*audiciav1alpha1.AudiciaSourceSpec){
+		"filters":              func(s *audiciav1alpha1.AudiciaSourceSpec) { s.Filters[0].UserPattern = "^system:node" },
+		"ignoreSystemUsers":    func(s *audiciav1alpha1.AudiciaSourceSpec) { s.IgnoreSystemUsers = true },
+		"collapseHousekeeping": func(s *audiciav1alpha1.AudiciaSourceSpec) { s.CollapseHousekeeping = true },
+		"includeDenied":        func(s *audiciav1alpha1.AudiciaSourceSpec) { s.IncludeDenied = true },
+		"policyStrategy": func(s *audiciav1alpha1.AudiciaSourceSpec) {
+			s.PolicyStrategy.ScopeMode = audiciav1alpha1.ScopeModeClusterScopeAllowed
+		},
+	} {
+		spec := base.DeepCopy()
+		change(spec)
+		if got := configurationHash(*spec); got == hash {
+			t.Errorf("changing %s kept the hash", name)
+		}
+	}
+}
+
+func TestRecordConfiguration(t *testing.T) {
+	changed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	report := &audiciav1alpha1.AudiciaReport{Status: audiciav1alpha1.AudiciaReportStatus{
+		ObservedRules: []audiciav1alpha1.ObservedRule{
+			{Resources: []string{"pods"}, Verbs: []string{"get"}, LastSeen: metav1.NewTime(changed.Add(-time.Hour))},
+			{Resources: []string{"secrets"}, Verbs: []string{"get"}, LastSeen: metav1.NewTime(changed.Add(time.Minute))},
+		},
+	}}
+
+	recordConfiguration(report, "aaaa", changed.Add(-2*time.Hour))
+	if cfg := report.Status.Configuration; cfg == nil || cfg.Hash != "aaaa" || cfg.ChangedTime != nil {
+		t.Fatalf("first stamp = %+v, want hash aaaa without a change", cfg)
+	}
+	for _, r := range report.Status.ObservedRules {
+		if r.PriorConfiguration {
+			t.Errorf("rule %v marked on the first stamp", r.Resources)
+		}
+	}
+
+	recordConfiguration(report, "bbbb", changed)
+	cfg := report.Status.Configuration
+	if cfg.Hash != "bbbb" || cfg.PreviousHash != "aaaa" || cfg.ChangedTime == nil || !cfg.ChangedTime.Time.Equal(changed) {
+		t.Fatalf("after the change = %+v, want bbbb after aaaa, changed at %v", cfg, changed)
+	}
+	if !report.Status.ObservedRules[0].PriorConfiguration {
+		t.Error("rule last seen before the change is not marked")
+	}
+	if report.Status.ObservedRules[1].PriorConfiguration {
+		t.Error("rule seen after the change is marked")
+	}
+
+	// The same configuration keeps the change and its marks.
+	recordConfiguration(report, "bbbb", changed.Add(time.Hour))
+	if !report.Status.Configuration.ChangedTime.Time.Equal(changed) {
+		t.Errorf("changedTime moved to %v", report.Status.Configuration.ChangedTime)
+	}
+	if !report.Status.ObservedRules[0].PriorConfiguration {
+		t.Error("mark was dropped without a change")
+	}
+}
+
+func TestFlushSubject_MarksRulesOfPriorConfiguration(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "config-source", Namespace: "default", Generation: 1},
+	}
+	key := types.NamespacedName{Namespace: "default", Name: "config-source"}
+	r := newTestReconciler(&source)
+	engine := strategy.NewEngine(audiciav1alpha1.PolicyStrategy{})
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "configured", Namespace: "default"}
+	reportKey := types.NamespacedName{Name: "report-configured", Namespace: "default"}
+
+	agg := aggregator.New()
+	agg.Add(normalizer.CanonicalRule{Resource: "pods", Verb: "get", Namespace: "default"}, time.Now().Add(-time.Hour))
+	if err := r.flushSubject(context.Background(), source, engine, subject, agg, true, logr.Discard()); err != nil {
+		t.Fatalf("flushSubject: %v", err)
+	}
+	var report audiciav1alpha1.AudiciaReport
+	if err := r.Get(context.Background(), reportKey, &report); err != nil {
+		t.Fatalf("get report: %v", err)
+	}
+	before := configurationHash(source.Spec)
+	if report.Status.Configuration == nil || report.Status.Configuration.Hash != before {
+		t.Fatalf("configuration = %+v, want hash %s", report.Status.Configuration, before)
+	}
+
+	// A new generation with another filter chain starts its pipeline now.
+	source.Generation = 2
+	source.Spec.IgnoreSystemUsers = true
+	r.pipelines = map[types.NamespacedName]*pipelineState{key: {generation: 2}}
+	r.pipelineStarted(key, 2)
+	agg.Add(normalizer.CanonicalRule{Resource: "configmaps", Verb: "list", Namespace: "default"}, time.Now().Add(time.Second))
+	if err := r.flushSubject(context.Background(), source, engine, subject, agg, true, logr.Discard()); err != nil {
+		t.Fatalf("flushSubject: %v", err)
+	}
+	if err := r.Get(context.Background(), reportKey, &report); err != nil {
+		t.Fatalf("get report: %v", err)
+	}
+	cfg := report.Status.Configuration
+	if cfg == nil || cfg.Hash != configurationHash(source.Spec) || cfg.PreviousHash != before || cfg.ChangedTime == nil {
+		t.Fatalf("configuration = %+v, want a change from %s", cfg, before)
+	}
+	for _, rule := range report.Status.ObservedRules {
+		if want := rule.Resources[0] == "pods"; rule.PriorConfiguration != want {
+			t.Errorf("rule %v: priorConfiguration = %v, want %v", rule.Resources, rule.PriorConfiguration, want)
+		}
+	}
+}
@@ -1,29 +1,44 @@
 package audiciasource
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"maps"
+	"net/http"
 	"path"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/felixnotka/audicia/operator/pkg/aggregator"
 	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
@@ -31,9 +46,12 @@ import (
 	"github.com/felixnotka/audicia/operator/pkg/filter"
 	"github.com/felixnotka/audicia/operator/pkg/ingestor"
 	"github.com/felixnotka/audicia/operator/pkg/ingestor/cloud"
+	"github.com/felixnotka/audicia/operator/pkg/integrity"
 	"github.com/felixnotka/audicia/operator/pkg/metrics"
 	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+	"github.com/felixnotka/audicia/operator/pkg/notify"
 	"github.com/felixnotka/audicia/operator/pkg/rbac"
+	"github.com/felixnotka/audicia/operator/pkg/rulestream"
 	"github.com/felixnotka/audicia/operator/pkg/strategy"
 )
 
@@ -41,6 +59,19 @@ import (
 type pipelineState struct {
 	cancel     context.CancelFunc
 	generation int64
+
+	// selfTests delivers synthetic events from the self-test endpoint.
+	selfTests    chan selfTestRequest
+	lastSelfTest time.Time
+
+	// failures counts the consecutive failed starts of the pipeline, and
+	// retryAt is when it is next restarted; zero while it runs.
+	failures int
+	retryAt  time.Time
+
+	// startedAt is when the pipeline started, and with it the configuration
+	// of its generation took effect.
+	startedAt time.Time
 }
 
 // Reconciler reconciles AudiciaSource objects.
@@ -50,27 +81,166 @@ type Reconciler struct {
 	Resolver *rbac.Resolver
 	Recorder events.EventRecorder
 
+	// DeferCompliance leaves compliance evaluation to separate compliance
+	// workers: flushed reports are marked pending instead of evaluated inline.
+	DeferCompliance bool
+
+	// LocalIngestion permits Local sources, which receive events without TLS
+	// or authentication. Only enabled for development clusters.
+	LocalIngestion bool
+
+	// Generator identifies this operator build on the reports, policies and
+	// manifests it writes.
+	Generator audiciav1alpha1.GeneratorInfo
+
+	// Notifier sends compliance changes of the reports to the configured
+	// notification sinks. Nil when none are configured.
+	Notifier *notify.Dispatcher
+
+	// ReportHooks delivers material report changes to the webhooks of their
+	// source (spec.notifications.webhooks). Nil discards them.
+	ReportHooks *notify.ReportDispatcher
+
+	// RuleStream receives every rule as it is aggregated, for clients of
+	// the gRPC rule stream. Nil when the stream is disabled.
+	RuleStream *rulestream.Hub
+
+	// PipelineWorkers is the number of event workers and aggregator shards
+	// of each source's pipeline. With 1 or less, events are processed on
+	// the pipeline goroutine.
+	PipelineWorkers int
+
+	// FlushConcurrency is the number of subjects of a source flushed in
+	// parallel. With 1 or less, subjects are flushed one at a time.
+	FlushConcurrency int
+
+	// FlushLimiter paces subject flushes across all sources, so a burst of
+	// new subjects does not flood the API server. Nil means unlimited.
+	FlushLimiter *rate.Limiter
+
+	// Verbs knows the verbs each resource supports, so suggested policies
+	// leave out the others. Nil disables the check.
+	Verbs strategy.VerbCatalog
+
+	// PolicySinkGitURLs are the repositories of spec.policySink.git the
+	// operator's Git credentials may be sent to.
+	PolicySinkGitURLs []string
+
+	// requeue triggers the reconcile of a source whose pipeline failed to
+	// start. Nil when the controller is not registered with a manager.
+	requeue chan event.GenericEvent
+
+	// newIngestor, when set, replaces createIngestor; the load test feeds
+	// pipelines from a synthetic ingestor through it.
+	newIngestor func(audiciav1alpha1.AudiciaSource) (ingestor.Ingestor, error)
+
+	// crds holds back pipelines while the installed CRDs are incompatible.
+	crds crdCompatibility
+
 	mu        sync.Mutex
 	pipelines map[types.NamespacedName]*pipelineState
 }
 
+// SetupOptions configures the AudiciaSource controller. The zero value
+// reconciles one source at a time and processes and flushes each source's
+// events on its pipeline goroutine.
+type SetupOptions struct {
+	// MaxConcurrentReconciles is the number of sources reconciled in
+	// parallel; less than 1 means 1.
+	MaxConcurrentReconciles int
+
+	// DeferCompliance queues reports for the compliance worker instead of
+	// evaluating them in the ingestion pipeline.
+	DeferCompliance bool
+
+	// LocalIngestion permits Local sources.
+	LocalIngestion bool
+
+	// Generator is stamped on every generated artifact.
+	Generator audiciav1alpha1.GeneratorInfo
+
+	// Notifier, ReportHooks and RuleStream may be nil; see the Reconciler
+	// fields of the same names.
+	Notifier    *notify.Dispatcher
+	ReportHooks *notify.ReportDispatcher
+	RuleStream  *rulestream.Hub
+
+	// PipelineWorkers is the number of event workers per source.
+	PipelineWorkers int
+
+	// FlushConcurrency subjects of a source are flushed in parallel, at
+	// most FlushQPS per second across all sources (0 for no limit).
+	FlushConcurrency int
+	FlushQPS         int
+
+	// Verbs, when not nil, drops verbs resources do not support from
+	// suggested policies.
+	Verbs strategy.VerbCatalog
+
+	// Resolver resolves effective permissions for compliance. Nil uses the
+	// manager's client without implicit groups.
+	Resolver *rbac.Resolver
+
+	// PolicySinkGitURLs are the repositories the Git credentials may be
+	// sent to.
+	PolicySinkGitURLs []string
+}
+
 // SetupWithManager registers the AudiciaSource controller with the manager.
-func SetupWithManager(mgr ctrl.Manager, maxConcurrent int) error {
-	if maxConcurrent < 1 {
-		maxConcurrent = 1
-	}
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&audiciav1alpha1.AudiciaSource{}).
-		Owns(&audiciav1alpha1.AudiciaReport{}).
-		Owns(&audiciav1alpha1.AudiciaPolicy{}).
+func SetupWithManager(mgr ctrl.Manager, opts SetupOptions) error {
+	maxConcurrent := max(opts.MaxConcurrentReconciles, 1)
+	resolver := opts.Resolver
+	if resolver == nil {
+		resolver = rbac.NewResolver(mgr.GetClient())
+	}
+	var limiter *rate.Limiter
+	if opts.FlushQPS > 0 {
+		limiter = rate.NewLimiter(rate.Limit(opts.FlushQPS), max(opts.FlushConcurrency, 1))
+	}
+	r := &Reconciler{
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		Resolver:          resolver,
+		Recorder:          mgr.GetEventRecorder("audicia-operator"),
+		DeferCompliance:   opts.DeferCompliance,
+		LocalIngestion:    opts.LocalIngestion,
+		Generator:         opts.Generator,
+		Notifier:          opts.Notifier,
+		ReportHooks:       opts.ReportHooks,
+		RuleStream:        opts.RuleStream,
+		PipelineWorkers:   opts.PipelineWorkers,
+		FlushConcurrency:  opts.FlushConcurrency,
+		FlushLimiter:      limiter,
+		Verbs:             opts.Verbs,
+		PolicySinkGitURLs: opts.PolicySinkGitURLs,
+		requeue:           make(chan event.GenericEvent),
+		pipelines:         make(map[types.NamespacedName]*pipelineState),
+	}
+	if err := mgr.AddMetricsServerExtraHandler(SelfTestPath, newSelfTestHandler(r)); err != nil {
+		return fmt.Errorf("registering self-test endpoint: %w", err)
+	}
+	if err := mgr.Add(manager.RunnableFunc(r.checkCRDsAtStartup)); err != nil {
+		return fmt.Errorf("registering CRD compatibility check: %w", err)
+	}
+	b := ctrl.NewControllerManagedBy(mgr).For(&audiciav1alpha1.AudiciaSource{})
+	// Watching a kind the API server does not serve would keep the manager
+	// from starting. Without the watch, pipelines still run and report the
+	// missing CRD in their ReportCRDReady condition.
+	for _, owned := range []client.Object{&audiciav1alpha1.AudiciaReport{}, &audiciav1alpha1.AudiciaPolicy{}} {
+		gvk, err := apiutil.GVKForObject(owned, mgr.GetScheme())
+		if err != nil {
+			return err
+		}
+		if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); meta.IsNoMatchError(err) {
+			ctrl.Log.WithName("setup").Info("CRD not installed, not watching it; restart the operator after installing it", "kind", gvk.Kind)
+			continue
+		}
+		b = b.Owns(owned)
+	}
+	return b.
+		WatchesRawSource(source.Channel(r.requeue, &handler.EnqueueRequestForObject{})).
 		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrent}).
-		Complete(&Reconciler{
-			Client:    mgr.GetClient(),
-			Scheme:    mgr.GetScheme(),
-			Resolver:  rbac.NewResolver(mgr.GetClient()),
-			Recorder:  mgr.GetEventRecorder("audicia-operator"),
-			pipelines: make(map[types.NamespacedName]*pipelineState),
-		})
+		Complete(r)
 }
 
 // Reconcile handles a single reconciliation for an AudiciaSource resource.
@@ -89,13 +259,31 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
+	// Pipelines write fields the API server would silently prune from
+	// outdated CRDs, so none starts until the CRDs are compatible.
+	message, unknown := r.crdIncompatibility(ctx, time.Now())
+	r.recordCRDCompatibility(ctx, &source, message, unknown)
+	if message != "" {
+		return ctrl.Result{RequeueAfter: reportCRDRecheckInterval}, nil
+	}
+
 	// Check if pipeline is already running for this source.
 	r.mu.Lock()
 	existing, running := r.pipelines[req.NamespacedName]
+	var failures int
 	if running && existing.generation == source.Generation {
-		// Pipeline is running and spec hasn't changed — nothing to do.
-		r.mu.Unlock()
-		return ctrl.Result{}, nil
+		if existing.retryAt.IsZero() {
+			// Pipeline is running and spec hasn't changed — nothing to do.
+			r.mu.Unlock()
+			return ctrl.Result{}, nil
+		}
+		// The pipeline failed to start: restart it once its backoff has
+		// elapsed.
+		if wait := time.Until(existing.retryAt); wait > 0 {
+			r.mu.Unlock()
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+		failures = existing.failures
 	}
 	r.mu.Unlock()
 
@@ -108,9 +296,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	pipelineCtx, cancel := context.WithCancel(context.Background())
 
 	r.mu.Lock()
+	selfTests := make(chan selfTestRequest)
 	r.pipelines[req.NamespacedName] = &pipelineState{
 		cancel:     cancel,
 		generation: source.Generation,
+		selfTests:  selfTests,
+		failures:   failures,
 	}
 	r.mu.Unlock()
 
@@ -125,7 +316,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		logger.Error(err, "failed to set starting condition")
 	}
 
-	go r.runPipeline(pipelineCtx, req.NamespacedName, source)
+	go r.runPipeline(pipelineCtx, req.NamespacedName, source, selfTests)
 
 	logger.Info("pipeline started", "sourceType", source.Spec.SourceType)
 	r.Recorder.Eventf(&source, nil, corev1.EventTypeNormal, "PipelineStarted", "Start",
@@ -144,33 +335,104 @@ func (r *Reconciler) stopPipeline(key types.NamespacedName) {
 }
 
 // runPipeline runs the full ingestion pipeline for a single AudiciaSource.
-func (r *Reconciler) runPipeline(ctx context.Context, key types.NamespacedName, source audiciav1alpha1.AudiciaSource) {
+func (r *Reconciler) runPipeline(ctx context.Context, key types.NamespacedName, source audiciav1alpha1.AudiciaSource, selfTests <-chan selfTestRequest) {
 	logger := ctrl.Log.WithName("pipeline").WithValues("source", key)
 
 	// 1. Create the ingestor based on source type.
-	ing, err := createIngestor(source, logger)
+	if source.Spec.SourceType == audiciav1alpha1.SourceTypeLocal && !r.LocalIngestion {
+		r.failPipeline(ctx, key, source, "LocalIngestionDisabled",
+			"Local sources accept events without TLS and are disabled. Set LOCAL_INGESTION_ENABLED=true on development clusters only.")
+		return
+	}
+	var ing ingestor.Ingestor
+	var err error
+	if r.newIngestor != nil {
+		ing, err = r.newIngestor(source)
+	} else {
+		ing, err = createIngestor(source, r.Client, logger)
+	}
 	if err != nil {
+		r.failPipelineErr(ctx, key, source, "IngestorInvalid", err)
 		return
 	}
 
 	// 2. Create the filter chain.
 	filterChain, err := filter.NewChain(source.Spec.Filters)
 	if err != nil {
-		logger.Error(err, "failed to compile filter chain")
+		r.failPipelineErr(ctx, key, source, "FiltersInvalid", fmt.Errorf("failed to compile filter chain: %w", err))
+		return
+	}
+
+	// 3. Compile subject aliases.
+	aliases, err := normalizer.NewSubjectAliases(source.Spec.SubjectAliases)
+	if err != nil {
+		r.failPipelineErr(ctx, key, source, "SubjectAliasesInvalid", fmt.Errorf("failed to compile subject aliases: %w", err))
+		return
+	}
+
+	// 4. Compile group tracking.
+	groups, err := normalizer.NewGroupTracker(source.Spec.SubjectTracking, source.Spec.IgnoreSystemUsers)
+	if err != nil {
+		r.failPipelineErr(ctx, key, source, "SubjectTrackingInvalid", fmt.Errorf("failed to compile group tracking: %w", err))
+		return
+	}
+
+	// 5. Check the activity time zone.
+	if _, err := activityLocation(source.Spec.ActivityTimeZone); err != nil {
+		r.failPipelineErr(ctx, key, source, "ActivityTimeZoneInvalid", fmt.Errorf("invalid activityTimeZone: %w", err))
+		return
+	}
+
+	// 6. Check the sampling rate.
+	if _, err := parseSamplingRate(source.Spec.Sampling); err != nil {
+		r.failPipelineErr(ctx, key, source, "SamplingInvalid", fmt.Errorf("invalid spec.sampling: %w", err))
 		return
 	}
 
-	// 3. Create the strategy engine.
+	// 7. Create the strategy engine.
 	engine := strategy.NewEngine(source.Spec.PolicyStrategy)
+	engine.Generator = r.Generator
+	engine.Verbs = r.Verbs
+	if m := source.Spec.Metadata; m != nil {
+		// Keys of spec.policyStrategy.naming win over spec.metadata.
+		engine.Labels = mergeMetadata(m.Labels, engine.Labels)
+		engine.Annotations = mergeMetadata(m.Annotations, engine.Annotations)
+	}
+	apiVersion, err := strategy.ResolveRBACAPIVersion(source.Spec.PolicyStrategy.RBACAPIVersion, r.servedRBACVersions())
+	if err != nil {
+		r.failPipeline(ctx, key, source, "UnsupportedRBACVersion",
+			err.Error()+". Set spec.policyStrategy.rbacAPIVersion to override.")
+		return
+	}
+	engine.APIVersion = apiVersion
 
-	// 4. Start ingestion.
+	// 8. Start ingestion.
+	history := newErrorHistory(source)
+	if reporter, ok := ing.(ingestor.ErrorReporter); ok {
+		reporter.OnError(history.reporter())
+	}
+	if prober, ok := ing.(ingestor.Prober); ok {
+		prober.OnProbe(r.reachabilityReporter(ctx, key, source))
+	}
 	events, err := ing.Start(ctx)
+	var tlsErr *ingestor.TLSConfigError
+	if stderrors.As(err, &tlsErr) {
+		r.failPipelineErr(ctx, key, source, "TLSConfigInvalid", err)
+		go r.retryOnChange(ctx, key, source, tlsErr.Files, mountedFilePollInterval)
+		return
+	}
 	if err != nil {
-		logger.Error(err, "failed to start ingestor")
+		r.failPipelineErr(ctx, key, source, "IngestorStartFailed", fmt.Errorf("failed to start ingestor: %w", err))
 		return
 	}
 
 	// Set Ready condition.
+	r.pipelineStarted(key, source.Generation)
+	var listenAddress string
+	if listener, ok := ing.(ingestor.Listener); ok {
+		listenAddress = listener.ListenAddress()
+	}
+	r.recordListenAddress(ctx, key, listenAddress)
 	r.setSourceCondition(ctx, key, metav1.Condition{
 		Type:               "Ready",
 		Status:             metav1.ConditionTrue,
@@ -179,19 +441,26 @@ func (r *Reconciler) runPipeline(ctx context.Context, key types.NamespacedName,
 		ObservedGeneration: source.Generation,
 	})
 
-	// 5. Process events through the pipeline.
-	r.eventLoop(ctx, key, source, engine, filterChain, ing, events)
+	// 9. Process events through the pipeline.
+	r.eventLoop(ctx, key, source, engine, filterChain, aliases, groups, ing, events, selfTests, history)
 }
 
-// createIngestor builds the appropriate ingestor for the source type.
-func createIngestor(source audiciav1alpha1.AudiciaSource, logger logr.Logger) (ingestor.Ingestor, error) {
+// createIngestor builds the appropriate ingestor for the source type. c is
+// used by webhook sources that authenticate callers via TokenReview.
+func createIngestor(source audiciav1alpha1.AudiciaSource, c client.Client, logger logr.Logger) (ingestor.Ingestor, error) {
 	switch source.Spec.SourceType {
 	case audiciav1alpha1.SourceTypeK8sAuditLog:
 		return createFileIngestor(source, logger)
 	case audiciav1alpha1.SourceTypeWebhook:
-		return createWebhookIngestor(source, logger)
+		return createWebhookIngestor(source, c, logger)
 	case audiciav1alpha1.SourceTypeCloudAuditLog:
 		return createCloudIngestor(source, logger)
+	case audiciav1alpha1.SourceTypeLocal:
+		return createLocalIngestor(source, logger)
+	case audiciav1alpha1.SourceTypeForward:
+		return createForwardIngestor(source, logger)
+	case audiciav1alpha1.SourceTypeGrpc:
+		return createGrpcIngestor(source, c, logger)
 	default:
 		logger.Error(nil, "unknown source type", "sourceType", source.Spec.SourceType)
 		return nil, fmt.Errorf("unknown source type: %s", source.Spec.SourceType)
@@ -203,18 +472,38 @@ func createFileIngestor(source audiciav1alpha1.AudiciaSource, logger logr.Logger
 		logger.Error(nil, "K8sAuditLog source requires location config")
 		return nil, fmt.Errorf("K8sAuditLog source requires location config")
 	}
-	startPos := ingestor.Position{
-		FileOffset: source.Status.FileOffset,
-		Inode:      source.Status.Inode,
-	}
 	batchSize := int(source.Spec.Checkpoint.BatchSize)
 	if batchSize == 0 {
 		batchSize = 500
 	}
-	return ingestor.NewFileIngestor(source.Spec.Location.Path, startPos, batchSize), nil
+
+	if ingestor.IsGlobPattern(source.Spec.Location.Path) {
+		positions := make(map[string]ingestor.Position, len(source.Status.Files))
+		for _, f := range source.Status.Files {
+			positions[f.Path] = ingestor.Position{FileOffset: f.FileOffset, Inode: f.Inode}
+		}
+		ing := ingestor.NewMultiFileIngestor(source.Spec.Location.Path, positions, batchSize)
+		ing.RotatedFilePattern = source.Spec.Location.RotatedFilePattern
+		ing.SourceLabel = metricsLabel(source)
+		return ing, nil
+	}
+
+	startPos := ingestor.Position{
+		FileOffset: source.Status.FileOffset,
+		Inode:      source.Status.Inode,
+	}
+	ing := ingestor.NewFileIngestor(source.Spec.Location.Path, startPos, batchSize)
+	ing.RotatedFilePattern = source.Spec.Location.RotatedFilePattern
+	ing.SourceLabel = metricsLabel(source)
+	return ing, nil
+}
+
+// metricsLabel is the source label of the source's metrics, namespace/name.
+func metricsLabel(source audiciav1alpha1.AudiciaSource) string {
+	return types.NamespacedName{Namespace: source.Namespace, Name: source.Name}.String()
 }
 
-func createWebhookIngestor(source audiciav1alpha1.AudiciaSource, logger logr.Logger) (ingestor.Ingestor, error) {
+func createWebhookIngestor(source audiciav1alpha1.AudiciaSource, c client.Client, logger logr.Logger) (ingestor.Ingestor, error) {
 	if source.Spec.Webhook == nil {
 		logger.Error(nil, "Webhook source requires webhook config")
 		return nil, fmt.Errorf("webhook source requires webhook config")
@@ -232,8 +521,14 @@ func createWebhookIngestor(source audiciav1alpha1.AudiciaSource, logger logr.Log
 		source.Spec.Webhook.Port,
 		tlsCertFile, tlsKeyFile,
 	)
+	if _, _, err := ingestor.ListenNetwork(source.Spec.Webhook.BindAddress, source.Spec.Webhook.Port); err != nil {
+		return nil, fmt.Errorf("invalid webhook bindAddress: %w", err)
+	}
+	wh.BindAddress = source.Spec.Webhook.BindAddress
 	wh.MaxRequestBodyBytes = source.Spec.Webhook.MaxRequestBodyBytes
 	wh.RateLimitPerSecond = source.Spec.Webhook.RateLimitPerSecond
+	wh.PerClientRateLimitPerSecond = source.Spec.Webhook.PerClientRateLimitPerSecond
+	wh.SourceLabel = metricsLabel(source)
 
 	// Optional mTLS: if a client CA Secret is specified, mount its ca.crt
 	// and configure the webhook server to require client certificates.
@@ -242,16 +537,142 @@ func createWebhookIngestor(source audiciav1alpha1.AudiciaSource, logger logr.Log
 		wh.ClientCAFile = path.Join(clientCAMountPath, "ca.crt")
 	}
 
+	// Optional bearer token auth: in-cluster forwarders present their
+	// ServiceAccount token, validated against the local API server.
+	tokenReview := false
+	if auth := source.Spec.Webhook.Authentication; auth != nil && auth.Mode == audiciav1alpha1.WebhookAuthModeTokenReview {
+		if c == nil {
+			return nil, fmt.Errorf("webhook TokenReview authentication requires a Kubernetes client")
+		}
+		wh.Authenticator = ingestor.NewTokenReviewAuthenticator(c, source.Namespace, source.Name, auth.Audiences)
+		tokenReview = true
+	}
+
+	// Optional static token: the Helm chart mounts the Secret named in
+	// spec.webhook.authTokenSecretName at /etc/audicia/webhook-token.
+	if source.Spec.Webhook.AuthTokenSecretName != "" {
+		if tokenReview {
+			logger.Error(nil, "webhook authTokenSecretName and TokenReview authentication are mutually exclusive")
+			return nil, fmt.Errorf("webhook authTokenSecretName and TokenReview authentication are mutually exclusive")
+		}
+		const tokenMountPath = "/etc/audicia/webhook-token"
+		wh.Authenticator = ingestor.NewStaticTokenAuthenticator(path.Join(tokenMountPath, "token"))
+	}
+
 	return wh, nil
 }
 
+func createLocalIngestor(source audiciav1alpha1.AudiciaSource, logger logr.Logger) (ingestor.Ingestor, error) {
+	if source.Spec.Local == nil {
+		logger.Error(nil, "Local source requires local config")
+		return nil, fmt.Errorf("local source requires local config")
+	}
+	return ingestor.NewLocalIngestor(source.Spec.Local.SocketPath, source.Spec.Local.Port), nil
+}
+
+func createForwardIngestor(source audiciav1alpha1.AudiciaSource, logger logr.Logger) (ingestor.Ingestor, error) {
+	cfg := source.Spec.Forward
+	if cfg == nil {
+		logger.Error(nil, "Forward source requires forward config")
+		return nil, fmt.Errorf("forward source requires forward config")
+	}
+	protocol := cfg.Protocol
+	if protocol == "" {
+		protocol = audiciav1alpha1.ForwardProtocolFluentd
+	}
+
+	fw := ingestor.NewForwardIngestor(string(protocol), cfg.Port)
+	if cfg.MaxMessageBytes > 0 {
+		fw.MaxMessageBytes = cfg.MaxMessageBytes
+	}
+
+	// TLS material is mounted by the Helm chart from the Secrets named in
+	// spec.forward, following the webhook's path convention.
+	if cfg.TLSSecretName != "" {
+		const tlsMountPath = "/etc/audicia/forward-tls"
+		fw.TLSCertFile = path.Join(tlsMountPath, "tls.crt")
+		fw.TLSKeyFile = path.Join(tlsMountPath, "tls.key")
+	}
+	if cfg.ClientCASecretName != "" {
+		if cfg.TLSSecretName == "" {
+			logger.Error(nil, "forward clientCASecretName requires tlsSecretName")
+			return nil, fmt.Errorf("forward clientCASecretName requires tlsSecretName")
+		}
+		const clientCAMountPath = "/etc/audicia/forward-client-ca"
+		fw.ClientCAFile = path.Join(clientCAMountPath, "ca.crt")
+	}
+	return fw, nil
+}
+
+func createGrpcIngestor(source audiciav1alpha1.AudiciaSource, c client.Client, logger logr.Logger) (ingestor.Ingestor, error) {
+	cfg := source.Spec.Grpc
+	if cfg == nil {
+		logger.Error(nil, "Grpc source requires grpc config")
+		return nil, fmt.Errorf("grpc source requires grpc config")
+	}
+
+	g := ingestor.NewGrpcIngestor(cfg.Port)
+	if cfg.MaxMessageBytes > 0 {
+		g.MaxMessageBytes = int(cfg.MaxMessageBytes)
+	}
+	g.SourceLabel = metricsLabel(source)
+
+	// TLS material and the token are mounted by the Helm chart from the
+	// Secrets named in spec.grpc, following the webhook's path convention.
+	if cfg.TLSSecretName != "" {
+		const tlsMountPath = "/etc/audicia/grpc-tls"
+		g.TLSCertFile = path.Join(tlsMountPath, "tls.crt")
+		g.TLSKeyFile = path.Join(tlsMountPath, "tls.key")
+	}
+	if cfg.ClientCASecretName != "" {
+		if cfg.TLSSecretName == "" {
+			logger.Error(nil, "grpc clientCASecretName requires tlsSecretName")
+			return nil, fmt.Errorf("grpc clientCASecretName requires tlsSecretName")
+		}
+		const clientCAMountPath = "/etc/audicia/grpc-client-ca"
+		g.ClientCAFile = path.Join(clientCAMountPath, "ca.crt")
+	}
+
+	tokenReview := false
+	if auth := cfg.Authentication; auth != nil && auth.Mode == audiciav1alpha1.WebhookAuthModeTokenReview {
+		if c == nil {
+			return nil, fmt.Errorf("grpc TokenReview authentication requires a Kubernetes client")
+		}
+		g.Authenticator = ingestor.NewTokenReviewAuthenticator(c, source.Namespace, source.Name, auth.Audiences)
+		tokenReview = true
+	}
+	if cfg.AuthTokenSecretName != "" {
+		if tokenReview {
+			logger.Error(nil, "grpc authTokenSecretName and TokenReview authentication are mutually exclusive")
+			return nil, fmt.Errorf("grpc authTokenSecretName and TokenReview authentication are mutually exclusive")
+		}
+		const tokenMountPath = "/etc/audicia/grpc-token"
+		g.Authenticator = ingestor.NewStaticTokenAuthenticator(path.Join(tokenMountPath, "token"))
+	}
+	return g, nil
+}
+
 func createCloudIngestor(source audiciav1alpha1.AudiciaSource, logger logr.Logger) (ingestor.Ingestor, error) {
 	if source.Spec.Cloud == nil {
 		logger.Error(nil, "CloudAuditLog source requires cloud config")
 		return nil, fmt.Errorf("CloudAuditLog source requires cloud config")
 	}
 
-	msgSource, parser, err := cloud.BuildAdapter(source.Spec.Cloud)
+	strict := source.Spec.Cloud.ClusterIdentityEnforcement == audiciav1alpha1.ClusterIdentityEnforcementStrict
+	if strict {
+		switch source.Spec.Cloud.Provider {
+		case audiciav1alpha1.CloudProviderAWSCloudWatch, audiciav1alpha1.CloudProviderAWSS3:
+			return nil, fmt.Errorf("clusterIdentityEnforcement Strict is not supported for %s: its events carry no cluster identity", source.Spec.Cloud.Provider)
+		}
+	}
+
+	if foreign := cloud.ForeignProviderSettings(source.Spec.Cloud); len(foreign) > 0 {
+		logger.Info("ignoring cloud settings of providers other than the selected one; remove them, as the CRD now rejects them",
+			"provider", source.Spec.Cloud.Provider, "ignored", foreign)
+	}
+
+	id := cloud.SourceIdentity{Namespace: source.Namespace, Name: source.Name}
+	msgSource, parser, err := cloud.BuildAdapter(source.Spec.Cloud, id)
 	if err != nil {
 		logger.Error(err, "failed to build cloud adapter", "provider", source.Spec.Cloud.Provider)
 		return nil, fmt.Errorf("building cloud adapter: %w", err)
@@ -263,6 +684,7 @@ func createCloudIngestor(source audiciav1alpha1.AudiciaSource, logger logr.Logge
 	if source.Spec.Cloud.ClusterIdentity != "" {
 		validator = &cloud.ClusterIdentityValidator{
 			ExpectedIdentity: source.Spec.Cloud.ClusterIdentity,
+			Strict:           strict,
 		}
 	}
 
@@ -288,12 +710,16 @@ func (r *Reconciler) eventLoop(
 	source audiciav1alpha1.AudiciaSource,
 	engine *strategy.Engine,
 	filterChain *filter.Chain,
+	aliases *normalizer.SubjectAliases,
+	groups *normalizer.GroupTracker,
 	ing ingestor.Ingestor,
 	events <-chan auditv1.Event,
+	selfTests <-chan selfTestRequest,
+	history *errorHistory,
 ) {
 	logger := ctrl.Log.WithName("pipeline").WithValues("source", key)
-	aggregators := make(map[string]*aggregator.Aggregator)
-	subjects := make(map[string]audiciav1alpha1.Subject)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
 
 	checkpointInterval := time.Duration(source.Spec.Checkpoint.IntervalSeconds) * time.Second
 	if checkpointInterval == 0 {
@@ -302,16 +728,53 @@ func (r *Reconciler) eventLoop(
 	checkpointTicker := time.NewTicker(checkpointInterval)
 	defer checkpointTicker.Stop()
 
+	// Failed subjects are retried on their own backoff schedule, so they
+	// recover even when no new events mark the pipeline dirty.
+	retries := newFlushRetryQueue()
+	contention := newReportContention()
+	retryTimer := time.NewTimer(time.Hour)
+	retryTimer.Stop()
+	defer retryTimer.Stop()
+	var retryC <-chan time.Time
+
+	// source.Spec.Limits holds the limits in force, which lag behind
+	// spec.limits while a change waits out its grace period.
+	specLimits := source.Spec.Limits
+
 	dirty := false
+	pendingSweep := source.Spec.PendingReports != nil
+	gaps := newGapDetector(source)
+	dedup := newEventDeduplicator(source)
+	filtered := newFilteredTracker(source, time.Now())
+	stamps := newTimestampTracker(source)
+	var lastExpiry time.Time
+	admission := newSubjectAdmission(source)
+	publisher := r.newPolicyPublisher(key, newPolicySink(source, r.PolicySinkGitURLs), history)
+	defer publisher.wait()
+	provenance := newProvenanceTracker(source, ing)
+	evictions := newEvictionTracker(source)
+	versions := newReportVersions()
+	workers := r.newEventWorkers(r.PipelineWorkers, source, filterChain, aliases, groups, aggregators, subjects, filtered)
+	defer workers.stop()
+	crds := r.newReportCRDGate(ctx, key, source)
 
 	for {
 		select {
 		case <-ctx.Done():
 			// Pipeline shutting down. Do a final flush.
-			if dirty {
-				r.flushReports(context.Background(), key, source, engine, aggregators, subjects)
-				r.flushCheckpoint(context.Background(), key, ing)
+			if dirty && crds.reason == "" {
+				workers.sync(aggregators, subjects)
+				provenance.attribute(aggregators)
+				source.Spec.Limits = r.resolveLimits(context.Background(), key, specLimits, aggregators)
+				changed, _ := versions.changed(admission.filter(aggregators, subjects), source.Spec.Limits, time.Now())
+				result := r.flushReports(context.Background(), key, source, engine, changed, subjects)
+				history.recordFlush(result, time.Now())
+				r.recordExcludedSubjects(context.Background(), key, admission)
+				history.record(audiciav1alpha1.PipelineErrorCheckpoint, r.flushCheckpoint(context.Background(), key, ing, gaps), time.Now())
+				r.recordFilteredEvents(context.Background(), key, filtered)
+				r.recordEventTimestamps(context.Background(), key, stamps)
 			}
+			r.recordErrors(context.Background(), key, history)
 			return
 
 		case event, ok := <-events:
@@ -321,180 +784,361 @@ func (r *Reconciler) eventLoop(
 				return
 			}
 
-			r.processEvent(event, source, filterChain, aggregators, subjects)
+			if dedup != nil && dedup.Seen(event) {
+				metrics.EventsFilteredTotal.WithLabelValues("duplicate").Inc()
+				continue
+			}
+			gaps.observe(eventTime(event))
+			stamps.observe(event, time.Now())
+			workers.dispatch(event)
 			dirty = true
+			if source.Spec.PendingReports != nil && isProvisioningEvent(event) {
+				pendingSweep = true
+			}
+
+		case req := <-selfTests:
+			req.done <- r.processSelfTest(ctx, key, source, engine, req.events)
 
 		case <-checkpointTicker.C:
+			workers.sync(aggregators, subjects)
+			if !crds.ready(ctx, time.Now()) {
+				r.recordErrors(ctx, key, history)
+				continue
+			}
+			if pendingSweep {
+				pendingSweep = !r.sweepPendingReports(ctx, source, filterChain, subjects, logger)
+			}
+			if specLimits.ReportTTLDays > 0 && time.Since(lastExpiry) >= reportExpiryInterval {
+				if _, err := r.expireReports(ctx, key, source, specLimits, aggregators, subjects, time.Now(), logger); err != nil {
+					logger.Error(err, "failed to expire reports")
+					history.record(audiciav1alpha1.PipelineErrorExpiry, err, time.Now())
+				} else {
+					lastExpiry = time.Now()
+				}
+				workers.retain(aggregators)
+			}
+			r.recordEvictions(ctx, key, evictions, workers.evictions())
 			if !dirty {
+				r.recordErrors(ctx, key, history)
 				continue
 			}
 			start := time.Now()
-			r.flushReports(ctx, key, source, engine, aggregators, subjects)
-			r.flushCheckpoint(ctx, key, ing)
+			provenance.attribute(aggregators)
+			source.Spec.Limits = r.resolveLimits(ctx, key, specLimits, aggregators)
+			changed, unchanged := versions.changed(admission.filter(aggregators, subjects), source.Spec.Limits, time.Now())
+			changed, deferred := versions.limit(changed, r.flushBudget(checkpointInterval))
+			result := r.flushReports(ctx, key, source, engine, changed, subjects)
+			result.unchanged, result.deferred = unchanged, deferred
+			versions.record(aggregators, result)
+			workers.drained(aggregators, subjects, versions.current)
+			history.recordFlush(result, time.Now())
+			r.recordExcludedSubjects(ctx, key, admission)
+			r.recordFlushResult(ctx, key, result, retries)
+			r.recordContention(ctx, key, result, contention)
+			history.record(audiciav1alpha1.PipelineErrorCheckpoint, r.flushCheckpoint(ctx, key, ing, gaps), time.Now())
+			r.recordFilteredEvents(ctx, key, filtered)
+			r.recordEventTimestamps(ctx, key, stamps)
 			metrics.PipelineLatencySeconds.Observe(time.Since(start).Seconds())
-			dirty = false
+			publisher.publish(ctx)
+			r.recordErrors(ctx, key, history)
+			// Deferred subjects are flushed on the next tick even if no
+			// events arrive.
+			dirty = deferred > 0
+			retryC = retries.arm(retryTimer, time.Now())
+
+		case <-retryC:
+			if !crds.ready(ctx, time.Now()) {
+				retryC = retries.arm(retryTimer, time.Now())
+				continue
+			}
+			workers.sync(aggregators, subjects)
+			result := r.retryFlushes(ctx, key, source, engine, aggregators, subjects, retries.due(time.Now()))
+			versions.record(aggregators, result)
+			history.recordFlush(result, time.Now())
+			r.recordFlushResult(ctx, key, result, retries)
+			r.recordContention(ctx, key, result, contention)
+			retryC = retries.arm(retryTimer, time.Now())
 		}
 	}
 }
 
-// processEvent runs a single audit event through filter -> normalizer -> aggregator.
+// newEventDeduplicator returns nil when spec.deduplication disables it.
+func newEventDeduplicator(source audiciav1alpha1.AudiciaSource) *ingestor.EventDeduplicator {
+	cfg := source.Spec.Deduplication
+	if cfg == nil {
+		return ingestor.NewEventDeduplicator(ingestor.DefaultDeduplicationWindow)
+	}
+	if cfg.Disabled {
+		return nil
+	}
+	return ingestor.NewEventDeduplicator(int(cfg.WindowSize))
+}
+
+// processEvent runs a single audit event through the standard processors of
+// the source (see newEventBus). It returns the filter_rule label of the
+// processor that dropped the event, or "" if the event was aggregated.
 func (r *Reconciler) processEvent(
 	event auditv1.Event,
 	source audiciav1alpha1.AudiciaSource,
 	filterChain *filter.Chain,
-	aggregators map[string]*aggregator.Aggregator,
-	subjects map[string]audiciav1alpha1.Subject,
-) {
-	username := ""
-	if event.User.Username != "" {
-		username = event.User.Username
+	aliases *normalizer.SubjectAliases,
+	groups *normalizer.GroupTracker,
+	aggregators map[subjectKey]*aggregator.Aggregator,
+	subjects map[subjectKey]audiciav1alpha1.Subject,
+) string {
+	return r.newEventBus(source, filterChain, aliases, groups, aggregateRule(aggregators, subjects, nil)).publish(event)
+}
+
+// streamRule publishes an aggregated rule to the clients of the rule stream.
+func (r *Reconciler) streamRule(source audiciav1alpha1.AudiciaSource, subject audiciav1alpha1.Subject, rule normalizer.CanonicalRule, eventTime time.Time) {
+	r.RuleStream.Publish(rulestream.Observation{
+		Source:  types.NamespacedName{Namespace: source.Namespace, Name: source.Name},
+		Subject: subject,
+		Rule:    rule,
+		Time:    eventTime,
+	})
+}
+
+// locations caches the time zones activityLocation has loaded.
+var locations sync.Map // string → *time.Location
+
+// activityLocation returns the time zone spec.activityTimeZone names, UTC
+// when it is empty. Lookups are cached, as every event needs one.
+func activityLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
 	}
+	locations.Store(name, loc)
+	return loc, nil
+}
 
-	namespace := ""
+// filterRequest extracts the request attributes spec.filters match on.
+func filterRequest(event auditv1.Event, eventTime time.Time) filter.Request {
+	req := filter.Request{Verb: event.Verb, Time: eventTime}
 	if event.ObjectRef != nil {
-		namespace = event.ObjectRef.Namespace
+		req.APIGroup = event.ObjectRef.APIGroup
+		req.Resource = event.ObjectRef.Resource
+		req.Subresource = event.ObjectRef.Subresource
 	}
+	return req
+}
 
-	// Filter.
-	if !filterChain.Allow(username, namespace) {
-		metrics.EventsFilteredTotal.WithLabelValues("deny").Inc()
-		return
-	}
+// authorizationDecisionAnnotation is the audit annotation in which the API
+// server records the authorizer's decision.
+const authorizationDecisionAnnotation = "authorization.k8s.io/decision"
 
-	// Normalize subject.
-	subject, include := normalizer.NormalizeSubject(username, source.Spec.IgnoreSystemUsers)
-	if !include {
-		metrics.EventsFilteredTotal.WithLabelValues("system_user").Inc()
-		return
+// statusFieldOwner is the server-side apply field manager of report
+// statuses.
+const statusFieldOwner = "audicia-operator"
+
+// isForbidden reports whether the authorizer denied the request (403). A 403
+// from admission control is not an RBAC denial, see isAdmissionDenied.
+func isForbidden(event auditv1.Event) bool {
+	return event.ResponseStatus != nil && event.ResponseStatus.Code == http.StatusForbidden &&
+		!isAdmissionDenied(event)
+}
+
+// isAdmissionDenied reports whether admission control rejected a request the
+// authorizer allowed: a validating webhook, a ValidatingAdmissionPolicy or a
+// quota. Without the authorizer's decision annotation, webhook and policy
+// denials are recognized by their status message.
+func isAdmissionDenied(event auditv1.Event) bool {
+	status := event.ResponseStatus
+	if status == nil || status.Code < http.StatusBadRequest || status.Code == http.StatusUnauthorized {
+		return false
 	}
+	if status.Code == http.StatusForbidden && event.Annotations[authorizationDecisionAnnotation] == "allow" {
+		return true
+	}
+	return strings.Contains(status.Message, "admission webhook") ||
+		strings.Contains(status.Message, "ValidatingAdmissionPolicy")
+}
 
-	// Normalize event into a canonical rule.
-	resource := ""
-	subresource := ""
-	apiGroup := ""
-	if event.ObjectRef != nil {
-		resource = event.ObjectRef.Resource
-		subresource = event.ObjectRef.Subresource
-		apiGroup = event.ObjectRef.APIGroup
-	}
-	rule := normalizer.NormalizeEvent(
-		resource,
-		subresource,
-		apiGroup,
-		event.Verb,
-		namespace,
-		event.RequestURI,
-		event.ObjectRef != nil,
-	)
+// isUnauthenticated reports whether the request failed authentication (401).
+func isUnauthenticated(event auditv1.Event) bool {
+	return event.ResponseStatus != nil && event.ResponseStatus.Code == http.StatusUnauthorized
+}
 
-	// Skip events that resolved to neither a resource nor a non-resource URL
-	// (e.g., no objectRef and empty requestURI). These produce empty
-	// apiGroups/resources which fail CRD validation.
-	if rule.Resource == "" && rule.NonResourceURL == "" {
-		metrics.EventsFilteredTotal.WithLabelValues("unresolvable").Inc()
-		return
+// isDryRun reports whether the request was a dry run: a dryRun query
+// parameter, as sent by kubectl --dry-run=server and server-side apply, or
+// dryRun in the DeleteOptions body of a delete.
+func isDryRun(event auditv1.Event) bool {
+	_, query, _ := strings.Cut(event.RequestURI, "?")
+	for query != "" {
+		var param string
+		param, query, _ = strings.Cut(query, "&")
+		if value, ok := strings.CutPrefix(param, "dryRun="); ok && value != "" {
+			return true
+		}
 	}
+	if (event.Verb == "delete" || event.Verb == "deletecollection") && event.RequestObject != nil {
+		var opts struct {
+			DryRun []string `json:"dryRun"`
+		}
+		if json.Unmarshal(event.RequestObject.Raw, &opts) == nil && len(opts.DryRun) > 0 {
+			return true
+		}
+	}
+	return false
+}
 
-	// Aggregate per subject.
-	subjectKey := subjectKeyString(subject)
-	if _, exists := aggregators[subjectKey]; !exists {
-		aggregators[subjectKey] = aggregator.New()
-		subjects[subjectKey] = subject
+// stageAllowed reports whether events at stage are processed: the stages in
+// spec.stages (ResponseComplete by default), plus ResponseStarted and Panic
+// with spec.captureIncompleteStages. Events without a stage are kept.
+func stageAllowed(spec audiciav1alpha1.AudiciaSourceSpec, stage auditv1.Stage) bool {
+	switch {
+	case stage == "":
+		return true
+	case spec.CaptureIncompleteStages && (stage == auditv1.StageResponseStarted || stage == auditv1.StagePanic):
+		return true
+	case len(spec.Stages) == 0:
+		return stage == auditv1.StageResponseComplete
 	}
+	return slices.Contains(spec.Stages, audiciav1alpha1.AuditStage(stage))
+}
 
-	eventTime := time.Now()
-	if !event.RequestReceivedTimestamp.Time.IsZero() {
-		eventTime = event.RequestReceivedTimestamp.Time
+// eventNamespace returns the namespace of the event's object, or "" for
+// cluster-scoped and non-resource requests.
+func eventNamespace(event auditv1.Event) string {
+	if event.ObjectRef == nil {
+		return ""
 	}
-	aggregators[subjectKey].Add(rule, eventTime)
+	return event.ObjectRef.Namespace
+}
 
-	metrics.EventsProcessedTotal.WithLabelValues(string(source.Spec.SourceType), "accepted").Inc()
+// aggregate adds a rule to the subject's aggregator, creating it on first use.
+func aggregate(
+	aggregators map[subjectKey]*aggregator.Aggregator,
+	subjects map[subjectKey]audiciav1alpha1.Subject,
+	subject audiciav1alpha1.Subject,
+	rule normalizer.CanonicalRule,
+	eventTime time.Time,
+	weight int64,
+) {
+	key := keyFor(subject)
+	agg, exists := aggregators[key]
+	if !exists {
+		agg = aggregator.New()
+		aggregators[key] = agg
+		subjects[key] = subject
+	}
+	agg.AddWeighted(rule, eventTime, weight)
 }
 
 // flushReports creates or updates AudiciaReport and AudiciaPolicy resources for each subject.
+// Subjects are flushed independently: one failing subject does not prevent the
+// others from being written. The result records which subjects failed and
+// which were skipped because another source holds their report.
 func (r *Reconciler) flushReports(
 	ctx context.Context,
 	key types.NamespacedName,
 	source audiciav1alpha1.AudiciaSource,
 	engine *strategy.Engine,
-	aggregators map[string]*aggregator.Aggregator,
-	subjects map[string]audiciav1alpha1.Subject,
-) {
+	aggregators map[subjectKey]*aggregator.Aggregator,
+	subjects map[subjectKey]audiciav1alpha1.Subject,
+) flushResult {
 	logger := ctrl.Log.WithName("pipeline").WithValues("source", key)
+	return r.flushSubjects(ctx, source, engine, slices.Collect(maps.Keys(aggregators)), aggregators, subjects, true, logger)
+}
 
-	for subjectKey, agg := range aggregators {
-		subject := subjects[subjectKey]
-		rules, dropped := compactRules(agg.Rules(), source.Spec.Limits, subject.Name, logger)
+// flushSubject writes the report and policy for a single subject. With
+// notifyChanges, changes of the report are sent to the Notifier and the report
+// webhooks.
+func (r *Reconciler) flushSubject(
+	ctx context.Context,
+	source audiciav1alpha1.AudiciaSource,
+	engine *strategy.Engine,
+	subject audiciav1alpha1.Subject,
+	agg *aggregator.Aggregator,
+	notifyChanges bool,
+	logger logr.Logger,
+) error {
+	// Never overwrite imported or merged rules that have not been seeded yet.
+	if err := r.seedReportRules(ctx, source, subject, agg); err != nil {
+		logger.Error(err, "failed to read existing report rules", "subject", subject.Name)
+		return fmt.Errorf("reading existing report rules: %w", err)
+	}
 
-		if dropped > 0 {
-			r.Recorder.Eventf(&source, nil, corev1.EventTypeWarning, "CompactionTriggered", "Compact",
-				"Subject %s has %d rules, exceeds limit; dropped %d oldest rules",
-				subject.Name, len(rules)+dropped, dropped)
-		}
+	rules, dropped := compactRules(agg.Rules(), source.Spec.Limits, subject.Name, logger)
 
-		if err := r.flushReport(ctx, source, subject, rules, agg.EventsProcessed(), logger); err != nil {
-			logger.Error(err, "failed to flush report", "subject", subject.Name)
-			metrics.ReconcileErrorsTotal.Inc()
-			r.Recorder.Eventf(&source, nil, corev1.EventTypeWarning, "FlushFailed", "Flush",
-				"Failed to flush report for %s: %v", subject.Name, err)
-		}
+	if dropped > 0 {
+		r.Recorder.Eventf(&source, nil, corev1.EventTypeWarning, "CompactionTriggered", "Compact",
+			"Subject %s has %d rules, exceeds limit; dropped %d oldest rules",
+			subject.Name, len(rules)+dropped, dropped)
+	}
 
-		if err := r.flushPolicy(ctx, source, engine, subject, rules, logger); err != nil {
-			logger.Error(err, "failed to flush policy", "subject", subject.Name)
-			metrics.ReconcileErrorsTotal.Inc()
-			r.Recorder.Eventf(&source, nil, corev1.EventTypeWarning, "FlushFailed", "Flush",
-				"Failed to flush policy for %s: %v", subject.Name, err)
-		}
+	denied, _ := compactRules(agg.DeniedRules(), source.Spec.Limits, subject.Name, logger)
+	activity := agg.Activity()
+	if activity != nil {
+		activity.TimeZone = cmp.Or(source.Spec.ActivityTimeZone, "UTC")
 	}
-}
 
-// compactRules applies retention and truncation limits to observed rules.
-// Returns the compacted rules and the number of rules dropped by truncation.
-func compactRules(rules []audiciav1alpha1.ObservedRule, limits audiciav1alpha1.LimitsConfig, subjectName string, logger logr.Logger) ([]audiciav1alpha1.ObservedRule, int) {
-	retentionDays := int(limits.RetentionDays)
-	if retentionDays <= 0 {
-		retentionDays = 30
+	reportErr := r.flushReport(ctx, source, subject, rules, denied, activity, agg.EventsProcessed(), notifyChanges, logger)
+	var contended *reportContendedError
+	if stderrors.As(reportErr, &contended) {
+		// The policy is derived from the report, so it belongs to the same writer.
+		logger.V(1).Info("skipping contended subject", "subject", subject.Name, "holder", contended.holder)
+		return reportErr
 	}
-	cutoff := metav1.NewTime(time.Now().Add(-time.Duration(retentionDays) * 24 * time.Hour))
-	retained := make([]audiciav1alpha1.ObservedRule, 0, len(rules))
-	for _, rule := range rules {
-		if !rule.LastSeen.Before(&cutoff) {
-			retained = append(retained, rule)
-		}
+	if reportErr != nil {
+		logger.Error(reportErr, "failed to flush report", "subject", subject.Name)
+		metrics.ReconcileErrorsTotal.Inc()
+		r.Recorder.Eventf(&source, nil, corev1.EventTypeWarning, "FlushFailed", "Flush",
+			"Failed to flush report for %s: %v", subject.Name, reportErr)
 	}
-	rules = retained
 
-	// Sort by LastSeen descending for truncation (keep most recent).
-	sort.Slice(rules, func(i, j int) bool {
-		return rules[j].LastSeen.Before(&rules[i].LastSeen)
-	})
+	// Emergency access is not a permission to suggest.
+	if isBreakGlass(source, subject) {
+		return reportErr
+	}
 
-	maxRules := int(limits.MaxRulesPerReport)
-	if maxRules <= 0 {
-		maxRules = 200
+	policyErr := r.flushPolicy(ctx, source, engine, subject, rules, logger)
+	if policyErr != nil {
+		logger.Error(policyErr, "failed to flush policy", "subject", subject.Name)
+		metrics.ReconcileErrorsTotal.Inc()
+		r.Recorder.Eventf(&source, nil, corev1.EventTypeWarning, "FlushFailed", "Flush",
+			"Failed to flush policy for %s: %v", subject.Name, policyErr)
 	}
-	dropped := 0
-	if len(rules) > maxRules {
-		dropped = len(rules) - maxRules
+
+	return stderrors.Join(reportErr, policyErr)
+}
+
+// compactRules applies retention and truncation limits to observed rules.
+// Returns the compacted rules and the number of rules dropped by truncation.
+func compactRules(rules []audiciav1alpha1.ObservedRule, limits audiciav1alpha1.LimitsConfig, subjectName string, logger logr.Logger) ([]audiciav1alpha1.ObservedRule, int) {
+	result := aggregator.Compact(rules, limits, time.Now())
+	if result.Truncated > 0 {
 		logger.Info("compacting rules", "subject", subjectName,
-			"total", len(rules), "max", maxRules,
-			"dropped", dropped)
-		rules = rules[:maxRules]
+			"total", len(result.Rules)+result.Truncated, "max", len(result.Rules),
+			"dropped", result.Truncated)
 	}
-	return rules, dropped
+	return result.Rules, result.Truncated
 }
 
-// flushReport creates/updates a single AudiciaReport for one subject.
+// flushReport creates/updates a single AudiciaReport for one subject. With
+// notifyChanges, its changes are sent to the Notifier and the report webhooks.
 func (r *Reconciler) flushReport(
 	ctx context.Context,
 	source audiciav1alpha1.AudiciaSource,
 	subject audiciav1alpha1.Subject,
 	rules []audiciav1alpha1.ObservedRule,
+	denied []audiciav1alpha1.ObservedRule,
+	activity *audiciav1alpha1.ActivitySummary,
 	eventsProcessed int64,
+	notifyChanges bool,
 	logger logr.Logger,
 ) error {
-	reportName := fmt.Sprintf("report-%s", sanitizeName(subject.Name))
+	reportName := reportNameFor(subject)
 	reportNamespace := reportNamespaceFor(source, subject)
+	sourceKey := types.NamespacedName{Namespace: source.Namespace, Name: source.Name}
+	configHash := configurationHash(source.Spec)
 
 	report := &audiciav1alpha1.AudiciaReport{
 		ObjectMeta: metav1.ObjectMeta{
@@ -507,11 +1151,18 @@ func (r *Reconciler) flushReport(
 	// severity so we can emit events after a successful flush.
 	var created bool
 	var prevSeverity audiciav1alpha1.ComplianceSeverity
+	var prevCompliance *audiciav1alpha1.ComplianceReport
+	var previousSeen time.Time
+	var before *audiciav1alpha1.AudiciaReportStatus
+	var unchanged bool
 
 	// Create/update spec and status in a single retry loop so that a report
 	// deleted between the two phases is re-created automatically.
 	err := retry.OnError(retry.DefaultRetry, retryOnConflictOrNotFound, func() error {
 		result, createErr := controllerutil.CreateOrUpdate(ctx, r.Client, report, func() error {
+			if err := claimReport(report, source, sourceKey, time.Now()); err != nil {
+				return err
+			}
 			return r.applyReportSpec(source, report, subject, reportNamespace)
 		})
 		if createErr != nil {
@@ -522,14 +1173,44 @@ func (r *Reconciler) flushReport(
 			logger.Info("report spec updated", "report", reportName, "result", result)
 		}
 		prevSeverity = currentSeverity(report)
-		r.populateReportStatus(ctx, report, subject, rules, eventsProcessed, logger)
-		return r.Status().Update(ctx, report)
+		prevCompliance = report.Status.Compliance.DeepCopy()
+		before = report.Status.DeepCopy()
+		previous := report.Status.ObservedRules
+		previousSeen = latestSeen(previous)
+		events, summary := eventsProcessed, activity
+		union := source.Spec.ReportMerge == audiciav1alpha1.ReportMergeUnion
+		if union {
+			events, summary = mergeReportSources(report, sourceKey, eventsProcessed, activity, configHash, time.Now())
+		} else {
+			report.Status.Sources = nil
+		}
+		r.populateReportStatus(ctx, report, subject, rules, denied, summary, events, logger)
+		report.Status.Ignored = r.ignoredVerbs(source, rules)
+		if union {
+			report.Status.Configuration = nil
+		} else {
+			recordConfiguration(report, configHash, r.pipelineStartTime(sourceKey))
+		}
+		recordIntegrity(source, report, previous, logger)
+		if unchanged = !created && sameReportStatus(before, &report.Status); unchanged {
+			return nil
+		}
+		return r.applyReportStatus(ctx, report)
 	})
 	if err != nil {
 		return fmt.Errorf("flush report %s: %w", reportName, err)
 	}
+	if unchanged {
+		logger.V(1).Info("report unchanged", "report", reportName)
+		return nil
+	}
 
 	r.emitReportEvents(report, subject, created, prevSeverity)
+	if notifyChanges {
+		r.Notifier.Notify(notify.Changes(report, prevCompliance)...)
+		r.notifyReportWebhooks(source, report, before, created)
+	}
+	r.alertBreakGlass(source, report, subject, previousSeen, rules)
 
 	metrics.ReportsUpdatedTotal.Inc()
 	metrics.ReportRulesCount.WithLabelValues(reportName).Set(float64(len(rules)))
@@ -537,6 +1218,42 @@ func (r *Reconciler) flushReport(
 	return nil
 }
 
+// ignoredVerbs returns the status.ignored of a report written by source: the
+// observed verbs its strategy engine leaves out of the suggested policy.
+func (r *Reconciler) ignoredVerbs(source audiciav1alpha1.AudiciaSource, rules []audiciav1alpha1.ObservedRule) []audiciav1alpha1.IgnoredVerb {
+	engine := strategy.NewEngine(source.Spec.PolicyStrategy)
+	engine.Verbs = r.Verbs
+	return engine.IgnoredVerbs(rules)
+}
+
+// applyReportStatus writes the report's status with a server-side apply
+// patch. The resourceVersion the status was computed from is sent along, so
+// a concurrent writer causes a conflict rather than being overwritten.
+func (r *Reconciler) applyReportStatus(ctx context.Context, report *audiciav1alpha1.AudiciaReport) error {
+	status, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&report.Status)
+	if err != nil {
+		return fmt.Errorf("converting status: %w", err)
+	}
+	u := &unstructured.Unstructured{Object: map[string]any{"status": status}}
+	u.SetGroupVersionKind(audiciav1alpha1.SchemeGroupVersion.WithKind("AudiciaReport"))
+	u.SetName(report.Name)
+	u.SetNamespace(report.Namespace)
+	u.SetResourceVersion(report.ResourceVersion)
+	return r.Status().Apply(ctx, client.ApplyConfigurationFromUnstructured(u),
+		client.FieldOwner(statusFieldOwner), client.ForceOwnership)
+}
+
+// sameReportStatus reports whether a flush would leave a report's status
+// as it is, apart from status.lastProcessedTime. Statuses are compared in
+// their serialized form, which has the precision the API server stores.
+func sameReportStatus(before, after *audiciav1alpha1.AudiciaReportStatus) bool {
+	a, b := before.DeepCopy(), after.DeepCopy()
+	a.LastProcessedTime, b.LastProcessedTime = nil, nil
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && string(x) == string(y)
+}
+
 // manifestGenerator generates RBAC manifests for a subject.
 type manifestGenerator interface {
 	GenerateManifests(subject audiciav1alpha1.Subject, rules []audiciav1alpha1.ObservedRule) ([]string, error)
@@ -556,7 +1273,7 @@ func (r *Reconciler) flushPolicy(
 		return fmt.Errorf("generating manifests: %w", err)
 	}
 
-	policyName := fmt.Sprintf("policy-%s", sanitizeName(subject.Name))
+	policyName := policyNameFor(subject)
 	policyNamespace := reportNamespaceFor(source, subject)
 
 	policy := &audiciav1alpha1.AudiciaPolicy{
@@ -578,6 +1295,7 @@ func (r *Reconciler) flushPolicy(
 		}
 		policy.Status.State = determinePolicyState(result, policy.Status.State)
 		policy.Status.RuleCount = int32(len(rules))
+		policy.Status.GeneratedBy = r.generatedBy()
 		return r.Status().Update(ctx, policy)
 	})
 	if err != nil {
@@ -615,18 +1333,52 @@ func (r *Reconciler) applyPolicySpec(
 	manifests []string,
 ) error {
 	if policyNamespace == source.Namespace {
-		if err := controllerutil.SetControllerReference(&source, policy, r.Scheme); err != nil {
+		if err := r.setSourceOwner(source, policy); err != nil {
 			return err
 		}
 	}
+	applyOutputMetadata(source, &policy.ObjectMeta)
 	policy.Spec.Subject = subject
 	policy.Spec.SourceRef = source.Name
 	policy.Spec.Manifests = manifests
 	return nil
 }
 
+// generatedBy returns the status stamp for artifacts written by this
+// operator build, or nil if the build is unidentified.
+func (r *Reconciler) generatedBy() *audiciav1alpha1.GeneratorInfo {
+	if r.Generator == (audiciav1alpha1.GeneratorInfo{}) {
+		return nil
+	}
+	g := r.Generator
+	return &g
+}
+
+// reportNameFor returns the AudiciaReport name for a subject.
+func reportNameFor(subject audiciav1alpha1.Subject) string {
+	return fmt.Sprintf("report-%s", subjectNameSegment(subject))
+}
+
+// policyNameFor returns the AudiciaPolicy name for a subject.
+func policyNameFor(subject audiciav1alpha1.Subject) string {
+	return fmt.Sprintf("policy-%s", subjectNameSegment(subject))
+}
+
+// subjectNameSegment returns the name-safe part of a subject's report and
+// policy names. Groups are prefixed so that a group never shares resources
+// with a user of the same name.
+func subjectNameSegment(subject audiciav1alpha1.Subject) string {
+	if subject.Kind == audiciav1alpha1.SubjectKindGroup {
+		return sanitizeName("group-" + subject.Name)
+	}
+	return sanitizeName(subject.Name)
+}
+
 // reportNamespaceFor returns the namespace where the report should be written.
 func reportNamespaceFor(source audiciav1alpha1.AudiciaSource, subject audiciav1alpha1.Subject) string {
+	if isBreakGlass(source, subject) {
+		return source.Spec.BreakGlass.Namespace
+	}
 	if subject.Kind == audiciav1alpha1.SubjectKindServiceAccount && subject.Namespace != "" {
 		return subject.Namespace
 	}
@@ -638,7 +1390,8 @@ func retryOnConflictOrNotFound(err error) bool {
 	return errors.IsConflict(err) || errors.IsNotFound(err)
 }
 
-// applyReportSpec sets the owner reference and subject on the report.
+// applyReportSpec sets the owner reference, subject and break-glass label on
+// the report.
 func (r *Reconciler) applyReportSpec(
 	source audiciav1alpha1.AudiciaSource,
 	report *audiciav1alpha1.AudiciaReport,
@@ -646,14 +1399,67 @@ func (r *Reconciler) applyReportSpec(
 	reportNamespace string,
 ) error {
 	if reportNamespace == source.Namespace {
-		if err := controllerutil.SetControllerReference(&source, report, r.Scheme); err != nil {
+		if err := r.setSourceOwner(source, report); err != nil {
 			return err
 		}
 	}
+	applyOutputMetadata(source, &report.ObjectMeta)
+	if isBreakGlass(source, subject) {
+		if report.Labels == nil {
+			report.Labels = make(map[string]string, 1)
+		}
+		report.Labels[BreakGlassLabel] = "true"
+	}
 	report.Spec.Subject = subject
 	return nil
 }
 
+// setSourceOwner makes source the controller of obj. An object another
+// source already controls, e.g. a report merged from several sources, gets
+// source as an additional owner instead, so it is only garbage collected once
+// all its sources are deleted.
+func (r *Reconciler) setSourceOwner(source audiciav1alpha1.AudiciaSource, obj client.Object) error {
+	if owner := metav1.GetControllerOf(obj); owner != nil && owner.UID != source.UID {
+		return controllerutil.SetOwnerReference(&source, obj, r.Scheme)
+	}
+	return controllerutil.SetControllerReference(&source, obj, r.Scheme)
+}
+
+// mergeMetadata returns the keys of base and over, preferring over. It
+// returns base or over unchanged when the other is empty.
+func mergeMetadata(base, over map[string]string) map[string]string {
+	if len(over) == 0 {
+		return base
+	}
+	if len(base) == 0 {
+		return over
+	}
+	merged := maps.Clone(base)
+	maps.Copy(merged, over)
+	return merged
+}
+
+// applyOutputMetadata adds the source's spec.metadata labels and annotations
+// to a generated object, leaving any other keys untouched.
+func applyOutputMetadata(source audiciav1alpha1.AudiciaSource, obj *metav1.ObjectMeta) {
+	m := source.Spec.Metadata
+	if m == nil {
+		return
+	}
+	if len(m.Labels) > 0 {
+		if obj.Labels == nil {
+			obj.Labels = make(map[string]string, len(m.Labels))
+		}
+		maps.Copy(obj.Labels, m.Labels)
+	}
+	if len(m.Annotations) > 0 {
+		if obj.Annotations == nil {
+			obj.Annotations = make(map[string]string, len(m.Annotations))
+		}
+		maps.Copy(obj.Annotations, m.Annotations)
+	}
+}
+
 // currentSeverity returns the compliance severity of a report, or empty if unset.
 func currentSeverity(report *audiciav1alpha1.AudiciaReport) audiciav1alpha1.ComplianceSeverity {
 	if report.Status.Compliance != nil {
@@ -662,6 +1468,15 @@ func currentSeverity(report *audiciav1alpha1.AudiciaReport) audiciav1alpha1.Comp
 	return ""
 }
 
+// observeCompliance exports the report's compliance as metrics.
+func observeCompliance(report *audiciav1alpha1.AudiciaReport) {
+	c := report.Status.Compliance
+	if c == nil {
+		return
+	}
+	metrics.ObserveCompliance(report.Namespace, report.Name, string(report.Spec.Subject.Kind), c.Score, len(c.SensitiveExcess))
+}
+
 // emitReportEvents emits Kubernetes events for report creation and drift detection.
 func (r *Reconciler) emitReportEvents(
 	report *audiciav1alpha1.AudiciaReport,
@@ -674,12 +1489,18 @@ func (r *Reconciler) emitReportEvents(
 			"Created policy report for %s %s", subject.Kind, subject.Name)
 		return
 	}
+	emitDriftEvent(r.Recorder, report, prevSeverity)
+}
+
+// emitDriftEvent emits a DriftDetected warning if the report's compliance
+// severity worsened relative to prevSeverity.
+func emitDriftEvent(recorder events.EventRecorder, report *audiciav1alpha1.AudiciaReport, prevSeverity audiciav1alpha1.ComplianceSeverity) {
 	if report.Status.Compliance == nil {
 		return
 	}
 	newSeverity := report.Status.Compliance.Severity
 	if newSeverity != prevSeverity && severityWorsened(prevSeverity, newSeverity) {
-		r.Recorder.Eventf(report, nil, corev1.EventTypeWarning, "DriftDetected", "Evaluate",
+		recorder.Eventf(report, nil, corev1.EventTypeWarning, "DriftDetected", "Evaluate",
 			"Compliance degraded from %s to %s (score=%d, excess=%d, uncovered=%d)",
 			prevSeverity, newSeverity,
 			report.Status.Compliance.Score,
@@ -704,20 +1525,32 @@ func (r *Reconciler) populateReportStatus(
 	report *audiciav1alpha1.AudiciaReport,
 	subject audiciav1alpha1.Subject,
 	rules []audiciav1alpha1.ObservedRule,
+	denied []audiciav1alpha1.ObservedRule,
+	activity *audiciav1alpha1.ActivitySummary,
 	eventsProcessed int64,
 	logger logr.Logger,
 ) {
 	now := metav1.Now()
 	report.Status.ObservedRules = rules
+	report.Status.DeniedRules = denied
+	report.Status.Activity = activity
 	report.Status.EventsProcessed = eventsProcessed
 	report.Status.LastProcessedTime = &now
+	report.Status.GeneratedBy = r.generatedBy()
 
-	if r.Resolver != nil {
+	if r.DeferCompliance {
+		markCompliancePending(report)
+	} else if r.Resolver != nil {
 		effective, err := r.Resolver.EffectiveRules(ctx, subject)
 		if err != nil {
 			logger.V(1).Info("skipping compliance evaluation", "subject", subject.Name, "error", err)
 		} else {
 			report.Status.Compliance = diff.Evaluate(rules, effective)
+			observeCompliance(report)
+			// Clear a pending marker left behind by a previous ingest-only deployment.
+			if meta.FindStatusCondition(report.Status.Conditions, complianceCondition) != nil {
+				markComplianceEvaluated(report)
+			}
 		}
 	}
 
@@ -727,29 +1560,78 @@ func (r *Reconciler) populateReportStatus(
 		Reason:  "ReportGenerated",
 		Message: fmt.Sprintf("Generated %d rules for %s", len(rules), subject.Name),
 	})
+
+	// Resolve the placeholder state of reports created before any activity.
+	if meta.FindStatusCondition(report.Status.Conditions, "NoActivityObserved") != nil {
+		meta.SetStatusCondition(&report.Status.Conditions, metav1.Condition{
+			Type:    "NoActivityObserved",
+			Status:  metav1.ConditionFalse,
+			Reason:  "ActivityObserved",
+			Message: "Audit activity has been observed for this subject.",
+		})
+	}
+	if meta.IsStatusConditionTrue(report.Status.Conditions, staleCondition) {
+		meta.SetStatusCondition(&report.Status.Conditions, metav1.Condition{
+			Type:    staleCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "ActivityObserved",
+			Message: "Audit activity has been observed again for this subject.",
+		})
+	}
 }
 
-// flushCheckpoint persists the ingestor checkpoint back to the AudiciaSource status.
-func (r *Reconciler) flushCheckpoint(ctx context.Context, key types.NamespacedName, ing ingestor.Ingestor) {
+// recordIntegrity extends the report's hash chain with the rules just
+// populated, or removes the chain when spec.integrity is unset, since it
+// would no longer match.
+func recordIntegrity(
+	source audiciav1alpha1.AudiciaSource,
+	report *audiciav1alpha1.AudiciaReport,
+	previous []audiciav1alpha1.ObservedRule,
+	logger logr.Logger,
+) {
+	cfg := source.Spec.Integrity
+	if cfg == nil {
+		report.Status.Integrity = nil
+		return
+	}
+	chain, err := integrity.Append(report.Status.Integrity, previous, report.Status.ObservedRules, time.Now(), int(cfg.HistoryLimit))
+	if err != nil {
+		logger.Error(err, "failed to extend integrity chain", "report", report.Name)
+		return
+	}
+	report.Status.Integrity = chain
+}
+
+// flushCheckpoint persists the ingestor checkpoint back to the AudiciaSource
+// status, together with any ingestion gaps detected since the last one. It
+// returns the error of a failed write.
+func (r *Reconciler) flushCheckpoint(ctx context.Context, key types.NamespacedName, ing ingestor.Ingestor, gaps *gapDetector) error {
 	logger := ctrl.Log.WithName("pipeline").WithValues("source", key)
 
 	// Cloud ingestors have partition-based checkpoints.
 	if cloudIng, ok := ing.(*cloud.CloudIngestor); ok {
-		r.flushCloudCheckpoint(ctx, key, cloudIng, logger)
-		return
+		return r.flushCloudCheckpoint(ctx, key, cloudIng, gaps, logger)
 	}
 
 	// File/webhook checkpoint path (unchanged).
 	pos := ing.Checkpoint()
+	var files []audiciav1alpha1.FileCheckpointStatus
+	multi, isMulti := ing.(*ingestor.MultiFileIngestor)
+	if isMulti {
+		files = fileCheckpoints(multi.FileCheckpoints())
+	}
 
+	var source audiciav1alpha1.AudiciaSource
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		var source audiciav1alpha1.AudiciaSource
 		if err := r.Get(ctx, key, &source); err != nil {
 			return err
 		}
 
 		source.Status.FileOffset = pos.FileOffset
 		source.Status.Inode = pos.Inode
+		if isMulti {
+			source.Status.Files = files
+		}
 		if pos.LastTimestamp != "" {
 			t, err := time.Parse(time.RFC3339, pos.LastTimestamp)
 			if err == nil {
@@ -757,24 +1639,38 @@ func (r *Reconciler) flushCheckpoint(ctx context.Context, key types.NamespacedNa
 				source.Status.LastTimestamp = &mt
 			}
 		}
+		markCheckpointHealthy(&source)
+		gaps.apply(&source.Status)
 
 		return r.Status().Update(ctx, &source)
 	})
-	if err != nil {
-		if !errors.IsNotFound(err) {
-			logger.Error(err, "failed to update checkpoint")
-		}
-	} else {
-		metrics.CheckpointLagSeconds.WithLabelValues(key.String()).Set(0)
+	if err == nil {
+		r.reportGaps(&source, gaps.commit())
+	}
+	return r.handleCheckpointResult(ctx, key, err, logger)
+}
+
+// fileCheckpoints converts per-file positions into status entries sorted by
+// path.
+func fileCheckpoints(positions map[string]ingestor.Position) []audiciav1alpha1.FileCheckpointStatus {
+	files := make([]audiciav1alpha1.FileCheckpointStatus, 0, len(positions))
+	for path, pos := range positions {
+		files = append(files, audiciav1alpha1.FileCheckpointStatus{
+			Path:       path,
+			FileOffset: pos.FileOffset,
+			Inode:      pos.Inode,
+		})
 	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
 }
 
 // flushCloudCheckpoint persists cloud-specific partition offsets to AudiciaSource status.
-func (r *Reconciler) flushCloudCheckpoint(ctx context.Context, key types.NamespacedName, ing *cloud.CloudIngestor, logger logr.Logger) {
+func (r *Reconciler) flushCloudCheckpoint(ctx context.Context, key types.NamespacedName, ing *cloud.CloudIngestor, gaps *gapDetector, logger logr.Logger) error {
 	cp := ing.CloudCheckpoint()
 
+	var source audiciav1alpha1.AudiciaSource
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		var source audiciav1alpha1.AudiciaSource
 		if err := r.Get(ctx, key, &source); err != nil {
 			return err
 		}
@@ -791,16 +1687,77 @@ func (r *Reconciler) flushCloudCheckpoint(ctx context.Context, key types.Namespa
 				source.Status.LastTimestamp = &mt
 			}
 		}
+		markCheckpointHealthy(&source)
+		gaps.apply(&source.Status)
 
 		return r.Status().Update(ctx, &source)
 	})
-	if err != nil {
-		if !errors.IsNotFound(err) {
-			logger.Error(err, "failed to update cloud checkpoint")
-		}
-	} else {
+	if err == nil {
+		r.reportGaps(&source, gaps.commit())
+	}
+	return r.handleCheckpointResult(ctx, key, err, logger)
+}
+
+// markCheckpointHealthy records a successful checkpoint write on the source
+// status. It is applied inside the checkpoint update itself so that a healthy
+// pipeline costs no extra API calls.
+func markCheckpointHealthy(source *audiciav1alpha1.AudiciaSource) {
+	now := metav1.Now()
+	source.Status.LastCheckpointTime = &now
+	meta.SetStatusCondition(&source.Status.Conditions, metav1.Condition{
+		Type:               "CheckpointHealthy",
+		Status:             metav1.ConditionTrue,
+		Reason:             "CheckpointPersisted",
+		Message:            "Ingestion checkpoint persisted successfully.",
+		ObservedGeneration: source.Generation,
+	})
+}
+
+// handleCheckpointResult updates metrics after a checkpoint write and, on
+// failure, surfaces the error as a CheckpointHealthy=False condition and a
+// Warning event. Without this, persistent failures (RBAC, conflicts, API
+// slowness) only show up in logs until a restart replays the backlog. It
+// returns err unless the source was deleted.
+func (r *Reconciler) handleCheckpointResult(ctx context.Context, key types.NamespacedName, err error, logger logr.Logger) error {
+	if err == nil {
 		metrics.CheckpointLagSeconds.WithLabelValues(key.String()).Set(0)
+		return nil
 	}
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	logger.Error(err, "failed to update checkpoint")
+
+	// Best effort: the same root cause may also block this write, in which
+	// case the log line above is all we can offer.
+	var source audiciav1alpha1.AudiciaSource
+	if getErr := r.Get(ctx, key, &source); getErr != nil {
+		return err
+	}
+
+	lastSuccess := "never"
+	if t := source.Status.LastCheckpointTime; t != nil {
+		lastSuccess = t.UTC().Format(time.RFC3339)
+		metrics.CheckpointLagSeconds.WithLabelValues(key.String()).Set(time.Since(t.Time).Seconds())
+	}
+
+	wasHealthy := !meta.IsStatusConditionFalse(source.Status.Conditions, "CheckpointHealthy")
+	message := fmt.Sprintf("Failed to persist checkpoint (last success: %s): %v", lastSuccess, err)
+	if condErr := r.setCondition(ctx, &source, metav1.Condition{
+		Type:               "CheckpointHealthy",
+		Status:             metav1.ConditionFalse,
+		Reason:             "CheckpointWriteFailed",
+		Message:            message,
+		ObservedGeneration: source.Generation,
+	}); condErr != nil {
+		logger.V(1).Info("failed to record CheckpointHealthy condition", "error", condErr)
+	}
+
+	// Emit on transition only, so a persistent failure does not flood events.
+	if wasHealthy {
+		r.Recorder.Eventf(&source, nil, corev1.EventTypeWarning, "CheckpointFailed", "Checkpoint", "%s", message)
+	}
+	return err
 }
 
 // setCondition updates a condition on the AudiciaSource status.
@@ -814,6 +1771,41 @@ func (r *Reconciler) setCondition(ctx context.Context, source *audiciav1alpha1.A
 	})
 }
 
+// servedRBACVersions returns the RBAC group versions the cluster serves,
+// preferred first. It returns nil when discovery fails, which leaves the
+// renderer on its default version.
+func (r *Reconciler) servedRBACVersions() []string {
+	mappings, err := r.RESTMapper().RESTMappings(schema.GroupKind{Group: rbacv1.GroupName, Kind: "Role"})
+	if err != nil {
+		ctrl.Log.WithName("pipeline").V(1).Info("RBAC discovery failed, using default apiVersion", "error", err)
+		return nil
+	}
+	versions := make([]string, 0, len(mappings))
+	for _, m := range mappings {
+		versions = append(versions, m.GroupVersionKind.GroupVersion().String())
+	}
+	return versions
+}
+
+// recordListenAddress persists status.listenAddress when it changed, so
+// operators can see where the receiver listens, including which IP family.
+func (r *Reconciler) recordListenAddress(ctx context.Context, key types.NamespacedName, address string) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var source audiciav1alpha1.AudiciaSource
+		if err := r.Get(ctx, key, &source); err != nil {
+			return err
+		}
+		if source.Status.ListenAddress == address {
+			return nil
+		}
+		source.Status.ListenAddress = address
+		return r.Status().Update(ctx, &source)
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		ctrl.Log.WithName("pipeline").WithValues("source", key).Error(err, "failed to record listen address")
+	}
+}
+
 // setSourceCondition is a convenience wrapper for setting conditions by key.
 func (r *Reconciler) setSourceCondition(ctx context.Context, key types.NamespacedName, condition metav1.Condition) {
 	var source audiciav1alpha1.AudiciaSource
@@ -823,12 +1815,35 @@ func (r *Reconciler) setSourceCondition(ctx context.Context, key types.Namespace
 	_ = r.setCondition(ctx, &source, condition)
 }
 
-// subjectKeyString returns a unique string key for a subject.
-func subjectKeyString(s audiciav1alpha1.Subject) string {
-	if s.Namespace != "" {
-		return fmt.Sprintf("%s/%s/%s", s.Kind, s.Namespace, s.Name)
+// subjectKey indexes a pipeline's aggregators. It is a comparable struct
+// rather than a formatted string, so aggregating an event never allocates a
+// key; the string form is only built for messages.
+type subjectKey struct {
+	kind      audiciav1alpha1.SubjectKind
+	namespace string
+	name      string
+}
+
+// keyFor returns the index key for a subject.
+func keyFor(s audiciav1alpha1.Subject) subjectKey {
+	return subjectKey{kind: s.Kind, namespace: s.Namespace, name: s.Name}
+}
+
+// String returns Kind/namespace/name, or Kind/name without a namespace.
+func (k subjectKey) String() string {
+	if k.namespace != "" {
+		return string(k.kind) + "/" + k.namespace + "/" + k.name
 	}
-	return fmt.Sprintf("%s/%s", s.Kind, s.Name)
+	return string(k.kind) + "/" + k.name
+}
+
+// compareSubjectKeys orders keys by kind, namespace and name.
+func compareSubjectKeys(a, b subjectKey) int {
+	return cmp.Or(
+		cmp.Compare(a.kind, b.kind),
+		cmp.Compare(a.namespace, b.namespace),
+		cmp.Compare(a.name, b.name),
+	)
 }
 
 // sanitizeName converts a subject name into a valid Kubernetes object name
@@ -3,6 +3,11 @@ package audiciasource
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -22,6 +27,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/felixnotka/audicia/operator/pkg/aggregator"
@@ -29,6 +35,7 @@ import (
 	"github.com/felixnotka/audicia/operator/pkg/filter"
 	"github.com/felixnotka/audicia/operator/pkg/ingestor"
 	"github.com/felixnotka/audicia/operator/pkg/ingestor/cloud"
+	"github.com/felixnotka/audicia/operator/pkg/integrity"
 	"github.com/felixnotka/audicia/operator/pkg/normalizer"
 	"github.com/felixnotka/audicia/operator/pkg/rbac"
 	"github.com/felixnotka/audicia/operator/pkg/strategy"
@@ -138,7 +145,7 @@ func TestCreateIngestor_K8sAuditLog(t *testing.T) {
 		},
 	}
 
-	ing, err := createIngestor(source, logr.Discard())
+	ing, err := createIngestor(source, nil, logr.Discard())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -147,6 +154,43 @@ func TestCreateIngestor_K8sAuditLog(t *testing.T) {
 	}
 }
 
+func TestCreateIngestor_K8sAuditLog_Glob(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			SourceType: audiciav1alpha1.SourceTypeK8sAuditLog,
+			Location:   &audiciav1alpha1.FileLocation{Path: "/var/log/kubernetes/audit/*.log"},
+		},
+		Status: audiciav1alpha1.AudiciaSourceStatus{
+			Files: []audiciav1alpha1.FileCheckpointStatus{
+				{Path: "/var/log/kubernetes/audit/apiserver-1.log", FileOffset: 100, Inode: 7},
+			},
+		},
+	}
+
+	ing, err := createIngestor(source, nil, logr.Discard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	multi, ok := ing.(*ingestor.MultiFileIngestor)
+	if !ok {
+		t.Fatalf("expected *ingestor.MultiFileIngestor, got %T", ing)
+	}
+	pos := multi.StartPositions["/var/log/kubernetes/audit/apiserver-1.log"]
+	if pos.FileOffset != 100 || pos.Inode != 7 {
+		t.Errorf("start position = %+v, want offset 100, inode 7", pos)
+	}
+}
+
+func TestFileCheckpoints_SortedByPath(t *testing.T) {
+	files := fileCheckpoints(map[string]ingestor.Position{
+		"/audit/b.log": {FileOffset: 2},
+		"/audit/a.log": {FileOffset: 1},
+	})
+	if len(files) != 2 || files[0].Path != "/audit/a.log" || files[1].FileOffset != 2 {
+		t.Errorf("unexpected checkpoints %+v", files)
+	}
+}
+
 func TestCreateIngestor_K8sAuditLog_NilLocation(t *testing.T) {
 	source := audiciav1alpha1.AudiciaSource{
 		Spec: audiciav1alpha1.AudiciaSourceSpec{
@@ -155,7 +199,7 @@ func TestCreateIngestor_K8sAuditLog_NilLocation(t *testing.T) {
 		},
 	}
 
-	_, err := createIngestor(source, logr.Discard())
+	_, err := createIngestor(source, nil, logr.Discard())
 	if err == nil {
 		t.Error("expected error for nil location")
 	}
@@ -172,7 +216,7 @@ func TestCreateIngestor_Webhook(t *testing.T) {
 		},
 	}
 
-	ing, err := createIngestor(source, logr.Discard())
+	ing, err := createIngestor(source, nil, logr.Discard())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -181,6 +225,132 @@ func TestCreateIngestor_Webhook(t *testing.T) {
 	}
 }
 
+func TestCreateIngestor_Local(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			SourceType: audiciav1alpha1.SourceTypeLocal,
+			Local:      &audiciav1alpha1.LocalConfig{SocketPath: "/tmp/audicia.sock"},
+		},
+	}
+	ing, err := createIngestor(source, nil, logr.Discard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ing.(*ingestor.LocalIngestor); !ok {
+		t.Fatalf("expected *LocalIngestor, got %T", ing)
+	}
+
+	source.Spec.Local = nil
+	if _, err := createIngestor(source, nil, logr.Discard()); err == nil {
+		t.Error("expected error for nil local config")
+	}
+}
+
+func TestRunPipeline_LocalIngestionDisabled(t *testing.T) {
+	source := &audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "local-src", Namespace: "default"},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			SourceType: audiciav1alpha1.SourceTypeLocal,
+			Local:      &audiciav1alpha1.LocalConfig{Port: 18080},
+		},
+	}
+	r := newTestReconciler(source)
+	key := types.NamespacedName{Name: "local-src", Namespace: "default"}
+
+	r.runPipeline(context.Background(), key, *source, nil)
+
+	var updated audiciav1alpha1.AudiciaSource
+	if err := r.Get(context.Background(), key, &updated); err != nil {
+		t.Fatal(err)
+	}
+	cond := meta.FindStatusCondition(updated.Status.Conditions, "Ready")
+	if cond == nil || cond.Reason != "LocalIngestionDisabled" {
+		t.Errorf("Ready condition = %+v, want LocalIngestionDisabled", cond)
+	}
+}
+
+func TestServedRBACVersions(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{rbacv1.SchemeGroupVersion})
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("Role"), meta.RESTScopeNamespace)
+	r := newTestReconciler()
+	r.Client = fake.NewClientBuilder().WithScheme(newTestScheme()).WithRESTMapper(mapper).Build()
+
+	versions := r.servedRBACVersions()
+	if !slices.Contains(versions, "rbac.authorization.k8s.io/v1") {
+		t.Errorf("served versions = %v, want rbac.authorization.k8s.io/v1", versions)
+	}
+	if got, err := strategy.ResolveRBACAPIVersion("", versions); err != nil || got != "rbac.authorization.k8s.io/v1" {
+		t.Errorf("ResolveRBACAPIVersion() = %q, %v", got, err)
+	}
+}
+
+func TestCreateIngestor_Webhook_TokenReviewAuth(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "forwarded", Namespace: "audicia-system"},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			SourceType: audiciav1alpha1.SourceTypeWebhook,
+			Webhook: &audiciav1alpha1.WebhookConfig{
+				Port:          8443,
+				TLSSecretName: "tls-secret",
+				Authentication: &audiciav1alpha1.WebhookAuthentication{
+					Mode:      audiciav1alpha1.WebhookAuthModeTokenReview,
+					Audiences: []string{"audicia"},
+				},
+			},
+		},
+	}
+
+	if _, err := createIngestor(source, nil, logr.Discard()); err == nil {
+		t.Error("expected error when TokenReview auth has no client")
+	}
+
+	r := newTestReconciler()
+	ing, err := createIngestor(source, r.Client, logr.Discard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, ok := ing.(*ingestor.WebhookIngestor).Authenticator.(*ingestor.TokenReviewAuthenticator)
+	if !ok {
+		t.Fatal("expected a TokenReviewAuthenticator")
+	}
+	if auth.Attributes.Namespace != "audicia-system" || auth.Attributes.Name != "forwarded" {
+		t.Errorf("SubjectAccessReview target = %s/%s, want audicia-system/forwarded", auth.Attributes.Namespace, auth.Attributes.Name)
+	}
+	if len(auth.Audiences) != 1 || auth.Audiences[0] != "audicia" {
+		t.Errorf("Audiences = %v, want [audicia]", auth.Audiences)
+	}
+}
+
+func TestCreateIngestor_Webhook_StaticToken(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			SourceType: audiciav1alpha1.SourceTypeWebhook,
+			Webhook: &audiciav1alpha1.WebhookConfig{
+				Port:                8443,
+				TLSSecretName:       "tls-secret",
+				AuthTokenSecretName: "webhook-token",
+			},
+		},
+	}
+
+	ing, err := createIngestor(source, nil, logr.Discard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, ok := ing.(*ingestor.WebhookIngestor).Authenticator.(*ingestor.StaticTokenAuthenticator)
+	if !ok {
+		t.Fatal("expected a StaticTokenAuthenticator")
+	}
+	if auth.TokenFile.Path != "/etc/audicia/webhook-token/token" {
+		t.Errorf("TokenFile = %q", auth.TokenFile.Path)
+	}
+
+	source.Spec.Webhook.Authentication = &audiciav1alpha1.WebhookAuthentication{Mode: audiciav1alpha1.WebhookAuthModeTokenReview}
+	if _, err := createIngestor(source, newTestReconciler().Client, logr.Discard()); err == nil {
+		t.Error("expected error when combined with TokenReview")
+	}
+}
+
 func TestCreateIngestor_Webhook_TLSPathsSet(t *testing.T) {
 	source := audiciav1alpha1.AudiciaSource{
 		Spec: audiciav1alpha1.AudiciaSourceSpec{
@@ -194,7 +364,7 @@ func TestCreateIngestor_Webhook_TLSPathsSet(t *testing.T) {
 		},
 	}
 
-	ing, err := createIngestor(source, logr.Discard())
+	ing, err := createIngestor(source, nil, logr.Discard())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -217,6 +387,57 @@ func TestCreateIngestor_Webhook_TLSPathsSet(t *testing.T) {
 	}
 }
 
+func TestCreateIngestor_Webhook_BindAddress(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			SourceType: audiciav1alpha1.SourceTypeWebhook,
+			Webhook: &audiciav1alpha1.WebhookConfig{
+				Port:          8443,
+				TLSSecretName: "tls-secret",
+				BindAddress:   "::",
+			},
+		},
+	}
+
+	ing, err := createIngestor(source, nil, logr.Discard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wh := ing.(*ingestor.WebhookIngestor); wh.BindAddress != "::" {
+		t.Errorf("BindAddress = %q, want ::", wh.BindAddress)
+	}
+
+	source.Spec.Webhook.BindAddress = "audicia.example.com"
+	if _, err := createIngestor(source, nil, logr.Discard()); err == nil {
+		t.Error("expected an error for a hostname bind address")
+	}
+}
+
+func TestRecordListenAddress(t *testing.T) {
+	source := &audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook", Namespace: "audicia-system"},
+	}
+	r := newTestReconciler(source)
+	key := types.NamespacedName{Name: "webhook", Namespace: "audicia-system"}
+
+	r.recordListenAddress(context.Background(), key, "[::]:8443")
+	var got audiciav1alpha1.AudiciaSource
+	if err := r.Get(context.Background(), key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.ListenAddress != "[::]:8443" {
+		t.Errorf("status.listenAddress = %q, want [::]:8443", got.Status.ListenAddress)
+	}
+
+	r.recordListenAddress(context.Background(), key, "")
+	if err := r.Get(context.Background(), key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.ListenAddress != "" {
+		t.Errorf("status.listenAddress = %q after a non-listening start, want empty", got.Status.ListenAddress)
+	}
+}
+
 func TestCreateIngestor_Webhook_MTLSEnabled(t *testing.T) {
 	source := audiciav1alpha1.AudiciaSource{
 		Spec: audiciav1alpha1.AudiciaSourceSpec{
@@ -229,7 +450,7 @@ func TestCreateIngestor_Webhook_MTLSEnabled(t *testing.T) {
 		},
 	}
 
-	ing, err := createIngestor(source, logr.Discard())
+	ing, err := createIngestor(source, nil, logr.Discard())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -255,7 +476,7 @@ func TestCreateIngestor_Webhook_MTLSDisabledWhenEmpty(t *testing.T) {
 		},
 	}
 
-	ing, err := createIngestor(source, logr.Discard())
+	ing, err := createIngestor(source, nil, logr.Discard())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -269,6 +490,63 @@ func TestCreateIngestor_Webhook_MTLSDisabledWhenEmpty(t *testing.T) {
 	}
 }
 
+func TestCreateIngestor_Forward(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			SourceType: audiciav1alpha1.SourceTypeForward,
+			Forward: &audiciav1alpha1.ForwardConfig{
+				TLSSecretName:      "forward-tls",
+				ClientCASecretName: "forward-ca",
+				MaxMessageBytes:    1 << 20,
+			},
+		},
+	}
+
+	ing, err := createIngestor(source, nil, logr.Discard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw, ok := ing.(*ingestor.ForwardIngestor)
+	if !ok {
+		t.Fatal("expected *ingestor.ForwardIngestor")
+	}
+	if fw.Protocol != ingestor.ForwardProtocolFluentd || fw.Port != ingestor.DefaultFluentdPort {
+		t.Errorf("protocol/port = %s/%d, want Fluentd/%d", fw.Protocol, fw.Port, ingestor.DefaultFluentdPort)
+	}
+	if fw.TLSCertFile != "/etc/audicia/forward-tls/tls.crt" || fw.TLSKeyFile != "/etc/audicia/forward-tls/tls.key" {
+		t.Errorf("TLS files = %q, %q", fw.TLSCertFile, fw.TLSKeyFile)
+	}
+	if fw.ClientCAFile != "/etc/audicia/forward-client-ca/ca.crt" {
+		t.Errorf("ClientCAFile = %q, want /etc/audicia/forward-client-ca/ca.crt", fw.ClientCAFile)
+	}
+	if fw.MaxMessageBytes != 1<<20 {
+		t.Errorf("MaxMessageBytes = %d, want %d", fw.MaxMessageBytes, 1<<20)
+	}
+}
+
+func TestCreateIngestor_Forward_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		forward *audiciav1alpha1.ForwardConfig
+	}{
+		{"nil config", nil},
+		{"client CA without TLS", &audiciav1alpha1.ForwardConfig{
+			Protocol:           audiciav1alpha1.ForwardProtocolSyslog,
+			ClientCASecretName: "forward-ca",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := audiciav1alpha1.AudiciaSource{
+				Spec: audiciav1alpha1.AudiciaSourceSpec{SourceType: audiciav1alpha1.SourceTypeForward, Forward: tt.forward},
+			}
+			if _, err := createIngestor(source, nil, logr.Discard()); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
 func TestCreateIngestor_Webhook_NilConfig(t *testing.T) {
 	source := audiciav1alpha1.AudiciaSource{
 		Spec: audiciav1alpha1.AudiciaSourceSpec{
@@ -277,7 +555,7 @@ func TestCreateIngestor_Webhook_NilConfig(t *testing.T) {
 		},
 	}
 
-	_, err := createIngestor(source, logr.Discard())
+	_, err := createIngestor(source, nil, logr.Discard())
 	if err == nil {
 		t.Error("expected error for nil webhook config")
 	}
@@ -290,7 +568,7 @@ func TestCreateIngestor_UnknownSourceType(t *testing.T) {
 		},
 	}
 
-	_, err := createIngestor(source, logr.Discard())
+	_, err := createIngestor(source, nil, logr.Discard())
 	if err == nil {
 		t.Error("expected error for unknown source type")
 	}
@@ -305,7 +583,7 @@ func TestCreateIngestor_K8sAuditLog_DefaultBatchSize(t *testing.T) {
 		},
 	}
 
-	ing, err := createIngestor(source, logr.Discard())
+	ing, err := createIngestor(source, nil, logr.Discard())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -356,11 +634,11 @@ func TestSanitizeName_TrailingDash(t *testing.T) {
 	}
 }
 
-// --- subjectKeyString ---
+// --- subjectKey ---
 
 func TestSubjectKeyString_WithNamespace(t *testing.T) {
 	s := audiciav1alpha1.Subject{Kind: "ServiceAccount", Name: "backend", Namespace: "prod"}
-	got := subjectKeyString(s)
+	got := keyFor(s).String()
 	if got != "ServiceAccount/prod/backend" {
 		t.Errorf("got %q, want ServiceAccount/prod/backend", got)
 	}
@@ -368,7 +646,7 @@ func TestSubjectKeyString_WithNamespace(t *testing.T) {
 
 func TestSubjectKeyString_WithoutNamespace(t *testing.T) {
 	s := audiciav1alpha1.Subject{Kind: "User", Name: "alice"}
-	got := subjectKeyString(s)
+	got := keyFor(s).String()
 	if got != "User/alice" {
 		t.Errorf("got %q, want User/alice", got)
 	}
@@ -475,6 +753,11 @@ func TestReconcile_PipelineAlreadyRunning(t *testing.T) {
 }
 
 func TestReconcile_StartsNewPipeline(t *testing.T) {
+	// A readable audit log, since an unreadable one sets Ready=False.
+	auditLog := filepath.Join(t.TempDir(), "audit.log")
+	if err := os.WriteFile(auditLog, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
 	source := &audiciav1alpha1.AudiciaSource{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:       "new-source",
@@ -483,7 +766,7 @@ func TestReconcile_StartsNewPipeline(t *testing.T) {
 		},
 		Spec: audiciav1alpha1.AudiciaSourceSpec{
 			SourceType: audiciav1alpha1.SourceTypeK8sAuditLog,
-			Location:   &audiciav1alpha1.FileLocation{Path: "/tmp/nonexistent.log"},
+			Location:   &audiciav1alpha1.FileLocation{Path: auditLog},
 		},
 	}
 
@@ -621,8 +904,8 @@ func TestProcessEvent_Accepted(t *testing.T) {
 	}
 
 	chain, _ := filter.NewChain(nil)
-	aggregators := make(map[string]*aggregator.Aggregator)
-	subjects := make(map[string]audiciav1alpha1.Subject)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
 
 	event := auditv1.Event{
 		Verb: "get",
@@ -635,7 +918,7 @@ func TestProcessEvent_Accepted(t *testing.T) {
 		RequestURI: "/api/v1/namespaces/default/pods",
 	}
 
-	r.processEvent(event, source, chain, aggregators, subjects)
+	r.processEvent(event, source, chain, nil, nil, aggregators, subjects)
 
 	if len(aggregators) != 1 {
 		t.Errorf("expected 1 subject aggregator, got %d", len(aggregators))
@@ -671,8 +954,8 @@ func TestProcessEvent_DeniedByFilter(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	aggregators := make(map[string]*aggregator.Aggregator)
-	subjects := make(map[string]audiciav1alpha1.Subject)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
 
 	event := auditv1.Event{
 		Verb: "get",
@@ -683,7 +966,7 @@ func TestProcessEvent_DeniedByFilter(t *testing.T) {
 		},
 	}
 
-	r.processEvent(event, source, chain, aggregators, subjects)
+	r.processEvent(event, source, chain, nil, nil, aggregators, subjects)
 
 	if len(aggregators) != 0 {
 		t.Errorf("expected 0 aggregators (event denied by filter), got %d", len(aggregators))
@@ -699,8 +982,8 @@ func TestProcessEvent_SystemUserFiltered(t *testing.T) {
 	}
 
 	chain, _ := filter.NewChain(nil)
-	aggregators := make(map[string]*aggregator.Aggregator)
-	subjects := make(map[string]audiciav1alpha1.Subject)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
 
 	event := auditv1.Event{
 		Verb: "get",
@@ -711,7 +994,7 @@ func TestProcessEvent_SystemUserFiltered(t *testing.T) {
 		},
 	}
 
-	r.processEvent(event, source, chain, aggregators, subjects)
+	r.processEvent(event, source, chain, nil, nil, aggregators, subjects)
 
 	if len(aggregators) != 0 {
 		t.Errorf("expected 0 aggregators (system user filtered), got %d", len(aggregators))
@@ -727,8 +1010,8 @@ func TestProcessEvent_MultipleSubjects(t *testing.T) {
 	}
 
 	chain, _ := filter.NewChain(nil)
-	aggregators := make(map[string]*aggregator.Aggregator)
-	subjects := make(map[string]audiciav1alpha1.Subject)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
 
 	events := []auditv1.Event{
 		{
@@ -750,7 +1033,7 @@ func TestProcessEvent_MultipleSubjects(t *testing.T) {
 	}
 
 	for _, e := range events {
-		r.processEvent(e, source, chain, aggregators, subjects)
+		r.processEvent(e, source, chain, nil, nil, aggregators, subjects)
 	}
 
 	if len(aggregators) != 2 {
@@ -775,11 +1058,16 @@ func TestPopulateReportStatus(t *testing.T) {
 		makeObservedRule("pods", "get", "default", time.Now()),
 	}
 
-	r.populateReportStatus(context.Background(), report, subject, rules, 5, logr.Discard())
+	denied := []audiciav1alpha1.ObservedRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}, Count: 1}}
+	r.populateReportStatus(context.Background(), report, subject, rules, denied, nil, 5, logr.Discard())
 
 	if len(report.Status.ObservedRules) != 1 {
 		t.Errorf("expected 1 observed rule, got %d", len(report.Status.ObservedRules))
 	}
+
+	if len(report.Status.DeniedRules) != 1 {
+		t.Errorf("expected 1 denied rule, got %d", len(report.Status.DeniedRules))
+	}
 	if report.Status.EventsProcessed != 5 {
 		t.Errorf("expected 5 events processed, got %d", report.Status.EventsProcessed)
 	}
@@ -799,6 +1087,53 @@ func TestPopulateReportStatus(t *testing.T) {
 	}
 }
 
+func TestIgnoredVerbs(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{Spec: audiciav1alpha1.AudiciaSourceSpec{
+		PolicyStrategy: audiciav1alpha1.PolicyStrategy{MinCount: 5},
+	}}
+	frequent := makeObservedRule("pods", "get", "default", time.Now())
+	frequent.Count = 5
+	rules := []audiciav1alpha1.ObservedRule{
+		frequent,
+		makeObservedRule("secrets", "get", "default", time.Now()),
+		makeObservedRule("services/proxy", "proxy", "default", time.Now()),
+	}
+
+	r := &Reconciler{}
+	got := map[string]audiciav1alpha1.IgnoredVerbReason{}
+	for _, ignored := range r.ignoredVerbs(source, rules) {
+		got[ignored.Resources[0]+":"+ignored.Verb] = ignored.Reason
+	}
+	want := map[string]audiciav1alpha1.IgnoredVerbReason{
+		"secrets:get":          audiciav1alpha1.IgnoredVerbBelowThreshold,
+		"services/proxy:proxy": audiciav1alpha1.IgnoredVerbNonStandard,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ignoredVerbs() = %v, want %v", got, want)
+	}
+}
+
+func TestRecordIntegrity(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{
+		Spec: audiciav1alpha1.AudiciaSourceSpec{Integrity: &audiciav1alpha1.IntegrityConfig{}},
+	}
+	report := &audiciav1alpha1.AudiciaReport{}
+	report.Status.ObservedRules = []audiciav1alpha1.ObservedRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}}
+
+	recordIntegrity(source, report, nil, logr.Discard())
+	if report.Status.Integrity == nil || len(report.Status.Integrity.Entries) != 1 {
+		t.Fatalf("expected one chain entry, got %+v", report.Status.Integrity)
+	}
+	if err := integrity.Verify(report.Status.ObservedRules, report.Status.Integrity, ""); err != nil {
+		t.Errorf("Verify() = %v", err)
+	}
+
+	recordIntegrity(audiciav1alpha1.AudiciaSource{}, report, nil, logr.Discard())
+	if report.Status.Integrity != nil {
+		t.Error("expected the chain to be removed when spec.integrity is unset")
+	}
+}
+
 // --- setCondition ---
 
 func TestSetCondition(t *testing.T) {
@@ -858,7 +1193,7 @@ func TestFlushReport(t *testing.T) {
 		makeObservedRule("pods", "get", "default", time.Now()),
 	}
 
-	err := r.flushReport(context.Background(), source, subject, rules, 3, logr.Discard())
+	err := r.flushReport(context.Background(), source, subject, rules, nil, nil, 3, true, logr.Discard())
 	if err != nil {
 		t.Fatalf("flushReport: %v", err)
 	}
@@ -888,6 +1223,44 @@ func TestFlushReport(t *testing.T) {
 	}
 }
 
+func TestFlushSubject_StampsGenerator(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "stamp-source", Namespace: "default"},
+	}
+	r := newTestReconciler(&source)
+	r.Generator = audiciav1alpha1.GeneratorInfo{Version: "v0.6.0", Commit: "abc1234"}
+	engine := strategy.NewEngine(audiciav1alpha1.PolicyStrategy{})
+	engine.Generator = r.Generator
+
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "stamped", Namespace: "default"}
+	agg := aggregator.New()
+	agg.Add(normalizer.CanonicalRule{Resource: "pods", Verb: "get", Namespace: "default"}, time.Now())
+	if err := r.flushSubject(context.Background(), source, engine, subject, agg, true, logr.Discard()); err != nil {
+		t.Fatalf("flushSubject: %v", err)
+	}
+
+	var report audiciav1alpha1.AudiciaReport
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "report-stamped", Namespace: "default"}, &report); err != nil {
+		t.Fatalf("get report: %v", err)
+	}
+	if report.Status.GeneratedBy == nil || *report.Status.GeneratedBy != r.Generator {
+		t.Errorf("report generatedBy = %+v, want %+v", report.Status.GeneratedBy, r.Generator)
+	}
+
+	var policy audiciav1alpha1.AudiciaPolicy
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "policy-stamped", Namespace: "default"}, &policy); err != nil {
+		t.Fatalf("get policy: %v", err)
+	}
+	if policy.Status.GeneratedBy == nil || *policy.Status.GeneratedBy != r.Generator {
+		t.Errorf("policy generatedBy = %+v, want %+v", policy.Status.GeneratedBy, r.Generator)
+	}
+	for _, m := range policy.Spec.Manifests {
+		if !strings.Contains(m, strategy.GeneratorVersionAnnotation+": v0.6.0") {
+			t.Errorf("manifest missing generator version:\n%s", m)
+		}
+	}
+}
+
 // --- restoreCloudCheckpoint ---
 
 func TestRestoreCloudCheckpoint_Empty(t *testing.T) {
@@ -934,12 +1307,60 @@ func TestCreateCloudIngestor_NilConfig(t *testing.T) {
 		},
 	}
 
-	_, err := createIngestor(source, logr.Discard())
+	_, err := createIngestor(source, nil, logr.Discard())
 	if err == nil {
 		t.Error("expected error for nil cloud config")
 	}
 }
 
+func TestCreateCloudIngestor_StrictUnsupportedForAWS(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			SourceType: audiciav1alpha1.SourceTypeCloudAuditLog,
+			Cloud: &audiciav1alpha1.CloudConfig{
+				Provider:                   audiciav1alpha1.CloudProviderAWSCloudWatch,
+				ClusterIdentity:            "arn:aws:eks:eu-central-1:123456789012:cluster/my-cluster",
+				ClusterIdentityEnforcement: audiciav1alpha1.ClusterIdentityEnforcementStrict,
+				AWS:                        &audiciav1alpha1.AWSCloudWatchConfig{LogGroupName: "/aws/eks/my-cluster/cluster"},
+			},
+		},
+	}
+
+	_, err := createIngestor(source, nil, logr.Discard())
+	if err == nil || !strings.Contains(err.Error(), "Strict is not supported") {
+		t.Errorf("err = %v, want Strict rejected for AWSCloudWatch", err)
+	}
+}
+
+// BenchmarkProcessEvent measures the per-event hot path. Indexing by the
+// subjectKey struct and caching alias resolution keep it allocation-free.
+func BenchmarkProcessEvent(b *testing.B) {
+	aliases, err := normalizer.NewSubjectAliases([]audiciav1alpha1.SubjectAlias{{
+		UserPattern: "^oidc:(.+)$",
+		Subject:     audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "$1"},
+	}})
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, username := range []string{"system:serviceaccount:default:my-sa", "alice", "oidc:bob"} {
+		b.Run(username, func(b *testing.B) {
+			r := &Reconciler{}
+			chain, _ := filter.NewChain(nil)
+			aggregators := make(map[subjectKey]*aggregator.Aggregator)
+			subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+			event := auditv1.Event{
+				Verb:      "get",
+				User:      authnv1.UserInfo{Username: username},
+				ObjectRef: &auditv1.ObjectReference{Resource: "pods", Namespace: "default", Name: "web-0"},
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				r.processEvent(event, audiciav1alpha1.AudiciaSource{}, chain, aliases, nil, aggregators, subjects)
+			}
+		})
+	}
+}
+
 // --- processEvent edge cases ---
 
 func TestProcessEvent_NilObjectRef_NoRequestURI_Skipped(t *testing.T) {
@@ -951,8 +1372,8 @@ func TestProcessEvent_NilObjectRef_NoRequestURI_Skipped(t *testing.T) {
 	}
 
 	chain, _ := filter.NewChain(nil)
-	aggregators := make(map[string]*aggregator.Aggregator)
-	subjects := make(map[string]audiciav1alpha1.Subject)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
 
 	event := auditv1.Event{
 		Verb:      "get",
@@ -960,7 +1381,7 @@ func TestProcessEvent_NilObjectRef_NoRequestURI_Skipped(t *testing.T) {
 		ObjectRef: nil, // No ObjectRef and no RequestURI — unresolvable, should be skipped.
 	}
 
-	r.processEvent(event, source, chain, aggregators, subjects)
+	r.processEvent(event, source, chain, nil, nil, aggregators, subjects)
 
 	if len(aggregators) != 0 {
 		t.Errorf("expected 0 aggregators (unresolvable event skipped), got %d", len(aggregators))
@@ -976,8 +1397,8 @@ func TestProcessEvent_NilObjectRef_WithRequestURI(t *testing.T) {
 	}
 
 	chain, _ := filter.NewChain(nil)
-	aggregators := make(map[string]*aggregator.Aggregator)
-	subjects := make(map[string]audiciav1alpha1.Subject)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
 
 	event := auditv1.Event{
 		Verb:       "get",
@@ -986,13 +1407,67 @@ func TestProcessEvent_NilObjectRef_WithRequestURI(t *testing.T) {
 		RequestURI: "/metrics", // Non-resource URL — should be accepted.
 	}
 
-	r.processEvent(event, source, chain, aggregators, subjects)
+	r.processEvent(event, source, chain, nil, nil, aggregators, subjects)
 
 	if len(aggregators) != 1 {
 		t.Errorf("expected 1 aggregator (non-resource URL), got %d", len(aggregators))
 	}
 }
 
+func TestProcessEvent_GroupTracking(t *testing.T) {
+	r := &Reconciler{}
+	source := audiciav1alpha1.AudiciaSource{
+		Spec: audiciav1alpha1.AudiciaSourceSpec{IgnoreSystemUsers: true},
+	}
+	groups, err := normalizer.NewGroupTracker(&audiciav1alpha1.SubjectTrackingConfig{Groups: true}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain, _ := filter.NewChain(nil)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+
+	for _, e := range []auditv1.Event{
+		{
+			Verb:      "get",
+			User:      authnv1.UserInfo{Username: "alice", Groups: []string{"devs", "system:authenticated"}},
+			ObjectRef: &auditv1.ObjectReference{Resource: "pods", Namespace: "default"},
+		},
+		{
+			// The system user is ignored, but its group is still tracked.
+			Verb:      "list",
+			User:      authnv1.UserInfo{Username: "system:kube-proxy", Groups: []string{"devs"}},
+			ObjectRef: &auditv1.ObjectReference{Resource: "services", Namespace: "default"},
+		},
+	} {
+		r.processEvent(e, source, chain, nil, groups, aggregators, subjects)
+	}
+
+	if len(aggregators) != 2 {
+		t.Fatalf("expected aggregators for alice and devs, got %v", subjects)
+	}
+	devs := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindGroup, Name: "devs"}
+	agg, ok := aggregators[keyFor(devs)]
+	if !ok {
+		t.Fatalf("no aggregator for group devs: %v", subjects)
+	}
+	if got := len(agg.Rules()); got != 2 {
+		t.Errorf("group rules = %d, want 2", got)
+	}
+}
+
+func TestSubjectNames_GroupPrefixed(t *testing.T) {
+	user := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "devs"}
+	group := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindGroup, Name: "devs"}
+	if reportNameFor(user) != "report-devs" || policyNameFor(user) != "policy-devs" {
+		t.Errorf("user names = %s, %s", reportNameFor(user), policyNameFor(user))
+	}
+	if reportNameFor(group) != "report-group-devs" || policyNameFor(group) != "policy-group-devs" {
+		t.Errorf("group names = %s, %s", reportNameFor(group), policyNameFor(group))
+	}
+}
+
 func TestProcessEvent_ExplicitTimestamp(t *testing.T) {
 	r := &Reconciler{}
 	source := audiciav1alpha1.AudiciaSource{
@@ -1002,8 +1477,8 @@ func TestProcessEvent_ExplicitTimestamp(t *testing.T) {
 	}
 
 	chain, _ := filter.NewChain(nil)
-	aggregators := make(map[string]*aggregator.Aggregator)
-	subjects := make(map[string]audiciav1alpha1.Subject)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
 
 	ts := metav1.NewMicroTime(time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC))
 	event := auditv1.Event{
@@ -1013,7 +1488,7 @@ func TestProcessEvent_ExplicitTimestamp(t *testing.T) {
 		RequestReceivedTimestamp: ts,
 	}
 
-	r.processEvent(event, source, chain, aggregators, subjects)
+	r.processEvent(event, source, chain, nil, nil, aggregators, subjects)
 
 	for _, agg := range aggregators {
 		rules := agg.Rules()
@@ -1026,6 +1501,321 @@ func TestProcessEvent_ExplicitTimestamp(t *testing.T) {
 	}
 }
 
+func TestProcessEvent_SubjectAliasesMergeIdentities(t *testing.T) {
+	r := newTestReconciler()
+	source := audiciav1alpha1.AudiciaSource{
+		Spec: audiciav1alpha1.AudiciaSourceSpec{IgnoreSystemUsers: true},
+	}
+
+	chain, _ := filter.NewChain(nil)
+	aliases, err := normalizer.NewSubjectAliases([]audiciav1alpha1.SubjectAlias{{
+		UserPattern: `^cluster-[a-z]+:system:serviceaccount:([^:]+):([^:]+)$`,
+		Subject:     audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Namespace: "$1", Name: "$2"},
+	}})
+	if err != nil {
+		t.Fatalf("NewSubjectAliases() error = %v", err)
+	}
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+
+	for _, user := range []string{
+		"cluster-east:system:serviceaccount:shop:cart",
+		"cluster-west:system:serviceaccount:shop:cart",
+		"system:serviceaccount:shop:cart",
+	} {
+		r.processEvent(auditv1.Event{
+			Verb:      "get",
+			User:      authnv1.UserInfo{Username: user},
+			ObjectRef: &auditv1.ObjectReference{Resource: "pods", Namespace: "shop"},
+		}, source, chain, aliases, nil, aggregators, subjects)
+	}
+
+	if len(subjects) != 1 {
+		t.Fatalf("expected identities to merge into 1 subject, got %v", subjects)
+	}
+	agg := aggregators[subjectKey{kind: audiciav1alpha1.SubjectKindServiceAccount, namespace: "shop", name: "cart"}]
+	if agg == nil || agg.EventsProcessed() != 3 {
+		t.Errorf("expected 3 events under the logical subject, got %+v", agg)
+	}
+}
+
+func TestProcessEvent_CollapseHousekeeping(t *testing.T) {
+	r := newTestReconciler()
+	chain, _ := filter.NewChain(nil)
+
+	for _, collapse := range []bool{false, true} {
+		source := audiciav1alpha1.AudiciaSource{
+			Spec: audiciav1alpha1.AudiciaSourceSpec{CollapseHousekeeping: collapse},
+		}
+		aggregators := make(map[subjectKey]*aggregator.Aggregator)
+		subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+
+		for _, verb := range []string{"create", "patch", "update"} {
+			r.processEvent(auditv1.Event{
+				Verb:      verb,
+				User:      authnv1.UserInfo{Username: "system:serviceaccount:default:ctrl"},
+				ObjectRef: &auditv1.ObjectReference{Resource: "events", Namespace: "default"},
+			}, source, chain, nil, nil, aggregators, subjects)
+		}
+
+		rules := aggregators[subjectKey{kind: audiciav1alpha1.SubjectKindServiceAccount, namespace: "default", name: "ctrl"}].Rules()
+		want := 3
+		if collapse {
+			want = 1
+		}
+		if len(rules) != want {
+			t.Errorf("collapse=%v: got %d rules, want %d", collapse, len(rules), want)
+		}
+		if collapse && rules[0].Preset != normalizer.PresetEvents {
+			t.Errorf("expected events preset, got %+v", rules[0])
+		}
+	}
+}
+
+func TestProcessEvent_IncompleteStages(t *testing.T) {
+	r := newTestReconciler()
+	chain, _ := filter.NewChain(nil)
+
+	for _, capture := range []bool{false, true} {
+		source := audiciav1alpha1.AudiciaSource{
+			Spec: audiciav1alpha1.AudiciaSourceSpec{CaptureIncompleteStages: capture},
+		}
+		aggregators := make(map[subjectKey]*aggregator.Aggregator)
+		subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+
+		for _, stage := range []auditv1.Stage{auditv1.StageResponseStarted, auditv1.StagePanic} {
+			r.processEvent(auditv1.Event{
+				Stage:     stage,
+				Verb:      "watch",
+				User:      authnv1.UserInfo{Username: "alice"},
+				ObjectRef: &auditv1.ObjectReference{Resource: "pods", Namespace: "default"},
+			}, source, chain, nil, nil, aggregators, subjects)
+		}
+
+		agg, ok := aggregators[subjectKey{kind: audiciav1alpha1.SubjectKindUser, name: "alice"}]
+		if !capture {
+			if ok {
+				t.Error("expected incomplete stages to be dropped by default")
+			}
+			continue
+		}
+		rules := agg.Rules()
+		if len(rules) != 1 || rules[0].Count != 2 || !rules[0].Incomplete {
+			t.Errorf("expected one incomplete rule counted twice, got %+v", rules)
+		}
+	}
+}
+
+func TestProcessEvent_DeniedRequests(t *testing.T) {
+	r := newTestReconciler()
+	chain, _ := filter.NewChain(nil)
+
+	for _, include := range []bool{false, true} {
+		source := audiciav1alpha1.AudiciaSource{
+			Spec: audiciav1alpha1.AudiciaSourceSpec{IncludeDenied: include},
+		}
+		aggregators := make(map[subjectKey]*aggregator.Aggregator)
+		subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+
+		for _, code := range []int32{http.StatusForbidden, http.StatusForbidden, http.StatusUnauthorized} {
+			rule := r.processEvent(auditv1.Event{
+				Verb:           "get",
+				User:           authnv1.UserInfo{Username: "alice"},
+				ObjectRef:      &auditv1.ObjectReference{Resource: "secrets", Namespace: "default"},
+				ResponseStatus: &metav1.Status{Code: code},
+			}, source, chain, nil, nil, aggregators, subjects)
+			want := "denied"
+			if include && code == http.StatusForbidden {
+				want = ""
+			}
+			if rule != want {
+				t.Errorf("includeDenied=%v: filter rule = %q, want %q", include, rule, want)
+			}
+		}
+
+		agg, ok := aggregators[userKey("alice")]
+		if !include {
+			if ok {
+				t.Error("expected denied requests to be dropped by default")
+			}
+			continue
+		}
+		if rules := agg.Rules(); len(rules) != 0 {
+			t.Errorf("expected no observed rules, got %+v", rules)
+		}
+		if denied := agg.DeniedRules(); len(denied) != 1 || denied[0].Count != 2 {
+			t.Errorf("expected one denied rule counted twice, got %+v", denied)
+		}
+	}
+}
+
+func TestIsAdmissionDenied(t *testing.T) {
+	allowed := map[string]string{authorizationDecisionAnnotation: "allow"}
+	tests := []struct {
+		name        string
+		event       auditv1.Event
+		want        bool
+		wantForbids bool
+	}{
+		{"success", auditv1.Event{ResponseStatus: &metav1.Status{Code: http.StatusCreated}, Annotations: allowed}, false, false},
+		{"rbac denial", auditv1.Event{ResponseStatus: &metav1.Status{Code: http.StatusForbidden}, Annotations: map[string]string{authorizationDecisionAnnotation: "forbid"}}, false, true},
+		{"rbac denial without annotation", auditv1.Event{ResponseStatus: &metav1.Status{Code: http.StatusForbidden, Message: `secrets is forbidden: User "alice" cannot get resource "secrets"`}}, false, true},
+		{"authorized 403", auditv1.Event{ResponseStatus: &metav1.Status{Code: http.StatusForbidden, Message: "exceeded quota: compute"}, Annotations: allowed}, true, false},
+		{"webhook message", auditv1.Event{ResponseStatus: &metav1.Status{Code: http.StatusForbidden, Message: `admission webhook "validate.kyverno.svc" denied the request`}}, true, false},
+		{"policy message", auditv1.Event{ResponseStatus: &metav1.Status{Code: http.StatusUnprocessableEntity, Message: `deployments.apps "web" is forbidden: ValidatingAdmissionPolicy 'replicas' with binding 'replicas' denied request`}}, true, false},
+		{"validation error", auditv1.Event{ResponseStatus: &metav1.Status{Code: http.StatusUnprocessableEntity, Message: "spec.replicas: Invalid value"}, Annotations: allowed}, false, false},
+		{"unauthenticated", auditv1.Event{ResponseStatus: &metav1.Status{Code: http.StatusUnauthorized, Message: "admission webhook"}}, false, false},
+	}
+	for _, tt := range tests {
+		if got := isAdmissionDenied(tt.event); got != tt.want {
+			t.Errorf("%s: isAdmissionDenied() = %v, want %v", tt.name, got, tt.want)
+		}
+		if got := isForbidden(tt.event); got != tt.wantForbids {
+			t.Errorf("%s: isForbidden() = %v, want %v", tt.name, got, tt.wantForbids)
+		}
+	}
+}
+
+func TestProcessEvent_AdmissionDenied(t *testing.T) {
+	r := newTestReconciler()
+	chain, _ := filter.NewChain(nil)
+	source := audiciav1alpha1.AudiciaSource{}
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+
+	for _, code := range []int32{http.StatusCreated, http.StatusForbidden, http.StatusForbidden} {
+		rule := r.processEvent(auditv1.Event{
+			Verb:           "create",
+			User:           authnv1.UserInfo{Username: "alice"},
+			ObjectRef:      &auditv1.ObjectReference{Resource: "pods", Namespace: "default"},
+			ResponseStatus: &metav1.Status{Code: code},
+			Annotations:    map[string]string{authorizationDecisionAnnotation: "allow"},
+		}, source, chain, nil, nil, aggregators, subjects)
+		if rule != "" {
+			t.Errorf("status %d: filter rule = %q, want none", code, rule)
+		}
+	}
+
+	agg := aggregators[userKey("alice")]
+	if agg == nil {
+		t.Fatal("expected admission-denied requests to be aggregated")
+	}
+	rules := agg.Rules()
+	if len(rules) != 1 || rules[0].Count != 3 || rules[0].AdmissionDenied != 2 {
+		t.Errorf("expected one rule counted three times, twice denied by admission, got %+v", rules)
+	}
+	if denied := agg.DeniedRules(); len(denied) != 0 {
+		t.Errorf("expected no denied rules, got %+v", denied)
+	}
+}
+
+func TestIsDryRun(t *testing.T) {
+	deleteOptions := func(raw string) *runtime.Unknown { return &runtime.Unknown{Raw: []byte(raw)} }
+	tests := []struct {
+		name  string
+		event auditv1.Event
+		want  bool
+	}{
+		{"plain request", auditv1.Event{Verb: "create", RequestURI: "/api/v1/namespaces/default/configmaps"}, false},
+		{"kubectl dry run", auditv1.Event{Verb: "create", RequestURI: "/api/v1/namespaces/default/configmaps?dryRun=All&fieldManager=kubectl-create"}, true},
+		{"server-side apply dry run", auditv1.Event{Verb: "patch", RequestURI: "/apis/apps/v1/namespaces/default/deployments/web?fieldManager=kubectl&dryRun=All&force=false"}, true},
+		{"empty dryRun", auditv1.Event{Verb: "create", RequestURI: "/api/v1/namespaces/default/pods?dryRun="}, false},
+		{"similar parameter", auditv1.Event{Verb: "get", RequestURI: "/api/v1/pods?notdryRun=All"}, false},
+		{"delete options", auditv1.Event{Verb: "delete", RequestObject: deleteOptions(`{"kind":"DeleteOptions","dryRun":["All"]}`)}, true},
+		{"delete without dry run", auditv1.Event{Verb: "delete", RequestObject: deleteOptions(`{"kind":"DeleteOptions"}`)}, false},
+	}
+	for _, tt := range tests {
+		if got := isDryRun(tt.event); got != tt.want {
+			t.Errorf("%s: isDryRun() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProcessEvent_ExcludeDryRun(t *testing.T) {
+	r := newTestReconciler()
+	chain, _ := filter.NewChain(nil)
+	event := auditv1.Event{
+		Verb:       "create",
+		RequestURI: "/api/v1/namespaces/default/configmaps?dryRun=All",
+		User:       authnv1.UserInfo{Username: "alice"},
+		ObjectRef:  &auditv1.ObjectReference{Resource: "configmaps", Namespace: "default"},
+	}
+
+	for _, exclude := range []bool{false, true} {
+		source := audiciav1alpha1.AudiciaSource{Spec: audiciav1alpha1.AudiciaSourceSpec{ExcludeDryRun: exclude}}
+		aggregators := make(map[subjectKey]*aggregator.Aggregator)
+		rule := r.processEvent(event, source, chain, nil, nil, aggregators, make(map[subjectKey]audiciav1alpha1.Subject))
+		want := ""
+		if exclude {
+			want = "dry_run"
+		}
+		if rule != want {
+			t.Errorf("excludeDryRun=%v: filter rule = %q, want %q", exclude, rule, want)
+		}
+		if _, ok := aggregators[userKey("alice")]; ok == exclude {
+			t.Errorf("excludeDryRun=%v: aggregated = %v", exclude, ok)
+		}
+	}
+}
+
+func TestProcessEvent_ActivityTimeZone(t *testing.T) {
+	r := newTestReconciler()
+	chain, _ := filter.NewChain(nil)
+	source := audiciav1alpha1.AudiciaSource{
+		Spec: audiciav1alpha1.AudiciaSourceSpec{ActivityTimeZone: "Asia/Tokyo"},
+	}
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+
+	// Saturday 20:00 UTC is Sunday 05:00 in Tokyo.
+	received := time.Date(2026, 2, 28, 20, 0, 0, 0, time.UTC)
+	r.processEvent(auditv1.Event{
+		Verb:                     "get",
+		User:                     authnv1.UserInfo{Username: "alice"},
+		ObjectRef:                &auditv1.ObjectReference{Resource: "pods", Namespace: "default"},
+		RequestReceivedTimestamp: metav1.NewMicroTime(received),
+	}, source, chain, nil, nil, aggregators, subjects)
+
+	activity := aggregators[userKey("alice")].Activity()
+	if activity.ByHour[5] != 1 || activity.ByDay[6] != 1 {
+		t.Errorf("activity = %+v, want Sunday 05:00", activity)
+	}
+	if _, err := activityLocation("Nowhere/Atlantis"); err == nil {
+		t.Error("expected an unknown time zone to fail")
+	}
+}
+
+func TestStageAllowed(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  audiciav1alpha1.AudiciaSourceSpec
+		stage auditv1.Stage
+		want  bool
+	}{
+		{"default complete", audiciav1alpha1.AudiciaSourceSpec{}, auditv1.StageResponseComplete, true},
+		{"default request received", audiciav1alpha1.AudiciaSourceSpec{}, auditv1.StageRequestReceived, false},
+		{"default panic", audiciav1alpha1.AudiciaSourceSpec{}, auditv1.StagePanic, false},
+		{"no stage", audiciav1alpha1.AudiciaSourceSpec{}, "", true},
+		{"capture incomplete", audiciav1alpha1.AudiciaSourceSpec{CaptureIncompleteStages: true}, auditv1.StageResponseStarted, true},
+		{"capture incomplete keeps default", audiciav1alpha1.AudiciaSourceSpec{CaptureIncompleteStages: true}, auditv1.StageRequestReceived, false},
+		{
+			"explicit stages", audiciav1alpha1.AudiciaSourceSpec{Stages: []audiciav1alpha1.AuditStage{audiciav1alpha1.AuditStageRequestReceived}},
+			auditv1.StageRequestReceived, true,
+		},
+		{
+			"explicit stages replace default", audiciav1alpha1.AudiciaSourceSpec{Stages: []audiciav1alpha1.AuditStage{audiciav1alpha1.AuditStageRequestReceived}},
+			auditv1.StageResponseComplete, false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stageAllowed(tt.spec, tt.stage); got != tt.want {
+				t.Errorf("stageAllowed(%q) = %v, want %v", tt.stage, got, tt.want)
+			}
+		})
+	}
+}
+
 // --- setSourceCondition ---
 
 func TestSetSourceCondition(t *testing.T) {
@@ -1103,7 +1893,7 @@ func TestFlushCheckpoint(t *testing.T) {
 		LastTimestamp: "2025-06-15T12:00:00Z",
 	}}
 
-	r.flushCheckpoint(context.Background(), key, ing)
+	r.flushCheckpoint(context.Background(), key, ing, nil)
 
 	var updated audiciav1alpha1.AudiciaSource
 	if err := r.Get(context.Background(), key, &updated); err != nil {
@@ -1115,6 +1905,78 @@ func TestFlushCheckpoint(t *testing.T) {
 	if updated.Status.LastTimestamp == nil {
 		t.Fatal("expected non-nil LastTimestamp")
 	}
+	if updated.Status.LastCheckpointTime == nil {
+		t.Fatal("expected non-nil LastCheckpointTime")
+	}
+	if !meta.IsStatusConditionTrue(updated.Status.Conditions, "CheckpointHealthy") {
+		t.Errorf("expected CheckpointHealthy=True, got %+v", updated.Status.Conditions)
+	}
+}
+
+func TestFlushCheckpoint_WriteFailureSetsCondition(t *testing.T) {
+	lastSuccess := metav1.NewTime(time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC))
+	source := &audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "ckpt-fail", Namespace: "default"},
+		Status:     audiciav1alpha1.AudiciaSourceStatus{LastCheckpointTime: &lastSuccess},
+	}
+
+	s := newTestScheme()
+	recorder := events.NewFakeRecorder(10)
+	r := &Reconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(s).
+			WithObjects(source).
+			WithStatusSubresource(&audiciav1alpha1.AudiciaSource{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				// Reject checkpoint writes but let the failure condition through.
+				SubResourceUpdate: func(ctx context.Context, c client.Client, sub string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+					src := obj.(*audiciav1alpha1.AudiciaSource)
+					if meta.IsStatusConditionTrue(src.Status.Conditions, "CheckpointHealthy") {
+						return errors.NewForbidden(schema.GroupResource{Group: "audicia.io", Resource: "audiciasources"}, src.Name, fmt.Errorf("denied"))
+					}
+					return c.SubResource(sub).Update(ctx, obj, opts...)
+				},
+			}).
+			Build(),
+		Scheme:    s,
+		Recorder:  recorder,
+		pipelines: make(map[types.NamespacedName]*pipelineState),
+	}
+	key := types.NamespacedName{Name: "ckpt-fail", Namespace: "default"}
+
+	r.flushCheckpoint(context.Background(), key, &fakeIngestor{pos: ingestor.Position{FileOffset: 10}}, nil)
+
+	var updated audiciav1alpha1.AudiciaSource
+	if err := r.Get(context.Background(), key, &updated); err != nil {
+		t.Fatalf("get source: %v", err)
+	}
+	cond := meta.FindStatusCondition(updated.Status.Conditions, "CheckpointHealthy")
+	if cond == nil || cond.Status != metav1.ConditionFalse {
+		t.Fatalf("expected CheckpointHealthy=False, got %+v", cond)
+	}
+	if !strings.Contains(cond.Message, "2025-06-15T12:00:00Z") {
+		t.Errorf("expected last success time in message, got %q", cond.Message)
+	}
+	if updated.Status.FileOffset != 0 {
+		t.Errorf("expected FileOffset unchanged, got %d", updated.Status.FileOffset)
+	}
+
+	select {
+	case e := <-recorder.Events:
+		if !strings.Contains(e, "CheckpointFailed") {
+			t.Errorf("unexpected event %q", e)
+		}
+	default:
+		t.Error("expected a CheckpointFailed event")
+	}
+
+	// A second failure must not emit another event.
+	r.flushCheckpoint(context.Background(), key, &fakeIngestor{pos: ingestor.Position{FileOffset: 20}}, nil)
+	select {
+	case e := <-recorder.Events:
+		t.Errorf("unexpected repeated event %q", e)
+	default:
+	}
 }
 
 func TestFlushCheckpoint_NotFound(t *testing.T) {
@@ -1123,7 +1985,7 @@ func TestFlushCheckpoint_NotFound(t *testing.T) {
 	ing := &fakeIngestor{pos: ingestor.Position{FileOffset: 100}}
 
 	// Should not panic when source doesn't exist.
-	r.flushCheckpoint(context.Background(), key, ing)
+	r.flushCheckpoint(context.Background(), key, ing, nil)
 }
 
 // --- flushReports ---
@@ -1139,12 +2001,12 @@ func TestFlushReports(t *testing.T) {
 	r := newTestReconciler(&source)
 	engine := strategy.NewEngine(audiciav1alpha1.PolicyStrategy{})
 
-	aggregators := make(map[string]*aggregator.Aggregator)
-	subjects := make(map[string]audiciav1alpha1.Subject)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
 
 	// Add two subjects with rules.
 	for _, name := range []string{"sa-alpha", "sa-beta"} {
-		key := fmt.Sprintf("ServiceAccount/default/%s", name)
+		key := subjectKey{kind: audiciav1alpha1.SubjectKindServiceAccount, namespace: "default", name: name}
 		aggregators[key] = aggregator.New()
 		subjects[key] = audiciav1alpha1.Subject{
 			Kind:      audiciav1alpha1.SubjectKindServiceAccount,
@@ -1195,7 +2057,7 @@ func TestFlushReport_CrossNamespace(t *testing.T) {
 		makeObservedRule("pods", "get", "other-ns", time.Now()),
 	}
 
-	err := r.flushReport(context.Background(), source, subject, rules, 1, logr.Discard())
+	err := r.flushReport(context.Background(), source, subject, rules, nil, nil, 1, true, logr.Discard())
 	if err != nil {
 		t.Fatalf("flushReport: %v", err)
 	}
@@ -1250,7 +2112,7 @@ func TestPopulateReportStatus_WithResolver(t *testing.T) {
 		makeObservedRule("pods", "get", "default", time.Now()),
 	}
 
-	r.populateReportStatus(context.Background(), report, subject, rules, 1, logr.Discard())
+	r.populateReportStatus(context.Background(), report, subject, rules, nil, nil, 1, logr.Discard())
 
 	if report.Status.Compliance == nil {
 		t.Fatal("expected non-nil compliance (Resolver is set)")
@@ -1286,7 +2148,7 @@ func TestFlushCloudCheckpoint(t *testing.T) {
 		"test",
 	)
 
-	r.flushCloudCheckpoint(context.Background(), key, ing, logr.Discard())
+	r.flushCloudCheckpoint(context.Background(), key, ing, nil, logr.Discard())
 
 	var updated audiciav1alpha1.AudiciaSource
 	if err := r.Get(context.Background(), key, &updated); err != nil {
@@ -1316,7 +2178,7 @@ func TestFlushCloudCheckpoint_NotFound(t *testing.T) {
 	)
 
 	// Should not panic when source doesn't exist.
-	r.flushCloudCheckpoint(context.Background(), key, ing, logr.Discard())
+	r.flushCloudCheckpoint(context.Background(), key, ing, nil, logr.Discard())
 }
 
 // --- eventLoop ---
@@ -1364,7 +2226,7 @@ func TestEventLoop_ProcessesEventsAndFlushes(t *testing.T) {
 
 	done := make(chan struct{})
 	go func() {
-		r.eventLoop(ctx, key, source, engine, filterChain, ing, events)
+		r.eventLoop(ctx, key, source, engine, filterChain, nil, nil, ing, events, nil, nil)
 		close(done)
 	}()
 
@@ -1424,7 +2286,7 @@ func TestEventLoop_ChannelClosed(t *testing.T) {
 
 	done := make(chan struct{})
 	go func() {
-		r.eventLoop(context.Background(), key, source, engine, filterChain, ing, events)
+		r.eventLoop(context.Background(), key, source, engine, filterChain, nil, nil, ing, events, nil, nil)
 		close(done)
 	}()
 
@@ -1435,6 +2297,68 @@ func TestEventLoop_ChannelClosed(t *testing.T) {
 	}
 }
 
+func TestEventLoop_DropsDuplicateEvents(t *testing.T) {
+	tests := []struct {
+		name  string
+		dedup *audiciav1alpha1.DeduplicationConfig
+		want  int64
+	}{
+		// The repeated ResponseComplete is dropped; RequestReceived is another stage.
+		{"default", nil, 2},
+		{"disabled", &audiciav1alpha1.DeduplicationConfig{Disabled: true}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := audiciav1alpha1.AudiciaSource{
+				ObjectMeta: metav1.ObjectMeta{Name: "dedup-source", Namespace: "default"},
+				Spec: audiciav1alpha1.AudiciaSourceSpec{
+					Checkpoint:    audiciav1alpha1.CheckpointConfig{IntervalSeconds: 60},
+					Stages:        []audiciav1alpha1.AuditStage{audiciav1alpha1.AuditStageRequestReceived, audiciav1alpha1.AuditStageResponseComplete},
+					Deduplication: tt.dedup,
+				},
+			}
+			r := newTestReconciler(&source)
+			key := types.NamespacedName{Name: "dedup-source", Namespace: "default"}
+			filterChain, _ := filter.NewChain(nil)
+
+			event := func(stage auditv1.Stage) auditv1.Event {
+				return auditv1.Event{
+					AuditID:   "req-1",
+					Stage:     stage,
+					Verb:      "get",
+					User:      authnv1.UserInfo{Username: "system:serviceaccount:default:dedup-sa"},
+					ObjectRef: &auditv1.ObjectReference{Resource: "pods", Namespace: "default"},
+				}
+			}
+			events := make(chan auditv1.Event, 10)
+			events <- event(auditv1.StageResponseComplete)
+			events <- event(auditv1.StageResponseComplete)
+			events <- event(auditv1.StageRequestReceived)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				r.eventLoop(ctx, key, source, strategy.NewEngine(audiciav1alpha1.PolicyStrategy{}), filterChain, nil, nil, &fakeIngestor{}, events, nil, nil)
+				close(done)
+			}()
+			for len(events) > 0 {
+				time.Sleep(10 * time.Millisecond)
+			}
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+			<-done
+
+			var report audiciav1alpha1.AudiciaReport
+			if err := r.Get(context.Background(), types.NamespacedName{Name: "report-dedup-sa", Namespace: "default"}, &report); err != nil {
+				t.Fatalf("expected report: %v", err)
+			}
+			if len(report.Status.ObservedRules) != 1 || report.Status.ObservedRules[0].Count != tt.want {
+				t.Errorf("observed rules = %+v, want one rule with count %d", report.Status.ObservedRules, tt.want)
+			}
+		})
+	}
+}
+
 // --- severityWorsened ---
 
 func TestSeverityWorsened(t *testing.T) {
@@ -1658,10 +2582,10 @@ func TestFlushReports_CompactionEvent(t *testing.T) {
 	r.Recorder = rec
 
 	engine := strategy.NewEngine(audiciav1alpha1.PolicyStrategy{})
-	aggregators := make(map[string]*aggregator.Aggregator)
-	subjects := make(map[string]audiciav1alpha1.Subject)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
 
-	key := "ServiceAccount/default/compact-sa"
+	key := subjectKey{kind: audiciav1alpha1.SubjectKindServiceAccount, namespace: "default", name: "compact-sa"}
 	aggregators[key] = aggregator.New()
 	subjects[key] = audiciav1alpha1.Subject{
 		Kind:      audiciav1alpha1.SubjectKindServiceAccount,
@@ -1776,6 +2700,74 @@ func TestFlushPolicy(t *testing.T) {
 	}
 }
 
+func TestFlush_PropagatesOutputMetadata(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "meta-source", Namespace: "default"},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			Metadata: &audiciav1alpha1.OutputMetadata{
+				Labels:      map[string]string{"env": "prod", "cluster": "eu-1"},
+				Annotations: map[string]string{"team": "platform"},
+			},
+		},
+	}
+	existing := &audiciav1alpha1.AudiciaReport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "report-meta-sa",
+			Namespace: "default",
+			Labels:    map[string]string{"owner": "someone", "env": "staging"},
+		},
+	}
+
+	r := newTestReconciler(&source, existing)
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "meta-sa", Namespace: "default"}
+	rules := []audiciav1alpha1.ObservedRule{makeObservedRule("pods", "get", "default", time.Now())}
+
+	if err := r.flushReport(context.Background(), source, subject, rules, nil, nil, 1, true, logr.Discard()); err != nil {
+		t.Fatalf("flushReport: %v", err)
+	}
+	if err := r.flushPolicy(context.Background(), source, strategy.NewEngine(audiciav1alpha1.PolicyStrategy{}), subject, rules, logr.Discard()); err != nil {
+		t.Fatalf("flushPolicy: %v", err)
+	}
+
+	var report audiciav1alpha1.AudiciaReport
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "report-meta-sa", Namespace: "default"}, &report); err != nil {
+		t.Fatal(err)
+	}
+	wantLabels := map[string]string{"env": "prod", "cluster": "eu-1", "owner": "someone"}
+	for k, v := range wantLabels {
+		if report.Labels[k] != v {
+			t.Errorf("report label %s = %q, want %q", k, report.Labels[k], v)
+		}
+	}
+	if report.Annotations["team"] != "platform" {
+		t.Errorf("report annotations = %v, want team=platform", report.Annotations)
+	}
+
+	var policy audiciav1alpha1.AudiciaPolicy
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "policy-meta-sa", Namespace: "default"}, &policy); err != nil {
+		t.Fatal(err)
+	}
+	if policy.Labels["cluster"] != "eu-1" || policy.Annotations["team"] != "platform" {
+		t.Errorf("policy metadata = %v / %v, want cluster=eu-1 and team=platform", policy.Labels, policy.Annotations)
+	}
+}
+
+func TestMergeMetadata(t *testing.T) {
+	base := map[string]string{"env": "prod", "team": "platform"}
+	over := map[string]string{"team": "payments", "ticket": "SEC-42"}
+	got := mergeMetadata(base, over)
+	want := map[string]string{"env": "prod", "team": "payments", "ticket": "SEC-42"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeMetadata() = %v, want %v", got, want)
+	}
+	if base["team"] != "platform" {
+		t.Errorf("base was modified: %v", base)
+	}
+	if got := mergeMetadata(base, nil); !reflect.DeepEqual(got, base) {
+		t.Errorf("mergeMetadata(base, nil) = %v, want %v", got, base)
+	}
+}
+
 func TestFlushPolicy_OutdatedOnUpdate(t *testing.T) {
 	source := audiciav1alpha1.AudiciaSource{
 		ObjectMeta: metav1.ObjectMeta{
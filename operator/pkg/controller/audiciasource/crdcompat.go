@@ -0,0 +1,167 @@
+package audiciasource
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+	crdschema "github.com/felixnotka/audicia/operator/pkg/schema"
+)
+
+// crdCompatibleCondition reports whether the installed Audicia CRDs serve
+// every field of the schemas compiled into the operator.
+const crdCompatibleCondition = "CRDsCompatible"
+
+// crdCompatibility remembers the comparison of the installed Audicia CRDs
+// with the schemas compiled into the operator. After a partial upgrade, such
+// as a Helm upgrade that left the CRDs alone, the API server silently prunes
+// the fields it does not know from every status the operator writes, so
+// pipelines are not started until the CRDs are compatible. The CRDs are
+// compared once at startup and, while incompatible or unreadable, again
+// every reportCRDRecheckInterval, so upgrading them or granting access to
+// them takes effect without a restart.
+type crdCompatibility struct {
+	mu        sync.Mutex
+	checked   bool
+	checkedAt time.Time
+	// message describes the incompatible CRDs; empty while compatible.
+	message string
+	// unknown describes the CRDs the operator may not read, whose
+	// compatibility is unknown.
+	unknown string
+}
+
+// checkCRDsAtStartup checks the CRDs when the manager starts, so the log and
+// the audicia_crd_schema_compatible metric tell about an incompatible
+// install before any AudiciaSource is reconciled.
+func (r *Reconciler) checkCRDsAtStartup(ctx context.Context) error {
+	message, unknown := r.crdIncompatibility(ctx, time.Now())
+	if message != "" {
+		ctrl.Log.WithName("setup").Info("installed CRDs are incompatible with the operator, not starting pipelines", "message", message)
+	}
+	if unknown != "" {
+		ctrl.Log.WithName("setup").Info("cannot compare the installed CRDs with the operator", "message", unknown)
+	}
+	return nil
+}
+
+// crdIncompatibility returns why the installed CRDs are incompatible with
+// the operator, or "" when they are compatible, and which CRDs the operator
+// may not read, or "" when it may read all of them.
+func (r *Reconciler) crdIncompatibility(ctx context.Context, now time.Time) (message, unknown string) {
+	c := &r.crds
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.checked && (c.message == "" && c.unknown == "" || now.Sub(c.checkedAt) < reportCRDRecheckInterval) {
+		return c.message, c.unknown
+	}
+	c.message, c.unknown = r.compareCRDs(ctx)
+	c.checked = true
+	c.checkedAt = now
+	return c.message, c.unknown
+}
+
+// compareCRDs compares the installed CRD of every Audicia resource version
+// with the operator's schema and records the result in the
+// audicia_crd_schema_compatible metric. CRDs the operator may not read are
+// reported as unknown, with a metric of -1. CRDs that are not installed are
+// left to the checks of their users, such as the ReportCRDReady condition.
+func (r *Reconciler) compareCRDs(ctx context.Context) (message, unknown string) {
+	logger := ctrl.Log.WithName("pipeline")
+	all, err := crdschema.All()
+	if err != nil {
+		logger.Error(err, "cannot load the CRD schemas of the operator")
+		return "", ""
+	}
+	var messages, unreadable []string
+	for _, want := range all {
+		missing, err := r.missingSchemaFields(ctx, want)
+		if apierrors.IsForbidden(err) {
+			metrics.CRDSchemaCompatible.WithLabelValues(want.Kind, want.Version).Set(-1)
+			if name := want.Plural + "." + want.Group; !slices.Contains(unreadable, name) {
+				unreadable = append(unreadable, name)
+			}
+			continue
+		}
+		if err != nil {
+			logger.V(1).Info("cannot compare the CRD schema", "kind", want.Kind, "version", want.Version, "error", err)
+			continue
+		}
+		compatible := 1.0
+		if len(missing) > 0 {
+			compatible = 0
+			messages = append(messages, outdatedMessage(want.Kind, missing)+".")
+		}
+		metrics.CRDSchemaCompatible.WithLabelValues(want.Kind, want.Version).Set(compatible)
+	}
+	if len(unreadable) > 0 {
+		unknown = "The operator may not read the CustomResourceDefinitions " + strings.Join(unreadable, ", ") +
+			", so their compatibility is unknown. Grant it get on them."
+	}
+	if len(messages) > 0 {
+		message = strings.Join(messages, " ") + " Upgrade the CRDs to the operator's version."
+	}
+	return message, unknown
+}
+
+// recordCRDCompatibility sets the CRDsCompatible condition of the source
+// from message and unknown, the results of crdIncompatibility. While the
+// CRDs are compatible, the condition is only written to clear an earlier
+// failure or unknown state. Unreadable CRDs do not hold back the pipeline.
+func (r *Reconciler) recordCRDCompatibility(ctx context.Context, source *audiciav1alpha1.AudiciaSource, message, unknown string) {
+	current := meta.FindStatusCondition(source.Status.Conditions, crdCompatibleCondition)
+	wasIncompatible := current != nil && current.Status == metav1.ConditionFalse
+	if message == "" && unknown != "" {
+		if current == nil || current.Status != metav1.ConditionUnknown || current.Message != unknown {
+			_ = r.setCondition(ctx, source, metav1.Condition{
+				Type:               crdCompatibleCondition,
+				Status:             metav1.ConditionUnknown,
+				Reason:             "CRDsUnreadable",
+				Message:            unknown,
+				ObservedGeneration: source.Generation,
+			})
+		}
+		return
+	}
+	if message == "" {
+		if current != nil && current.Status != metav1.ConditionTrue {
+			_ = r.setCondition(ctx, source, metav1.Condition{
+				Type:               crdCompatibleCondition,
+				Status:             metav1.ConditionTrue,
+				Reason:             "CRDsCurrent",
+				Message:            "The installed Audicia CRDs are compatible with the operator.",
+				ObservedGeneration: source.Generation,
+			})
+		}
+		return
+	}
+
+	message += " The ingestion pipeline is not started until the CRDs are upgraded."
+	_ = r.setCondition(ctx, source, metav1.Condition{
+		Type:               crdCompatibleCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "SchemaIncompatible",
+		Message:            message,
+		ObservedGeneration: source.Generation,
+	})
+	_ = r.setCondition(ctx, source, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "CRDsIncompatible",
+		Message:            message,
+		ObservedGeneration: source.Generation,
+	})
+	if !wasIncompatible {
+		r.Recorder.Eventf(source, nil, corev1.EventTypeWarning, "CRDsIncompatible", "Start", "%s", message)
+	}
+}
@@ -0,0 +1,131 @@
+package audiciasource
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+)
+
+func TestReconcile_CRDsIncompatible(t *testing.T) {
+	dropCompliance := func(openAPI map[string]any) {
+		unstructured.RemoveNestedField(openAPI, "properties", "status", "properties", "compliance")
+	}
+	source := &audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "audit", Namespace: "audicia-system", Generation: 1},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			SourceType: audiciav1alpha1.SourceTypeK8sAuditLog,
+			Location:   &audiciav1alpha1.FileLocation{Path: "/tmp/test.log"},
+		},
+	}
+	outdated := installedCRD(t, "AudiciaReport", dropCompliance)
+	r := newTestReconciler(source, outdated, installedCRD(t, "AudiciaPolicy", nil))
+	key := types.NamespacedName{Name: "audit", Namespace: "audicia-system"}
+	ctx := context.Background()
+	reportCompatible := metrics.CRDSchemaCompatible.WithLabelValues("AudiciaReport", "v1alpha1")
+
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RequeueAfter != reportCRDRecheckInterval {
+		t.Errorf("RequeueAfter = %v, want %v", result.RequeueAfter, reportCRDRecheckInterval)
+	}
+	if len(r.pipelines) != 0 {
+		t.Fatal("pipeline started with incompatible CRDs")
+	}
+	var updated audiciav1alpha1.AudiciaSource
+	if err := r.Get(ctx, key, &updated); err != nil {
+		t.Fatal(err)
+	}
+	cond := meta.FindStatusCondition(updated.Status.Conditions, crdCompatibleCondition)
+	if cond == nil || cond.Status != metav1.ConditionFalse || !strings.Contains(cond.Message, "AudiciaReport CRD is older than the operator and lacks status.compliance") {
+		t.Errorf("%s condition = %+v", crdCompatibleCondition, cond)
+	}
+	if ready := meta.FindStatusCondition(updated.Status.Conditions, "Ready"); ready == nil || ready.Reason != "CRDsIncompatible" {
+		t.Errorf("Ready condition = %+v, want CRDsIncompatible", ready)
+	}
+	if got := testutil.ToFloat64(reportCompatible); got != 0 {
+		t.Errorf("audicia_crd_schema_compatible = %v, want 0", got)
+	}
+
+	// Upgrading the CRDs takes effect at the next check.
+	if err := r.Delete(ctx, outdated); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Create(ctx, installedCRD(t, "AudiciaReport", nil)); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if message, _ := r.crdIncompatibility(ctx, now); message == "" {
+		t.Error("CRDs checked again before the recheck interval")
+	}
+	if message, _ := r.crdIncompatibility(ctx, now.Add(reportCRDRecheckInterval)); message != "" {
+		t.Fatalf("crdIncompatibility() = %q after the upgrade", message)
+	}
+	if got := testutil.ToFloat64(reportCompatible); got != 1 {
+		t.Errorf("audicia_crd_schema_compatible = %v, want 1", got)
+	}
+	r.recordCRDCompatibility(ctx, &updated, "", "")
+	if !meta.IsStatusConditionTrue(updated.Status.Conditions, crdCompatibleCondition) {
+		t.Errorf("%s condition not cleared: %+v", crdCompatibleCondition, updated.Status.Conditions)
+	}
+}
+
+func TestReconcile_CRDsUnreadable(t *testing.T) {
+	source := &audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "audit", Namespace: "audicia-system", Generation: 1},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			SourceType: audiciav1alpha1.SourceTypeK8sAuditLog,
+			Location:   &audiciav1alpha1.FileLocation{Path: "/tmp/test.log"},
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(newTestScheme()).
+		WithObjects(source).
+		WithStatusSubresource(&audiciav1alpha1.AudiciaSource{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if obj.GetObjectKind().GroupVersionKind().Kind == "CustomResourceDefinition" && key.Name == "audiciasources.audicia.io" {
+					return apierrors.NewForbidden(schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}, key.Name, nil)
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).
+		Build()
+	r := &Reconciler{Client: c, Scheme: newTestScheme(), Recorder: events.NewFakeRecorder(10), pipelines: make(map[types.NamespacedName]*pipelineState)}
+	ctx := context.Background()
+
+	message, unknown := r.crdIncompatibility(ctx, time.Now())
+	if message != "" {
+		t.Errorf("crdIncompatibility() message = %q, want none", message)
+	}
+	if !strings.Contains(unknown, "audiciasources.audicia.io") {
+		t.Errorf("crdIncompatibility() unknown = %q, want audiciasources.audicia.io", unknown)
+	}
+	if got := testutil.ToFloat64(metrics.CRDSchemaCompatible.WithLabelValues("AudiciaSource", "v1alpha1")); got != -1 {
+		t.Errorf("audicia_crd_schema_compatible = %v, want -1", got)
+	}
+
+	r.recordCRDCompatibility(ctx, source, message, unknown)
+	cond := meta.FindStatusCondition(source.Status.Conditions, crdCompatibleCondition)
+	if cond == nil || cond.Status != metav1.ConditionUnknown || cond.Reason != "CRDsUnreadable" {
+		t.Errorf("%s condition = %+v, want Unknown", crdCompatibleCondition, cond)
+	}
+}
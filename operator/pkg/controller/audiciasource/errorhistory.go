@@ -0,0 +1,132 @@
+package audiciasource
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+const (
+	// errorHistorySize is the number of errors kept in status.recentErrors.
+	errorHistorySize = 10
+
+	// maxErrorMessageLength truncates error messages in status.recentErrors.
+	maxErrorMessageLength = 512
+)
+
+// errorHistory is a ring of the last errors a pipeline recovered from,
+// persisted in status.recentErrors. A nil *errorHistory records nothing.
+// It is safe for concurrent use, as ingestors report errors from their own
+// goroutines.
+type errorHistory struct {
+	mu      sync.Mutex
+	entries []audiciav1alpha1.PipelineError
+	dirty   bool
+}
+
+// newErrorHistory continues the history persisted in the source's status,
+// so a restart does not lose it.
+func newErrorHistory(source audiciav1alpha1.AudiciaSource) *errorHistory {
+	return &errorHistory{entries: slices.Clone(source.Status.RecentErrors)}
+}
+
+// record adds err to the history. An error repeating the most recent entry
+// only advances its LastSeen and Count, so a persistent failure does not
+// push everything else out of the ring.
+func (h *errorHistory) record(category audiciav1alpha1.PipelineErrorCategory, err error, now time.Time) {
+	if h == nil || err == nil {
+		return
+	}
+	message := err.Error()
+	if len(message) > maxErrorMessageLength {
+		message = strings.ToValidUTF8(message[:maxErrorMessageLength-3], "") + "..."
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.dirty = true
+	if n := len(h.entries); n > 0 {
+		last := &h.entries[n-1]
+		if last.Category == category && last.Message == message {
+			last.LastSeen = metav1.NewTime(now)
+			last.Count++
+			return
+		}
+	}
+	h.entries = append(h.entries, audiciav1alpha1.PipelineError{
+		Category:  category,
+		Message:   message,
+		FirstSeen: metav1.NewTime(now),
+		LastSeen:  metav1.NewTime(now),
+		Count:     1,
+	})
+	if len(h.entries) > errorHistorySize {
+		h.entries = slices.Delete(h.entries, 0, len(h.entries)-errorHistorySize)
+	}
+}
+
+// recordFlush adds the errors of the subjects that failed to flush.
+func (h *errorHistory) recordFlush(result flushResult, now time.Time) {
+	for _, err := range result.errs {
+		h.record(audiciav1alpha1.PipelineErrorFlush, err, now)
+	}
+}
+
+// reporter returns the callback ingestors report their errors to.
+func (h *errorHistory) reporter() func(error) {
+	return func(err error) {
+		h.record(audiciav1alpha1.PipelineErrorIngestion, err, time.Now())
+	}
+}
+
+// pending returns a copy of the history if it changed since it was last
+// persisted.
+func (h *errorHistory) pending() ([]audiciav1alpha1.PipelineError, bool) {
+	if h == nil {
+		return nil, false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.entries), h.dirty
+}
+
+// persisted marks the history as persisted unless it changed since
+// pending returned entries.
+func (h *errorHistory) persisted(entries []audiciav1alpha1.PipelineError) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.dirty = !slices.Equal(h.entries, entries)
+}
+
+// recordErrors persists status.recentErrors when errors were recorded since
+// the last call.
+func (r *Reconciler) recordErrors(ctx context.Context, key types.NamespacedName, h *errorHistory) {
+	entries, dirty := h.pending()
+	if !dirty {
+		return
+	}
+	logger := ctrl.Log.WithName("pipeline").WithValues("source", key)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var source audiciav1alpha1.AudiciaSource
+		if err := r.Get(ctx, key, &source); err != nil {
+			return err
+		}
+		source.Status.RecentErrors = entries
+		return r.Status().Update(ctx, &source)
+	})
+	switch {
+	case err == nil:
+		h.persisted(entries)
+	case !errors.IsNotFound(err):
+		logger.Error(err, "failed to record recent errors")
+	}
+}
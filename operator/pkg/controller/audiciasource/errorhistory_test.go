@@ -0,0 +1,81 @@
+package audiciasource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func TestErrorHistory(t *testing.T) {
+	h := newErrorHistory(audiciav1alpha1.AudiciaSource{})
+	start := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+
+	throttled := errors.New("receiving messages: ThrottlingException")
+	for i := range 3 {
+		h.record(audiciav1alpha1.PipelineErrorIngestion, throttled, start.Add(time.Duration(i)*time.Minute))
+	}
+	h.record(audiciav1alpha1.PipelineErrorCheckpoint, nil, start)
+	entries, dirty := h.pending()
+	if !dirty || len(entries) != 1 {
+		t.Fatalf("entries = %+v, want one collapsed entry", entries)
+	}
+	if e := entries[0]; e.Count != 3 || !e.FirstSeen.Time.Equal(start) || !e.LastSeen.Time.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("entry = %+v, want 3 occurrences from 03:00 to 03:02", e)
+	}
+
+	for i := range errorHistorySize + 2 {
+		h.record(audiciav1alpha1.PipelineErrorFlush, fmt.Errorf("flush %d", i), start.Add(time.Hour))
+	}
+	entries, _ = h.pending()
+	if len(entries) != errorHistorySize || entries[0].Message != "flush 2" || entries[errorHistorySize-1].Message != "flush 11" {
+		t.Errorf("ring holds %d entries from %q, want the last %d", len(entries), entries[0].Message, errorHistorySize)
+	}
+
+	h.record(audiciav1alpha1.PipelineErrorPolicySink, errors.New(strings.Repeat("x", 1000)), start)
+	entries, _ = h.pending()
+	if n := len(entries[len(entries)-1].Message); n != maxErrorMessageLength {
+		t.Errorf("message length = %d, want %d", n, maxErrorMessageLength)
+	}
+
+	var disabled *errorHistory
+	disabled.record(audiciav1alpha1.PipelineErrorFlush, throttled, start)
+	if _, dirty := disabled.pending(); dirty {
+		t.Error("nil history recorded an error")
+	}
+}
+
+func TestRecordErrors(t *testing.T) {
+	source := &audiciav1alpha1.AudiciaSource{ObjectMeta: metav1.ObjectMeta{Name: "src", Namespace: "default"}}
+	r := newTestReconciler(source)
+	key := types.NamespacedName{Name: "src", Namespace: "default"}
+	h := newErrorHistory(*source)
+
+	h.recordFlush(flushResult{errs: []error{errors.New("User/alice: forbidden")}}, time.Now())
+	r.recordErrors(context.Background(), key, h)
+
+	var got audiciav1alpha1.AudiciaSource
+	if err := r.Get(context.Background(), key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Status.RecentErrors) != 1 || got.Status.RecentErrors[0].Category != audiciav1alpha1.PipelineErrorFlush {
+		t.Fatalf("recentErrors = %+v, want the flush error", got.Status.RecentErrors)
+	}
+	if _, dirty := h.pending(); dirty {
+		t.Error("history still dirty after it was persisted")
+	}
+
+	// A restarted pipeline continues the persisted history.
+	restarted := newErrorHistory(got)
+	restarted.record(audiciav1alpha1.PipelineErrorFlush, errors.New("User/alice: forbidden"), time.Now())
+	if entries, _ := restarted.pending(); len(entries) != 1 || entries[0].Count != 2 {
+		t.Errorf("entries = %+v, want the persisted entry continued", entries)
+	}
+}
@@ -0,0 +1,205 @@
+package audiciasource
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+)
+
+// subjectLRU enforces spec.limits.maxSubjects on the aggregators it is
+// touched for, evicting the least recently active subject. Evicted subjects
+// are held until a flush writes their activity, at most max of them, so
+// memory stays bounded by twice the cap. A nil *subjectLRU evicts
+// nothing. It is owned by the goroutine that writes the aggregators and is
+// not safe for concurrent use.
+type subjectLRU struct {
+	max    int
+	source string
+
+	// order holds the subject keys, most recently active first.
+	order   *list.List
+	entries map[subjectKey]*list.Element
+	evicted int64
+
+	// draining holds the evicted subjects whose report is not yet written.
+	draining map[subjectKey]evictedSubject
+}
+
+// evictedSubject is the aggregator of an evicted subject awaiting its flush.
+type evictedSubject struct {
+	agg     *aggregator.Aggregator
+	subject audiciav1alpha1.Subject
+}
+
+// newSubjectLRU returns nil when max is zero.
+func newSubjectLRU(max int, source types.NamespacedName) *subjectLRU {
+	if max <= 0 {
+		return nil
+	}
+	return &subjectLRU{
+		max:      max,
+		source:   source.String(),
+		order:    list.New(),
+		entries:  make(map[subjectKey]*list.Element),
+		draining: make(map[subjectKey]evictedSubject),
+	}
+}
+
+// touch marks sk as the most recently active subject, before its rule is
+// aggregated, and evicts the least recently active one from aggregators if
+// the cap is exceeded. An evicted subject that is active again before its
+// flush gets its aggregator back.
+func (l *subjectLRU) touch(sk subjectKey, aggregators map[subjectKey]*aggregator.Aggregator, subjects map[subjectKey]audiciav1alpha1.Subject) {
+	if l == nil {
+		return
+	}
+	if e, ok := l.entries[sk]; ok {
+		l.order.MoveToFront(e)
+		return
+	}
+	if d, ok := l.draining[sk]; ok {
+		delete(l.draining, sk)
+		if _, ok := aggregators[sk]; !ok {
+			aggregators[sk], subjects[sk] = d.agg, d.subject
+		}
+	}
+	l.entries[sk] = l.order.PushFront(sk)
+	for l.order.Len() > l.max {
+		oldest := l.order.Remove(l.order.Back()).(subjectKey)
+		delete(l.entries, oldest)
+		if agg, ok := aggregators[oldest]; ok && len(l.draining) < l.max {
+			l.draining[oldest] = evictedSubject{agg: agg, subject: subjects[oldest]}
+		}
+		delete(aggregators, oldest)
+		delete(subjects, oldest)
+		l.evicted++
+		metrics.SubjectsEvictedTotal.WithLabelValues(l.source).Inc()
+	}
+}
+
+// addDraining adds the evicted subjects awaiting their flush to aggregators,
+// so the next flush writes them.
+func (l *subjectLRU) addDraining(aggregators map[subjectKey]*aggregator.Aggregator, subjects map[subjectKey]audiciav1alpha1.Subject) {
+	if l == nil {
+		return
+	}
+	for sk, d := range l.draining {
+		aggregators[sk], subjects[sk] = d.agg, d.subject
+	}
+}
+
+// drained forgets the evicted subjects whose report was written, as
+// reported by written, removing them from aggregators again. Those whose
+// write failed or was deferred stay until a later flush writes them; those
+// removed from aggregators by other means (e.g. expired) are forgotten.
+func (l *subjectLRU) drained(aggregators map[subjectKey]*aggregator.Aggregator, subjects map[subjectKey]audiciav1alpha1.Subject, written func(subjectKey, *aggregator.Aggregator) bool) {
+	if l == nil {
+		return
+	}
+	for sk, d := range l.draining {
+		if aggregators[sk] != d.agg {
+			delete(l.draining, sk)
+			continue
+		}
+		if written(sk, d.agg) {
+			delete(aggregators, sk)
+			delete(subjects, sk)
+			delete(l.draining, sk)
+		}
+	}
+}
+
+// retain forgets the subjects removed from aggregators by other means
+// (e.g. expired), so they don't hold a slot.
+func (l *subjectLRU) retain(aggregators map[subjectKey]*aggregator.Aggregator) {
+	if l == nil {
+		return
+	}
+	for sk, e := range l.entries {
+		if _, ok := aggregators[sk]; !ok {
+			l.order.Remove(e)
+			delete(l.entries, sk)
+		}
+	}
+}
+
+// evictions returns the number of subjects evicted so far.
+func (l *subjectLRU) evictions() int64 {
+	if l == nil {
+		return 0
+	}
+	return l.evicted
+}
+
+// evictionTracker reports evictions in the SubjectsEvicted condition. The
+// condition turns True when a checkpoint interval saw evictions and False
+// after one that saw none.
+type evictionTracker struct {
+	max      int32
+	recorded int64
+	evicting bool
+}
+
+// newEvictionTracker returns nil when spec.limits.maxSubjects is unset.
+func newEvictionTracker(source audiciav1alpha1.AudiciaSource) *evictionTracker {
+	if source.Spec.Limits.MaxSubjects <= 0 {
+		return nil
+	}
+	return &evictionTracker{max: source.Spec.Limits.MaxSubjects}
+}
+
+// recordEvictions updates the SubjectsEvicted condition when evictions, the
+// total since the pipeline started, changes whether subjects are being
+// evicted.
+func (r *Reconciler) recordEvictions(ctx context.Context, key types.NamespacedName, t *evictionTracker, evictions int64) {
+	if t == nil {
+		return
+	}
+	evicting := evictions > t.recorded
+	if !evicting && !t.evicting {
+		return
+	}
+	condition := metav1.Condition{
+		Type:    "SubjectsEvicted",
+		Status:  metav1.ConditionFalse,
+		Reason:  "WithinLimit",
+		Message: fmt.Sprintf("No subjects evicted in the last checkpoint interval (%d since the pipeline started).", evictions),
+	}
+	if evicting {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "MaxSubjectsExceeded"
+		condition.Message = fmt.Sprintf("%d subject(s) evicted in the last checkpoint interval to stay within spec.limits.maxSubjects=%d (%d since the pipeline started).",
+			evictions-t.recorded, t.max, evictions)
+	}
+
+	logger := ctrl.Log.WithName("pipeline").WithValues("source", key)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var source audiciav1alpha1.AudiciaSource
+		if err := r.Get(ctx, key, &source); err != nil {
+			return err
+		}
+		condition.ObservedGeneration = source.Generation
+		meta.SetStatusCondition(&source.Status.Conditions, condition)
+		return r.Status().Update(ctx, &source)
+	})
+	switch {
+	case err == nil:
+		t.recorded, t.evicting = evictions, evicting
+		if evicting {
+			logger.Info("subjects evicted by maxSubjects", "evicted", evictions, "max", t.max)
+		}
+	case !errors.IsNotFound(err):
+		logger.Error(err, "failed to record subject evictions")
+	}
+}
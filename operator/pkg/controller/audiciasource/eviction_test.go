@@ -0,0 +1,179 @@
+package audiciasource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/filter"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+)
+
+func TestSubjectLRU(t *testing.T) {
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+	lru := newSubjectLRU(2, types.NamespacedName{Namespace: "default", Name: "src"})
+	observe := func(user string) subjectKey {
+		subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: user}
+		sk := keyFor(subject)
+		lru.touch(sk, aggregators, subjects)
+		aggregate(aggregators, subjects, subject, normalizer.CanonicalRule{Resource: "pods", Verb: "get"}, time.Now(), 1)
+		return sk
+	}
+
+	alice, bob := observe("alice"), observe("bob")
+	observe("alice")
+	carol := observe("carol")
+	if _, ok := aggregators[bob]; ok {
+		t.Error("bob, the least recently active subject, was not evicted")
+	}
+	if aggregators[alice] == nil || aggregators[carol] == nil || len(subjects) != 2 {
+		t.Errorf("aggregators = %v, want alice and carol", aggregators)
+	}
+	if lru.evictions() != 1 {
+		t.Errorf("evictions = %d, want 1", lru.evictions())
+	}
+
+	// The evicted subject is flushed with the next checkpoint.
+	lru.addDraining(aggregators, subjects)
+	if aggregators[bob] == nil || subjects[bob].Name != "bob" {
+		t.Fatal("evicted bob not added for the next flush")
+	}
+	// Until a flush writes bob's report, his activity is held.
+	versions := newReportVersions()
+	lru.drained(aggregators, subjects, versions.current)
+	if aggregators[bob] == nil || len(lru.draining) != 1 {
+		t.Fatal("bob dropped before his report was written")
+	}
+	versions.record(aggregators, flushResult{succeeded: []subjectKey{bob}})
+	lru.drained(aggregators, subjects, versions.current)
+	if _, ok := aggregators[bob]; ok || len(lru.draining) != 0 {
+		t.Errorf("bob still held after the flush: %v", aggregators)
+	}
+
+	// An expired subject frees its slot.
+	delete(aggregators, alice)
+	delete(subjects, alice)
+	lru.retain(aggregators)
+	observe("dave")
+	if aggregators[carol] == nil || lru.evictions() != 1 {
+		t.Errorf("carol evicted after alice expired (evictions = %d)", lru.evictions())
+	}
+
+	var unlimited *subjectLRU
+	unlimited.touch(alice, aggregators, subjects)
+	if unlimited.evictions() != 0 {
+		t.Error("nil LRU must not evict")
+	}
+}
+
+func TestSubjectLRU_EvictedSubjectResumesBeforeFlush(t *testing.T) {
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+	lru := newSubjectLRU(1, types.NamespacedName{Namespace: "default", Name: "src"})
+	observe := func(user string) subjectKey {
+		subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: user}
+		sk := keyFor(subject)
+		lru.touch(sk, aggregators, subjects)
+		aggregate(aggregators, subjects, subject, normalizer.CanonicalRule{Resource: "pods", Verb: "get"}, time.Now(), 1)
+		return sk
+	}
+
+	alice := observe("alice")
+	observe("bob")
+	observe("alice")
+	if got := aggregators[alice].Rules()[0].Count; got != 2 {
+		t.Errorf("alice count = %d, want her activity before the eviction kept", got)
+	}
+	if lru.evictions() != 2 {
+		t.Errorf("evictions = %d, want 2", lru.evictions())
+	}
+}
+
+func TestShardShare(t *testing.T) {
+	for _, tt := range []struct{ max, n int }{{10, 4}, {4, 4}, {7, 3}, {100, 8}} {
+		total := 0
+		for i := range tt.n {
+			share := shardShare(tt.max, tt.n, i)
+			if share < tt.max/tt.n || share > tt.max/tt.n+1 {
+				t.Errorf("shardShare(%d, %d, %d) = %d, want an even split", tt.max, tt.n, i, share)
+			}
+			total += share
+		}
+		if total != tt.max {
+			t.Errorf("shares of %d over %d shards add up to %d", tt.max, tt.n, total)
+		}
+	}
+}
+
+func TestEventWorkers_EvictPerShard(t *testing.T) {
+	r := newTestReconciler()
+	source := audiciav1alpha1.AudiciaSource{Spec: audiciav1alpha1.AudiciaSourceSpec{SourceType: audiciav1alpha1.SourceTypeK8sAuditLog}}
+	source.Spec.Limits.MaxSubjects = 4
+	chain, _ := filter.NewChain(nil)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+	w := r.newEventWorkers(2, source, chain, nil, nil, aggregators, subjects, nil)
+	defer w.stop()
+
+	for _, event := range workerEvents(70) {
+		w.dispatch(event)
+	}
+	w.sync(aggregators, subjects)
+	if len(aggregators) > 8 || len(subjects) != len(aggregators) {
+		t.Errorf("%d aggregators and %d subjects, want at most 4 and 4 evicted awaiting their flush", len(aggregators), len(subjects))
+	}
+	w.drained(aggregators, subjects, func(subjectKey, *aggregator.Aggregator) bool { return true })
+	if len(aggregators) > 4 || len(subjects) != len(aggregators) {
+		t.Errorf("%d aggregators and %d subjects after the flush, want at most 4", len(aggregators), len(subjects))
+	}
+	for _, shard := range w.shards {
+		if len(shard.aggregators) > 2 {
+			t.Errorf("shard holds %d subjects, want at most its share of 2", len(shard.aggregators))
+		}
+	}
+	if int(w.evictions()) < 7-4 {
+		t.Errorf("evictions = %d, want at least 3", w.evictions())
+	}
+}
+
+func TestRecordEvictions(t *testing.T) {
+	source := &audiciav1alpha1.AudiciaSource{ObjectMeta: metav1.ObjectMeta{Name: "src", Namespace: "default"}}
+	source.Spec.Limits.MaxSubjects = 10
+	r := newTestReconciler(source)
+	key := types.NamespacedName{Name: "src", Namespace: "default"}
+	tracker := newEvictionTracker(*source)
+
+	condition := func() *metav1.Condition {
+		var got audiciav1alpha1.AudiciaSource
+		if err := r.Get(context.Background(), key, &got); err != nil {
+			t.Fatal(err)
+		}
+		return meta.FindStatusCondition(got.Status.Conditions, "SubjectsEvicted")
+	}
+
+	r.recordEvictions(context.Background(), key, tracker, 0)
+	if c := condition(); c != nil {
+		t.Fatalf("condition set without evictions: %+v", c)
+	}
+
+	r.recordEvictions(context.Background(), key, tracker, 5)
+	if c := condition(); c == nil || c.Status != metav1.ConditionTrue || c.Reason != "MaxSubjectsExceeded" {
+		t.Fatalf("condition = %+v, want True", c)
+	}
+
+	r.recordEvictions(context.Background(), key, tracker, 5)
+	if c := condition(); c == nil || c.Status != metav1.ConditionFalse {
+		t.Errorf("condition = %+v, want False after an interval without evictions", c)
+	}
+
+	if newEvictionTracker(audiciav1alpha1.AudiciaSource{}) != nil {
+		t.Error("tracker created without spec.limits.maxSubjects")
+	}
+}
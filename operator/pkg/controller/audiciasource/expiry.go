@@ -0,0 +1,165 @@
+package audiciasource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+)
+
+const (
+	// reportExpiryInterval is how often the pipeline looks for expired
+	// reports when spec.limits.reportTTLDays is set.
+	reportExpiryInterval = time.Hour
+
+	// staleCondition is set on reports kept past their TTL with the
+	// MarkStale action.
+	staleCondition = "Stale"
+)
+
+// latestSeen returns the newest lastSeen among the rule lists.
+func latestSeen(lists ...[]audiciav1alpha1.ObservedRule) time.Time {
+	var latest time.Time
+	for _, rules := range lists {
+		for _, r := range rules {
+			if r.LastSeen.After(latest) {
+				latest = r.LastSeen.Time
+			}
+		}
+	}
+	return latest
+}
+
+// reportActivity returns when the subject of a report was last active. The
+// report's lastProcessedTime moves on every flush, so the newest lastSeen of
+// its rules is used, preferring the in-memory aggregator (agg may be nil),
+// which also holds rules the limits dropped from the report. Placeholder
+// reports return the zero time: they never expire.
+func reportActivity(report *audiciav1alpha1.AudiciaReport, agg *aggregator.Aggregator) time.Time {
+	if meta.IsStatusConditionTrue(report.Status.Conditions, "NoActivityObserved") {
+		return time.Time{}
+	}
+	if agg != nil {
+		if latest := latestSeen(agg.Rules(), agg.DeniedRules()); !latest.IsZero() {
+			return latest
+		}
+	}
+	if latest := latestSeen(report.Status.ObservedRules, report.Status.DeniedRules); !latest.IsZero() {
+		return latest
+	}
+	if report.Status.LastProcessedTime != nil {
+		return report.Status.LastProcessedTime.Time
+	}
+	return time.Time{}
+}
+
+// expireReports applies spec.limits.reportTTLDays to the reports this source
+// writes. Expired subjects are also dropped from the pipeline, so the next
+// flush does not write their reports again; new activity starts them afresh.
+// It returns the number of reports expired.
+func (r *Reconciler) expireReports(
+	ctx context.Context,
+	key types.NamespacedName,
+	source audiciav1alpha1.AudiciaSource,
+	limits audiciav1alpha1.LimitsConfig,
+	aggregators map[subjectKey]*aggregator.Aggregator,
+	subjects map[subjectKey]audiciav1alpha1.Subject,
+	now time.Time,
+	logger logr.Logger,
+) (int, error) {
+	if limits.ReportTTLDays <= 0 {
+		return 0, nil
+	}
+	ttl := time.Duration(limits.ReportTTLDays) * 24 * time.Hour
+	action := limits.ExpiredReportAction
+	if action == "" {
+		action = audiciav1alpha1.ExpiredReportDelete
+	}
+
+	var reports audiciav1alpha1.AudiciaReportList
+	if err := r.List(ctx, &reports); err != nil {
+		return 0, fmt.Errorf("listing reports: %w", err)
+	}
+
+	expired := 0
+	for i := range reports.Items {
+		report := &reports.Items[i]
+		if !writtenBy(report, key) {
+			continue
+		}
+		sk := keyFor(report.Spec.Subject)
+		last := reportActivity(report, aggregators[sk])
+		if last.IsZero() || now.Sub(last) < ttl {
+			continue
+		}
+		if action == audiciav1alpha1.ExpiredReportMarkStale &&
+			meta.IsStatusConditionTrue(report.Status.Conditions, staleCondition) {
+			continue
+		}
+
+		delete(aggregators, sk)
+		delete(subjects, sk)
+
+		var err error
+		if action == audiciav1alpha1.ExpiredReportMarkStale {
+			err = r.markReportStale(ctx, report, last)
+		} else {
+			err = r.deleteExpiredReport(ctx, report)
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+		metrics.ReportsExpiredTotal.WithLabelValues(string(action)).Inc()
+		logger.Info("report expired", "report", report.Namespace+"/"+report.Name,
+			"subject", report.Spec.Subject.Name, "lastActivity", last, "action", action)
+	}
+
+	if expired > 0 {
+		r.Recorder.Eventf(&source, nil, corev1.EventTypeNormal, "ReportsExpired", "Expire",
+			"%d reports without activity for %d days expired (%s)", expired, limits.ReportTTLDays, action)
+	}
+	return expired, nil
+}
+
+// deleteExpiredReport removes an expired report and the policy derived from it.
+func (r *Reconciler) deleteExpiredReport(ctx context.Context, report *audiciav1alpha1.AudiciaReport) error {
+	policy := &audiciav1alpha1.AudiciaPolicy{ObjectMeta: metav1.ObjectMeta{
+		Name:      policyNameFor(report.Spec.Subject),
+		Namespace: report.Namespace,
+	}}
+	if err := r.Delete(ctx, policy); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("delete policy %s: %w", policy.Name, err)
+	}
+	if err := r.Delete(ctx, report); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("delete report %s: %w", report.Name, err)
+	}
+	metrics.ReportRulesCount.DeleteLabelValues(report.Name)
+	metrics.ForgetCompliance(report.Namespace, report.Name, string(report.Spec.Subject.Kind))
+	return nil
+}
+
+// markReportStale sets the Stale condition on an expired report. The next
+// flush of the subject clears it.
+func (r *Reconciler) markReportStale(ctx context.Context, report *audiciav1alpha1.AudiciaReport, last time.Time) error {
+	meta.SetStatusCondition(&report.Status.Conditions, metav1.Condition{
+		Type:    staleCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  "NoRecentActivity",
+		Message: fmt.Sprintf("No audit activity observed since %s.", last.UTC().Format(time.RFC3339)),
+	})
+	if err := r.Status().Update(ctx, report); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("mark report %s stale: %w", report.Name, err)
+	}
+	return nil
+}
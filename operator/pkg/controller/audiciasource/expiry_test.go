@@ -0,0 +1,133 @@
+package audiciasource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+)
+
+func expiryReport(user, writer string, lastSeen time.Time) *audiciav1alpha1.AudiciaReport {
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: user}
+	report := &audiciav1alpha1.AudiciaReport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        reportNameFor(subject),
+			Namespace:   "default",
+			Annotations: map[string]string{WriterAnnotation: writer},
+		},
+		Spec: audiciav1alpha1.AudiciaReportSpec{Subject: subject},
+	}
+	report.Status.ObservedRules = []audiciav1alpha1.ObservedRule{{
+		APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"},
+		FirstSeen: metav1.NewTime(lastSeen), LastSeen: metav1.NewTime(lastSeen),
+	}}
+	return report
+}
+
+func TestReportActivity(t *testing.T) {
+	old := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	report := expiryReport("alice", "default/src", old)
+	if got := reportActivity(report, nil); !got.Equal(old) {
+		t.Errorf("activity = %v, want %v", got, old)
+	}
+
+	// The aggregator's rules are newer than the report's.
+	recent := old.Add(48 * time.Hour)
+	agg := aggregator.New()
+	agg.Add(normalizer.CanonicalRule{APIGroup: "", Resource: "pods", Verb: "list"}, recent)
+	if got := reportActivity(report, agg); !got.Equal(recent) {
+		t.Errorf("activity = %v, want %v", got, recent)
+	}
+
+	placeholder := &audiciav1alpha1.AudiciaReport{}
+	meta.SetStatusCondition(&placeholder.Status.Conditions, metav1.Condition{
+		Type: "NoActivityObserved", Status: metav1.ConditionTrue, Reason: "AwaitingActivity",
+	})
+	if got := reportActivity(placeholder, nil); !got.IsZero() {
+		t.Errorf("placeholder activity = %v, want zero", got)
+	}
+}
+
+func TestExpireReports(t *testing.T) {
+	now := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	key := types.NamespacedName{Name: "src", Namespace: "default"}
+	source := audiciav1alpha1.AudiciaSource{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}
+
+	stale := expiryReport("stale", key.String(), now.AddDate(0, 0, -10))
+	fresh := expiryReport("fresh", key.String(), now.AddDate(0, 0, -1))
+	foreign := expiryReport("foreign", "default/other", now.AddDate(0, 0, -10))
+	policy := &audiciav1alpha1.AudiciaPolicy{ObjectMeta: metav1.ObjectMeta{
+		Name: policyNameFor(stale.Spec.Subject), Namespace: "default",
+	}}
+
+	t.Run("delete", func(t *testing.T) {
+		r := newTestReconciler(stale.DeepCopy(), fresh.DeepCopy(), foreign.DeepCopy(), policy.DeepCopy())
+		sk := keyFor(stale.Spec.Subject)
+		aggregators := map[subjectKey]*aggregator.Aggregator{sk: aggregator.New()}
+		subjects := map[subjectKey]audiciav1alpha1.Subject{sk: stale.Spec.Subject}
+		limits := audiciav1alpha1.LimitsConfig{ReportTTLDays: 7}
+
+		n, err := r.expireReports(context.Background(), key, source, limits, aggregators, subjects, now, logr.Discard())
+		if err != nil || n != 1 {
+			t.Fatalf("expireReports = %d, %v; want 1, nil", n, err)
+		}
+		if _, ok := aggregators[sk]; ok {
+			t.Error("expected the expired subject to leave the pipeline")
+		}
+		for _, obj := range []client.Object{stale.DeepCopy(), policy.DeepCopy()} {
+			if err := r.Get(context.Background(), client.ObjectKeyFromObject(obj), obj); !errors.IsNotFound(err) {
+				t.Errorf("%s: expected NotFound, got %v", obj.GetName(), err)
+			}
+		}
+		for _, obj := range []client.Object{fresh.DeepCopy(), foreign.DeepCopy()} {
+			if err := r.Get(context.Background(), client.ObjectKeyFromObject(obj), obj); err != nil {
+				t.Errorf("%s: expected the report to be kept, got %v", obj.GetName(), err)
+			}
+		}
+	})
+
+	t.Run("mark stale", func(t *testing.T) {
+		r := newTestReconciler(stale.DeepCopy(), fresh.DeepCopy())
+		limits := audiciav1alpha1.LimitsConfig{ReportTTLDays: 7, ExpiredReportAction: audiciav1alpha1.ExpiredReportMarkStale}
+		run := func() int {
+			n, err := r.expireReports(context.Background(), key, source, limits,
+				map[subjectKey]*aggregator.Aggregator{}, map[subjectKey]audiciav1alpha1.Subject{}, now, logr.Discard())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return n
+		}
+		if n := run(); n != 1 {
+			t.Fatalf("expired %d, want 1", n)
+		}
+		var got audiciav1alpha1.AudiciaReport
+		if err := r.Get(context.Background(), client.ObjectKeyFromObject(stale), &got); err != nil {
+			t.Fatal(err)
+		}
+		if !meta.IsStatusConditionTrue(got.Status.Conditions, staleCondition) {
+			t.Errorf("expected Stale condition, got %+v", got.Status.Conditions)
+		}
+		if n := run(); n != 0 {
+			t.Errorf("already stale report expired again")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		r := newTestReconciler(stale.DeepCopy())
+		n, err := r.expireReports(context.Background(), key, source, audiciav1alpha1.LimitsConfig{},
+			nil, nil, now, logr.Discard())
+		if err != nil || n != 0 {
+			t.Errorf("expireReports = %d, %v; want 0, nil", n, err)
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package audiciasource
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+const (
+	// filterRuleDeny is the filter_rule label of events denied by spec.filters.
+	filterRuleDeny = "deny"
+
+	// defaultFilteredTopN is used when spec.filteredEventTracking omits topN.
+	defaultFilteredTopN = 10
+
+	// filteredSlotsPerEntry is how many names the tracker holds per listed
+	// entry. The spare slots keep the listed counts exact unless many more
+	// distinct names are denied.
+	filteredSlotsPerEntry = 10
+)
+
+// topCounter counts occurrences of names in bounded memory.
+type topCounter struct {
+	counts   map[string]int64
+	capacity int
+}
+
+func newTopCounter(capacity int) *topCounter {
+	return &topCounter{counts: make(map[string]int64, capacity), capacity: capacity}
+}
+
+// add counts n occurrences of name. When the counter is full, the name with
+// the lowest count is replaced and the newcomer inherits that count (the
+// Space-Saving algorithm), so a frequent name is never lost and counts can
+// only be overestimated.
+func (c *topCounter) add(name string, n int64) {
+	if _, ok := c.counts[name]; !ok && len(c.counts) >= c.capacity {
+		var minName string
+		var minCount int64 = -1
+		for k, v := range c.counts {
+			if minCount < 0 || v < minCount || (v == minCount && k < minName) {
+				minName, minCount = k, v
+			}
+		}
+		delete(c.counts, minName)
+		n += minCount
+	}
+	c.counts[name] += n
+}
+
+// top returns the n highest counts, highest first, ties by name.
+func (c *topCounter) top(n int) []audiciav1alpha1.FilteredEventCount {
+	out := make([]audiciav1alpha1.FilteredEventCount, 0, len(c.counts))
+	for name, count := range c.counts {
+		out = append(out, audiciav1alpha1.FilteredEventCount{Name: name, Count: count})
+	}
+	slices.SortFunc(out, func(a, b audiciav1alpha1.FilteredEventCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Name, b.Name))
+	})
+	return out[:min(n, len(out))]
+}
+
+// filteredTracker counts the users and namespaces denied by spec.filters.
+// The pipeline's event workers observe concurrently; the counts are read by
+// the pipeline goroutine once the workers are synced.
+type filteredTracker struct {
+	mu         sync.Mutex
+	topN       int
+	since      time.Time
+	total      int64
+	users      *topCounter
+	namespaces *topCounter
+	dirty      bool
+}
+
+// newFilteredTracker returns nil when tracking is disabled. Counts already in
+// status.filteredEvents are restored, so they carry over restarts.
+func newFilteredTracker(source audiciav1alpha1.AudiciaSource, now time.Time) *filteredTracker {
+	cfg := source.Spec.FilteredEventTracking
+	if cfg == nil {
+		return nil
+	}
+	topN := int(cfg.TopN)
+	if topN <= 0 {
+		topN = defaultFilteredTopN
+	}
+	t := &filteredTracker{
+		topN:       topN,
+		since:      now,
+		users:      newTopCounter(topN * filteredSlotsPerEntry),
+		namespaces: newTopCounter(topN * filteredSlotsPerEntry),
+	}
+	if prev := source.Status.FilteredEvents; prev != nil {
+		t.since = prev.Since.Time
+		t.total = prev.Total
+		for _, u := range prev.Users {
+			t.users.add(u.Name, u.Count)
+		}
+		for _, ns := range prev.Namespaces {
+			t.namespaces.add(ns.Name, ns.Count)
+		}
+	}
+	return t
+}
+
+// observe counts one denied event.
+func (t *filteredTracker) observe(username, namespace string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total++
+	t.users.add(username, 1)
+	if namespace != "" {
+		t.namespaces.add(namespace, 1)
+	}
+	t.dirty = true
+}
+
+// status returns the counts to persist.
+func (t *filteredTracker) status() *audiciav1alpha1.FilteredEventsStatus {
+	return &audiciav1alpha1.FilteredEventsStatus{
+		Since:      metav1.NewTime(t.since),
+		Total:      t.total,
+		Users:      t.users.top(t.topN),
+		Namespaces: t.namespaces.top(t.topN),
+	}
+}
+
+// recordFilteredEvents persists status.filteredEvents when denials were
+// counted since the last call.
+func (r *Reconciler) recordFilteredEvents(ctx context.Context, key types.NamespacedName, t *filteredTracker) {
+	if t == nil || !t.dirty {
+		return
+	}
+	logger := ctrl.Log.WithName("pipeline").WithValues("source", key)
+	status := t.status()
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var source audiciav1alpha1.AudiciaSource
+		if err := r.Get(ctx, key, &source); err != nil {
+			return err
+		}
+		source.Status.FilteredEvents = status
+		return r.Status().Update(ctx, &source)
+	})
+	switch {
+	case err == nil:
+		t.dirty = false
+	case !errors.IsNotFound(err):
+		logger.Error(err, "failed to record filtered events")
+	}
+}
@@ -0,0 +1,135 @@
+package audiciasource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/filter"
+	"github.com/felixnotka/audicia/operator/pkg/strategy"
+)
+
+func TestTopCounter(t *testing.T) {
+	c := newTopCounter(2)
+	for range 5 {
+		c.add("frequent", 1)
+	}
+	c.add("rare", 1)
+	// Full: "rare" (1) is replaced and "new" inherits its count.
+	c.add("new", 1)
+
+	got := c.top(5)
+	if len(got) != 2 || got[0] != (audiciav1alpha1.FilteredEventCount{Name: "frequent", Count: 5}) ||
+		got[1] != (audiciav1alpha1.FilteredEventCount{Name: "new", Count: 2}) {
+		t.Errorf("top = %+v", got)
+	}
+	if got := c.top(1); len(got) != 1 || got[0].Name != "frequent" {
+		t.Errorf("top(1) = %+v", got)
+	}
+}
+
+func TestNewFilteredTracker(t *testing.T) {
+	if tr := newFilteredTracker(audiciav1alpha1.AudiciaSource{}, time.Now()); tr != nil {
+		t.Fatalf("expected nil tracker without config, got %+v", tr)
+	}
+	// A nil tracker must be safe to use.
+	var nilTracker *filteredTracker
+	nilTracker.observe("alice", "default")
+
+	since := metav1.NewTime(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	source := audiciav1alpha1.AudiciaSource{
+		Spec: audiciav1alpha1.AudiciaSourceSpec{FilteredEventTracking: &audiciav1alpha1.FilteredEventTrackingConfig{}},
+		Status: audiciav1alpha1.AudiciaSourceStatus{FilteredEvents: &audiciav1alpha1.FilteredEventsStatus{
+			Since:      since,
+			Total:      7,
+			Users:      []audiciav1alpha1.FilteredEventCount{{Name: "alice", Count: 7}},
+			Namespaces: []audiciav1alpha1.FilteredEventCount{{Name: "default", Count: 7}},
+		}},
+	}
+	tr := newFilteredTracker(source, time.Now())
+	if tr.topN != defaultFilteredTopN {
+		t.Errorf("topN = %d, want %d", tr.topN, defaultFilteredTopN)
+	}
+	tr.observe("alice", "")
+	tr.observe("bob", "prod")
+
+	status := tr.status()
+	if !status.Since.Equal(&since) || status.Total != 9 {
+		t.Errorf("since = %v, total = %d; want restored since and 9", status.Since, status.Total)
+	}
+	if len(status.Users) != 2 || status.Users[0].Name != "alice" || status.Users[0].Count != 8 {
+		t.Errorf("users = %+v", status.Users)
+	}
+	if len(status.Namespaces) != 2 || status.Namespaces[0].Count != 7 || status.Namespaces[1].Name != "prod" {
+		t.Errorf("namespaces = %+v", status.Namespaces)
+	}
+}
+
+func TestEventLoop_RecordsFilteredEvents(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "filtered-source", Namespace: "default"},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			Checkpoint:            audiciav1alpha1.CheckpointConfig{IntervalSeconds: 60},
+			FilteredEventTracking: &audiciav1alpha1.FilteredEventTrackingConfig{TopN: 1},
+		},
+	}
+	r := newTestReconciler(&source)
+	key := types.NamespacedName{Name: "filtered-source", Namespace: "default"}
+	chain, err := filter.NewChain([]audiciav1alpha1.Filter{
+		{Action: audiciav1alpha1.FilterActionDeny, UserPattern: "^system:serviceaccount:"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := func(user, namespace string) auditv1.Event {
+		return auditv1.Event{
+			Verb:      "get",
+			User:      authnv1.UserInfo{Username: user},
+			ObjectRef: &auditv1.ObjectReference{Resource: "pods", Namespace: namespace},
+		}
+	}
+	events := make(chan auditv1.Event, 10)
+	events <- event("system:serviceaccount:prod:api", "prod")
+	events <- event("system:serviceaccount:prod:api", "prod")
+	events <- event("system:serviceaccount:dev:job", "dev")
+	events <- event("alice", "prod")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.eventLoop(ctx, key, source, strategy.NewEngine(audiciav1alpha1.PolicyStrategy{}), chain, nil, nil, &fakeIngestor{}, events, nil, nil)
+		close(done)
+	}()
+	for len(events) > 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	var got audiciav1alpha1.AudiciaSource
+	if err := r.Get(context.Background(), key, &got); err != nil {
+		t.Fatal(err)
+	}
+	fe := got.Status.FilteredEvents
+	if fe == nil {
+		t.Fatal("expected status.filteredEvents")
+	}
+	if fe.Total != 3 {
+		t.Errorf("total = %d, want 3", fe.Total)
+	}
+	wantUser := audiciav1alpha1.FilteredEventCount{Name: "system:serviceaccount:prod:api", Count: 2}
+	if len(fe.Users) != 1 || fe.Users[0] != wantUser {
+		t.Errorf("users = %+v, want [%+v]", fe.Users, wantUser)
+	}
+	if len(fe.Namespaces) != 1 || fe.Namespaces[0].Name != "prod" {
+		t.Errorf("namespaces = %+v, want [prod]", fe.Namespaces)
+	}
+}
@@ -0,0 +1,384 @@
+package audiciasource
+
+import (
+	"cmp"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/strategy"
+)
+
+const (
+	// flushRetryBaseDelay is the delay before the first retry of a failed subject.
+	flushRetryBaseDelay = 5 * time.Second
+	// flushRetryMaxDelay caps the exponential backoff between retries.
+	flushRetryMaxDelay = 5 * time.Minute
+	// maxFailedSubjectsInMessage limits how many subjects the FlushDegraded
+	// condition message lists.
+	maxFailedSubjectsInMessage = 5
+	// reportResyncInterval is how often every subject is flushed, changed
+	// or not, so reports pick up RBAC changes in their compliance, rules
+	// merged by other sources and retention.
+	reportResyncInterval = 5 * time.Minute
+	// flushIntervalShare is the share of the checkpoint interval a rate
+	// limited flush may take, leaving the rest to event processing.
+	flushIntervalShare = 0.5
+)
+
+// reportVersions tracks the aggregator version each subject's report was
+// last written at, so flushes skip subjects nothing was observed for. It is
+// owned by a single pipeline goroutine and is not safe for concurrent use.
+type reportVersions struct {
+	written map[subjectKey]writtenVersion
+	limits  audiciav1alpha1.LimitsConfig
+	synced  time.Time
+}
+
+// writtenVersion is the aggregator version of a report's last write.
+type writtenVersion struct {
+	version uint64
+	at      time.Time
+}
+
+func newReportVersions() *reportVersions {
+	return &reportVersions{written: make(map[subjectKey]writtenVersion)}
+}
+
+// changed returns the aggregators with observations since their report was
+// last written, and the number of subjects skipped. All aggregators are
+// returned when a resync is due or the limits in force changed.
+func (v *reportVersions) changed(
+	aggregators map[subjectKey]*aggregator.Aggregator,
+	limits audiciav1alpha1.LimitsConfig,
+	now time.Time,
+) (map[subjectKey]*aggregator.Aggregator, int) {
+	if now.Sub(v.synced) >= reportResyncInterval || !equality.Semantic.DeepEqual(limits, v.limits) {
+		v.synced, v.limits = now, limits
+		return aggregators, 0
+	}
+	changed := make(map[subjectKey]*aggregator.Aggregator)
+	for sk, agg := range aggregators {
+		if written, ok := v.written[sk]; !ok || agg.Version() != written.version {
+			changed[sk] = agg
+		}
+	}
+	return changed, len(aggregators) - len(changed)
+}
+
+// limit returns the budget subjects of changed whose reports were written
+// longest ago, never-written first, and the number of subjects deferred.
+// A budget of 0 returns changed as it is.
+func (v *reportVersions) limit(changed map[subjectKey]*aggregator.Aggregator, budget int) (map[subjectKey]*aggregator.Aggregator, int) {
+	if budget <= 0 || len(changed) <= budget {
+		return changed, 0
+	}
+	keys := slices.Collect(maps.Keys(changed))
+	slices.SortFunc(keys, func(a, b subjectKey) int {
+		return cmp.Or(v.written[a].at.Compare(v.written[b].at), strings.Compare(a.String(), b.String()))
+	})
+	limited := make(map[subjectKey]*aggregator.Aggregator, budget)
+	for _, sk := range keys[:budget] {
+		limited[sk] = changed[sk]
+	}
+	return limited, len(keys) - budget
+}
+
+// current reports whether the report of sk was written at the version of
+// agg.
+func (v *reportVersions) current(sk subjectKey, agg *aggregator.Aggregator) bool {
+	written, ok := v.written[sk]
+	return ok && written.version == agg.Version()
+}
+
+// record notes the versions written by a flush and forgets subjects that
+// left the pipeline.
+func (v *reportVersions) record(aggregators map[subjectKey]*aggregator.Aggregator, result flushResult) {
+	now := time.Now()
+	for _, sk := range result.succeeded {
+		if agg, ok := aggregators[sk]; ok {
+			v.written[sk] = writtenVersion{version: agg.Version(), at: now}
+		}
+	}
+	for sk := range v.written {
+		if _, ok := aggregators[sk]; !ok {
+			delete(v.written, sk)
+		}
+	}
+}
+
+// flushResult records the outcome of flushing a set of subjects.
+type flushResult struct {
+	succeeded []subjectKey
+	failed    []subjectKey
+	contended map[subjectKey]string // subject → source holding its report
+	unchanged int
+	deferred  int
+	// errs holds the errors of the failed subjects.
+	errs []error
+}
+
+// add records the outcome of flushing one subject.
+func (res *flushResult) add(sk subjectKey, err error) {
+	var contended *reportContendedError
+	switch {
+	case err == nil:
+		res.succeeded = append(res.succeeded, sk)
+	case stderrors.As(err, &contended):
+		if res.contended == nil {
+			res.contended = make(map[subjectKey]string)
+		}
+		res.contended[sk] = contended.holder
+	default:
+		res.failed = append(res.failed, sk)
+		res.errs = append(res.errs, fmt.Errorf("%s: %w", sk, err))
+	}
+}
+
+// retryEntry is the backoff state of one failed subject.
+type retryEntry struct {
+	attempts int
+	next     time.Time
+}
+
+// flushRetryQueue tracks subjects whose last flush failed and schedules
+// their retries with exponential backoff. It is owned by a single pipeline
+// goroutine and is not safe for concurrent use.
+type flushRetryQueue struct {
+	entries map[subjectKey]*retryEntry
+}
+
+func newFlushRetryQueue() *flushRetryQueue {
+	return &flushRetryQueue{entries: make(map[subjectKey]*retryEntry)}
+}
+
+// update applies a flush result: succeeded and contended subjects leave the
+// queue, failed subjects are (re)scheduled with a doubled delay.
+func (q *flushRetryQueue) update(result flushResult, now time.Time) {
+	for _, key := range result.succeeded {
+		delete(q.entries, key)
+	}
+	for key := range result.contended {
+		delete(q.entries, key)
+	}
+	for _, key := range result.failed {
+		e, ok := q.entries[key]
+		if !ok {
+			e = &retryEntry{}
+			q.entries[key] = e
+		}
+		e.attempts++
+		e.next = now.Add(backoffDelay(e.attempts))
+	}
+}
+
+// due returns the subjects whose retry time has passed, sorted for
+// deterministic processing.
+func (q *flushRetryQueue) due(now time.Time) []subjectKey {
+	var keys []subjectKey
+	for key, e := range q.entries {
+		if !e.next.After(now) {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, compareSubjectKeys)
+	return keys
+}
+
+// pending returns the queued subjects, sorted.
+func (q *flushRetryQueue) pending() []subjectKey {
+	keys := make([]subjectKey, 0, len(q.entries))
+	for key := range q.entries {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, compareSubjectKeys)
+	return keys
+}
+
+// arm resets timer to fire at the earliest scheduled retry and returns its
+// channel, or nil (which blocks forever in a select) if nothing is queued.
+func (q *flushRetryQueue) arm(timer *time.Timer, now time.Time) <-chan time.Time {
+	if len(q.entries) == 0 {
+		timer.Stop()
+		return nil
+	}
+	var earliest time.Time
+	for _, e := range q.entries {
+		if earliest.IsZero() || e.next.Before(earliest) {
+			earliest = e.next
+		}
+	}
+	timer.Reset(max(earliest.Sub(now), 0))
+	return timer.C
+}
+
+// backoffDelay returns the retry delay after the given number of failed attempts.
+func backoffDelay(attempts int) time.Duration {
+	d := flushRetryBaseDelay
+	for i := 1; i < attempts && d < flushRetryMaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, flushRetryMaxDelay)
+}
+
+// retryFlushes re-flushes the given subjects. Subjects that no longer have
+// an aggregator are reported as succeeded so they leave the retry queue.
+func (r *Reconciler) retryFlushes(
+	ctx context.Context,
+	key types.NamespacedName,
+	source audiciav1alpha1.AudiciaSource,
+	engine *strategy.Engine,
+	aggregators map[subjectKey]*aggregator.Aggregator,
+	subjects map[subjectKey]audiciav1alpha1.Subject,
+	due []subjectKey,
+) flushResult {
+	logger := ctrl.Log.WithName("pipeline").WithValues("source", key)
+
+	var result flushResult
+	retry := make([]subjectKey, 0, len(due))
+	for _, sk := range due {
+		if _, ok := aggregators[sk]; !ok {
+			result.succeeded = append(result.succeeded, sk)
+			continue
+		}
+		logger.V(1).Info("retrying failed flush", "subject", sk.String())
+		retry = append(retry, sk)
+	}
+	retried := r.flushSubjects(ctx, source, engine, retry, aggregators, subjects, true, logger)
+	retried.succeeded = append(result.succeeded, retried.succeeded...)
+	return retried
+}
+
+// flushSubjects flushes the given subjects on up to r.FlushConcurrency
+// goroutines, each flush waiting for r.FlushLimiter. The aggregators must
+// not be written until it returns. notifyChanges is passed to flushSubject.
+func (r *Reconciler) flushSubjects(
+	ctx context.Context,
+	source audiciav1alpha1.AudiciaSource,
+	engine *strategy.Engine,
+	keys []subjectKey,
+	aggregators map[subjectKey]*aggregator.Aggregator,
+	subjects map[subjectKey]audiciav1alpha1.Subject,
+	notifyChanges bool,
+	logger logr.Logger,
+) flushResult {
+	var (
+		result flushResult
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+	)
+	queue := make(chan subjectKey)
+	for range min(max(r.FlushConcurrency, 1), max(len(keys), 1)) {
+		wg.Go(func() {
+			for sk := range queue {
+				err := r.waitFlush(ctx)
+				if err == nil {
+					err = r.flushSubject(ctx, source, engine, subjects[sk], aggregators[sk], notifyChanges, logger)
+				}
+				mu.Lock()
+				result.add(sk, err)
+				mu.Unlock()
+			}
+		})
+	}
+	for _, sk := range keys {
+		queue <- sk
+	}
+	close(queue)
+	wg.Wait()
+	return result
+}
+
+// waitFlush blocks until r.FlushLimiter permits another subject flush.
+func (r *Reconciler) waitFlush(ctx context.Context) error {
+	if r.FlushLimiter == nil {
+		return nil
+	}
+	if err := r.FlushLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("waiting for flush rate limiter: %w", err)
+	}
+	return nil
+}
+
+// flushBudget returns how many subjects a checkpoint tick may flush so that
+// the flushes, paced by r.FlushLimiter, fit into flushIntervalShare of the
+// interval. Subjects over the budget wait for the next tick. 0 means no
+// budget.
+func (r *Reconciler) flushBudget(interval time.Duration) int {
+	if r.FlushLimiter == nil {
+		return 0
+	}
+	return max(int(float64(r.FlushLimiter.Limit())*interval.Seconds()*flushIntervalShare), 1)
+}
+
+// recordFlushResult feeds a flush result into the retry queue and publishes
+// the outcome in status.lastFlush and the FlushDegraded condition.
+func (r *Reconciler) recordFlushResult(
+	ctx context.Context,
+	key types.NamespacedName,
+	result flushResult,
+	queue *flushRetryQueue,
+) {
+	logger := ctrl.Log.WithName("pipeline").WithValues("source", key)
+	now := time.Now()
+	queue.update(result, now)
+	pending := queue.pending()
+
+	condition := metav1.Condition{
+		Type:    "FlushDegraded",
+		Status:  metav1.ConditionFalse,
+		Reason:  "AllSubjectsFlushed",
+		Message: "All subjects flushed successfully.",
+	}
+	if len(pending) > 0 {
+		listed := make([]string, 0, maxFailedSubjectsInMessage)
+		for _, sk := range pending[:min(len(pending), maxFailedSubjectsInMessage)] {
+			listed = append(listed, sk.String())
+		}
+		msg := fmt.Sprintf("%d subject(s) failed to flush and are queued for retry: %s",
+			len(pending), strings.Join(listed, ", "))
+		if more := len(pending) - len(listed); more > 0 {
+			msg += fmt.Sprintf(" (and %d more)", more)
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "SubjectsFailing"
+		condition.Message = msg
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var source audiciav1alpha1.AudiciaSource
+		if err := r.Get(ctx, key, &source); err != nil {
+			return err
+		}
+		source.Status.LastFlush = &audiciav1alpha1.FlushStatus{
+			Time:         metav1.NewTime(now),
+			Succeeded:    int32(len(result.succeeded)),
+			Failed:       int32(len(result.failed)),
+			PendingRetry: int32(len(pending)),
+			Unchanged:    int32(result.unchanged),
+			Deferred:     int32(result.deferred),
+		}
+		condition.ObservedGeneration = source.Generation
+		meta.SetStatusCondition(&source.Status.Conditions, condition)
+		return r.Status().Update(ctx, &source)
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "failed to record flush status")
+	}
+}
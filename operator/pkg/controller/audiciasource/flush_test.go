@@ -0,0 +1,345 @@
+package audiciasource
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+	"github.com/felixnotka/audicia/operator/pkg/strategy"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{3, 20 * time.Second},
+		{7, 5 * time.Minute},
+		{50, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("attempt %d", tt.attempts), func(t *testing.T) {
+			if got := backoffDelay(tt.attempts); got != tt.want {
+				t.Errorf("backoffDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlushRetryQueue(t *testing.T) {
+	q := newFlushRetryQueue()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	a, b := userKey("a"), userKey("b")
+
+	q.update(flushResult{failed: []subjectKey{b, a}}, now)
+	if due := q.due(now); len(due) != 0 {
+		t.Errorf("expected nothing due immediately, got %v", due)
+	}
+	if due := q.due(now.Add(flushRetryBaseDelay)); len(due) != 2 || due[0] != a {
+		t.Errorf("expected [a b] due after base delay, got %v", due)
+	}
+
+	// "a" fails again (longer backoff), "b" recovers.
+	later := now.Add(flushRetryBaseDelay)
+	q.update(flushResult{failed: []subjectKey{a}, succeeded: []subjectKey{b}}, later)
+	if p := q.pending(); len(p) != 1 || p[0] != a {
+		t.Fatalf("expected only a pending, got %v", p)
+	}
+	if due := q.due(later.Add(flushRetryBaseDelay)); len(due) != 0 {
+		t.Errorf("expected doubled backoff for a, got due %v", due)
+	}
+	if due := q.due(later.Add(2 * flushRetryBaseDelay)); len(due) != 1 {
+		t.Errorf("expected a due after doubled backoff, got %v", due)
+	}
+}
+
+func TestFlushRetryQueue_Arm(t *testing.T) {
+	q := newFlushRetryQueue()
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	if c := q.arm(timer, time.Now()); c != nil {
+		t.Error("expected nil channel for empty queue")
+	}
+
+	now := time.Now()
+	q.entries[userKey("a")] = &retryEntry{attempts: 1, next: now.Add(-time.Second)}
+	c := q.arm(timer, now)
+	if c == nil {
+		t.Fatal("expected armed channel")
+	}
+	select {
+	case <-c:
+	case <-time.After(time.Second):
+		t.Error("expected overdue retry to fire immediately")
+	}
+}
+
+// userKey returns the subject key of a User.
+func userKey(name string) subjectKey {
+	return subjectKey{kind: audiciav1alpha1.SubjectKindUser, name: name}
+}
+
+// failingReportReconciler builds a reconciler whose client rejects writes of the
+// report with the given name.
+func failingReportReconciler(source *audiciav1alpha1.AudiciaSource, failReport string) *Reconciler {
+	s := newTestScheme()
+	deny := func(obj client.Object) error {
+		if _, ok := obj.(*audiciav1alpha1.AudiciaReport); ok && obj.GetName() == failReport {
+			return errors.NewForbidden(schema.GroupResource{Group: "audicia.io", Resource: "audiciareports"}, failReport, fmt.Errorf("denied"))
+		}
+		return nil
+	}
+	return &Reconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(s).
+			WithObjects(source).
+			WithStatusSubresource(
+				&audiciav1alpha1.AudiciaSource{},
+				&audiciav1alpha1.AudiciaReport{},
+				&audiciav1alpha1.AudiciaPolicy{},
+			).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if err := deny(obj); err != nil {
+						return err
+					}
+					return c.Create(ctx, obj, opts...)
+				},
+			}).
+			Build(),
+		Scheme:    s,
+		Recorder:  events.NewFakeRecorder(100),
+		pipelines: make(map[types.NamespacedName]*pipelineState),
+	}
+}
+
+func TestFlushReports_PartialFailure(t *testing.T) {
+	source := &audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "partial", Namespace: "default"},
+	}
+	r := failingReportReconciler(source, "report-sa-bad")
+	engine := strategy.NewEngine(audiciav1alpha1.PolicyStrategy{})
+	key := types.NamespacedName{Name: "partial", Namespace: "default"}
+
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+	for _, name := range []string{"sa-good", "sa-bad"} {
+		subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: name, Namespace: "default"}
+		sk := keyFor(subject)
+		subjects[sk] = subject
+		aggregators[sk] = aggregator.New()
+		aggregators[sk].Add(normalizer.CanonicalRule{Resource: "pods", Verb: "get", Namespace: "default"}, time.Now())
+	}
+
+	result := r.flushReports(context.Background(), key, *source, engine, aggregators, subjects)
+	if len(result.succeeded) != 1 || len(result.failed) != 1 {
+		t.Fatalf("expected 1 success and 1 failure, got %+v", result)
+	}
+	if result.failed[0].String() != "ServiceAccount/default/sa-bad" {
+		t.Errorf("unexpected failed subject %q", result.failed[0])
+	}
+
+	queue := newFlushRetryQueue()
+	r.recordFlushResult(context.Background(), key, result, queue)
+
+	var updated audiciav1alpha1.AudiciaSource
+	if err := r.Get(context.Background(), key, &updated); err != nil {
+		t.Fatalf("get source: %v", err)
+	}
+	lf := updated.Status.LastFlush
+	if lf == nil || lf.Succeeded != 1 || lf.Failed != 1 || lf.PendingRetry != 1 {
+		t.Errorf("unexpected lastFlush %+v", lf)
+	}
+	if !meta.IsStatusConditionTrue(updated.Status.Conditions, "FlushDegraded") {
+		t.Errorf("expected FlushDegraded=True, got %+v", updated.Status.Conditions)
+	}
+
+	// Once the subject recovers, the condition clears.
+	r.recordFlushResult(context.Background(), key, flushResult{succeeded: result.failed}, queue)
+	if err := r.Get(context.Background(), key, &updated); err != nil {
+		t.Fatalf("get source: %v", err)
+	}
+	if !meta.IsStatusConditionFalse(updated.Status.Conditions, "FlushDegraded") {
+		t.Errorf("expected FlushDegraded=False, got %+v", updated.Status.Conditions)
+	}
+	if updated.Status.LastFlush.PendingRetry != 0 {
+		t.Errorf("expected no pending retries, got %d", updated.Status.LastFlush.PendingRetry)
+	}
+}
+
+func TestRetryFlushes_DropsUnknownSubjects(t *testing.T) {
+	source := &audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "retry", Namespace: "default"},
+	}
+	r := newTestReconciler(source)
+	engine := strategy.NewEngine(audiciav1alpha1.PolicyStrategy{})
+
+	result := r.retryFlushes(context.Background(), types.NamespacedName{Name: "retry", Namespace: "default"},
+		*source, engine, nil, nil, []subjectKey{{kind: audiciav1alpha1.SubjectKindServiceAccount, namespace: "default", name: "gone"}})
+	if len(result.succeeded) != 1 || len(result.failed) != 0 {
+		t.Errorf("expected unknown subject to leave the queue, got %+v", result)
+	}
+}
+
+func TestReportVersions(t *testing.T) {
+	alice := subjectKey{kind: audiciav1alpha1.SubjectKindUser, name: "alice"}
+	bob := subjectKey{kind: audiciav1alpha1.SubjectKindUser, name: "bob"}
+	aggregators := map[subjectKey]*aggregator.Aggregator{alice: aggregator.New(), bob: aggregator.New()}
+	rule := normalizer.CanonicalRule{Resource: "pods", Verb: "get"}
+	aggregators[alice].Add(rule, time.Now())
+	aggregators[bob].Add(rule, time.Now())
+
+	v := newReportVersions()
+	now := time.Now()
+	limits := audiciav1alpha1.LimitsConfig{MaxRulesPerReport: 200}
+	if changed, _ := v.changed(aggregators, limits, now); len(changed) != 2 {
+		t.Fatalf("first flush: %d changed, want all", len(changed))
+	}
+	v.record(aggregators, flushResult{succeeded: []subjectKey{alice}, failed: []subjectKey{bob}})
+
+	aggregators[alice].Add(rule, time.Now())
+	if changed, unchanged := v.changed(aggregators, limits, now.Add(time.Minute)); len(changed) != 2 || unchanged != 0 {
+		t.Errorf("changed = %d, unchanged = %d; want alice with new events and bob after a failure", len(changed), unchanged)
+	}
+	v.record(aggregators, flushResult{succeeded: []subjectKey{alice, bob}})
+
+	changed, unchanged := v.changed(aggregators, limits, now.Add(2*time.Minute))
+	if len(changed) != 0 || unchanged != 2 {
+		t.Errorf("changed = %d, unchanged = %d; want nothing to flush", len(changed), unchanged)
+	}
+	if changed, _ := v.changed(aggregators, audiciav1alpha1.LimitsConfig{MaxRulesPerReport: 50}, now.Add(3*time.Minute)); len(changed) != 2 {
+		t.Errorf("limits change: %d changed, want all", len(changed))
+	}
+	if changed, _ := v.changed(aggregators, audiciav1alpha1.LimitsConfig{MaxRulesPerReport: 50}, now.Add(3*time.Minute+reportResyncInterval)); len(changed) != 2 {
+		t.Errorf("resync: %d changed, want all", len(changed))
+	}
+
+	delete(aggregators, bob)
+	v.record(aggregators, flushResult{})
+	if _, ok := v.written[bob]; ok {
+		t.Error("expired subject still tracked")
+	}
+}
+
+func TestFlushReport_SkipsUnchangedStatus(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{ObjectMeta: metav1.ObjectMeta{Name: "src", Namespace: "default"}}
+	r := newTestReconciler(&source)
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "backend", Namespace: "default"}
+	rules := []audiciav1alpha1.ObservedRule{makeObservedRule("pods", "get", "default", time.Now())}
+	key := types.NamespacedName{Name: reportNameFor(subject), Namespace: "default"}
+
+	get := func() audiciav1alpha1.AudiciaReport {
+		var report audiciav1alpha1.AudiciaReport
+		if err := r.Get(context.Background(), key, &report); err != nil {
+			t.Fatal(err)
+		}
+		return report
+	}
+	if err := r.flushReport(context.Background(), source, subject, rules, nil, nil, 1, true, logr.Discard()); err != nil {
+		t.Fatal(err)
+	}
+	first := get()
+	if len(first.Status.ObservedRules) != 1 {
+		t.Fatalf("status not applied: %+v", first.Status)
+	}
+
+	if err := r.flushReport(context.Background(), source, subject, rules, nil, nil, 1, true, logr.Discard()); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(); got.ResourceVersion != first.ResourceVersion {
+		t.Errorf("unchanged report rewritten: resourceVersion %s → %s", first.ResourceVersion, got.ResourceVersion)
+	}
+
+	rules = append(rules, makeObservedRule("secrets", "list", "default", time.Now()))
+	if err := r.flushReport(context.Background(), source, subject, rules, nil, nil, 2, true, logr.Discard()); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(); len(got.Status.ObservedRules) != 2 || got.Status.EventsProcessed != 2 {
+		t.Errorf("changed status not applied: %d rules, %d events", len(got.Status.ObservedRules), got.Status.EventsProcessed)
+	}
+}
+
+func TestReportVersions_Limit(t *testing.T) {
+	keys := make([]subjectKey, 4)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	for i := range keys {
+		keys[i] = subjectKey{kind: audiciav1alpha1.SubjectKindUser, name: fmt.Sprintf("user-%d", i)}
+		aggregators[keys[i]] = aggregator.New()
+	}
+	v := newReportVersions()
+	v.written[keys[0]] = writtenVersion{at: time.Now().Add(-time.Minute)}
+	v.written[keys[1]] = writtenVersion{at: time.Now().Add(-time.Hour)}
+	v.written[keys[2]] = writtenVersion{at: time.Now()}
+
+	limited, deferred := v.limit(aggregators, 2)
+	if deferred != 2 || len(limited) != 2 {
+		t.Fatalf("limited = %d, deferred = %d; want 2 and 2", len(limited), deferred)
+	}
+	if limited[keys[3]] == nil || limited[keys[1]] == nil {
+		t.Errorf("limited = %v, want the never-written and the oldest subject", limited)
+	}
+
+	if all, deferred := v.limit(aggregators, 0); len(all) != 4 || deferred != 0 {
+		t.Errorf("budget 0 limited %d subjects, deferred %d", len(all), deferred)
+	}
+}
+
+func TestFlushReports_ConcurrentAndRateLimited(t *testing.T) {
+	source := &audiciav1alpha1.AudiciaSource{ObjectMeta: metav1.ObjectMeta{Name: "burst", Namespace: "default"}}
+	r := newTestReconciler(source)
+	r.FlushConcurrency = 4
+	r.FlushLimiter = rate.NewLimiter(50, 1)
+	engine := strategy.NewEngine(audiciav1alpha1.PolicyStrategy{})
+	key := types.NamespacedName{Name: "burst", Namespace: "default"}
+
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+	for i := range 10 {
+		subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: fmt.Sprintf("sa-%d", i), Namespace: "default"}
+		sk := keyFor(subject)
+		subjects[sk] = subject
+		aggregators[sk] = aggregator.New()
+		aggregators[sk].Add(normalizer.CanonicalRule{Resource: "pods", Verb: "get", Namespace: "default"}, time.Now())
+	}
+
+	start := time.Now()
+	result := r.flushReports(context.Background(), key, *source, engine, aggregators, subjects)
+	if len(result.succeeded) != 10 || len(result.failed) != 0 {
+		t.Fatalf("result = %+v, want 10 subjects flushed", result)
+	}
+	// Nine flushes beyond the burst at 50 per second take at least 180ms.
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("flushed in %s, want the rate limit to pace the flushes", elapsed)
+	}
+
+	var reports audiciav1alpha1.AudiciaReportList
+	if err := r.List(context.Background(), &reports); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports.Items) != 10 {
+		t.Errorf("%d reports written, want 10", len(reports.Items))
+	}
+
+	if budget := r.flushBudget(30 * time.Second); budget != 750 {
+		t.Errorf("budget = %d, want 50/s over half of 30s", budget)
+	}
+}
@@ -0,0 +1,136 @@
+package audiciasource
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+)
+
+const (
+	// defaultGapThreshold is used when spec.gapDetection omits a threshold.
+	defaultGapThreshold = 5 * time.Minute
+
+	// maxRecentGaps bounds status.gaps.recent.
+	maxRecentGaps = 10
+)
+
+// gapDetector watches audit event timestamps for silences longer than the
+// threshold. Timestamps only ever advance the watermark, so events that
+// arrive out of order (HA API servers, batched webhooks) never open a gap.
+// Detected gaps are held until the next checkpoint persists them.
+type gapDetector struct {
+	threshold time.Duration
+	last      time.Time
+	// resumed is true until the first event after a restart that resumed
+	// from a persisted watermark; a gap found then spans the downtime.
+	resumed bool
+	pending []audiciav1alpha1.IngestionGap
+}
+
+// newGapDetector returns nil when gap detection is disabled.
+func newGapDetector(source audiciav1alpha1.AudiciaSource) *gapDetector {
+	cfg := source.Spec.GapDetection
+	if cfg == nil {
+		return nil
+	}
+	d := &gapDetector{threshold: time.Duration(cfg.ThresholdSeconds) * time.Second}
+	if d.threshold <= 0 {
+		d.threshold = defaultGapThreshold
+	}
+	if g := source.Status.Gaps; g != nil && g.LastEventTime != nil {
+		d.last = g.LastEventTime.Time
+		d.resumed = true
+	}
+	return d
+}
+
+// eventTime returns the timestamp used for continuity tracking.
+func eventTime(event auditv1.Event) time.Time {
+	if !event.StageTimestamp.IsZero() {
+		return event.StageTimestamp.Time
+	}
+	return event.RequestReceivedTimestamp.Time
+}
+
+// observe records one event timestamp.
+func (d *gapDetector) observe(ts time.Time) {
+	if d == nil || ts.IsZero() {
+		return
+	}
+	if !d.last.IsZero() && ts.Sub(d.last) > d.threshold {
+		kind := audiciav1alpha1.GapKindStream
+		if d.resumed {
+			kind = audiciav1alpha1.GapKindDowntime
+		}
+		d.pending = append(d.pending, audiciav1alpha1.IngestionGap{
+			Kind:          kind,
+			Start:         metav1.NewTime(d.last),
+			End:           metav1.NewTime(ts),
+			MissedSeconds: int64(ts.Sub(d.last).Seconds()),
+		})
+	}
+	d.resumed = false
+	if ts.After(d.last) {
+		d.last = ts
+	}
+}
+
+// apply writes the watermark and pending gaps into a freshly read status. It
+// is safe to call repeatedly across conflict retries; commit clears the
+// pending gaps once the write has succeeded.
+func (d *gapDetector) apply(status *audiciav1alpha1.AudiciaSourceStatus) {
+	if d == nil || d.last.IsZero() {
+		return
+	}
+	if status.Gaps == nil {
+		status.Gaps = &audiciav1alpha1.GapStatus{}
+	}
+	g := status.Gaps
+	last := metav1.NewTime(d.last)
+	g.LastEventTime = &last
+	for _, gap := range d.pending {
+		g.Count++
+		g.TotalMissedSeconds += gap.MissedSeconds
+		g.Recent = append(g.Recent, gap)
+	}
+	if len(g.Recent) > maxRecentGaps {
+		g.Recent = g.Recent[len(g.Recent)-maxRecentGaps:]
+	}
+	if g.Count > 0 {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:   "GapsDetected",
+			Status: metav1.ConditionTrue,
+			Reason: "IngestionGaps",
+			Message: fmt.Sprintf("%d audit stream gaps detected, about %s of activity unobserved; suggested policies may be incomplete.",
+				g.Count, time.Duration(g.TotalMissedSeconds)*time.Second),
+		})
+	}
+}
+
+// commit clears persisted gaps and returns them for reporting.
+func (d *gapDetector) commit() []audiciav1alpha1.IngestionGap {
+	if d == nil {
+		return nil
+	}
+	gaps := d.pending
+	d.pending = nil
+	return gaps
+}
+
+// reportGaps emits a Warning event and updates metrics for newly persisted gaps.
+func (r *Reconciler) reportGaps(source *audiciav1alpha1.AudiciaSource, gaps []audiciav1alpha1.IngestionGap) {
+	for _, gap := range gaps {
+		metrics.IngestionGapSecondsTotal.WithLabelValues(source.Name).Add(float64(gap.MissedSeconds))
+		r.Recorder.Eventf(source, nil, corev1.EventTypeWarning, "IngestionGapDetected", "Ingest",
+			"%s gap: no audit events between %s and %s (%s)",
+			gap.Kind, gap.Start.UTC().Format(time.RFC3339), gap.End.UTC().Format(time.RFC3339),
+			time.Duration(gap.MissedSeconds)*time.Second)
+	}
+}
@@ -0,0 +1,202 @@
+package audiciasource
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/client-go/tools/events"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/ingestor"
+)
+
+var gapBase = time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+
+func gapSource(threshold int32, lastEvent *time.Time) audiciav1alpha1.AudiciaSource {
+	source := audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "gap-source", Namespace: "default"},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			GapDetection: &audiciav1alpha1.GapDetectionConfig{ThresholdSeconds: threshold},
+		},
+	}
+	if lastEvent != nil {
+		ts := metav1.NewTime(*lastEvent)
+		source.Status.Gaps = &audiciav1alpha1.GapStatus{LastEventTime: &ts}
+	}
+	return source
+}
+
+func TestNewGapDetector_DisabledWithoutConfig(t *testing.T) {
+	d := newGapDetector(audiciav1alpha1.AudiciaSource{})
+	if d != nil {
+		t.Fatalf("expected nil detector, got %+v", d)
+	}
+	// A nil detector must be safe to use everywhere.
+	d.observe(gapBase)
+	var status audiciav1alpha1.AudiciaSourceStatus
+	d.apply(&status)
+	if status.Gaps != nil || d.commit() != nil {
+		t.Error("nil detector should not touch status")
+	}
+}
+
+func TestNewGapDetector_DefaultThreshold(t *testing.T) {
+	d := newGapDetector(gapSource(0, nil))
+	if d.threshold != defaultGapThreshold {
+		t.Errorf("threshold = %v, want %v", d.threshold, defaultGapThreshold)
+	}
+}
+
+func TestGapDetector_Observe(t *testing.T) {
+	tests := []struct {
+		name      string
+		offsets   []time.Duration
+		wantGaps  int
+		wantTotal int64
+	}{
+		{"steady stream", []time.Duration{0, 30 * time.Second, 60 * time.Second}, 0, 0},
+		{"exactly at threshold", []time.Duration{0, 60 * time.Second}, 0, 0},
+		{"single silence", []time.Duration{0, 10 * time.Second, 5 * time.Minute}, 1, 290},
+		{"out of order events", []time.Duration{0, 50 * time.Second, -10 * time.Second, 90 * time.Second}, 0, 0},
+		{"two silences", []time.Duration{0, 2 * time.Minute, 4 * time.Minute}, 2, 240},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newGapDetector(gapSource(60, nil))
+			for _, off := range tt.offsets {
+				d.observe(gapBase.Add(off))
+			}
+			gaps := d.commit()
+			if len(gaps) != tt.wantGaps {
+				t.Fatalf("got %d gaps, want %d: %+v", len(gaps), tt.wantGaps, gaps)
+			}
+			var total int64
+			for _, g := range gaps {
+				if g.Kind != audiciav1alpha1.GapKindStream {
+					t.Errorf("kind = %s, want Stream", g.Kind)
+				}
+				total += g.MissedSeconds
+			}
+			if total != tt.wantTotal {
+				t.Errorf("missed seconds = %d, want %d", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestGapDetector_DowntimeAfterResume(t *testing.T) {
+	last := gapBase
+	d := newGapDetector(gapSource(60, &last))
+
+	d.observe(gapBase.Add(10 * time.Minute))
+	d.observe(gapBase.Add(20 * time.Minute))
+
+	gaps := d.commit()
+	if len(gaps) != 2 {
+		t.Fatalf("got %d gaps, want 2: %+v", len(gaps), gaps)
+	}
+	if gaps[0].Kind != audiciav1alpha1.GapKindDowntime {
+		t.Errorf("first gap kind = %s, want Downtime", gaps[0].Kind)
+	}
+	if gaps[1].Kind != audiciav1alpha1.GapKindStream {
+		t.Errorf("second gap kind = %s, want Stream", gaps[1].Kind)
+	}
+}
+
+func TestGapDetector_EventTimeFallback(t *testing.T) {
+	received := metav1.NewMicroTime(gapBase)
+	event := auditv1.Event{RequestReceivedTimestamp: received}
+	if got := eventTime(event); !got.Equal(gapBase) {
+		t.Errorf("eventTime = %v, want RequestReceivedTimestamp %v", got, gapBase)
+	}
+	event.StageTimestamp = metav1.NewMicroTime(gapBase.Add(time.Second))
+	if got := eventTime(event); !got.Equal(gapBase.Add(time.Second)) {
+		t.Errorf("eventTime = %v, want StageTimestamp", got)
+	}
+}
+
+func TestGapDetector_ApplyIsIdempotentAndTrims(t *testing.T) {
+	d := newGapDetector(gapSource(60, nil))
+	for i := 0; i <= maxRecentGaps+2; i++ {
+		d.observe(gapBase.Add(time.Duration(i) * 2 * time.Minute))
+	}
+
+	// Simulate a conflict retry: apply runs against two fresh reads.
+	for attempt := 0; attempt < 2; attempt++ {
+		var status audiciav1alpha1.AudiciaSourceStatus
+		d.apply(&status)
+
+		g := status.Gaps
+		if g == nil {
+			t.Fatal("expected gap status")
+		}
+		if g.Count != maxRecentGaps+2 {
+			t.Errorf("attempt %d: Count = %d, want %d", attempt, g.Count, maxRecentGaps+2)
+		}
+		if len(g.Recent) != maxRecentGaps {
+			t.Errorf("attempt %d: len(Recent) = %d, want %d", attempt, len(g.Recent), maxRecentGaps)
+		}
+		if g.TotalMissedSeconds != int64(maxRecentGaps+2)*120 {
+			t.Errorf("attempt %d: TotalMissedSeconds = %d", attempt, g.TotalMissedSeconds)
+		}
+		if !meta.IsStatusConditionTrue(status.Conditions, "GapsDetected") {
+			t.Errorf("attempt %d: expected GapsDetected=True", attempt)
+		}
+	}
+
+	if len(d.commit()) != maxRecentGaps+2 || d.commit() != nil {
+		t.Error("commit should return pending gaps once")
+	}
+}
+
+func TestFlushCheckpoint_PersistsGaps(t *testing.T) {
+	source := gapSource(60, nil)
+	r := newTestReconciler(&source)
+	rec := events.NewFakeRecorder(10)
+	r.Recorder = rec
+	key := types.NamespacedName{Name: source.Name, Namespace: source.Namespace}
+
+	d := newGapDetector(source)
+	d.observe(gapBase)
+	d.observe(gapBase.Add(10 * time.Minute))
+
+	r.flushCheckpoint(context.Background(), key, &fakeIngestor{pos: ingestor.Position{FileOffset: 10}}, d)
+
+	var updated audiciav1alpha1.AudiciaSource
+	if err := r.Get(context.Background(), key, &updated); err != nil {
+		t.Fatal(err)
+	}
+	g := updated.Status.Gaps
+	if g == nil || g.Count != 1 || g.TotalMissedSeconds != 600 {
+		t.Fatalf("unexpected gap status: %+v", g)
+	}
+	if !g.LastEventTime.Time.Equal(gapBase.Add(10 * time.Minute)) {
+		t.Errorf("LastEventTime = %v", g.LastEventTime)
+	}
+	if !meta.IsStatusConditionTrue(updated.Status.Conditions, "GapsDetected") {
+		t.Error("expected GapsDetected=True")
+	}
+
+	evts := drainEvents(rec)
+	if len(evts) != 1 || !strings.Contains(evts[0], "IngestionGapDetected") {
+		t.Errorf("expected one IngestionGapDetected event, got %v", evts)
+	}
+
+	// A second flush must not double-count the persisted gap.
+	r.flushCheckpoint(context.Background(), key, &fakeIngestor{pos: ingestor.Position{FileOffset: 20}}, d)
+	if err := r.Get(context.Background(), key, &updated); err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status.Gaps.Count != 1 {
+		t.Errorf("Count = %d after second flush, want 1", updated.Status.Gaps.Count)
+	}
+	if evts := drainEvents(rec); len(evts) != 0 {
+		t.Errorf("unexpected events on second flush: %v", evts)
+	}
+}
@@ -0,0 +1,59 @@
+package audiciasource
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// ImportedFromAnnotation marks a report whose observed rules were imported
+// from another tool (e.g. "audit2rbac") rather than observed by Audicia. The
+// pipeline seeds its aggregator with such a report's rules, so the imported
+// history is kept when the report is next written.
+const ImportedFromAnnotation = "audicia.io/imported-from"
+
+// ReportKey returns the AudiciaReport that a source in sourceNamespace writes
+// for subject.
+func ReportKey(sourceNamespace string, subject audiciav1alpha1.Subject) types.NamespacedName {
+	source := audiciav1alpha1.AudiciaSource{}
+	source.Namespace = sourceNamespace
+	return types.NamespacedName{
+		Namespace: reportNamespaceFor(source, subject),
+		Name:      reportNameFor(subject),
+	}
+}
+
+// PolicyName returns the name of the AudiciaPolicy suggested for subject,
+// which is written next to its report.
+func PolicyName(subject audiciav1alpha1.Subject) string {
+	return policyNameFor(subject)
+}
+
+// seedReportRules merges the rules of the subject's existing report into agg
+// when the report carries ImportedFromAnnotation, or when the source merges
+// reports with other sources (spec.reportMerge: Union), so that rules
+// written by another source are kept. Seeding is idempotent, so the report
+// is simply re-read on every flush.
+func (r *Reconciler) seedReportRules(ctx context.Context, source audiciav1alpha1.AudiciaSource, subject audiciav1alpha1.Subject, agg *aggregator.Aggregator) error {
+	var report audiciav1alpha1.AudiciaReport
+	if err := r.Get(ctx, ReportKey(source.Namespace, subject), &report); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if source.Spec.ReportMerge == audiciav1alpha1.ReportMergeUnion {
+		agg.Seed(report.Status.ObservedRules)
+		agg.SeedDenied(report.Status.DeniedRules)
+		return nil
+	}
+	if report.Annotations[ImportedFromAnnotation] == "" {
+		return nil
+	}
+	agg.Seed(report.Status.ObservedRules)
+	return nil
+}
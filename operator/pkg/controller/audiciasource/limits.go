@@ -0,0 +1,167 @@
+package audiciasource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// limitsPreview is the impact of replacing the applied limits with new ones.
+type limitsPreview struct {
+	dropped  int
+	subjects int
+}
+
+// previewLimits counts the rules the proposed limits would drop on top of
+// those the applied limits already drop.
+func previewLimits(
+	aggregators map[subjectKey]*aggregator.Aggregator,
+	applied, proposed audiciav1alpha1.LimitsConfig,
+	now time.Time,
+) limitsPreview {
+	var p limitsPreview
+	for _, agg := range aggregators {
+		rules := agg.Rules()
+		extra := aggregator.Compact(rules, proposed, now).Dropped() - aggregator.Compact(rules, applied, now).Dropped()
+		if extra > 0 {
+			p.dropped += extra
+			p.subjects++
+		}
+	}
+	return p
+}
+
+// sameLimits compares the limits that affect compaction.
+func sameLimits(a, b audiciav1alpha1.LimitsConfig) bool {
+	return a.MaxRulesPerReport == b.MaxRulesPerReport && a.RetentionDays == b.RetentionDays &&
+		a.ConsolidateSubresources == b.ConsolidateSubresources &&
+		equality.Semantic.DeepEqual(a.RetentionPolicies, b.RetentionPolicies)
+}
+
+// nextLimitsStatus decides which limits are in force. spec.limits applies
+// immediately unless it would drop rules and a grace period is configured;
+// then the applied limits stay in force until the grace period, counted
+// from when these exact limits were first seen, has elapsed.
+func nextLimitsStatus(
+	spec audiciav1alpha1.LimitsConfig,
+	current *audiciav1alpha1.LimitsStatus,
+	preview limitsPreview,
+	now time.Time,
+) *audiciav1alpha1.LimitsStatus {
+	inForce := &audiciav1alpha1.LimitsStatus{Applied: spec}
+	if current == nil || sameLimits(current.Applied, spec) || spec.GracePeriodHours <= 0 || preview.dropped == 0 {
+		return inForce
+	}
+
+	since := now
+	if current.Pending != nil && current.PendingSince != nil && sameLimits(*current.Pending, spec) {
+		since = current.PendingSince.Time
+	}
+	if !now.Before(since.Add(time.Duration(spec.GracePeriodHours) * time.Hour)) {
+		return inForce
+	}
+
+	pending := spec
+	pendingSince := metav1.NewTime(since)
+	return &audiciav1alpha1.LimitsStatus{
+		Applied:                 current.Applied,
+		Pending:                 &pending,
+		PendingSince:            &pendingSince,
+		PendingDroppedRules:     int32(preview.dropped),
+		PendingAffectedSubjects: int32(preview.subjects),
+	}
+}
+
+// resolveLimits returns the limits the next flush compacts with and records
+// them in status.limits, together with the LimitsChangePending condition.
+// If the status cannot be read or written, spec.limits is used.
+func (r *Reconciler) resolveLimits(
+	ctx context.Context,
+	key types.NamespacedName,
+	spec audiciav1alpha1.LimitsConfig,
+	aggregators map[subjectKey]*aggregator.Aggregator,
+) audiciav1alpha1.LimitsConfig {
+	logger := ctrl.Log.WithName("pipeline").WithValues("source", key)
+	now := time.Now()
+
+	var source audiciav1alpha1.AudiciaSource
+	var previous, next *audiciav1alpha1.LimitsStatus
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := r.Get(ctx, key, &source); err != nil {
+			return err
+		}
+		previous = source.Status.Limits
+		var preview limitsPreview
+		if previous != nil && !sameLimits(previous.Applied, spec) {
+			preview = previewLimits(aggregators, previous.Applied, spec, now)
+		}
+		next = nextLimitsStatus(spec, previous, preview, now)
+		if equality.Semantic.DeepEqual(previous, next) {
+			return nil
+		}
+		source.Status.Limits = next
+		setLimitsCondition(&source, next)
+		return r.Status().Update(ctx, &source)
+	})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error(err, "failed to record limits status")
+		}
+		return spec
+	}
+
+	wasPending := previous != nil && previous.Pending != nil
+	switch {
+	case next.Pending != nil && (!wasPending || !next.PendingSince.Equal(previous.PendingSince)):
+		r.Recorder.Eventf(&source, nil, corev1.EventTypeWarning, "LimitsChangePending", "Compact",
+			"%s; previous limits stay in force until %s", limitsPendingMessage(next),
+			next.PendingSince.Add(time.Duration(spec.GracePeriodHours)*time.Hour).UTC().Format(time.RFC3339))
+	case next.Pending == nil && wasPending:
+		r.Recorder.Eventf(&source, nil, corev1.EventTypeNormal, "LimitsChangeApplied", "Compact",
+			"spec.limits (maxRulesPerReport=%d, retentionDays=%d) now in force",
+			next.Applied.MaxRulesPerReport, next.Applied.RetentionDays)
+	}
+	return next.Applied
+}
+
+// setLimitsCondition reflects a pending limits change in the
+// LimitsChangePending condition. The condition is only added once a change
+// has been held back.
+func setLimitsCondition(source *audiciav1alpha1.AudiciaSource, status *audiciav1alpha1.LimitsStatus) {
+	if status.Pending != nil {
+		meta.SetStatusCondition(&source.Status.Conditions, metav1.Condition{
+			Type:               "LimitsChangePending",
+			Status:             metav1.ConditionTrue,
+			Reason:             "GracePeriod",
+			Message:            limitsPendingMessage(status) + ".",
+			ObservedGeneration: source.Generation,
+		})
+		return
+	}
+	if meta.FindStatusCondition(source.Status.Conditions, "LimitsChangePending") != nil {
+		meta.SetStatusCondition(&source.Status.Conditions, metav1.Condition{
+			Type:               "LimitsChangePending",
+			Status:             metav1.ConditionFalse,
+			Reason:             "LimitsApplied",
+			Message:            "spec.limits is in force.",
+			ObservedGeneration: source.Generation,
+		})
+	}
+}
+
+func limitsPendingMessage(status *audiciav1alpha1.LimitsStatus) string {
+	return fmt.Sprintf("spec.limits change would drop %d rule(s) across %d subject(s)",
+		status.PendingDroppedRules, status.PendingAffectedSubjects)
+}
@@ -0,0 +1,117 @@
+package audiciasource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+)
+
+func TestNextLimitsStatus(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := audiciav1alpha1.LimitsConfig{MaxRulesPerReport: 200, RetentionDays: 30}
+	strict := audiciav1alpha1.LimitsConfig{MaxRulesPerReport: 50, RetentionDays: 30, GracePeriodHours: 24}
+	since := func(ago time.Duration) *metav1.Time {
+		t := metav1.NewTime(now.Add(-ago))
+		return &t
+	}
+	withPolicies := strict
+	withPolicies.RetentionPolicies = []audiciav1alpha1.RetentionPolicy{{Name: "events", Resources: []string{"events"}, RetentionDays: 7}}
+	drops := limitsPreview{dropped: 12, subjects: 3}
+
+	tests := []struct {
+		name        string
+		spec        audiciav1alpha1.LimitsConfig
+		current     *audiciav1alpha1.LimitsStatus
+		preview     limitsPreview
+		wantApplied audiciav1alpha1.LimitsConfig
+		wantSince   *metav1.Time
+	}{
+		{"first flush applies spec", strict, nil, drops, strict, nil},
+		{"unchanged", old, &audiciav1alpha1.LimitsStatus{Applied: old}, limitsPreview{}, old, nil},
+		{"no grace period", audiciav1alpha1.LimitsConfig{MaxRulesPerReport: 50}, &audiciav1alpha1.LimitsStatus{Applied: old}, drops, audiciav1alpha1.LimitsConfig{MaxRulesPerReport: 50}, nil},
+		{"change drops nothing", strict, &audiciav1alpha1.LimitsStatus{Applied: old}, limitsPreview{}, strict, nil},
+		{"change starts grace period", strict, &audiciav1alpha1.LimitsStatus{Applied: old}, drops, old, since(0)},
+		{"grace period running", strict, &audiciav1alpha1.LimitsStatus{Applied: old, Pending: &strict, PendingSince: since(time.Hour)}, drops, old, since(time.Hour)},
+		{"grace period elapsed", strict, &audiciav1alpha1.LimitsStatus{Applied: old, Pending: &strict, PendingSince: since(25 * time.Hour)}, drops, strict, nil},
+		{
+			"retention policy change starts grace period",
+			withPolicies,
+			&audiciav1alpha1.LimitsStatus{Applied: strict},
+			drops, strict, since(0),
+		},
+		{
+			"edited pending change restarts grace period",
+			strict,
+			&audiciav1alpha1.LimitsStatus{Applied: old, Pending: &audiciav1alpha1.LimitsConfig{MaxRulesPerReport: 100, RetentionDays: 30}, PendingSince: since(25 * time.Hour)},
+			drops, old, since(0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextLimitsStatus(tt.spec, tt.current, tt.preview, now)
+			if !equality.Semantic.DeepEqual(got.Applied, tt.wantApplied) {
+				t.Errorf("applied = %+v, want %+v", got.Applied, tt.wantApplied)
+			}
+			if (got.PendingSince == nil) != (tt.wantSince == nil) || (got.PendingSince != nil && !got.PendingSince.Equal(tt.wantSince)) {
+				t.Errorf("pendingSince = %v, want %v", got.PendingSince, tt.wantSince)
+			}
+			if tt.wantSince != nil && (got.Pending == nil || !equality.Semantic.DeepEqual(*got.Pending, tt.spec) || got.PendingDroppedRules != 12) {
+				t.Errorf("pending = %+v, dropped = %d", got.Pending, got.PendingDroppedRules)
+			}
+		})
+	}
+}
+
+func TestResolveLimits_GracePeriod(t *testing.T) {
+	old := audiciav1alpha1.LimitsConfig{MaxRulesPerReport: 200, RetentionDays: 30}
+	source := &audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "limits-source", Namespace: "default"},
+		Status:     audiciav1alpha1.AudiciaSourceStatus{Limits: &audiciav1alpha1.LimitsStatus{Applied: old}},
+	}
+	r := newTestReconciler(source)
+	key := types.NamespacedName{Name: "limits-source", Namespace: "default"}
+
+	agg := aggregator.New()
+	now := time.Now()
+	agg.Add(normalizer.CanonicalRule{Resource: "pods", Verb: "get", Namespace: "default"}, now)
+	agg.Add(normalizer.CanonicalRule{Resource: "secrets", Verb: "get", Namespace: "default"}, now.Add(-10*24*time.Hour))
+	aggregators := map[subjectKey]*aggregator.Aggregator{{kind: audiciav1alpha1.SubjectKindServiceAccount, namespace: "default", name: "app"}: agg}
+
+	spec := audiciav1alpha1.LimitsConfig{MaxRulesPerReport: 200, RetentionDays: 7, GracePeriodHours: 24}
+	if got := r.resolveLimits(context.Background(), key, spec, aggregators); !equality.Semantic.DeepEqual(got, old) {
+		t.Fatalf("limits in force = %+v, want previous %+v", got, old)
+	}
+
+	var updated audiciav1alpha1.AudiciaSource
+	if err := r.Get(context.Background(), key, &updated); err != nil {
+		t.Fatal(err)
+	}
+	st := updated.Status.Limits
+	if st.Pending == nil || st.PendingDroppedRules != 1 || st.PendingAffectedSubjects != 1 {
+		t.Errorf("status.limits = %+v, want one pending dropped rule", st)
+	}
+	if !meta.IsStatusConditionTrue(updated.Status.Conditions, "LimitsChangePending") {
+		t.Error("expected LimitsChangePending=True")
+	}
+
+	// Without the grace period the change applies and the condition clears.
+	spec.GracePeriodHours = 0
+	if got := r.resolveLimits(context.Background(), key, spec, aggregators); !equality.Semantic.DeepEqual(got, spec) {
+		t.Fatalf("limits in force = %+v, want spec %+v", got, spec)
+	}
+	if err := r.Get(context.Background(), key, &updated); err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status.Limits.Pending != nil || !meta.IsStatusConditionFalse(updated.Status.Conditions, "LimitsChangePending") {
+		t.Errorf("expected change applied, got status.limits = %+v", updated.Status.Limits)
+	}
+}
@@ -0,0 +1,84 @@
+package audiciasource
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func TestRunLoadTest(t *testing.T) {
+	var out bytes.Buffer
+	res, err := RunLoadTest(context.Background(), LoadTestOptions{
+		Rate:           200,
+		Duration:       3500 * time.Millisecond,
+		Warmup:         500 * time.Millisecond,
+		SampleInterval: 500 * time.Millisecond,
+		Subjects:       10,
+		Namespaces:     3,
+		FlushInterval:  time.Second,
+	}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TargetRate != 200 || res.AchievedRate <= 0 {
+		t.Errorf("rates = %.0f of %.0f", res.AchievedRate, res.TargetRate)
+	}
+	if res.Flushes == 0 || res.FlushMean <= 0 {
+		t.Errorf("flushes = %d, mean %s; want flushes after the warmup", res.Flushes, res.FlushMean)
+	}
+	if res.PeakHeapBytes == 0 {
+		t.Error("peak heap not sampled")
+	}
+	if !strings.Contains(out.String(), "steady state:") {
+		t.Errorf("output lacks the summary:\n%s", out.String())
+	}
+}
+
+func TestRunLoadTest_RejectsInvalidOptions(t *testing.T) {
+	_, err := RunLoadTest(context.Background(), LoadTestOptions{
+		Rate: 100, Duration: time.Second, Warmup: 2 * time.Second, Subjects: 1, Namespaces: 1, FlushInterval: time.Second,
+	}, &bytes.Buffer{})
+	if err == nil {
+		t.Error("warmup longer than the duration was accepted")
+	}
+}
+
+func TestMemoryStore_KeepsReports(t *testing.T) {
+	store := &memoryStore{objects: make(map[memoryKey][]byte)}
+	c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithInterceptorFuncs(store.funcs()).Build()
+	r := &Reconciler{Client: c}
+	ctx := context.Background()
+
+	report := &audiciav1alpha1.AudiciaReport{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "report-builder"}}
+	if err := c.Create(ctx, report); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Create(ctx, report.DeepCopy()); !apierrors.IsAlreadyExists(err) {
+		t.Errorf("second create: err = %v, want AlreadyExists", err)
+	}
+	report.Status.EventsProcessed = 42
+	if err := r.applyReportStatus(ctx, report); err != nil {
+		t.Fatal(err)
+	}
+
+	var got audiciav1alpha1.AudiciaReport
+	if err := c.Get(ctx, client.ObjectKeyFromObject(report), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.EventsProcessed != 42 || got.ResourceVersion != "2" {
+		t.Errorf("stored report: events %d, resourceVersion %q", got.Status.EventsProcessed, got.ResourceVersion)
+	}
+	missing := &audiciav1alpha1.AudiciaPolicy{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "team-a", Name: "missing"}, missing); !apierrors.IsNotFound(err) {
+		t.Errorf("missing policy: err = %v, want NotFound", err)
+	}
+}
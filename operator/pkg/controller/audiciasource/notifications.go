@@ -0,0 +1,32 @@
+package audiciasource
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/notify"
+)
+
+// notifyReportWebhooks sends a material change of report, whose status was
+// before until this flush, to the webhooks of the source. A report created
+// by the flush always counts as changed.
+func (r *Reconciler) notifyReportWebhooks(source audiciav1alpha1.AudiciaSource, report *audiciav1alpha1.AudiciaReport, before *audiciav1alpha1.AudiciaReportStatus, created bool) {
+	n := source.Spec.Notifications
+	if n == nil || len(n.Webhooks) == 0 || before == nil {
+		return
+	}
+	diff, changed := notify.DiffReport(before, &report.Status)
+	if !changed && !created {
+		return
+	}
+	r.ReportHooks.Notify(types.NamespacedName{Namespace: source.Namespace, Name: source.Name}, n.Webhooks, notify.ReportUpdate{
+		Namespace: report.Namespace,
+		Report:    report.Name,
+		Subject:   report.Spec.Subject,
+		Created:   created,
+		Diff:      diff,
+		Time:      time.Now(),
+	}, report)
+}
@@ -0,0 +1,117 @@
+package audiciasource
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+	"github.com/felixnotka/audicia/operator/pkg/notify"
+	"github.com/felixnotka/audicia/operator/pkg/strategy"
+)
+
+func TestFlushSubject_NotifiesReportWebhooks(t *testing.T) {
+	updates := make(chan notify.ReportUpdate, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var update notify.ReportUpdate
+		_ = json.NewDecoder(r.Body).Decode(&update)
+		updates <- update
+	}))
+	defer srv.Close()
+
+	source := audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "audit", Namespace: "default", UID: "audit"},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{Notifications: &audiciav1alpha1.SourceNotifications{
+			Webhooks: []audiciav1alpha1.ReportWebhook{{Name: "cmdb", URL: srv.URL, Payload: audiciav1alpha1.ReportWebhookPayloadDiff}},
+		}},
+	}
+	r := newTestReconciler(&source)
+	r.ReportHooks = notify.NewReportDispatcher(t.TempDir())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = r.ReportHooks.Start(ctx) }()
+
+	engine := strategy.NewEngine(audiciav1alpha1.PolicyStrategy{})
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "alice"}
+	agg := aggregator.New()
+	flush := func(resource string) {
+		t.Helper()
+		agg.Add(normalizer.CanonicalRule{Resource: resource, Verb: "get", Namespace: "default"}, time.Now())
+		if err := r.flushSubject(ctx, source, engine, subject, agg, true, logr.Discard()); err != nil {
+			t.Fatalf("flushSubject: %v", err)
+		}
+	}
+	next := func() *notify.ReportUpdate {
+		select {
+		case u := <-updates:
+			return &u
+		case <-time.After(500 * time.Millisecond):
+			return nil
+		}
+	}
+
+	flush("pods")
+	if u := next(); u == nil || !u.Created || len(u.Diff.AddedRules) != 1 {
+		t.Fatalf("first flush: update = %+v, want the created report", u)
+	}
+
+	// Observing a known rule again is not a material change.
+	flush("pods")
+	if u := next(); u != nil {
+		t.Fatalf("repeated rule: unexpected update %+v", u)
+	}
+
+	flush("configmaps")
+	u := next()
+	if u == nil || u.Created || len(u.Diff.AddedRules) != 1 || u.Diff.AddedRules[0].Resources[0] != "configmaps" {
+		t.Fatalf("new rule: update = %+v, want configmaps added", u)
+	}
+	if u.Object != nil {
+		t.Error("Diff payload carries the report")
+	}
+}
+
+func TestProcessSelfTest_SkipsReportWebhooks(t *testing.T) {
+	updates := make(chan notify.ReportUpdate, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var update notify.ReportUpdate
+		_ = json.NewDecoder(r.Body).Decode(&update)
+		updates <- update
+	}))
+	defer srv.Close()
+
+	source := audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "audit", Namespace: "default", UID: "audit"},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			SourceType: audiciav1alpha1.SourceTypeK8sAuditLog,
+			Notifications: &audiciav1alpha1.SourceNotifications{
+				Webhooks: []audiciav1alpha1.ReportWebhook{{Name: "cmdb", URL: srv.URL, Payload: audiciav1alpha1.ReportWebhookPayloadDiff}},
+			},
+		},
+	}
+	r := newTestReconciler(&source)
+	r.ReportHooks = notify.NewReportDispatcher(t.TempDir())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = r.ReportHooks.Start(ctx) }()
+
+	key := types.NamespacedName{Name: "audit", Namespace: "default"}
+	engine := strategy.NewEngine(audiciav1alpha1.PolicyStrategy{})
+	if err := r.processSelfTest(ctx, key, source, engine, selfTestEvents("default", time.Now())); err != nil {
+		t.Fatalf("processSelfTest: %v", err)
+	}
+	select {
+	case u := <-updates:
+		t.Errorf("self-test report sent to the webhook: %+v", u)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
@@ -0,0 +1,163 @@
+package audiciasource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/filter"
+)
+
+// isProvisioningEvent reports whether an audit event records the successful
+// creation of a Namespace or ServiceAccount, after which new subjects may
+// need placeholder reports.
+func isProvisioningEvent(event auditv1.Event) bool {
+	if event.Verb != "create" || event.ObjectRef == nil {
+		return false
+	}
+	ref := event.ObjectRef
+	// Subresources such as serviceaccounts/token (TokenRequest) don't create objects.
+	if ref.APIGroup != "" || ref.Subresource != "" {
+		return false
+	}
+	if event.ResponseStatus != nil && event.ResponseStatus.Code >= 300 {
+		return false
+	}
+	return ref.Resource == "namespaces" || ref.Resource == "serviceaccounts"
+}
+
+// ensurePendingReports creates placeholder reports for ServiceAccounts in the
+// selected namespaces that have neither been observed nor have a report yet.
+// It returns the number of placeholders created.
+func (r *Reconciler) ensurePendingReports(
+	ctx context.Context,
+	source audiciav1alpha1.AudiciaSource,
+	filterChain *filter.Chain,
+	subjects map[subjectKey]audiciav1alpha1.Subject,
+) (int, error) {
+	selector := labels.Everything()
+	if sel := source.Spec.PendingReports.NamespaceSelector; sel != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(sel); err != nil {
+			return 0, fmt.Errorf("invalid namespaceSelector: %w", err)
+		}
+	}
+
+	var namespaces corev1.NamespaceList
+	if err := r.List(ctx, &namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return 0, fmt.Errorf("listing namespaces: %w", err)
+	}
+
+	created := 0
+	for _, ns := range namespaces.Items {
+		if ns.Status.Phase == corev1.NamespaceTerminating {
+			continue
+		}
+
+		var serviceAccounts corev1.ServiceAccountList
+		if err := r.List(ctx, &serviceAccounts, client.InNamespace(ns.Name)); err != nil {
+			return created, fmt.Errorf("listing serviceaccounts in %s: %w", ns.Name, err)
+		}
+
+		for _, sa := range serviceAccounts.Items {
+			subject := audiciav1alpha1.Subject{
+				Kind:      audiciav1alpha1.SubjectKindServiceAccount,
+				Namespace: sa.Namespace,
+				Name:      sa.Name,
+			}
+			if _, observed := subjects[keyFor(subject)]; observed {
+				continue
+			}
+			username := fmt.Sprintf("system:serviceaccount:%s:%s", sa.Namespace, sa.Name)
+			if !filterChain.Allow(username, sa.Namespace) {
+				continue
+			}
+
+			ok, err := r.createPendingReport(ctx, source, subject)
+			if err != nil {
+				return created, err
+			}
+			if ok {
+				created++
+			}
+		}
+	}
+	return created, nil
+}
+
+// createPendingReport creates an empty report with a NoActivityObserved
+// condition. Existing reports are left untouched. Returns true if a report
+// was created.
+func (r *Reconciler) createPendingReport(
+	ctx context.Context,
+	source audiciav1alpha1.AudiciaSource,
+	subject audiciav1alpha1.Subject,
+) (bool, error) {
+	reportNamespace := reportNamespaceFor(source, subject)
+	report := &audiciav1alpha1.AudiciaReport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      reportNameFor(subject),
+			Namespace: reportNamespace,
+		},
+	}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(report), report)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.IsNotFound(err) {
+		return false, fmt.Errorf("get report %s: %w", report.Name, err)
+	}
+
+	if err := r.applyReportSpec(source, report, subject, reportNamespace); err != nil {
+		return false, err
+	}
+	if err := r.Create(ctx, report); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("create pending report %s: %w", report.Name, err)
+	}
+
+	meta.SetStatusCondition(&report.Status.Conditions, metav1.Condition{
+		Type:    "NoActivityObserved",
+		Status:  metav1.ConditionTrue,
+		Reason:  "AwaitingActivity",
+		Message: fmt.Sprintf("No audit activity observed for %s/%s yet.", subject.Namespace, subject.Name),
+	})
+	if err := r.Status().Update(ctx, report); err != nil {
+		return true, fmt.Errorf("update pending report %s status: %w", report.Name, err)
+	}
+	return true, nil
+}
+
+// sweepPendingReports runs ensurePendingReports and reports whether the sweep
+// completed. Failed sweeps are retried on the next tick.
+func (r *Reconciler) sweepPendingReports(
+	ctx context.Context,
+	source audiciav1alpha1.AudiciaSource,
+	filterChain *filter.Chain,
+	subjects map[subjectKey]audiciav1alpha1.Subject,
+	logger logr.Logger,
+) bool {
+	created, err := r.ensurePendingReports(ctx, source, filterChain, subjects)
+	if created > 0 {
+		logger.Info("created pending reports", "count", created)
+		r.Recorder.Eventf(&source, nil, corev1.EventTypeNormal, "PendingReportsCreated", "Create",
+			"Created %d placeholder reports for unobserved ServiceAccounts", created)
+	}
+	if err != nil {
+		logger.Error(err, "failed to create pending reports")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,188 @@
+package audiciasource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/filter"
+)
+
+func TestIsProvisioningEvent(t *testing.T) {
+	tests := []struct {
+		name  string
+		event auditv1.Event
+		want  bool
+	}{
+		{
+			name:  "namespace created",
+			event: auditv1.Event{Verb: "create", ObjectRef: &auditv1.ObjectReference{Resource: "namespaces"}},
+			want:  true,
+		},
+		{
+			name:  "serviceaccount created",
+			event: auditv1.Event{Verb: "create", ObjectRef: &auditv1.ObjectReference{Resource: "serviceaccounts", Namespace: "a"}},
+			want:  true,
+		},
+		{
+			name:  "token request is not a creation",
+			event: auditv1.Event{Verb: "create", ObjectRef: &auditv1.ObjectReference{Resource: "serviceaccounts", Subresource: "token"}},
+			want:  false,
+		},
+		{
+			name: "failed creation",
+			event: auditv1.Event{
+				Verb:           "create",
+				ObjectRef:      &auditv1.ObjectReference{Resource: "namespaces"},
+				ResponseStatus: &metav1.Status{Code: 409},
+			},
+			want: false,
+		},
+		{
+			name:  "other verb",
+			event: auditv1.Event{Verb: "get", ObjectRef: &auditv1.ObjectReference{Resource: "namespaces"}},
+			want:  false,
+		},
+		{
+			name:  "non-core group",
+			event: auditv1.Event{Verb: "create", ObjectRef: &auditv1.ObjectReference{APIGroup: "example.io", Resource: "namespaces"}},
+			want:  false,
+		},
+		{
+			name:  "no objectRef",
+			event: auditv1.Event{Verb: "create"},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isProvisioningEvent(tt.event); got != tt.want {
+				t.Errorf("isProvisioningEvent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func pendingTestObjects() (*audiciav1alpha1.AudiciaSource, []*corev1.Namespace, []*corev1.ServiceAccount) {
+	source := &audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "src", Namespace: "audicia-system"},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			PendingReports: &audiciav1alpha1.PendingReportsConfig{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"audicia": "watch"}},
+			},
+		},
+	}
+	namespaces := []*corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"audicia": "watch"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+	}
+	sas := []*corev1.ServiceAccount{
+		{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "team-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ignored", Namespace: "team-b"}},
+	}
+	return source, namespaces, sas
+}
+
+func TestEnsurePendingReports(t *testing.T) {
+	source, namespaces, sas := pendingTestObjects()
+	r := newTestReconciler(source, namespaces[0], namespaces[1], sas[0], sas[1], sas[2])
+	chain, _ := filter.NewChain(nil)
+
+	// "worker" has already been observed and will get a real report.
+	observed := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Namespace: "team-a", Name: "worker"}
+	subjects := map[subjectKey]audiciav1alpha1.Subject{keyFor(observed): observed}
+
+	created, err := r.ensurePendingReports(context.Background(), *source, chain, subjects)
+	if err != nil {
+		t.Fatalf("ensurePendingReports() error = %v", err)
+	}
+	if created != 1 {
+		t.Fatalf("expected 1 placeholder, got %d", created)
+	}
+
+	var report audiciav1alpha1.AudiciaReport
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "report-api", Namespace: "team-a"}, &report); err != nil {
+		t.Fatalf("get placeholder report: %v", err)
+	}
+	if !meta.IsStatusConditionTrue(report.Status.Conditions, "NoActivityObserved") {
+		t.Errorf("expected NoActivityObserved=True, got %+v", report.Status.Conditions)
+	}
+	if report.Spec.Subject.Name != "api" || report.Spec.Subject.Kind != audiciav1alpha1.SubjectKindServiceAccount {
+		t.Errorf("unexpected subject %+v", report.Spec.Subject)
+	}
+
+	var list audiciav1alpha1.AudiciaReportList
+	if err := r.List(context.Background(), &list); err != nil {
+		t.Fatalf("list reports: %v", err)
+	}
+	if len(list.Items) != 1 {
+		t.Errorf("expected only the selected, unobserved SA to get a report, got %d", len(list.Items))
+	}
+
+	// A second sweep is idempotent.
+	created, err = r.ensurePendingReports(context.Background(), *source, chain, subjects)
+	if err != nil || created != 0 {
+		t.Errorf("second sweep: created=%d err=%v, want 0, nil", created, err)
+	}
+}
+
+func TestEnsurePendingReports_RespectsFilters(t *testing.T) {
+	source, namespaces, sas := pendingTestObjects()
+	r := newTestReconciler(source, namespaces[0], sas[0])
+	chain, err := filter.NewChain([]audiciav1alpha1.Filter{
+		{Action: audiciav1alpha1.FilterActionDeny, UserPattern: "^system:serviceaccount:team-a:api$"},
+	})
+	if err != nil {
+		t.Fatalf("NewChain: %v", err)
+	}
+
+	created, err := r.ensurePendingReports(context.Background(), *source, chain, nil)
+	if err != nil || created != 0 {
+		t.Errorf("created=%d err=%v, want filtered SA to be skipped", created, err)
+	}
+}
+
+func TestEnsurePendingReports_NilSelectorMatchesAll(t *testing.T) {
+	source, namespaces, sas := pendingTestObjects()
+	source.Spec.PendingReports.NamespaceSelector = nil
+	r := newTestReconciler(source, namespaces[0], namespaces[1], sas[0], sas[2])
+	chain, _ := filter.NewChain(nil)
+
+	created, err := r.ensurePendingReports(context.Background(), *source, chain, nil)
+	if err != nil || created != 2 {
+		t.Errorf("created=%d err=%v, want 2 placeholders", created, err)
+	}
+}
+
+func TestFlushReport_ResolvesPendingPlaceholder(t *testing.T) {
+	source, namespaces, sas := pendingTestObjects()
+	r := newTestReconciler(source, namespaces[0], sas[0])
+	chain, _ := filter.NewChain(nil)
+	if _, err := r.ensurePendingReports(context.Background(), *source, chain, nil); err != nil {
+		t.Fatalf("ensurePendingReports() error = %v", err)
+	}
+
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Namespace: "team-a", Name: "api"}
+	rules := []audiciav1alpha1.ObservedRule{makeObservedRule("pods", "get", "team-a", time.Now())}
+	if err := r.flushReport(context.Background(), *source, subject, rules, nil, nil, 1, true, logr.Discard()); err != nil {
+		t.Fatalf("flushReport() error = %v", err)
+	}
+
+	var report audiciav1alpha1.AudiciaReport
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "report-api", Namespace: "team-a"}, &report); err != nil {
+		t.Fatalf("get report: %v", err)
+	}
+	if !meta.IsStatusConditionFalse(report.Status.Conditions, "NoActivityObserved") {
+		t.Errorf("expected NoActivityObserved=False after activity, got %+v", report.Status.Conditions)
+	}
+}
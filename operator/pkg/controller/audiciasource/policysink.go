@@ -0,0 +1,234 @@
+package audiciasource
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+	"github.com/felixnotka/audicia/operator/pkg/policysink"
+)
+
+const (
+	// policySinkWorkDir holds the working copies of Git sinks. The Helm chart
+	// mounts an emptyDir there, as the root filesystem is read-only.
+	policySinkWorkDir = "/var/lib/audicia/policysink"
+
+	// policySinkTimeout bounds one publication, so an unreachable repository
+	// does not hold up the next one.
+	policySinkTimeout = 2 * time.Minute
+
+	// policySinkCondition reports whether the last publication succeeded.
+	policySinkCondition = "PolicySinkSynced"
+)
+
+// newPolicySink returns the Git sink of spec.policySink, or nil if unset.
+// The operator's Git credentials are only sent to credentialURLs.
+func newPolicySink(source audiciav1alpha1.AudiciaSource, credentialURLs []string) *policysink.Git {
+	cfg := source.Spec.PolicySink
+	if cfg == nil || cfg.Git == nil {
+		return nil
+	}
+	g := &policysink.Git{
+		URL:    cfg.Git.URL,
+		Branch: cmp.Or(cfg.Git.Branch, "main"),
+		Path:   cmp.Or(cfg.Git.Path, path.Join("audicia", source.Namespace, source.Name)),
+		Dir:    filepath.Join(policySinkWorkDir, source.Namespace, source.Name),
+	}
+	if cfg.Git.SecretName != "" {
+		g.CredentialsDir = policysink.CredentialsMountPath
+		g.CredentialURLs = credentialURLs
+	}
+	return g
+}
+
+// policyPublisher publishes the policies of a source to its sink off the
+// pipeline goroutine, so a slow or unreachable repository does not stall
+// event processing. Publications run one at a time; one requested while
+// another runs follows it. Errors are recorded in the source's history. A
+// nil *policyPublisher publishes nothing.
+type policyPublisher struct {
+	r       *Reconciler
+	key     types.NamespacedName
+	sink    *policysink.Git
+	history *errorHistory
+
+	mu      sync.Mutex
+	running bool
+	pending bool
+	wg      sync.WaitGroup
+}
+
+// newPolicyPublisher returns nil when sink is nil.
+func (r *Reconciler) newPolicyPublisher(key types.NamespacedName, sink *policysink.Git, history *errorHistory) *policyPublisher {
+	if sink == nil {
+		return nil
+	}
+	return &policyPublisher{r: r, key: key, sink: sink, history: history}
+}
+
+// publish starts a publication, or, while one runs, another after it.
+func (p *policyPublisher) publish(ctx context.Context) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.running {
+		p.pending = true
+		return
+	}
+	p.running = true
+	p.wg.Go(func() { p.run(ctx) })
+}
+
+// run publishes until no further publication was requested.
+func (p *policyPublisher) run(ctx context.Context) {
+	for {
+		p.history.record(audiciav1alpha1.PipelineErrorPolicySink, p.r.publishPolicies(ctx, p.key, p.sink), time.Now())
+		p.mu.Lock()
+		if !p.pending || ctx.Err() != nil {
+			p.running, p.pending = false, false
+			p.mu.Unlock()
+			return
+		}
+		p.pending = false
+		p.mu.Unlock()
+	}
+}
+
+// wait blocks until the running publication finished, so the working copy
+// is not shared with the pipeline that replaces this one.
+func (p *policyPublisher) wait() {
+	if p != nil {
+		p.wg.Wait()
+	}
+}
+
+// policyFiles returns the manifests of the policies derived from the
+// reports source writes, one file per policy at <namespace>/<policy>.yaml.
+func (r *Reconciler) policyFiles(ctx context.Context, key types.NamespacedName) (map[string][]byte, error) {
+	var reports audiciav1alpha1.AudiciaReportList
+	if err := r.List(ctx, &reports); err != nil {
+		return nil, fmt.Errorf("listing reports: %w", err)
+	}
+	files := make(map[string][]byte)
+	for i := range reports.Items {
+		report := &reports.Items[i]
+		if !writtenBy(report, key) {
+			continue
+		}
+		var policy audiciav1alpha1.AudiciaPolicy
+		policyKey := types.NamespacedName{Namespace: report.Namespace, Name: policyNameFor(report.Spec.Subject)}
+		if err := r.Get(ctx, policyKey, &policy); err != nil {
+			if errors.IsNotFound(err) {
+				// Break-glass identities have no policy.
+				continue
+			}
+			return nil, fmt.Errorf("reading policy %s: %w", policyKey, err)
+		}
+		if len(policy.Spec.Manifests) == 0 {
+			continue
+		}
+		files[policy.Namespace+"/"+policy.Name+".yaml"] = []byte(renderPolicyFile(policy))
+	}
+	return files, nil
+}
+
+// renderPolicyFile joins a policy's manifests into a multi-document YAML
+// file, headed by a comment identifying the subject. The policy state is
+// left out: it changes without the manifests changing.
+func renderPolicyFile(p audiciav1alpha1.AudiciaPolicy) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Suggested by Audicia for %s %s (AudiciaPolicy %s/%s)\n",
+		p.Spec.Subject.Kind, p.Spec.Subject.Name, p.Namespace, p.Name)
+	for _, m := range p.Spec.Manifests {
+		b.WriteString("---\n")
+		b.WriteString(m)
+		if !strings.HasSuffix(m, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// publishPolicies pushes the source's policies to its sink and records the
+// outcome in status.policySink and the PolicySinkSynced condition. The sink
+// skips the push when the policies did not change since its last one. It
+// returns the error of a failed push.
+func (r *Reconciler) publishPolicies(ctx context.Context, key types.NamespacedName, sink *policysink.Git) error {
+	if sink == nil {
+		return nil
+	}
+	logger := ctrl.Log.WithName("pipeline").WithValues("source", key)
+	ctx, cancel := context.WithTimeout(ctx, policySinkTimeout)
+	defer cancel()
+
+	files, syncErr := r.policyFiles(ctx, key)
+	commit := ""
+	if syncErr == nil {
+		commit, syncErr = sink.Sync(ctx, files, fmt.Sprintf("Update Audicia policies of %s (%d policies)", key, len(files)))
+	}
+
+	condition := metav1.Condition{
+		Type:    policySinkCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Synced",
+		Message: fmt.Sprintf("%d policies published to %s.", len(files), sink.URL),
+	}
+	if syncErr != nil {
+		logger.Error(syncErr, "failed to publish policies", "url", sink.URL)
+		metrics.PolicySinkErrorsTotal.Inc()
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SyncFailed"
+		condition.Message = syncErr.Error()
+	} else if commit != "" {
+		logger.Info("policies published", "url", sink.URL, "branch", sink.Branch, "commit", commit)
+		metrics.PolicySinkCommitsTotal.Inc()
+	}
+
+	var source audiciav1alpha1.AudiciaSource
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := r.Get(ctx, key, &source); err != nil {
+			return err
+		}
+		if syncErr == nil {
+			status := source.Status.PolicySink
+			if status == nil {
+				status = &audiciav1alpha1.PolicySinkStatus{}
+			}
+			status.LastSyncTime = metav1.Now()
+			status.LastCommit = cmp.Or(commit, status.LastCommit)
+			status.Policies = int32(len(files))
+			source.Status.PolicySink = status
+		}
+		condition.ObservedGeneration = source.Generation
+		meta.SetStatusCondition(&source.Status.Conditions, condition)
+		return r.Status().Update(ctx, &source)
+	})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error(err, "failed to record policy sink status")
+		}
+		return syncErr
+	}
+	if syncErr != nil {
+		r.Recorder.Eventf(&source, nil, corev1.EventTypeWarning, "PolicySinkFailed", "Publish",
+			"Failed to publish policies to %s: %v", sink.URL, syncErr)
+	}
+	return syncErr
+}
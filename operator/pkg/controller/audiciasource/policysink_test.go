@@ -0,0 +1,157 @@
+package audiciasource
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+	"github.com/felixnotka/audicia/operator/pkg/policysink"
+	"github.com/felixnotka/audicia/operator/pkg/strategy"
+)
+
+func TestNewPolicySink_Defaults(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{ObjectMeta: metav1.ObjectMeta{Name: "audit", Namespace: "prod"}}
+	if newPolicySink(source, nil) != nil {
+		t.Fatal("expected no sink without spec.policySink")
+	}
+	source.Spec.PolicySink = &audiciav1alpha1.PolicySinkConfig{Git: &audiciav1alpha1.GitSinkConfig{
+		URL:        "https://git.example.com/rbac.git",
+		SecretName: "git-creds",
+	}}
+	allowed := []string{"https://git.example.com/rbac.git"}
+	g := newPolicySink(source, allowed)
+	if g.Branch != "main" || g.Path != "audicia/prod/audit" || g.CredentialsDir != policysink.CredentialsMountPath {
+		t.Errorf("sink = %+v", g)
+	}
+	if !slices.Equal(g.CredentialURLs, allowed) {
+		t.Errorf("credential URLs = %v, want %v", g.CredentialURLs, allowed)
+	}
+}
+
+func TestPublishPolicies(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "init", "--quiet", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+
+	source := audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "audit", Namespace: "default", UID: "audit"},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			PolicySink: &audiciav1alpha1.PolicySinkConfig{Git: &audiciav1alpha1.GitSinkConfig{URL: remote}},
+		},
+	}
+	key := types.NamespacedName{Namespace: "default", Name: "audit"}
+	r := newTestReconciler(&source)
+	engine := strategy.NewEngine(audiciav1alpha1.PolicyStrategy{})
+	ctx := context.Background()
+
+	for _, name := range []string{"alice", "bob"} {
+		agg := aggregator.New()
+		agg.Add(normalizer.CanonicalRule{Resource: "pods", Verb: "get", Namespace: "default"}, time.Now())
+		subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: name}
+		if err := r.flushSubject(ctx, source, engine, subject, agg, true, logr.Discard()); err != nil {
+			t.Fatalf("flushSubject: %v", err)
+		}
+	}
+
+	sink := newPolicySink(source, nil)
+	sink.Dir = t.TempDir()
+	r.publishPolicies(ctx, key, sink)
+
+	out, err := exec.Command("git", "-C", remote, "ls-tree", "-r", "--name-only", "main").Output()
+	if err != nil {
+		t.Fatalf("git ls-tree: %v", err)
+	}
+	want := []string{
+		"audicia/default/audit/default/policy-alice.yaml",
+		"audicia/default/audit/default/policy-bob.yaml",
+	}
+	if got := strings.Fields(string(out)); !slices.Equal(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+	doc, err := exec.Command("git", "-C", remote, "show", "main:"+want[0]).Output()
+	if err != nil {
+		t.Fatalf("git show: %v", err)
+	}
+	if !strings.Contains(string(doc), "kind: Role") || !strings.Contains(string(doc), "name: alice") {
+		t.Errorf("unexpected manifest:\n%s", doc)
+	}
+
+	var got audiciav1alpha1.AudiciaSource
+	if err := r.Get(ctx, key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.PolicySink == nil || got.Status.PolicySink.LastCommit == "" || got.Status.PolicySink.Policies != 2 {
+		t.Errorf("status.policySink = %+v", got.Status.PolicySink)
+	}
+	if !meta.IsStatusConditionTrue(got.Status.Conditions, policySinkCondition) {
+		t.Errorf("expected %s=True, got %v", policySinkCondition, got.Status.Conditions)
+	}
+}
+
+func TestPublishPolicies_Failure(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{ObjectMeta: metav1.ObjectMeta{Name: "audit", Namespace: "default"}}
+	key := types.NamespacedName{Namespace: "default", Name: "audit"}
+	r := newTestReconciler(&source)
+
+	sink := &policysink.Git{URL: "unused", Branch: "main", Path: "../outside", Dir: t.TempDir()}
+	r.publishPolicies(context.Background(), key, sink)
+
+	var got audiciav1alpha1.AudiciaSource
+	if err := r.Get(context.Background(), key, &got); err != nil {
+		t.Fatal(err)
+	}
+	cond := meta.FindStatusCondition(got.Status.Conditions, policySinkCondition)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "SyncFailed" {
+		t.Errorf("condition = %+v", cond)
+	}
+	if got.Status.PolicySink != nil {
+		t.Errorf("expected no sync status after a failure, got %+v", got.Status.PolicySink)
+	}
+}
+
+func TestPolicyPublisher(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{ObjectMeta: metav1.ObjectMeta{Name: "audit", Namespace: "default"}}
+	key := types.NamespacedName{Namespace: "default", Name: "audit"}
+	r := newTestReconciler(&source)
+	history := newErrorHistory(source)
+
+	var none *policyPublisher
+	none.publish(context.Background())
+	none.wait()
+
+	sink := &policysink.Git{URL: "unused", Branch: "main", Path: "../outside", Dir: t.TempDir()}
+	p := r.newPolicyPublisher(key, sink, history)
+	p.publish(context.Background())
+	p.publish(context.Background())
+	p.wait()
+
+	if p.running || p.pending {
+		t.Errorf("running = %v, pending = %v after wait", p.running, p.pending)
+	}
+	if len(history.entries) != 1 || history.entries[0].Category != audiciav1alpha1.PipelineErrorPolicySink {
+		t.Errorf("history = %+v, want the policy sink error", history.entries)
+	}
+	var got audiciav1alpha1.AudiciaSource
+	if err := r.Get(context.Background(), key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !meta.IsStatusConditionFalse(got.Status.Conditions, policySinkCondition) {
+		t.Errorf("expected %s=False, got %v", policySinkCondition, got.Status.Conditions)
+	}
+}
@@ -0,0 +1,107 @@
+package audiciasource
+
+import (
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/ingestor"
+	"github.com/felixnotka/audicia/operator/pkg/ingestor/cloud"
+)
+
+// provenanceTracker cuts the ingested audit stream into checkpoint ranges,
+// one per flush, and attributes the rules observed in between to them
+// (spec.ruleProvenance). It is owned by a single pipeline goroutine and is
+// not safe for concurrent use.
+type provenanceTracker struct {
+	ing  ingestor.Ingestor
+	from audiciav1alpha1.IngestionPosition
+}
+
+// newProvenanceTracker returns nil unless spec.ruleProvenance is set and
+// the ingestor keeps checkpoints.
+func newProvenanceTracker(source audiciav1alpha1.AudiciaSource, ing ingestor.Ingestor) *provenanceTracker {
+	if !source.Spec.RuleProvenance {
+		return nil
+	}
+	from, ok := ingestionPosition(ing)
+	if !ok {
+		return nil
+	}
+	return &provenanceTracker{ing: ing, from: from}
+}
+
+// attribute closes the current range at the ingestor's position and records
+// it on the rules observed since the previous call.
+func (t *provenanceTracker) attribute(aggregators map[subjectKey]*aggregator.Aggregator) {
+	if t == nil {
+		return
+	}
+	to, _ := ingestionPosition(t.ing)
+	span := checkpointRange(t.from, to)
+	for _, agg := range aggregators {
+		agg.Attribute(span)
+	}
+	t.from = to
+}
+
+// ingestionPosition returns the checkpoint of ing, or false for ingestors
+// without one (Webhook, Local, Forward).
+func ingestionPosition(ing ingestor.Ingestor) (audiciav1alpha1.IngestionPosition, bool) {
+	switch ing := ing.(type) {
+	case *ingestor.FileIngestor:
+		pos := ing.Checkpoint()
+		return audiciav1alpha1.IngestionPosition{FileOffset: pos.FileOffset, Inode: pos.Inode}, true
+	case *ingestor.MultiFileIngestor:
+		return audiciav1alpha1.IngestionPosition{Files: fileCheckpoints(ing.FileCheckpoints())}, true
+	case *cloud.CloudIngestor:
+		return audiciav1alpha1.IngestionPosition{PartitionOffsets: ing.CloudCheckpoint().PartitionOffsets}, true
+	default:
+		return audiciav1alpha1.IngestionPosition{}, false
+	}
+}
+
+// checkpointRange returns the range between two checkpoints. Of glob and
+// cloud sources, only the files and partitions that moved are kept, so the
+// range stays small on sources with many of them.
+func checkpointRange(from, to audiciav1alpha1.IngestionPosition) audiciav1alpha1.CheckpointRange {
+	if to.Files != nil {
+		before := make(map[string]audiciav1alpha1.FileCheckpointStatus, len(from.Files))
+		for _, f := range from.Files {
+			before[f.Path] = f
+		}
+		from.Files, to.Files = nil, movedFiles(before, to.Files)
+		for _, f := range to.Files {
+			if b, ok := before[f.Path]; ok {
+				from.Files = append(from.Files, b)
+			}
+		}
+	}
+	if to.PartitionOffsets != nil {
+		start := make(map[string]string)
+		moved := make(map[string]string)
+		for partition, offset := range to.PartitionOffsets {
+			b, ok := from.PartitionOffsets[partition]
+			if ok && b == offset {
+				continue
+			}
+			if ok {
+				start[partition] = b
+			}
+			moved[partition] = offset
+		}
+		from.PartitionOffsets, to.PartitionOffsets = start, moved
+	}
+	return audiciav1alpha1.CheckpointRange{From: from, To: to}
+}
+
+// movedFiles returns the entries of files whose checkpoint differs from
+// before.
+func movedFiles(before map[string]audiciav1alpha1.FileCheckpointStatus, files []audiciav1alpha1.FileCheckpointStatus) []audiciav1alpha1.FileCheckpointStatus {
+	var moved []audiciav1alpha1.FileCheckpointStatus
+	for _, f := range files {
+		if b, ok := before[f.Path]; ok && b == f {
+			continue
+		}
+		moved = append(moved, f)
+	}
+	return moved
+}
@@ -0,0 +1,78 @@
+package audiciasource
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/ingestor"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+)
+
+func TestCheckpointRange_KeepsMovedEntries(t *testing.T) {
+	from := audiciav1alpha1.IngestionPosition{
+		Files: []audiciav1alpha1.FileCheckpointStatus{
+			{Path: "/var/log/a.log", FileOffset: 100, Inode: 1},
+			{Path: "/var/log/b.log", FileOffset: 50, Inode: 2},
+		},
+		PartitionOffsets: map[string]string{"0": "10", "1": "20"},
+	}
+	to := audiciav1alpha1.IngestionPosition{
+		Files: []audiciav1alpha1.FileCheckpointStatus{
+			{Path: "/var/log/a.log", FileOffset: 100, Inode: 1},
+			{Path: "/var/log/b.log", FileOffset: 80, Inode: 2},
+			{Path: "/var/log/c.log", FileOffset: 30, Inode: 3},
+		},
+		PartitionOffsets: map[string]string{"0": "10", "1": "25", "2": "5"},
+	}
+	span := checkpointRange(from, to)
+
+	if len(span.To.Files) != 2 || span.To.Files[0].Path != "/var/log/b.log" || span.To.Files[1].Path != "/var/log/c.log" {
+		t.Errorf("to.files = %+v", span.To.Files)
+	}
+	if len(span.From.Files) != 1 || span.From.Files[0].FileOffset != 50 {
+		t.Errorf("from.files = %+v", span.From.Files)
+	}
+	if want := map[string]string{"1": "25", "2": "5"}; !maps.Equal(span.To.PartitionOffsets, want) {
+		t.Errorf("to.partitionOffsets = %v, want %v", span.To.PartitionOffsets, want)
+	}
+	if want := map[string]string{"1": "20"}; !maps.Equal(span.From.PartitionOffsets, want) {
+		t.Errorf("from.partitionOffsets = %v, want %v", span.From.PartitionOffsets, want)
+	}
+	// The inputs are not modified.
+	if len(to.Files) != 3 || len(to.PartitionOffsets) != 3 {
+		t.Error("checkpointRange modified its input")
+	}
+}
+
+func TestProvenanceTracker(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{Spec: audiciav1alpha1.AudiciaSourceSpec{RuleProvenance: true}}
+	if newProvenanceTracker(source, ingestor.NewWebhookIngestor(8443, "", "")) != nil {
+		t.Error("expected no tracker for a source without checkpoints")
+	}
+	path := filepath.Join(t.TempDir(), "audit.log")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ing := ingestor.NewFileIngestor(path, ingestor.Position{FileOffset: 100, Inode: 7}, 10)
+	if newProvenanceTracker(audiciav1alpha1.AudiciaSource{}, ing) != nil {
+		t.Error("expected no tracker without spec.ruleProvenance")
+	}
+
+	tracker := newProvenanceTracker(source, ing)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "alice"}
+	aggregate(aggregators, subjects, subject, normalizer.CanonicalRule{Resource: "pods", Verb: "get"}, time.Now(), 1)
+	tracker.attribute(aggregators)
+
+	rules := aggregators[keyFor(subject)].Rules()
+	p := rules[0].Provenance
+	if p == nil || p.FirstSeen == nil || p.FirstSeen.From.FileOffset != 100 || p.LastSeen.To.Inode != 7 {
+		t.Errorf("provenance = %+v", p)
+	}
+}
@@ -0,0 +1,94 @@
+package audiciasource
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// sourceReachableCondition reports whether the last probe of a file
+// source's audit log could read it.
+const sourceReachableCondition = "SourceReachable"
+
+// reachabilityCondition returns the SourceReachable condition for the result
+// of a probe.
+func reachabilityCondition(err error, generation int64) metav1.Condition {
+	cond := metav1.Condition{
+		Type:               sourceReachableCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Readable",
+		Message:            "The audit log exists and is readable.",
+		ObservedGeneration: generation,
+	}
+	if err == nil {
+		return cond
+	}
+	cond.Status = metav1.ConditionFalse
+	cond.Message = err.Error()
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		cond.Reason = "PathNotFound"
+	case errors.Is(err, fs.ErrPermission):
+		cond.Reason = "PermissionDenied"
+	default:
+		cond.Reason = "Unreadable"
+	}
+	return cond
+}
+
+// reachabilityReporter returns the probe callback of the pipeline of key. It
+// writes the SourceReachable condition when the result changes. While the
+// audit log is unreadable, Ready is False with reason SourceUnreachable and a
+// Warning Event is emitted, so a permission problem shows on the CR rather
+// than only in the logs. The file ingestor keeps retrying with backoff; once
+// the file is readable again, Ready returns to True without restarting the
+// pipeline.
+func (r *Reconciler) reachabilityReporter(ctx context.Context, key types.NamespacedName, source audiciav1alpha1.AudiciaSource) func(error) {
+	var (
+		mu   sync.Mutex
+		last *metav1.Condition
+	)
+	return func(err error) {
+		cond := reachabilityCondition(err, source.Generation)
+		mu.Lock()
+		defer mu.Unlock()
+		if last != nil && last.Reason == cond.Reason && last.Message == cond.Message {
+			return
+		}
+		wasReachable := last == nil || last.Status == metav1.ConditionTrue
+		last = &cond
+		r.setSourceCondition(ctx, key, cond)
+
+		switch {
+		case cond.Status == metav1.ConditionFalse:
+			if wasReachable {
+				r.Recorder.Eventf(&source, nil, corev1.EventTypeWarning, "SourceUnreachable", "Probe",
+					"Audit log is not readable (%s): %s", cond.Reason, cond.Message)
+			}
+			r.setSourceCondition(ctx, key, metav1.Condition{
+				Type:               "Ready",
+				Status:             metav1.ConditionFalse,
+				Reason:             "SourceUnreachable",
+				Message:            "The audit log is not readable; retrying with backoff: " + cond.Message,
+				ObservedGeneration: source.Generation,
+			})
+		case !wasReachable:
+			r.Recorder.Eventf(&source, nil, corev1.EventTypeNormal, "SourceRecovered", "Probe",
+				"Audit log is readable again; ingestion resumed.")
+			r.setSourceCondition(ctx, key, metav1.Condition{
+				Type:               "Ready",
+				Status:             metav1.ConditionTrue,
+				Reason:             "PipelineRunning",
+				Message:            "Ingestion pipeline is running.",
+				ObservedGeneration: source.Generation,
+			})
+		}
+	}
+}
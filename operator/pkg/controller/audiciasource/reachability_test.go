@@ -0,0 +1,95 @@
+package audiciasource
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func TestReachabilityCondition(t *testing.T) {
+	tests := []struct {
+		err    error
+		status metav1.ConditionStatus
+		reason string
+	}{
+		{nil, metav1.ConditionTrue, "Readable"},
+		{fmt.Errorf("open /var/log/audit.log: %w", fs.ErrNotExist), metav1.ConditionFalse, "PathNotFound"},
+		{fmt.Errorf("open /var/log/audit.log: %w", fs.ErrPermission), metav1.ConditionFalse, "PermissionDenied"},
+		{fmt.Errorf("/var/log is a directory"), metav1.ConditionFalse, "Unreadable"},
+	}
+	for _, tt := range tests {
+		cond := reachabilityCondition(tt.err, 3)
+		if cond.Status != tt.status || cond.Reason != tt.reason || cond.ObservedGeneration != 3 {
+			t.Errorf("reachabilityCondition(%v) = %s/%s, want %s/%s", tt.err, cond.Status, cond.Reason, tt.status, tt.reason)
+		}
+	}
+}
+
+func TestReachabilityReporter(t *testing.T) {
+	source := &audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "src", Namespace: "default", Generation: 1},
+	}
+	r := newTestReconciler(source)
+	key := types.NamespacedName{Name: "src", Namespace: "default"}
+	report := r.reachabilityReporter(context.Background(), key, *source)
+	recorder := r.Recorder.(*events.FakeRecorder)
+
+	condition := func(condType string) *metav1.Condition {
+		t.Helper()
+		var updated audiciav1alpha1.AudiciaSource
+		if err := r.Get(context.Background(), key, &updated); err != nil {
+			t.Fatal(err)
+		}
+		return meta.FindStatusCondition(updated.Status.Conditions, condType)
+	}
+
+	denied := fmt.Errorf("open /var/log/audit.log: %w", fs.ErrPermission)
+	report(denied)
+	if cond := condition(sourceReachableCondition); cond == nil || cond.Reason != "PermissionDenied" {
+		t.Fatalf("condition = %+v, want PermissionDenied", cond)
+	}
+	if cond := condition("Ready"); cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "SourceUnreachable" {
+		t.Errorf("Ready = %+v, want False/SourceUnreachable", cond)
+	}
+	select {
+	case e := <-recorder.Events:
+		if !strings.Contains(e, "SourceUnreachable") {
+			t.Errorf("event = %q, want SourceUnreachable", e)
+		}
+	default:
+		t.Error("no event emitted")
+	}
+
+	// A repeated failure neither rewrites the condition nor emits an event.
+	report(denied)
+	select {
+	case e := <-recorder.Events:
+		t.Errorf("unexpected event %q", e)
+	default:
+	}
+
+	report(nil)
+	if cond := condition(sourceReachableCondition); cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Errorf("condition = %+v, want True", cond)
+	}
+	if cond := condition("Ready"); cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Errorf("Ready = %+v, want True once the audit log is readable again", cond)
+	}
+	select {
+	case e := <-recorder.Events:
+		if !strings.Contains(e, "SourceRecovered") {
+			t.Errorf("event = %q, want SourceRecovered", e)
+		}
+	default:
+		t.Error("no recovery event emitted")
+	}
+}
@@ -0,0 +1,217 @@
+package audiciasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	crdschema "github.com/felixnotka/audicia/operator/pkg/schema"
+)
+
+// reportCRDCondition reports whether the CRDs the pipeline writes, those of
+// AudiciaReport and AudiciaPolicy, are installed and current.
+const reportCRDCondition = "ReportCRDReady"
+
+const (
+	// reportCRDRecheckInterval is how often a pipeline checks the CRDs
+	// again while they are missing or outdated.
+	reportCRDRecheckInterval = time.Minute
+
+	// maxOutdatedFieldsInMessage limits how many missing schema fields the
+	// SchemaOutdated condition message lists.
+	maxOutdatedFieldsInMessage = 5
+)
+
+// reportKinds are the kinds the pipeline writes.
+var reportKinds = []string{"AudiciaReport", "AudiciaPolicy"}
+
+// reportCRDGate holds back report flushes while the CRDs of the reports are
+// missing or older than the operator, as happens on fresh installs that
+// skipped the CRDs and on upgrades that did not update them. Writes would
+// fail on every flush, or the API server would prune the fields it does not
+// know. The pipeline keeps aggregating in the meantime and neither flushes
+// nor advances its checkpoint, so nothing is lost when the operator restarts
+// before the CRDs are fixed. It is owned by a single pipeline goroutine.
+type reportCRDGate struct {
+	r      *Reconciler
+	key    types.NamespacedName
+	source audiciav1alpha1.AudiciaSource
+
+	// reason is empty while the CRDs are ready.
+	reason    string
+	checkedAt time.Time
+}
+
+// newReportCRDGate checks the CRDs and records the ReportCRDReady condition.
+func (r *Reconciler) newReportCRDGate(ctx context.Context, key types.NamespacedName, source audiciav1alpha1.AudiciaSource) *reportCRDGate {
+	g := &reportCRDGate{r: r, key: key, source: source}
+	g.check(ctx, time.Now(), true)
+	return g
+}
+
+// ready reports whether reports can be written. While they cannot, the CRDs
+// are checked again every reportCRDRecheckInterval.
+func (g *reportCRDGate) ready(ctx context.Context, now time.Time) bool {
+	if g.reason != "" && now.Sub(g.checkedAt) >= reportCRDRecheckInterval {
+		g.check(ctx, now, false)
+	}
+	return g.reason == ""
+}
+
+// check checks the CRDs and records a change of their state in the
+// ReportCRDReady condition and an Event.
+func (g *reportCRDGate) check(ctx context.Context, now time.Time, initial bool) {
+	reason, message := g.r.checkReportCRDs(ctx)
+	g.checkedAt = now
+	if !initial && reason == g.reason {
+		return
+	}
+	wasReady := g.reason == ""
+	g.reason = reason
+
+	cond := metav1.Condition{
+		Type:               reportCRDCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "CRDsCurrent",
+		Message:            "The AudiciaReport and AudiciaPolicy CRDs are installed and current.",
+		ObservedGeneration: g.source.Generation,
+	}
+	if reason != "" {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = reason
+		cond.Message = message + " Reports are held back and the checkpoint is not advanced until the CRDs are fixed."
+	}
+	g.r.setSourceCondition(ctx, g.key, cond)
+
+	switch {
+	case reason != "":
+		ctrl.Log.WithName("pipeline").WithValues("source", g.key).Info("holding back reports", "reason", reason, "message", message)
+		g.r.Recorder.Eventf(&g.source, nil, corev1.EventTypeWarning, reason, "Flush", "%s", cond.Message)
+	case !wasReady:
+		g.r.Recorder.Eventf(&g.source, nil, corev1.EventTypeNormal, "ReportCRDReady", "Flush",
+			"Report CRDs are installed and current; flushing held-back reports.")
+	}
+}
+
+// checkReportCRDs returns ReportCRDMissing when the API server does not
+// serve a report kind, SchemaOutdated when its CRD lacks fields the operator
+// writes, or "" when reports can be written. The schema is only compared
+// when the operator may read the CRD; otherwise the CRD counts as current.
+func (r *Reconciler) checkReportCRDs(ctx context.Context) (reason, message string) {
+	logger := ctrl.Log.WithName("pipeline")
+	lists := map[string]client.ObjectList{
+		"AudiciaReport": &audiciav1alpha1.AudiciaReportList{},
+		"AudiciaPolicy": &audiciav1alpha1.AudiciaPolicyList{},
+	}
+	for _, kind := range reportKinds {
+		if err := r.List(ctx, lists[kind], client.Limit(1)); err != nil {
+			if meta.IsNoMatchError(err) {
+				return "ReportCRDMissing", fmt.Sprintf("The API server does not serve %s %s; install the Audicia CRDs.",
+					audiciav1alpha1.SchemeGroupVersion, kind)
+			}
+			logger.V(1).Info("cannot list reports", "kind", kind, "error", err)
+		}
+	}
+	for _, kind := range reportKinds {
+		missing, err := r.missingCRDFields(ctx, kind)
+		if err != nil {
+			logger.V(1).Info("cannot compare the report CRD schema", "kind", kind, "error", err)
+			continue
+		}
+		if len(missing) > 0 {
+			return "SchemaOutdated", outdatedMessage(kind, missing) + "; upgrade the CRDs."
+		}
+	}
+	return "", ""
+}
+
+// outdatedMessage describes the fields missing from the installed CRD of
+// kind, listing at most maxOutdatedFieldsInMessage of them.
+func outdatedMessage(kind string, missing []string) string {
+	listed := missing[:min(len(missing), maxOutdatedFieldsInMessage)]
+	msg := fmt.Sprintf("The installed %s CRD is older than the operator and lacks %s", kind, strings.Join(listed, ", "))
+	if more := len(missing) - len(listed); more > 0 {
+		msg += fmt.Sprintf(" (and %d more)", more)
+	}
+	return msg
+}
+
+// missingCRDFields returns the paths of the fields of the operator's schema
+// of kind that the installed CRD's schema lacks.
+func (r *Reconciler) missingCRDFields(ctx context.Context, kind string) ([]string, error) {
+	want, err := crdschema.Lookup(kind, audiciav1alpha1.SchemeGroupVersion.Version)
+	if err != nil {
+		return nil, err
+	}
+	return r.missingSchemaFields(ctx, want)
+}
+
+// missingSchemaFields returns the paths of the fields of want that the
+// installed CRD's schema of its version lacks, or the version itself when
+// the CRD does not define it.
+func (r *Reconciler) missingSchemaFields(ctx context.Context, want crdschema.Schema) ([]string, error) {
+	var wantSchema map[string]any
+	if err := json.Unmarshal(want.OpenAPIV3, &wantSchema); err != nil {
+		return nil, err
+	}
+
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"})
+	if err := r.Get(ctx, client.ObjectKey{Name: want.Plural + "." + want.Group}, crd); err != nil {
+		return nil, err
+	}
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		version, _ := v.(map[string]any)
+		if version["name"] != want.Version {
+			continue
+		}
+		haveSchema, _, _ := unstructured.NestedMap(version, "schema", "openAPIV3Schema")
+		var missing []string
+		missingProperties(wantSchema, haveSchema, "", &missing)
+		return missing, nil
+	}
+	return []string{"version " + want.Version}, nil
+}
+
+// missingProperties appends the paths of the properties of want that have
+// lacks, descending into objects and array items. A schema that preserves
+// unknown fields lacks nothing.
+func missingProperties(want, have map[string]any, path string, missing *[]string) {
+	if preserve, _ := have["x-kubernetes-preserve-unknown-fields"].(bool); preserve {
+		return
+	}
+	if wantItems, ok := want["items"].(map[string]any); ok {
+		haveItems, _ := have["items"].(map[string]any)
+		missingProperties(wantItems, haveItems, path+"[]", missing)
+	}
+	wantProps, _ := want["properties"].(map[string]any)
+	haveProps, _ := have["properties"].(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(wantProps)) {
+		field := name
+		if path != "" {
+			field = path + "." + name
+		}
+		haveProp, ok := haveProps[name].(map[string]any)
+		if !ok {
+			*missing = append(*missing, field)
+			continue
+		}
+		wantProp, _ := wantProps[name].(map[string]any)
+		missingProperties(wantProp, haveProp, field, missing)
+	}
+}
@@ -0,0 +1,172 @@
+package audiciasource
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	crdschema "github.com/felixnotka/audicia/operator/pkg/schema"
+)
+
+func TestMissingProperties(t *testing.T) {
+	var want, have map[string]any
+	_ = json.Unmarshal([]byte(`{"properties": {
+		"spec": {"properties": {"subject": {"type": "object"}}},
+		"status": {"properties": {
+			"rules": {"type": "array", "items": {"properties": {"verb": {}, "lastSeen": {}}}},
+			"sources": {"type": "array"},
+			"extra": {"properties": {"a": {}}}
+		}}
+	}}`), &want)
+	_ = json.Unmarshal([]byte(`{"properties": {
+		"spec": {"properties": {"subject": {"type": "object"}}},
+		"status": {"properties": {
+			"rules": {"type": "array", "items": {"properties": {"verb": {}}}},
+			"extra": {"x-kubernetes-preserve-unknown-fields": true}
+		}}
+	}}`), &have)
+
+	var missing []string
+	missingProperties(want, have, "", &missing)
+	if wantMissing := []string{"status.rules[].lastSeen", "status.sources"}; !slices.Equal(missing, wantMissing) {
+		t.Errorf("missing = %v, want %v", missing, wantMissing)
+	}
+}
+
+// installedCRD returns the CRD of kind as the operator ships it, with
+// mutate applied to its v1alpha1 schema.
+func installedCRD(t *testing.T, kind string, mutate func(map[string]any)) *unstructured.Unstructured {
+	t.Helper()
+	s, err := crdschema.Lookup(kind, "v1alpha1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var openAPI map[string]any
+	if err := json.Unmarshal(s.OpenAPIV3, &openAPI); err != nil {
+		t.Fatal(err)
+	}
+	if mutate != nil {
+		mutate(openAPI)
+	}
+	crd := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"group": s.Group,
+			"versions": []any{map[string]any{
+				"name":   s.Version,
+				"schema": map[string]any{"openAPIV3Schema": openAPI},
+			}},
+		},
+	}}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"})
+	crd.SetName(s.Plural + "." + s.Group)
+	return crd
+}
+
+func TestCheckReportCRDs(t *testing.T) {
+	dropStatusField := func(openAPI map[string]any) {
+		status, _, _ := unstructured.NestedMap(openAPI, "properties", "status")
+		props, _ := status["properties"].(map[string]any)
+		delete(props, "compliance")
+		_ = unstructured.SetNestedMap(openAPI, status, "properties", "status")
+	}
+	noMatch := interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if _, ok := list.(*audiciav1alpha1.AudiciaPolicyList); ok {
+				return &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "audicia.io", Kind: "AudiciaPolicy"}}
+			}
+			return c.List(ctx, list, opts...)
+		},
+	}
+
+	tests := []struct {
+		name       string
+		objs       []client.Object
+		funcs      interceptor.Funcs
+		wantReason string
+	}{
+		{name: "current", objs: []client.Object{installedCRD(t, "AudiciaReport", nil), installedCRD(t, "AudiciaPolicy", nil)}},
+		{name: "unreadable CRDs"},
+		{name: "missing", funcs: noMatch, wantReason: "ReportCRDMissing"},
+		{name: "outdated", objs: []client.Object{installedCRD(t, "AudiciaReport", dropStatusField)}, wantReason: "SchemaOutdated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(tt.objs...).WithInterceptorFuncs(tt.funcs).Build()
+			r := &Reconciler{Client: c}
+			reason, message := r.checkReportCRDs(context.Background())
+			if reason != tt.wantReason {
+				t.Errorf("reason = %q (%s), want %q", reason, message, tt.wantReason)
+			}
+			if tt.wantReason == "SchemaOutdated" && message != "The installed AudiciaReport CRD is older than the operator and lacks status.compliance; upgrade the CRDs." {
+				t.Errorf("message = %q", message)
+			}
+		})
+	}
+}
+
+func TestReportCRDGate_HoldsBackUntilInstalled(t *testing.T) {
+	source := &audiciav1alpha1.AudiciaSource{ObjectMeta: metav1.ObjectMeta{Namespace: "audicia-system", Name: "audit", Generation: 1}}
+	var installed atomic.Bool
+	c := fake.NewClientBuilder().
+		WithScheme(newTestScheme()).
+		WithObjects(source).
+		WithStatusSubresource(&audiciav1alpha1.AudiciaSource{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if _, ok := list.(*audiciav1alpha1.AudiciaReportList); ok && !installed.Load() {
+					return &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "audicia.io", Kind: "AudiciaReport"}}
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).
+		Build()
+	recorder := events.NewFakeRecorder(10)
+	r := &Reconciler{Client: c, Recorder: recorder}
+	key := types.NamespacedName{Namespace: "audicia-system", Name: "audit"}
+	ctx := context.Background()
+
+	condition := func() *metav1.Condition {
+		var got audiciav1alpha1.AudiciaSource
+		if err := c.Get(ctx, key, &got); err != nil {
+			t.Fatal(err)
+		}
+		return meta.FindStatusCondition(got.Status.Conditions, reportCRDCondition)
+	}
+
+	now := time.Now()
+	gate := r.newReportCRDGate(ctx, key, *source)
+	if gate.ready(ctx, now) {
+		t.Fatal("ready without the AudiciaReport CRD")
+	}
+	if cond := condition(); cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "ReportCRDMissing" {
+		t.Errorf("condition = %+v, want False/ReportCRDMissing", cond)
+	}
+
+	installed.Store(true)
+	if gate.ready(ctx, now.Add(reportCRDRecheckInterval/2)) {
+		t.Error("rechecked before the recheck interval")
+	}
+	if !gate.ready(ctx, now.Add(reportCRDRecheckInterval+time.Second)) {
+		t.Fatal("not ready after the CRD was installed")
+	}
+	if cond := condition(); cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Errorf("condition = %+v, want True", cond)
+	}
+	if got := len(recorder.Events); got != 2 {
+		t.Errorf("recorded %d events, want a warning and a recovery", got)
+	}
+}
@@ -0,0 +1,89 @@
+package audiciasource
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// eventSampler implements spec.sampling. Kept events are weighted with the
+// inverse of their keep probability, rounded to a whole number at random so
+// the weights stay unbiased, which keeps aggregated counts estimates of the
+// full traffic. It is owned by a single event bus and is not safe for
+// concurrent use.
+type eventSampler struct {
+	rate          float64
+	perSubjectMax int
+
+	// second is the wall-clock second seen counts events in; previous holds
+	// the counts of the second before, which set the keep probability of
+	// users above perSubjectMax.
+	second   int64
+	seen     map[string]int
+	previous map[string]int
+
+	random func() float64
+	now    func() time.Time
+}
+
+// parseSamplingRate parses spec.sampling.rate, which defaults to 1.
+func parseSamplingRate(cfg *audiciav1alpha1.SamplingConfig) (float64, error) {
+	if cfg == nil || cfg.Rate == "" {
+		return 1, nil
+	}
+	rate, err := strconv.ParseFloat(cfg.Rate, 64)
+	if err != nil || rate <= 0 || rate > 1 {
+		return 0, fmt.Errorf("invalid sampling rate %q: must be a decimal in (0, 1]", cfg.Rate)
+	}
+	return rate, nil
+}
+
+// newEventSampler returns nil when spec.sampling keeps every event.
+func newEventSampler(source audiciav1alpha1.AudiciaSource) *eventSampler {
+	cfg := source.Spec.Sampling
+	rate, err := parseSamplingRate(cfg)
+	if cfg == nil || err != nil || rate == 1 && cfg.PerSubjectMax <= 0 {
+		return nil
+	}
+	return &eventSampler{
+		rate:          rate,
+		perSubjectMax: int(cfg.PerSubjectMax),
+		seen:          make(map[string]int),
+		previous:      make(map[string]int),
+		random:        rand.Float64,
+		now:           time.Now,
+	}
+}
+
+// sample decides whether to keep an event of user, returning the number of
+// events the kept event stands for, or 0 to drop it.
+func (s *eventSampler) sample(user string) int64 {
+	p := s.rate
+	if s.perSubjectMax > 0 {
+		if second := s.now().Unix(); second != s.second {
+			if second == s.second+1 {
+				s.previous, s.seen = s.seen, s.previous
+			} else {
+				clear(s.previous)
+			}
+			clear(s.seen)
+			s.second = second
+		}
+		s.seen[user]++
+		if n := s.previous[user]; n > s.perSubjectMax {
+			p *= float64(s.perSubjectMax) / float64(n)
+		}
+	}
+	if p < 1 && s.random() >= p {
+		return 0
+	}
+	weight, frac := math.Modf(1 / p)
+	if frac > 0 && s.random() < frac {
+		weight++
+	}
+	return int64(weight)
+}
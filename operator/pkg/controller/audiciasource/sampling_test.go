@@ -0,0 +1,113 @@
+package audiciasource
+
+import (
+	"testing"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/filter"
+)
+
+func samplingSource(cfg *audiciav1alpha1.SamplingConfig) audiciav1alpha1.AudiciaSource {
+	return audiciav1alpha1.AudiciaSource{Spec: audiciav1alpha1.AudiciaSourceSpec{Sampling: cfg}}
+}
+
+func TestNewEventSampler_DisabledKeepsEverything(t *testing.T) {
+	for _, cfg := range []*audiciav1alpha1.SamplingConfig{nil, {}, {Rate: "1"}, {Rate: "1.0"}} {
+		if s := newEventSampler(samplingSource(cfg)); s != nil {
+			t.Errorf("newEventSampler(%+v) = %+v, want nil", cfg, s)
+		}
+	}
+}
+
+func TestParseSamplingRate(t *testing.T) {
+	for _, rate := range []string{"0", "1.5", "-0.1", "abc"} {
+		if _, err := parseSamplingRate(&audiciav1alpha1.SamplingConfig{Rate: rate}); err == nil {
+			t.Errorf("parseSamplingRate(%q) succeeded", rate)
+		}
+	}
+	if got, err := parseSamplingRate(&audiciav1alpha1.SamplingConfig{Rate: ".25"}); err != nil || got != 0.25 {
+		t.Errorf("parseSamplingRate(.25) = %v, %v", got, err)
+	}
+}
+
+func TestEventSampler_RateWeightsKeptEvents(t *testing.T) {
+	s := newEventSampler(samplingSource(&audiciav1alpha1.SamplingConfig{Rate: "0.25"}))
+	draws := []float64{0.1, 0.9}
+	s.random = func() float64 {
+		d := draws[0]
+		draws = draws[1:]
+		return d
+	}
+	if w := s.sample("alice"); w != 4 {
+		t.Errorf("kept event weight = %d, want 4", w)
+	}
+	if w := s.sample("alice"); w != 0 {
+		t.Errorf("dropped event weight = %d, want 0", w)
+	}
+}
+
+func TestEventSampler_PerSubjectMax(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newEventSampler(samplingSource(&audiciav1alpha1.SamplingConfig{PerSubjectMax: 10}))
+	s.now = func() time.Time { return now }
+	s.random = func() float64 { return 0 }
+
+	// Below the cap, or without a previous second, every event is kept.
+	var total int64
+	for range 40 {
+		total += s.sample("controller")
+	}
+	s.sample("quiet")
+	if total != 40 {
+		t.Fatalf("first second total weight = %d, want 40", total)
+	}
+
+	// 40 events in the previous second: keep a quarter, each weighing 4.
+	now = now.Add(time.Second)
+	if w := s.sample("controller"); w != 4 {
+		t.Errorf("capped subject weight = %d, want 4", w)
+	}
+	if w := s.sample("quiet"); w != 1 {
+		t.Errorf("quiet subject weight = %d, want 1", w)
+	}
+
+	// After a silent second, the cap starts over.
+	now = now.Add(3 * time.Second)
+	if w := s.sample("controller"); w != 1 {
+		t.Errorf("weight after a gap = %d, want 1", w)
+	}
+}
+
+func TestProcessEvent_SamplingWeightsCounts(t *testing.T) {
+	r := &Reconciler{}
+	source := samplingSource(&audiciav1alpha1.SamplingConfig{Rate: "0.5"})
+	chain, _ := filter.NewChain(nil)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+	event := auditv1.Event{
+		Verb:       "get",
+		User:       authnv1.UserInfo{Username: "alice"},
+		ObjectRef:  &auditv1.ObjectReference{Resource: "pods", Namespace: "default", APIVersion: "v1"},
+		RequestURI: "/api/v1/namespaces/default/pods",
+	}
+
+	var kept int64
+	for range 200 {
+		if r.processEvent(event, source, chain, nil, nil, aggregators, subjects) == "" {
+			kept++
+		}
+	}
+	if kept == 0 || kept == 200 {
+		t.Fatalf("kept %d of 200 events at rate 0.5", kept)
+	}
+	for _, agg := range aggregators {
+		if got := agg.EventsProcessed(); got != 2*kept {
+			t.Errorf("weighted count = %d, want %d for %d kept events", got, 2*kept, kept)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package audiciasource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/filter"
+	"github.com/felixnotka/audicia/operator/pkg/ingestor"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+	"github.com/felixnotka/audicia/operator/pkg/strategy"
+)
+
+const (
+	// SelfTestPath is served on the operator's metrics server.
+	SelfTestPath = "/selftest"
+
+	// SelfTestAnnotation tags synthetic audit events injected by a self-test.
+	SelfTestAnnotation = "audicia.io/selftest"
+
+	// selfTestAccount is the ServiceAccount name the synthetic events are
+	// attributed to, in the namespace of the source under test.
+	selfTestAccount = "audicia-selftest"
+
+	// selfTestInterval is the minimum time between self-tests of one source.
+	selfTestInterval = time.Minute
+
+	// selfTestTimeout bounds a complete self-test, including report verification.
+	selfTestTimeout = 30 * time.Second
+)
+
+var (
+	errNoPipeline          = errors.New("no ingestion pipeline is running for this source on this replica")
+	errSelfTestRateLimited = errors.New("self-test already ran recently for this source")
+)
+
+// SelfTestResult is the JSON body returned by the self-test endpoint.
+type SelfTestResult struct {
+	Source         string `json:"source"`
+	Subject        string `json:"subject"`
+	EventsInjected int    `json:"eventsInjected"`
+	Report         string `json:"report,omitempty"`
+	RulesObserved  int    `json:"rulesObserved"`
+	Passed         bool   `json:"passed"`
+	Message        string `json:"message"`
+	DurationMillis int64  `json:"durationMillis"`
+}
+
+// selfTestRequest hands synthetic events to a running event loop. The loop
+// reports the flush outcome on done.
+type selfTestRequest struct {
+	events []auditv1.Event
+	done   chan error
+}
+
+// selfTestEvents builds the synthetic events for a source namespace. Each
+// maps to a distinct rule, so the resulting report must contain all of them.
+func selfTestEvents(namespace string, now time.Time) []auditv1.Event {
+	username := fmt.Sprintf("system:serviceaccount:%s:%s", namespace, selfTestAccount)
+	calls := []struct {
+		verb, resource, name string
+	}{
+		{"get", "pods", selfTestAccount},
+		{"list", "pods", ""},
+		{"get", "configmaps", selfTestAccount},
+	}
+
+	ts := metav1.NewMicroTime(now)
+	out := make([]auditv1.Event, 0, len(calls))
+	for i, call := range calls {
+		uri := fmt.Sprintf("/api/v1/namespaces/%s/%s", namespace, call.resource)
+		if call.name != "" {
+			uri += "/" + call.name
+		}
+		out = append(out, auditv1.Event{
+			AuditID:    types.UID(fmt.Sprintf("audicia-selftest-%d-%d", now.UnixNano(), i)),
+			Stage:      auditv1.StageResponseComplete,
+			RequestURI: uri,
+			Verb:       call.verb,
+			User:       authenticationv1.UserInfo{Username: username},
+			ObjectRef: &auditv1.ObjectReference{
+				Resource:   call.resource,
+				Namespace:  namespace,
+				Name:       call.name,
+				APIVersion: "v1",
+			},
+			ResponseStatus:           &metav1.Status{Code: http.StatusOK},
+			RequestReceivedTimestamp: ts,
+			StageTimestamp:           ts,
+			Annotations:              map[string]string{SelfTestAnnotation: "true"},
+		})
+	}
+	return out
+}
+
+// processSelfTest runs synthetic events through normalization, aggregation
+// and flushing, isolated from the source's live aggregators. Filters and
+// subject aliases are bypassed so that a deliberately narrow source
+// configuration does not make the test fail, and the report's changes are
+// not sent to the Notifier or the report webhooks.
+func (r *Reconciler) processSelfTest(
+	ctx context.Context,
+	key types.NamespacedName,
+	source audiciav1alpha1.AudiciaSource,
+	engine *strategy.Engine,
+	events []auditv1.Event,
+) error {
+	allowAll, err := filter.NewChain(nil)
+	if err != nil {
+		return err
+	}
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+	for _, event := range events {
+		r.processEvent(event, source, allowAll, nil, nil, aggregators, subjects)
+	}
+	if len(aggregators) == 0 {
+		return errors.New("synthetic events were dropped during normalization")
+	}
+	// Synthetic activity is not a change to notify receivers of.
+	logger := ctrl.Log.WithName("pipeline").WithValues("source", key)
+	result := r.flushSubjects(ctx, source, engine, slices.Collect(maps.Keys(aggregators)), aggregators, subjects, false, logger)
+	if len(result.failed) > 0 {
+		return errors.New("flushing the self-test report failed; see FlushFailed events on the source")
+	}
+	return nil
+}
+
+// runSelfTest injects synthetic events into the running pipeline for key,
+// waits for the resulting report and removes the test outputs again.
+func (r *Reconciler) runSelfTest(ctx context.Context, key types.NamespacedName) (SelfTestResult, error) {
+	start := time.Now()
+
+	r.mu.Lock()
+	ps, ok := r.pipelines[key]
+	if !ok || ps.selfTests == nil {
+		r.mu.Unlock()
+		return SelfTestResult{}, errNoPipeline
+	}
+	if !ps.lastSelfTest.IsZero() && start.Sub(ps.lastSelfTest) < selfTestInterval {
+		r.mu.Unlock()
+		return SelfTestResult{}, errSelfTestRateLimited
+	}
+	ps.lastSelfTest = start
+	selfTests := ps.selfTests
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, selfTestTimeout)
+	defer cancel()
+
+	subject := audiciav1alpha1.Subject{
+		Kind:      audiciav1alpha1.SubjectKindServiceAccount,
+		Name:      selfTestAccount,
+		Namespace: key.Namespace,
+	}
+	reportKey := types.NamespacedName{Name: reportNameFor(subject), Namespace: key.Namespace}
+	req := selfTestRequest{
+		events: selfTestEvents(key.Namespace, start),
+		done:   make(chan error, 1),
+	}
+	result := SelfTestResult{
+		Source:         key.String(),
+		Subject:        fmt.Sprintf("system:serviceaccount:%s:%s", key.Namespace, selfTestAccount),
+		EventsInjected: len(req.events),
+	}
+	finish := func(passed bool, msg string) SelfTestResult {
+		result.Passed = passed
+		result.Message = msg
+		result.DurationMillis = time.Since(start).Milliseconds()
+		return result
+	}
+
+	select {
+	case selfTests <- req:
+	case <-ctx.Done():
+		return finish(false, "pipeline did not accept the synthetic events in time; it may be stalled"), nil
+	}
+	var flushErr error
+	select {
+	case flushErr = <-req.done:
+	case <-ctx.Done():
+		return finish(false, "pipeline did not flush the synthetic events in time"), nil
+	}
+	defer r.cleanupSelfTest(key, subject)
+	if flushErr != nil {
+		return finish(false, flushErr.Error()), nil
+	}
+
+	// The client reads from the informer cache, so give it a moment to
+	// observe the freshly written report.
+	var report audiciav1alpha1.AudiciaReport
+	err := wait.PollUntilContextCancel(ctx, 200*time.Millisecond, true, func(ctx context.Context) (bool, error) {
+		if err := r.Get(ctx, reportKey, &report); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		return len(report.Status.ObservedRules) >= len(req.events), nil
+	})
+	result.Report = reportKey.String()
+	result.RulesObserved = len(report.Status.ObservedRules)
+	if err != nil {
+		return finish(false, fmt.Sprintf("report %s was not produced with the expected rules: %v", reportKey, err)), nil
+	}
+	return finish(true, "synthetic events were ingested, aggregated and written to a report"), nil
+}
+
+// cleanupSelfTest removes the report and policy produced by a self-test.
+func (r *Reconciler) cleanupSelfTest(key types.NamespacedName, subject audiciav1alpha1.Subject) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	logger := ctrl.Log.WithName("selftest").WithValues("source", key)
+
+	reportName := reportNameFor(subject)
+	objs := []client.Object{
+		&audiciav1alpha1.AudiciaReport{ObjectMeta: metav1.ObjectMeta{Name: reportName, Namespace: subject.Namespace}},
+		&audiciav1alpha1.AudiciaPolicy{ObjectMeta: metav1.ObjectMeta{Name: policyNameFor(subject), Namespace: subject.Namespace}},
+	}
+	for _, obj := range objs {
+		if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			logger.Error(err, "failed to remove self-test output", "name", obj.GetName())
+		}
+	}
+	metrics.ReportRulesCount.DeleteLabelValues(reportName)
+	metrics.ForgetCompliance(subject.Namespace, reportName, string(subject.Kind))
+}
+
+// selfTestHandler serves POST /selftest?namespace=<ns>&name=<source>.
+type selfTestHandler struct {
+	reconciler *Reconciler
+
+	// authenticator returns the Authenticator guarding a source. Callers
+	// must hold "create" on audiciasources/selftest for that source.
+	authenticator func(key types.NamespacedName) ingestor.Authenticator
+}
+
+func newSelfTestHandler(r *Reconciler) *selfTestHandler {
+	return &selfTestHandler{
+		reconciler: r,
+		authenticator: func(key types.NamespacedName) ingestor.Authenticator {
+			a := ingestor.NewTokenReviewAuthenticator(r.Client, key.Namespace, key.Name, nil)
+			a.Attributes.Subresource = "selftest"
+			return a
+		},
+	}
+}
+
+func (h *selfTestHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	key := types.NamespacedName{
+		Namespace: req.URL.Query().Get("namespace"),
+		Name:      req.URL.Query().Get("name"),
+	}
+	if key.Namespace == "" || key.Name == "" {
+		http.Error(rw, "namespace and name query parameters are required", http.StatusBadRequest)
+		return
+	}
+	if err := h.authenticator(key).Authenticate(req.Context(), req); err != nil {
+		ingestor.WriteAuthError(rw, err)
+		return
+	}
+
+	result, err := h.reconciler.runSelfTest(req.Context(), key)
+	switch {
+	case errors.Is(err, errSelfTestRateLimited):
+		rw.Header().Set("Retry-After", strconv.Itoa(int(selfTestInterval.Seconds())))
+		http.Error(rw, err.Error(), http.StatusTooManyRequests)
+		return
+	case err != nil:
+		http.Error(rw, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	status := http.StatusOK
+	if !result.Passed {
+		status = http.StatusInternalServerError
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(result)
+}
@@ -0,0 +1,168 @@
+package audiciasource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/filter"
+	"github.com/felixnotka/audicia/operator/pkg/ingestor"
+	"github.com/felixnotka/audicia/operator/pkg/strategy"
+)
+
+type authFunc func(ctx context.Context, req *http.Request) error
+
+func (f authFunc) Authenticate(ctx context.Context, req *http.Request) error { return f(ctx, req) }
+
+func allowAllAuth(types.NamespacedName) ingestor.Authenticator {
+	return authFunc(func(context.Context, *http.Request) error { return nil })
+}
+
+// startSelfTestPipeline registers a pipeline for source and runs its event
+// loop until the test ends.
+func startSelfTestPipeline(t *testing.T, r *Reconciler, source audiciav1alpha1.AudiciaSource) types.NamespacedName {
+	t.Helper()
+	key := types.NamespacedName{Name: source.Name, Namespace: source.Namespace}
+	filterChain, err := filter.NewChain(source.Spec.Filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	selfTests := make(chan selfTestRequest)
+	r.pipelines[key] = &pipelineState{cancel: cancel, generation: 1, selfTests: selfTests}
+
+	done := make(chan struct{})
+	go func() {
+		r.eventLoop(ctx, key, source, strategy.NewEngine(source.Spec.PolicyStrategy), filterChain, nil, nil,
+			&fakeIngestor{}, make(chan auditv1.Event), selfTests, nil)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return key
+}
+
+func TestSelfTestEvents_Tagged(t *testing.T) {
+	events := selfTestEvents("audicia-system", time.Now())
+	if len(events) == 0 {
+		t.Fatal("expected synthetic events")
+	}
+	for _, e := range events {
+		if e.Annotations[SelfTestAnnotation] != "true" {
+			t.Errorf("event %s missing %s annotation", e.AuditID, SelfTestAnnotation)
+		}
+		if e.User.Username != "system:serviceaccount:audicia-system:audicia-selftest" {
+			t.Errorf("unexpected user %q", e.User.Username)
+		}
+	}
+}
+
+func TestRunSelfTest_ProducesAndRemovesReport(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "selftest-source", Namespace: "default"},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			// A deny-all filter must not make the self-test fail.
+			Filters: []audiciav1alpha1.Filter{{Action: audiciav1alpha1.FilterActionDeny, UserPattern: ".*"}},
+		},
+	}
+	r := newTestReconciler(&source)
+	key := startSelfTestPipeline(t, r, source)
+
+	result, err := r.runSelfTest(context.Background(), key)
+	if err != nil {
+		t.Fatalf("runSelfTest() error = %v", err)
+	}
+	if !result.Passed {
+		t.Fatalf("self-test failed: %+v", result)
+	}
+	if result.RulesObserved != result.EventsInjected {
+		t.Errorf("RulesObserved = %d, want %d", result.RulesObserved, result.EventsInjected)
+	}
+
+	var reports audiciav1alpha1.AudiciaReportList
+	if err := r.List(context.Background(), &reports); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports.Items) != 0 {
+		t.Errorf("expected self-test report to be removed, found %d", len(reports.Items))
+	}
+	var policies audiciav1alpha1.AudiciaPolicyList
+	if err := r.List(context.Background(), &policies); err != nil {
+		t.Fatal(err)
+	}
+	if len(policies.Items) != 0 {
+		t.Errorf("expected self-test policy to be removed, found %d", len(policies.Items))
+	}
+
+	if _, err := r.runSelfTest(context.Background(), key); !errors.Is(err, errSelfTestRateLimited) {
+		t.Errorf("second runSelfTest() error = %v, want rate limit", err)
+	}
+}
+
+func TestRunSelfTest_NoPipeline(t *testing.T) {
+	r := newTestReconciler()
+	_, err := r.runSelfTest(context.Background(), types.NamespacedName{Name: "missing", Namespace: "default"})
+	if !errors.Is(err, errNoPipeline) {
+		t.Errorf("runSelfTest() error = %v, want errNoPipeline", err)
+	}
+}
+
+func TestSelfTestHandler(t *testing.T) {
+	source := audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "selftest-http", Namespace: "default"},
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		query      string
+		auth       func(types.NamespacedName) ingestor.Authenticator
+		wantStatus int
+	}{
+		{"wrong method", http.MethodGet, "?namespace=default&name=selftest-http", allowAllAuth, http.StatusMethodNotAllowed},
+		{"missing source", http.MethodPost, "?namespace=default", allowAllAuth, http.StatusBadRequest},
+		{"unauthenticated", http.MethodPost, "?namespace=default&name=selftest-http", func(types.NamespacedName) ingestor.Authenticator {
+			return authFunc(func(context.Context, *http.Request) error { return ingestor.ErrUnauthenticated })
+		}, http.StatusUnauthorized},
+		{"forbidden", http.MethodPost, "?namespace=default&name=selftest-http", func(types.NamespacedName) ingestor.Authenticator {
+			return authFunc(func(context.Context, *http.Request) error { return ingestor.ErrForbidden })
+		}, http.StatusForbidden},
+		{"no pipeline", http.MethodPost, "?namespace=default&name=other", allowAllAuth, http.StatusServiceUnavailable},
+		{"passes", http.MethodPost, "?namespace=default&name=selftest-http", allowAllAuth, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(&source)
+			startSelfTestPipeline(t, r, source)
+			h := &selfTestHandler{reconciler: r, authenticator: tt.auth}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, SelfTestPath+tt.query, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var result SelfTestResult
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatal(err)
+			}
+			if !result.Passed || result.Source != "default/selftest-http" {
+				t.Errorf("unexpected result %+v", result)
+			}
+		})
+	}
+}
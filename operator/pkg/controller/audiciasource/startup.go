@@ -0,0 +1,132 @@
+package audiciasource
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+)
+
+// failPipeline reports that the pipeline of key could not start: Ready is
+// set to False with reason and message, a Warning Event is emitted on the
+// source, and the source is reconciled again, which restarts the pipeline
+// once a backoff growing with each consecutive failure has elapsed.
+func (r *Reconciler) failPipeline(ctx context.Context, key types.NamespacedName, source audiciav1alpha1.AudiciaSource, reason, message string) {
+	// The retry time is recorded before the condition is written, so the
+	// reconcile the status update triggers waits out the backoff.
+	delay := r.markPipelineFailed(key, source.Generation, time.Now())
+	ctrl.Log.WithName("pipeline").WithValues("source", key).
+		Error(nil, "pipeline failed to start", "reason", reason, "message", message, "retryIn", delay)
+	metrics.ReconcileErrorsTotal.Inc()
+
+	r.setSourceCondition(ctx, key, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: source.Generation,
+	})
+	r.Recorder.Eventf(&source, nil, corev1.EventTypeWarning, "PipelineFailed", "Start",
+		"Ingestion pipeline failed to start (%s): %s Retrying in %s.", reason, message, delay)
+
+	if r.requeue == nil {
+		return
+	}
+	select {
+	case r.requeue <- event.GenericEvent{Object: &source}:
+	case <-ctx.Done():
+	}
+}
+
+// failPipelineErr is failPipeline with err as the message.
+func (r *Reconciler) failPipelineErr(ctx context.Context, key types.NamespacedName, source audiciav1alpha1.AudiciaSource, reason string, err error) {
+	r.failPipeline(ctx, key, source, reason, fmt.Sprintf("%v.", err))
+}
+
+// markPipelineFailed records a failed start of the pipeline of key at the
+// given generation and returns the delay before it is restarted.
+func (r *Reconciler) markPipelineFailed(key types.NamespacedName, generation int64, now time.Time) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ps, ok := r.pipelines[key]
+	if !ok || ps.generation != generation {
+		// Already replaced by a pipeline for a newer spec.
+		return backoffDelay(1)
+	}
+	ps.failures++
+	delay := backoffDelay(ps.failures)
+	ps.retryAt = now.Add(delay)
+	return delay
+}
+
+// pipelineStarted resets the failed starts of the pipeline of key and
+// records when it started.
+func (r *Reconciler) pipelineStarted(key types.NamespacedName, generation int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ps, ok := r.pipelines[key]; ok && ps.generation == generation {
+		ps.failures = 0
+		ps.retryAt = time.Time{}
+		ps.startedAt = time.Now()
+	}
+}
+
+// mountedFilePollInterval is how often the mounted files a failed pipeline
+// depends on are checked for changes.
+const mountedFilePollInterval = 5 * time.Second
+
+// retryOnChange restarts the failed pipeline of key as soon as one of files
+// changes, e.g. once the kubelet updates a mounted Secret, rather than after
+// its backoff. It returns when ctx is done, i.e. when the pipeline is
+// replaced or the source deleted.
+func (r *Reconciler) retryOnChange(ctx context.Context, key types.NamespacedName, source audiciav1alpha1.AudiciaSource, files []string, interval time.Duration) {
+	initial := filesDigest(files)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if filesDigest(files) == initial {
+			continue
+		}
+		ctrl.Log.WithName("pipeline").WithValues("source", key).Info("mounted files changed, restarting pipeline", "files", files)
+		r.mu.Lock()
+		if ps, ok := r.pipelines[key]; ok && ps.generation == source.Generation && !ps.retryAt.IsZero() {
+			ps.retryAt = time.Now()
+		}
+		r.mu.Unlock()
+		if r.requeue != nil {
+			select {
+			case r.requeue <- event.GenericEvent{Object: &source}:
+			case <-ctx.Done():
+			}
+		}
+		return
+	}
+}
+
+// filesDigest hashes the contents of files; missing files hash as empty.
+func filesDigest(files []string) [sha256.Size]byte {
+	h := sha256.New()
+	for _, f := range files {
+		data, _ := os.ReadFile(f)
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00", f, len(data))
+		h.Write(data)
+	}
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
@@ -0,0 +1,113 @@
+package audiciasource
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func TestRunPipeline_FailureSetsConditionAndBacksOff(t *testing.T) {
+	source := &audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "src", Namespace: "default", Generation: 1},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			SourceType: audiciav1alpha1.SourceTypeK8sAuditLog,
+			Location:   &audiciav1alpha1.FileLocation{Path: "/tmp/audit.log"},
+			Filters:    []audiciav1alpha1.Filter{{Action: audiciav1alpha1.FilterActionDeny, UserPattern: "("}},
+		},
+	}
+	r := newTestReconciler(source)
+	key := types.NamespacedName{Name: "src", Namespace: "default"}
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.pipelines[key] = &pipelineState{cancel: cancel, generation: 1}
+
+	r.runPipeline(context.Background(), key, *source, nil)
+
+	var updated audiciav1alpha1.AudiciaSource
+	if err := r.Get(context.Background(), key, &updated); err != nil {
+		t.Fatal(err)
+	}
+	cond := meta.FindStatusCondition(updated.Status.Conditions, "Ready")
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "FiltersInvalid" ||
+		!strings.Contains(cond.Message, "filter chain") {
+		t.Errorf("Ready condition = %+v, want False/FiltersInvalid", cond)
+	}
+	select {
+	case e := <-r.Recorder.(*events.FakeRecorder).Events:
+		if !strings.Contains(e, "PipelineFailed") {
+			t.Errorf("event = %q, want PipelineFailed", e)
+		}
+	default:
+		t.Error("no event emitted")
+	}
+
+	// The source is not restarted before its backoff has elapsed.
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RequeueAfter <= 0 || result.RequeueAfter > flushRetryBaseDelay {
+		t.Errorf("RequeueAfter = %v, want up to %v", result.RequeueAfter, flushRetryBaseDelay)
+	}
+	if delay := r.markPipelineFailed(key, 1, time.Now()); delay != 2*flushRetryBaseDelay {
+		t.Errorf("second failure delay = %v, want %v", delay, 2*flushRetryBaseDelay)
+	}
+
+	r.pipelineStarted(key, 1)
+	if ps := r.pipelines[key]; ps.failures != 0 || !ps.retryAt.IsZero() {
+		t.Errorf("failures = %d, retryAt = %v after a successful start", ps.failures, ps.retryAt)
+	}
+}
+
+func TestRetryOnChange_RestartsWhenMountedFileChanges(t *testing.T) {
+	source := &audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "src", Namespace: "default", Generation: 1},
+	}
+	r := newTestReconciler(source)
+	r.requeue = make(chan event.GenericEvent, 1)
+	key := types.NamespacedName{Name: "src", Namespace: "default"}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.pipelines[key] = &pipelineState{cancel: cancel, generation: 1}
+	r.markPipelineFailed(key, 1, time.Now())
+
+	file := filepath.Join(t.TempDir(), "tls.crt")
+	if err := os.WriteFile(file, []byte("broken"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		r.retryOnChange(ctx, key, *source, []string{file}, 10*time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-r.requeue:
+		t.Fatal("requeued before the file changed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if err := os.WriteFile(file, []byte("fixed"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-r.requeue:
+	case <-time.After(5 * time.Second):
+		t.Fatal("not requeued after the file changed")
+	}
+	<-done
+	if wait := time.Until(r.pipelines[key].retryAt); wait > 0 {
+		t.Errorf("retryAt is %v away, want the backoff skipped", wait)
+	}
+}
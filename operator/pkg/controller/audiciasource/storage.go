@@ -0,0 +1,181 @@
+package audiciasource
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+)
+
+// StorageEstimator periodically estimates the etcd storage used by Audicia
+// objects, as the size of their JSON serialization. It exports the totals by
+// kind and namespace as metrics and records in each source's status.storage
+// the size of the source and of the reports and policies it writes.
+type StorageEstimator struct {
+	client.Client
+
+	// Interval is the time between estimates.
+	Interval time.Duration
+}
+
+// SetupStorageEstimatorWithManager registers a StorageEstimator running every
+// interval with the manager. A zero interval disables it.
+func SetupStorageEstimatorWithManager(mgr ctrl.Manager, interval time.Duration) error {
+	if interval <= 0 {
+		return nil
+	}
+	return mgr.Add(&StorageEstimator{Client: mgr.GetClient(), Interval: interval})
+}
+
+// Start estimates once and then every Interval until ctx is done. As a
+// leader election runnable, it only runs on the leader, after the caches
+// have synced.
+func (e *StorageEstimator) Start(ctx context.Context) error {
+	logger := ctrl.Log.WithName("storage-estimator")
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+	for {
+		if err := e.Estimate(ctx, time.Now()); err != nil {
+			logger.Error(err, "failed to estimate storage")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// storageKey groups objects for the storage metrics.
+type storageKey struct {
+	kind, namespace string
+}
+
+// Estimate sums the serialized sizes of all AudiciaSources, AudiciaReports
+// and AudiciaPolicies, updates the storage metrics and records each source's
+// share in its status. A report or policy counts towards every source owning
+// it, and a report towards the source holding its write lease.
+func (e *StorageEstimator) Estimate(ctx context.Context, now time.Time) error {
+	var sources audiciav1alpha1.AudiciaSourceList
+	if err := e.List(ctx, &sources); err != nil {
+		return err
+	}
+	var reports audiciav1alpha1.AudiciaReportList
+	if err := e.List(ctx, &reports); err != nil {
+		return err
+	}
+	var policies audiciav1alpha1.AudiciaPolicyList
+	if err := e.List(ctx, &policies); err != nil {
+		return err
+	}
+
+	bytes := make(map[storageKey]int64)
+	objects := make(map[storageKey]int)
+	count := func(kind string, obj client.Object) int64 {
+		size := objectSize(obj)
+		k := storageKey{kind: kind, namespace: obj.GetNamespace()}
+		bytes[k] += size
+		objects[k]++
+		return size
+	}
+
+	perSource := make(map[types.NamespacedName]*audiciav1alpha1.StorageEstimate, len(sources.Items))
+	for i := range sources.Items {
+		source := &sources.Items[i]
+		perSource[client.ObjectKeyFromObject(source)] = &audiciav1alpha1.StorageEstimate{
+			Bytes:         count("AudiciaSource", source),
+			EstimatedTime: metav1.NewTime(now),
+		}
+	}
+	for i := range reports.Items {
+		report := &reports.Items[i]
+		size := count("AudiciaReport", report)
+		for _, key := range writingSources(report, report.Annotations[WriterAnnotation]) {
+			if est := perSource[key]; est != nil {
+				est.Reports++
+				est.Bytes += size
+			}
+		}
+	}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		size := count("AudiciaPolicy", policy)
+		for _, key := range writingSources(policy, "") {
+			if est := perSource[key]; est != nil {
+				est.Policies++
+				est.Bytes += size
+			}
+		}
+	}
+
+	metrics.StorageBytes.Reset()
+	metrics.StorageObjects.Reset()
+	for k, b := range bytes {
+		metrics.StorageBytes.WithLabelValues(k.kind, k.namespace).Set(float64(b))
+		metrics.StorageObjects.WithLabelValues(k.kind, k.namespace).Set(float64(objects[k]))
+	}
+
+	var errs []error
+	for key, est := range perSource {
+		if err := e.recordStorage(ctx, key, *est); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return stderrors.Join(errs...)
+}
+
+// recordStorage writes status.storage of the source key.
+func (e *StorageEstimator) recordStorage(ctx context.Context, key types.NamespacedName, est audiciav1alpha1.StorageEstimate) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var source audiciav1alpha1.AudiciaSource
+		if err := e.Get(ctx, key, &source); err != nil {
+			return err
+		}
+		source.Status.Storage = &est
+		return e.Status().Update(ctx, &source)
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// writingSources returns the AudiciaSources owning obj, and the source named
+// by writer ("namespace/name") if not empty.
+func writingSources(obj client.Object, writer string) []types.NamespacedName {
+	var keys []types.NamespacedName
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind == "AudiciaSource" {
+			keys = append(keys, types.NamespacedName{Namespace: obj.GetNamespace(), Name: ref.Name})
+		}
+	}
+	if ns, name, ok := strings.Cut(writer, "/"); ok {
+		key := types.NamespacedName{Namespace: ns, Name: name}
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// objectSize returns the size of obj's JSON serialization, which is how the
+// API server stores custom resources in etcd.
+func objectSize(obj client.Object) int64 {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
@@ -0,0 +1,50 @@
+package audiciasource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+)
+
+func TestStorageEstimator(t *testing.T) {
+	source := &audiciav1alpha1.AudiciaSource{ObjectMeta: metav1.ObjectMeta{Name: "src", Namespace: "audicia", UID: "src-uid"}}
+	owner := []metav1.OwnerReference{{APIVersion: audiciav1alpha1.SchemeGroupVersion.String(), Kind: "AudiciaSource", Name: "src", UID: "src-uid"}}
+	owned := &audiciav1alpha1.AudiciaReport{ObjectMeta: metav1.ObjectMeta{Name: "owned", Namespace: "audicia", OwnerReferences: owner}}
+	leased := &audiciav1alpha1.AudiciaReport{ObjectMeta: metav1.ObjectMeta{Name: "leased", Namespace: "team",
+		Annotations: map[string]string{WriterAnnotation: "audicia/src"}}}
+	other := &audiciav1alpha1.AudiciaReport{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "team"}}
+	policy := &audiciav1alpha1.AudiciaPolicy{ObjectMeta: metav1.ObjectMeta{Name: "owned", Namespace: "audicia", OwnerReferences: owner}}
+	r := newTestReconciler(source, owned, leased, other, policy)
+	e := &StorageEstimator{Client: r.Client, Interval: time.Minute}
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := e.Estimate(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+
+	var got audiciav1alpha1.AudiciaSource
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "src", Namespace: "audicia"}, &got); err != nil {
+		t.Fatal(err)
+	}
+	est := got.Status.Storage
+	if est == nil || est.Reports != 2 || est.Policies != 1 || !est.EstimatedTime.Time.Equal(now) {
+		t.Fatalf("status.storage = %+v, want 2 reports and 1 policy", est)
+	}
+	reports := testutil.ToFloat64(metrics.StorageBytes.WithLabelValues("AudiciaReport", "team"))
+	if reports <= 0 {
+		t.Errorf("audicia_storage_bytes of reports in team = %v, want > 0", reports)
+	}
+	if n := testutil.ToFloat64(metrics.StorageObjects.WithLabelValues("AudiciaReport", "team")); n != 2 {
+		t.Errorf("audicia_storage_objects of reports in team = %v, want 2", n)
+	}
+	if est.Bytes <= objectSize(owned)+objectSize(leased)+objectSize(policy) {
+		t.Errorf("status.storage.bytes = %d, want the source, its reports and its policy", est.Bytes)
+	}
+}
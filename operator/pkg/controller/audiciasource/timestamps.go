@@ -0,0 +1,152 @@
+package audiciasource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+const (
+	// eventTimestampsCondition is False while a significant share of the
+	// source's events carry no timestamp.
+	eventTimestampsCondition = "EventTimestamps"
+
+	// missingTimestampsShare is the share of events without a timestamp at
+	// which the EventTimestamps condition turns False.
+	missingTimestampsShare = 0.05
+
+	// missingTimestampsMinEvents is the number of events needed before the
+	// share is judged, so a handful of odd events does not flip the
+	// condition.
+	missingTimestampsMinEvents = 100
+
+	// timestampWindow bounds the event count: above it, both counts are
+	// halved, so the share follows recent traffic.
+	timestampWindow = 100000
+)
+
+// requestTime returns when the API server received the request of event,
+// falling back to the stage timestamp. It returns false when the event
+// carries neither, as some cloud exports and hand-written test events do.
+func requestTime(event auditv1.Event) (time.Time, bool) {
+	if !event.RequestReceivedTimestamp.IsZero() {
+		return event.RequestReceivedTimestamp.Time, true
+	}
+	if !event.StageTimestamp.IsZero() {
+		return event.StageTimestamp.Time, true
+	}
+	return time.Time{}, false
+}
+
+// timestampTracker counts the events of a source that carry no timestamp.
+// It is owned by the pipeline goroutine.
+type timestampTracker struct {
+	events      int64
+	missing     int64
+	lastMissing time.Time
+	dirty       bool
+
+	// reported is the condition status last written, "" before the first
+	// write.
+	reported metav1.ConditionStatus
+}
+
+// newTimestampTracker restores the counts in status.eventTimestamps, so they
+// carry over restarts.
+func newTimestampTracker(source audiciav1alpha1.AudiciaSource) *timestampTracker {
+	t := &timestampTracker{}
+	if prev := source.Status.EventTimestamps; prev != nil {
+		t.events, t.missing = prev.Events, prev.Missing
+		if prev.LastMissingTime != nil {
+			t.lastMissing = prev.LastMissingTime.Time
+		}
+	}
+	if c := meta.FindStatusCondition(source.Status.Conditions, eventTimestampsCondition); c != nil {
+		t.reported = c.Status
+	}
+	return t
+}
+
+// observe counts one event.
+func (t *timestampTracker) observe(event auditv1.Event, now time.Time) {
+	t.events++
+	if _, ok := requestTime(event); !ok {
+		t.missing++
+		t.lastMissing = now
+	}
+	if t.events > timestampWindow {
+		t.events /= 2
+		t.missing /= 2
+	}
+	// Once events without a timestamp were seen, every count is persisted
+	// so the share stays accurate.
+	t.dirty = t.dirty || t.missing > 0
+}
+
+// significant reports whether the share of events without a timestamp is
+// high enough to flag.
+func (t *timestampTracker) significant() bool {
+	return t.events >= missingTimestampsMinEvents &&
+		float64(t.missing) >= missingTimestampsShare*float64(t.events)
+}
+
+// condition returns the EventTimestamps condition for the current counts.
+func (t *timestampTracker) condition() metav1.Condition {
+	if !t.significant() {
+		return metav1.Condition{
+			Type:    eventTimestampsCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Present",
+			Message: "Events carry timestamps; reports record when requests were made.",
+		}
+	}
+	return metav1.Condition{
+		Type:   eventTimestampsCondition,
+		Status: metav1.ConditionFalse,
+		Reason: "TimestampsMissing",
+		Message: fmt.Sprintf("%d of the last %d events carried no timestamp; their rules record the processing time "+
+			"and are marked timestampFallback.", t.missing, t.events),
+	}
+}
+
+// recordEventTimestamps persists status.eventTimestamps and the
+// EventTimestamps condition once events without a timestamp have been seen,
+// so sources whose events all carry timestamps are left alone.
+func (r *Reconciler) recordEventTimestamps(ctx context.Context, key types.NamespacedName, t *timestampTracker) {
+	cond := t.condition()
+	if !t.dirty && (t.reported == "" || t.reported == cond.Status) {
+		return
+	}
+	logger := ctrl.Log.WithName("pipeline").WithValues("source", key)
+	status := &audiciav1alpha1.EventTimestampsStatus{Events: t.events, Missing: t.missing}
+	if !t.lastMissing.IsZero() {
+		status.LastMissingTime = &metav1.Time{Time: t.lastMissing}
+	}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var source audiciav1alpha1.AudiciaSource
+		if err := r.Get(ctx, key, &source); err != nil {
+			return err
+		}
+		source.Status.EventTimestamps = status
+		cond.ObservedGeneration = source.Generation
+		meta.SetStatusCondition(&source.Status.Conditions, cond)
+		return r.Status().Update(ctx, &source)
+	})
+	switch {
+	case err == nil:
+		t.dirty = false
+		t.reported = cond.Status
+	case !errors.IsNotFound(err):
+		logger.Error(err, "failed to record event timestamps")
+	}
+}
@@ -0,0 +1,82 @@
+package audiciasource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func TestRequestTime(t *testing.T) {
+	received := metav1.NewMicroTime(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+	stage := metav1.NewMicroTime(time.Date(2025, 1, 1, 10, 0, 1, 0, time.UTC))
+
+	if got, ok := requestTime(auditv1.Event{RequestReceivedTimestamp: received, StageTimestamp: stage}); !ok || !got.Equal(received.Time) {
+		t.Errorf("requestTime() = %v, %v, want the received timestamp", got, ok)
+	}
+	if got, ok := requestTime(auditv1.Event{StageTimestamp: stage}); !ok || !got.Equal(stage.Time) {
+		t.Errorf("requestTime() = %v, %v, want the stage timestamp", got, ok)
+	}
+	if _, ok := requestTime(auditv1.Event{}); ok {
+		t.Error("requestTime() reported a timestamp for an event without one")
+	}
+}
+
+func TestRecordEventTimestamps(t *testing.T) {
+	source := &audiciav1alpha1.AudiciaSource{ObjectMeta: metav1.ObjectMeta{Name: "src", Namespace: "default"}}
+	r := newTestReconciler(source)
+	key := types.NamespacedName{Name: "src", Namespace: "default"}
+	get := func() audiciav1alpha1.AudiciaSource {
+		t.Helper()
+		var got audiciav1alpha1.AudiciaSource
+		if err := r.Get(context.Background(), key, &got); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+	timed := auditv1.Event{RequestReceivedTimestamp: metav1.NewMicroTime(time.Now())}
+	now := time.Now()
+
+	// Sources whose events all carry timestamps are left alone.
+	tracker := newTimestampTracker(*source)
+	for range missingTimestampsMinEvents {
+		tracker.observe(timed, now)
+	}
+	r.recordEventTimestamps(context.Background(), key, tracker)
+	if got := get(); got.Status.EventTimestamps != nil || len(got.Status.Conditions) != 0 {
+		t.Fatalf("status = %+v, want untouched", got.Status)
+	}
+
+	for range 10 {
+		tracker.observe(auditv1.Event{}, now)
+	}
+	r.recordEventTimestamps(context.Background(), key, tracker)
+	got := get()
+	if s := got.Status.EventTimestamps; s == nil || s.Events != 110 || s.Missing != 10 || s.LastMissingTime == nil {
+		t.Fatalf("status.eventTimestamps = %+v, want 10 of 110 missing", s)
+	}
+	if c := meta.FindStatusCondition(got.Status.Conditions, eventTimestampsCondition); c == nil || c.Status != metav1.ConditionFalse {
+		t.Fatalf("condition = %+v, want False", c)
+	}
+
+	// The counts carry over restarts, and the condition clears once events
+	// carry timestamps again.
+	tracker = newTimestampTracker(got)
+	for range 200 {
+		tracker.observe(timed, now)
+	}
+	r.recordEventTimestamps(context.Background(), key, tracker)
+	got = get()
+	if s := got.Status.EventTimestamps; s.Events != 310 || s.Missing != 10 {
+		t.Errorf("status.eventTimestamps = %+v, want 10 of 310 missing", s)
+	}
+	if c := meta.FindStatusCondition(got.Status.Conditions, eventTimestampsCondition); c == nil || c.Status != metav1.ConditionTrue {
+		t.Errorf("condition = %+v, want True", c)
+	}
+}
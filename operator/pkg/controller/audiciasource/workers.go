@@ -0,0 +1,247 @@
+package audiciasource
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/filter"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+)
+
+// workerQueueSize is the number of events or rules buffered per worker.
+const workerQueueSize = 256
+
+// eventWorkers spreads the events of a source over several cores. Event
+// workers, picked by a hash of the username so each user's sampling state
+// stays on one worker, run the bus up to aggregation. They hand each rule to
+// the aggregator shard picked by a hash of its subject key; a shard owns the
+// aggregators of its subjects, so they are only ever written by one
+// goroutine and need no locking.
+//
+// The pipeline goroutine reads and prunes the aggregators between sync and
+// the next dispatch, while the workers are idle. With a single worker,
+// events are processed on the pipeline goroutine and sync is a no-op.
+//
+// spec.limits.maxSubjects is split between the shards, each evicting its own
+// least recently active subjects. The shares add up to the cap; with a cap
+// below the number of workers, only that many shards are started.
+type eventWorkers struct {
+	// inline processes events on the caller's goroutine.
+	inline *eventBus
+	lru    *subjectLRU
+
+	filtered *filteredTracker
+	queues   []chan auditv1.Event
+	shards   []*aggregatorShard
+
+	// inflight counts the dispatched events and routed rules not yet
+	// processed.
+	inflight sync.WaitGroup
+	workers  sync.WaitGroup
+}
+
+// aggregatorShard owns the aggregators of the subjects hashed to it.
+type aggregatorShard struct {
+	rules       chan shardRule
+	aggregators map[subjectKey]*aggregator.Aggregator
+	subjects    map[subjectKey]audiciav1alpha1.Subject
+	lru         *subjectLRU
+}
+
+// shardRule is a rule routed to the shard of its subject.
+type shardRule struct {
+	subject audiciav1alpha1.Subject
+	rule    normalizer.CanonicalRule
+	time    time.Time
+	weight  int64
+}
+
+// newEventWorkers starts n workers and n shards, or as many shards as
+// spec.limits.maxSubjects if that is lower. With n <= 1, events are
+// aggregated into aggregators directly.
+func (r *Reconciler) newEventWorkers(
+	n int,
+	source audiciav1alpha1.AudiciaSource,
+	filterChain *filter.Chain,
+	aliases *normalizer.SubjectAliases,
+	groups *normalizer.GroupTracker,
+	aggregators map[subjectKey]*aggregator.Aggregator,
+	subjects map[subjectKey]audiciav1alpha1.Subject,
+	filtered *filteredTracker,
+) *eventWorkers {
+	w := &eventWorkers{filtered: filtered}
+	key := types.NamespacedName{Namespace: source.Namespace, Name: source.Name}
+	maxSubjects := int(source.Spec.Limits.MaxSubjects)
+	if n <= 1 {
+		w.lru = newSubjectLRU(maxSubjects, key)
+		w.inline = r.newEventBus(source, filterChain, aliases, groups, aggregateRule(aggregators, subjects, w.lru))
+		return w
+	}
+
+	shards := n
+	if maxSubjects > 0 {
+		shards = min(n, maxSubjects)
+	}
+	w.shards = make([]*aggregatorShard, shards)
+	for i := range w.shards {
+		shard := &aggregatorShard{
+			rules:       make(chan shardRule, workerQueueSize),
+			aggregators: make(map[subjectKey]*aggregator.Aggregator),
+			subjects:    make(map[subjectKey]audiciav1alpha1.Subject),
+			lru:         newSubjectLRU(shardShare(maxSubjects, shards, i), key),
+		}
+		w.shards[i] = shard
+		go w.runShard(shard)
+	}
+	w.queues = make([]chan auditv1.Event, n)
+	for i := range w.queues {
+		w.queues[i] = make(chan auditv1.Event, workerQueueSize)
+		bus := r.newEventBus(source, filterChain, aliases, groups, w.route)
+		w.workers.Add(1)
+		go w.runWorker(w.queues[i], bus)
+	}
+	return w
+}
+
+// dispatch hands event to the worker of its user.
+func (w *eventWorkers) dispatch(event auditv1.Event) {
+	if w.inline != nil {
+		if w.inline.publish(event) == filterRuleDeny {
+			w.filtered.observe(event.User.Username, eventNamespace(event))
+		}
+		return
+	}
+	w.inflight.Add(1)
+	w.queues[hashIndex(len(w.queues), event.User.Username)] <- event
+}
+
+func (w *eventWorkers) runWorker(queue <-chan auditv1.Event, bus *eventBus) {
+	defer w.workers.Done()
+	for event := range queue {
+		if bus.publish(event) == filterRuleDeny {
+			w.filtered.observe(event.User.Username, eventNamespace(event))
+		}
+		w.inflight.Done()
+	}
+}
+
+// route is the aggregate processor of the workers' buses.
+func (w *eventWorkers) route(e *busEvent) string {
+	for _, s := range e.subjects() {
+		shard := w.shardFor(keyFor(s))
+		w.inflight.Add(1)
+		shard.rules <- shardRule{subject: s, rule: e.rule, time: e.time, weight: e.weight}
+	}
+	return ""
+}
+
+// shardFor returns the shard owning the aggregator of sk.
+func (w *eventWorkers) shardFor(sk subjectKey) *aggregatorShard {
+	return w.shards[hashIndex(len(w.shards), string(sk.kind), sk.namespace, sk.name)]
+}
+
+func (w *eventWorkers) runShard(shard *aggregatorShard) {
+	for r := range shard.rules {
+		shard.lru.touch(keyFor(r.subject), shard.aggregators, shard.subjects)
+		aggregate(shard.aggregators, shard.subjects, r.subject, r.rule, r.time, r.weight)
+		w.inflight.Done()
+	}
+}
+
+// shardShare returns shard i's share of maxSubjects split between n shards:
+// an even split, with the first maxSubjects%n shards taking one more.
+func shardShare(maxSubjects, n, i int) int {
+	share := maxSubjects / n
+	if i < maxSubjects%n {
+		share++
+	}
+	return share
+}
+
+// sync waits until the dispatched events are aggregated and mirrors the
+// shards' subjects in aggregators: those created since the last sync are
+// added, those evicted removed. Evicted subjects awaiting their flush are
+// added until drained.
+func (w *eventWorkers) sync(aggregators map[subjectKey]*aggregator.Aggregator, subjects map[subjectKey]audiciav1alpha1.Subject) {
+	if w.inline != nil {
+		w.lru.addDraining(aggregators, subjects)
+		return
+	}
+	w.inflight.Wait()
+	for sk := range aggregators {
+		if _, ok := w.shardFor(sk).aggregators[sk]; !ok {
+			delete(aggregators, sk)
+			delete(subjects, sk)
+		}
+	}
+	for _, shard := range w.shards {
+		for sk, agg := range shard.aggregators {
+			if aggregators[sk] != agg {
+				aggregators[sk] = agg
+				subjects[sk] = shard.subjects[sk]
+			}
+		}
+		shard.lru.addDraining(aggregators, subjects)
+	}
+}
+
+// drained removes the evicted subjects added by sync from aggregators once
+// their report was written, as reported by written.
+func (w *eventWorkers) drained(aggregators map[subjectKey]*aggregator.Aggregator, subjects map[subjectKey]audiciav1alpha1.Subject, written func(subjectKey, *aggregator.Aggregator) bool) {
+	w.lru.drained(aggregators, subjects, written)
+	for _, shard := range w.shards {
+		shard.lru.drained(aggregators, subjects, written)
+	}
+}
+
+// retain drops the subjects removed from aggregators since sync (e.g.
+// expired) from the shards, so new events start them over.
+func (w *eventWorkers) retain(aggregators map[subjectKey]*aggregator.Aggregator) {
+	w.lru.retain(aggregators)
+	for _, shard := range w.shards {
+		for sk := range shard.aggregators {
+			if _, ok := aggregators[sk]; !ok {
+				delete(shard.aggregators, sk)
+				delete(shard.subjects, sk)
+			}
+		}
+		shard.lru.retain(shard.aggregators)
+	}
+}
+
+// evictions returns the number of subjects evicted by
+// spec.limits.maxSubjects. Only call it after sync.
+func (w *eventWorkers) evictions() int64 {
+	n := w.lru.evictions()
+	for _, shard := range w.shards {
+		n += shard.lru.evictions()
+	}
+	return n
+}
+
+// stop lets the workers finish the dispatched events and exit.
+func (w *eventWorkers) stop() {
+	for _, q := range w.queues {
+		close(q)
+	}
+	w.workers.Wait()
+	for _, shard := range w.shards {
+		close(shard.rules)
+	}
+}
+
+// hashIndex maps parts to one of n slots.
+func hashIndex(n int, parts ...string) int {
+	h := fnv.New32a()
+	for _, p := range parts {
+		_, _ = h.Write([]byte(p))
+		_, _ = h.Write([]byte{0})
+	}
+	return int(h.Sum32() % uint32(n))
+}
@@ -0,0 +1,104 @@
+package audiciasource
+
+import (
+	"fmt"
+	"testing"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/filter"
+)
+
+// workerEvents returns n get events spread over a few users and resources.
+func workerEvents(n int) []auditv1.Event {
+	events := make([]auditv1.Event, n)
+	for i := range events {
+		events[i] = auditv1.Event{
+			Stage:     auditv1.StageResponseComplete,
+			Verb:      "get",
+			User:      authnv1.UserInfo{Username: fmt.Sprintf("user-%d", i%7)},
+			ObjectRef: &auditv1.ObjectReference{Resource: []string{"pods", "secrets", "configmaps"}[i%3], Namespace: "default", APIVersion: "v1"},
+		}
+	}
+	return events
+}
+
+// ruleCounts sums the rule counts per subject.
+func ruleCounts(aggregators map[subjectKey]*aggregator.Aggregator) map[subjectKey]int64 {
+	counts := make(map[subjectKey]int64)
+	for sk, agg := range aggregators {
+		for _, rule := range agg.Rules() {
+			counts[sk] += rule.Count
+		}
+	}
+	return counts
+}
+
+func TestEventWorkers_MatchInlineAggregation(t *testing.T) {
+	r := newTestReconciler()
+	source := audiciav1alpha1.AudiciaSource{Spec: audiciav1alpha1.AudiciaSourceSpec{SourceType: audiciav1alpha1.SourceTypeK8sAuditLog}}
+	chain, _ := filter.NewChain(nil)
+	events := workerEvents(1000)
+
+	results := make(map[int]map[subjectKey]int64)
+	for _, n := range []int{1, 4} {
+		aggregators := make(map[subjectKey]*aggregator.Aggregator)
+		subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+		w := r.newEventWorkers(n, source, chain, nil, nil, aggregators, subjects, nil)
+		for _, event := range events {
+			w.dispatch(event)
+		}
+		w.sync(aggregators, subjects)
+		w.stop()
+		if len(subjects) != len(aggregators) {
+			t.Errorf("n=%d: %d subjects for %d aggregators", n, len(subjects), len(aggregators))
+		}
+		results[n] = ruleCounts(aggregators)
+	}
+
+	if len(results[1]) != 7 {
+		t.Fatalf("inline subjects = %d, want 7", len(results[1]))
+	}
+	for sk, want := range results[1] {
+		if got := results[4][sk]; got != want {
+			t.Errorf("%v: count = %d with 4 workers, want %d", sk, got, want)
+		}
+	}
+}
+
+func TestEventWorkers_RetainDropsExpiredSubjects(t *testing.T) {
+	r := newTestReconciler()
+	source := audiciav1alpha1.AudiciaSource{Spec: audiciav1alpha1.AudiciaSourceSpec{SourceType: audiciav1alpha1.SourceTypeK8sAuditLog}}
+	chain, _ := filter.NewChain(nil)
+	aggregators := make(map[subjectKey]*aggregator.Aggregator)
+	subjects := make(map[subjectKey]audiciav1alpha1.Subject)
+	w := r.newEventWorkers(3, source, chain, nil, nil, aggregators, subjects, nil)
+	defer w.stop()
+
+	event := workerEvents(1)[0]
+	w.dispatch(event)
+	w.sync(aggregators, subjects)
+	if len(aggregators) != 1 {
+		t.Fatalf("aggregators = %d, want 1", len(aggregators))
+	}
+
+	// Expire the subject, as expireReports does.
+	clear(aggregators)
+	clear(subjects)
+	w.retain(aggregators)
+
+	w.dispatch(event)
+	w.sync(aggregators, subjects)
+	if counts := ruleCounts(aggregators); len(counts) != 1 {
+		t.Fatalf("aggregators = %d, want the subject to start over", len(counts))
+	} else {
+		for sk, n := range counts {
+			if n != 1 {
+				t.Errorf("%v: count = %d, want 1 after expiry", sk, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package audiciasource
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+const (
+	// WriterAnnotation names the AudiciaSource ("namespace/name") holding the
+	// write lease on an AudiciaReport.
+	WriterAnnotation = "audicia.io/writer"
+	// WriterRenewedAnnotation records when the lease was last renewed (RFC 3339).
+	WriterRenewedAnnotation = "audicia.io/writer-renewed"
+
+	// writerLeaseDuration is how long a lease stays valid without renewal.
+	// A source that stops flushing a subject for this long gives the report
+	// up to the next source that flushes it.
+	writerLeaseDuration = 10 * time.Minute
+	// writerRenewInterval limits how often the holder rewrites the renewal
+	// annotation, so steady flushes do not touch report metadata every time.
+	writerRenewInterval = writerLeaseDuration / 5
+
+	// degradedCondition is set while another source holds a report this
+	// source would write.
+	degradedCondition = "Degraded"
+)
+
+// reportContendedError is returned when another source holds the write lease
+// on a report.
+type reportContendedError struct {
+	report string
+	holder string
+}
+
+func (e *reportContendedError) Error() string {
+	return fmt.Sprintf("report %s is written by AudiciaSource %s", e.report, e.holder)
+}
+
+// acquireWriter takes or renews the write lease on report for source. Unless
+// sources merge reports (spec.reportMerge: Union), only one source may write
+// a report: a lease held by another source blocks the write until it
+// expires, instead of the sources overwriting each other's ObservedRules on
+// alternate flushes.
+func acquireWriter(report *audiciav1alpha1.AudiciaReport, source types.NamespacedName, now time.Time) error {
+	self := source.String()
+	holder := report.Annotations[WriterAnnotation]
+	renewed, err := time.Parse(time.RFC3339, report.Annotations[WriterRenewedAnnotation])
+	if err != nil {
+		renewed = time.Time{}
+	}
+
+	if holder != "" && holder != self && now.Before(renewed.Add(writerLeaseDuration)) {
+		return &reportContendedError{report: report.Namespace + "/" + report.Name, holder: holder}
+	}
+	if holder == self && now.Before(renewed.Add(writerRenewInterval)) {
+		return nil
+	}
+
+	if report.Annotations == nil {
+		report.Annotations = make(map[string]string, 2)
+	}
+	report.Annotations[WriterAnnotation] = self
+	report.Annotations[WriterRenewedAnnotation] = now.UTC().Format(time.RFC3339)
+	return nil
+}
+
+// claimReport prepares report to be written by source. Exclusive sources
+// take the write lease. Union sources share the report and take no lease, so
+// they only wait for a lease held by an Exclusive source, and give up a
+// lease they took before switching to Union.
+func claimReport(report *audiciav1alpha1.AudiciaReport, source audiciav1alpha1.AudiciaSource, key types.NamespacedName, now time.Time) error {
+	if source.Spec.ReportMerge != audiciav1alpha1.ReportMergeUnion {
+		return acquireWriter(report, key, now)
+	}
+	holder := report.Annotations[WriterAnnotation]
+	if holder == key.String() {
+		delete(report.Annotations, WriterAnnotation)
+		delete(report.Annotations, WriterRenewedAnnotation)
+		return nil
+	}
+	renewed, err := time.Parse(time.RFC3339, report.Annotations[WriterRenewedAnnotation])
+	if holder != "" && err == nil && now.Before(renewed.Add(writerLeaseDuration)) {
+		return &reportContendedError{report: report.Namespace + "/" + report.Name, holder: holder}
+	}
+	return nil
+}
+
+// mergeReportSources records the flush of a Union source, with the hash of
+// its configuration, in status.sources. Sources in a merge usually see the
+// same requests (a file and a webhook source of one cluster), so like rule
+// counts, the report's event count is the highest any source reported, and
+// the activity summary is that of the busiest source. It returns the event
+// count and activity to write.
+func mergeReportSources(
+	report *audiciav1alpha1.AudiciaReport,
+	key types.NamespacedName,
+	eventsProcessed int64,
+	activity *audiciav1alpha1.ActivitySummary,
+	configHash string,
+	now time.Time,
+) (int64, *audiciav1alpha1.ActivitySummary) {
+	entry := audiciav1alpha1.ReportSource{
+		Name:              key.String(),
+		EventsProcessed:   eventsProcessed,
+		LastFlushTime:     metav1.NewTime(now),
+		ConfigurationHash: configHash,
+	}
+	i := slices.IndexFunc(report.Status.Sources, func(s audiciav1alpha1.ReportSource) bool { return s.Name == entry.Name })
+	if i < 0 {
+		report.Status.Sources = append(report.Status.Sources, entry)
+		slices.SortFunc(report.Status.Sources, func(a, b audiciav1alpha1.ReportSource) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+	} else {
+		report.Status.Sources[i] = entry
+	}
+
+	busiest := eventsProcessed
+	for _, s := range report.Status.Sources {
+		busiest = max(busiest, s.EventsProcessed)
+	}
+	if busiest > eventsProcessed && report.Status.Activity != nil {
+		activity = report.Status.Activity
+	}
+	return busiest, activity
+}
+
+// writtenBy reports whether source writes report, holding its lease or
+// merged into it.
+func writtenBy(report *audiciav1alpha1.AudiciaReport, source types.NamespacedName) bool {
+	self := source.String()
+	return report.Annotations[WriterAnnotation] == self ||
+		slices.ContainsFunc(report.Status.Sources, func(s audiciav1alpha1.ReportSource) bool { return s.Name == self })
+}
+
+// reportContention tracks the subjects whose reports another source holds.
+// It is owned by a single pipeline goroutine and is not safe for concurrent
+// use.
+type reportContention struct {
+	holders map[subjectKey]string // subject → holding source
+}
+
+func newReportContention() *reportContention {
+	return &reportContention{holders: make(map[subjectKey]string)}
+}
+
+// update applies a flush result and returns the subjects that became
+// contended with it.
+func (c *reportContention) update(result flushResult) []subjectKey {
+	for _, key := range result.succeeded {
+		delete(c.holders, key)
+	}
+	for _, key := range result.failed {
+		delete(c.holders, key)
+	}
+	var added []subjectKey
+	for key, holder := range result.contended {
+		if _, ok := c.holders[key]; !ok {
+			added = append(added, key)
+		}
+		c.holders[key] = holder
+	}
+	slices.SortFunc(added, compareSubjectKeys)
+	return added
+}
+
+// condition returns the Degraded condition for the current contention, and
+// false if there is none to report.
+func (c *reportContention) condition() (metav1.Condition, bool) {
+	if len(c.holders) == 0 {
+		return metav1.Condition{
+			Type:    degradedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "ReportsOwned",
+			Message: "This source holds the write lease on all of its reports.",
+		}, false
+	}
+
+	keys := make([]subjectKey, 0, len(c.holders))
+	for key := range c.holders {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, compareSubjectKeys)
+	listed := keys
+	if len(listed) > maxFailedSubjectsInMessage {
+		listed = listed[:maxFailedSubjectsInMessage]
+	}
+	parts := make([]string, 0, len(listed))
+	for _, key := range listed {
+		parts = append(parts, fmt.Sprintf("%s (held by %s)", key, c.holders[key]))
+	}
+	msg := fmt.Sprintf("%d subject(s) are not written because another AudiciaSource holds their report: %s",
+		len(keys), strings.Join(parts, ", "))
+	if more := len(keys) - len(listed); more > 0 {
+		msg += fmt.Sprintf(" (and %d more)", more)
+	}
+	return metav1.Condition{
+		Type:    degradedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ReportContended",
+		Message: msg,
+	}, true
+}
+
+// recordContention publishes report contention in the Degraded condition and
+// emits a ReportContended event for each newly contended subject. The
+// condition is only added once contention has been seen.
+func (r *Reconciler) recordContention(
+	ctx context.Context,
+	key types.NamespacedName,
+	result flushResult,
+	contention *reportContention,
+) {
+	logger := ctrl.Log.WithName("pipeline").WithValues("source", key)
+	added := contention.update(result)
+	condition, contended := contention.condition()
+
+	var source audiciav1alpha1.AudiciaSource
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := r.Get(ctx, key, &source); err != nil {
+			return err
+		}
+		existing := meta.FindStatusCondition(source.Status.Conditions, degradedCondition)
+		if existing == nil && !contended {
+			return nil
+		}
+		if existing != nil && existing.Status == condition.Status && existing.Message == condition.Message {
+			return nil
+		}
+		condition.ObservedGeneration = source.Generation
+		meta.SetStatusCondition(&source.Status.Conditions, condition)
+		return r.Status().Update(ctx, &source)
+	})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error(err, "failed to record report contention")
+		}
+		return
+	}
+
+	for _, sk := range added {
+		r.Recorder.Eventf(&source, nil, corev1.EventTypeWarning, "ReportContended", "Flush",
+			"Not writing report for %s: AudiciaSource %s holds its write lease",
+			sk, contention.holders[sk])
+	}
+}
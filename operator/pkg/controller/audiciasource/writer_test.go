@@ -0,0 +1,273 @@
+package audiciasource
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+
+	"github.com/felixnotka/audicia/operator/pkg/aggregator"
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+	"github.com/felixnotka/audicia/operator/pkg/strategy"
+)
+
+func TestAcquireWriter(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	self := types.NamespacedName{Namespace: "audicia-system", Name: "a"}
+	lease := func(holder string, renewed time.Time) map[string]string {
+		return map[string]string{
+			WriterAnnotation:        holder,
+			WriterRenewedAnnotation: renewed.Format(time.RFC3339),
+		}
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+		wantRenewed time.Time
+	}{
+		{"unclaimed", nil, false, now},
+		{"held by self, fresh", lease("audicia-system/a", now.Add(-time.Minute)), false, now.Add(-time.Minute)},
+		{"held by self, due for renewal", lease("audicia-system/a", now.Add(-writerRenewInterval)), false, now},
+		{"held by other, fresh", lease("team/b", now.Add(-time.Minute)), true, now.Add(-time.Minute)},
+		{"held by other, expired", lease("team/b", now.Add(-writerLeaseDuration)), false, now},
+		{"held by other, unparsable renewal", map[string]string{WriterAnnotation: "team/b", WriterRenewedAnnotation: "soon"}, false, now},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &audiciav1alpha1.AudiciaReport{
+				ObjectMeta: metav1.ObjectMeta{Name: "report-x", Namespace: "team", Annotations: tt.annotations},
+			}
+			err := acquireWriter(report, self, now)
+			var contended *reportContendedError
+			if tt.wantErr != errors.As(err, &contended) {
+				t.Fatalf("acquireWriter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if contended.holder != "team/b" {
+					t.Errorf("holder = %q, want team/b", contended.holder)
+				}
+				if report.Annotations[WriterAnnotation] != "team/b" {
+					t.Errorf("lease must not change hands, got %q", report.Annotations[WriterAnnotation])
+				}
+			} else if report.Annotations[WriterAnnotation] != "audicia-system/a" {
+				t.Errorf("writer = %q, want audicia-system/a", report.Annotations[WriterAnnotation])
+			}
+			if got := report.Annotations[WriterRenewedAnnotation]; got != tt.wantRenewed.Format(time.RFC3339) {
+				t.Errorf("renewed = %q, want %s", got, tt.wantRenewed.Format(time.RFC3339))
+			}
+		})
+	}
+}
+
+func TestReportContention(t *testing.T) {
+	c := newReportContention()
+	a, b := userKey("a"), userKey("b")
+	if _, contended := c.condition(); contended {
+		t.Fatal("expected no contention initially")
+	}
+
+	added := c.update(flushResult{contended: map[subjectKey]string{b: "ns/other", a: "ns/other"}})
+	if len(added) != 2 || added[0] != a {
+		t.Errorf("expected [a b] newly contended, got %v", added)
+	}
+	if added := c.update(flushResult{contended: map[subjectKey]string{a: "ns/other"}}); len(added) != 0 {
+		t.Errorf("expected no newly contended subjects, got %v", added)
+	}
+
+	cond, contended := c.condition()
+	if !contended || cond.Reason != "ReportContended" || !strings.Contains(cond.Message, "User/a (held by ns/other)") {
+		t.Errorf("unexpected condition %+v", cond)
+	}
+
+	c.update(flushResult{succeeded: []subjectKey{a}, failed: []subjectKey{b}})
+	if cond, contended := c.condition(); contended || cond.Status != metav1.ConditionFalse {
+		t.Errorf("expected contention to clear, got %+v", cond)
+	}
+}
+
+func TestFlushReports_ContendedReport(t *testing.T) {
+	sourceA := &audiciav1alpha1.AudiciaSource{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "audicia-system"}}
+	sourceB := &audiciav1alpha1.AudiciaSource{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "audicia-system"}}
+	r := newTestReconciler(sourceA, sourceB)
+	engine := strategy.NewEngine(audiciav1alpha1.PolicyStrategy{})
+
+	// Both sources observe the same ServiceAccount, whose report lives in its
+	// own namespace.
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "shared", Namespace: "team"}
+	sk := keyFor(subject)
+	flush := func(source *audiciav1alpha1.AudiciaSource, verb string) flushResult {
+		agg := aggregator.New()
+		agg.Add(normalizer.CanonicalRule{Resource: "pods", Verb: verb, Namespace: "team"}, time.Now())
+		key := types.NamespacedName{Name: source.Name, Namespace: source.Namespace}
+		return r.flushReports(context.Background(), key, *source, engine,
+			map[subjectKey]*aggregator.Aggregator{sk: agg}, map[subjectKey]audiciav1alpha1.Subject{sk: subject})
+	}
+
+	if result := flush(sourceA, "get"); len(result.succeeded) != 1 {
+		t.Fatalf("expected source a to write the report, got %+v", result)
+	}
+	result := flush(sourceB, "delete")
+	if result.contended[sk] != "audicia-system/a" || len(result.succeeded)+len(result.failed) != 0 {
+		t.Fatalf("expected source b to be contended, got %+v", result)
+	}
+
+	var report audiciav1alpha1.AudiciaReport
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "report-shared", Namespace: "team"}, &report); err != nil {
+		t.Fatalf("get report: %v", err)
+	}
+	if len(report.Status.ObservedRules) != 1 || report.Status.ObservedRules[0].Verbs[0] != "get" {
+		t.Errorf("expected source a's rules to remain, got %+v", report.Status.ObservedRules)
+	}
+
+	keyB := types.NamespacedName{Name: "b", Namespace: "audicia-system"}
+	contention := newReportContention()
+	r.recordContention(context.Background(), keyB, result, contention)
+	var updated audiciav1alpha1.AudiciaSource
+	if err := r.Get(context.Background(), keyB, &updated); err != nil {
+		t.Fatalf("get source: %v", err)
+	}
+	if !meta.IsStatusConditionTrue(updated.Status.Conditions, degradedCondition) {
+		t.Errorf("expected Degraded=True, got %+v", updated.Status.Conditions)
+	}
+	if evts := drainEvents(r.Recorder.(*events.FakeRecorder)); !containsEvent(evts, "ReportContended") {
+		t.Errorf("expected ReportContended event, got %v", evts)
+	}
+
+	// Once source a's lease expires, source b takes over and recovers.
+	report.Annotations[WriterRenewedAnnotation] = time.Now().Add(-writerLeaseDuration).UTC().Format(time.RFC3339)
+	if err := r.Update(context.Background(), &report); err != nil {
+		t.Fatalf("update report: %v", err)
+	}
+	result = flush(sourceB, "delete")
+	if len(result.succeeded) != 1 {
+		t.Fatalf("expected source b to take over, got %+v", result)
+	}
+	r.recordContention(context.Background(), keyB, result, contention)
+	if err := r.Get(context.Background(), keyB, &updated); err != nil {
+		t.Fatalf("get source: %v", err)
+	}
+	if !meta.IsStatusConditionFalse(updated.Status.Conditions, degradedCondition) {
+		t.Errorf("expected Degraded=False, got %+v", updated.Status.Conditions)
+	}
+}
+
+func containsEvent(evts []string, reason string) bool {
+	for _, e := range evts {
+		if strings.Contains(e, reason) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestClaimReport_Union(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	self := types.NamespacedName{Namespace: "audicia-system", Name: "a"}
+	source := audiciav1alpha1.AudiciaSource{Spec: audiciav1alpha1.AudiciaSourceSpec{ReportMerge: audiciav1alpha1.ReportMergeUnion}}
+	lease := func(holder string, renewed time.Time) *audiciav1alpha1.AudiciaReport {
+		return &audiciav1alpha1.AudiciaReport{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			WriterAnnotation:        holder,
+			WriterRenewedAnnotation: renewed.Format(time.RFC3339),
+		}}}
+	}
+
+	if err := claimReport(&audiciav1alpha1.AudiciaReport{}, source, self, now); err != nil {
+		t.Errorf("unclaimed report: %v", err)
+	}
+
+	own := lease("audicia-system/a", now)
+	if err := claimReport(own, source, self, now); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := own.Annotations[WriterAnnotation]; ok {
+		t.Error("a Union source must give up its own lease")
+	}
+
+	var contended *reportContendedError
+	if err := claimReport(lease("team/b", now.Add(-time.Minute)), source, self, now); !errors.As(err, &contended) {
+		t.Errorf("lease of an Exclusive source: got %v, want contended", err)
+	}
+	if err := claimReport(lease("team/b", now.Add(-writerLeaseDuration)), source, self, now); err != nil {
+		t.Errorf("expired lease: %v", err)
+	}
+}
+
+func TestMergeReportSources(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	busy := &audiciav1alpha1.ActivitySummary{ByHour: make([]int64, 24), ByDay: make([]int64, 7)}
+	report := &audiciav1alpha1.AudiciaReport{}
+	report.Status.Sources = []audiciav1alpha1.ReportSource{{Name: "team/webhook", EventsProcessed: 10}}
+	report.Status.Activity = busy
+
+	mine := &audiciav1alpha1.ActivitySummary{}
+	events, activity := mergeReportSources(report, types.NamespacedName{Namespace: "team", Name: "file"}, 4, mine, "abc", now)
+	if events != 10 || activity != busy {
+		t.Errorf("got %d events and own activity %v; want the busiest source's", events, activity == mine)
+	}
+	if len(report.Status.Sources) != 2 || report.Status.Sources[0].Name != "team/file" ||
+		!report.Status.Sources[0].LastFlushTime.Time.Equal(now) || report.Status.Sources[0].ConfigurationHash != "abc" {
+		t.Errorf("sources = %+v", report.Status.Sources)
+	}
+	if !writtenBy(report, types.NamespacedName{Namespace: "team", Name: "file"}) {
+		t.Error("expected the merged source to count as a writer")
+	}
+
+	events, activity = mergeReportSources(report, types.NamespacedName{Namespace: "team", Name: "file"}, 12, mine, "abc", now)
+	if events != 12 || activity != mine || len(report.Status.Sources) != 2 {
+		t.Errorf("got %d events, sources %+v; want 12 and own activity", events, report.Status.Sources)
+	}
+}
+
+func TestFlushSubject_UnionMergesSources(t *testing.T) {
+	newSource := func(name string) audiciav1alpha1.AudiciaSource {
+		return audiciav1alpha1.AudiciaSource{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name)},
+			Spec:       audiciav1alpha1.AudiciaSourceSpec{ReportMerge: audiciav1alpha1.ReportMergeUnion},
+		}
+	}
+	file, webhook := newSource("file"), newSource("webhook")
+	r := newTestReconciler(&file, &webhook)
+	engine := strategy.NewEngine(audiciav1alpha1.PolicyStrategy{})
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "alice"}
+
+	flush := func(source audiciav1alpha1.AudiciaSource, verb string) {
+		t.Helper()
+		agg := aggregator.New()
+		agg.Add(normalizer.CanonicalRule{Resource: "pods", Verb: verb, Namespace: "default"}, time.Now())
+		if err := r.flushSubject(context.Background(), source, engine, subject, agg, true, logr.Discard()); err != nil {
+			t.Fatalf("flushSubject(%s): %v", source.Name, err)
+		}
+	}
+	flush(file, "get")
+	flush(webhook, "list")
+
+	var report audiciav1alpha1.AudiciaReport
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "report-alice", Namespace: "default"}, &report); err != nil {
+		t.Fatal(err)
+	}
+	var verbs []string
+	for _, rule := range report.Status.ObservedRules {
+		verbs = append(verbs, rule.Verbs...)
+	}
+	slices.Sort(verbs)
+	if strings.Join(verbs, ",") != "get,list" {
+		t.Errorf("observed verbs = %v, want the union [get list]", verbs)
+	}
+	if len(report.Status.Sources) != 2 {
+		t.Errorf("sources = %+v, want both", report.Status.Sources)
+	}
+	if len(report.OwnerReferences) != 2 {
+		t.Errorf("owners = %+v, want both sources", report.OwnerReferences)
+	}
+}
@@ -3,11 +3,13 @@
 package diff
 
 import (
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
 	"github.com/felixnotka/audicia/operator/pkg/rbac"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -32,11 +34,17 @@ var sensitiveResources = map[string]bool{
 	"storageclasses":                  true,
 	"customresourcedefinitions":       true,
 	"serviceaccounts/token":           true,
+	"pods/exec":                       true,
+	"pods/attach":                     true,
+	"pods/portforward":                true,
+	"pods/ephemeralcontainers":        true,
+	"nodes/proxy":                     true,
 }
 
 // Evaluate compares observed usage against effective permissions and returns
 // a ComplianceReport. The report captures how much of the granted RBAC is
-// actually being used, identifies excess grants, and flags sensitive resources.
+// actually being used, identifies excess grants, flags sensitive resources,
+// and plans the removal of the excess from existing bindings and roles.
 //
 // Score formula: usedEffective / totalEffective * 100
 //   - usedEffective = effective rules that were exercised by at least one observed action
@@ -100,6 +108,7 @@ func Evaluate(observed []audiciav1alpha1.ObservedRule, effective []rbac.ScopedRu
 		SensitiveExcess:    sensitiveExcess,
 		ExcessRules:        excessRules,
 		UncoveredRules:     uncoveredRules,
+		Remediation:        remediation(effective, used),
 		LastEvaluatedTime:  metav1.NewTime(time.Now()),
 	}
 }
@@ -182,19 +191,38 @@ func markUsed(obs audiciav1alpha1.ObservedRule, effective []rbac.ScopedRule, use
 		return
 	}
 
+	implied, hasImplied := impliedConnectRule(obs)
 	for i, eff := range effective {
-		if matchesResourceRule(obs, eff) {
+		if matchesResourceRule(obs, eff) || (hasImplied && matchesResourceRule(implied, eff)) {
 			used[i] = true
 		}
 	}
 }
 
+// impliedConnectRule returns the "create" rule Kubernetes 1.31 and later
+// also authorize for an observed "get" on websocket subresources
+// (pods/exec, pods/attach, pods/portforward). The audit log only records the
+// "get", so without it the grant of "create" would count as excess.
+func impliedConnectRule(obs audiciav1alpha1.ObservedRule) (audiciav1alpha1.ObservedRule, bool) {
+	if len(obs.Resources) == 0 || !slices.Contains(obs.Verbs, "get") {
+		return obs, false
+	}
+	for _, res := range obs.Resources {
+		if !normalizer.IsWebsocketSubresource(res) {
+			return obs, false
+		}
+	}
+	implied := obs
+	implied.Verbs = []string{"create"}
+	return implied, true
+}
+
 // matchesResourceRule checks whether a single effective rule covers the observed
 // resource rule, respecting namespace scoping and wildcards.
 //
 // Conservative choices:
-//   - ResourceNames-constrained rules are treated as NOT covering (audit events
-//     don't capture which specific resource was accessed, only the resource type).
+//   - ResourceNames-constrained rules only cover observed rules restricted to a
+//     subset of those names; rules observed without names are NOT covered.
 //   - Namespace-scoped rules only cover their own namespace; cluster-wide (ns="")
 //     rules cover all namespaces.
 func matchesResourceRule(obs audiciav1alpha1.ObservedRule, eff rbac.ScopedRule) bool {
@@ -204,9 +232,10 @@ func matchesResourceRule(obs audiciav1alpha1.ObservedRule, eff rbac.ScopedRule)
 		return false
 	}
 
-	// Effective rules with ResourceNames are more restrictive; we treat observed
-	// rules (which don't specify resource names) as NOT covered by them.
-	if len(eff.ResourceNames) > 0 {
+	// Effective rules with ResourceNames are more restrictive; they only cover
+	// observed rules whose every observation targeted one of those names.
+	if len(eff.ResourceNames) > 0 &&
+		(len(obs.ResourceNames) == 0 || !sliceCovers(eff.ResourceNames, obs.ResourceNames)) {
 		return false
 	}
 
@@ -214,7 +243,7 @@ func matchesResourceRule(obs audiciav1alpha1.ObservedRule, eff rbac.ScopedRule)
 	if !sliceCovers(eff.APIGroups, obs.APIGroups) {
 		return false
 	}
-	if !sliceCovers(eff.Resources, obs.Resources) {
+	if !resourcesCover(eff.Resources, obs.Resources) {
 		return false
 	}
 	if !sliceCovers(eff.Verbs, obs.Verbs) {
@@ -269,6 +298,32 @@ func emptyIfNil(s []string) []string {
 	return s
 }
 
+// resourcesCover returns true if every resource in required is granted,
+// following RBAC: "*" covers every resource and subresource, and "*/scale"
+// covers the scale subresource of every resource. A resource never covers
+// its subresources.
+func resourcesCover(granted, required []string) bool {
+	for _, r := range required {
+		if !slices.ContainsFunc(granted, func(g string) bool { return resourceMatches(g, r) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// resourceMatches reports whether the granted resource covers resource.
+func resourceMatches(granted, resource string) bool {
+	if granted == "*" || granted == resource {
+		return true
+	}
+	sub, ok := strings.CutPrefix(granted, "*/")
+	if !ok {
+		return false
+	}
+	_, rsub, found := strings.Cut(resource, "/")
+	return found && rsub == sub
+}
+
 // sliceCovers returns true if every element in required is present in granted.
 // A wildcard "*" in granted covers everything. Returns true when required is empty.
 func sliceCovers(granted, required []string) bool {
@@ -741,6 +741,21 @@ func TestMatchesResourceRule_ResourceNamesExcludes(t *testing.T) {
 	}
 }
 
+func TestMatchesResourceRule_ResourceNamesSubset(t *testing.T) {
+	e := effWithResourceNames("", "configmaps", []string{"get"}, []string{"my-config", "other"}, "default")
+
+	o := obs("", "configmaps", "get", "default")
+	o.ResourceNames = []string{"my-config"}
+	if !matchesResourceRule(o, e) {
+		t.Error("effective rule should cover observations of its named objects")
+	}
+
+	o.ResourceNames = []string{"my-config", "third"}
+	if matchesResourceRule(o, e) {
+		t.Error("effective rule should not cover objects outside its resourceNames")
+	}
+}
+
 func TestMatchesResourceRule_WildcardAPIGroup(t *testing.T) {
 	o := obs("apps", "deployments", "get", "default")
 	e := eff("*", "deployments", []string{"get"}, "default")
@@ -765,6 +780,48 @@ func TestMatchesResourceRule_WildcardVerb(t *testing.T) {
 	}
 }
 
+func TestMatchesResourceRule_Subresources(t *testing.T) {
+	tests := []struct {
+		granted, observed string
+		want              bool
+	}{
+		{"pods/log", "pods/log", true},
+		{"pods", "pods/log", false},
+		{"pods/log", "pods", false},
+		{"*", "pods/exec", true},
+		{"*/scale", "deployments/scale", true},
+		{"*/scale", "deployments", false},
+		{"*/scale", "deployments/status", false},
+	}
+	for _, tt := range tests {
+		o := obs("", tt.observed, "get", "default")
+		e := eff("", tt.granted, []string{"get"}, "default")
+		if got := matchesResourceRule(o, e); got != tt.want {
+			t.Errorf("%s covers %s = %v, want %v", tt.granted, tt.observed, got, tt.want)
+		}
+	}
+}
+
+func TestMarkUsed_WebsocketSubresourceCreate(t *testing.T) {
+	effective := []rbac.ScopedRule{
+		eff("", "pods/exec", []string{"get"}, "default"),
+		eff("", "pods/exec", []string{"create"}, "default"),
+		eff("", "pods/log", []string{"create"}, "default"),
+	}
+	used := make([]bool, len(effective))
+	markUsed(obs("", "pods/exec", "get", "default"), effective, used)
+	markUsed(obs("", "pods/log", "get", "default"), effective, used)
+	if !used[0] || !used[1] {
+		t.Errorf("used = %v, want get and create on pods/exec used", used)
+	}
+	if used[2] {
+		t.Error("create on pods/log marked used by a get")
+	}
+	if !isCovered(obs("", "pods/exec", "get", "default"), effective[:1]) {
+		t.Error("get on pods/exec not covered by get")
+	}
+}
+
 func TestMatchesResourceRule_APIGroupMismatch(t *testing.T) {
 	o := obs("apps", "deployments", "get", "default")
 	e := eff("batch", "deployments", []string{"get"}, "default")
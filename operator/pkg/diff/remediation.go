@@ -0,0 +1,225 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/rbac"
+)
+
+// maxRemediationSteps bounds compliance.remediation of a report.
+const maxRemediationSteps = 50
+
+// patchOp is an RFC 6902 JSON patch operation.
+type patchOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+}
+
+// roleUsage collects the grants of one role to the subject.
+type roleUsage struct {
+	role   rbac.ObjectRef
+	rules  []rbacv1.PolicyRule
+	grants []*rbac.Grant
+	// used marks the rules used through any grant.
+	used []bool
+	// grantUsed marks the grants with a used rule; effective holds a rule of
+	// each grant per rule index, for the namespace it applies in.
+	grantUsed map[*rbac.Grant]bool
+	effective map[*rbac.Grant][]rbac.ScopedRule
+}
+
+// remediation plans the changes to bindings and roles that take the unused
+// effective rules away from the subject. Rules without a known grant, and
+// those granted to an implicit group such as system:authenticated, which
+// cannot be taken away from the subject alone, are left out.
+//
+// A binding whose rules were all unused is deleted, or the subject removed
+// from it. Unused rules of a role in use are removed from the role when the
+// subject is its only user; otherwise, or when Kubernetes reconciles the
+// role, the binding is replaced with the suggested policy.
+func remediation(effective []rbac.ScopedRule, used []bool) []audiciav1alpha1.RemediationStep {
+	var roles []*roleUsage
+	byRole := make(map[rbac.ObjectRef]*roleUsage)
+	for i, eff := range effective {
+		g := eff.Grant
+		if g == nil || g.Via != "" || eff.RuleIndex >= len(g.RoleRules) {
+			continue
+		}
+		ru, ok := byRole[g.Role]
+		if !ok {
+			ru = &roleUsage{
+				role:      g.Role,
+				rules:     g.RoleRules,
+				used:      make([]bool, len(g.RoleRules)),
+				grantUsed: make(map[*rbac.Grant]bool),
+				effective: make(map[*rbac.Grant][]rbac.ScopedRule),
+			}
+			byRole[g.Role] = ru
+			roles = append(roles, ru)
+		}
+		if _, ok := ru.grantUsed[g]; !ok {
+			ru.grants = append(ru.grants, g)
+			ru.grantUsed[g] = false
+		}
+		ru.effective[g] = append(ru.effective[g], eff)
+		if used[i] {
+			ru.grantUsed[g] = true
+			ru.used[eff.RuleIndex] = true
+		}
+	}
+
+	var steps []audiciav1alpha1.RemediationStep
+	for _, ru := range roles {
+		steps = append(steps, ru.steps()...)
+	}
+	if len(steps) > maxRemediationSteps {
+		steps = steps[:maxRemediationSteps]
+	}
+	return steps
+}
+
+// steps returns the remediation steps for the role and its grants.
+func (ru *roleUsage) steps() []audiciav1alpha1.RemediationStep {
+	var steps []audiciav1alpha1.RemediationStep
+	anyUsed := false
+	for _, g := range ru.grants {
+		if ru.grantUsed[g] {
+			anyUsed = true
+			continue
+		}
+		steps = append(steps, unbind(g, complianceRules(ru.effective[g], nil)))
+	}
+
+	if !anyUsed {
+		if ru.exclusive() {
+			steps = append(steps, audiciav1alpha1.RemediationStep{
+				Action: audiciav1alpha1.RemediationDeleteRole,
+				Target: target(ru.role),
+			})
+		}
+		return steps
+	}
+
+	var unused []int
+	for i, u := range ru.used {
+		if !u {
+			unused = append(unused, i)
+		}
+	}
+	if len(unused) == 0 {
+		return steps
+	}
+
+	if !ru.exclusive() {
+		for _, g := range ru.grants {
+			if !ru.grantUsed[g] {
+				continue
+			}
+			step := unbind(g, complianceRules(ru.effective[g], unused))
+			step.Action = audiciav1alpha1.RemediationReplaceBinding
+			steps = append(steps, step)
+		}
+		return steps
+	}
+
+	g := ru.grants[0]
+	ops := make([]patchOp, 0, 2*len(unused))
+	for i := len(unused) - 1; i >= 0; i-- {
+		path := fmt.Sprintf("/rules/%d", unused[i])
+		ops = append(ops,
+			patchOp{Op: "test", Path: path, Value: ru.rules[unused[i]]},
+			patchOp{Op: "remove", Path: path})
+	}
+	return append(steps, audiciav1alpha1.RemediationStep{
+		Action:   audiciav1alpha1.RemediationRemoveRules,
+		Target:   target(ru.role),
+		Rules:    complianceRules(ru.effective[g], unused),
+		Patch:    marshalPatch(ops),
+		Manifest: ru.manifest(),
+	})
+}
+
+// exclusive reports whether the subject is the only one bound to the role,
+// through a single binding, and the role may be edited.
+func (ru *roleUsage) exclusive() bool {
+	if len(ru.grants) != 1 {
+		return false
+	}
+	g := ru.grants[0]
+	return g.RoleBindings == 1 && g.Subjects == 1 && !g.Reconciled
+}
+
+// manifest renders the role with only its used rules.
+func (ru *roleUsage) manifest() string {
+	var rules []rbacv1.PolicyRule
+	for i, rule := range ru.rules {
+		if ru.used[i] {
+			rules = append(rules, rule)
+		}
+	}
+	meta := metav1.ObjectMeta{Name: ru.role.Name, Namespace: ru.role.Namespace}
+	typeMeta := metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: ru.role.Kind}
+	var obj any = &rbacv1.Role{TypeMeta: typeMeta, ObjectMeta: meta, Rules: rules}
+	if ru.role.Kind == "ClusterRole" {
+		obj = &rbacv1.ClusterRole{TypeMeta: typeMeta, ObjectMeta: meta, Rules: rules}
+	}
+	out, err := yaml.Marshal(obj)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// unbind returns the step taking the binding g away from the subject:
+// deleting it, or removing the subject when the binding has others.
+func unbind(g *rbac.Grant, rules []audiciav1alpha1.ComplianceRule) audiciav1alpha1.RemediationStep {
+	step := audiciav1alpha1.RemediationStep{
+		Action: audiciav1alpha1.RemediationDeleteBinding,
+		Target: target(g.Binding),
+		Rules:  rules,
+	}
+	if g.Subjects > 1 {
+		path := fmt.Sprintf("/subjects/%d", g.SubjectIndex)
+		step.Action = audiciav1alpha1.RemediationRemoveSubject
+		step.Patch = marshalPatch([]patchOp{
+			{Op: "test", Path: path, Value: g.Subject},
+			{Op: "remove", Path: path},
+		})
+	}
+	return step
+}
+
+// complianceRules converts the rules of a grant, restricted to the given
+// rule indexes unless nil.
+func complianceRules(rules []rbac.ScopedRule, indexes []int) []audiciav1alpha1.ComplianceRule {
+	keep := make(map[int]bool, len(indexes))
+	for _, i := range indexes {
+		keep[i] = true
+	}
+	var out []audiciav1alpha1.ComplianceRule
+	for _, r := range rules {
+		if indexes == nil || keep[r.RuleIndex] {
+			out = append(out, scopedToComplianceRule(r))
+		}
+	}
+	return out
+}
+
+func target(ref rbac.ObjectRef) audiciav1alpha1.RemediationTarget {
+	return audiciav1alpha1.RemediationTarget{Kind: ref.Kind, Namespace: ref.Namespace, Name: ref.Name}
+}
+
+func marshalPatch(ops []patchOp) string {
+	out, err := json.Marshal(ops)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
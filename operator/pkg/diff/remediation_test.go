@@ -0,0 +1,144 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/rbac"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+var (
+	podRead    = rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}
+	secretRead = rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}
+	alice      = rbacv1.Subject{Kind: "User", Name: "alice"}
+)
+
+// grant returns a RoleBinding of alice in prod to the Role name with rules,
+// and the rules it grants.
+func grant(name string, rules ...rbacv1.PolicyRule) (*rbac.Grant, []rbac.ScopedRule) {
+	g := &rbac.Grant{
+		Binding:      rbac.ObjectRef{Kind: "RoleBinding", Namespace: "prod", Name: name + "-binding"},
+		Subject:      alice,
+		Subjects:     1,
+		Role:         rbac.ObjectRef{Kind: "Role", Namespace: "prod", Name: name},
+		RoleRules:    rules,
+		RoleBindings: 1,
+	}
+	return g, granted(g)
+}
+
+func granted(g *rbac.Grant) []rbac.ScopedRule {
+	out := make([]rbac.ScopedRule, 0, len(g.RoleRules))
+	for i, r := range g.RoleRules {
+		out = append(out, rbac.ScopedRule{PolicyRule: r, Namespace: g.Binding.Namespace, Grant: g, RuleIndex: i})
+	}
+	return out
+}
+
+func onlyStep(t *testing.T, report *audiciav1alpha1.ComplianceReport) audiciav1alpha1.RemediationStep {
+	t.Helper()
+	if len(report.Remediation) != 1 {
+		t.Fatalf("Remediation = %+v, want one step", report.Remediation)
+	}
+	return report.Remediation[0]
+}
+
+func TestRemediation_RemoveRules(t *testing.T) {
+	_, effective := grant("app", secretRead, podRead, secretRead)
+	report := Evaluate([]audiciav1alpha1.ObservedRule{obs("", "pods", "get", "prod")}, effective)
+
+	step := onlyStep(t, report)
+	if step.Action != audiciav1alpha1.RemediationRemoveRules || step.Target.Kind != "Role" || step.Target.Name != "app" {
+		t.Fatalf("step = %+v, want RemoveRules on Role app", step)
+	}
+	if len(step.Rules) != 2 || step.Rules[0].Resources[0] != "secrets" || step.Rules[0].Namespace != "prod" {
+		t.Errorf("Rules = %+v, want both secrets rules in prod", step.Rules)
+	}
+	wantPatch := `[{"op":"test","path":"/rules/2","value":{"verbs":["get"],"apiGroups":[""],"resources":["secrets"]}},` +
+		`{"op":"remove","path":"/rules/2"},` +
+		`{"op":"test","path":"/rules/0","value":{"verbs":["get"],"apiGroups":[""],"resources":["secrets"]}},` +
+		`{"op":"remove","path":"/rules/0"}]`
+	if step.Patch != wantPatch {
+		t.Errorf("Patch = %s\nwant %s", step.Patch, wantPatch)
+	}
+	if !strings.Contains(step.Manifest, "kind: Role\n") || !strings.Contains(step.Manifest, "- pods") ||
+		strings.Contains(step.Manifest, "secrets") {
+		t.Errorf("Manifest = %s, want the Role with the pods rule only", step.Manifest)
+	}
+}
+
+func TestRemediation_UnusedBinding(t *testing.T) {
+	_, effective := grant("debug", secretRead)
+	_, used := grant("app", podRead)
+	effective = append(effective, used...)
+
+	report := Evaluate([]audiciav1alpha1.ObservedRule{obs("", "pods", "get", "prod")}, effective)
+	if len(report.Remediation) != 2 {
+		t.Fatalf("Remediation = %+v, want the debug binding and role deleted", report.Remediation)
+	}
+	if s := report.Remediation[0]; s.Action != audiciav1alpha1.RemediationDeleteBinding ||
+		s.Target.Name != "debug-binding" || s.Patch != "" || len(s.Rules) != 1 {
+		t.Errorf("first step = %+v, want DeleteBinding debug-binding", s)
+	}
+	if s := report.Remediation[1]; s.Action != audiciav1alpha1.RemediationDeleteRole || s.Target.Name != "debug" {
+		t.Errorf("second step = %+v, want DeleteRole debug", s)
+	}
+}
+
+func TestRemediation_RemoveSubject(t *testing.T) {
+	g, effective := grant("debug", secretRead)
+	g.Subjects, g.SubjectIndex = 3, 1
+
+	step := onlyStep(t, Evaluate(nil, effective))
+	want := `[{"op":"test","path":"/subjects/1","value":{"kind":"User","name":"alice"}},{"op":"remove","path":"/subjects/1"}]`
+	if step.Action != audiciav1alpha1.RemediationRemoveSubject || step.Target.Name != "debug-binding" || step.Patch != want {
+		t.Errorf("step = %+v, want RemoveSubject patch %s", step, want)
+	}
+}
+
+func TestRemediation_ReplaceBinding(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(*rbac.Grant)
+	}{
+		{"role bound elsewhere", func(g *rbac.Grant) { g.RoleBindings = 2 }},
+		{"reconciled role", func(g *rbac.Grant) {
+			g.Role = rbac.ObjectRef{Kind: "ClusterRole", Name: "edit"}
+			g.Reconciled = true
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, _ := grant("app", podRead, secretRead)
+			tt.setup(g)
+			step := onlyStep(t, Evaluate([]audiciav1alpha1.ObservedRule{obs("", "pods", "get", "prod")}, granted(g)))
+			if step.Action != audiciav1alpha1.RemediationReplaceBinding || step.Target.Kind != "RoleBinding" ||
+				step.Patch != "" || step.Manifest != "" {
+				t.Errorf("step = %+v, want ReplaceBinding of app-binding", step)
+			}
+			if len(step.Rules) != 1 || step.Rules[0].Resources[0] != "secrets" {
+				t.Errorf("Rules = %+v, want the secrets rule", step.Rules)
+			}
+		})
+	}
+}
+
+func TestRemediation_UnknownGrant(t *testing.T) {
+	report := Evaluate(nil, []rbac.ScopedRule{eff("", "secrets", []string{"get"}, "prod")})
+	if report.Remediation != nil {
+		t.Errorf("Remediation = %+v, want none for rules without a grant", report.Remediation)
+	}
+}
+
+func TestRemediation_ImplicitGroup(t *testing.T) {
+	g, effective := grant("discovery", secretRead)
+	g.Subject = rbacv1.Subject{Kind: "Group", Name: "system:authenticated"}
+	g.Via = "system:authenticated"
+
+	report := Evaluate(nil, effective)
+	if report.ExcessCount != 1 || report.Remediation != nil {
+		t.Errorf("ExcessCount = %d, Remediation = %+v, want the excess without a step", report.ExcessCount, report.Remediation)
+	}
+}
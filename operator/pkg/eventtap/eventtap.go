@@ -0,0 +1,270 @@
+// Package eventtap writes the normalized rules observed by the operator's
+// pipelines to JSON Lines files, one (subject, rule, timestamp) record per
+// line, so anomaly-detection and other ML pipelines can train on Audicia's
+// normalized stream without reading raw audit logs.
+package eventtap
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+	"github.com/felixnotka/audicia/operator/pkg/rulestream"
+)
+
+var log = ctrl.Log.WithName("eventtap")
+
+const (
+	// DefaultMaxBytes is the size at which a file is closed and the next
+	// one started.
+	DefaultMaxBytes = 100 << 20
+
+	// DefaultMaxFiles is the number of files kept in the directory.
+	DefaultMaxFiles = 10
+
+	// subscriptionBuffer is the number of observations buffered between the
+	// pipelines and the writer. Observations beyond it are dropped rather
+	// than slowing ingestion down.
+	subscriptionBuffer = 4096
+
+	// flushInterval bounds how long a written record stays in memory.
+	flushInterval = time.Second
+
+	filePrefix = "events-"
+	fileSuffix = ".jsonl"
+)
+
+// Record is one line of a tap file.
+type Record struct {
+	// Time is when the audit event was observed by the pipeline.
+	Time time.Time `json:"time"`
+
+	// Source is the AudiciaSource that observed the rule, as namespace/name.
+	Source string `json:"source"`
+
+	Subject audiciav1alpha1.Subject `json:"subject"`
+	Rule    Rule                    `json:"rule"`
+}
+
+// Rule is the normalized rule of a Record.
+type Rule struct {
+	APIGroup        string `json:"apiGroup"`
+	Resource        string `json:"resource,omitempty"`
+	Verb            string `json:"verb"`
+	ResourceName    string `json:"resourceName,omitempty"`
+	NonResourceURL  string `json:"nonResourceURL,omitempty"`
+	Namespace       string `json:"namespace,omitempty"`
+	Preset          string `json:"preset,omitempty"`
+	Incomplete      bool   `json:"incomplete,omitempty"`
+	Denied          bool   `json:"denied,omitempty"`
+	AdmissionDenied bool   `json:"admissionDenied,omitempty"`
+}
+
+// NewRecord returns the Record of o.
+func NewRecord(o rulestream.Observation) Record {
+	return Record{
+		Time:    o.Time.UTC(),
+		Source:  o.Source.String(),
+		Subject: o.Subject,
+		Rule:    newRule(o.Rule),
+	}
+}
+
+func newRule(r normalizer.CanonicalRule) Rule {
+	return Rule{
+		APIGroup:        r.APIGroup,
+		Resource:        r.Resource,
+		Verb:            r.Verb,
+		ResourceName:    r.ResourceName,
+		NonResourceURL:  r.NonResourceURL,
+		Namespace:       r.Namespace,
+		Preset:          r.Preset,
+		Incomplete:      r.Incomplete,
+		Denied:          r.Denied,
+		AdmissionDenied: r.AdmissionDenied,
+	}
+}
+
+// Writer is a manager runnable that subscribes to a rule stream hub and
+// appends every observation to a JSON Lines file in Dir.
+//
+// Files are named events-<UTC start time>.jsonl and written sequentially
+// under their final name: once a file reaches MaxBytes it is closed and
+// never opened again, and a new one is created. Nothing is renamed or
+// appended to after a restart, so Dir may be a write-once object storage
+// mount (for example Mountpoint for Amazon S3 or Cloud Storage FUSE), and
+// any file other than the newest is complete and safe to ship.
+type Writer struct {
+	Hub *rulestream.Hub
+
+	// Dir is the directory the files are written to. It is created if
+	// missing.
+	Dir string
+
+	// MaxBytes is the size at which a file is closed. Defaults to
+	// DefaultMaxBytes.
+	MaxBytes int64
+
+	// MaxFiles is the number of files kept; older ones are deleted. Zero or
+	// less keeps every file, for storage that is pruned elsewhere.
+	MaxFiles int
+
+	// now is the clock used to name files; tests replace it.
+	now func() time.Time
+
+	file    *os.File
+	buf     *bufio.Writer
+	written int64
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Every
+// replica writes what its own pipelines observe.
+func (w *Writer) NeedLeaderElection() bool { return false }
+
+// Start implements manager.Runnable. It writes until ctx is done, then
+// flushes and closes the current file.
+func (w *Writer) Start(ctx context.Context) error {
+	if err := os.MkdirAll(w.Dir, 0o750); err != nil {
+		return fmt.Errorf("creating event tap directory: %w", err)
+	}
+	ch, cancel := w.Hub.Subscribe(subscriptionBuffer)
+	defer cancel()
+	defer w.close()
+
+	log.Info("writing event tap", "dir", w.Dir, "maxBytes", w.maxBytes(), "maxFiles", w.MaxFiles)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case o := <-ch:
+			w.write(o)
+		case <-ticker.C:
+			if w.buf != nil {
+				if err := w.buf.Flush(); err != nil {
+					log.Error(err, "unable to flush event tap", "file", w.file.Name())
+				}
+			}
+		}
+	}
+}
+
+// write appends o to the current file, starting a new one first if needed.
+// Errors are logged and counted; the record is lost, ingestion goes on.
+func (w *Writer) write(o rulestream.Observation) {
+	line, err := json.Marshal(NewRecord(o))
+	if err != nil {
+		w.fail(err)
+		return
+	}
+	line = append(line, '\n')
+	if w.file != nil && w.written > 0 && w.written+int64(len(line)) > w.maxBytes() {
+		w.close()
+	}
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			w.fail(err)
+			return
+		}
+	}
+	n, err := w.buf.Write(line)
+	w.written += int64(n)
+	if err != nil {
+		w.fail(err)
+		w.close()
+		return
+	}
+	metrics.EventTapRecordsTotal.WithLabelValues("written").Inc()
+}
+
+func (w *Writer) fail(err error) {
+	metrics.EventTapRecordsTotal.WithLabelValues("failed").Inc()
+	log.Error(err, "unable to write event tap record", "dir", w.Dir)
+}
+
+// open creates the next file and prunes the oldest beyond MaxFiles.
+func (w *Writer) open() error {
+	now := time.Now
+	if w.now != nil {
+		now = w.now
+	}
+	name := filepath.Join(w.Dir, filePrefix+now().UTC().Format("20060102T150405.000000000Z")+fileSuffix)
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return fmt.Errorf("creating event tap file: %w", err)
+	}
+	w.file = f
+	w.buf = bufio.NewWriter(f)
+	w.written = 0
+	w.prune()
+	return nil
+}
+
+// close flushes and closes the current file, if any.
+func (w *Writer) close() {
+	if w.file == nil {
+		return
+	}
+	if err := w.buf.Flush(); err != nil {
+		log.Error(err, "unable to flush event tap", "file", w.file.Name())
+	}
+	if err := w.file.Close(); err != nil {
+		log.Error(err, "unable to close event tap file", "file", w.file.Name())
+	}
+	w.file, w.buf = nil, nil
+}
+
+// prune deletes the oldest tap files beyond MaxFiles. File names sort by
+// start time.
+func (w *Writer) prune() {
+	if w.MaxFiles <= 0 {
+		return
+	}
+	files, err := Files(w.Dir)
+	if err != nil {
+		log.Error(err, "unable to list event tap files", "dir", w.Dir)
+		return
+	}
+	for len(files) > w.MaxFiles {
+		if err := os.Remove(files[0]); err != nil && !os.IsNotExist(err) {
+			log.Error(err, "unable to delete event tap file", "file", files[0])
+		}
+		files = files[1:]
+	}
+}
+
+func (w *Writer) maxBytes() int64 {
+	if w.MaxBytes > 0 {
+		return w.MaxBytes
+	}
+	return DefaultMaxBytes
+}
+
+// Files returns the tap files in dir, oldest first.
+func Files(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !strings.HasPrefix(e.Name(), filePrefix) || !strings.HasSuffix(e.Name(), fileSuffix) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, e.Name()))
+	}
+	slices.Sort(files)
+	return files, nil
+}
@@ -0,0 +1,158 @@
+package eventtap
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+	"github.com/felixnotka/audicia/operator/pkg/rulestream"
+)
+
+func observation(verb string) rulestream.Observation {
+	return rulestream.Observation{
+		Source:  types.NamespacedName{Namespace: "audicia-system", Name: "audit"},
+		Subject: audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Namespace: "team-a", Name: "builder"},
+		Rule:    normalizer.CanonicalRule{APIGroup: "apps", Resource: "deployments", Verb: verb, Namespace: "team-a"},
+		Time:    time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+// readRecords decodes every line of the tap files in dir.
+func readRecords(t *testing.T, dir string) []Record {
+	t.Helper()
+	files, err := Files(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []Record
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var r Record
+			if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			records = append(records, r)
+		}
+		_ = f.Close()
+	}
+	return records
+}
+
+func TestNewRecord_JSON(t *testing.T) {
+	data, err := json.Marshal(NewRecord(observation("get")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"time":"2026-03-01T12:00:00Z","source":"audicia-system/audit",` +
+		`"subject":{"kind":"ServiceAccount","name":"builder","namespace":"team-a"},` +
+		`"rule":{"apiGroup":"apps","resource":"deployments","verb":"get","namespace":"team-a"}}`
+	if string(data) != want {
+		t.Errorf("record = %s\nwant %s", data, want)
+	}
+}
+
+func TestWriter_WritesPublishedObservations(t *testing.T) {
+	hub := rulestream.NewHub()
+	dir := filepath.Join(t.TempDir(), "tap")
+	w := &Writer{Hub: hub, Dir: dir}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Start(ctx) }()
+
+	// Publish until the writer has subscribed and received the first one.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		hub.Publish(observation("get"))
+		if files, _ := Files(dir); len(files) > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("writer did not create a file")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	hub.Publish(observation("delete"))
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	records := readRecords(t, dir)
+	if len(records) < 2 {
+		t.Fatalf("got %d records, want at least 2", len(records))
+	}
+	last := records[len(records)-1]
+	if last.Rule.Verb != "delete" || last.Subject.Name != "builder" || last.Source != "audicia-system/audit" {
+		t.Errorf("last record = %+v", last)
+	}
+}
+
+func TestWriter_RotatesAndPrunes(t *testing.T) {
+	dir := t.TempDir()
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	line, _ := json.Marshal(NewRecord(observation("get")))
+	w := &Writer{
+		Dir:      dir,
+		MaxBytes: int64(len(line)+1) * 2,
+		MaxFiles: 2,
+		now: func() time.Time {
+			clock = clock.Add(time.Second)
+			return clock
+		},
+	}
+	for range 7 {
+		w.write(observation("get"))
+	}
+	w.close()
+
+	files, err := Files(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Seven records of two per file make four files, of which two are kept.
+	if len(files) != 2 {
+		t.Fatalf("files = %v, want 2", files)
+	}
+	if got := filepath.Base(files[1]); got != "events-20260301T120004.000000000Z.jsonl" {
+		t.Errorf("newest file = %s", got)
+	}
+	if got := len(readRecords(t, dir)); got != 3 {
+		t.Errorf("kept %d records, want 3", got)
+	}
+}
+
+func TestWriter_KeepsEverythingWithoutMaxFiles(t *testing.T) {
+	dir := t.TempDir()
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	w := &Writer{
+		Dir:      dir,
+		MaxBytes: 1,
+		now: func() time.Time {
+			clock = clock.Add(time.Second)
+			return clock
+		},
+	}
+	for range 5 {
+		w.write(observation("list"))
+	}
+	w.close()
+
+	if got := len(readRecords(t, dir)); got != 5 {
+		t.Errorf("kept %d records, want 5", got)
+	}
+}
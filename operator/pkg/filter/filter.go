@@ -2,6 +2,7 @@ package filter
 
 import (
 	"regexp"
+	"time"
 
 	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
 )
@@ -11,6 +12,21 @@ type compiledFilter struct {
 	action           audiciav1alpha1.FilterAction
 	userPattern      *regexp.Regexp
 	namespacePattern *regexp.Regexp
+	verbPattern      *regexp.Regexp
+	resourcePattern  *regexp.Regexp
+	apiGroupPattern  *regexp.Regexp
+	window           *timeWindow
+}
+
+// Request holds the request attributes filters can match on.
+type Request struct {
+	Verb        string
+	APIGroup    string
+	Resource    string
+	Subresource string
+
+	// Time is when the request was received.
+	Time time.Time
 }
 
 // Chain evaluates an ordered list of allow/deny filters. First match wins.
@@ -24,20 +40,24 @@ func NewChain(rules []audiciav1alpha1.Filter) (*Chain, error) {
 	for _, r := range rules {
 		cf := compiledFilter{action: r.Action}
 
-		if r.UserPattern != "" {
-			re, err := regexp.Compile(r.UserPattern)
-			if err != nil {
-				return nil, err
-			}
-			cf.userPattern = re
+		var err error
+		if cf.userPattern, err = compileOptional(r.UserPattern); err != nil {
+			return nil, err
 		}
-
-		if r.NamespacePattern != "" {
-			re, err := regexp.Compile(r.NamespacePattern)
-			if err != nil {
-				return nil, err
-			}
-			cf.namespacePattern = re
+		if cf.namespacePattern, err = compileOptional(r.NamespacePattern); err != nil {
+			return nil, err
+		}
+		if cf.verbPattern, err = compileOptional(r.VerbPattern); err != nil {
+			return nil, err
+		}
+		if cf.resourcePattern, err = compileOptional(r.ResourcePattern); err != nil {
+			return nil, err
+		}
+		if cf.apiGroupPattern, err = compileOptional(r.APIGroupPattern); err != nil {
+			return nil, err
+		}
+		if cf.window, err = compileWindow(r); err != nil {
+			return nil, err
 		}
 
 		compiled = append(compiled, cf)
@@ -46,20 +66,37 @@ func NewChain(rules []audiciav1alpha1.Filter) (*Chain, error) {
 	return &Chain{filters: compiled}, nil
 }
 
-// Allow returns true if the event should be processed (not filtered out).
+// compileOptional compiles pattern, returning nil for an empty pattern.
+func compileOptional(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile(pattern)
+}
+
+// Allow returns true if events of the user in the namespace should be
+// processed (not filtered out), whatever the request. Rules with request
+// conditions never match, since they only filter some of the user's requests.
 // First matching rule wins. If no rule matches, the event is allowed.
 func (c *Chain) Allow(username, namespace string) bool {
 	for _, f := range c.filters {
-		matched := false
-
-		if f.userPattern != nil && f.userPattern.MatchString(username) {
-			matched = true
+		if f.hasRequestConditions() {
+			continue
 		}
-		if f.namespacePattern != nil && f.namespacePattern.MatchString(namespace) {
-			matched = true
+		if f.matchesSubject(username, namespace) {
+			return f.action == audiciav1alpha1.FilterActionAllow
 		}
+	}
+
+	// Default: allow.
+	return true
+}
 
-		if matched {
+// AllowRequest returns true if the request should be processed (not filtered
+// out). First matching rule wins. If no rule matches, the event is allowed.
+func (c *Chain) AllowRequest(username, namespace string, req Request) bool {
+	for _, f := range c.filters {
+		if f.matchesSubject(username, namespace) && f.matchesRequest(req) {
 			return f.action == audiciav1alpha1.FilterActionAllow
 		}
 	}
@@ -67,3 +104,44 @@ func (c *Chain) Allow(username, namespace string) bool {
 	// Default: allow.
 	return true
 }
+
+func (f *compiledFilter) hasRequestConditions() bool {
+	return f.verbPattern != nil || f.resourcePattern != nil || f.apiGroupPattern != nil || f.window != nil
+}
+
+// matchesSubject applies the user and namespace patterns with OR-semantics.
+// A rule without either matches every subject, unless it has no request
+// conditions either.
+func (f *compiledFilter) matchesSubject(username, namespace string) bool {
+	if f.userPattern == nil && f.namespacePattern == nil {
+		return f.hasRequestConditions()
+	}
+	if f.userPattern != nil && f.userPattern.MatchString(username) {
+		return true
+	}
+	return f.namespacePattern != nil && f.namespacePattern.MatchString(namespace)
+}
+
+// matchesRequest applies the request conditions with AND-semantics, so a
+// rule can single out e.g. "get events" or a maintenance window.
+func (f *compiledFilter) matchesRequest(req Request) bool {
+	if f.window != nil && !f.window.contains(req.Time) {
+		return false
+	}
+	if f.verbPattern != nil && !f.verbPattern.MatchString(req.Verb) {
+		return false
+	}
+	if f.apiGroupPattern != nil && !f.apiGroupPattern.MatchString(req.APIGroup) {
+		return false
+	}
+	if f.resourcePattern != nil {
+		resource := req.Resource
+		if req.Subresource != "" {
+			resource += "/" + req.Subresource
+		}
+		if !f.resourcePattern.MatchString(resource) {
+			return false
+		}
+	}
+	return true
+}
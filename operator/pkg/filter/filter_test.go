@@ -2,6 +2,7 @@ package filter
 
 import (
 	"testing"
+	"time"
 
 	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
 )
@@ -221,3 +222,106 @@ func TestAllow_NoPatternNeverMatches(t *testing.T) {
 		t.Error("filter with no patterns should never match, expected default allow")
 	}
 }
+
+func TestNewChain_InvalidRequestRegex(t *testing.T) {
+	for _, f := range []audiciav1alpha1.Filter{
+		{Action: audiciav1alpha1.FilterActionDeny, VerbPattern: "["},
+		{Action: audiciav1alpha1.FilterActionDeny, ResourcePattern: "("},
+		{Action: audiciav1alpha1.FilterActionDeny, APIGroupPattern: "*"},
+	} {
+		if _, err := NewChain([]audiciav1alpha1.Filter{f}); err == nil {
+			t.Errorf("expected error for invalid regex in %+v", f)
+		}
+	}
+}
+
+func TestAllowRequest_RequestPatterns(t *testing.T) {
+	chain, err := NewChain([]audiciav1alpha1.Filter{
+		{Action: audiciav1alpha1.FilterActionDeny, VerbPattern: "^get$", ResourcePattern: "^events$"},
+		{Action: audiciav1alpha1.FilterActionDeny, VerbPattern: "^list$", ResourcePattern: "^leases$", APIGroupPattern: "^coordination.k8s.io$"},
+		{Action: audiciav1alpha1.FilterActionDeny, ResourcePattern: "^pods/log$"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		req  Request
+		want bool
+	}{
+		{"get events", Request{Verb: "get", Resource: "events"}, false},
+		{"list events", Request{Verb: "list", Resource: "events"}, true},
+		{"get pods", Request{Verb: "get", Resource: "pods"}, true},
+		{"list leases", Request{Verb: "list", APIGroup: "coordination.k8s.io", Resource: "leases"}, false},
+		{"list leases in another group", Request{Verb: "list", APIGroup: "example.com", Resource: "leases"}, true},
+		{"pod logs", Request{Verb: "get", Resource: "pods", Subresource: "log"}, false},
+		{"pod exec", Request{Verb: "create", Resource: "pods", Subresource: "exec"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chain.AllowRequest("alice", "default", tt.req); got != tt.want {
+				t.Errorf("AllowRequest(%+v) = %v, want %v", tt.req, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowRequest_SubjectAndRequestPatterns(t *testing.T) {
+	// User and request patterns must both match.
+	chain, err := NewChain([]audiciav1alpha1.Filter{
+		{Action: audiciav1alpha1.FilterActionDeny, UserPattern: "^system:serviceaccount:", VerbPattern: "^watch$"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if chain.AllowRequest("system:serviceaccount:prod:api", "prod", Request{Verb: "watch", Resource: "pods"}) {
+		t.Error("expected service account watch to be denied")
+	}
+	if !chain.AllowRequest("system:serviceaccount:prod:api", "prod", Request{Verb: "get", Resource: "pods"}) {
+		t.Error("expected service account get to be allowed")
+	}
+	if !chain.AllowRequest("alice", "prod", Request{Verb: "watch", Resource: "pods"}) {
+		t.Error("expected user watch to be allowed")
+	}
+}
+
+func TestAllow_IgnoresRequestPatterns(t *testing.T) {
+	// Allow decides for all of a user's requests, so rules that only match
+	// some requests do not apply.
+	chain, err := NewChain([]audiciav1alpha1.Filter{
+		{Action: audiciav1alpha1.FilterActionDeny, UserPattern: ".*", VerbPattern: "^get$"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !chain.Allow("alice", "default") {
+		t.Error("expected rule with request patterns to be ignored")
+	}
+	if chain.AllowRequest("alice", "default", Request{Verb: "get"}) {
+		t.Error("expected get to be denied")
+	}
+}
+
+func TestAllowRequest_MaintenanceWindow(t *testing.T) {
+	// Drop the CI user's traffic during the nightly run only.
+	chain, err := NewChain([]audiciav1alpha1.Filter{
+		{Action: audiciav1alpha1.FilterActionDeny, UserPattern: "^ci-bot$", ActiveHours: "01:00-03:00"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	night := time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)
+	if chain.AllowRequest("ci-bot", "default", Request{Verb: "get", Time: night}) {
+		t.Error("expected ci-bot to be denied during the window")
+	}
+	if !chain.AllowRequest("ci-bot", "default", Request{Verb: "get", Time: night.Add(2 * time.Hour)}) {
+		t.Error("expected ci-bot to be allowed outside the window")
+	}
+	if !chain.Allow("ci-bot", "default") {
+		t.Error("expected the time window to be ignored for the subject as a whole")
+	}
+}
@@ -0,0 +1,110 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// weekdays maps the spec day names onto time.Weekday.
+var weekdays = map[audiciav1alpha1.Weekday]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// timeWindow is a compiled activeHours/activeDays condition.
+type timeWindow struct {
+	loc *time.Location
+
+	// hours is false when any time of day matches. from and to are minutes
+	// since midnight; to < from spans midnight.
+	hours    bool
+	from, to int
+
+	// days is nil when any day matches.
+	days map[time.Weekday]bool
+}
+
+// compileWindow compiles the time conditions of a rule, returning nil when it
+// has none.
+func compileWindow(r audiciav1alpha1.Filter) (*timeWindow, error) {
+	if r.ActiveHours == "" && len(r.ActiveDays) == 0 {
+		return nil, nil
+	}
+	w := &timeWindow{loc: time.UTC}
+	if r.TimeZone != "" {
+		loc, err := time.LoadLocation(r.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeZone %q: %w", r.TimeZone, err)
+		}
+		w.loc = loc
+	}
+	if r.ActiveHours != "" {
+		from, to, ok := strings.Cut(r.ActiveHours, "-")
+		var err error
+		if !ok {
+			return nil, fmt.Errorf("invalid activeHours %q: want HH:MM-HH:MM", r.ActiveHours)
+		}
+		if w.from, err = parseClock(from); err != nil {
+			return nil, fmt.Errorf("invalid activeHours %q: %w", r.ActiveHours, err)
+		}
+		if w.to, err = parseClock(to); err != nil {
+			return nil, fmt.Errorf("invalid activeHours %q: %w", r.ActiveHours, err)
+		}
+		w.hours = true
+	}
+	if len(r.ActiveDays) > 0 {
+		w.days = make(map[time.Weekday]bool, len(r.ActiveDays))
+		for _, d := range r.ActiveDays {
+			wd, ok := weekdays[d]
+			if !ok {
+				return nil, fmt.Errorf("invalid activeDays entry %q", d)
+			}
+			w.days[wd] = true
+		}
+	}
+	return w, nil
+}
+
+// parseClock parses "HH:MM" into minutes since midnight.
+func parseClock(s string) (int, error) {
+	h, m, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	hours, err := strconv.Atoi(h)
+	if err != nil || hours < 0 || hours > 23 {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	minutes, err := strconv.Atoi(m)
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	return hours*60 + minutes, nil
+}
+
+// contains reports whether t falls within the window. The day is that of t
+// itself, so the part of an overnight window after midnight belongs to the
+// following day.
+func (w *timeWindow) contains(t time.Time) bool {
+	t = t.In(w.loc)
+	if w.days != nil && !w.days[t.Weekday()] {
+		return false
+	}
+	if !w.hours {
+		return true
+	}
+	m := t.Hour()*60 + t.Minute()
+	if w.from <= w.to {
+		return m >= w.from && m < w.to
+	}
+	return m >= w.from || m < w.to
+}
@@ -0,0 +1,63 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func TestCompileWindow_Invalid(t *testing.T) {
+	for _, f := range []audiciav1alpha1.Filter{
+		{ActiveHours: "22:00"},
+		{ActiveHours: "25:00-06:00"},
+		{ActiveHours: "22:00-06:60"},
+		{ActiveDays: []audiciav1alpha1.Weekday{"Monday"}},
+		{ActiveHours: "22:00-06:00", TimeZone: "Mars/Olympus_Mons"},
+	} {
+		if _, err := compileWindow(f); err == nil {
+			t.Errorf("expected error for %+v", f)
+		}
+	}
+}
+
+func TestTimeWindow_Contains(t *testing.T) {
+	// 2026-03-01 is a Sunday.
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 3, day, hour, minute, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		filter audiciav1alpha1.Filter
+		t      time.Time
+		want   bool
+	}{
+		{"within hours", audiciav1alpha1.Filter{ActiveHours: "02:00-04:00"}, at(1, 3, 0), true},
+		{"start is inclusive", audiciav1alpha1.Filter{ActiveHours: "02:00-04:00"}, at(1, 2, 0), true},
+		{"end is exclusive", audiciav1alpha1.Filter{ActiveHours: "02:00-04:00"}, at(1, 4, 0), false},
+		{"overnight before midnight", audiciav1alpha1.Filter{ActiveHours: "22:00-06:00"}, at(1, 23, 30), true},
+		{"overnight after midnight", audiciav1alpha1.Filter{ActiveHours: "22:00-06:00"}, at(2, 5, 59), true},
+		{"outside overnight", audiciav1alpha1.Filter{ActiveHours: "22:00-06:00"}, at(2, 12, 0), false},
+		{"matching day", audiciav1alpha1.Filter{ActiveDays: []audiciav1alpha1.Weekday{"Sat", "Sun"}}, at(1, 12, 0), true},
+		{"other day", audiciav1alpha1.Filter{ActiveDays: []audiciav1alpha1.Weekday{"Sat", "Sun"}}, at(2, 12, 0), false},
+		{
+			"hours and day", audiciav1alpha1.Filter{ActiveHours: "02:00-04:00", ActiveDays: []audiciav1alpha1.Weekday{"Sun"}},
+			at(2, 3, 0), false,
+		},
+		{
+			// 01:30 UTC is 02:30 in Berlin (CET).
+			"time zone", audiciav1alpha1.Filter{ActiveHours: "02:00-03:00", TimeZone: "Europe/Berlin"},
+			at(1, 1, 30), true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := compileWindow(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := w.contains(tt.t); got != tt.want {
+				t.Errorf("contains(%s) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
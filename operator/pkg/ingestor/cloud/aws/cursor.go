@@ -0,0 +1,94 @@
+package aws
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// logGroups returns the log groups of cfg in order, without duplicates.
+func logGroups(cfg *audiciav1alpha1.AWSCloudWatchConfig) []string {
+	var groups []string
+	for _, g := range append([]string{cfg.LogGroupName}, cfg.LogGroupNames...) {
+		if g != "" && !slices.Contains(groups, g) {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// logGroupCursor is the read position in one log group. It is the
+// checkpoint offset of the group's partition, so a restart resumes each
+// group where it stopped.
+type logGroupCursor struct {
+	// StartTime is the StartTime of the current FilterLogEvents query in
+	// milliseconds since the epoch, inclusive.
+	StartTime int64
+
+	// LastTime is the timestamp of the last event read, in milliseconds
+	// since the epoch.
+	LastTime int64
+
+	// NextToken continues the current query; empty once all its pages were
+	// read.
+	NextToken string
+}
+
+// String encodes the cursor as "<startTime>:<lastTime>[:<nextToken>]".
+func (c logGroupCursor) String() string {
+	s := strconv.FormatInt(c.StartTime, 10) + ":" + strconv.FormatInt(c.LastTime, 10)
+	if c.NextToken != "" {
+		s += ":" + c.NextToken
+	}
+	return s
+}
+
+// parseCursor decodes a cursor encoded by String.
+func parseCursor(s string) (logGroupCursor, error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) < 2 {
+		return logGroupCursor{}, fmt.Errorf("invalid log group cursor %q", s)
+	}
+	start, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return logGroupCursor{}, fmt.Errorf("invalid log group cursor %q: %w", s, err)
+	}
+	last, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return logGroupCursor{}, fmt.Errorf("invalid log group cursor %q: %w", s, err)
+	}
+	c := logGroupCursor{StartTime: start, LastTime: last}
+	if len(parts) == 3 {
+		c.NextToken = parts[2]
+	}
+	return c, nil
+}
+
+// advance returns the cursor after a page of events with the given
+// timestamps and the page's next token. The query's StartTime is kept
+// while pages remain, as a token only continues the query it was issued
+// for; once all pages were read, the next query starts after the last
+// event.
+func (c logGroupCursor) advance(timestamps []int64, nextToken string) logGroupCursor {
+	for _, ts := range timestamps {
+		c.LastTime = max(c.LastTime, ts)
+	}
+	c.NextToken = nextToken
+	if nextToken == "" && c.LastTime >= c.StartTime {
+		c.StartTime = c.LastTime + 1
+	}
+	return c
+}
+
+// restart returns the cursor for a new query after its token was lost or
+// rejected: after the last event read, or at StartTime if none was.
+func (c logGroupCursor) restart() logGroupCursor {
+	c.NextToken = ""
+	if c.LastTime >= c.StartTime {
+		c.StartTime = c.LastTime + 1
+	}
+	return c
+}
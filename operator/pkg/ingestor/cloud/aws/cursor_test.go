@@ -0,0 +1,75 @@
+package aws
+
+import (
+	"slices"
+	"testing"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func TestLogGroups(t *testing.T) {
+	cfg := &audiciav1alpha1.AWSCloudWatchConfig{
+		LogGroupName:  "/aws/eks/prod/cluster",
+		LogGroupNames: []string{"/aws/eks/staging/cluster", "/aws/eks/prod/cluster", ""},
+	}
+	want := []string{"/aws/eks/prod/cluster", "/aws/eks/staging/cluster"}
+	if got := logGroups(cfg); !slices.Equal(got, want) {
+		t.Errorf("logGroups = %v, want %v", got, want)
+	}
+	if got := logGroups(&audiciav1alpha1.AWSCloudWatchConfig{}); len(got) != 0 {
+		t.Errorf("logGroups of an empty config = %v", got)
+	}
+}
+
+func TestLogGroupCursorRoundTrip(t *testing.T) {
+	for _, c := range []logGroupCursor{
+		{StartTime: 1700000000000},
+		{StartTime: 1700000000000, LastTime: 1700000005000, NextToken: "f/3873:abc=="},
+	} {
+		got, err := parseCursor(c.String())
+		if err != nil {
+			t.Fatalf("parseCursor(%q): %v", c.String(), err)
+		}
+		if got != c {
+			t.Errorf("parseCursor(%q) = %+v, want %+v", c.String(), got, c)
+		}
+	}
+	for _, bad := range []string{"", "12", "x:1", "1:y"} {
+		if _, err := parseCursor(bad); err == nil {
+			t.Errorf("parseCursor(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestLogGroupCursorAdvance(t *testing.T) {
+	c := logGroupCursor{StartTime: 1000}
+
+	// A page with more to come keeps the query's StartTime.
+	c = c.advance([]int64{1000, 1500}, "token-2")
+	if c.StartTime != 1000 || c.LastTime != 1500 || c.NextToken != "token-2" {
+		t.Fatalf("after page 1: %+v", c)
+	}
+
+	// The last page, even an empty one, starts the next query after the
+	// last event.
+	c = c.advance(nil, "")
+	if c.StartTime != 1501 || c.LastTime != 1500 || c.NextToken != "" {
+		t.Fatalf("after the last page: %+v", c)
+	}
+
+	// Nothing new keeps the position.
+	if got := c.advance(nil, ""); got != c {
+		t.Errorf("empty poll moved the cursor to %+v", got)
+	}
+}
+
+func TestLogGroupCursorRestart(t *testing.T) {
+	c := logGroupCursor{StartTime: 1000, LastTime: 1500, NextToken: "expired"}
+	if got := c.restart(); got != (logGroupCursor{StartTime: 1501, LastTime: 1500}) {
+		t.Errorf("restart = %+v", got)
+	}
+	c = logGroupCursor{StartTime: 1000, NextToken: "expired"}
+	if got := c.restart(); got != (logGroupCursor{StartTime: 1000}) {
+		t.Errorf("restart without events = %+v", got)
+	}
+}
@@ -16,3 +16,12 @@ type EnvelopeParser struct{}
 func (p *EnvelopeParser) Parse(body []byte) ([]auditv1.Event, error) {
 	return parseCloudWatchEvent(body)
 }
+
+// S3EnvelopeParser implements cloud.EnvelopeParser for audit log objects
+// archived to S3. Each message body is a whole (possibly gzip-compressed)
+// object holding many audit events.
+type S3EnvelopeParser struct{}
+
+func (p *S3EnvelopeParser) Parse(body []byte) ([]auditv1.Event, error) {
+	return parseS3Object(body)
+}
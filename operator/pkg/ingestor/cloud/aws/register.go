@@ -11,22 +11,46 @@ import (
 
 func init() {
 	cloud.RegisterAdapter(audiciav1alpha1.CloudProviderAWSCloudWatch, buildAWSAdapter)
+	cloud.RegisterAdapter(audiciav1alpha1.CloudProviderAWSS3, buildS3Adapter)
 }
 
-func buildAWSAdapter(cfg *audiciav1alpha1.CloudConfig) (cloud.MessageSource, cloud.EnvelopeParser, error) {
+func buildAWSAdapter(cfg *audiciav1alpha1.CloudConfig, id cloud.SourceIdentity) (cloud.MessageSource, cloud.EnvelopeParser, error) {
 	if cfg.AWS == nil {
 		return nil, nil, fmt.Errorf("aws configuration is required for AWSCloudWatch provider")
 	}
 
-	if cfg.AWS.LogGroupName == "" {
-		return nil, nil, fmt.Errorf("aws.logGroupName is required")
+	groups := logGroups(cfg.AWS)
+	if len(groups) == 0 {
+		return nil, nil, fmt.Errorf("aws.logGroupName or aws.logGroupNames is required")
 	}
 
 	source := &CloudWatchSource{
-		LogGroupName:    cfg.AWS.LogGroupName,
+		LogGroups:       groups,
 		LogStreamPrefix: cfg.AWS.LogStreamPrefix,
+		FilterPattern:   cfg.AWS.FilterPattern,
 		Region:          cfg.AWS.Region,
+		RoleARN:         cfg.AWS.RoleARN,
+		SessionName:     id.SessionName(),
 	}
 
 	return source, &EnvelopeParser{}, nil
 }
+
+func buildS3Adapter(cfg *audiciav1alpha1.CloudConfig, id cloud.SourceIdentity) (cloud.MessageSource, cloud.EnvelopeParser, error) {
+	if cfg.S3 == nil {
+		return nil, nil, fmt.Errorf("s3 configuration is required for AWSS3 provider")
+	}
+	if cfg.S3.Bucket == "" {
+		return nil, nil, fmt.Errorf("s3.bucket is required")
+	}
+
+	source := &S3Source{
+		Bucket:      cfg.S3.Bucket,
+		Prefix:      cfg.S3.Prefix,
+		Region:      cfg.S3.Region,
+		RoleARN:     cfg.S3.RoleARN,
+		SessionName: id.SessionName(),
+	}
+
+	return source, &S3EnvelopeParser{}, nil
+}
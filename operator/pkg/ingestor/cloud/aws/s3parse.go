@@ -0,0 +1,131 @@
+package aws
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// parseS3Object extracts Kubernetes audit events from an S3 object holding
+// archived EKS audit logs. The object may be gzip-compressed and contain:
+//
+//   - newline-delimited audit events;
+//   - CloudWatch Logs subscription payloads ({"messageType":"DATA_MESSAGE",
+//     "logEvents":[...]}), as delivered by Kinesis Data Firehose, which
+//     concatenates records without a separator;
+//   - CloudWatch Logs export task lines ("<timestamp> <event JSON>").
+//
+// Malformed lines are skipped; an error is returned only when the object
+// contained no usable record at all.
+func parseS3Object(body []byte) ([]auditv1.Event, error) {
+	if len(body) == 0 {
+		return nil, nil
+	}
+	if isGzip(body) {
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("opening gzip object: %w", err)
+		}
+		decompressed, err := io.ReadAll(gz)
+		if err != nil {
+			return nil, fmt.Errorf("decompressing object: %w", err)
+		}
+		body = decompressed
+	}
+
+	var events []auditv1.Event
+	var parsed, malformed int
+	var lastErr error
+	r := bufio.NewReader(bytes.NewReader(body))
+	for {
+		line, readErr := r.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			lineEvents, err := parseS3Line(line)
+			if err != nil {
+				malformed++
+				lastErr = err
+			} else {
+				parsed++
+				events = append(events, lineEvents...)
+			}
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	if parsed == 0 && malformed > 0 {
+		return nil, fmt.Errorf("no parseable records in object (%d malformed lines): %w", malformed, lastErr)
+	}
+	return events, nil
+}
+
+// parseS3Line parses one line, which may hold several concatenated records.
+func parseS3Line(line []byte) ([]auditv1.Event, error) {
+	// Export task lines are prefixed with an ISO-8601 timestamp.
+	if line[0] != '{' && line[0] != '[' {
+		i := bytes.IndexByte(line, ' ')
+		if i < 0 {
+			return nil, fmt.Errorf("unrecognised record %.40q", line)
+		}
+		line = bytes.TrimSpace(line[i+1:])
+	}
+
+	var events []auditv1.Event
+	dec := json.NewDecoder(bytes.NewReader(line))
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return events, nil
+			}
+			return nil, fmt.Errorf("decoding record: %w", err)
+		}
+		recordEvents, err := parseS3Record(raw)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, recordEvents...)
+	}
+}
+
+// parseS3Record parses a single JSON record: a subscription payload, an audit
+// event, or an array of audit events.
+func parseS3Record(raw json.RawMessage) ([]auditv1.Event, error) {
+	if raw[0] == '{' {
+		var envelope struct {
+			MessageType string `json:"messageType"`
+			LogEvents   []struct {
+				Message string `json:"message"`
+			} `json:"logEvents"`
+		}
+		if err := json.Unmarshal(raw, &envelope); err != nil {
+			return nil, fmt.Errorf("unmarshaling record: %w", err)
+		}
+		if envelope.MessageType != "" {
+			// CONTROL_MESSAGE records are connectivity checks.
+			if envelope.MessageType != "DATA_MESSAGE" {
+				return nil, nil
+			}
+			var events []auditv1.Event
+			for _, le := range envelope.LogEvents {
+				e, err := parseCloudWatchEvent([]byte(le.Message))
+				if err != nil {
+					return nil, err
+				}
+				events = append(events, e...)
+			}
+			return events, nil
+		}
+	}
+	return parseCloudWatchEvent(raw)
+}
+
+func isGzip(body []byte) bool {
+	return len(body) >= 2 && body[0] == 0x1f && body[1] == 0x8b
+}
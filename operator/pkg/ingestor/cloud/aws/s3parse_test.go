@@ -0,0 +1,94 @@
+package aws
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"testing"
+)
+
+func subscriptionPayload(messageType string, events ...[]byte) []byte {
+	var logEvents []map[string]interface{}
+	for i, e := range events {
+		logEvents = append(logEvents, map[string]interface{}{"id": i, "timestamp": 0, "message": string(e)})
+	}
+	b, _ := json.Marshal(map[string]interface{}{
+		"messageType": messageType,
+		"logGroup":    "/aws/eks/prod/cluster",
+		"logEvents":   logEvents,
+	})
+	return b
+}
+
+func gzipBytes(t *testing.T, b []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func joinLines(lines ...[]byte) []byte {
+	return append(bytes.Join(lines, []byte("\n")), '\n')
+}
+
+func TestParseS3Object(t *testing.T) {
+	a1 := makeAuditEvent("a1", "get", "/api/v1/pods")
+	a2 := makeAuditEvent("a2", "list", "/api/v1/services")
+	a3 := makeAuditEvent("a3", "create", "/api/v1/configmaps")
+
+	tests := []struct {
+		name    string
+		input   []byte
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty object", input: nil},
+		{name: "newline-delimited events", input: joinLines(a1, a2), want: []string{"a1", "a2"}},
+		{name: "gzip-compressed", input: gzipBytes(t, joinLines(a1, a2)), want: []string{"a1", "a2"}},
+		{
+			name: "firehose concatenated subscription payloads",
+			input: gzipBytes(t, append(subscriptionPayload("DATA_MESSAGE", a1, a2),
+				subscriptionPayload("DATA_MESSAGE", a3)...)),
+			want: []string{"a1", "a2", "a3"},
+		},
+		{
+			name:  "control message skipped",
+			input: append(subscriptionPayload("CONTROL_MESSAGE", []byte("CWL CONTROL MESSAGE")), subscriptionPayload("DATA_MESSAGE", a1)...),
+			want:  []string{"a1"},
+		},
+		{
+			name:  "export task lines",
+			input: joinLines(append([]byte("2025-01-01T00:00:00.000Z "), a1...), append([]byte("2025-01-01T00:00:01.000Z "), a2...)),
+			want:  []string{"a1", "a2"},
+		},
+		{name: "malformed line skipped", input: joinLines(a1, []byte("{broken"), a2), want: []string{"a1", "a2"}},
+		{name: "nothing parseable", input: joinLines([]byte("garbage"), []byte("{broken")), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := parseS3Object(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseS3Object() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var ids []string
+			for _, e := range events {
+				ids = append(ids, string(e.AuditID))
+			}
+			if len(ids) != len(tt.want) {
+				t.Fatalf("got %v, want %v", ids, tt.want)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", ids, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+//go:build aws
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/felixnotka/audicia/operator/pkg/ingestor/cloud"
+)
+
+// maxObjectsPerPoll is the number of keys listed per ListObjectsV2 call.
+// Each object becomes one message, so this bounds the batch size.
+const maxObjectsPerPoll = 20
+
+// S3Source implements cloud.MessageSource over an S3 bucket of archived
+// audit logs. Objects are listed in key order and each one is delivered as a
+// single message; the checkpoint is the key of the last processed object.
+// Objects written later under a key that sorts before the checkpoint are
+// never read, so keys must sort chronologically.
+type S3Source struct {
+	Bucket string
+	Prefix string
+	Region string // Optional: if empty, uses AWS_REGION from environment.
+
+	// RoleARN is an optional IAM role assumed for this source only.
+	RoleARN string
+
+	// SessionName is the STS role session name used when assuming RoleARN.
+	SessionName string
+
+	mu         sync.Mutex
+	client     *s3.Client
+	startAfter string    // Exclusive lower bound for ListObjectsV2.
+	notBefore  time.Time // Without a checkpoint, older objects are skipped.
+}
+
+func (s *S3Source) Connect(ctx context.Context) error {
+	cfg, err := loadAWSConfig(ctx, s.Region, s.RoleARN, s.SessionName)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.client = s3.NewFromConfig(cfg)
+	if s.startAfter == "" {
+		s.notBefore = time.Now().Add(-defaultLookback)
+	}
+	s.mu.Unlock()
+
+	log.Info("connected to S3",
+		"bucket", s.Bucket, "prefix", s.Prefix, "region", cfg.Region, "roleARN", s.RoleARN)
+	return nil
+}
+
+func (s *S3Source) Receive(ctx context.Context) ([]cloud.Message, error) {
+	s.mu.Lock()
+	client := s.client
+	startAfter := s.startAfter
+	notBefore := s.notBefore
+	s.mu.Unlock()
+
+	if client == nil {
+		return nil, fmt.Errorf("S3 client not connected")
+	}
+
+	input := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(s.Bucket),
+		MaxKeys: aws.Int32(maxObjectsPerPoll),
+	}
+	if s.Prefix != "" {
+		input.Prefix = aws.String(s.Prefix)
+	}
+	if startAfter != "" {
+		input.StartAfter = aws.String(startAfter)
+	}
+
+	resp, err := client.ListObjectsV2(ctx, input)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("ListObjectsV2: %w", err)
+	}
+
+	if len(resp.Contents) == 0 {
+		// Caught up — wait before listing again.
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+		return nil, nil
+	}
+
+	msgs := make([]cloud.Message, 0, len(resp.Contents))
+	last := startAfter
+	for _, obj := range resp.Contents {
+		key := aws.ToString(obj.Key)
+		modified := aws.ToTime(obj.LastModified)
+		if strings.HasSuffix(key, "/") || (!notBefore.IsZero() && modified.Before(notBefore)) {
+			last = key
+			continue
+		}
+
+		// A failed download returns before startAfter moves, so the object
+		// is retried on the next call.
+		body, err := s.getObject(ctx, client, key)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, cloud.Message{
+			Body:           body,
+			SequenceNumber: key,
+			Partition:      s.Bucket,
+			EnqueuedTime:   modified.UTC().Format(time.RFC3339),
+		})
+		last = key
+	}
+
+	s.mu.Lock()
+	s.startAfter = last
+	s.mu.Unlock()
+
+	return msgs, nil
+}
+
+func (s *S3Source) getObject(ctx context.Context, client *s3.Client, key string) ([]byte, error) {
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetObject %s: %w", key, err)
+	}
+	defer func() { _ = out.Body.Close() }()
+
+	body, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading object %s: %w", key, err)
+	}
+	return body, nil
+}
+
+func (s *S3Source) Acknowledge(_ context.Context, _ []cloud.Message) error {
+	// S3 is pull-based — no acknowledgment needed. The last processed key is
+	// persisted by CloudIngestor.updatePosition() as this bucket's offset.
+	return nil
+}
+
+func (s *S3Source) Close(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.client = nil
+	log.Info("closed S3 source")
+	return nil
+}
+
+// RestoreCheckpoint implements cloud.CheckpointRestorer. Listing resumes after
+// the last processed object key.
+func (s *S3Source) RestoreCheckpoint(pos cloud.CloudPosition) {
+	key := pos.PartitionOffsets[s.Bucket]
+	if key == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.startAfter = key
+	log.Info("restored checkpoint", "startAfter", key)
+}
@@ -4,14 +4,17 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/felixnotka/audicia/operator/pkg/ingestor/cloud"
@@ -30,48 +33,61 @@ const defaultLookback = 5 * time.Minute
 const maxEventsPerPage = 100
 
 // CloudWatchSource implements cloud.MessageSource using the AWS CloudWatch
-// Logs FilterLogEvents API. It polls for new audit log events and converts
-// them to cloud.Message for the shared CloudIngestor receive loop.
+// Logs FilterLogEvents API. It polls its log groups in turn for new audit
+// log events and converts them to cloud.Message for the shared CloudIngestor
+// receive loop. Each log group is a partition whose offset is the group's
+// logGroupCursor.
 type CloudWatchSource struct {
-	LogGroupName    string
+	LogGroups       []string
 	LogStreamPrefix string
+	FilterPattern   string
 	Region          string // Optional: if empty, uses AWS_REGION from environment.
 
-	mu        sync.Mutex
-	client    *cloudwatchlogs.Client
-	startTime int64 // Millis since epoch — exclusive lower bound for FilterLogEvents.
-	nextToken *string
-}
+	// RoleARN is an optional IAM role assumed for this source only. The
+	// assumed-role credentials are cached on this source's config and never
+	// shared with other sources.
+	RoleARN string
 
-func (s *CloudWatchSource) Connect(ctx context.Context) error {
-	var opts []func(*config.LoadOptions) error
+	// SessionName is the STS role session name used when assuming RoleARN.
+	SessionName string
 
-	if s.Region != "" {
-		opts = append(opts, config.WithRegion(s.Region))
-	}
+	mu      sync.Mutex
+	client  *cloudwatchlogs.Client
+	cursors map[string]logGroupCursor
+	next    int // Index of the log group polled next.
+	idle    int // Consecutive polls that found nothing new.
+}
 
-	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+func (s *CloudWatchSource) Connect(ctx context.Context) error {
+	cfg, err := loadAWSConfig(ctx, s.Region, s.RoleARN, s.SessionName)
 	if err != nil {
-		return fmt.Errorf("loading AWS config: %w", err)
+		return err
 	}
 
 	s.mu.Lock()
 	s.client = cloudwatchlogs.NewFromConfig(cfg)
-	if s.startTime == 0 {
-		s.startTime = time.Now().Add(-defaultLookback).UnixMilli()
+	if s.cursors == nil {
+		s.cursors = make(map[string]logGroupCursor, len(s.LogGroups))
+	}
+	start := time.Now().Add(-defaultLookback).UnixMilli()
+	for _, group := range s.LogGroups {
+		if _, ok := s.cursors[group]; !ok {
+			s.cursors[group] = logGroupCursor{StartTime: start}
+		}
 	}
 	s.mu.Unlock()
 
 	log.Info("connected to CloudWatch Logs",
-		"logGroup", s.LogGroupName, "region", cfg.Region)
+		"logGroups", s.LogGroups, "region", cfg.Region, "roleARN", s.RoleARN)
 	return nil
 }
 
 func (s *CloudWatchSource) Receive(ctx context.Context) ([]cloud.Message, error) {
 	s.mu.Lock()
 	client := s.client
-	startTime := s.startTime
-	nextToken := s.nextToken
+	group := s.LogGroups[s.next]
+	cursor := s.cursors[group]
+	s.next = (s.next + 1) % len(s.LogGroups)
 	s.mu.Unlock()
 
 	if client == nil {
@@ -79,17 +95,18 @@ func (s *CloudWatchSource) Receive(ctx context.Context) ([]cloud.Message, error)
 	}
 
 	input := &cloudwatchlogs.FilterLogEventsInput{
-		LogGroupName: aws.String(s.LogGroupName),
-		StartTime:    aws.Int64(startTime),
+		LogGroupName: aws.String(group),
+		StartTime:    aws.Int64(cursor.StartTime),
 		Limit:        aws.Int32(maxEventsPerPage),
 	}
-
 	if s.LogStreamPrefix != "" {
 		input.LogStreamNamePrefix = aws.String(s.LogStreamPrefix)
 	}
-
-	if nextToken != nil {
-		input.NextToken = nextToken
+	if s.FilterPattern != "" {
+		input.FilterPattern = aws.String(s.FilterPattern)
+	}
+	if cursor.NextToken != "" {
+		input.NextToken = aws.String(cursor.NextToken)
 	}
 
 	resp, err := client.FilterLogEvents(ctx, input)
@@ -97,33 +114,45 @@ func (s *CloudWatchSource) Receive(ctx context.Context) ([]cloud.Message, error)
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
 		}
-		return nil, fmt.Errorf("FilterLogEvents: %w", err)
-	}
-
-	// Update pagination and startTime atomically.
-	//
-	// startTime is only advanced when pagination completes (NextToken == nil)
-	// to avoid skipping events at page boundaries. FilterLogEvents returns
-	// events sorted ascending by Timestamp, so the last event in the final
-	// page has the highest timestamp across all pages. We advance startTime
-	// past it so the next poll cycle starts fresh.
-	//
-	// If we advanced startTime after each page (like in Acknowledge), page 2
-	// events sharing the same millisecond as page 1's last event would be
-	// skipped because startTime is exclusive (lastTimestamp + 1).
-	s.mu.Lock()
-	s.nextToken = resp.NextToken
-	if resp.NextToken == nil && len(resp.Events) > 0 {
-		last := resp.Events[len(resp.Events)-1]
-		if last.Timestamp != nil {
-			s.startTime = aws.ToInt64(last.Timestamp) + 1
+		var invalid *types.InvalidParameterException
+		if cursor.NextToken != "" && errors.As(err, &invalid) {
+			// Tokens expire; start a new query after the last event read.
+			s.mu.Lock()
+			s.cursors[group] = cursor.restart()
+			s.mu.Unlock()
+		}
+		return nil, fmt.Errorf("FilterLogEvents %s: %w", group, err)
+	}
+
+	// The query's StartTime is only advanced when pagination completes, to
+	// avoid skipping events at page boundaries: FilterLogEvents returns
+	// events sorted ascending by Timestamp, and page 2 events sharing the
+	// same millisecond as page 1's last event would be skipped by an
+	// exclusive StartTime of lastTimestamp + 1.
+	timestamps := make([]int64, 0, len(resp.Events))
+	for _, event := range resp.Events {
+		if event.Timestamp != nil {
+			timestamps = append(timestamps, aws.ToInt64(event.Timestamp))
 		}
 	}
+	cursor = cursor.advance(timestamps, aws.ToString(resp.NextToken))
+
+	s.mu.Lock()
+	s.cursors[group] = cursor
+	if len(resp.Events) > 0 || resp.NextToken != nil {
+		s.idle = 0
+	} else {
+		s.idle++
+	}
+	wait := s.idle >= len(s.LogGroups)
+	if wait {
+		s.idle = 0
+	}
 	s.mu.Unlock()
 
 	if len(resp.Events) == 0 {
-		if resp.NextToken == nil {
-			// All pages consumed — wait before polling again.
+		if wait {
+			// All log groups consumed — wait before polling again.
 			select {
 			case <-ctx.Done():
 				return nil, ctx.Err()
@@ -135,13 +164,39 @@ func (s *CloudWatchSource) Receive(ctx context.Context) ([]cloud.Message, error)
 
 	msgs := make([]cloud.Message, 0, len(resp.Events))
 	for _, event := range resp.Events {
-		msgs = append(msgs, convertEvent(event))
+		msgs = append(msgs, convertEvent(event, group, cursor))
 	}
 
 	return msgs, nil
 }
 
-// convertEvent converts a CloudWatch FilteredLogEvent to a cloud.Message.
+// loadAWSConfig loads the default AWS config, optionally assuming roleARN in
+// a session owned by the calling source.
+func loadAWSConfig(ctx context.Context, region, roleARN, sessionName string) (aws.Config, error) {
+	var opts []func(*config.LoadOptions) error
+
+	if region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("loading AWS config: %w", err)
+	}
+
+	if roleARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), roleARN,
+			func(o *stscreds.AssumeRoleOptions) {
+				o.RoleSessionName = sessionName
+			})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+	return cfg, nil
+}
+
+// convertEvent converts a CloudWatch FilteredLogEvent of group to a
+// cloud.Message. Every message of a page carries the cursor after the page
+// as its sequence number, so the checkpoint resumes the group after it.
 //
 // EnqueuedTime uses the event's Timestamp (when the event occurred), NOT
 // IngestionTime (when CloudWatch received it). This is critical because
@@ -149,17 +204,11 @@ func (s *CloudWatchSource) Receive(ctx context.Context) ([]cloud.Message, error)
 // on IngestionTime but filtered on Timestamp, we could miss events where
 // Timestamp < IngestionTime (which is the common case — there is always
 // some delay between event creation and CloudWatch ingestion).
-func convertEvent(event types.FilteredLogEvent) cloud.Message {
+func convertEvent(event types.FilteredLogEvent, group string, cursor logGroupCursor) cloud.Message {
 	msg := cloud.Message{
-		Body: []byte(aws.ToString(event.Message)),
-	}
-
-	if event.EventId != nil {
-		msg.SequenceNumber = aws.ToString(event.EventId)
-	}
-
-	if event.LogStreamName != nil {
-		msg.Partition = aws.ToString(event.LogStreamName)
+		Body:           []byte(aws.ToString(event.Message)),
+		Partition:      group,
+		SequenceNumber: cursor.String(),
 	}
 
 	if event.Timestamp != nil {
@@ -180,25 +229,41 @@ func (s *CloudWatchSource) Close(_ context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.client = nil
-	s.nextToken = nil
 	log.Info("closed CloudWatch Logs source")
 	return nil
 }
 
-// RestoreCheckpoint implements cloud.CheckpointRestorer. It sets the
-// startTime from the saved CloudPosition so that polling resumes from
-// the last acknowledged event after a restart.
+// RestoreCheckpoint implements cloud.CheckpointRestorer. Each log group
+// resumes from its cursor in the saved CloudPosition. Groups without one,
+// as in checkpoints written before groups were tracked, start after the
+// last processed event.
 func (s *CloudWatchSource) RestoreCheckpoint(pos cloud.CloudPosition) {
-	if pos.LastTimestamp == "" {
-		return
-	}
-	t, err := time.Parse(time.RFC3339, pos.LastTimestamp)
-	if err != nil {
-		log.V(1).Info("failed to parse checkpoint timestamp", "timestamp", pos.LastTimestamp, "error", err)
-		return
+	var fallback int64
+	if pos.LastTimestamp != "" {
+		t, err := time.Parse(time.RFC3339, pos.LastTimestamp)
+		if err != nil {
+			log.V(1).Info("failed to parse checkpoint timestamp", "timestamp", pos.LastTimestamp, "error", err)
+		} else {
+			fallback = t.UnixMilli() + 1 // Start after the last processed event.
+		}
 	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.startTime = t.UnixMilli() + 1 // Start after the last processed event.
-	log.Info("restored checkpoint", "startTime", s.startTime, "from", pos.LastTimestamp)
+	s.cursors = make(map[string]logGroupCursor, len(s.LogGroups))
+	for _, group := range s.LogGroups {
+		if offset, ok := pos.PartitionOffsets[group]; ok {
+			cursor, err := parseCursor(offset)
+			if err == nil {
+				s.cursors[group] = cursor
+				log.Info("restored checkpoint", "logGroup", group, "cursor", cursor.String())
+				continue
+			}
+			log.V(1).Info("failed to parse checkpoint cursor", "logGroup", group, "error", err)
+		}
+		if fallback != 0 {
+			s.cursors[group] = logGroupCursor{StartTime: fallback}
+			log.Info("restored checkpoint", "logGroup", group, "startTime", fallback, "from", pos.LastTimestamp)
+		}
+	}
 }
@@ -1,27 +1,62 @@
 package azure
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/felixnotka/audicia/operator/pkg/ingestor/cloud"
 )
 
-// diagnosticEnvelope is the top-level Azure Diagnostic Settings JSON structure.
+var parseLog = ctrl.Log.WithName("ingestor").WithName("cloud").WithName("azure")
+
+// maxLogEncodings is how many times the audit event in properties.log may be
+// JSON-encoded as a string. AKS encodes it once; the newer diagnostic schema
+// and some forwarders encode the string again.
+const maxLogEncodings = 3
+
+// diagnosticEnvelope is the top-level Azure Diagnostic Settings JSON
+// structure. Records are decoded one by one, so a record of an unexpected
+// shape only skips itself rather than the whole batch.
 type diagnosticEnvelope struct {
-	Records []diagnosticRecord `json:"records"`
+	Records []json.RawMessage `json:"records"`
 }
 
 // diagnosticRecord is a single record within the Diagnostic Settings envelope.
 type diagnosticRecord struct {
-	Category   string           `json:"category"`
+	Category string `json:"category"`
+
+	// ResourceID is the resource ID of the AKS cluster that emitted the
+	// record, upper-cased by Azure.
+	ResourceID string `json:"resourceId"`
+
 	Properties recordProperties `json:"properties"`
 }
 
 // recordProperties holds the embedded audit event.
 type recordProperties struct {
-	// Log contains the JSON-encoded Kubernetes audit event as a string.
-	Log string `json:"log"`
+	// Log contains the Kubernetes audit event: a JSON string holding the
+	// encoded event, possibly encoded more than once, or the event object.
+	Log json.RawMessage `json:"log"`
+}
+
+// UnmarshalJSON accepts the properties object as well as properties
+// JSON-encoded into a string, as the newer diagnostic schema delivers them.
+func (p *recordProperties) UnmarshalJSON(data []byte) error {
+	type plain recordProperties
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var encoded string
+		if err := json.Unmarshal(data, &encoded); err != nil {
+			return err
+		}
+		data = []byte(encoded)
+	}
+	return json.Unmarshal(data, (*plain)(p))
 }
 
 // auditCategories are the Diagnostic Settings categories that contain
@@ -31,9 +66,17 @@ var auditCategories = map[string]bool{
 	"kube-audit-admin": true,
 }
 
+// isAuditCategory reports whether category holds audit events. Categories
+// are compared ignoring case, as the schemas differ in their spelling.
+func isAuditCategory(category string) bool {
+	return auditCategories[strings.ToLower(category)]
+}
+
 // parseEnvelope extracts Kubernetes audit events from an Azure Diagnostic
 // Settings envelope. This function is the core parsing logic shared between
-// the build-tagged EnvelopeParser and the untagged parser tests.
+// the build-tagged EnvelopeParser and the untagged parser tests. Records of
+// other categories are skipped; malformed audit records are skipped and
+// logged.
 func parseEnvelope(body []byte) ([]auditv1.Event, error) {
 	var envelope diagnosticEnvelope
 	if err := json.Unmarshal(body, &envelope); err != nil {
@@ -45,21 +88,61 @@ func parseEnvelope(body []byte) ([]auditv1.Event, error) {
 	}
 
 	var events []auditv1.Event
-	for _, rec := range envelope.Records {
-		if !auditCategories[rec.Category] {
+	for i, raw := range envelope.Records {
+		var rec diagnosticRecord
+		if err := json.Unmarshal(raw, &rec); err != nil {
+			var header struct {
+				Category string `json:"category"`
+			}
+			if json.Unmarshal(raw, &header) == nil && isAuditCategory(header.Category) {
+				parseLog.V(1).Info("skipping malformed audit record", "record", i, "error", err)
+			}
 			continue
 		}
-
-		if rec.Properties.Log == "" {
+		if !isAuditCategory(rec.Category) {
 			continue
 		}
 
-		var event auditv1.Event
-		if err := json.Unmarshal([]byte(rec.Properties.Log), &event); err != nil {
-			// Caller (EnvelopeParser.Parse) handles logging; here we just skip.
+		event, ok, err := decodeAuditLog(rec.Properties.Log)
+		if err != nil {
+			parseLog.V(1).Info("skipping malformed audit record",
+				"record", i, "category", rec.Category, "error", err)
+			continue
+		}
+		if !ok {
 			continue
 		}
+		if rec.ResourceID != "" {
+			if event.Annotations == nil {
+				event.Annotations = map[string]string{}
+			}
+			event.Annotations[cloud.ClusterIdentityAnnotation] = rec.ResourceID
+		}
 		events = append(events, event)
 	}
 	return events, nil
 }
+
+// decodeAuditLog decodes the audit event in properties.log, unwrapping up
+// to maxLogEncodings string encodings. It returns false for an empty log.
+func decodeAuditLog(log json.RawMessage) (auditv1.Event, bool, error) {
+	var event auditv1.Event
+	data := bytes.TrimSpace(log)
+	for range maxLogEncodings {
+		if len(data) == 0 || data[0] != '"' {
+			break
+		}
+		var encoded string
+		if err := json.Unmarshal(data, &encoded); err != nil {
+			return event, false, err
+		}
+		data = bytes.TrimSpace([]byte(encoded))
+	}
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return event, false, nil
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return event, false, err
+	}
+	return event, true, nil
+}
@@ -19,8 +19,11 @@ import (
 //	  ]
 //	}
 //
-// Some messages may contain non-audit records (e.g., activity logs). These
-// records are silently skipped.
+// The newer diagnostic schema encodes the audit event string a second time,
+// or delivers properties itself as a JSON string; both are unwrapped. A
+// message may mix audit records with records of other categories (e.g.,
+// kube-apiserver or guard logs), which are skipped. Malformed audit records
+// are skipped and logged without failing the rest of the message.
 type EnvelopeParser struct{}
 
 func (p *EnvelopeParser) Parse(body []byte) ([]auditv1.Event, error) {
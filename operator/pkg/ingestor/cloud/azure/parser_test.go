@@ -3,11 +3,18 @@ package azure
 import (
 	"encoding/json"
 	"testing"
+
+	"github.com/felixnotka/audicia/operator/pkg/ingestor/cloud"
 )
 
-func makeEnvelope(records ...diagnosticRecord) []byte {
-	env := diagnosticEnvelope{Records: records}
-	b, _ := json.Marshal(env)
+func makeEnvelope(records ...any) []byte {
+	b, _ := json.Marshal(map[string]any{"records": records})
+	return b
+}
+
+// logString encodes s as the JSON string properties.log holds.
+func logString(s string) json.RawMessage {
+	b, _ := json.Marshal(s)
 	return b
 }
 
@@ -20,7 +27,7 @@ func makeAuditRecord(category, auditID, verb string) diagnosticRecord {
 	eventJSON, _ := json.Marshal(event)
 	return diagnosticRecord{
 		Category:   category,
-		Properties: recordProperties{Log: string(eventJSON)},
+		Properties: recordProperties{Log: logString(string(eventJSON))},
 	}
 }
 
@@ -53,7 +60,7 @@ func TestEnvelopeParsing(t *testing.T) {
 		{
 			name: "non-audit category skipped",
 			input: makeEnvelope(
-				diagnosticRecord{Category: "kube-controller-manager", Properties: recordProperties{Log: "{}"}},
+				diagnosticRecord{Category: "kube-controller-manager", Properties: recordProperties{Log: logString("{}")}},
 				makeAuditRecord("kube-audit", "a1", "get"),
 			),
 			wantEvents: 1,
@@ -72,7 +79,7 @@ func TestEnvelopeParsing(t *testing.T) {
 		{
 			name: "empty log field skipped",
 			input: makeEnvelope(
-				diagnosticRecord{Category: "kube-audit", Properties: recordProperties{Log: ""}},
+				diagnosticRecord{Category: "kube-audit", Properties: recordProperties{}},
 				makeAuditRecord("kube-audit", "a1", "get"),
 			),
 			wantEvents: 1,
@@ -80,7 +87,7 @@ func TestEnvelopeParsing(t *testing.T) {
 		{
 			name: "malformed audit event in log skipped",
 			input: makeEnvelope(
-				diagnosticRecord{Category: "kube-audit", Properties: recordProperties{Log: "not valid json"}},
+				diagnosticRecord{Category: "kube-audit", Properties: recordProperties{Log: logString("not valid json")}},
 				makeAuditRecord("kube-audit", "a1", "get"),
 			),
 			wantEvents: 1,
@@ -120,3 +127,85 @@ func TestEnvelopeFieldExtraction(t *testing.T) {
 		t.Errorf("RequestURI = %q, want %q", events[0].RequestURI, "/api/v1/pods")
 	}
 }
+
+func TestEnvelopeClusterIdentity(t *testing.T) {
+	rec := makeAuditRecord("kube-audit", "a1", "get")
+	rec.ResourceID = "/SUBSCRIPTIONS/ABC/RESOURCEGROUPS/RG/PROVIDERS/MICROSOFT.CONTAINERSERVICE/MANAGEDCLUSTERS/MYCLUSTER"
+	events, err := parseEnvelope(makeEnvelope(rec, makeAuditRecord("kube-audit", "a2", "get")))
+	if err != nil {
+		t.Fatalf("parseEnvelope() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if got := events[0].Annotations[cloud.ClusterIdentityAnnotation]; got != rec.ResourceID {
+		t.Errorf("cluster identity = %q, want %q", got, rec.ResourceID)
+	}
+	if _, ok := events[1].Annotations[cloud.ClusterIdentityAnnotation]; ok {
+		t.Error("record without resourceId got a cluster identity")
+	}
+}
+
+func TestEnvelopeLogEncodings(t *testing.T) {
+	eventJSON := `{"auditID":"a1","verb":"delete","requestURI":"/api/v1/namespaces/default/secrets/s"}`
+	tests := []struct {
+		name   string
+		record any
+	}{
+		{
+			name:   "double-encoded log",
+			record: diagnosticRecord{Category: "kube-audit-admin", Properties: recordProperties{Log: logString(string(logString(eventJSON)))}},
+		},
+		{
+			name:   "log as object",
+			record: diagnosticRecord{Category: "kube-audit-admin", Properties: recordProperties{Log: json.RawMessage(eventJSON)}},
+		},
+		{
+			name: "properties as string",
+			record: map[string]any{
+				"category":   "kube-audit-admin",
+				"properties": `{"log":` + string(logString(eventJSON)) + `,"stream":"stdout"}`,
+			},
+		},
+		{
+			name:   "category in other case",
+			record: diagnosticRecord{Category: "KUBE-AUDIT-ADMIN", Properties: recordProperties{Log: logString(eventJSON)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := parseEnvelope(makeEnvelope(tt.record))
+			if err != nil {
+				t.Fatalf("parseEnvelope() error = %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("got %d events, want 1", len(events))
+			}
+			if events[0].AuditID != "a1" || events[0].Verb != "delete" {
+				t.Errorf("event = %s %s, want a1 delete", events[0].AuditID, events[0].Verb)
+			}
+		})
+	}
+}
+
+func TestEnvelopeMixedRecords(t *testing.T) {
+	input := makeEnvelope(
+		makeAuditRecord("kube-audit", "a1", "get"),
+		map[string]any{"category": "kube-apiserver", "properties": map[string]any{"log": 42}},
+		map[string]any{"category": "kube-audit-admin", "properties": 42},
+		"not a record",
+		map[string]any{"category": "guard", "properties": map[string]any{"log": "I0101 guard started"}},
+		makeAuditRecord("kube-audit-admin", "a2", "create"),
+	)
+	events, err := parseEnvelope(input)
+	if err != nil {
+		t.Fatalf("parseEnvelope() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if events[0].AuditID != "a1" || events[1].AuditID != "a2" {
+		t.Errorf("audit IDs = %s, %s, want a1, a2", events[0].AuditID, events[1].AuditID)
+	}
+}
@@ -13,7 +13,7 @@ func init() {
 	cloud.RegisterAdapter(audiciav1alpha1.CloudProviderAzureEventHub, buildAzureAdapter)
 }
 
-func buildAzureAdapter(cfg *audiciav1alpha1.CloudConfig) (cloud.MessageSource, cloud.EnvelopeParser, error) {
+func buildAzureAdapter(cfg *audiciav1alpha1.CloudConfig, _ cloud.SourceIdentity) (cloud.MessageSource, cloud.EnvelopeParser, error) {
 	if cfg.Azure == nil {
 		return nil, nil, fmt.Errorf("azure configuration is required for AzureEventHub provider")
 	}
@@ -31,6 +31,8 @@ func buildAzureAdapter(cfg *audiciav1alpha1.CloudConfig) (cloud.MessageSource, c
 		ConsumerGroup:        cfg.Azure.ConsumerGroup,
 		StorageAccountURL:    cfg.Azure.StorageAccountURL,
 		StorageContainerName: cfg.Azure.StorageContainerName,
+		ClientID:             cfg.Azure.ClientID,
+		TenantID:             cfg.Azure.TenantID,
 	}
 
 	return source, &EnvelopeParser{}, nil
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs/v2"
 	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs/v2/checkpoints"
@@ -23,7 +24,8 @@ var log = ctrl.Log.WithName("ingestor").WithName("cloud").WithName("azure")
 
 // EventHubSource implements cloud.MessageSource using the Azure Event Hub Processor.
 // It uses the load-balanced Processor pattern for distributed consumption.
-// Authentication is via Azure Workload Identity (DefaultAzureCredential).
+// Authentication is via Azure Workload Identity: a dedicated credential for
+// ClientID when set, otherwise DefaultAzureCredential.
 type EventHubSource struct {
 	Namespace     string // Fully qualified namespace (e.g., "myns.servicebus.windows.net")
 	EventHub      string
@@ -34,6 +36,11 @@ type EventHubSource struct {
 	StorageAccountURL    string
 	StorageContainerName string
 
+	// ClientID and TenantID select the Workload Identity this source
+	// authenticates as. Empty ClientID falls back to DefaultAzureCredential.
+	ClientID string
+	TenantID string
+
 	mu              sync.Mutex
 	consumerClient  *azeventhubs.ConsumerClient
 	processor       *azeventhubs.Processor
@@ -49,7 +56,7 @@ func (s *EventHubSource) Connect(ctx context.Context) error {
 		consumerGroup = azeventhubs.DefaultConsumerGroup
 	}
 
-	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	cred, err := s.newCredential()
 	if err != nil {
 		return fmt.Errorf("creating Azure credential: %w", err)
 	}
@@ -59,7 +66,7 @@ func (s *EventHubSource) Connect(ctx context.Context) error {
 		return fmt.Errorf("creating Event Hub consumer client: %w", err)
 	}
 
-	checkpointStore, err := s.buildCheckpointStore()
+	checkpointStore, err := s.buildCheckpointStore(cred)
 	if err != nil {
 		if closeErr := client.Close(ctx); closeErr != nil {
 			log.V(1).Info("failed to close client after checkpoint store error", "error", closeErr)
@@ -109,7 +116,8 @@ func (s *EventHubSource) Connect(ctx context.Context) error {
 	go s.dispatchPartitions(processorCtx)
 
 	log.Info("connected to Event Hub",
-		"namespace", s.Namespace, "eventHub", s.EventHub, "consumerGroup", consumerGroup)
+		"namespace", s.Namespace, "eventHub", s.EventHub, "consumerGroup", consumerGroup,
+		"clientID", s.ClientID)
 	return nil
 }
 
@@ -264,18 +272,28 @@ func (s *EventHubSource) dispatchPartitions(ctx context.Context) {
 	}
 }
 
-func (s *EventHubSource) buildCheckpointStore() (azeventhubs.CheckpointStore, error) {
+// newCredential builds the token credential for this source. With a ClientID,
+// a dedicated Workload Identity credential is created so that sources with
+// different identities never share a token cache.
+func (s *EventHubSource) newCredential() (azcore.TokenCredential, error) {
+	if s.ClientID == "" {
+		return azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+			TenantID: s.TenantID,
+		})
+	}
+	return azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+		ClientID: s.ClientID,
+		TenantID: s.TenantID,
+	})
+}
+
+func (s *EventHubSource) buildCheckpointStore(cred azcore.TokenCredential) (azeventhubs.CheckpointStore, error) {
 	if s.StorageAccountURL == "" || s.StorageContainerName == "" {
 		// No external checkpoint store configured — use in-memory.
 		// Checkpoints are persisted in AudiciaSource status instead.
 		return newInMemoryCheckpointStore(), nil
 	}
 
-	cred, err := azidentity.NewDefaultAzureCredential(nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating credential for checkpoint store: %w", err)
-	}
-
 	blobURL := fmt.Sprintf("%s/%s", s.StorageAccountURL, s.StorageContainerName)
 	containerClient, err := container.NewClient(blobURL, cred, nil)
 	if err != nil {
@@ -2,6 +2,7 @@ package cloud
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -30,6 +31,7 @@ type CloudIngestor struct {
 
 	mu       sync.Mutex
 	position CloudPosition
+	onError  func(error)
 }
 
 // NewCloudIngestor creates a cloud-based ingestor.
@@ -86,6 +88,17 @@ func (c *CloudIngestor) CloudCheckpoint() CloudPosition {
 	return cp
 }
 
+// OnError implements ingestor.ErrorReporter.
+func (c *CloudIngestor) OnError(fn func(error)) {
+	c.onError = fn
+}
+
+func (c *CloudIngestor) reportError(err error) {
+	if c.onError != nil {
+		c.onError(err)
+	}
+}
+
 func (c *CloudIngestor) receiveLoop(ctx context.Context, ch chan<- auditv1.Event) {
 	defer c.closeSource(ch)
 
@@ -109,7 +122,7 @@ func (c *CloudIngestor) receiveLoop(ctx context.Context, ch chan<- auditv1.Event
 		}
 
 		c.acknowledgeBatch(ctx, msgs)
-		c.updatePosition(msgs[len(msgs)-1])
+		c.updatePosition(msgs)
 
 		cloudLog.V(1).Info("processed batch",
 			"messages", len(msgs), "events", emitted)
@@ -133,6 +146,7 @@ func (c *CloudIngestor) handleReceiveError(ctx context.Context, err error) bool
 	}
 	metrics.CloudReceiveErrorsTotal.WithLabelValues(c.ProviderLabel).Inc()
 	cloudLog.Error(err, "error receiving messages, retrying in 5s")
+	c.reportError(fmt.Errorf("receiving messages: %w", err))
 	select {
 	case <-ctx.Done():
 		return true
@@ -189,7 +203,9 @@ func (c *CloudIngestor) emitMessageEvents(ctx context.Context, ch chan<- auditv1
 	var emitted int
 	for _, event := range events {
 		if c.Validator != nil && !c.Validator.Matches(event) {
-			cloudLog.V(2).Info("dropping event from different cluster", "auditID", event.AuditID)
+			reason := c.Validator.Verify(event).String()
+			metrics.CloudEventsRejectedTotal.WithLabelValues(c.ProviderLabel, reason).Inc()
+			cloudLog.V(2).Info("dropping event not verified to come from this cluster", "auditID", event.AuditID, "reason", reason)
 			continue
 		}
 		select {
@@ -207,20 +223,25 @@ func (c *CloudIngestor) acknowledgeBatch(ctx context.Context, msgs []Message) {
 	if err := c.Source.Acknowledge(ctx, msgs); err != nil {
 		if ctx.Err() == nil {
 			cloudLog.Error(err, "failed to acknowledge messages")
+			c.reportError(fmt.Errorf("acknowledging messages: %w", err))
 		}
 		return
 	}
 	metrics.CloudMessagesAckedTotal.WithLabelValues(c.ProviderLabel).Inc()
 }
 
-func (c *CloudIngestor) updatePosition(msg Message) {
+// updatePosition records the last message of each partition in a processed
+// batch, so batches spanning several partitions advance all of them.
+func (c *CloudIngestor) updatePosition(msgs []Message) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.position.PartitionOffsets == nil {
 		c.position.PartitionOffsets = make(map[string]string)
 	}
-	c.position.PartitionOffsets[msg.Partition] = msg.SequenceNumber
-	if msg.EnqueuedTime != "" {
-		c.position.LastTimestamp = msg.EnqueuedTime
+	for _, msg := range msgs {
+		c.position.PartitionOffsets[msg.Partition] = msg.SequenceNumber
+		if msg.EnqueuedTime != "" {
+			c.position.LastTimestamp = msg.EnqueuedTime
+		}
 	}
 }
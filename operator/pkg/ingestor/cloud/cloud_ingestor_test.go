@@ -8,8 +8,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"k8s.io/apimachinery/pkg/types"
 	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
 )
 
 // fakeParser implements EnvelopeParser for testing. It unmarshals the message
@@ -486,6 +489,31 @@ func TestCloudIngestor_GracefulShutdownSavesCheckpoint(t *testing.T) {
 	}
 }
 
+func TestCloudIngestor_BatchAdvancesEveryPartition(t *testing.T) {
+	source := NewFakeSource(
+		[]Message{
+			makeMessage("0", "7", "2026-01-01T00:00:00Z", makeEvent("a1", "get", "pods")),
+			makeMessage("1", "3", "2026-01-01T00:00:01Z", makeEvent("a2", "get", "pods")),
+			makeMessage("0", "8", "2026-01-01T00:00:02Z", makeEvent("a3", "get", "pods")),
+		},
+	)
+
+	ing := NewCloudIngestor(source, &fakeParser{}, nil, CloudPosition{}, "test")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := ing.Start(ctx)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if got := collectEvents(ch, 3, 3*time.Second); len(got) != 3 {
+		t.Fatalf("got %d events, want 3", len(got))
+	}
+	cancel()
+	drainChannel(ch)
+
+	assertPartitions(t, ing.CloudCheckpoint(), map[string]string{"0": "8", "1": "3"})
+}
+
 func TestCloudIngestor_PositionAdapter(t *testing.T) {
 	// Verify Checkpoint() returns ingestor.Position with LastTimestamp.
 	source := NewFakeSource(
@@ -654,6 +682,48 @@ func TestCloudIngestor_ValidatorPassesMatchingEvents(t *testing.T) {
 	}
 }
 
+func TestCloudIngestor_StrictValidatorDropsUnverifiedEvents(t *testing.T) {
+	validator := &ClusterIdentityValidator{ExpectedIdentity: "cluster-a", Strict: true}
+
+	matchEvent := makeEvent("a1", "get", "pods")
+	matchEvent.Annotations = map[string]string{ClusterIdentityAnnotation: "cluster-a"}
+	otherEvent := makeEvent("a2", "list", "pods")
+	otherEvent.Annotations = map[string]string{ClusterIdentityAnnotation: "cluster-b"}
+	unknownEvent := makeEvent("a3", "list", "pods")
+
+	body, _ := json.Marshal([]auditv1.Event{matchEvent, otherEvent, unknownEvent})
+	msgs := []Message{{Body: body, Partition: "0", SequenceNumber: "1"}}
+
+	mismatched := metrics.CloudEventsRejectedTotal.WithLabelValues("strict-test", "cluster_mismatch")
+	unknown := metrics.CloudEventsRejectedTotal.WithLabelValues("strict-test", "cluster_unknown")
+	beforeMismatched, beforeUnknown := testutil.ToFloat64(mismatched), testutil.ToFloat64(unknown)
+
+	ing := NewCloudIngestor(NewFakeSource(msgs), &fakeParser{}, validator, CloudPosition{}, "strict-test")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ch, err := ing.Start(ctx)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	received := collectEvents(ch, 1, 3*time.Second)
+	// Give the ingestor time to process the rest of the batch.
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	for event := range ch {
+		received = append(received, event)
+	}
+
+	if len(received) != 1 || received[0].AuditID != "a1" {
+		t.Errorf("received %v, want only a1", received)
+	}
+	if got := testutil.ToFloat64(mismatched) - beforeMismatched; got != 1 {
+		t.Errorf("cluster_mismatch rejections = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(unknown) - beforeUnknown; got != 1 {
+		t.Errorf("cluster_unknown rejections = %v, want 1", got)
+	}
+}
+
 func TestCloudIngestor_RecordBatchMetrics_NoEnqueuedTime(t *testing.T) {
 	// Message with empty EnqueuedTime — should not panic.
 	source := NewFakeSource(
@@ -0,0 +1,37 @@
+package cloud
+
+import (
+	"regexp"
+)
+
+// maxSessionNameLength is the AWS STS RoleSessionName limit, which is also
+// the tightest limit among the supported providers.
+const maxSessionNameLength = 64
+
+// sessionNameInvalidChars matches characters not allowed in an STS session name.
+var sessionNameInvalidChars = regexp.MustCompile(`[^\w+=,.@-]`)
+
+// SourceIdentity identifies the AudiciaSource an adapter is built for.
+// Adapters use it to scope credential sessions so that sources running
+// concurrently in one operator never share credential state.
+type SourceIdentity struct {
+	Namespace string
+	Name      string
+}
+
+// String returns "namespace/name".
+func (s SourceIdentity) String() string {
+	return s.Namespace + "/" + s.Name
+}
+
+// SessionName returns a provider-safe session name for this source
+// (e.g., an STS RoleSessionName). It shows up in cloud audit trails, which
+// lets administrators attribute API calls to a specific AudiciaSource.
+func (s SourceIdentity) SessionName() string {
+	name := "audicia-" + s.Namespace + "-" + s.Name
+	name = sessionNameInvalidChars.ReplaceAllString(name, "-")
+	if len(name) > maxSessionNameLength {
+		name = name[:maxSessionNameLength]
+	}
+	return name
+}
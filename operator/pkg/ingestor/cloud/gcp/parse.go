@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	authnv1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	"github.com/felixnotka/audicia/operator/pkg/ingestor/cloud"
 )
 
 // logEntry represents a Cloud Logging LogEntry as received from a
@@ -45,15 +49,28 @@ type authenticationInfo struct {
 }
 
 type requestMetadata struct {
-	CallerIP string `json:"callerIp"`
+	CallerIP          string             `json:"callerIp"`
+	RequestAttributes *requestAttributes `json:"requestAttributes"`
+}
+
+// requestAttributes holds the HTTP request headers GKE records, among them
+// the Impersonate-* headers of kubectl --as.
+type requestAttributes struct {
+	Headers map[string]string `json:"headers"`
 }
 
+// authorizationInfo is one authorization check of the request. Granted is
+// omitted from the JSON when false.
 type authorizationInfo struct {
 	Resource   string `json:"resource"`
 	Permission string `json:"permission"`
 	Granted    bool   `json:"granted"`
 }
 
+// authorizationDecisionAnnotation is the audit annotation in which the
+// kube-apiserver records the authorizer's decision, allow or forbid.
+const authorizationDecisionAnnotation = "authorization.k8s.io/decision"
+
 type logResource struct {
 	Type   string            `json:"type"`
 	Labels map[string]string `json:"labels"`
@@ -146,13 +163,14 @@ func convertLogEntry(entry logEntry) auditv1.Event {
 
 	// Parse methodName to extract verb, resource, group, version.
 	if pp.MethodName != "" {
-		verb, resource, apiGroup, apiVersion, err := parseMethodName(pp.MethodName)
+		verb, resource, subresource, apiGroup, apiVersion, err := parseMethodName(pp.MethodName)
 		if err == nil {
 			event.Verb = verb
 			event.ObjectRef = &auditv1.ObjectReference{
-				Resource:   resource,
-				APIGroup:   apiGroup,
-				APIVersion: apiVersion,
+				Resource:    resource,
+				Subresource: subresource,
+				APIGroup:    apiGroup,
+				APIVersion:  apiVersion,
 			}
 		}
 	}
@@ -173,6 +191,9 @@ func convertLogEntry(entry logEntry) auditv1.Event {
 			event.ObjectRef.Resource,
 			event.ObjectRef.Name,
 		)
+		if sub := event.ObjectRef.Subresource; sub != "" && event.ObjectRef.Name != "" {
+			event.RequestURI += "/" + sub
+		}
 	}
 
 	// Response status.
@@ -181,6 +202,13 @@ func convertLogEntry(entry logEntry) auditv1.Event {
 	// Annotations for traceability.
 	setAnnotations(&event, entry.LogName, entry.InsertID)
 
+	// Authorization decision and impersonation.
+	setAuthorization(&event, pp.AuthorizationInfo)
+	event.ImpersonatedUser = impersonatedUser(pp)
+	if identity := clusterIdentity(entry.Resource); identity != "" {
+		event.Annotations[cloud.ClusterIdentityAnnotation] = identity
+	}
+
 	return event
 }
 
@@ -220,49 +248,154 @@ func setAnnotations(event *auditv1.Event, logName, insertID string) {
 	}
 }
 
-// parseMethodName extracts verb, resource, API group, and API version from
-// a GKE method name. GKE method names follow the pattern:
+// setAuthorization records the authorizer's decision like the kube-apiserver
+// does: a denied check makes the event a 403 with decision forbid, even when
+// the log entry's status says OK, and a request whose checks were all granted
+// but that still failed with 403 was rejected by admission control.
+func setAuthorization(event *auditv1.Event, checks []authorizationInfo) {
+	if len(checks) == 0 {
+		return
+	}
+	for _, check := range checks {
+		if check.Granted {
+			continue
+		}
+		event.Annotations[authorizationDecisionAnnotation] = "forbid"
+		if event.ResponseStatus == nil || event.ResponseStatus.Code < 400 {
+			event.ResponseStatus = &metav1.Status{
+				Code:    403,
+				Reason:  metav1.StatusReasonForbidden,
+				Message: "forbidden: permission " + check.Permission + " denied",
+			}
+		}
+		return
+	}
+	event.Annotations[authorizationDecisionAnnotation] = "allow"
+}
+
+// impersonatedUser returns the user a request impersonated, or nil. The
+// Impersonate-* request headers are used when GKE recorded them; otherwise
+// the user is taken from the granted impersonate checks in authorizationInfo,
+// such as io.k8s.core.v1.users.impersonate on core/v1/users/alice. A request
+// whose impersonation was denied ran as nobody else, so it has none, as in
+// the native audit log.
+func impersonatedUser(pp *protoPayload) *authnv1.UserInfo {
+	var user authnv1.UserInfo
+	for _, check := range pp.AuthorizationInfo {
+		_, resource, _, _, _, err := parseMethodName(check.Permission)
+		if err != nil || !strings.HasSuffix(check.Permission, ".impersonate") {
+			continue
+		}
+		if !check.Granted {
+			return nil
+		}
+		namespace, _, name := parseResourceName(check.Resource)
+		switch resource {
+		case "users":
+			user.Username = name
+		case "serviceaccounts":
+			user.Username = "system:serviceaccount:" + namespace + ":" + name
+		case "groups":
+			user.Groups = append(user.Groups, name)
+		case "uids":
+			user.UID = name
+		}
+	}
+
+	if pp.RequestMetadata != nil && pp.RequestMetadata.RequestAttributes != nil {
+		var groups []string
+		for header, value := range pp.RequestMetadata.RequestAttributes.Headers {
+			switch strings.ToLower(header) {
+			case "impersonate-user":
+				user.Username = value
+			case "impersonate-uid":
+				user.UID = value
+			case "impersonate-group":
+				for group := range strings.SplitSeq(value, ",") {
+					if group = strings.TrimSpace(group); group != "" {
+						groups = append(groups, group)
+					}
+				}
+			}
+		}
+		if len(groups) > 0 {
+			slices.Sort(groups)
+			user.Groups = groups
+		}
+	}
+
+	// Groups and UIDs cannot be impersonated without a user.
+	if user.Username == "" {
+		return nil
+	}
+	return &user
+}
+
+// clusterIdentity returns the resource name of the GKE cluster of a
+// k8s_cluster log resource, projects/<project>/locations/<location>/clusters/<name>,
+// or "" if its labels are incomplete.
+func clusterIdentity(res *logResource) string {
+	if res == nil {
+		return ""
+	}
+	project, location, name := res.Labels["project_id"], res.Labels["location"], res.Labels["cluster_name"]
+	if project == "" || location == "" || name == "" {
+		return ""
+	}
+	return fmt.Sprintf("projects/%s/locations/%s/clusters/%s", project, location, name)
+}
+
+// parseMethodName extracts verb, resource, subresource, API group, and API
+// version from a GKE method name. GKE method names follow the pattern:
 //
-//	io.k8s.{groupPrefix}.{version}.{resource}.{verb}
+//	io.k8s.{groupPrefix}.{version}.{resource}[.{subresource}].{verb}
 //
 // Examples:
 //
-//	io.k8s.core.v1.pods.list                              → list, pods, "", v1
-//	io.k8s.apps.v1.deployments.create                     → create, deployments, apps, v1
-//	io.k8s.rbac.authorization.v1.clusterroles.list        → list, clusterroles, rbac.authorization.k8s.io, v1
-//	io.k8s.authorization.v1.subjectaccessreviews.create   → create, subjectaccessreviews, authorization.k8s.io, v1
-func parseMethodName(method string) (verb, resource, apiGroup, apiVersion string, err error) {
+//	io.k8s.core.v1.pods.list                              → list, pods, "", "", v1
+//	io.k8s.apps.v1.deployments.create                     → create, deployments, "", apps, v1
+//	io.k8s.core.v1.pods.exec.create                       → create, pods, exec, "", v1
+//	io.k8s.apps.v1.deployments.scale.update               → update, deployments, scale, apps, v1
+//	io.k8s.rbac.authorization.v1.clusterroles.list        → list, clusterroles, "", rbac.authorization.k8s.io, v1
+//	io.k8s.authorization.v1.subjectaccessreviews.create   → create, subjectaccessreviews, "", authorization.k8s.io, v1
+func parseMethodName(method string) (verb, resource, subresource, apiGroup, apiVersion string, err error) {
 	parts := strings.Split(method, ".")
 	if len(parts) < 5 || parts[0] != "io" || parts[1] != "k8s" {
-		return "", "", "", "", fmt.Errorf("unexpected method format: %s", method)
+		return "", "", "", "", "", fmt.Errorf("unexpected method format: %s", method)
 	}
 
 	// Remove "io.k8s." prefix.
 	parts = parts[2:]
 	// Verb is always the last segment.
 	verb = parts[len(parts)-1]
-	// Resource is second to last.
-	resource = parts[len(parts)-2]
-	// Remaining segments contain group prefix and version.
-	remaining := parts[:len(parts)-2]
+	remaining := parts[:len(parts)-1]
 
-	// Find the version segment (matches v\d+...).
+	// Find the version segment (matches v\d+...). The group prefix comes
+	// before it, the resource and an optional subresource after it.
 	versionIdx := -1
-	for i, p := range remaining {
+	for i, p := range remaining[:len(remaining)-1] {
 		if isVersionSegment(p) {
 			versionIdx = i
 			break
 		}
 	}
 	if versionIdx < 0 {
-		return "", "", "", "", fmt.Errorf("no version found in method: %s", method)
+		return "", "", "", "", "", fmt.Errorf("no version found in method: %s", method)
+	}
+	switch after := remaining[versionIdx+1:]; len(after) {
+	case 1:
+		resource = after[0]
+	case 2:
+		resource, subresource = after[0], after[1]
+	default:
+		return "", "", "", "", "", fmt.Errorf("unexpected resource in method: %s", method)
 	}
 
 	apiVersion = remaining[versionIdx]
 	groupPrefix := strings.Join(remaining[:versionIdx], ".")
 	apiGroup = mapGroupPrefix(groupPrefix)
 
-	return verb, resource, apiGroup, apiVersion, nil
+	return verb, resource, subresource, apiGroup, apiVersion, nil
 }
 
 // isVersionSegment returns true if s looks like a Kubernetes API version
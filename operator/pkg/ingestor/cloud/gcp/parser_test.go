@@ -2,7 +2,10 @@ package gcp
 
 import (
 	"encoding/json"
+	"slices"
 	"testing"
+
+	"github.com/felixnotka/audicia/operator/pkg/ingestor/cloud"
 )
 
 // makeLogEntry creates a minimal Cloud Logging LogEntry JSON for testing.
@@ -285,12 +288,38 @@ func TestParseLogEntryCoreGroup(t *testing.T) {
 	}
 }
 
+func TestParseLogEntrySubresource(t *testing.T) {
+	input := makeLogEntry(
+		"io.k8s.core.v1.pods.exec.create",
+		"user@example.com",
+		"core/v1/namespaces/prod/pods/web-0/exec",
+	)
+
+	events, err := parseLogEntry(input)
+	if err != nil {
+		t.Fatalf("parseLogEntry() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+
+	ref := events[0].ObjectRef
+	if ref == nil || ref.Resource != "pods" || ref.Subresource != "exec" || ref.Name != "web-0" || ref.Namespace != "prod" {
+		t.Fatalf("ObjectRef = %+v, want pods/exec of prod/web-0", ref)
+	}
+	wantURI := "/api/v1/namespaces/prod/pods/web-0/exec"
+	if events[0].RequestURI != wantURI {
+		t.Errorf("RequestURI = %q, want %q", events[0].RequestURI, wantURI)
+	}
+}
+
 func TestParseMethodName(t *testing.T) {
 	tests := []struct {
 		name      string
 		method    string
 		wantVerb  string
 		wantRes   string
+		wantSub   string
 		wantGroup string
 		wantVer   string
 		wantErr   bool
@@ -327,6 +356,24 @@ func TestParseMethodName(t *testing.T) {
 			wantGroup: "authorization.k8s.io",
 			wantVer:   "v1",
 		},
+		{
+			name:      "core group subresource",
+			method:    "io.k8s.core.v1.pods.exec.create",
+			wantVerb:  "create",
+			wantRes:   "pods",
+			wantSub:   "exec",
+			wantGroup: "",
+			wantVer:   "v1",
+		},
+		{
+			name:      "apps group subresource",
+			method:    "io.k8s.apps.v1.deployments.scale.update",
+			wantVerb:  "update",
+			wantRes:   "deployments",
+			wantSub:   "scale",
+			wantGroup: "apps",
+			wantVer:   "v1",
+		},
 		{
 			name:      "rbac.authorization multi-segment group",
 			method:    "io.k8s.rbac.authorization.v1.clusterroles.list",
@@ -402,7 +449,7 @@ func TestParseMethodName(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			verb, res, group, ver, err := parseMethodName(tt.method)
+			verb, res, sub, group, ver, err := parseMethodName(tt.method)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("parseMethodName(%q) error = %v, wantErr %v", tt.method, err, tt.wantErr)
 			}
@@ -415,6 +462,9 @@ func TestParseMethodName(t *testing.T) {
 			if res != tt.wantRes {
 				t.Errorf("resource = %q, want %q", res, tt.wantRes)
 			}
+			if sub != tt.wantSub {
+				t.Errorf("subresource = %q, want %q", sub, tt.wantSub)
+			}
 			if group != tt.wantGroup {
 				t.Errorf("apiGroup = %q, want %q", group, tt.wantGroup)
 			}
@@ -637,3 +687,156 @@ func TestParseLogEntryStatusCodes(t *testing.T) {
 		}
 	}
 }
+
+func TestParseLogEntryClusterIdentity(t *testing.T) {
+	events, err := parseLogEntry(makeLogEntry("io.k8s.core.v1.pods.get", "alice@example.com", "core/v1/namespaces/default/pods/nginx"))
+	if err != nil || len(events) != 1 {
+		t.Fatalf("parseLogEntry() = %d events, %v", len(events), err)
+	}
+	want := "projects/my-project/locations/us-central1-a/clusters/my-cluster"
+	if got := events[0].Annotations[cloud.ClusterIdentityAnnotation]; got != want {
+		t.Errorf("cluster identity = %q, want %q", got, want)
+	}
+
+	events, err = parseLogEntry(makeLogEntryWithStatus(0))
+	if err != nil || len(events) != 1 {
+		t.Fatalf("parseLogEntry() = %d events, %v", len(events), err)
+	}
+	if _, ok := events[0].Annotations[cloud.ClusterIdentityAnnotation]; ok {
+		t.Error("entry without resource labels got a cluster identity")
+	}
+}
+
+// makeLogEntryWithAuthorization creates a Cloud Logging entry for a pod get
+// with the given authorizationInfo, request headers and status code.
+func makeLogEntryWithAuthorization(checks []map[string]interface{}, headers map[string]string, statusCode int) []byte {
+	entry := map[string]interface{}{
+		"insertId":  "test-insert-id",
+		"timestamp": "2024-06-15T10:30:00Z",
+		"protoPayload": map[string]interface{}{
+			"@type":       "type.googleapis.com/google.cloud.audit.AuditLog",
+			"serviceName": "k8s.io",
+			"methodName":  "io.k8s.core.v1.pods.get",
+			"authenticationInfo": map[string]interface{}{
+				"principalEmail": "admin@example.com",
+			},
+			"requestMetadata": map[string]interface{}{
+				"callerIp":          "10.0.0.1",
+				"requestAttributes": map[string]interface{}{"headers": headers},
+			},
+			"authorizationInfo": checks,
+			"resourceName":      "core/v1/namespaces/default/pods/nginx",
+			"status":            map[string]interface{}{"code": statusCode},
+		},
+	}
+	b, _ := json.Marshal(entry)
+	return b
+}
+
+func TestParseLogEntryImpersonation(t *testing.T) {
+	podGet := map[string]interface{}{"permission": "io.k8s.core.v1.pods.get", "resource": "core/v1/namespaces/default/pods/nginx", "granted": true}
+	tests := []struct {
+		name       string
+		checks     []map[string]interface{}
+		headers    map[string]string
+		wantUser   string
+		wantGroups []string
+	}{
+		{
+			name: "user from authorizationInfo",
+			checks: []map[string]interface{}{
+				{"permission": "io.k8s.core.v1.users.impersonate", "resource": "core/v1/users/alice@example.com", "granted": true},
+				{"permission": "io.k8s.core.v1.groups.impersonate", "resource": "core/v1/groups/developers", "granted": true},
+				podGet,
+			},
+			wantUser:   "alice@example.com",
+			wantGroups: []string{"developers"},
+		},
+		{
+			name: "service account from authorizationInfo",
+			checks: []map[string]interface{}{
+				{"permission": "io.k8s.core.v1.serviceaccounts.impersonate", "resource": "core/v1/namespaces/ci/serviceaccounts/deployer", "granted": true},
+				podGet,
+			},
+			wantUser: "system:serviceaccount:ci:deployer",
+		},
+		{
+			name:       "headers",
+			checks:     []map[string]interface{}{podGet},
+			headers:    map[string]string{"Impersonate-User": "bob@example.com", "Impersonate-Group": "ops, developers", "Accept": "application/json"},
+			wantUser:   "bob@example.com",
+			wantGroups: []string{"developers", "ops"},
+		},
+		{
+			name: "denied impersonation",
+			checks: []map[string]interface{}{
+				{"permission": "io.k8s.core.v1.users.impersonate", "resource": "core/v1/users/alice@example.com"},
+			},
+		},
+		{
+			name:   "no impersonation",
+			checks: []map[string]interface{}{podGet},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := parseLogEntry(makeLogEntryWithAuthorization(tt.checks, tt.headers, 0))
+			if err != nil || len(events) != 1 {
+				t.Fatalf("parseLogEntry() = %d events, %v", len(events), err)
+			}
+			event := events[0]
+			if event.User.Username != "admin@example.com" {
+				t.Errorf("User = %q, want the impersonator", event.User.Username)
+			}
+			if tt.wantUser == "" {
+				if event.ImpersonatedUser != nil {
+					t.Errorf("ImpersonatedUser = %+v, want nil", event.ImpersonatedUser)
+				}
+				return
+			}
+			if event.ImpersonatedUser == nil {
+				t.Fatal("ImpersonatedUser is nil")
+			}
+			if event.ImpersonatedUser.Username != tt.wantUser {
+				t.Errorf("ImpersonatedUser.Username = %q, want %q", event.ImpersonatedUser.Username, tt.wantUser)
+			}
+			if !slices.Equal(event.ImpersonatedUser.Groups, tt.wantGroups) {
+				t.Errorf("ImpersonatedUser.Groups = %v, want %v", event.ImpersonatedUser.Groups, tt.wantGroups)
+			}
+		})
+	}
+}
+
+func TestParseLogEntryAuthorizationDecision(t *testing.T) {
+	granted := []map[string]interface{}{{"permission": "io.k8s.core.v1.pods.get", "resource": "core/v1/namespaces/default/pods/nginx", "granted": true}}
+	denied := []map[string]interface{}{{"permission": "io.k8s.core.v1.pods.get", "resource": "core/v1/namespaces/default/pods/nginx"}}
+	tests := []struct {
+		name         string
+		checks       []map[string]interface{}
+		grpcCode     int
+		wantCode     int32
+		wantDecision string
+	}{
+		{"granted", granted, 0, 200, "allow"},
+		{"denied with OK status", denied, 0, 403, "forbid"},
+		{"denied with PERMISSION_DENIED", denied, 7, 403, "forbid"},
+		{"granted but rejected by admission", granted, 7, 403, "allow"},
+		{"no checks", nil, 0, 200, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := parseLogEntry(makeLogEntryWithAuthorization(tt.checks, nil, tt.grpcCode))
+			if err != nil || len(events) != 1 {
+				t.Fatalf("parseLogEntry() = %d events, %v", len(events), err)
+			}
+			if got := events[0].ResponseStatus.Code; got != tt.wantCode {
+				t.Errorf("ResponseStatus.Code = %d, want %d", got, tt.wantCode)
+			}
+			if got := events[0].Annotations[authorizationDecisionAnnotation]; got != tt.wantDecision {
+				t.Errorf("decision = %q, want %q", got, tt.wantDecision)
+			}
+		})
+	}
+}
@@ -13,7 +13,7 @@ func init() {
 	cloud.RegisterAdapter(audiciav1alpha1.CloudProviderGCPPubSub, buildGCPAdapter)
 }
 
-func buildGCPAdapter(cfg *audiciav1alpha1.CloudConfig) (cloud.MessageSource, cloud.EnvelopeParser, error) {
+func buildGCPAdapter(cfg *audiciav1alpha1.CloudConfig, _ cloud.SourceIdentity) (cloud.MessageSource, cloud.EnvelopeParser, error) {
 	if cfg.GCP == nil {
 		return nil, nil, fmt.Errorf("gcp configuration is required for GCPPubSub provider")
 	}
@@ -28,6 +28,8 @@ func buildGCPAdapter(cfg *audiciav1alpha1.CloudConfig) (cloud.MessageSource, clo
 	source := &PubSubSource{
 		ProjectID:      cfg.GCP.ProjectID,
 		SubscriptionID: cfg.GCP.SubscriptionID,
+
+		ImpersonateServiceAccount: cfg.GCP.ImpersonateServiceAccount,
 	}
 
 	return source, &EnvelopeParser{}, nil
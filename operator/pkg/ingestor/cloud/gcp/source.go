@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"cloud.google.com/go/pubsub/v2"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/felixnotka/audicia/operator/pkg/ingestor/cloud"
@@ -23,6 +25,9 @@ const maxOutstandingMessages = 100
 // maxBatchSize is the maximum number of messages returned per Receive() call.
 const maxBatchSize = 100
 
+// pubsubScope is the OAuth scope requested for impersonated credentials.
+const pubsubScope = "https://www.googleapis.com/auth/pubsub"
+
 // batchWindow is how long Receive() waits after the first message to collect
 // additional messages into the batch before returning.
 const batchWindow = 50 * time.Millisecond
@@ -34,6 +39,11 @@ type PubSubSource struct {
 	ProjectID      string
 	SubscriptionID string
 
+	// ImpersonateServiceAccount is an optional service account email. When
+	// set, this source gets its own impersonated token source instead of
+	// sharing Application Default Credentials with other sources.
+	ImpersonateServiceAccount string
+
 	mu         sync.Mutex
 	client     *pubsub.Client
 	sub        *pubsub.Subscriber
@@ -44,7 +54,12 @@ type PubSubSource struct {
 }
 
 func (s *PubSubSource) Connect(ctx context.Context) error {
-	client, err := pubsub.NewClient(ctx, s.ProjectID)
+	opts, err := s.clientOptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	client, err := pubsub.NewClient(ctx, s.ProjectID, opts...)
 	if err != nil {
 		return fmt.Errorf("creating Pub/Sub client: %w", err)
 	}
@@ -86,10 +101,27 @@ func (s *PubSubSource) Connect(ctx context.Context) error {
 	}()
 
 	log.Info("connected to Pub/Sub",
-		"project", s.ProjectID, "subscription", s.SubscriptionID)
+		"project", s.ProjectID, "subscription", s.SubscriptionID,
+		"impersonate", s.ImpersonateServiceAccount)
 	return nil
 }
 
+// clientOptions returns the Pub/Sub client options carrying this source's
+// credentials. Without impersonation, the client falls back to ADC.
+func (s *PubSubSource) clientOptions(ctx context.Context) ([]option.ClientOption, error) {
+	if s.ImpersonateServiceAccount == "" {
+		return nil, nil
+	}
+	ts, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: s.ImpersonateServiceAccount,
+		Scopes:          []string{pubsubScope},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("impersonating %s: %w", s.ImpersonateServiceAccount, err)
+	}
+	return []option.ClientOption{option.WithTokenSource(ts)}, nil
+}
+
 func (s *PubSubSource) Receive(ctx context.Context) ([]cloud.Message, error) {
 	s.mu.Lock()
 	msgCh := s.msgCh
@@ -9,6 +9,38 @@ import (
 
 var identityLog = ctrl.Log.WithName("ingestor").WithName("cloud").WithName("identity")
 
+// ClusterIdentityAnnotation is the event annotation envelope parsers set to
+// the identity of the cluster that emitted the event, taken from the cloud
+// envelope: the AKS resource ID or the GKE cluster resource name.
+const ClusterIdentityAnnotation = "cloud.audicia.io/cluster-identity"
+
+// IdentityVerdict is the result of checking an event's cluster identity.
+type IdentityVerdict int
+
+const (
+	// IdentityMatched means the event belongs to the expected cluster, or
+	// no identity is expected.
+	IdentityMatched IdentityVerdict = iota
+
+	// IdentityMismatched means the event names a different cluster.
+	IdentityMismatched
+
+	// IdentityMissing means the event carries no cluster identity.
+	IdentityMissing
+)
+
+// String returns the reason label of the verdict.
+func (v IdentityVerdict) String() string {
+	switch v {
+	case IdentityMismatched:
+		return "cluster_mismatch"
+	case IdentityMissing:
+		return "cluster_unknown"
+	default:
+		return "matched"
+	}
+}
+
 // ClusterIdentityValidator verifies that audit events originate from the
 // expected cluster. This prevents the operator from processing events from
 // a different cluster when using a shared cloud message bus.
@@ -22,42 +54,64 @@ type ClusterIdentityValidator struct {
 	// For EKS: the cluster ARN
 	// For GKE: the cluster resource name
 	ExpectedIdentity string
+
+	// Strict drops events that are not verified to come from the expected
+	// cluster. Otherwise they are logged and ingested.
+	Strict bool
 }
 
-// Matches checks whether the audit event belongs to this cluster.
-// Returns true if:
-//   - No expected identity is configured (validation disabled)
-//   - The event annotations contain the expected identity string
-//   - The request URI contains the expected identity string
-//
-// Returns false only when the expected identity is set and the event
-// explicitly references a different cluster.
-func (v *ClusterIdentityValidator) Matches(event auditv1.Event) bool {
+// Verify checks the cluster identity of the event. The identity set by the
+// envelope parser in ClusterIdentityAnnotation is authoritative and must
+// equal the expected identity, ignoring case, as Azure upper-cases resource
+// IDs in Diagnostic Settings records. Without one, Strict reports the
+// identity missing; otherwise the event matches if any annotation or the
+// request URI contains the expected identity, which is only good enough to
+// decide what to log.
+func (v *ClusterIdentityValidator) Verify(event auditv1.Event) IdentityVerdict {
 	if v.ExpectedIdentity == "" {
-		return true
+		return IdentityMatched
+	}
+
+	if identity, ok := event.Annotations[ClusterIdentityAnnotation]; ok {
+		if strings.EqualFold(strings.TrimSpace(identity), strings.TrimSpace(v.ExpectedIdentity)) {
+			return IdentityMatched
+		}
+		return IdentityMismatched
+	}
+	if v.Strict {
+		return IdentityMissing
 	}
+	expected := strings.ToLower(v.ExpectedIdentity)
 
 	// Check annotations for cluster identity markers.
-	if event.Annotations != nil {
-		for _, value := range event.Annotations {
-			if strings.Contains(value, v.ExpectedIdentity) {
-				return true
-			}
+	for _, value := range event.Annotations {
+		if strings.Contains(strings.ToLower(value), expected) {
+			return IdentityMatched
 		}
 	}
 
 	// Check the request URI for cluster-specific paths.
-	if strings.Contains(event.RequestURI, v.ExpectedIdentity) {
-		return true
+	if strings.Contains(strings.ToLower(event.RequestURI), expected) {
+		return IdentityMatched
 	}
+	return IdentityMissing
+}
 
-	// AKS audit events don't always carry the cluster resource ID in the event
-	// itself. The Event Hub namespace/name (configured in Diagnostic Settings)
-	// provides the primary identity binding. This validator is defense-in-depth.
-	//
-	// Default to allow to avoid false drops during initial rollout. Log at high
-	// verbosity so operators can verify their setup.
-	identityLog.V(2).Info("cluster identity not found in event, allowing by default",
-		"auditID", event.AuditID, "expectedIdentity", v.ExpectedIdentity)
+// Matches checks whether the audit event should be ingested. Without
+// Strict, every event is: AKS audit events don't always carry the cluster
+// resource ID in the event itself, and the Event Hub configured in
+// Diagnostic Settings provides the primary identity binding. Unverified
+// events are logged at high verbosity so operators can check their setup
+// before switching to Strict.
+func (v *ClusterIdentityValidator) Matches(event auditv1.Event) bool {
+	verdict := v.Verify(event)
+	if verdict == IdentityMatched {
+		return true
+	}
+	if v.Strict {
+		return false
+	}
+	identityLog.V(2).Info("cluster identity not verified, allowing in permissive mode",
+		"auditID", event.AuditID, "expectedIdentity", v.ExpectedIdentity, "reason", verdict.String())
 	return true
 }
@@ -1,6 +1,7 @@
 package cloud
 
 import (
+	"strings"
 	"testing"
 
 	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
@@ -79,3 +80,94 @@ func TestClusterIdentityValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterIdentityValidator_Verify(t *testing.T) {
+	const aks = "/subscriptions/abc/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/mycluster"
+	const gke = "projects/p/locations/europe-west1/clusters/prod"
+	tests := []struct {
+		name     string
+		expected string
+		event    auditv1.Event
+		// permissive and strict are the verdicts without and with Strict.
+		permissive IdentityVerdict
+		strict     IdentityVerdict
+	}{
+		{
+			name:       "envelope identity matches ignoring case",
+			expected:   aks,
+			event:      auditv1.Event{Annotations: map[string]string{ClusterIdentityAnnotation: strings.ToUpper(aks)}},
+			permissive: IdentityMatched,
+			strict:     IdentityMatched,
+		},
+		{
+			name:     "envelope identity of another cluster",
+			expected: aks,
+			event: auditv1.Event{Annotations: map[string]string{
+				ClusterIdentityAnnotation: strings.Replace(aks, "mycluster", "other", 1),
+				"note":                    aks,
+			}},
+			permissive: IdentityMismatched,
+			strict:     IdentityMismatched,
+		},
+		{
+			name:       "envelope identity with a prefix of the cluster name",
+			expected:   gke,
+			event:      auditv1.Event{Annotations: map[string]string{ClusterIdentityAnnotation: "projects/p/locations/europe-west1/clusters/nonprod"}},
+			permissive: IdentityMismatched,
+			strict:     IdentityMismatched,
+		},
+		{
+			name:       "envelope identity with a suffix of the cluster name",
+			expected:   gke,
+			event:      auditv1.Event{Annotations: map[string]string{ClusterIdentityAnnotation: gke + "-2"}},
+			permissive: IdentityMismatched,
+			strict:     IdentityMismatched,
+		},
+		{
+			name:       "expected identity naming only the cluster",
+			expected:   "prod",
+			event:      auditv1.Event{Annotations: map[string]string{ClusterIdentityAnnotation: gke}},
+			permissive: IdentityMismatched,
+			strict:     IdentityMismatched,
+		},
+		{
+			name:       "identity in request URI",
+			expected:   aks,
+			event:      auditv1.Event{RequestURI: "/api/v1/pods?cluster=" + aks},
+			permissive: IdentityMatched,
+			strict:     IdentityMissing,
+		},
+		{
+			name:       "identity in another annotation",
+			expected:   aks,
+			event:      auditv1.Event{Annotations: map[string]string{"note": aks}},
+			permissive: IdentityMatched,
+			strict:     IdentityMissing,
+		},
+		{
+			name:       "no identity",
+			expected:   aks,
+			event:      auditv1.Event{RequestURI: "/api/v1/pods"},
+			permissive: IdentityMissing,
+			strict:     IdentityMissing,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &ClusterIdentityValidator{ExpectedIdentity: tt.expected}
+			if got := v.Verify(tt.event); got != tt.permissive {
+				t.Errorf("permissive Verify() = %v, want %v", got, tt.permissive)
+			}
+			if !v.Matches(tt.event) {
+				t.Error("permissive Matches() = false, want true")
+			}
+			v.Strict = true
+			if got := v.Verify(tt.event); got != tt.strict {
+				t.Errorf("strict Verify() = %v, want %v", got, tt.strict)
+			}
+			if got := v.Matches(tt.event); got != (tt.strict == IdentityMatched) {
+				t.Errorf("strict Matches() = %v, want %v", got, tt.strict == IdentityMatched)
+			}
+		})
+	}
+}
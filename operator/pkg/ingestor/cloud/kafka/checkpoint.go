@@ -0,0 +1,26 @@
+package kafka
+
+import (
+	"strconv"
+
+	"github.com/felixnotka/audicia/operator/pkg/ingestor/cloud"
+)
+
+// resumeOffsets converts a saved CloudPosition into the next offset to
+// consume per partition. Partitions are keyed by their number and hold the
+// offset of the last processed record. Malformed entries are skipped.
+func resumeOffsets(pos cloud.CloudPosition) map[int32]int64 {
+	offsets := make(map[int32]int64, len(pos.PartitionOffsets))
+	for p, o := range pos.PartitionOffsets {
+		partition, err := strconv.ParseInt(p, 10, 32)
+		if err != nil {
+			continue
+		}
+		offset, err := strconv.ParseInt(o, 10, 64)
+		if err != nil || offset < 0 {
+			continue
+		}
+		offsets[int32(partition)] = offset + 1
+	}
+	return offsets
+}
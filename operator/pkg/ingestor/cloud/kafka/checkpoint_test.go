@@ -0,0 +1,22 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/felixnotka/audicia/operator/pkg/ingestor/cloud"
+)
+
+func TestResumeOffsets(t *testing.T) {
+	pos := cloud.CloudPosition{PartitionOffsets: map[string]string{
+		"0":     "41",
+		"3":     "0",
+		"audit": "7",
+		"1":     "-1",
+	}}
+	got := resumeOffsets(pos)
+	want := map[int32]int64{0: 42, 3: 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("resumeOffsets() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Secrets named in spec.cloud.kafka are mounted by the Helm chart. The mount
+// paths are a convention, like the webhook's TLS and token Secrets.
+const (
+	tlsMountPath  = "/etc/audicia/kafka-tls"
+	saslMountPath = "/etc/audicia/kafka-sasl"
+)
+
+// loadTLSConfig builds the client TLS configuration from the files in dir.
+// ca.crt replaces the system roots; tls.crt and tls.key, if present, are
+// presented as the client certificate. A missing dir yields the defaults.
+func loadTLSConfig(dir string) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if dir == "" {
+		return cfg, nil
+	}
+
+	ca, err := os.ReadFile(filepath.Join(dir, "ca.crt"))
+	switch {
+	case err == nil:
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in %s", filepath.Join(dir, "ca.crt"))
+		}
+		cfg.RootCAs = pool
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("reading Kafka CA: %w", err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	_, certErr := os.Stat(certFile)
+	_, keyErr := os.Stat(keyFile)
+	switch {
+	case certErr == nil && keyErr == nil:
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading Kafka client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	case certErr == nil || keyErr == nil:
+		return nil, fmt.Errorf("kafka TLS Secret must contain both tls.crt and tls.key for client authentication")
+	}
+	return cfg, nil
+}
+
+// loadSASLCredentials reads the username and password keys from dir.
+func loadSASLCredentials(dir string) (username, password string, err error) {
+	read := func(key string) (string, error) {
+		data, err := os.ReadFile(filepath.Join(dir, key))
+		if err != nil {
+			return "", fmt.Errorf("reading Kafka SASL %s: %w", key, err)
+		}
+		value := strings.TrimSpace(string(data))
+		if value == "" {
+			return "", fmt.Errorf("kafka SASL %s is empty", key)
+		}
+		return value, nil
+	}
+	if username, err = read("username"); err != nil {
+		return "", "", err
+	}
+	if password, err = read("password"); err != nil {
+		return "", "", err
+	}
+	return username, password, nil
+}
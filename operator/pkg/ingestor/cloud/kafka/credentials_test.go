@@ -0,0 +1,58 @@
+package kafka
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadTLSConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr bool
+	}{
+		{name: "empty secret uses system roots"},
+		{name: "invalid CA", files: map[string]string{"ca.crt": "not a certificate"}, wantErr: true},
+		{name: "certificate without key", files: map[string]string{"tls.crt": "cert"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadTLSConfig(writeFiles(t, tt.files))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadTLSConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (cfg.RootCAs != nil || len(cfg.Certificates) != 0) {
+				t.Errorf("expected default TLS config, got %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestLoadSASLCredentials(t *testing.T) {
+	user, pass, err := loadSASLCredentials(writeFiles(t, map[string]string{"username": "audicia\n", "password": "s3cret\n"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user != "audicia" || pass != "s3cret" {
+		t.Errorf("credentials = %q/%q, want audicia/s3cret", user, pass)
+	}
+
+	if _, _, err := loadSASLCredentials(writeFiles(t, map[string]string{"username": "audicia", "password": " "})); err == nil {
+		t.Error("expected error for empty password")
+	}
+	if _, _, err := loadSASLCredentials(writeFiles(t, map[string]string{"password": "s3cret"})); err == nil {
+		t.Error("expected error for missing username")
+	}
+}
@@ -0,0 +1,65 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// recordProbe captures the fields that tell the supported record layouts
+// apart without decoding the full event twice.
+type recordProbe struct {
+	Kind    string          `json:"kind"`
+	AuditID string          `json:"auditID"`
+	Items   json.RawMessage `json:"items"`
+	Log     *string         `json:"log"`
+}
+
+// parseRecord extracts Kubernetes audit events from a Kafka record value.
+//
+// Pipelines that ship audit logs through Kafka publish them in one of a few
+// layouts, all of which are accepted:
+//   - a single audit event (one line of the API server's audit log)
+//   - a JSON array of audit events
+//   - an EventList, as POSTed by the API server's webhook backend
+//   - a log shipper record (Fluent Bit, Vector) whose "log" field holds the
+//     audit log line as a string
+func parseRecord(value []byte) ([]auditv1.Event, error) {
+	value = bytes.TrimSpace(value)
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	if value[0] == '[' {
+		var events []auditv1.Event
+		if err := json.Unmarshal(value, &events); err != nil {
+			return nil, fmt.Errorf("unmarshaling audit event array: %w", err)
+		}
+		return events, nil
+	}
+
+	var probe recordProbe
+	if err := json.Unmarshal(value, &probe); err != nil {
+		return nil, fmt.Errorf("unmarshaling record: %w", err)
+	}
+
+	switch {
+	case probe.Kind == "EventList":
+		var list auditv1.EventList
+		if err := json.Unmarshal(value, &list); err != nil {
+			return nil, fmt.Errorf("unmarshaling audit EventList: %w", err)
+		}
+		return list.Items, nil
+	case probe.AuditID != "":
+		var event auditv1.Event
+		if err := json.Unmarshal(value, &event); err != nil {
+			return nil, fmt.Errorf("unmarshaling audit event: %w", err)
+		}
+		return []auditv1.Event{event}, nil
+	case probe.Log != nil:
+		return parseRecord([]byte(*probe.Log))
+	}
+	return nil, fmt.Errorf("record is not an audit event, event array, EventList or log shipper record")
+}
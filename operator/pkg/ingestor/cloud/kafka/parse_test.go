@@ -0,0 +1,71 @@
+package kafka
+
+import (
+	"testing"
+)
+
+func TestParseRecord(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "single event",
+			value: `{"kind":"Event","apiVersion":"audit.k8s.io/v1","auditID":"a1","verb":"get"}`,
+			want:  []string{"a1"},
+		},
+		{
+			name:  "event array",
+			value: `[{"auditID":"a1"},{"auditID":"a2"}]`,
+			want:  []string{"a1", "a2"},
+		},
+		{
+			name:  "webhook EventList",
+			value: `{"kind":"EventList","apiVersion":"audit.k8s.io/v1","items":[{"auditID":"a1"},{"auditID":"a2"}]}`,
+			want:  []string{"a1", "a2"},
+		},
+		{
+			name:  "log shipper record",
+			value: `{"@timestamp":1718000000.0,"log":"{\"auditID\":\"a1\",\"verb\":\"list\"}\n"}`,
+			want:  []string{"a1"},
+		},
+		{
+			name:  "surrounding whitespace",
+			value: "\n  {\"auditID\":\"a1\"}\n",
+			want:  []string{"a1"},
+		},
+		{
+			name:  "empty value",
+			value: "",
+		},
+		{
+			name:    "unrelated JSON",
+			value:   `{"level":"info","msg":"hello"}`,
+			wantErr: true,
+		},
+		{
+			name:    "not JSON",
+			value:   `I0612 kube-apiserver started`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := parseRecord([]byte(tt.value))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRecord() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(events) != len(tt.want) {
+				t.Fatalf("got %d events, want %d", len(events), len(tt.want))
+			}
+			for i, e := range events {
+				if string(e.AuditID) != tt.want[i] {
+					t.Errorf("event %d auditID = %q, want %q", i, e.AuditID, tt.want[i])
+				}
+			}
+		})
+	}
+}
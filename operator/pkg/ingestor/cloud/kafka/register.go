@@ -0,0 +1,85 @@
+//go:build kafka
+
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/ingestor/cloud"
+)
+
+// defaultConsumerGroup matches the CRD default for spec.cloud.kafka.consumerGroup.
+const defaultConsumerGroup = "audicia"
+
+func init() {
+	cloud.RegisterAdapter(audiciav1alpha1.CloudProviderKafka, buildKafkaAdapter)
+}
+
+func buildKafkaAdapter(cfg *audiciav1alpha1.CloudConfig, id cloud.SourceIdentity) (cloud.MessageSource, cloud.EnvelopeParser, error) {
+	if cfg.Kafka == nil {
+		return nil, nil, fmt.Errorf("kafka configuration is required for Kafka provider")
+	}
+	k := cfg.Kafka
+
+	if len(k.Brokers) == 0 {
+		return nil, nil, fmt.Errorf("kafka.brokers is required")
+	}
+	if k.Topic == "" {
+		return nil, nil, fmt.Errorf("kafka.topic is required")
+	}
+
+	source := &KafkaSource{
+		Brokers:       k.Brokers,
+		Topic:         k.Topic,
+		ConsumerGroup: k.ConsumerGroup,
+		ClientID:      id.SessionName(),
+	}
+	if source.ConsumerGroup == "" {
+		source.ConsumerGroup = defaultConsumerGroup
+	}
+
+	if k.TLS != nil {
+		dir := ""
+		if k.TLS.SecretName != "" {
+			dir = tlsMountPath
+		}
+		tlsConfig, err := loadTLSConfig(dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		source.TLS = tlsConfig
+	}
+
+	if k.SASL != nil {
+		mechanism, err := saslMechanism(k.SASL.Mechanism)
+		if err != nil {
+			return nil, nil, err
+		}
+		source.SASL = mechanism
+	}
+
+	return source, &EnvelopeParser{}, nil
+}
+
+// saslMechanism builds the SASL mechanism from the mounted credentials.
+func saslMechanism(name audiciav1alpha1.KafkaSASLMechanism) (sasl.Mechanism, error) {
+	username, password, err := loadSASLCredentials(saslMountPath)
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case audiciav1alpha1.KafkaSASLPlain:
+		return plain.Auth{User: username, Pass: password}.AsMechanism(), nil
+	case audiciav1alpha1.KafkaSASLScramSHA256:
+		return scram.Auth{User: username, Pass: password}.AsSha256Mechanism(), nil
+	case audiciav1alpha1.KafkaSASLScramSHA512, "":
+		return scram.Auth{User: username, Pass: password}.AsSha512Mechanism(), nil
+	default:
+		return nil, fmt.Errorf("unsupported kafka.sasl.mechanism %q", name)
+	}
+}
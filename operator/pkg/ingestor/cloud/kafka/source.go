@@ -0,0 +1,215 @@
+//go:build kafka
+
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/felixnotka/audicia/operator/pkg/ingestor/cloud"
+)
+
+var log = ctrl.Log.WithName("ingestor").WithName("cloud").WithName("kafka")
+
+// maxPollRecords is the maximum number of records returned per Receive() call.
+const maxPollRecords = 500
+
+// defaultLookback is how far back to start reading partitions that have
+// neither a committed offset nor a checkpoint.
+const defaultLookback = 5 * time.Minute
+
+// KafkaSource implements cloud.MessageSource as a member of a Kafka consumer
+// group. Each record becomes one message, with the partition number as the
+// partition and the record offset as the sequence number. Offsets are
+// committed to the group once a batch is processed; on restart, the
+// checkpoint saved in the AudiciaSource status takes over where it is ahead
+// of the group's commits (for example after a commit failed).
+type KafkaSource struct {
+	Brokers       []string
+	Topic         string
+	ConsumerGroup string
+
+	// ClientID identifies this source in broker logs and quotas.
+	ClientID string
+
+	// TLS enables TLS to the brokers when set.
+	TLS *tls.Config
+
+	// SASL enables SASL authentication when set.
+	SASL sasl.Mechanism
+
+	mu          sync.Mutex
+	client      *kgo.Client
+	startOffset kgo.Offset
+	resume      map[int32]int64        // Next offset per partition from the checkpoint.
+	pending     map[string]*kgo.Record // "partition/offset" → record awaiting commit.
+}
+
+// RestoreCheckpoint sets the offsets to resume from. It is called before
+// Connect.
+func (s *KafkaSource) RestoreCheckpoint(pos cloud.CloudPosition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resume = resumeOffsets(pos)
+	log.Info("restored checkpoint", "topic", s.Topic, "partitions", len(s.resume))
+}
+
+func (s *KafkaSource) Connect(ctx context.Context) error {
+	// adjustOffsets compares against startOffset and may run as soon as the
+	// client exists, so it is set first.
+	startOffset := kgo.NewOffset().AfterMilli(time.Now().Add(-defaultLookback).UnixMilli())
+	s.mu.Lock()
+	s.startOffset = startOffset
+	s.mu.Unlock()
+
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(s.Brokers...),
+		kgo.ConsumeTopics(s.Topic),
+		kgo.ConsumerGroup(s.ConsumerGroup),
+		kgo.DisableAutoCommit(),
+		kgo.ConsumeResetOffset(startOffset),
+		kgo.AdjustFetchOffsetsFn(s.adjustOffsets),
+	}
+	if s.ClientID != "" {
+		opts = append(opts, kgo.ClientID(s.ClientID))
+	}
+	if s.TLS != nil {
+		opts = append(opts, kgo.DialTLSConfig(s.TLS))
+	}
+	if s.SASL != nil {
+		opts = append(opts, kgo.SASL(s.SASL))
+	}
+
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return fmt.Errorf("creating Kafka client: %w", err)
+	}
+	if err := client.Ping(ctx); err != nil {
+		client.Close()
+		return fmt.Errorf("connecting to Kafka brokers: %w", err)
+	}
+
+	s.mu.Lock()
+	s.client = client
+	s.pending = make(map[string]*kgo.Record)
+	s.mu.Unlock()
+
+	log.Info("connected to Kafka",
+		"brokers", s.Brokers, "topic", s.Topic, "consumerGroup", s.ConsumerGroup,
+		"tls", s.TLS != nil, "sasl", s.SASL != nil)
+	return nil
+}
+
+// adjustOffsets runs when partitions are assigned to this member. Each
+// partition starts at the later of the group's committed offset and the
+// restored checkpoint; partitions with neither start defaultLookback ago.
+func (s *KafkaSource) adjustOffsets(_ context.Context, offsets map[string]map[int32]kgo.Offset) (map[string]map[int32]kgo.Offset, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for partition, offset := range offsets[s.Topic] {
+		next, ok := s.resume[partition]
+		if !ok {
+			continue
+		}
+		if offset == s.startOffset || offset.EpochOffset().Offset < next {
+			offsets[s.Topic][partition] = kgo.NewOffset().At(next)
+		}
+	}
+	return offsets, nil
+}
+
+func (s *KafkaSource) Receive(ctx context.Context) ([]cloud.Message, error) {
+	s.mu.Lock()
+	client := s.client
+	s.mu.Unlock()
+
+	if client == nil {
+		return nil, fmt.Errorf("Kafka client not connected")
+	}
+
+	fetches := client.PollRecords(ctx, maxPollRecords)
+	if fetches.IsClientClosed() {
+		return nil, nil // clean shutdown
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var errs []error
+	fetches.EachError(func(topic string, partition int32, err error) {
+		errs = append(errs, fmt.Errorf("fetching %s[%d]: %w", topic, partition, err))
+	})
+	if len(errs) > 0 && fetches.NumRecords() == 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(errs) > 0 {
+		log.Error(errors.Join(errs...), "partial fetch failure, continuing with received records")
+	}
+
+	msgs := make([]cloud.Message, 0, fetches.NumRecords())
+	s.mu.Lock()
+	fetches.EachRecord(func(r *kgo.Record) {
+		partition := strconv.FormatInt(int64(r.Partition), 10)
+		offset := strconv.FormatInt(r.Offset, 10)
+		s.pending[partition+"/"+offset] = r
+		msgs = append(msgs, cloud.Message{
+			Body:           r.Value,
+			SequenceNumber: offset,
+			Partition:      partition,
+			EnqueuedTime:   r.Timestamp.UTC().Format(time.RFC3339),
+		})
+	})
+	s.mu.Unlock()
+
+	return msgs, nil
+}
+
+// Acknowledge commits the offsets of the processed records to the consumer
+// group.
+func (s *KafkaSource) Acknowledge(ctx context.Context, msgs []cloud.Message) error {
+	s.mu.Lock()
+	client := s.client
+	records := make([]*kgo.Record, 0, len(msgs))
+	for _, msg := range msgs {
+		key := msg.Partition + "/" + msg.SequenceNumber
+		if r, ok := s.pending[key]; ok {
+			records = append(records, r)
+			delete(s.pending, key)
+		}
+	}
+	s.mu.Unlock()
+
+	if client == nil || len(records) == 0 {
+		return nil
+	}
+	if err := client.CommitRecords(ctx, records...); err != nil {
+		return fmt.Errorf("committing Kafka offsets: %w", err)
+	}
+	return nil
+}
+
+func (s *KafkaSource) Close(_ context.Context) error {
+	s.mu.Lock()
+	client := s.client
+	s.client = nil
+	s.pending = nil
+	s.mu.Unlock()
+
+	// Close leaves the group; uncommitted records are redelivered to
+	// whichever member is assigned their partition next.
+	if client != nil {
+		client.Close()
+	}
+
+	log.Info("closed Kafka source")
+	return nil
+}
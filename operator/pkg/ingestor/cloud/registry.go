@@ -7,7 +7,9 @@ import (
 )
 
 // AdapterFactory creates a MessageSource and EnvelopeParser pair for a cloud provider.
-type AdapterFactory func(cfg *audiciav1alpha1.CloudConfig) (MessageSource, EnvelopeParser, error)
+// Factories must build credentials from cfg and id only — never from shared
+// package-level state — so that concurrently running sources stay isolated.
+type AdapterFactory func(cfg *audiciav1alpha1.CloudConfig, id SourceIdentity) (MessageSource, EnvelopeParser, error)
 
 var registry = map[audiciav1alpha1.CloudProvider]AdapterFactory{}
 
@@ -18,10 +20,36 @@ func RegisterAdapter(provider audiciav1alpha1.CloudProvider, factory AdapterFact
 }
 
 // BuildAdapter creates the MessageSource and EnvelopeParser for the given config.
-func BuildAdapter(cfg *audiciav1alpha1.CloudConfig) (MessageSource, EnvelopeParser, error) {
+// Each call produces an independent adapter with its own credentials, scoped
+// to the AudiciaSource identified by id.
+func BuildAdapter(cfg *audiciav1alpha1.CloudConfig, id SourceIdentity) (MessageSource, EnvelopeParser, error) {
 	factory, ok := registry[cfg.Provider]
 	if !ok {
 		return nil, nil, fmt.Errorf("unsupported cloud provider: %s (no adapter registered — check build tags)", cfg.Provider)
 	}
-	return factory(cfg)
+	return factory(cfg, id)
+}
+
+// ForeignProviderSettings returns the providers other than the selected one
+// whose settings blocks cfg carries. The CRD rejects such configs, but
+// sources created before the rule was added may still have them; their
+// blocks are ignored.
+func ForeignProviderSettings(cfg *audiciav1alpha1.CloudConfig) []audiciav1alpha1.CloudProvider {
+	blocks := []struct {
+		provider audiciav1alpha1.CloudProvider
+		set      bool
+	}{
+		{audiciav1alpha1.CloudProviderAzureEventHub, cfg.Azure != nil},
+		{audiciav1alpha1.CloudProviderAWSCloudWatch, cfg.AWS != nil},
+		{audiciav1alpha1.CloudProviderAWSS3, cfg.S3 != nil},
+		{audiciav1alpha1.CloudProviderGCPPubSub, cfg.GCP != nil},
+		{audiciav1alpha1.CloudProviderKafka, cfg.Kafka != nil},
+	}
+	var foreign []audiciav1alpha1.CloudProvider
+	for _, b := range blocks {
+		if b.set && b.provider != cfg.Provider {
+			foreign = append(foreign, b.provider)
+		}
+	}
+	return foreign
 }
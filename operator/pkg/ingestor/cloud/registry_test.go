@@ -0,0 +1,132 @@
+package cloud
+
+import (
+	"strings"
+	"testing"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func TestBuildAdapter_PassesSourceIdentity(t *testing.T) {
+	const provider audiciav1alpha1.CloudProvider = "TestProvider"
+	var got []SourceIdentity
+	RegisterAdapter(provider, func(_ *audiciav1alpha1.CloudConfig, id SourceIdentity) (MessageSource, EnvelopeParser, error) {
+		got = append(got, id)
+		return NewFakeSource(), &fakeParser{}, nil
+	})
+	defer delete(registry, provider)
+
+	cfg := &audiciav1alpha1.CloudConfig{Provider: provider}
+	ids := []SourceIdentity{{Namespace: "team-a", Name: "aks"}, {Namespace: "team-b", Name: "eks"}}
+	var sources []MessageSource
+	for _, id := range ids {
+		src, _, err := BuildAdapter(cfg, id)
+		if err != nil {
+			t.Fatalf("BuildAdapter(%s) error = %v", id, err)
+		}
+		sources = append(sources, src)
+	}
+
+	if len(got) != 2 || got[0] != ids[0] || got[1] != ids[1] {
+		t.Errorf("factory received identities %v, want %v", got, ids)
+	}
+	if sources[0] == sources[1] {
+		t.Error("expected each BuildAdapter call to return an independent source")
+	}
+}
+
+func TestBuildAdapter_UnknownProvider(t *testing.T) {
+	_, _, err := BuildAdapter(&audiciav1alpha1.CloudConfig{Provider: "Nope"}, SourceIdentity{})
+	if err == nil {
+		t.Fatal("expected error for unregistered provider")
+	}
+}
+
+func TestForeignProviderSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  audiciav1alpha1.CloudConfig
+		want audiciav1alpha1.CloudProvider
+	}{
+		{
+			name: "aws block on azure source",
+			want: audiciav1alpha1.CloudProviderAWSCloudWatch,
+			cfg: audiciav1alpha1.CloudConfig{
+				Provider: audiciav1alpha1.CloudProviderAzureEventHub,
+				Azure:    &audiciav1alpha1.AzureEventHubConfig{},
+				AWS:      &audiciav1alpha1.AWSCloudWatchConfig{RoleARN: "arn:aws:iam::1:role/x"},
+			},
+		},
+		{
+			name: "gcp block on aws source",
+			want: audiciav1alpha1.CloudProviderGCPPubSub,
+			cfg: audiciav1alpha1.CloudConfig{
+				Provider: audiciav1alpha1.CloudProviderAWSCloudWatch,
+				GCP:      &audiciav1alpha1.GCPPubSubConfig{},
+			},
+		},
+		{
+			name: "cloudwatch block on s3 source",
+			want: audiciav1alpha1.CloudProviderAWSCloudWatch,
+			cfg: audiciav1alpha1.CloudConfig{
+				Provider: audiciav1alpha1.CloudProviderAWSS3,
+				S3:       &audiciav1alpha1.AWSS3Config{Bucket: "audit"},
+				AWS:      &audiciav1alpha1.AWSCloudWatchConfig{LogGroupName: "/aws/eks/prod/cluster"},
+			},
+		},
+		{
+			name: "gcp block on kafka source",
+			want: audiciav1alpha1.CloudProviderGCPPubSub,
+			cfg: audiciav1alpha1.CloudConfig{
+				Provider: audiciav1alpha1.CloudProviderKafka,
+				Kafka:    &audiciav1alpha1.KafkaConfig{Brokers: []string{"kafka:9092"}, Topic: "audit"},
+				GCP:      &audiciav1alpha1.GCPPubSubConfig{ProjectID: "p"},
+			},
+		},
+		{
+			name: "azure block on gcp source",
+			want: audiciav1alpha1.CloudProviderAzureEventHub,
+			cfg: audiciav1alpha1.CloudConfig{
+				Provider: audiciav1alpha1.CloudProviderGCPPubSub,
+				Azure:    &audiciav1alpha1.AzureEventHubConfig{ClientID: "abc"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ForeignProviderSettings(&tt.cfg)
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("ForeignProviderSettings() = %v, want [%s]", got, tt.want)
+			}
+		})
+	}
+
+	own := &audiciav1alpha1.CloudConfig{Provider: audiciav1alpha1.CloudProviderKafka, Kafka: &audiciav1alpha1.KafkaConfig{}}
+	if got := ForeignProviderSettings(own); got != nil {
+		t.Errorf("ForeignProviderSettings() = %v for the selected provider's block, want nil", got)
+	}
+}
+
+func TestSourceIdentity_SessionName(t *testing.T) {
+	tests := []struct {
+		name string
+		id   SourceIdentity
+		want string
+	}{
+		{"simple", SourceIdentity{Namespace: "audicia-system", Name: "eks"}, "audicia-audicia-system-eks"},
+		{"invalid chars replaced", SourceIdentity{Namespace: "ns", Name: "a/b c"}, "audicia-ns-a-b-c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.id.SessionName(); got != tt.want {
+				t.Errorf("SessionName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	long := SourceIdentity{Namespace: strings.Repeat("n", 63), Name: strings.Repeat("s", 63)}
+	if got := long.SessionName(); len(got) != maxSessionNameLength {
+		t.Errorf("SessionName() length = %d, want %d", len(got), maxSessionNameLength)
+	}
+}
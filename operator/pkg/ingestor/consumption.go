@@ -0,0 +1,52 @@
+package ingestor
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+)
+
+// consumption counts the input an ingestor consumed for one source in the
+// audicia_ingestor_* metrics. A nil *consumption counts nothing.
+type consumption struct {
+	bytes, lines, failures, events prometheus.Counter
+}
+
+// newConsumption returns nil when source is empty, for ingestors not
+// labelled with a source.
+func newConsumption(source string) *consumption {
+	if source == "" {
+		return nil
+	}
+	return &consumption{
+		bytes:    metrics.IngestorBytesReadTotal.WithLabelValues(source),
+		lines:    metrics.IngestorLinesParsedTotal.WithLabelValues(source),
+		failures: metrics.IngestorParseFailuresTotal.WithLabelValues(source),
+		events:   metrics.IngestorEventsEmittedTotal.WithLabelValues(source),
+	}
+}
+
+// read counts n bytes read.
+func (c *consumption) read(n int) {
+	if c != nil {
+		c.bytes.Add(float64(n))
+	}
+}
+
+// parsed counts a line or payload parsed, or one that failed to parse.
+func (c *consumption) parsed(ok bool) {
+	switch {
+	case c == nil:
+	case ok:
+		c.lines.Inc()
+	default:
+		c.failures.Inc()
+	}
+}
+
+// emitted counts n events handed to the pipeline.
+func (c *consumption) emitted(n int) {
+	if c != nil {
+		c.events.Add(float64(n))
+	}
+}
@@ -0,0 +1,59 @@
+package ingestor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+)
+
+// consumed returns the audicia_ingestor_* counters of source.
+func consumed(source string) (bytes, lines, failures, events float64) {
+	return testutil.ToFloat64(metrics.IngestorBytesReadTotal.WithLabelValues(source)),
+		testutil.ToFloat64(metrics.IngestorLinesParsedTotal.WithLabelValues(source)),
+		testutil.ToFloat64(metrics.IngestorParseFailuresTotal.WithLabelValues(source)),
+		testutil.ToFloat64(metrics.IngestorEventsEmittedTotal.WithLabelValues(source))
+}
+
+func TestScanAndEmit_CountsConsumption(t *testing.T) {
+	input := validAuditJSON("a1", "get", "pods", "default") + "\n" +
+		"not json\n" +
+		validAuditJSON("a2", "list", "pods", "default") + "\n"
+	ch := make(chan auditv1.Event, 10)
+
+	if _, err := scanAndEmit(context.Background(), newAuditScanner(strings.NewReader(input)), ch, newConsumption("default/file")); err != nil {
+		t.Fatal(err)
+	}
+	read, lines, failures, events := consumed("default/file")
+	if int(read) != len(input) || lines != 2 || failures != 1 || events != 2 {
+		t.Errorf("bytes=%v lines=%v failures=%v events=%v, want %d, 2, 1, 2", read, lines, failures, events, len(input))
+	}
+}
+
+func TestHandleAuditRequest_CountsConsumption(t *testing.T) {
+	w := &WebhookIngestor{MaxRequestBodyBytes: 1048576, consumed: newConsumption("default/webhook")}
+	handler := w.handleAuditRequest(make(chan auditv1.Event, 10), NewEventDeduplicator(100), newRequestLimiter(100, 0))
+
+	body, _ := json.Marshal(auditv1.EventList{Items: []auditv1.Event{{AuditID: "w1", Verb: "get"}, {AuditID: "w2", Verb: "get"}}})
+	for _, payload := range [][]byte{body, []byte("not json")} {
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload)))
+	}
+
+	read, lines, failures, events := consumed("default/webhook")
+	if int(read) != len(body)+len("not json") || lines != 1 || failures != 1 || events != 2 {
+		t.Errorf("bytes=%v lines=%v failures=%v events=%v, want %d, 1, 1, 2", read, lines, failures, events, len(body)+8)
+	}
+
+	var unlabelled *consumption
+	unlabelled.read(1)
+	unlabelled.parsed(true)
+	unlabelled.emitted(1)
+}
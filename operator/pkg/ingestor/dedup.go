@@ -0,0 +1,71 @@
+package ingestor
+
+import (
+	"container/list"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// DefaultDeduplicationWindow is the number of recent events remembered when
+// no window size is configured.
+const DefaultDeduplicationWindow = 10000
+
+// dedupKey identifies one stage of one request. Every stage of a request
+// shares its auditID, so the stage is part of the key.
+type dedupKey struct {
+	auditID types.UID
+	stage   auditv1.Stage
+}
+
+// EventDeduplicator drops audit events already seen within a bounded LRU
+// window. HA control planes log a request on the replica that served it, but
+// log shippers, webhook retries and overlapping sources deliver some events
+// more than once; the window keeps each auditID and stage pair counted once.
+type EventDeduplicator struct {
+	mu      sync.Mutex
+	entries map[dedupKey]*list.Element
+	order   *list.List // front is the most recently seen key
+	maxSize int
+}
+
+// NewEventDeduplicator creates a deduplicator remembering up to maxSize
+// events. maxSize <= 0 uses DefaultDeduplicationWindow.
+func NewEventDeduplicator(maxSize int) *EventDeduplicator {
+	if maxSize <= 0 {
+		maxSize = DefaultDeduplicationWindow
+	}
+	return &EventDeduplicator{
+		entries: make(map[dedupKey]*list.Element, maxSize),
+		order:   list.New(),
+		maxSize: maxSize,
+	}
+}
+
+// Seen reports whether the event's auditID and stage were already seen and
+// records them if not. Events without an auditID are never duplicates.
+func (d *EventDeduplicator) Seen(event auditv1.Event) bool {
+	if event.AuditID == "" {
+		return false
+	}
+	return d.seen(dedupKey{auditID: event.AuditID, stage: event.Stage})
+}
+
+func (d *EventDeduplicator) seen(key dedupKey) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if el, ok := d.entries[key]; ok {
+		d.order.MoveToFront(el)
+		return true
+	}
+
+	if d.order.Len() >= d.maxSize {
+		oldest := d.order.Back()
+		d.order.Remove(oldest)
+		delete(d.entries, oldest.Value.(dedupKey))
+	}
+	d.entries[key] = d.order.PushFront(key)
+	return false
+}
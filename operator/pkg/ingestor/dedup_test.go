@@ -0,0 +1,77 @@
+package ingestor
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+func dedupEvent(id string, stage auditv1.Stage) auditv1.Event {
+	return auditv1.Event{AuditID: types.UID(id), Stage: stage}
+}
+
+func TestEventDeduplicator_Basic(t *testing.T) {
+	d := NewEventDeduplicator(3)
+
+	if d.Seen(dedupEvent("a", auditv1.StageResponseComplete)) {
+		t.Error("'a' should not be seen yet")
+	}
+	if !d.Seen(dedupEvent("a", auditv1.StageResponseComplete)) {
+		t.Error("'a' should be seen now")
+	}
+}
+
+func TestEventDeduplicator_StageIsPartOfKey(t *testing.T) {
+	d := NewEventDeduplicator(10)
+
+	d.Seen(dedupEvent("a", auditv1.StageRequestReceived))
+	if d.Seen(dedupEvent("a", auditv1.StageResponseComplete)) {
+		t.Error("another stage of the same request is not a duplicate")
+	}
+	if !d.Seen(dedupEvent("a", auditv1.StageRequestReceived)) {
+		t.Error("the same stage again is a duplicate")
+	}
+}
+
+func TestEventDeduplicator_EmptyAuditID(t *testing.T) {
+	d := NewEventDeduplicator(10)
+
+	d.Seen(dedupEvent("", auditv1.StageResponseComplete))
+	if d.Seen(dedupEvent("", auditv1.StageResponseComplete)) {
+		t.Error("events without an auditID are never duplicates")
+	}
+}
+
+func TestEventDeduplicator_Eviction(t *testing.T) {
+	d := NewEventDeduplicator(2)
+
+	d.Seen(dedupEvent("a", ""))
+	d.Seen(dedupEvent("b", ""))
+	d.Seen(dedupEvent("c", "")) // Evicts "a". Window: [c, b].
+
+	// Check "b" first: Seen is check-and-add, so checking "a" first would
+	// re-add it and evict "b".
+	if !d.Seen(dedupEvent("b", "")) {
+		t.Error("'b' should still be present")
+	}
+	if d.Seen(dedupEvent("a", "")) {
+		t.Error("'a' should have been evicted")
+	}
+}
+
+func TestEventDeduplicator_HitRefreshesEntry(t *testing.T) {
+	d := NewEventDeduplicator(2)
+
+	d.Seen(dedupEvent("a", ""))
+	d.Seen(dedupEvent("b", ""))
+	d.Seen(dedupEvent("a", "")) // Hit: "a" becomes the most recent.
+	d.Seen(dedupEvent("c", "")) // Evicts "b", the least recently seen.
+
+	if !d.Seen(dedupEvent("a", "")) {
+		t.Error("'a' was refreshed and should still be present")
+	}
+	if d.Seen(dedupEvent("b", "")) {
+		t.Error("'b' should have been evicted")
+	}
+}
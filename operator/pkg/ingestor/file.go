@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"sync"
@@ -26,8 +27,30 @@ type FileIngestor struct {
 	// BatchSize is the number of events to read per batch.
 	BatchSize int
 
+	// RotatedFilePattern is a glob for rotated copies of Path. When set, the
+	// unread tail of a rotated file is read before the new file.
+	RotatedFilePattern string
+
+	// SourceLabel is the source label of the audicia_ingestor_* metrics.
+	// Without one, no metrics are recorded.
+	SourceLabel string
+
 	mu       sync.Mutex
 	position Position
+	consumed *consumption
+
+	// observe, when set, is called with every new position.
+	observe func(Position)
+	onError func(error)
+	onProbe func(error)
+
+	// probeInterval is how often Path is probed; zero disables the probe.
+	probeInterval time.Duration
+	// reprobe requests a probe before the next interval.
+	reprobe chan struct{}
+
+	// failures counts consecutive failed reads. Only tail uses it.
+	failures int
 }
 
 // NewFileIngestor creates a new file-based ingestor.
@@ -40,17 +63,23 @@ func NewFileIngestor(path string, startPos Position, batchSize int) *FileIngesto
 		StartPosition: startPos,
 		BatchSize:     batchSize,
 		position:      startPos,
+		probeInterval: probeInterval,
+		reprobe:       make(chan struct{}, 1),
 	}
 }
 
 // Start begins tailing the audit log file.
 func (f *FileIngestor) Start(ctx context.Context) (<-chan auditv1.Event, error) {
 	ch := make(chan auditv1.Event, f.BatchSize)
+	f.consumed = newConsumption(f.SourceLabel)
 
 	go func() {
 		defer close(ch)
 		f.tail(ctx, ch)
 	}()
+	if f.probeInterval > 0 {
+		go runProbe(ctx, f.probeInterval, f.reprobe, f.SourceLabel, func() error { return probePath(f.Path) }, f.onProbe)
+	}
 
 	return ch, nil
 }
@@ -62,28 +91,83 @@ func (f *FileIngestor) Checkpoint() Position {
 	return f.position
 }
 
+// OnError implements ErrorReporter.
+func (f *FileIngestor) OnError(fn func(error)) {
+	f.onError = fn
+}
+
+// OnProbe implements Prober.
+func (f *FileIngestor) OnProbe(fn func(error)) {
+	f.onProbe = fn
+}
+
+func (f *FileIngestor) reportError(err error) {
+	if f.onError != nil {
+		f.onError(err)
+	}
+}
+
 func (f *FileIngestor) setPosition(pos Position) {
 	f.mu.Lock()
-	defer f.mu.Unlock()
 	f.position = pos
+	f.mu.Unlock()
+	if f.observe != nil {
+		f.observe(pos)
+	}
+}
+
+// Retry delays of a file that cannot be read: the delay doubles with each
+// consecutive failure, so a missing file or denied permission is retried
+// quickly at first without flooding the logs while it persists.
+const (
+	fileRetryBaseDelay = 2 * time.Second
+	fileRetryMaxDelay  = time.Minute
+)
+
+// fileRetryDelay returns the delay before reopening the file after the given
+// number of consecutive failures. Without failures, e.g. after a rotation,
+// the file is reopened after the base delay.
+func fileRetryDelay(failures int) time.Duration {
+	delay := fileRetryBaseDelay
+	for i := 1; i < failures && delay < fileRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, fileRetryMaxDelay)
 }
 
 // tail is the main loop that opens, reads, and watches the audit log file.
 func (f *FileIngestor) tail(ctx context.Context, ch chan<- auditv1.Event) {
 	for {
-		if err := f.readFile(ctx, ch); err != nil {
-			fileLog.Error(err, "error reading audit log", "path", f.Path)
+		if err := f.readFile(ctx, ch); err != nil && ctx.Err() == nil {
+			f.failures++
+			fileLog.Error(err, "error reading audit log", "path", f.Path, "retryIn", fileRetryDelay(f.failures))
+			f.reportError(fmt.Errorf("reading %s: %w", f.Path, err))
 		}
 
 		// Wait before retrying (file may not exist yet, or rotation happened).
 		select {
 		case <-ctx.Done():
 			return
-		case <-time.After(2 * time.Second):
+		case <-time.After(fileRetryDelay(f.failures)):
 		}
 	}
 }
 
+// opened resets the failures once the file could be opened again, and
+// triggers a probe so the source reports it readable without waiting for the
+// next probe interval.
+func (f *FileIngestor) opened() {
+	if f.failures == 0 {
+		return
+	}
+	fileLog.Info("audit log readable again", "path", f.Path, "failedAttempts", f.failures)
+	f.failures = 0
+	select {
+	case f.reprobe <- struct{}{}:
+	default:
+	}
+}
+
 // readFile opens the file, seeks to the checkpoint offset, and reads events.
 func (f *FileIngestor) readFile(ctx context.Context, ch chan<- auditv1.Event) error {
 	file, err := os.Open(f.Path)
@@ -95,6 +179,7 @@ func (f *FileIngestor) readFile(ctx context.Context, ch chan<- auditv1.Event) er
 			fileLog.V(1).Info("error closing audit log file", "error", cerr)
 		}
 	}()
+	f.opened()
 
 	// Check inode to detect log rotation.
 	currentInode, err := fileInode(file)
@@ -104,9 +189,14 @@ func (f *FileIngestor) readFile(ctx context.Context, ch chan<- auditv1.Event) er
 
 	startPos := f.Checkpoint()
 
-	// If inode changed (rotation), read from beginning.
+	// If inode changed (rotation), catch up on the rotated file and read
+	// the new one from the beginning.
 	if startPos.Inode != 0 && currentInode != 0 && startPos.Inode != currentInode {
 		fileLog.Info("detected log rotation (inode changed)", "oldInode", startPos.Inode, "newInode", currentInode)
+		if err := f.catchUpRotated(ctx, startPos, ch); err != nil {
+			fileLog.Error(err, "error reading rotated audit log", "pattern", f.RotatedFilePattern)
+			f.reportError(fmt.Errorf("reading rotated %s: %w", f.RotatedFilePattern, err))
+		}
 		startPos.FileOffset = 0
 	}
 
@@ -119,7 +209,7 @@ func (f *FileIngestor) readFile(ctx context.Context, ch chan<- auditv1.Event) er
 
 	scanner := newAuditScanner(file)
 
-	if _, err := scanAndEmit(ctx, scanner, ch); err != nil {
+	if _, err := scanAndEmit(ctx, scanner, ch, f.consumed); err != nil {
 		return err
 	}
 
@@ -147,8 +237,9 @@ func newAuditScanner(r io.Reader) *bufio.Scanner {
 }
 
 // scanAndEmit reads all available lines from the scanner, parses them as audit
-// events, and sends them on ch. Returns whether any events were emitted.
-func scanAndEmit(ctx context.Context, scanner *bufio.Scanner, ch chan<- auditv1.Event) (bool, error) {
+// events, and sends them on ch, counting them in consumed. Returns whether any
+// events were emitted.
+func scanAndEmit(ctx context.Context, scanner *bufio.Scanner, ch chan<- auditv1.Event, consumed *consumption) (bool, error) {
 	readAny := false
 	for scanner.Scan() {
 		select {
@@ -158,6 +249,7 @@ func scanAndEmit(ctx context.Context, scanner *bufio.Scanner, ch chan<- auditv1.
 		}
 
 		line := scanner.Bytes()
+		consumed.read(len(line) + 1)
 		if len(line) == 0 {
 			continue
 		}
@@ -165,11 +257,14 @@ func scanAndEmit(ctx context.Context, scanner *bufio.Scanner, ch chan<- auditv1.
 		var event auditv1.Event
 		if err := json.Unmarshal(line, &event); err != nil {
 			fileLog.V(1).Info("skipping malformed audit event line", "error", err)
+			consumed.parsed(false)
 			continue
 		}
+		consumed.parsed(true)
 
 		select {
 		case ch <- event:
+			consumed.emitted(1)
 			readAny = true
 		case <-ctx.Done():
 			return readAny, ctx.Err()
@@ -193,16 +288,45 @@ func (f *FileIngestor) pollForData(ctx context.Context, file *os.File, ch chan<-
 		case <-ticker.C:
 		}
 
-		// Check if file was rotated by comparing inodes.
+		// Check if file was rotated by comparing inodes. The open handle still
+		// refers to the rotated file, so drain it before switching: events
+		// written between the last poll and the rotation would be lost
+		// otherwise.
 		currentInode, err := fileInodeByPath(f.Path)
 		if err != nil {
 			// File may have been removed during rotation; return to reopen.
+			// The checkpoint keeps the old inode, so readFile catches up on
+			// anything appended after this drain via RotatedFilePattern.
+			if _, err := scanAndEmit(ctx, scanner, ch, f.consumed); err != nil {
+				return err
+			}
+			offset, err := file.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+			f.setPosition(Position{
+				FileOffset:    offset,
+				Inode:         originalInode,
+				LastTimestamp: time.Now().UTC().Format(time.RFC3339),
+			})
 			return nil
 		}
 		if originalInode != 0 && currentInode != 0 && originalInode != currentInode {
 			// File rotated. Save position and return so tail() reopens.
 			fileLog.Info("file rotated during polling, reopening")
-			pos := f.Checkpoint()
+			if _, err := scanAndEmit(ctx, scanner, ch, f.consumed); err != nil {
+				return err
+			}
+			offset, err := file.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+			pos := Position{
+				FileOffset:    offset,
+				Inode:         originalInode,
+				LastTimestamp: time.Now().UTC().Format(time.RFC3339),
+			}
+			f.setPosition(pos)
 			pos.Inode = currentInode
 			pos.FileOffset = 0
 			f.setPosition(pos)
@@ -210,7 +334,7 @@ func (f *FileIngestor) pollForData(ctx context.Context, file *os.File, ch chan<-
 		}
 
 		// Try to read more lines.
-		readAny, err := scanAndEmit(ctx, scanner, ch)
+		readAny, err := scanAndEmit(ctx, scanner, ch, f.consumed)
 		if err != nil {
 			return err
 		}
@@ -2,6 +2,7 @@ package ingestor
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -46,7 +47,7 @@ func TestScanAndEmit_ValidEvents(t *testing.T) {
 	scanner := newAuditScanner(strings.NewReader(input))
 	ch := make(chan auditv1.Event, 10)
 
-	readAny, err := scanAndEmit(context.Background(), scanner, ch)
+	readAny, err := scanAndEmit(context.Background(), scanner, ch, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -75,7 +76,7 @@ func TestScanAndEmit_MalformedLinesSkipped(t *testing.T) {
 	scanner := newAuditScanner(strings.NewReader(input))
 	ch := make(chan auditv1.Event, 10)
 
-	readAny, err := scanAndEmit(context.Background(), scanner, ch)
+	readAny, err := scanAndEmit(context.Background(), scanner, ch, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -97,7 +98,7 @@ func TestScanAndEmit_EmptyInput(t *testing.T) {
 	scanner := newAuditScanner(strings.NewReader(""))
 	ch := make(chan auditv1.Event, 10)
 
-	readAny, err := scanAndEmit(context.Background(), scanner, ch)
+	readAny, err := scanAndEmit(context.Background(), scanner, ch, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -111,7 +112,7 @@ func TestScanAndEmit_EmptyLinesIgnored(t *testing.T) {
 	scanner := newAuditScanner(strings.NewReader(input))
 	ch := make(chan auditv1.Event, 10)
 
-	readAny, err := scanAndEmit(context.Background(), scanner, ch)
+	readAny, err := scanAndEmit(context.Background(), scanner, ch, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -142,7 +143,7 @@ func TestScanAndEmit_ContextCancelled(t *testing.T) {
 	cancel() // Cancel immediately.
 
 	ch := make(chan auditv1.Event, 1)
-	_, err := scanAndEmit(ctx, scanner, ch)
+	_, err := scanAndEmit(ctx, scanner, ch, nil)
 	if err != nil && err != context.Canceled {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -331,3 +332,95 @@ func TestFileIngestor_PollDetectsRotation(t *testing.T) {
 	for range ch {
 	}
 }
+
+func TestFileIngestor_ReportsReadErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.log")
+	ing := NewFileIngestor(path, Position{}, 100)
+	errs := make(chan error, 1)
+	ing.OnError(func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := ing.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-errs:
+		if !os.IsNotExist(errors.Unwrap(err)) || !strings.Contains(err.Error(), path) {
+			t.Errorf("reported error = %v, want the missing file", err)
+		}
+	case <-time.After(4 * time.Second):
+		t.Error("timeout: read error not reported")
+	}
+
+	cancel()
+	for range ch {
+	}
+}
+
+func TestFileRetryDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 2 * time.Second},
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{5, 32 * time.Second},
+		{6, time.Minute},
+		{100, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := fileRetryDelay(tt.failures); got != tt.want {
+			t.Errorf("fileRetryDelay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestFileIngestor_RecoversWhenFileAppears(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	ing := NewFileIngestor(path, Position{}, 100)
+	ing.probeInterval = time.Hour // only probes triggered by recovery
+	probes := make(chan error, 10)
+	ing.OnProbe(func(err error) { probes <- err })
+	readErrors := make(chan error, 10)
+	ing.OnError(func(err error) { readErrors <- err })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ch, err := ing.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-probes; err == nil {
+		t.Fatal("first probe succeeded on a missing file")
+	}
+	<-readErrors // the first read failed; the retry is backed off
+
+	writeAuditFile(t, path, []string{validAuditJSON("a1", "get", "pods", "default")})
+	select {
+	case event := <-ch:
+		if string(event.AuditID) != "a1" {
+			t.Errorf("expected auditID=a1, got %s", event.AuditID)
+		}
+	case <-time.After(8 * time.Second):
+		t.Fatal("timeout: the ingestor did not recover once the file appeared")
+	}
+	select {
+	case err := <-probes:
+		if err != nil {
+			t.Errorf("probe after recovery: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("recovery did not trigger a probe")
+	}
+
+	cancel()
+	for range ch {
+	}
+}
@@ -0,0 +1,414 @@
+package ingestor
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var forwardLog = ctrl.Log.WithName("ingestor").WithName("forward")
+
+const (
+	// DefaultFluentdPort is the conventional Fluentd forward port.
+	DefaultFluentdPort = 24224
+	// DefaultSyslogPort is the conventional syslog-over-TLS port (RFC 5425).
+	DefaultSyslogPort = 6514
+)
+
+// Forward protocols accepted by ForwardIngestor. The values match the
+// AudiciaSource spec.forward.protocol enum.
+const (
+	ForwardProtocolFluentd = "Fluentd"
+	ForwardProtocolSyslog  = "Syslog"
+)
+
+// ForwardIngestor receives audit log lines pushed by node log shippers over
+// TCP, either as Fluentd forward messages or as RFC 5424 syslog messages.
+// Agents tail the apiserver audit log on the control plane node, so the
+// operator needs no hostPath access to it. Like the webhook, it is
+// stateless: delivery guarantees come from the agent (Fluentd "chunk" acks
+// are sent once a message's events are queued), and redelivered events are
+// dropped by auditID.
+type ForwardIngestor struct {
+	// Protocol is ForwardProtocolFluentd or ForwardProtocolSyslog.
+	Protocol string
+
+	// Port is the TCP port to listen on.
+	Port int32
+
+	// TLSCertFile and TLSKeyFile enable TLS when both are set.
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// ClientCAFile is the path to the CA bundle for client certificate
+	// verification. Requires TLS.
+	ClientCAFile string
+
+	// MaxMessageBytes is the maximum size of one forward message (after
+	// decompression) or syslog frame.
+	MaxMessageBytes int64
+
+	// DeduplicationCacheSize is the size of the auditID and stage LRU cache.
+	DeduplicationCacheSize int
+}
+
+// NewForwardIngestor creates a forward ingestor with default limits. A zero
+// port selects the protocol's conventional port.
+func NewForwardIngestor(protocol string, port int32) *ForwardIngestor {
+	if port <= 0 {
+		port = DefaultFluentdPort
+		if protocol == ForwardProtocolSyslog {
+			port = DefaultSyslogPort
+		}
+	}
+	return &ForwardIngestor{
+		Protocol:               protocol,
+		Port:                   port,
+		MaxMessageBytes:        8 << 20, // 8MB
+		DeduplicationCacheSize: DefaultDeduplicationWindow,
+	}
+}
+
+// Start opens the listener and begins accepting agent connections. Listener
+// and TLS configuration errors are returned immediately.
+func (f *ForwardIngestor) Start(ctx context.Context) (<-chan auditv1.Event, error) {
+	if f.Protocol != ForwardProtocolFluentd && f.Protocol != ForwardProtocolSyslog {
+		return nil, fmt.Errorf("unsupported forward protocol %q", f.Protocol)
+	}
+	listener, err := f.listen()
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan auditv1.Event, 500)
+	dedup := NewEventDeduplicator(f.DeduplicationCacheSize)
+
+	go func() {
+		var wg sync.WaitGroup
+		defer func() {
+			wg.Wait()
+			close(ch)
+		}()
+		stop := context.AfterFunc(ctx, func() { _ = listener.Close() })
+		defer stop()
+
+		forwardLog.Info("starting forward receiver", "protocol", f.Protocol,
+			"address", listener.Addr().String(), "tls", f.TLSCertFile != "")
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if ctx.Err() == nil {
+					forwardLog.Error(err, "forward receiver stopped accepting connections")
+				}
+				return
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				f.serveConn(ctx, conn, ch, dedup)
+			}()
+		}
+	}()
+
+	return ch, nil
+}
+
+// listen opens the TCP listener, wrapped in TLS when configured.
+func (f *ForwardIngestor) listen() (net.Listener, error) {
+	var tlsConfig *tls.Config
+	if f.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(f.TLSCertFile, f.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading forward TLS certificate: %w", err)
+		}
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		if f.ClientCAFile != "" {
+			tlsConfig, err = clientCATLSConfig(f.ClientCAFile)
+			if err != nil {
+				return nil, err
+			}
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	} else if f.ClientCAFile != "" {
+		return nil, fmt.Errorf("forward client CA requires a TLS certificate")
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", f.Port))
+	if err != nil {
+		return nil, fmt.Errorf("listening on port %d: %w", f.Port, err)
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	return listener, nil
+}
+
+// serveConn reads messages from one agent connection until it closes, the
+// context ends, or the stream is corrupt.
+func (f *ForwardIngestor) serveConn(ctx context.Context, conn net.Conn, ch chan<- auditv1.Event, dedup *EventDeduplicator) {
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+	defer func() { _ = conn.Close() }()
+
+	emit := func(events []auditv1.Event) bool {
+		for _, event := range events {
+			if dedup.Seen(event) {
+				continue
+			}
+			// Block rather than drop: the agent buffers while the
+			// pipeline catches up.
+			select {
+			case ch <- event:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		return true
+	}
+
+	var err error
+	if f.Protocol == ForwardProtocolSyslog {
+		err = f.serveSyslog(conn, emit)
+	} else {
+		err = f.serveFluentd(conn, emit)
+	}
+	if err != nil && ctx.Err() == nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+		forwardLog.Error(err, "closing forward connection", "remote", conn.RemoteAddr().String())
+	}
+}
+
+// serveFluentd handles the Fluentd forward protocol: a stream of msgpack
+// arrays in Message, Forward, PackedForward or CompressedPackedForward mode.
+// Messages carrying a "chunk" option are acknowledged once their events are
+// queued, so the agent retries anything not acknowledged.
+func (f *ForwardIngestor) serveFluentd(conn net.Conn, emit func([]auditv1.Event) bool) error {
+	r := bufio.NewReader(conn)
+	for {
+		v, err := newMsgpackDecoder(r, f.MaxMessageBytes).decode()
+		if err != nil {
+			return err
+		}
+		records, chunk, err := fluentdRecords(v, f.MaxMessageBytes)
+		if err != nil {
+			return err
+		}
+
+		var events []auditv1.Event
+		for _, record := range records {
+			recordEvents, err := fluentdRecordEvents(record)
+			if err != nil {
+				forwardLog.V(1).Info("skipping unparsable record", "error", err.Error())
+				continue
+			}
+			events = append(events, recordEvents...)
+		}
+		if !emit(events) {
+			return nil
+		}
+
+		if chunk != "" {
+			if _, err := conn.Write(encodeFluentdAck(chunk)); err != nil {
+				return fmt.Errorf("writing ack: %w", err)
+			}
+		}
+	}
+}
+
+// fluentdRecords returns the records of one forward message and the chunk ID
+// to acknowledge, if any.
+func fluentdRecords(v any, maxBytes int64) ([]map[string]any, string, error) {
+	msg, ok := v.([]any)
+	if !ok || len(msg) < 2 {
+		return nil, "", fmt.Errorf("forward message is not an array of at least 2 elements")
+	}
+
+	var records []map[string]any
+	var option any
+	switch entries := msg[1].(type) {
+	case []any: // Forward mode: [tag, [[time, record], ...], option]
+		for _, entry := range entries {
+			if record, ok := fluentdEntryRecord(entry); ok {
+				records = append(records, record)
+			}
+		}
+		option = optionalElement(msg, 2)
+	case string, []byte: // PackedForward mode: [tag, msgpack stream, option]
+		option = optionalElement(msg, 2)
+		var err error
+		records, err = unpackEntries(asBytes(entries), optionString(option, "compressed") == "gzip", maxBytes)
+		if err != nil {
+			return nil, "", err
+		}
+	default: // Message mode: [tag, time, record, option]
+		if record, ok := optionalElement(msg, 2).(map[string]any); ok {
+			records = append(records, record)
+		}
+		option = optionalElement(msg, 3)
+	}
+	return records, optionString(option, "chunk"), nil
+}
+
+// unpackEntries decodes the concatenated [time, record] entries of a
+// PackedForward message.
+func unpackEntries(data []byte, gzipped bool, maxBytes int64) ([]map[string]any, error) {
+	var src io.Reader = bytes.NewReader(data)
+	if gzipped {
+		gz, err := gzip.NewReader(src)
+		if err != nil {
+			return nil, fmt.Errorf("opening compressed entries: %w", err)
+		}
+		defer func() { _ = gz.Close() }()
+		src = gz
+	}
+	r := bufio.NewReader(io.LimitReader(src, maxBytes+1))
+	dec := newMsgpackDecoder(r, maxBytes)
+
+	var records []map[string]any
+	for {
+		if _, err := r.Peek(1); errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		entry, err := dec.decode()
+		if err != nil {
+			return nil, fmt.Errorf("decoding packed entries: %w", err)
+		}
+		if record, ok := fluentdEntryRecord(entry); ok {
+			records = append(records, record)
+		}
+	}
+}
+
+// fluentdEntryRecord extracts the record of a [time, record] entry.
+func fluentdEntryRecord(entry any) (map[string]any, bool) {
+	pair, ok := entry.([]any)
+	if !ok || len(pair) < 2 {
+		return nil, false
+	}
+	record, ok := pair[1].(map[string]any)
+	return record, ok
+}
+
+// fluentdRecordEvents converts a record into audit events. Agents that tail
+// the audit log without a parser put the line in "log" (Fluent Bit) or
+// "message" (Fluentd); agents that parse it send the event's fields as the
+// record itself.
+func fluentdRecordEvents(record map[string]any) ([]auditv1.Event, error) {
+	for _, key := range []string{"log", "message"} {
+		if line, ok := record[key]; ok {
+			switch l := line.(type) {
+			case string:
+				return parseAuditLine([]byte(l))
+			case []byte:
+				return parseAuditLine(l)
+			}
+		}
+	}
+	data, err := json.Marshal(jsonCompatible(record))
+	if err != nil {
+		return nil, fmt.Errorf("encoding record: %w", err)
+	}
+	return parseAuditLine(data)
+}
+
+// parseAuditLine decodes one audit log line: a single audit event, or an
+// EventList as POSTed by the apiserver's webhook backend. Lines that are not
+// audit events yield no events.
+func parseAuditLine(line []byte) ([]auditv1.Event, error) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil, nil
+	}
+
+	var probe struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(line, &probe); err != nil {
+		return nil, fmt.Errorf("decoding audit line: %w", err)
+	}
+	if probe.Kind == "EventList" {
+		var list auditv1.EventList
+		if err := json.Unmarshal(line, &list); err != nil {
+			return nil, fmt.Errorf("decoding audit event list: %w", err)
+		}
+		return list.Items, nil
+	}
+
+	var event auditv1.Event
+	if err := json.Unmarshal(line, &event); err != nil {
+		return nil, fmt.Errorf("decoding audit event: %w", err)
+	}
+	if event.AuditID == "" {
+		return nil, nil
+	}
+	return []auditv1.Event{event}, nil
+}
+
+// jsonCompatible converts decoded msgpack values into values encoding/json
+// can marshal: binary strings become strings and extensions are dropped.
+func jsonCompatible(v any) any {
+	switch t := v.(type) {
+	case []byte:
+		return string(t)
+	case msgpackExt:
+		return nil
+	case []any:
+		out := make([]any, len(t))
+		for i := range t {
+			out[i] = jsonCompatible(t[i])
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			out[k] = jsonCompatible(e)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func optionalElement(msg []any, i int) any {
+	if i < len(msg) {
+		return msg[i]
+	}
+	return nil
+}
+
+// optionString returns a string option from a forward message option map.
+func optionString(option any, key string) string {
+	m, ok := option.(map[string]any)
+	if !ok {
+		return ""
+	}
+	switch v := m[key].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return ""
+}
+
+func asBytes(v any) []byte {
+	if s, ok := v.(string); ok {
+		return []byte(s)
+	}
+	return v.([]byte)
+}
+
+// Checkpoint returns an empty position (forward sources are stateless).
+func (f *ForwardIngestor) Checkpoint() Position {
+	return Position{}
+}
@@ -0,0 +1,190 @@
+package ingestor
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+const testAuditLine = `{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","auditID":"%s","stage":"ResponseComplete","verb":"get","user":{"username":"alice"}}`
+
+func auditLine(id string) string {
+	return fmt.Sprintf(testAuditLine, id)
+}
+
+// serveTestConn runs serveConn on one end of a pipe and returns the client
+// end and the event channel, which is closed once the connection is served.
+func serveTestConn(t *testing.T, f *ForwardIngestor) (net.Conn, <-chan auditv1.Event) {
+	t.Helper()
+	server, client := net.Pipe()
+	ch := make(chan auditv1.Event, 100)
+	go func() {
+		f.serveConn(context.Background(), server, ch, NewEventDeduplicator(100))
+		close(ch)
+	}()
+	t.Cleanup(func() { _ = client.Close() })
+	return client, ch
+}
+
+func collectAuditIDs(ch <-chan auditv1.Event) []string {
+	var ids []string
+	for event := range ch {
+		ids = append(ids, string(event.AuditID))
+	}
+	return ids
+}
+
+func TestServeFluentd_Modes(t *testing.T) {
+	entry := func(id string) []any {
+		return []any{msgpackExt{Type: 0, Data: make([]byte, 8)}, map[string]any{"log": auditLine(id)}}
+	}
+	var packed []byte
+	packed = append(packed, mpEncode(entry("packed-1"))...)
+	packed = append(packed, mpEncode(entry("packed-2"))...)
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write(mpEncode(entry("gzip-1")))
+	_ = zw.Close()
+
+	f := NewForwardIngestor(ForwardProtocolFluentd, 0)
+	client, ch := serveTestConn(t, f)
+
+	messages := [][]byte{
+		// Message mode, with the line under "message".
+		mpEncode([]any{"kube.audit", 1700000000, map[string]any{"message": auditLine("message-1")}}),
+		// Forward mode.
+		mpEncode([]any{"kube.audit", []any{entry("forward-1"), entry("forward-2")}}),
+		// PackedForward mode, as bin.
+		mpEncode([]any{"kube.audit", packed}),
+		// CompressedPackedForward mode.
+		mpEncode([]any{"kube.audit", gz.Bytes(), map[string]any{"compressed": "gzip"}}),
+		// Parsed record: the event's fields are the record itself.
+		mpEncode([]any{"kube.audit", 1700000000, map[string]any{
+			"kind": "Event", "auditID": "parsed-1", "verb": "list", "user": map[string]any{"username": "bob"},
+		}}),
+		// A line that is not an audit event is skipped.
+		mpEncode([]any{"kube.audit", 1700000000, map[string]any{"log": "not json"}}),
+		// A redelivered event is dropped.
+		mpEncode([]any{"kube.audit", []any{entry("forward-1")}}),
+	}
+	for _, m := range messages {
+		if _, err := client.Write(m); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	_ = client.Close()
+
+	got := collectAuditIDs(ch)
+	want := []string{"message-1", "forward-1", "forward-2", "packed-1", "packed-2", "gzip-1", "parsed-1"}
+	if len(got) != len(want) {
+		t.Fatalf("got events %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestServeFluentd_ChunkAck(t *testing.T) {
+	f := NewForwardIngestor(ForwardProtocolFluentd, 0)
+	client, ch := serveTestConn(t, f)
+
+	msg := mpEncode([]any{"kube.audit", 1700000000, map[string]any{"log": auditLine("ack-1")}, map[string]any{"chunk": "Y2h1bmsx"}})
+	go func() { _, _ = client.Write(msg) }()
+
+	_ = client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	ack, err := newMsgpackDecoder(bufio.NewReader(client), 1024).decode()
+	if err != nil {
+		t.Fatalf("reading ack: %v", err)
+	}
+	if m, ok := ack.(map[string]any); !ok || m["ack"] != "Y2h1bmsx" {
+		t.Errorf("ack = %#v, want chunk Y2h1bmsx", ack)
+	}
+	_ = client.Close()
+
+	if ids := collectAuditIDs(ch); len(ids) != 1 || ids[0] != "ack-1" {
+		t.Errorf("got events %v, want [ack-1]", ids)
+	}
+}
+
+func TestServeFluentd_MessageTooLarge(t *testing.T) {
+	f := NewForwardIngestor(ForwardProtocolFluentd, 0)
+	f.MaxMessageBytes = 64
+	client, ch := serveTestConn(t, f)
+
+	go func() {
+		_, _ = client.Write(mpEncode([]any{"kube.audit", 1700000000, map[string]any{"log": auditLine("big-1")}}))
+	}()
+
+	// The connection is closed without emitting the event.
+	if ids := collectAuditIDs(ch); len(ids) != 0 {
+		t.Errorf("got events %v, want none", ids)
+	}
+}
+
+func TestServeSyslog(t *testing.T) {
+	f := NewForwardIngestor(ForwardProtocolSyslog, 0)
+	client, ch := serveTestConn(t, f)
+
+	rfc5424 := "<14>1 2025-06-01T12:00:00Z cp-1 kube-apiserver - - - " + auditLine("syslog-1")
+	frames := []string{
+		// Octet-counted framing.
+		strconv.Itoa(len(rfc5424)) + " " + rfc5424,
+		// Newline framing with structured data.
+		`<14>1 2025-06-01T12:00:00Z cp-1 kube-apiserver - audit [meta file="audit.log"] ` + auditLine("syslog-2") + "\n",
+		// RFC 3164.
+		"<14>Jun  1 12:00:00 cp-1 kube-apiserver: " + auditLine("syslog-3") + "\n",
+		// Not an audit event.
+		"<14>1 2025-06-01T12:00:00Z cp-1 sshd - - - session opened\n",
+	}
+	for _, frame := range frames {
+		if _, err := client.Write([]byte(frame)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	_ = client.Close()
+
+	got := collectAuditIDs(ch)
+	if len(got) != 3 || got[0] != "syslog-1" || got[1] != "syslog-2" || got[2] != "syslog-3" {
+		t.Errorf("got events %v, want [syslog-1 syslog-2 syslog-3]", got)
+	}
+}
+
+func TestForwardIngestor_StartErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		f    *ForwardIngestor
+	}{
+		{"unsupported protocol", &ForwardIngestor{Protocol: "GELF"}},
+		{"client CA without TLS", &ForwardIngestor{Protocol: ForwardProtocolFluentd, ClientCAFile: "/tmp/ca.crt"}},
+		{"missing certificate", &ForwardIngestor{Protocol: ForwardProtocolFluentd, TLSCertFile: "/nonexistent/tls.crt", TLSKeyFile: "/nonexistent/tls.key"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.f.Start(context.Background()); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestNewForwardIngestor_DefaultPort(t *testing.T) {
+	if got := NewForwardIngestor(ForwardProtocolFluentd, 0).Port; got != DefaultFluentdPort {
+		t.Errorf("fluentd port = %d, want %d", got, DefaultFluentdPort)
+	}
+	if got := NewForwardIngestor(ForwardProtocolSyslog, 0).Port; got != DefaultSyslogPort {
+		t.Errorf("syslog port = %d, want %d", got, DefaultSyslogPort)
+	}
+	if got := NewForwardIngestor(ForwardProtocolSyslog, 1514).Port; got != 1514 {
+		t.Errorf("explicit port = %d, want 1514", got)
+	}
+}
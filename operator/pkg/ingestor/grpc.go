@@ -0,0 +1,233 @@
+package ingestor
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	pushv1 "github.com/felixnotka/audicia/operator/pkg/ingestor/push/v1"
+)
+
+var grpcLog = ctrl.Log.WithName("ingestor").WithName("grpc")
+
+// DefaultGrpcPort is the conventional gRPC port.
+const DefaultGrpcPort = 50051
+
+// GrpcIngestor receives audit events pushed by external collectors over the
+// EventPush gRPC service (operator/pkg/ingestor/push/v1), so collectors for
+// any pipeline can feed a source without a dedicated adapter. Like the
+// webhook, it is stateless: each batch is acknowledged once its events are
+// queued, collectors resend unacknowledged batches, and redelivered events
+// are dropped by auditID.
+type GrpcIngestor struct {
+	// Port is the TCP port to listen on.
+	Port int32
+
+	// TLSCertFile and TLSKeyFile enable TLS when both are set.
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// ClientCAFile is the path to the CA bundle for client certificate
+	// verification. Requires TLS.
+	ClientCAFile string
+
+	// Authenticator, if set, must accept the bearer token in the
+	// "authorization" metadata of each stream.
+	Authenticator Authenticator
+
+	// MaxMessageBytes is the maximum size of one PushEventsRequest.
+	MaxMessageBytes int
+
+	// DeduplicationCacheSize is the size of the auditID and stage LRU cache.
+	DeduplicationCacheSize int
+
+	// SourceLabel is the source label of the audicia_ingestor_* metrics.
+	// Without one, no metrics are recorded.
+	SourceLabel string
+}
+
+// NewGrpcIngestor creates a gRPC ingestor with default limits. A zero port
+// selects DefaultGrpcPort.
+func NewGrpcIngestor(port int32) *GrpcIngestor {
+	if port <= 0 {
+		port = DefaultGrpcPort
+	}
+	return &GrpcIngestor{
+		Port:                   port,
+		MaxMessageBytes:        4 << 20, // 4MB, gRPC's default
+		DeduplicationCacheSize: DefaultDeduplicationWindow,
+	}
+}
+
+// Start opens the listener and begins serving EventPush. Listener and TLS
+// configuration errors are returned immediately.
+func (g *GrpcIngestor) Start(ctx context.Context) (<-chan auditv1.Event, error) {
+	opts, err := g.serverOptions()
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", g.Port))
+	if err != nil {
+		return nil, fmt.Errorf("listening on port %d: %w", g.Port, err)
+	}
+	return g.serve(ctx, listener, opts...), nil
+}
+
+// serverOptions returns the TLS credentials of the server, if configured.
+func (g *GrpcIngestor) serverOptions() ([]grpc.ServerOption, error) {
+	if g.TLSCertFile == "" {
+		if g.ClientCAFile != "" {
+			return nil, fmt.Errorf("gRPC client CA requires a TLS certificate")
+		}
+		return nil, nil
+	}
+
+	// Load the TLS material up front, so a broken Secret fails the pipeline
+	// start instead of every handshake.
+	files := []string{g.TLSCertFile, g.TLSKeyFile}
+	if g.ClientCAFile != "" {
+		files = append(files, g.ClientCAFile)
+	}
+	cert, err := loadServingCertificate(g.TLSCertFile, g.TLSKeyFile, files)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if g.ClientCAFile != "" {
+		tlsConfig, err = clientCATLSConfig(g.ClientCAFile)
+		if err != nil {
+			return nil, &TLSConfigError{File: g.ClientCAFile, Files: files, Err: err}
+		}
+	}
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}, nil
+}
+
+// serve runs the gRPC server on listener until ctx is cancelled. The
+// returned channel is closed once every stream has ended.
+func (g *GrpcIngestor) serve(ctx context.Context, listener net.Listener, opts ...grpc.ServerOption) <-chan auditv1.Event {
+	ch := make(chan auditv1.Event, 500)
+	push := &pushServer{
+		ch:       ch,
+		dedup:    NewEventDeduplicator(g.DeduplicationCacheSize),
+		consumed: newConsumption(g.SourceLabel),
+	}
+	opts = append(opts,
+		grpc.MaxRecvMsgSize(g.MaxMessageBytes),
+		grpc.WaitForHandlers(true),
+		grpc.StreamInterceptor(g.authenticate),
+	)
+	srv := grpc.NewServer(opts...)
+	pushv1.RegisterEventPushServer(srv, push)
+
+	go func() {
+		defer close(ch)
+		stop := context.AfterFunc(ctx, srv.Stop)
+		defer stop()
+
+		grpcLog.Info("starting gRPC push receiver", "address", listener.Addr().String(),
+			"tls", g.TLSCertFile != "", "authentication", g.Authenticator != nil)
+		if err := srv.Serve(listener); err != nil && ctx.Err() == nil {
+			grpcLog.Error(err, "gRPC push receiver stopped")
+		}
+		// Wait for the handlers still sending to ch.
+		srv.Stop()
+	}()
+	return ch
+}
+
+// authenticate rejects streams whose bearer token the Authenticator does
+// not accept.
+func (g *GrpcIngestor) authenticate(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if g.Authenticator == nil {
+		return handler(srv, stream)
+	}
+	ctx := stream.Context()
+	req := &http.Request{Header: http.Header{}}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		req.Header.Add("Authorization", value)
+	}
+	err := g.Authenticator.Authenticate(ctx, req.WithContext(ctx))
+	switch {
+	case err == nil:
+		return handler(srv, stream)
+	case errors.Is(err, ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, "unauthorized")
+	case errors.Is(err, ErrForbidden):
+		return status.Error(codes.PermissionDenied, "forbidden")
+	default:
+		grpcLog.Error(err, "gRPC authentication backend error")
+		return status.Error(codes.Unavailable, "authentication unavailable")
+	}
+}
+
+// Checkpoint returns an empty position (gRPC sources are stateless).
+func (g *GrpcIngestor) Checkpoint() Position {
+	return Position{}
+}
+
+// pushServer implements pushv1.EventPushServer for one source.
+type pushServer struct {
+	pushv1.UnimplementedEventPushServer
+
+	ch       chan<- auditv1.Event
+	dedup    *EventDeduplicator
+	consumed *consumption
+}
+
+// PushEvents implements pushv1.EventPushServer.
+func (s *pushServer) PushEvents(stream grpc.BidiStreamingServer[pushv1.PushEventsRequest, pushv1.PushEventsResponse]) error {
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp := &pushv1.PushEventsResponse{}
+		for _, data := range req.GetEvents() {
+			s.consumed.read(len(data))
+			events, err := parseAuditLine(data)
+			s.consumed.parsed(err == nil)
+			if err != nil {
+				grpcLog.V(1).Info("rejecting undecodable event", "error", err.Error())
+				resp.Rejected++
+				continue
+			}
+			for _, event := range events {
+				if s.dedup.Seen(event) {
+					resp.Duplicates++
+					continue
+				}
+				// Block rather than drop: the collector waits for the
+				// response while the pipeline catches up.
+				select {
+				case s.ch <- event:
+					resp.Accepted++
+				case <-ctx.Done():
+					return status.FromContextError(ctx.Err()).Err()
+				}
+			}
+		}
+		s.consumed.emitted(int(resp.Accepted))
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
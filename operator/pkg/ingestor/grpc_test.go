@@ -0,0 +1,158 @@
+package ingestor
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	pushv1 "github.com/felixnotka/audicia/operator/pkg/ingestor/push/v1"
+)
+
+// startGrpcIngestor serves g over an in-memory listener and returns its
+// events and a client.
+func startGrpcIngestor(t *testing.T, g *GrpcIngestor) (<-chan auditv1.Event, pushv1.EventPushClient) {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	events := g.serve(ctx, listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return events, pushv1.NewEventPushClient(conn)
+}
+
+func encodeEvent(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestGrpcIngestor_PushEvents(t *testing.T) {
+	events, client := startGrpcIngestor(t, NewGrpcIngestor(0))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.PushEvents(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch := &pushv1.PushEventsRequest{Events: [][]byte{
+		encodeEvent(t, auditv1.Event{AuditID: "a-1", Stage: auditv1.StageResponseComplete, Verb: "get"}),
+		encodeEvent(t, auditv1.EventList{TypeMeta: metav1.TypeMeta{Kind: "EventList", APIVersion: "audit.k8s.io/v1"}, Items: []auditv1.Event{
+			{AuditID: "a-2", Stage: auditv1.StageResponseComplete, Verb: "list"},
+			{AuditID: "a-1", Stage: auditv1.StageResponseComplete, Verb: "get"},
+		}}),
+		[]byte("not json"),
+	}}
+	if err := stream.Send(batch); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetAccepted() != 2 || resp.GetDuplicates() != 1 || resp.GetRejected() != 1 {
+		t.Errorf("response = %v, want 2 accepted, 1 duplicate, 1 rejected", resp)
+	}
+	for _, want := range []string{"a-1", "a-2"} {
+		if got := (<-events).AuditID; string(got) != want {
+			t.Errorf("event auditID = %s, want %s", got, want)
+		}
+	}
+
+	// A resent batch is acknowledged without queueing its events again.
+	if err := stream.Send(batch); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetAccepted() != 0 || resp.GetDuplicates() != 3 {
+		t.Errorf("resent batch: response = %v, want 0 accepted, 3 duplicates", resp)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGrpcIngestor_Authentication(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	g := NewGrpcIngestor(0)
+	g.Authenticator = NewStaticTokenAuthenticator(tokenFile)
+	_, client := startGrpcIngestor(t, g)
+
+	push := func(token string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		}
+		stream, err := client.PushEvents(ctx)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&pushv1.PushEventsRequest{}); err != nil {
+			return err
+		}
+		_, err = stream.Recv()
+		return err
+	}
+	for _, token := range []string{"", "wrong"} {
+		if err := push(token); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("token %q: error = %v, want Unauthenticated", token, err)
+		}
+	}
+	if err := push("s3cret"); err != nil {
+		t.Errorf("valid token: %v", err)
+	}
+}
+
+func TestGrpcIngestor_ClientCARequiresTLS(t *testing.T) {
+	g := NewGrpcIngestor(0)
+	g.ClientCAFile = "/etc/audicia/grpc-client-ca/ca.crt"
+	if _, err := g.Start(context.Background()); err == nil {
+		t.Fatal("Start succeeded with a client CA but no TLS certificate")
+	}
+}
+
+func TestGrpcIngestor_ClosesChannelOnCancel(t *testing.T) {
+	g := NewGrpcIngestor(0)
+	listener := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	events := g.serve(ctx, listener)
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Fatal("received an event after cancel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
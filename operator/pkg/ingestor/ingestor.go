@@ -16,6 +16,15 @@ type Ingestor interface {
 	Checkpoint() Position
 }
 
+// ErrorReporter is implemented by ingestors that recover from errors, e.g.
+// by retrying a read, so the pipeline can keep a history of them.
+type ErrorReporter interface {
+	// OnError registers fn to be called with every error the ingestor
+	// recovers from. It must be called before Start; fn may be called from
+	// several goroutines.
+	OnError(fn func(error))
+}
+
 // Position represents a resumable position in the audit stream.
 type Position struct {
 	// FileOffset is the byte offset in the audit log file.
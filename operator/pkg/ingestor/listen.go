@@ -0,0 +1,42 @@
+package ingestor
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// Listener is implemented by ingestors that accept connections, so the
+// pipeline can report where they listen.
+type Listener interface {
+	// ListenAddress returns the address the ingestor listens on as
+	// host:port, or "" before Start.
+	ListenAddress() string
+}
+
+// ListenNetwork returns the network and address to listen on for bindAddress
+// and port. An empty bindAddress listens on all interfaces over "tcp", which
+// is dual-stack where the node has IPv6. IPv4 addresses listen over "tcp4"
+// and IPv6 addresses, optionally bracketed, over "tcp6", so "::" accepts
+// IPv6 connections only.
+func ListenNetwork(bindAddress string, port int32) (network, address string, err error) {
+	portStr := strconv.Itoa(int(port))
+	if bindAddress == "" {
+		return "tcp", ":" + portStr, nil
+	}
+	host := bindAddress
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		host = host[1 : len(host)-1]
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return "", "", fmt.Errorf("bind address %q is not an IP address", bindAddress)
+	}
+	ip = ip.Unmap()
+	if ip.Is4() {
+		return "tcp4", net.JoinHostPort(ip.String(), portStr), nil
+	}
+	return "tcp6", net.JoinHostPort(ip.String(), portStr), nil
+}
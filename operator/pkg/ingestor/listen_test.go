@@ -0,0 +1,114 @@
+package ingestor
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListenNetwork(t *testing.T) {
+	tests := []struct {
+		bind        string
+		wantNetwork string
+		wantAddress string
+	}{
+		{"", "tcp", ":8443"},
+		{"0.0.0.0", "tcp4", "0.0.0.0:8443"},
+		{"10.0.0.5", "tcp4", "10.0.0.5:8443"},
+		{"::", "tcp6", "[::]:8443"},
+		{"[::]", "tcp6", "[::]:8443"},
+		{"fd00::5", "tcp6", "[fd00::5]:8443"},
+		{"fe80::1%eth0", "tcp6", "[fe80::1%eth0]:8443"},
+		{"::ffff:10.0.0.5", "tcp4", "10.0.0.5:8443"},
+	}
+	for _, tt := range tests {
+		network, address, err := ListenNetwork(tt.bind, 8443)
+		if err != nil {
+			t.Errorf("ListenNetwork(%q) err = %v", tt.bind, err)
+			continue
+		}
+		if network != tt.wantNetwork || address != tt.wantAddress {
+			t.Errorf("ListenNetwork(%q) = %s %s, want %s %s", tt.bind, network, address, tt.wantNetwork, tt.wantAddress)
+		}
+	}
+
+	for _, bind := range []string{"localhost", "10.0.0.300", "[::1]:8443", "10.0.0.0/8", "[10.0.0.5"} {
+		if _, _, err := ListenNetwork(bind, 8443); err == nil {
+			t.Errorf("ListenNetwork(%q) accepted an invalid address", bind)
+		}
+	}
+}
+
+// ipv6Available reports whether the host can listen on the IPv6 loopback.
+func ipv6Available() bool {
+	l, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		return false
+	}
+	_ = l.Close()
+	return true
+}
+
+func TestWebhookIngestor_BindAddress(t *testing.T) {
+	certFile, keyFile := writeKeyPair(t, t.TempDir(), "webhook")
+
+	tests := []struct {
+		name, bind string
+		ipv6       bool
+		dial       []string
+	}{
+		{"all interfaces", "", false, []string{"127.0.0.1"}},
+		{"IPv4 loopback", "127.0.0.1", false, []string{"127.0.0.1"}},
+		{"IPv6 loopback", "::1", true, []string{"::1"}},
+		{"dual-stack", "", true, []string{"127.0.0.1", "::1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.ipv6 && !ipv6Available() {
+				t.Skip("IPv6 is not available")
+			}
+			w := NewWebhookIngestor(0, certFile, keyFile)
+			w.BindAddress = tt.bind
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if _, err := w.Start(ctx); err != nil {
+				t.Fatalf("Start() err = %v", err)
+			}
+
+			_, port, err := net.SplitHostPort(w.ListenAddress())
+			if err != nil || port == "0" {
+				t.Fatalf("ListenAddress() = %q, want host:port", w.ListenAddress())
+			}
+			client := &http.Client{
+				Timeout:   5 * time.Second,
+				Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}, //nolint:gosec // self-signed test certificate
+			}
+			for _, host := range tt.dial {
+				resp, err := client.Get("https://" + net.JoinHostPort(host, port) + "/")
+				if err != nil {
+					t.Fatalf("GET via %s: %v", host, err)
+				}
+				_ = resp.Body.Close()
+				if resp.StatusCode != http.StatusMethodNotAllowed {
+					t.Errorf("GET via %s = %d, want %d", host, resp.StatusCode, http.StatusMethodNotAllowed)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookIngestor_BindAddressInvalid(t *testing.T) {
+	certFile, keyFile := writeKeyPair(t, t.TempDir(), "webhook")
+	w := NewWebhookIngestor(0, certFile, keyFile)
+	w.BindAddress = "webhook.local"
+	if _, err := w.Start(context.Background()); err == nil || !strings.Contains(err.Error(), "not an IP address") {
+		t.Errorf("Start() err = %v, want an invalid bind address", err)
+	}
+	if w.ListenAddress() != "" {
+		t.Errorf("ListenAddress() = %q after a failed start", w.ListenAddress())
+	}
+}
@@ -0,0 +1,125 @@
+package ingestor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var localLog = ctrl.Log.WithName("ingestor").WithName("local")
+
+// LocalIngestor receives webhook EventList payloads without TLS, on a UNIX
+// domain socket or a plain-HTTP port on the loopback interface. It exists for
+// kind clusters and e2e tests, where generating certificates for every run
+// is friction; it must not be used to receive audit events over a network.
+type LocalIngestor struct {
+	// SocketPath is the UNIX domain socket to listen on. Takes precedence
+	// over Port.
+	SocketPath string
+
+	// Port is the plain-HTTP port to listen on at 127.0.0.1.
+	Port int32
+
+	// MaxRequestBodyBytes is the maximum request body size.
+	MaxRequestBodyBytes int64
+
+	// RateLimitPerSecond is the maximum requests per second.
+	RateLimitPerSecond int32
+
+	// DeduplicationCacheSize is the size of the auditID and stage LRU cache.
+	DeduplicationCacheSize int
+}
+
+// NewLocalIngestor creates a local ingestor with the webhook's defaults.
+func NewLocalIngestor(socketPath string, port int32) *LocalIngestor {
+	return &LocalIngestor{
+		SocketPath:             socketPath,
+		Port:                   port,
+		MaxRequestBodyBytes:    1048576, // 1MB
+		RateLimitPerSecond:     100,
+		DeduplicationCacheSize: DefaultDeduplicationWindow,
+	}
+}
+
+// Start opens the listener and begins accepting audit events. Listener
+// errors, such as a port already in use, are returned immediately.
+func (l *LocalIngestor) Start(ctx context.Context) (<-chan auditv1.Event, error) {
+	listener, err := l.listen()
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan auditv1.Event, 500)
+	wh := &WebhookIngestor{MaxRequestBodyBytes: l.MaxRequestBodyBytes}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", wh.handleAuditRequest(ch,
+		NewEventDeduplicator(l.DeduplicationCacheSize),
+		newRequestLimiter(int(l.RateLimitPerSecond), 0)))
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+
+	go func() {
+		defer close(ch)
+		errCh := make(chan error, 1)
+		go func() {
+			localLog.Info("starting local audit receiver without TLS", "address", listener.Addr().String())
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				localLog.Error(err, "local receiver error")
+				errCh <- err
+			}
+			close(errCh)
+		}()
+
+		select {
+		case <-ctx.Done():
+		case <-errCh:
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			localLog.Error(err, "error shutting down local receiver")
+		}
+	}()
+
+	return ch, nil
+}
+
+// listen opens the UNIX socket or the loopback TCP listener.
+func (l *LocalIngestor) listen() (net.Listener, error) {
+	if l.SocketPath != "" {
+		if err := os.Remove(l.SocketPath); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("removing stale socket %s: %w", l.SocketPath, err)
+		}
+		listener, err := net.Listen("unix", l.SocketPath)
+		if err != nil {
+			return nil, fmt.Errorf("listening on socket %s: %w", l.SocketPath, err)
+		}
+		return listener, nil
+	}
+	if l.Port <= 0 {
+		return nil, fmt.Errorf("local source requires a socket path or a port")
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", l.Port))
+	if err != nil {
+		return nil, fmt.Errorf("listening on 127.0.0.1:%d: %w", l.Port, err)
+	}
+	return listener, nil
+}
+
+// Checkpoint returns an empty position (local sources are stateless).
+func (l *LocalIngestor) Checkpoint() Position {
+	return Position{}
+}
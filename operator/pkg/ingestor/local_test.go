@@ -0,0 +1,62 @@
+package ingestor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalIngestor_UnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "audit.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := NewLocalIngestor(socket, 0).Start(ctx)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	body := `{"kind":"EventList","apiVersion":"audit.k8s.io/v1","items":[{"auditID":"a1","verb":"get","stage":"ResponseComplete"}]}`
+	resp, err := client.Post("http://local/", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	select {
+	case e := <-events:
+		if e.AuditID != "a1" {
+			t.Errorf("auditID = %q, want a1", e.AuditID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("expected channel to close after cancel")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
+
+func TestLocalIngestor_RequiresListener(t *testing.T) {
+	if _, err := NewLocalIngestor("", 0).Start(context.Background()); err == nil {
+		t.Error("expected error without socket path or port")
+	}
+}
@@ -0,0 +1,243 @@
+package ingestor
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// errMessageTooLarge is returned when a msgpack value exceeds the decoder's
+// byte budget.
+var errMessageTooLarge = errors.New("message exceeds maximum size")
+
+// maxMsgpackDepth bounds the nesting of decoded arrays and maps.
+const maxMsgpackDepth = 64
+
+// msgpackExt is a msgpack extension value, such as a Fluentd EventTime.
+type msgpackExt struct {
+	Type int8
+	Data []byte
+}
+
+// msgpackDecoder decodes msgpack values into Go values: nil, bool, int64,
+// uint64, float64, string, []byte, []any, map[string]any and msgpackExt.
+// It only implements what the Fluentd forward protocol needs. Every value
+// decoded by one decoder counts against budget, which bounds the memory a
+// single message can allocate.
+type msgpackDecoder struct {
+	r      *bufio.Reader
+	budget int64
+}
+
+func newMsgpackDecoder(r *bufio.Reader, maxBytes int64) *msgpackDecoder {
+	return &msgpackDecoder{r: r, budget: maxBytes}
+}
+
+// decode reads the next complete value.
+func (d *msgpackDecoder) decode() (any, error) {
+	return d.value(0)
+}
+
+func (d *msgpackDecoder) value(depth int) (any, error) {
+	if depth > maxMsgpackDepth {
+		return nil, fmt.Errorf("msgpack value nested deeper than %d levels", maxMsgpackDepth)
+	}
+	b, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case b <= 0x7f:
+		return int64(b), nil
+	case b >= 0xe0:
+		return int64(int8(b)), nil
+	case b >= 0x80 && b <= 0x8f:
+		return d.mapValue(int64(b&0x0f), depth)
+	case b >= 0x90 && b <= 0x9f:
+		return d.array(int64(b&0x0f), depth)
+	case b >= 0xa0 && b <= 0xbf:
+		return d.str(int64(b & 0x1f))
+	}
+
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.length(b - 0xc4)
+		if err != nil {
+			return nil, err
+		}
+		return d.bytes(n)
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.length(b - 0xc7)
+		if err != nil {
+			return nil, err
+		}
+		return d.ext(n)
+	case 0xca:
+		v, err := d.uint(4)
+		return float64(math.Float32frombits(uint32(v))), err
+	case 0xcb:
+		v, err := d.uint(8)
+		return math.Float64frombits(v), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return d.uint(1 << (b - 0xcc))
+	case 0xd0:
+		v, err := d.uint(1)
+		return int64(int8(v)), err
+	case 0xd1:
+		v, err := d.uint(2)
+		return int64(int16(v)), err
+	case 0xd2:
+		v, err := d.uint(4)
+		return int64(int32(v)), err
+	case 0xd3:
+		v, err := d.uint(8)
+		return int64(v), err
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.ext(1 << (b - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.length(b - 0xd9)
+		if err != nil {
+			return nil, err
+		}
+		return d.str(n)
+	case 0xdc, 0xdd:
+		n, err := d.length(b - 0xdc + 1)
+		if err != nil {
+			return nil, err
+		}
+		return d.array(n, depth)
+	case 0xde, 0xdf:
+		n, err := d.length(b - 0xde + 1)
+		if err != nil {
+			return nil, err
+		}
+		return d.mapValue(n, depth)
+	}
+	return nil, fmt.Errorf("unsupported msgpack type 0x%02x", b)
+}
+
+// length reads a 1-, 2- or 4-byte big-endian length (size class 0, 1, 2).
+func (d *msgpackDecoder) length(class byte) (int64, error) {
+	v, err := d.uint(1 << class)
+	return int64(v), err
+}
+
+func (d *msgpackDecoder) array(n int64, depth int) ([]any, error) {
+	// Every element takes at least one byte, so n is bounded by the budget.
+	if n > d.budget {
+		return nil, errMessageTooLarge
+	}
+	out := make([]any, 0, n)
+	for range n {
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func (d *msgpackDecoder) mapValue(n int64, depth int) (map[string]any, error) {
+	if 2*n > d.budget {
+		return nil, errMessageTooLarge
+	}
+	out := make(map[string]any, n)
+	for range n {
+		k, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		switch key := k.(type) {
+		case string:
+			out[key] = v
+		case []byte:
+			out[string(key)] = v
+		default:
+			out[fmt.Sprint(key)] = v
+		}
+	}
+	return out, nil
+}
+
+func (d *msgpackDecoder) str(n int64) (string, error) {
+	b, err := d.bytes(n)
+	return string(b), err
+}
+
+func (d *msgpackDecoder) ext(n int64) (msgpackExt, error) {
+	t, err := d.byte()
+	if err != nil {
+		return msgpackExt{}, err
+	}
+	data, err := d.bytes(n)
+	return msgpackExt{Type: int8(t), Data: data}, err
+}
+
+func (d *msgpackDecoder) uint(size int) (uint64, error) {
+	b, err := d.bytes(int64(size))
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+func (d *msgpackDecoder) byte() (byte, error) {
+	if d.budget < 1 {
+		return 0, errMessageTooLarge
+	}
+	d.budget--
+	return d.r.ReadByte()
+}
+
+func (d *msgpackDecoder) bytes(n int64) ([]byte, error) {
+	if n > d.budget {
+		return nil, errMessageTooLarge
+	}
+	d.budget -= n
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+// encodeFluentdAck encodes the {"ack": chunk} response to a forward message
+// that requested an acknowledgement.
+func encodeFluentdAck(chunk string) []byte {
+	out := []byte{0x81, 0xa3, 'a', 'c', 'k'}
+	switch n := len(chunk); {
+	case n < 32:
+		out = append(out, 0xa0|byte(n))
+	case n < 256:
+		out = append(out, 0xd9, byte(n))
+	case n < 65536:
+		out = append(out, 0xda)
+		out = binary.BigEndian.AppendUint16(out, uint16(n))
+	default:
+		out = append(out, 0xdb)
+		out = binary.BigEndian.AppendUint32(out, uint32(n))
+	}
+	return append(out, chunk...)
+}
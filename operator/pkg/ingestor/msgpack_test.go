@@ -0,0 +1,134 @@
+package ingestor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// mpEncode is a minimal msgpack encoder for building test messages.
+func mpEncode(v any) []byte {
+	var b []byte
+	var enc func(v any)
+	length := func(fix, b8, b16, b32 byte, n int) {
+		switch {
+		case fix != 0 && n < 16 && fix != 0xa0:
+			b = append(b, fix|byte(n))
+		case fix == 0xa0 && n < 32:
+			b = append(b, fix|byte(n))
+		case b8 != 0 && n < 256:
+			b = append(b, b8, byte(n))
+		case n < 65536:
+			b = append(b, b16)
+			b = binary.BigEndian.AppendUint16(b, uint16(n))
+		default:
+			b = append(b, b32)
+			b = binary.BigEndian.AppendUint32(b, uint32(n))
+		}
+	}
+	enc = func(v any) {
+		switch t := v.(type) {
+		case nil:
+			b = append(b, 0xc0)
+		case bool:
+			if t {
+				b = append(b, 0xc3)
+			} else {
+				b = append(b, 0xc2)
+			}
+		case int:
+			b = append(b, 0xd3)
+			b = binary.BigEndian.AppendUint64(b, uint64(t))
+		case float64:
+			b = append(b, 0xcb)
+			b = binary.BigEndian.AppendUint64(b, math.Float64bits(t))
+		case string:
+			length(0xa0, 0xd9, 0xda, 0xdb, len(t))
+			b = append(b, t...)
+		case []byte:
+			length(0, 0xc4, 0xc5, 0xc6, len(t))
+			b = append(b, t...)
+		case msgpackExt:
+			b = append(b, 0xc7, byte(len(t.Data)), byte(t.Type))
+			b = append(b, t.Data...)
+		case []any:
+			length(0x90, 0, 0xdc, 0xdd, len(t))
+			for _, e := range t {
+				enc(e)
+			}
+		case map[string]any:
+			keys := make([]string, 0, len(t))
+			for k := range t {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			length(0x80, 0, 0xde, 0xdf, len(t))
+			for _, k := range keys {
+				enc(k)
+				enc(t[k])
+			}
+		default:
+			panic("mpEncode: unsupported type")
+		}
+	}
+	enc(v)
+	return b
+}
+
+func TestMsgpackDecoder(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+		want any
+	}{
+		{"positive fixint", []byte{0x05}, int64(5)},
+		{"negative fixint", []byte{0xff}, int64(-1)},
+		{"uint16", []byte{0xcd, 0x01, 0x00}, uint64(256)},
+		{"int8", []byte{0xd0, 0x80}, int64(-128)},
+		{"float32", []byte{0xca, 0x3f, 0xc0, 0x00, 0x00}, float64(1.5)},
+		{"fixext8 event time", []byte{0xd7, 0x00, 0, 0, 0, 1, 0, 0, 0, 2}, msgpackExt{Type: 0, Data: []byte{0, 0, 0, 1, 0, 0, 0, 2}}},
+		{"nested", mpEncode([]any{"tag", map[string]any{"k": []byte("v"), "n": nil, "b": true}}),
+			[]any{"tag", map[string]any{"k": []byte("v"), "n": nil, "b": true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newMsgpackDecoder(bufio.NewReader(bytes.NewReader(tt.in)), 1024).decode()
+			if err != nil {
+				t.Fatalf("decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decode() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMsgpackDecoder_Limits(t *testing.T) {
+	// A str32 header claiming 1GB must fail before allocating.
+	huge := []byte{0xdb, 0x40, 0x00, 0x00, 0x00}
+	if _, err := newMsgpackDecoder(bufio.NewReader(bytes.NewReader(huge)), 1024).decode(); !errors.Is(err, errMessageTooLarge) {
+		t.Errorf("expected errMessageTooLarge, got %v", err)
+	}
+
+	deep := bytes.Repeat([]byte{0x91}, maxMsgpackDepth+2)
+	if _, err := newMsgpackDecoder(bufio.NewReader(bytes.NewReader(deep)), 1024).decode(); err == nil {
+		t.Error("expected error for excessive nesting")
+	}
+}
+
+func TestEncodeFluentdAck(t *testing.T) {
+	for _, chunk := range []string{"abc", string(bytes.Repeat([]byte("x"), 40))} {
+		got, err := newMsgpackDecoder(bufio.NewReader(bytes.NewReader(encodeFluentdAck(chunk))), 1024).decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m, ok := got.(map[string]any); !ok || m["ack"] != chunk {
+			t.Errorf("ack = %#v, want chunk %q", got, chunk)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package ingestor
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// globRescanInterval is how often a MultiFileIngestor re-evaluates its glob.
+const globRescanInterval = 10 * time.Second
+
+// IsGlobPattern reports whether path contains glob metacharacters and should
+// be read with a MultiFileIngestor.
+func IsGlobPattern(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// MultiFileIngestor tails every file matching a glob concurrently, one
+// FileIngestor per file. HA control planes that write one audit log per
+// apiserver can then be read by a single source. Files that appear later are
+// picked up on the next rescan; files that stop matching are dropped.
+//
+// A file that appears under a new name but carries an inode already read
+// (a rotated copy that also matches the glob) resumes where that inode was
+// left, so it is not read twice.
+type MultiFileIngestor struct {
+	// Pattern is the glob selecting the audit log files.
+	Pattern string
+
+	// StartPositions are the per-file positions to resume from, keyed by path.
+	StartPositions map[string]Position
+
+	// BatchSize is the channel buffer size, shared by all files.
+	BatchSize int
+
+	// RotatedFilePattern is passed to each file's ingestor. Relative patterns
+	// resolve against the directory of the matched file.
+	RotatedFilePattern string
+
+	// SourceLabel is passed to each file's ingestor.
+	SourceLabel string
+
+	rescanInterval time.Duration
+	probeInterval  time.Duration
+	reprobe        chan struct{}
+
+	mu    sync.Mutex
+	files map[string]*FileIngestor
+	// inodes holds the last position reached in every inode read so far.
+	inodes map[uint64]Position
+
+	onError func(error)
+	onProbe func(error)
+}
+
+// NewMultiFileIngestor creates an ingestor for all files matching pattern.
+func NewMultiFileIngestor(pattern string, startPositions map[string]Position, batchSize int) *MultiFileIngestor {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	return &MultiFileIngestor{
+		Pattern:        pattern,
+		StartPositions: startPositions,
+		BatchSize:      batchSize,
+		rescanInterval: globRescanInterval,
+		probeInterval:  probeInterval,
+		reprobe:        make(chan struct{}, 1),
+		files:          make(map[string]*FileIngestor),
+		inodes:         make(map[uint64]Position),
+	}
+}
+
+// Start validates the pattern and begins tailing all matching files.
+func (m *MultiFileIngestor) Start(ctx context.Context) (<-chan auditv1.Event, error) {
+	if _, err := filepath.Match(m.Pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid location pattern %q: %w", m.Pattern, err)
+	}
+
+	m.mu.Lock()
+	for _, pos := range m.StartPositions {
+		if pos.Inode != 0 {
+			m.inodes[pos.Inode] = pos
+		}
+	}
+	m.mu.Unlock()
+
+	ch := make(chan auditv1.Event, m.BatchSize)
+	go func() {
+		var wg sync.WaitGroup
+		defer func() {
+			wg.Wait()
+			close(ch)
+		}()
+		cancels := make(map[string]context.CancelFunc)
+		defer func() {
+			for _, cancel := range cancels {
+				cancel()
+			}
+		}()
+		if m.probeInterval > 0 {
+			go runProbe(ctx, m.probeInterval, m.reprobe, m.SourceLabel, func() error { return probeGlob(m.Pattern) }, m.onProbe)
+		}
+
+		ticker := time.NewTicker(m.rescanInterval)
+		defer ticker.Stop()
+		for {
+			m.rescan(ctx, ch, &wg, cancels)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// rescan starts an ingestor for each new match and stops those whose file no
+// longer matches.
+func (m *MultiFileIngestor) rescan(ctx context.Context, ch chan<- auditv1.Event, wg *sync.WaitGroup, cancels map[string]context.CancelFunc) {
+	matches, err := filepath.Glob(m.Pattern)
+	if err != nil {
+		fileLog.Error(err, "error matching audit log pattern", "pattern", m.Pattern)
+		if m.onError != nil {
+			m.onError(fmt.Errorf("matching %s: %w", m.Pattern, err))
+		}
+		return
+	}
+	matched := make(map[string]bool, len(matches))
+	for _, path := range matches {
+		matched[path] = true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for path, cancel := range cancels {
+		if matched[path] {
+			continue
+		}
+		fileLog.Info("audit log no longer matches pattern, stopping", "path", path)
+		cancel()
+		delete(cancels, path)
+		delete(m.files, path)
+	}
+
+	for _, path := range matches {
+		if _, ok := m.files[path]; ok {
+			continue
+		}
+		fi := NewFileIngestor(path, m.startPosition(path), m.BatchSize)
+		fi.RotatedFilePattern = m.RotatedFilePattern
+		fi.observe = m.observe
+		fi.onError = m.onError
+		fi.SourceLabel = m.SourceLabel
+		fi.probeInterval = 0 // the pattern is probed as a whole
+		fi.reprobe = m.reprobe
+		fileCtx, cancel := context.WithCancel(ctx)
+		events, _ := fi.Start(fileCtx)
+		m.files[path] = fi
+		cancels[path] = cancel
+		fileLog.Info("tailing audit log", "path", path, "offset", fi.StartPosition.FileOffset)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for event := range events {
+				select {
+				case ch <- event:
+				case <-fileCtx.Done():
+					// Drain so the file ingestor can exit.
+					for range events {
+					}
+					return
+				}
+			}
+		}()
+	}
+}
+
+// OnError implements ErrorReporter for all matched files.
+func (m *MultiFileIngestor) OnError(fn func(error)) {
+	m.onError = fn
+}
+
+// OnProbe implements Prober. The probe fails while the pattern matches no
+// file or any match is unreadable.
+func (m *MultiFileIngestor) OnProbe(fn func(error)) {
+	m.onProbe = fn
+}
+
+// observe records the position reached in an inode.
+func (m *MultiFileIngestor) observe(pos Position) {
+	if pos.Inode == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inodes[pos.Inode] = pos
+}
+
+// startPosition returns the position to resume path from: its own checkpoint,
+// or the last position in its inode if another path already read it. Anything
+// else is new and read from the beginning. The caller must hold m.mu.
+func (m *MultiFileIngestor) startPosition(path string) Position {
+	if pos, ok := m.StartPositions[path]; ok {
+		return pos
+	}
+	inode, err := fileInodeByPath(path)
+	if err != nil || inode == 0 {
+		return Position{}
+	}
+	return m.inodes[inode]
+}
+
+// FileCheckpoints returns the current position of every tailed file, keyed by
+// path.
+func (m *MultiFileIngestor) FileCheckpoints() map[string]Position {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]Position, len(m.files))
+	for path, fi := range m.files {
+		out[path] = fi.Checkpoint()
+	}
+	return out
+}
+
+// Checkpoint returns the latest LastTimestamp across all files. Per-file
+// offsets are available from FileCheckpoints.
+func (m *MultiFileIngestor) Checkpoint() Position {
+	var latest Position
+	for _, pos := range m.FileCheckpoints() {
+		if pos.LastTimestamp > latest.LastTimestamp {
+			latest.LastTimestamp = pos.LastTimestamp
+		}
+	}
+	return latest
+}
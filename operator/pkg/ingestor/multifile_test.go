@@ -0,0 +1,169 @@
+package ingestor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// receiveIDs reads n events from ch, failing the test on timeout.
+func receiveIDs(t *testing.T, ch <-chan auditv1.Event, n int) map[string]bool {
+	t.Helper()
+	ids := make(map[string]bool, n)
+	deadline := time.After(5 * time.Second)
+	for len(ids) < n {
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				t.Fatalf("channel closed after %d events", len(ids))
+			}
+			ids[string(e.AuditID)] = true
+		case <-deadline:
+			t.Fatalf("timed out after %d of %d events", len(ids), n)
+		}
+	}
+	return ids
+}
+
+func TestIsGlobPattern(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/var/log/kubernetes/audit/audit.log": false,
+		"/var/log/kubernetes/audit/*.log":     true,
+		"/var/log/audit-?.log":                true,
+		"/var/log/audit-[abc].log":            true,
+	} {
+		if got := IsGlobPattern(path); got != want {
+			t.Errorf("IsGlobPattern(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestMultiFileIngestor_TailsAllMatches(t *testing.T) {
+	dir := t.TempDir()
+	writeAuditFile(t, filepath.Join(dir, "apiserver-1.log"), []string{validAuditJSON("a1", "get", "pods", "default")})
+	writeAuditFile(t, filepath.Join(dir, "apiserver-2.log"), []string{
+		validAuditJSON("b1", "get", "pods", "default"),
+		validAuditJSON("b2", "list", "pods", "default"),
+	})
+	writeAuditFile(t, filepath.Join(dir, "other.txt"), []string{validAuditJSON("x1", "get", "pods", "default")})
+
+	ing := NewMultiFileIngestor(filepath.Join(dir, "*.log"), nil, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := ing.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := receiveIDs(t, ch, 3)
+	for _, id := range []string{"a1", "b1", "b2"} {
+		if !ids[id] {
+			t.Errorf("missing event %s, got %v", id, ids)
+		}
+	}
+
+	// Offsets are tracked per file once the backlog is read.
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		cps := ing.FileCheckpoints()
+		if len(cps) == 2 && cps[filepath.Join(dir, "apiserver-1.log")].FileOffset > 0 &&
+			cps[filepath.Join(dir, "apiserver-2.log")].FileOffset > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected offsets for both files, got %+v", cps)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if ing.Checkpoint().LastTimestamp == "" {
+		t.Error("expected LastTimestamp in aggregate checkpoint")
+	}
+
+	cancel()
+	for range ch {
+	}
+}
+
+func TestMultiFileIngestor_ResumesFromCheckpoints(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "apiserver-1.log")
+	writeAuditFile(t, path, []string{validAuditJSON("a1", "get", "pods", "default")})
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inode, _ := fileInodeByPath(path)
+
+	ing := NewMultiFileIngestor(filepath.Join(dir, "*.log"),
+		map[string]Position{path: {FileOffset: info.Size(), Inode: inode}}, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := ing.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(validAuditJSON("a2", "list", "pods", "default") + "\n")
+	_ = f.Close()
+
+	if ids := receiveIDs(t, ch, 1); !ids["a2"] {
+		t.Errorf("expected only the appended event, got %v", ids)
+	}
+	cancel()
+	for range ch {
+	}
+}
+
+func TestMultiFileIngestor_RenamedFileNotReread(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("inode detection only works on Linux")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+	writeAuditFile(t, path, []string{validAuditJSON("a1", "get", "pods", "default")})
+
+	ing := NewMultiFileIngestor(filepath.Join(dir, "audit*.log"), nil, 100)
+	ing.rescanInterval = 100 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := ing.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiveIDs(t, ch, 1)
+	time.Sleep(200 * time.Millisecond)
+
+	// Rotate: the old file is renamed to a name the glob also matches.
+	if err := os.Rename(path, filepath.Join(dir, "audit-2025-06-01.log")); err != nil {
+		t.Fatal(err)
+	}
+	writeAuditFile(t, path, []string{validAuditJSON("a2", "get", "pods", "default")})
+
+	ids := receiveIDs(t, ch, 1)
+	if !ids["a2"] {
+		t.Errorf("expected the new file's event, got %v", ids)
+	}
+	select {
+	case e := <-ch:
+		t.Errorf("renamed file was re-read: got %s", e.AuditID)
+	case <-time.After(1500 * time.Millisecond):
+	}
+	cancel()
+	for range ch {
+	}
+}
+
+func TestMultiFileIngestor_InvalidPattern(t *testing.T) {
+	if _, err := NewMultiFileIngestor("/var/log/[", nil, 0).Start(context.Background()); err == nil {
+		t.Error("expected error for malformed pattern")
+	}
+}
@@ -0,0 +1,91 @@
+package ingestor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+)
+
+// probeInterval is how often file ingestors check that their audit log is
+// readable.
+const probeInterval = 30 * time.Second
+
+// Prober is implemented by ingestors that periodically check whether their
+// source can be read, independently of reading it.
+type Prober interface {
+	// OnProbe registers fn to be called with the result of every probe: nil
+	// when the source is readable, else the reason it is not. It must be
+	// called before Start.
+	OnProbe(fn func(error))
+}
+
+// probePath checks that path exists, is not a directory and can be opened
+// for reading.
+func probePath(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	return nil
+}
+
+// probeGlob checks that pattern matches at least one file and that every
+// match passes probePath.
+func probeGlob(pattern string) error {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no file matches %s: %w", pattern, os.ErrNotExist)
+	}
+	for _, path := range matches {
+		if err := probePath(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runProbe calls probe immediately, then every interval and whenever reprobe
+// receives, until ctx is done. It publishes the result in
+// audicia_source_unreadable for source and passes it to fn when set. Without
+// either, there is nothing to probe for.
+func runProbe(ctx context.Context, interval time.Duration, reprobe <-chan struct{}, source string, probe func() error, fn func(error)) {
+	if source == "" && fn == nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := probe()
+		if source != "" {
+			unreadable := 0.0
+			if err != nil {
+				unreadable = 1
+			}
+			metrics.SourceUnreadable.WithLabelValues(source).Set(unreadable)
+		}
+		if fn != nil {
+			fn(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-reprobe:
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package ingestor
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+)
+
+func TestProbePath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+
+	if err := probePath(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing file: err = %v, want ErrNotExist", err)
+	}
+	if err := probePath(dir); err == nil {
+		t.Error("directory: want an error")
+	}
+	writeAuditFile(t, path, nil)
+	if err := probePath(path); err != nil {
+		t.Errorf("readable file: err = %v", err)
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can read files without read permission")
+	}
+	if err := os.Chmod(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := probePath(path); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("unreadable file: err = %v, want ErrPermission", err)
+	}
+}
+
+func TestProbeGlob(t *testing.T) {
+	dir := t.TempDir()
+	pattern := filepath.Join(dir, "audit-*.log")
+
+	if err := probeGlob(pattern); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("no match: err = %v, want ErrNotExist", err)
+	}
+	writeAuditFile(t, filepath.Join(dir, "audit-1.log"), nil)
+	if err := probeGlob(pattern); err != nil {
+		t.Errorf("readable match: err = %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "audit-2.log"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := probeGlob(pattern); err == nil {
+		t.Error("directory match: want an error")
+	}
+}
+
+func TestFileIngestor_Probe(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	ing := NewFileIngestor(path, Position{}, 100)
+	ing.SourceLabel = "default/probe"
+	ing.probeInterval = 10 * time.Millisecond
+	results := make(chan error, 100)
+	ing.OnProbe(func(err error) {
+		select {
+		case results <- err:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ch, err := ing.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-results; !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("first probe: err = %v, want ErrNotExist", err)
+	}
+	if got := testutil.ToFloat64(metrics.SourceUnreadable.WithLabelValues("default/probe")); got != 1 {
+		t.Errorf("audicia_source_unreadable = %v while missing, want 1", got)
+	}
+
+	writeAuditFile(t, path, nil)
+	for err := range results {
+		if err == nil {
+			break
+		}
+	}
+	if got := testutil.ToFloat64(metrics.SourceUnreadable.WithLabelValues("default/probe")); got != 0 {
+		t.Errorf("audicia_source_unreadable = %v once readable, want 0", got)
+	}
+
+	cancel()
+	for range ch {
+	}
+}
@@ -0,0 +1,202 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11-devel
+// 	protoc        (unknown)
+// source: pkg/ingestor/push/v1/push.proto
+
+package pushv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PushEventsRequest is a batch of audit events.
+type PushEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Events, each the JSON encoding of an audit.k8s.io/v1 Event as written to
+	// the audit log, or of an EventList as sent by the API server's webhook
+	// backend.
+	Events        [][]byte `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushEventsRequest) Reset() {
+	*x = PushEventsRequest{}
+	mi := &file_pkg_ingestor_push_v1_push_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushEventsRequest) ProtoMessage() {}
+
+func (x *PushEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ingestor_push_v1_push_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushEventsRequest.ProtoReflect.Descriptor instead.
+func (*PushEventsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_ingestor_push_v1_push_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PushEventsRequest) GetEvents() [][]byte {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// PushEventsResponse acknowledges one PushEventsRequest.
+type PushEventsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of events queued for the pipeline.
+	Accepted int64 `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	// Number of events dropped as redeliveries of queued events.
+	Duplicates int64 `protobuf:"varint,2,opt,name=duplicates,proto3" json:"duplicates,omitempty"`
+	// Number of entries that could not be decoded as audit events.
+	Rejected      int64 `protobuf:"varint,3,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushEventsResponse) Reset() {
+	*x = PushEventsResponse{}
+	mi := &file_pkg_ingestor_push_v1_push_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushEventsResponse) ProtoMessage() {}
+
+func (x *PushEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_ingestor_push_v1_push_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushEventsResponse.ProtoReflect.Descriptor instead.
+func (*PushEventsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_ingestor_push_v1_push_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PushEventsResponse) GetAccepted() int64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *PushEventsResponse) GetDuplicates() int64 {
+	if x != nil {
+		return x.Duplicates
+	}
+	return 0
+}
+
+func (x *PushEventsResponse) GetRejected() int64 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+var File_pkg_ingestor_push_v1_push_proto protoreflect.FileDescriptor
+
+const file_pkg_ingestor_push_v1_push_proto_rawDesc = "" +
+	"\n" +
+	"\x1fpkg/ingestor/push/v1/push.proto\x12\x0faudicia.push.v1\"+\n" +
+	"\x11PushEventsRequest\x12\x16\n" +
+	"\x06events\x18\x01 \x03(\fR\x06events\"l\n" +
+	"\x12PushEventsResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\x03R\baccepted\x12\x1e\n" +
+	"\n" +
+	"duplicates\x18\x02 \x01(\x03R\n" +
+	"duplicates\x12\x1a\n" +
+	"\brejected\x18\x03 \x01(\x03R\brejected2f\n" +
+	"\tEventPush\x12Y\n" +
+	"\n" +
+	"PushEvents\x12\".audicia.push.v1.PushEventsRequest\x1a#.audicia.push.v1.PushEventsResponse(\x010\x01BDZBgithub.com/felixnotka/audicia/operator/pkg/ingestor/push/v1;pushv1b\x06proto3"
+
+var (
+	file_pkg_ingestor_push_v1_push_proto_rawDescOnce sync.Once
+	file_pkg_ingestor_push_v1_push_proto_rawDescData []byte
+)
+
+func file_pkg_ingestor_push_v1_push_proto_rawDescGZIP() []byte {
+	file_pkg_ingestor_push_v1_push_proto_rawDescOnce.Do(func() {
+		file_pkg_ingestor_push_v1_push_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_ingestor_push_v1_push_proto_rawDesc), len(file_pkg_ingestor_push_v1_push_proto_rawDesc)))
+	})
+	return file_pkg_ingestor_push_v1_push_proto_rawDescData
+}
+
+var file_pkg_ingestor_push_v1_push_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_pkg_ingestor_push_v1_push_proto_goTypes = []any{
+	(*PushEventsRequest)(nil),  // 0: audicia.push.v1.PushEventsRequest
+	(*PushEventsResponse)(nil), // 1: audicia.push.v1.PushEventsResponse
+}
+var file_pkg_ingestor_push_v1_push_proto_depIdxs = []int32{
+	0, // 0: audicia.push.v1.EventPush.PushEvents:input_type -> audicia.push.v1.PushEventsRequest
+	1, // 1: audicia.push.v1.EventPush.PushEvents:output_type -> audicia.push.v1.PushEventsResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_pkg_ingestor_push_v1_push_proto_init() }
+func file_pkg_ingestor_push_v1_push_proto_init() {
+	if File_pkg_ingestor_push_v1_push_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_ingestor_push_v1_push_proto_rawDesc), len(file_pkg_ingestor_push_v1_push_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_ingestor_push_v1_push_proto_goTypes,
+		DependencyIndexes: file_pkg_ingestor_push_v1_push_proto_depIdxs,
+		MessageInfos:      file_pkg_ingestor_push_v1_push_proto_msgTypes,
+	}.Build()
+	File_pkg_ingestor_push_v1_push_proto = out.File
+	file_pkg_ingestor_push_v1_push_proto_goTypes = nil
+	file_pkg_ingestor_push_v1_push_proto_depIdxs = nil
+}
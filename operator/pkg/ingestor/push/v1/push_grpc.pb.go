@@ -0,0 +1,129 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: pkg/ingestor/push/v1/push.proto
+
+package pushv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	EventPush_PushEvents_FullMethodName = "/audicia.push.v1.EventPush/PushEvents"
+)
+
+// EventPushClient is the client API for EventPush service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// EventPush accepts audit events from external collectors for an
+// AudiciaSource of type Grpc.
+type EventPushClient interface {
+	// PushEvents receives batches of audit events until the client closes its
+	// side of the stream. Each request is answered once its events are queued
+	// for the pipeline, in order, so a collector can resend the batches it has
+	// no response for. Redelivered events are dropped by auditID and stage.
+	PushEvents(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PushEventsRequest, PushEventsResponse], error)
+}
+
+type eventPushClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEventPushClient(cc grpc.ClientConnInterface) EventPushClient {
+	return &eventPushClient{cc}
+}
+
+func (c *eventPushClient) PushEvents(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PushEventsRequest, PushEventsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EventPush_ServiceDesc.Streams[0], EventPush_PushEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PushEventsRequest, PushEventsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventPush_PushEventsClient = grpc.BidiStreamingClient[PushEventsRequest, PushEventsResponse]
+
+// EventPushServer is the server API for EventPush service.
+// All implementations must embed UnimplementedEventPushServer
+// for forward compatibility.
+//
+// EventPush accepts audit events from external collectors for an
+// AudiciaSource of type Grpc.
+type EventPushServer interface {
+	// PushEvents receives batches of audit events until the client closes its
+	// side of the stream. Each request is answered once its events are queued
+	// for the pipeline, in order, so a collector can resend the batches it has
+	// no response for. Redelivered events are dropped by auditID and stage.
+	PushEvents(grpc.BidiStreamingServer[PushEventsRequest, PushEventsResponse]) error
+	mustEmbedUnimplementedEventPushServer()
+}
+
+// UnimplementedEventPushServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEventPushServer struct{}
+
+func (UnimplementedEventPushServer) PushEvents(grpc.BidiStreamingServer[PushEventsRequest, PushEventsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method PushEvents not implemented")
+}
+func (UnimplementedEventPushServer) mustEmbedUnimplementedEventPushServer() {}
+func (UnimplementedEventPushServer) testEmbeddedByValue()                   {}
+
+// UnsafeEventPushServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EventPushServer will
+// result in compilation errors.
+type UnsafeEventPushServer interface {
+	mustEmbedUnimplementedEventPushServer()
+}
+
+func RegisterEventPushServer(s grpc.ServiceRegistrar, srv EventPushServer) {
+	// If the following call pancis, it indicates UnimplementedEventPushServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&EventPush_ServiceDesc, srv)
+}
+
+func _EventPush_PushEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EventPushServer).PushEvents(&grpc.GenericServerStream[PushEventsRequest, PushEventsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventPush_PushEventsServer = grpc.BidiStreamingServer[PushEventsRequest, PushEventsResponse]
+
+// EventPush_ServiceDesc is the grpc.ServiceDesc for EventPush service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EventPush_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "audicia.push.v1.EventPush",
+	HandlerType: (*EventPushServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PushEvents",
+			Handler:       _EventPush_PushEvents_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pkg/ingestor/push/v1/push.proto",
+}
@@ -0,0 +1,98 @@
+package ingestor
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// catchUpRotated emits the events written to the previous audit log after
+// the checkpoint in pos. The rotated file is the one matching
+// RotatedFilePattern with the checkpointed inode; if it was compressed (and
+// so has a new inode), the most recently modified ".gz" match is used. Gzip
+// preserves the byte stream, so the checkpoint offset applies unchanged to
+// the decompressed content.
+func (f *FileIngestor) catchUpRotated(ctx context.Context, pos Position, ch chan<- auditv1.Event) error {
+	if f.RotatedFilePattern == "" || pos.Inode == 0 {
+		return nil
+	}
+	path, err := f.findRotated(pos.Inode)
+	if err != nil || path == "" {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil {
+			fileLog.V(1).Info("error closing rotated audit log", "error", cerr)
+		}
+	}()
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", path, err)
+		}
+		defer func() { _ = gz.Close() }()
+		r = gz
+	}
+	if pos.FileOffset > 0 {
+		if _, err := io.CopyN(io.Discard, r, pos.FileOffset); err != nil {
+			if err == io.EOF {
+				// Shorter than the checkpoint: not the file we were reading.
+				return nil
+			}
+			return fmt.Errorf("skipping to offset %d in %s: %w", pos.FileOffset, path, err)
+		}
+	}
+
+	fileLog.Info("catching up on rotated audit log", "path", path, "offset", pos.FileOffset)
+	_, err = scanAndEmit(ctx, newAuditScanner(r), ch, f.consumed)
+	return err
+}
+
+// findRotated returns the rotated file holding the given inode, falling back
+// to the newest compressed match. It returns "" when nothing matches.
+func (f *FileIngestor) findRotated(inode uint64) (string, error) {
+	pattern := f.RotatedFilePattern
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(filepath.Dir(f.Path), pattern)
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", fmt.Errorf("rotated file pattern %q: %w", f.RotatedFilePattern, err)
+	}
+
+	var newest string
+	var newestMod time.Time
+	for _, m := range matches {
+		if m == f.Path {
+			continue
+		}
+		if !strings.HasSuffix(m, ".gz") {
+			if ino, err := fileInodeByPath(m); err == nil && ino == inode {
+				return m, nil
+			}
+			continue
+		}
+		info, err := os.Stat(m)
+		if err != nil {
+			continue
+		}
+		if newest == "" || info.ModTime().After(newestMod) {
+			newest, newestMod = m, info.ModTime()
+		}
+	}
+	return newest, nil
+}
@@ -0,0 +1,139 @@
+package ingestor
+
+import (
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+func receiveAuditIDs(t *testing.T, ch <-chan auditv1.Event, n int) []string {
+	t.Helper()
+	var ids []string
+	for len(ids) < n {
+		select {
+		case e := <-ch:
+			ids = append(ids, string(e.AuditID))
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout after %v, want %d events", ids, n)
+		}
+	}
+	return ids
+}
+
+func TestFileIngestor_CatchUpRotated(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("inode detection only works on Linux")
+	}
+
+	first := validAuditJSON("a1", "get", "pods", "default") + "\n"
+	rotated := first + validAuditJSON("a2", "list", "pods", "default") + "\n"
+
+	tests := []struct {
+		name     string
+		compress bool
+		pattern  string
+	}{
+		{name: "renamed", pattern: "audit.log.*"},
+		{name: "compressed", compress: true, pattern: "audit.log.*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "audit.log")
+			if err := os.WriteFile(path, []byte(rotated), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			inode, err := fileInodeByPath(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Rotate after a1 was checkpointed but before a2 was read. The new
+			// file is created before the old one goes away so that its inode
+			// cannot be reused.
+			next := path + ".next"
+			writeAuditFile(t, next, []string{validAuditJSON("b1", "create", "configmaps", "default")})
+			if tt.compress {
+				writeGzip(t, path+".1.gz", rotated)
+			} else if err := os.Rename(path, path+".1"); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Rename(next, path); err != nil {
+				t.Fatal(err)
+			}
+
+			ing := NewFileIngestor(path, Position{FileOffset: int64(len(first)), Inode: inode}, 100)
+			ing.RotatedFilePattern = tt.pattern
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ch, err := ing.Start(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ids := receiveAuditIDs(t, ch, 2)
+			if ids[0] != "a2" || ids[1] != "b1" {
+				t.Errorf("got %v, want [a2 b1]", ids)
+			}
+		})
+	}
+}
+
+func TestFileIngestor_PollDrainsRotatedFile(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("inode detection only works on Linux")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+	writeAuditFile(t, path, []string{validAuditJSON("a1", "get", "pods", "default")})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := NewFileIngestor(path, Position{}, 100).Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiveAuditIDs(t, ch, 1)
+
+	// Append and rotate within one poll interval.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(validAuditJSON("a2", "list", "pods", "default") + "\n")
+	_ = f.Close()
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	writeAuditFile(t, path, []string{validAuditJSON("b1", "create", "configmaps", "default")})
+
+	ids := receiveAuditIDs(t, ch, 2)
+	if ids[0] != "a2" || ids[1] != "b1" {
+		t.Errorf("got %v, want [a2 b1]", ids)
+	}
+}
+
+func writeGzip(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close() //nolint:errcheck // test helper
+	gz := gzip.NewWriter(f)
+	if _, err := gz.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,164 @@
+package ingestor
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// utf8BOM may prefix the MSG part of an RFC 5424 message.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// serveSyslog handles RFC 5424 syslog over TCP. Each message body is one
+// audit log line; messages that do not carry an audit event are skipped.
+func (f *ForwardIngestor) serveSyslog(conn net.Conn, emit func([]auditv1.Event) bool) error {
+	r := bufio.NewReader(conn)
+	for {
+		frame, err := readSyslogFrame(r, f.MaxMessageBytes)
+		if err != nil {
+			return err
+		}
+		if len(frame) == 0 {
+			continue
+		}
+		msg, err := syslogMessageBody(frame)
+		if err != nil {
+			forwardLog.V(1).Info("skipping malformed syslog message", "error", err.Error())
+			continue
+		}
+		events, err := parseAuditLine(msg)
+		if err != nil {
+			forwardLog.V(1).Info("skipping syslog message without an audit event", "error", err.Error())
+			continue
+		}
+		if !emit(events) {
+			return nil
+		}
+	}
+}
+
+// readSyslogFrame reads one frame using either RFC 6587 framing: octet
+// counting ("<length> <message>") or newline-terminated messages. The
+// framing is detected per frame, as senders may not announce it.
+func readSyslogFrame(r *bufio.Reader, maxBytes int64) ([]byte, error) {
+	first, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if first[0] >= '1' && first[0] <= '9' {
+		digits, err := r.ReadSlice(' ')
+		if err != nil {
+			return nil, fmt.Errorf("reading syslog frame length: %w", err)
+		}
+		n, err := strconv.ParseInt(string(digits[:len(digits)-1]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid syslog frame length %q", digits[:len(digits)-1])
+		}
+		if n > maxBytes {
+			return nil, errMessageTooLarge
+		}
+		frame := make([]byte, n)
+		if _, err := io.ReadFull(r, frame); err != nil {
+			return nil, err
+		}
+		return frame, nil
+	}
+
+	var frame []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if int64(len(frame)+len(chunk)) > maxBytes+1 {
+			return nil, errMessageTooLarge
+		}
+		frame = append(frame, chunk...)
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if err != nil && (len(frame) == 0 || !errors.Is(err, io.EOF)) {
+			return nil, err
+		}
+		return bytes.TrimRight(frame, "\r\n"), nil
+	}
+}
+
+// syslogMessageBody returns the MSG part of an RFC 5424 message:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA [MSG]
+//
+// BSD-style (RFC 3164) messages have no reliable field layout; for them the
+// body is taken to start at the first '{', which suits JSON audit lines.
+func syslogMessageBody(frame []byte) ([]byte, error) {
+	if len(frame) == 0 || frame[0] != '<' {
+		return nil, fmt.Errorf("syslog message does not start with a priority")
+	}
+	end := bytes.IndexByte(frame, '>')
+	if end < 2 || end > 4 {
+		return nil, fmt.Errorf("invalid syslog priority")
+	}
+	rest := frame[end+1:]
+
+	if !bytes.HasPrefix(rest, []byte("1 ")) {
+		i := bytes.IndexByte(rest, '{')
+		if i < 0 {
+			return nil, fmt.Errorf("syslog message has no JSON body")
+		}
+		return rest[i:], nil
+	}
+
+	// Skip VERSION and the five header fields.
+	rest = rest[2:]
+	for range 5 {
+		i := bytes.IndexByte(rest, ' ')
+		if i < 0 {
+			return nil, fmt.Errorf("truncated syslog header")
+		}
+		rest = rest[i+1:]
+	}
+
+	rest, err := skipStructuredData(rest)
+	if err != nil {
+		return nil, err
+	}
+	rest = bytes.TrimPrefix(rest, []byte(" "))
+	return bytes.TrimPrefix(rest, utf8BOM), nil
+}
+
+// skipStructuredData skips the STRUCTURED-DATA field: "-" or one or more
+// "[id param="value" ...]" elements, where values may escape '"', '\' and ']'.
+func skipStructuredData(s []byte) ([]byte, error) {
+	if len(s) > 0 && s[0] == '-' {
+		return s[1:], nil
+	}
+	if len(s) == 0 || s[0] != '[' {
+		return nil, fmt.Errorf("invalid syslog structured data")
+	}
+	for len(s) > 0 && s[0] == '[' {
+		i, inValue := 1, false
+		for ; i < len(s); i++ {
+			c := s[i]
+			if inValue && c == '\\' {
+				i++
+				continue
+			}
+			if c == '"' {
+				inValue = !inValue
+				continue
+			}
+			if c == ']' && !inValue {
+				break
+			}
+		}
+		if i >= len(s) {
+			return nil, fmt.Errorf("unterminated syslog structured data")
+		}
+		s = s[i+1:]
+	}
+	return s, nil
+}
@@ -0,0 +1,57 @@
+package ingestor
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSyslogMessageBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		frame   string
+		want    string
+		wantErr bool
+	}{
+		{"rfc5424 nil structured data", `<14>1 2025-06-01T12:00:00Z host app 12 ID - {"a":1}`, `{"a":1}`, false},
+		{"rfc5424 structured data", `<14>1 2025-06-01T12:00:00Z host app - - [x@1 k="v"][y@1 k="a\]b"] {"a":1}`, `{"a":1}`, false},
+		{"rfc5424 BOM", "<14>1 2025-06-01T12:00:00Z host app - - - \xef\xbb\xbf{\"a\":1}", `{"a":1}`, false},
+		{"rfc5424 no message", `<14>1 2025-06-01T12:00:00Z host app - - -`, ``, false},
+		{"rfc3164", `<14>Jun  1 12:00:00 host app[12]: {"a":1}`, `{"a":1}`, false},
+		{"rfc3164 without JSON", `<14>Jun  1 12:00:00 host app: hello`, ``, true},
+		{"no priority", `{"a":1}`, ``, true},
+		{"truncated header", `<14>1 2025-06-01T12:00:00Z host`, ``, true},
+		{"unterminated structured data", `<14>1 2025-06-01T12:00:00Z host app - - [x@1 k="v] {}`, ``, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := syslogMessageBody([]byte(tt.frame))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("syslogMessageBody() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("syslogMessageBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadSyslogFrame(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("5 hello3 abcnewline\r\nlast"))
+	for _, want := range []string{"hello", "abc", "newline", "last"} {
+		got, err := readSyslogFrame(r, 64)
+		if err != nil {
+			t.Fatalf("readSyslogFrame() error = %v", err)
+		}
+		if string(got) != want {
+			t.Errorf("readSyslogFrame() = %q, want %q", got, want)
+		}
+	}
+
+	for _, in := range []string{"100 " + strings.Repeat("x", 100), strings.Repeat("x", 100) + "\n"} {
+		if _, err := readSyslogFrame(bufio.NewReader(strings.NewReader(in)), 64); !errors.Is(err, errMessageTooLarge) {
+			t.Errorf("expected errMessageTooLarge, got %v", err)
+		}
+	}
+}
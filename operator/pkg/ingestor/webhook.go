@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"sync"
@@ -24,24 +25,50 @@ type WebhookIngestor struct {
 	// Port is the HTTPS port to listen on.
 	Port int32
 
+	// BindAddress is the IP address to listen on. Empty listens on all
+	// interfaces; see ListenNetwork.
+	BindAddress string
+
 	// TLSCertFile is the path to the TLS certificate.
 	TLSCertFile string
 
 	// TLSKeyFile is the path to the TLS private key.
 	TLSKeyFile string
 
-	// MaxRequestBodyBytes is the maximum request body size.
+	// MaxRequestBodyBytes is the maximum request body size, after gzip
+	// decoding, of requests to any path but EventsPath.
 	MaxRequestBodyBytes int64
 
+	// MaxStreamBodyBytes is the maximum body size, after gzip decoding, of
+	// requests to EventsPath. Zero means DefaultMaxStreamBodyBytes.
+	MaxStreamBodyBytes int64
+
 	// RateLimitPerSecond is the maximum requests per second.
 	RateLimitPerSecond int32
 
+	// PerClientRateLimitPerSecond is the maximum requests per second of a
+	// single client, identified by its client certificate's common name or
+	// else its source IP. Zero disables per-client limits.
+	PerClientRateLimitPerSecond int32
+
 	// ClientCAFile is the path to the CA bundle for mTLS client certificate
 	// verification. If empty, client certificates are not required.
 	ClientCAFile string
 
-	// DeduplicationCacheSize is the size of the auditID LRU cache.
+	// DeduplicationCacheSize is the size of the auditID and stage LRU cache.
 	DeduplicationCacheSize int
+
+	// Authenticator, if set, must accept each request before its body is read.
+	Authenticator Authenticator
+
+	// SourceLabel is the source label of the audicia_ingestor_* metrics.
+	// Without one, no metrics are recorded.
+	SourceLabel string
+
+	consumed *consumption
+
+	mu         sync.Mutex
+	listenAddr string
 }
 
 // NewWebhookIngestor creates a new webhook-based ingestor.
@@ -52,7 +79,7 @@ func NewWebhookIngestor(port int32, tlsCert, tlsKey string) *WebhookIngestor {
 		TLSKeyFile:             tlsKey,
 		MaxRequestBodyBytes:    1048576, // 1MB
 		RateLimitPerSecond:     100,
-		DeduplicationCacheSize: 10000,
+		DeduplicationCacheSize: DefaultDeduplicationWindow,
 	}
 }
 
@@ -60,75 +87,143 @@ func NewWebhookIngestor(port int32, tlsCert, tlsKey string) *WebhookIngestor {
 func (w *WebhookIngestor) Start(ctx context.Context) (<-chan auditv1.Event, error) {
 	ch := make(chan auditv1.Event, 500)
 
-	dedup := newDeduplicationCache(w.DeduplicationCacheSize)
-	limiter := newRateLimiter(int(w.RateLimitPerSecond))
+	dedup := NewEventDeduplicator(w.DeduplicationCacheSize)
+	limiter := newRequestLimiter(int(w.RateLimitPerSecond), int(w.PerClientRateLimitPerSecond))
+	w.consumed = newConsumption(w.SourceLabel)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", w.handleAuditRequest(ch, dedup, limiter))
+	mux.HandleFunc(EventsPath, w.handleEventsRequest(ch, dedup, limiter))
 
 	server := &http.Server{
-		Addr:              fmt.Sprintf(":%d", w.Port),
 		Handler:           mux,
 		ReadHeaderTimeout: 10 * time.Second,
 		ReadTimeout:       30 * time.Second,
 		WriteTimeout:      30 * time.Second,
 	}
 
+	// Load the TLS material up front, so a broken Secret fails the pipeline
+	// start instead of every handshake.
+	files := []string{w.TLSCertFile, w.TLSKeyFile}
+	if w.ClientCAFile != "" {
+		files = append(files, w.ClientCAFile)
+	}
+	cert, err := loadServingCertificate(w.TLSCertFile, w.TLSKeyFile, files)
+	if err != nil {
+		return nil, err
+	}
+	server.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
 	// If a client CA is configured, enable mTLS: only clients presenting a
 	// certificate signed by this CA (typically the kube-apiserver) are accepted.
 	if w.ClientCAFile != "" {
 		tlsConfig, err := w.buildMTLSConfig()
 		if err != nil {
-			return nil, fmt.Errorf("building mTLS config: %w", err)
+			return nil, &TLSConfigError{File: w.ClientCAFile, Files: files, Err: err}
 		}
 		server.TLSConfig = tlsConfig
 		webhookLog.Info("mTLS enabled", "clientCA", w.ClientCAFile)
 	}
+	server.TLSConfig.Certificates = []tls.Certificate{cert}
+	if w.Authenticator != nil {
+		webhookLog.Info("bearer token authentication enabled")
+	}
+
+	// Listen before returning, so a taken port or an address the node
+	// does not have fails the pipeline start.
+	network, address, err := ListenNetwork(w.BindAddress, w.Port)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", address, err)
+	}
+	w.mu.Lock()
+	w.listenAddr = listener.Addr().String()
+	w.mu.Unlock()
 
-	go w.runServer(ctx, server, ch)
+	go w.runServer(ctx, server, listener, ch)
 
 	return ch, nil
 }
 
+// ListenAddress returns the address the webhook server listens on, or ""
+// before Start.
+func (w *WebhookIngestor) ListenAddress() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.listenAddr
+}
+
+// admit checks the method, rate limits and authentication of a request,
+// answering it and returning false if it is rejected.
+func (w *WebhookIngestor) admit(rw http.ResponseWriter, req *http.Request, limiter *requestLimiter) bool {
+	if req.Method != http.MethodPost {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+
+	if ok, scope, retryAfter := limiter.allow(clientKey(req)); !ok {
+		w.tooManyRequests(rw, scope, retryAfter)
+		return false
+	}
+
+	if w.Authenticator != nil {
+		if err := w.Authenticator.Authenticate(req.Context(), req); err != nil {
+			WriteAuthError(rw, err)
+			return false
+		}
+	}
+	return true
+}
+
 // handleAuditRequest returns an HTTP handler that parses audit EventLists
-// and forwards individual events to ch.
-func (w *WebhookIngestor) handleAuditRequest(ch chan<- auditv1.Event, dedup *deduplicationCache, limiter *rateLimiter) http.HandlerFunc {
+// and forwards individual events to ch. The body is read whole; see
+// handleEventsRequest for large batches.
+func (w *WebhookIngestor) handleAuditRequest(ch chan<- auditv1.Event, dedup *EventDeduplicator, limiter *requestLimiter) http.HandlerFunc {
 	return func(rw http.ResponseWriter, req *http.Request) {
-		if req.Method != http.MethodPost {
-			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		if !w.admit(rw, req, limiter) {
 			return
 		}
 
-		if !limiter.allow() {
-			http.Error(rw, "too many requests", http.StatusTooManyRequests)
+		body, err := requestBody(rw, req, w.MaxRequestBodyBytes)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusUnsupportedMediaType)
 			return
 		}
-
-		body := http.MaxBytesReader(rw, req.Body, w.MaxRequestBodyBytes)
+		defer func() { _ = body.Close() }()
 		data, err := io.ReadAll(body)
-		if err != nil {
+		if tooLarge := (*http.MaxBytesError)(nil); errors.As(err, &tooLarge) {
 			http.Error(rw, "request body too large", http.StatusRequestEntityTooLarge)
 			return
 		}
+		if err != nil {
+			http.Error(rw, "unreadable request body", http.StatusBadRequest)
+			return
+		}
+		w.consumed.read(len(data))
 
 		var eventList auditv1.EventList
 		if err := json.Unmarshal(data, &eventList); err != nil {
+			w.consumed.parsed(false)
 			http.Error(rw, "invalid audit event payload", http.StatusBadRequest)
 			return
 		}
+		w.consumed.parsed(true)
 
 		for i := range eventList.Items {
 			event := eventList.Items[i]
 
-			auditID := string(event.AuditID)
-			if auditID != "" && dedup.seen(auditID) {
+			if dedup.Seen(event) {
 				continue
 			}
 
 			select {
 			case ch <- event:
+				w.consumed.emitted(1)
 			default:
-				http.Error(rw, "too many requests", http.StatusTooManyRequests)
+				w.tooManyRequests(rw, throttleBackpressure, time.Second)
 				return
 			}
 		}
@@ -137,14 +232,29 @@ func (w *WebhookIngestor) handleAuditRequest(ch chan<- auditv1.Event, dedup *ded
 	}
 }
 
+// WriteAuthError maps an Authenticator error to an HTTP response.
+func WriteAuthError(rw http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		rw.Header().Set("WWW-Authenticate", `Bearer realm="audicia"`)
+		http.Error(rw, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrForbidden):
+		http.Error(rw, "forbidden", http.StatusForbidden)
+	default:
+		webhookLog.Error(err, "webhook authentication backend error")
+		http.Error(rw, "authentication unavailable", http.StatusServiceUnavailable)
+	}
+}
+
 // runServer starts the HTTPS server and handles graceful shutdown.
-func (w *WebhookIngestor) runServer(ctx context.Context, server *http.Server, ch chan auditv1.Event) {
+func (w *WebhookIngestor) runServer(ctx context.Context, server *http.Server, listener net.Listener, ch chan auditv1.Event) {
 	defer close(ch)
 
 	errCh := make(chan error, 1)
 	go func() {
-		webhookLog.Info("starting webhook HTTPS server", "port", w.Port)
-		if err := server.ListenAndServeTLS(w.TLSCertFile, w.TLSKeyFile); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		webhookLog.Info("starting webhook HTTPS server", "address", listener.Addr().String())
+		// The certificate was loaded by Start into server.TLSConfig.
+		if err := server.ServeTLS(listener, "", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			webhookLog.Error(err, "webhook server error")
 			errCh <- err
 		}
@@ -166,14 +276,20 @@ func (w *WebhookIngestor) runServer(ctx context.Context, server *http.Server, ch
 // buildMTLSConfig creates a tls.Config that requires and verifies client
 // certificates against the CA bundle in ClientCAFile.
 func (w *WebhookIngestor) buildMTLSConfig() (*tls.Config, error) {
-	caCert, err := os.ReadFile(w.ClientCAFile)
+	return clientCATLSConfig(w.ClientCAFile)
+}
+
+// clientCATLSConfig creates a tls.Config that requires client certificates
+// signed by a CA in caFile.
+func clientCATLSConfig(caFile string) (*tls.Config, error) {
+	caCert, err := os.ReadFile(caFile)
 	if err != nil {
-		return nil, fmt.Errorf("reading client CA file %s: %w", w.ClientCAFile, err)
+		return nil, fmt.Errorf("reading client CA file %s: %w", caFile, err)
 	}
 
 	caPool := x509.NewCertPool()
 	if !caPool.AppendCertsFromPEM(caCert) {
-		return nil, fmt.Errorf("client CA file %s contains no valid certificates", w.ClientCAFile)
+		return nil, fmt.Errorf("client CA file %s contains no valid certificates", caFile)
 	}
 
 	return &tls.Config{
@@ -188,43 +304,6 @@ func (w *WebhookIngestor) Checkpoint() Position {
 	return Position{}
 }
 
-// deduplicationCache is a simple bounded cache for deduplicating audit IDs.
-type deduplicationCache struct {
-	mu      sync.Mutex
-	entries map[string]struct{}
-	order   []string
-	maxSize int
-}
-
-func newDeduplicationCache(maxSize int) *deduplicationCache {
-	return &deduplicationCache{
-		entries: make(map[string]struct{}, maxSize),
-		order:   make([]string, 0, maxSize),
-		maxSize: maxSize,
-	}
-}
-
-// seen returns true if the key was already in the cache. Adds it if not.
-func (c *deduplicationCache) seen(key string) bool {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if _, exists := c.entries[key]; exists {
-		return true
-	}
-
-	// Evict oldest if at capacity.
-	if len(c.order) >= c.maxSize {
-		oldest := c.order[0]
-		c.order = c.order[1:]
-		delete(c.entries, oldest)
-	}
-
-	c.entries[key] = struct{}{}
-	c.order = append(c.order, key)
-	return false
-}
-
 // rateLimiter is a simple token bucket rate limiter.
 type rateLimiter struct {
 	mu         sync.Mutex
@@ -244,6 +323,13 @@ func newRateLimiter(perSecond int) *rateLimiter {
 }
 
 func (r *rateLimiter) allow() bool {
+	ok, _ := r.take()
+	return ok
+}
+
+// take consumes a token. Without one, it returns how long until the next
+// token is available.
+func (r *rateLimiter) take() (bool, time.Duration) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -256,8 +342,18 @@ func (r *rateLimiter) allow() bool {
 	r.lastRefill = now
 
 	if r.tokens < 1 {
-		return false
+		if r.refillRate <= 0 {
+			return false, time.Second
+		}
+		return false, time.Duration((1 - r.tokens) / r.refillRate * float64(time.Second))
 	}
 	r.tokens--
-	return true
+	return true, 0
+}
+
+// refund returns a token taken for a request that was rejected elsewhere.
+func (r *rateLimiter) refund() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens = min(r.tokens+1, r.maxTokens)
 }
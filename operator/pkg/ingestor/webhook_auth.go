@@ -0,0 +1,200 @@
+package ingestor
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/felixnotka/audicia/operator/pkg/bearer"
+)
+
+var (
+	// ErrUnauthenticated is returned when a webhook caller presents no token
+	// or a token the cluster does not accept.
+	ErrUnauthenticated = errors.New("unauthenticated")
+
+	// ErrForbidden is returned when an authenticated caller is not allowed
+	// to send events to this source.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Authenticator verifies the credentials presented on a webhook request.
+// Implementations return ErrUnauthenticated or ErrForbidden for rejected
+// callers; any other error is treated as the backend being unavailable.
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *http.Request) error
+}
+
+// defaultTokenCacheTTL bounds how long a successful review is reused, so
+// token revocation and RBAC changes take effect within a minute.
+const defaultTokenCacheTTL = time.Minute
+
+// TokenReviewAuthenticator validates bearer tokens with a TokenReview and
+// authorizes the resulting identity with a SubjectAccessReview against the
+// local cluster. Successful reviews are cached per token for CacheTTL.
+type TokenReviewAuthenticator struct {
+	// Client issues TokenReview and SubjectAccessReview requests.
+	Client client.Client
+
+	// Audiences restricts accepted tokens. Empty means the API server default.
+	Audiences []string
+
+	// Attributes describes the permission the caller must hold.
+	Attributes authorizationv1.ResourceAttributes
+
+	// CacheTTL is how long a successful review is reused.
+	CacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]time.Time
+	now   func() time.Time
+}
+
+// NewTokenReviewAuthenticator creates an authenticator that requires callers
+// to hold "create" on audiciasources/ingest for the named source.
+func NewTokenReviewAuthenticator(c client.Client, namespace, name string, audiences []string) *TokenReviewAuthenticator {
+	return &TokenReviewAuthenticator{
+		Client:    c,
+		Audiences: audiences,
+		Attributes: authorizationv1.ResourceAttributes{
+			Namespace:   namespace,
+			Verb:        "create",
+			Group:       "audicia.io",
+			Resource:    "audiciasources",
+			Subresource: "ingest",
+			Name:        name,
+		},
+		CacheTTL: defaultTokenCacheTTL,
+		cache:    make(map[[sha256.Size]byte]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Authenticate checks the request's bearer token.
+func (a *TokenReviewAuthenticator) Authenticate(ctx context.Context, req *http.Request) error {
+	token, ok := bearerToken(req)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	key := sha256.Sum256([]byte(token))
+	if a.cached(key) {
+		return nil
+	}
+
+	tr := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token, Audiences: a.Audiences},
+	}
+	if err := a.Client.Create(ctx, tr); err != nil {
+		return fmt.Errorf("creating TokenReview: %w", err)
+	}
+	if !tr.Status.Authenticated {
+		return ErrUnauthenticated
+	}
+
+	attrs := a.Attributes
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &attrs,
+			User:               tr.Status.User.Username,
+			UID:                tr.Status.User.UID,
+			Groups:             tr.Status.User.Groups,
+			Extra:              convertExtra(tr.Status.User.Extra),
+		},
+	}
+	if err := a.Client.Create(ctx, sar); err != nil {
+		return fmt.Errorf("creating SubjectAccessReview: %w", err)
+	}
+	if !sar.Status.Allowed {
+		webhookLog.V(1).Info("webhook caller denied", "user", tr.Status.User.Username, "reason", sar.Status.Reason)
+		return ErrForbidden
+	}
+
+	a.store(key)
+	return nil
+}
+
+func (a *TokenReviewAuthenticator) cached(key [sha256.Size]byte) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	expiry, ok := a.cache[key]
+	if !ok {
+		return false
+	}
+	if a.now().After(expiry) {
+		delete(a.cache, key)
+		return false
+	}
+	return true
+}
+
+func (a *TokenReviewAuthenticator) store(key [sha256.Size]byte) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	// Drop expired entries opportunistically so rotated tokens don't pile up.
+	for k, expiry := range a.cache {
+		if now.After(expiry) {
+			delete(a.cache, k)
+		}
+	}
+	a.cache[key] = now.Add(a.CacheTTL)
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer" header.
+func bearerToken(req *http.Request) (string, bool) {
+	const prefix = "bearer "
+	h := req.Header.Get("Authorization")
+	if len(h) <= len(prefix) || !strings.EqualFold(h[:len(prefix)], prefix) {
+		return "", false
+	}
+	token := strings.TrimSpace(h[len(prefix):])
+	return token, token != ""
+}
+
+func convertExtra(in map[string]authenticationv1.ExtraValue) map[string]authorizationv1.ExtraValue {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]authorizationv1.ExtraValue, len(in))
+	for k, v := range in {
+		out[k] = authorizationv1.ExtraValue(v)
+	}
+	return out
+}
+
+// StaticTokenAuthenticator accepts requests whose bearer token matches the
+// contents of a token file. The file is re-read when it changes, so a
+// rotated Secret takes effect as soon as the kubelet updates the mount.
+type StaticTokenAuthenticator struct {
+	// TokenFile holds the expected token. Surrounding whitespace is ignored.
+	TokenFile *bearer.TokenFile
+}
+
+// NewStaticTokenAuthenticator creates an authenticator for the token in tokenFile.
+func NewStaticTokenAuthenticator(tokenFile string) *StaticTokenAuthenticator {
+	return &StaticTokenAuthenticator{TokenFile: bearer.NewTokenFile(tokenFile)}
+}
+
+// Authenticate compares the request's bearer token with the expected token.
+func (a *StaticTokenAuthenticator) Authenticate(_ context.Context, req *http.Request) error {
+	expected, err := a.TokenFile.Token()
+	if err != nil {
+		return fmt.Errorf("reading webhook token: %w", err)
+	}
+
+	token, ok := bearerToken(req)
+	if !ok || !bearer.Equal(token, expected) {
+		return ErrUnauthenticated
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package ingestor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// fakeReviewer answers TokenReviews from a token→username map and
+// SubjectAccessReviews from a set of allowed usernames.
+type fakeReviewer struct {
+	tokens  map[string]string
+	allowed map[string]bool
+	calls   int
+	lastSAR authorizationv1.SubjectAccessReviewSpec
+}
+
+func (f *fakeReviewer) client() client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(clientgoscheme.Scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+				f.calls++
+				switch o := obj.(type) {
+				case *authenticationv1.TokenReview:
+					if user, ok := f.tokens[o.Spec.Token]; ok {
+						o.Status.Authenticated = true
+						o.Status.User.Username = user
+					}
+				case *authorizationv1.SubjectAccessReview:
+					f.lastSAR = o.Spec
+					o.Status.Allowed = f.allowed[o.Spec.User]
+				}
+				return nil
+			},
+		}).
+		Build()
+}
+
+func TestTokenReviewAuthenticator_Authenticate(t *testing.T) {
+	reviewer := &fakeReviewer{
+		tokens: map[string]string{
+			"good-token":  "system:serviceaccount:logging:fluent-bit",
+			"other-token": "system:serviceaccount:default:app",
+		},
+		allowed: map[string]bool{"system:serviceaccount:logging:fluent-bit": true},
+	}
+	auth := NewTokenReviewAuthenticator(reviewer.client(), "audicia-system", "forwarded", nil)
+
+	tests := []struct {
+		name   string
+		header string
+		want   error
+	}{
+		{"missing header", "", ErrUnauthenticated},
+		{"wrong scheme", "Basic Zm9vOmJhcg==", ErrUnauthenticated},
+		{"empty token", "Bearer ", ErrUnauthenticated},
+		{"unknown token", "Bearer bogus", ErrUnauthenticated},
+		{"authenticated but not authorized", "Bearer other-token", ErrForbidden},
+		{"authorized", "Bearer good-token", nil},
+		{"case-insensitive scheme", "bearer good-token", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			err := auth.Authenticate(context.Background(), req)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Authenticate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	attrs := reviewer.lastSAR.ResourceAttributes
+	if attrs == nil || attrs.Resource != "audiciasources" || attrs.Subresource != "ingest" ||
+		attrs.Namespace != "audicia-system" || attrs.Name != "forwarded" || attrs.Verb != "create" {
+		t.Errorf("SubjectAccessReview attributes = %+v", attrs)
+	}
+}
+
+func TestTokenReviewAuthenticator_CachesSuccess(t *testing.T) {
+	reviewer := &fakeReviewer{
+		tokens:  map[string]string{"good-token": "forwarder"},
+		allowed: map[string]bool{"forwarder": true},
+	}
+	auth := NewTokenReviewAuthenticator(reviewer.client(), "ns", "src", nil)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	auth.now = func() time.Time { return now }
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer good-token")
+
+	for i := 0; i < 3; i++ {
+		if err := auth.Authenticate(context.Background(), req); err != nil {
+			t.Fatalf("Authenticate() error = %v", err)
+		}
+	}
+	if reviewer.calls != 2 {
+		t.Errorf("review calls = %d, want 2 (one TokenReview + one SAR)", reviewer.calls)
+	}
+
+	now = now.Add(defaultTokenCacheTTL + time.Second)
+	if err := auth.Authenticate(context.Background(), req); err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if reviewer.calls != 4 {
+		t.Errorf("review calls after expiry = %d, want 4", reviewer.calls)
+	}
+}
+
+// stubAuthenticator returns a fixed result.
+type stubAuthenticator struct{ err error }
+
+func (s stubAuthenticator) Authenticate(context.Context, *http.Request) error { return s.err }
+
+func TestHandleAuditRequest_AuthenticatorStatusCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"accepted", nil, http.StatusOK},
+		{"unauthenticated", ErrUnauthenticated, http.StatusUnauthorized},
+		{"forbidden", ErrForbidden, http.StatusForbidden},
+		{"backend unavailable", errors.New("connection refused"), http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &WebhookIngestor{
+				MaxRequestBodyBytes: 1048576,
+				Authenticator:       stubAuthenticator{err: tt.err},
+			}
+			ch := make(chan auditv1.Event, 10)
+			handler := w.handleAuditRequest(ch, NewEventDeduplicator(100), newRequestLimiter(100, 0))
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`{"items":[]}`)))
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			if rr.Code != tt.want {
+				t.Errorf("status = %d, want %d", rr.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestStaticTokenAuthenticator_Authenticate(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a := NewStaticTokenAuthenticator(tokenFile)
+
+	tests := []struct {
+		name   string
+		header string
+		want   error
+	}{
+		{"valid token", "Bearer s3cret", nil},
+		{"case-insensitive scheme", "bearer s3cret", nil},
+		{"wrong token", "Bearer s3cret2", ErrUnauthenticated},
+		{"missing header", "", ErrUnauthenticated},
+		{"basic auth", "Basic czNjcmV0", ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if err := a.Authenticate(context.Background(), req); !errors.Is(err, tt.want) {
+				t.Errorf("Authenticate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStaticTokenAuthenticator_MissingFile(t *testing.T) {
+	a := NewStaticTokenAuthenticator(filepath.Join(t.TempDir(), "missing"))
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer anything")
+
+	err := a.Authenticate(context.Background(), req)
+	if err == nil || errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Authenticate() = %v, want backend error", err)
+	}
+}
@@ -0,0 +1,142 @@
+package ingestor
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+)
+
+const (
+	// clientBucketIdle is how long a client's bucket is kept after its last
+	// request. A bucket idle that long has refilled, so dropping it loses
+	// nothing.
+	clientBucketIdle = time.Minute
+
+	// clientBucketSweep is the minimum time between sweeps for idle
+	// buckets. A full map of active clients is not rescanned per request.
+	clientBucketSweep = 10 * time.Second
+
+	// maxClientBuckets bounds the number of client buckets. Clients beyond
+	// it, once idle buckets were dropped, share the global limit only.
+	maxClientBuckets = 10000
+)
+
+// Rate limit scopes, reported in the audicia_webhook_throttled_total metric.
+const (
+	throttleGlobal       = "global"
+	throttleClient       = "client"
+	throttleBackpressure = "backpressure"
+)
+
+// requestLimiter limits webhook requests globally and, optionally, per
+// client. A request is charged to its client's bucket first, so a client over
+// its own limit is rejected without using up the global budget the other
+// clients share; one misconfigured API server in a multi-control-plane setup
+// cannot starve the others.
+type requestLimiter struct {
+	global *rateLimiter
+
+	// perClient is the per-client limit in requests per second. Zero
+	// disables per-client buckets.
+	perClient int
+
+	mu        sync.Mutex
+	clients   map[string]*clientBucket
+	lastSweep time.Time
+}
+
+type clientBucket struct {
+	limiter  *rateLimiter
+	lastSeen time.Time
+}
+
+func newRequestLimiter(perSecond, perClientPerSecond int) *requestLimiter {
+	return &requestLimiter{
+		global:    newRateLimiter(perSecond),
+		perClient: perClientPerSecond,
+		clients:   make(map[string]*clientBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// allow charges a request of client. When it is rejected, it returns the
+// scope of the exhausted limit and how long until it admits a request again.
+// A request the global limit rejects is refunded to the client's bucket, so
+// a crowded global budget does not also use up the client's own.
+func (l *requestLimiter) allow(client string) (ok bool, scope string, retryAfter time.Duration) {
+	bucket := l.bucket(client)
+	if bucket != nil {
+		if ok, retryAfter := bucket.take(); !ok {
+			return false, throttleClient, retryAfter
+		}
+	}
+	if ok, retryAfter := l.global.take(); !ok {
+		if bucket != nil {
+			bucket.refund()
+		}
+		return false, throttleGlobal, retryAfter
+	}
+	return true, "", 0
+}
+
+// bucket returns the rate limiter of client, or nil without per-client
+// limits.
+func (l *requestLimiter) bucket(client string) *rateLimiter {
+	if l.perClient <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) >= clientBucketSweep {
+		for key, b := range l.clients {
+			if now.Sub(b.lastSeen) >= clientBucketIdle {
+				delete(l.clients, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.clients[client]
+	if !ok {
+		if len(l.clients) >= maxClientBuckets {
+			return nil
+		}
+		b = &clientBucket{limiter: newRateLimiter(l.perClient)}
+		l.clients[client] = b
+	}
+	b.lastSeen = now
+	return b.limiter
+}
+
+// clientKey identifies the caller of req for per-client rate limiting: the
+// common name of a verified client certificate, or else the source IP.
+func clientKey(req *http.Request) string {
+	if req.TLS != nil && len(req.TLS.VerifiedChains) > 0 && len(req.TLS.PeerCertificates) > 0 {
+		if cn := req.TLS.PeerCertificates[0].Subject.CommonName; cn != "" {
+			return "cn:" + cn
+		}
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// tooManyRequests answers 429 with a Retry-After header of at least one
+// second, and counts the rejection for the source.
+func (w *WebhookIngestor) tooManyRequests(rw http.ResponseWriter, scope string, retryAfter time.Duration) {
+	seconds := max(int(math.Ceil(retryAfter.Seconds())), 1)
+	rw.Header().Set("Retry-After", strconv.Itoa(seconds))
+	http.Error(rw, "too many requests", http.StatusTooManyRequests)
+	if w.SourceLabel != "" {
+		metrics.WebhookThrottledTotal.WithLabelValues(w.SourceLabel, scope).Inc()
+	}
+}
@@ -0,0 +1,159 @@
+package ingestor
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+func TestRequestLimiter_ClientOverLimitDoesNotStarveOthers(t *testing.T) {
+	l := newRequestLimiter(3, 1)
+
+	if ok, _, _ := l.allow("ip:10.0.0.1"); !ok {
+		t.Fatal("first request of the noisy client was rejected")
+	}
+	for range 5 {
+		ok, scope, retryAfter := l.allow("ip:10.0.0.1")
+		if ok || scope != throttleClient {
+			t.Fatalf("noisy client: ok=%v scope=%q, want rejected by its client bucket", ok, scope)
+		}
+		if retryAfter <= 0 || retryAfter > time.Second {
+			t.Errorf("retryAfter = %v, want within (0, 1s]", retryAfter)
+		}
+	}
+	// The rejected requests did not use up the global budget.
+	for _, client := range []string{"ip:10.0.0.2", "cn:kube-apiserver-2"} {
+		if ok, scope, _ := l.allow(client); !ok {
+			t.Errorf("%s rejected by the %s limit", client, scope)
+		}
+	}
+	if ok, scope, _ := l.allow("ip:10.0.0.3"); ok || scope != throttleGlobal {
+		t.Errorf("fourth client: ok=%v scope=%q, want rejected by the global limit", ok, scope)
+	}
+}
+
+func TestRequestLimiter_GlobalRejectionRefundsClient(t *testing.T) {
+	l := newRequestLimiter(1, 1)
+	if ok, _, _ := l.allow("ip:10.0.0.1"); !ok {
+		t.Fatal("first request rejected")
+	}
+	if ok, scope, _ := l.allow("ip:10.0.0.2"); ok || scope != throttleGlobal {
+		t.Fatalf("ok=%v scope=%q, want rejected by the global limit", ok, scope)
+	}
+
+	// Once the global budget refills, the second client's single token is
+	// still there.
+	l.global.mu.Lock()
+	l.global.tokens = 1
+	l.global.mu.Unlock()
+	if ok, scope, _ := l.allow("ip:10.0.0.2"); !ok {
+		t.Errorf("rejected by the %s limit, want the earlier global rejection refunded", scope)
+	}
+}
+
+func TestRequestLimiter_GlobalOnly(t *testing.T) {
+	l := newRequestLimiter(1, 0)
+	if ok, _, _ := l.allow("ip:10.0.0.1"); !ok {
+		t.Fatal("first request rejected")
+	}
+	if ok, scope, _ := l.allow("ip:10.0.0.2"); ok || scope != throttleGlobal {
+		t.Errorf("ok=%v scope=%q, want rejected by the global limit", ok, scope)
+	}
+	if len(l.clients) != 0 {
+		t.Errorf("kept %d client buckets without per-client limits", len(l.clients))
+	}
+}
+
+func TestRequestLimiter_DropsIdleBuckets(t *testing.T) {
+	l := newRequestLimiter(100, 10)
+	l.allow("ip:10.0.0.1")
+	l.clients["ip:10.0.0.1"].lastSeen = time.Now().Add(-2 * clientBucketIdle)
+	l.lastSweep = time.Now().Add(-2 * clientBucketIdle)
+
+	l.allow("ip:10.0.0.2")
+	if _, ok := l.clients["ip:10.0.0.1"]; ok {
+		t.Error("idle bucket was kept")
+	}
+	if _, ok := l.clients["ip:10.0.0.2"]; !ok {
+		t.Error("active bucket is missing")
+	}
+}
+
+func TestRequestLimiter_FullMapSweepsAtMostOncePerInterval(t *testing.T) {
+	l := newRequestLimiter(100, 10)
+	for i := range maxClientBuckets {
+		l.clients[fmt.Sprintf("ip:%d", i)] = &clientBucket{limiter: newRateLimiter(10), lastSeen: time.Now()}
+	}
+	l.lastSweep = time.Now()
+
+	// An idle bucket is only dropped by the next due sweep, not by every
+	// request arriving while the map is full.
+	l.clients["ip:0"].lastSeen = time.Now().Add(-2 * clientBucketIdle)
+	if ok, _, _ := l.allow("ip:new"); !ok {
+		t.Fatal("request of a client without a bucket rejected")
+	}
+	if _, ok := l.clients["ip:0"]; !ok {
+		t.Fatal("full map swept before the sweep interval elapsed")
+	}
+
+	l.lastSweep = time.Now().Add(-clientBucketSweep)
+	l.allow("ip:new")
+	if _, ok := l.clients["ip:0"]; ok {
+		t.Error("idle bucket kept after the sweep interval elapsed")
+	}
+	if _, ok := l.clients["ip:new"]; !ok {
+		t.Error("new client got no bucket from the freed slot")
+	}
+}
+
+func TestClientKey(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.RemoteAddr = "[fd00::1]:51234"
+	if got := clientKey(req); got != "ip:fd00::1" {
+		t.Errorf("without certificate: key = %q", got)
+	}
+
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "kube-apiserver"}}
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	if got := clientKey(req); got != "ip:fd00::1" {
+		t.Errorf("unverified certificate: key = %q", got)
+	}
+	req.TLS.VerifiedChains = [][]*x509.Certificate{{cert}}
+	if got := clientKey(req); got != "cn:kube-apiserver" {
+		t.Errorf("verified certificate: key = %q", got)
+	}
+}
+
+func TestHandleAuditRequest_RetryAfter(t *testing.T) {
+	w := &WebhookIngestor{MaxRequestBodyBytes: 1048576}
+	handler := w.handleAuditRequest(make(chan auditv1.Event, 10), NewEventDeduplicator(100), newRequestLimiter(100, 1))
+	body, _ := json.Marshal(auditv1.EventList{Items: []auditv1.Event{{AuditID: "ra-1", Verb: "get"}}})
+
+	post := func(remote string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		req.RemoteAddr = remote
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		return rr
+	}
+	post("10.0.0.1:1000")
+	rr := post("10.0.0.1:1001")
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusTooManyRequests)
+	}
+	if got := rr.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want 1", got)
+	}
+	if rr := post("10.0.0.2:1000"); rr.Code != http.StatusOK {
+		t.Errorf("other client: status = %d, want %d", rr.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,194 @@
+package ingestor
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// EventsPath is the streaming webhook endpoint. It accepts an
+// audit.k8s.io/v1 EventList or a single Event, optionally gzip-encoded, and
+// decodes it incrementally, so large batches are neither rejected for their
+// size nor buffered whole.
+const EventsPath = "/audit/v1/events"
+
+// DefaultMaxStreamBodyBytes bounds the decompressed body of a request to
+// EventsPath. Only one event is held in memory at a time, so the bound can
+// be far larger than MaxRequestBodyBytes.
+const DefaultMaxStreamBodyBytes = 64 << 20
+
+// requestBody returns the body of req, decompressed if it is gzip-encoded.
+// Reading fails once more than limit decompressed bytes were read, so a
+// small compressed body cannot expand without bound.
+func requestBody(rw http.ResponseWriter, req *http.Request, limit int64) (io.ReadCloser, error) {
+	switch encoding := strings.ToLower(strings.TrimSpace(req.Header.Get("Content-Encoding"))); encoding {
+	case "", "identity":
+		return http.MaxBytesReader(rw, req.Body, limit), nil
+	case "gzip":
+		gz, err := gzip.NewReader(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip body: %w", err)
+		}
+		return http.MaxBytesReader(rw, gz, limit), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+}
+
+// handleEventsRequest returns the handler of EventsPath. Events are handed
+// to ch as they are decoded; if the client disconnects midway, the events
+// already decoded are kept and the deduplicator drops them when the batch is
+// retried.
+func (w *WebhookIngestor) handleEventsRequest(ch chan<- auditv1.Event, dedup *EventDeduplicator, limiter *requestLimiter) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if !w.admit(rw, req, limiter) {
+			return
+		}
+
+		limit := w.MaxStreamBodyBytes
+		if limit <= 0 {
+			limit = DefaultMaxStreamBodyBytes
+		}
+		body, err := requestBody(rw, req, limit)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusUnsupportedMediaType)
+			return
+		}
+		defer func() { _ = body.Close() }()
+
+		counted := &countingReader{r: body}
+		err = decodeEvents(counted, func(event auditv1.Event) error {
+			w.consumed.parsed(true)
+			if dedup.Seen(event) {
+				return nil
+			}
+			select {
+			case ch <- event:
+				w.consumed.emitted(1)
+				return nil
+			case <-req.Context().Done():
+				return req.Context().Err()
+			}
+		})
+		w.consumed.read(int(counted.n))
+
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			http.Error(rw, "request body too large", http.StatusRequestEntityTooLarge)
+		case req.Context().Err() != nil:
+			// The client is gone; there is no one to answer.
+		case err != nil:
+			w.consumed.parsed(false)
+			http.Error(rw, "invalid audit event payload", http.StatusBadRequest)
+		default:
+			rw.WriteHeader(http.StatusOK)
+		}
+	}
+}
+
+// decodeEvents streams the events of an audit.k8s.io/v1 EventList, or a
+// single Event, from r to emit. The items of a list are decoded one at a
+// time; other fields are small and read whole.
+func decodeEvents(r io.Reader, emit func(auditv1.Event) error) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	fields := make(map[string]json.RawMessage)
+	list := false
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		if key == "items" {
+			list = true
+			if err := decodeItems(dec, emit); err != nil {
+				return err
+			}
+			continue
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		fields[key] = value
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+
+	var kind string
+	_ = json.Unmarshal(fields["kind"], &kind)
+	if list || kind == "EventList" {
+		return nil
+	}
+	if _, ok := fields["auditID"]; !ok && kind != "Event" {
+		return errors.New("payload is neither an audit Event nor an EventList")
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	var event auditv1.Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+	return emit(event)
+}
+
+// decodeItems decodes the items array of an EventList, or null.
+func decodeItems(dec *json.Decoder, emit func(auditv1.Event) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return fmt.Errorf("items: expected an array, got %v", tok)
+	}
+	for dec.More() {
+		var event auditv1.Event
+		if err := dec.Decode(&event); err != nil {
+			return err
+		}
+		if err := emit(event); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// expectDelim reads the next token and checks that it is delim.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %q, got %v", delim, tok)
+	}
+	return nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
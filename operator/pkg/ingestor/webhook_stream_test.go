@@ -0,0 +1,121 @@
+package ingestor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+func gzipped(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func eventListJSON(n int) string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = validAuditJSON(fmt.Sprintf("e%d", i), "get", "pods", "default")
+	}
+	return `{"kind":"EventList","apiVersion":"audit.k8s.io/v1","items":[` + strings.Join(items, ",") + `]}`
+}
+
+func TestDecodeEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    int
+		wantErr bool
+	}{
+		{"event list", eventListJSON(3), 3, false},
+		{"items before kind", `{"items":[` + validAuditJSON("a", "get", "pods", "default") + `],"kind":"EventList"}`, 1, false},
+		{"empty list", `{"kind":"EventList","items":null}`, 0, false},
+		{"single event", validAuditJSON("a", "get", "pods", "default"), 1, false},
+		{"not an event", `{"foo":"bar"}`, 0, true},
+		{"truncated list", eventListJSON(2)[:60], 0, true},
+		{"not an object", `[1,2]`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []auditv1.Event
+			err := decodeEvents(strings.NewReader(tt.payload), func(e auditv1.Event) error {
+				got = append(got, e)
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(got) != tt.want {
+				t.Errorf("decoded %d events, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleEventsRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     []byte
+		encoding string
+		maxBytes int64
+		status   int
+		events   int
+	}{
+		{"plain event list", []byte(eventListJSON(5)), "", 0, http.StatusOK, 5},
+		{"gzip event list", gzipped(t, eventListJSON(5)), "gzip", 0, http.StatusOK, 5},
+		{"single event", []byte(validAuditJSON("a", "get", "pods", "default")), "", 0, http.StatusOK, 1},
+		{"unsupported encoding", []byte(eventListJSON(1)), "br", 0, http.StatusUnsupportedMediaType, 0},
+		{"corrupt gzip", []byte("not gzip"), "gzip", 0, http.StatusUnsupportedMediaType, 0},
+		{"decompressed body too large", gzipped(t, eventListJSON(50)), "gzip", 1024, http.StatusRequestEntityTooLarge, -1},
+		{"invalid payload", []byte(`{"foo":"bar"}`), "", 0, http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &WebhookIngestor{MaxStreamBodyBytes: tt.maxBytes}
+			ch := make(chan auditv1.Event, 100)
+			handler := w.handleEventsRequest(ch, NewEventDeduplicator(100), newRequestLimiter(100, 0))
+
+			req := httptest.NewRequest(http.MethodPost, EventsPath, bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.events >= 0 && len(ch) != tt.events {
+				t.Errorf("emitted %d events, want %d", len(ch), tt.events)
+			}
+		})
+	}
+}
+
+func TestHandleAuditRequest_Gzip(t *testing.T) {
+	w := &WebhookIngestor{MaxRequestBodyBytes: 1 << 20}
+	ch := make(chan auditv1.Event, 10)
+	handler := w.handleAuditRequest(ch, NewEventDeduplicator(100), newRequestLimiter(100, 0))
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(gzipped(t, eventListJSON(2))))
+	req.Header.Set("Content-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || len(ch) != 2 {
+		t.Errorf("status = %d, events = %d, want 200 and 2", rec.Code, len(ch))
+	}
+}
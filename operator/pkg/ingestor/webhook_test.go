@@ -24,8 +24,8 @@ import (
 func TestHandleAuditRequest_ValidPost(t *testing.T) {
 	w := &WebhookIngestor{MaxRequestBodyBytes: 1048576}
 	ch := make(chan auditv1.Event, 10)
-	dedup := newDeduplicationCache(100)
-	limiter := newRateLimiter(100)
+	dedup := NewEventDeduplicator(100)
+	limiter := newRequestLimiter(100, 0)
 
 	handler := w.handleAuditRequest(ch, dedup, limiter)
 
@@ -70,8 +70,8 @@ func TestHandleAuditRequest_ValidPost(t *testing.T) {
 func TestHandleAuditRequest_GetMethodRejected(t *testing.T) {
 	w := &WebhookIngestor{MaxRequestBodyBytes: 1048576}
 	ch := make(chan auditv1.Event, 10)
-	dedup := newDeduplicationCache(100)
-	limiter := newRateLimiter(100)
+	dedup := NewEventDeduplicator(100)
+	limiter := newRequestLimiter(100, 0)
 
 	handler := w.handleAuditRequest(ch, dedup, limiter)
 
@@ -88,8 +88,8 @@ func TestHandleAuditRequest_GetMethodRejected(t *testing.T) {
 func TestHandleAuditRequest_InvalidJSON(t *testing.T) {
 	w := &WebhookIngestor{MaxRequestBodyBytes: 1048576}
 	ch := make(chan auditv1.Event, 10)
-	dedup := newDeduplicationCache(100)
-	limiter := newRateLimiter(100)
+	dedup := NewEventDeduplicator(100)
+	limiter := newRequestLimiter(100, 0)
 
 	handler := w.handleAuditRequest(ch, dedup, limiter)
 
@@ -106,8 +106,8 @@ func TestHandleAuditRequest_InvalidJSON(t *testing.T) {
 func TestHandleAuditRequest_Deduplication(t *testing.T) {
 	w := &WebhookIngestor{MaxRequestBodyBytes: 1048576}
 	ch := make(chan auditv1.Event, 10)
-	dedup := newDeduplicationCache(100)
-	limiter := newRateLimiter(100)
+	dedup := NewEventDeduplicator(100)
+	limiter := newRequestLimiter(100, 0)
 
 	handler := w.handleAuditRequest(ch, dedup, limiter)
 
@@ -141,35 +141,6 @@ func TestHandleAuditRequest_Deduplication(t *testing.T) {
 	}
 }
 
-func TestDeduplicationCache_Basic(t *testing.T) {
-	c := newDeduplicationCache(3)
-
-	if c.seen("a") {
-		t.Error("'a' should not be seen yet")
-	}
-	if !c.seen("a") {
-		t.Error("'a' should be seen now")
-	}
-}
-
-func TestDeduplicationCache_Eviction(t *testing.T) {
-	c := newDeduplicationCache(2)
-
-	c.seen("a")
-	c.seen("b")
-	c.seen("c") // Should evict "a". Cache: ["b","c"].
-
-	// Check "b" first — it should still be present.
-	// (Note: seen() is check-and-add, so checking "a" first would re-add it
-	// and evict "b", making the second assertion fail.)
-	if !c.seen("b") {
-		t.Error("'b' should still be present")
-	}
-	if c.seen("a") {
-		t.Error("'a' should have been evicted")
-	}
-}
-
 func TestRateLimiter_AllowsWithinLimit(t *testing.T) {
 	rl := newRateLimiter(10)
 	for i := 0; i < 10; i++ {
@@ -190,8 +161,8 @@ func TestRateLimiter_DeniesOverLimit(t *testing.T) {
 func TestHandleAuditRequest_BodyTooLarge(t *testing.T) {
 	w := &WebhookIngestor{MaxRequestBodyBytes: 10} // Tiny limit.
 	ch := make(chan auditv1.Event, 10)
-	dedup := newDeduplicationCache(100)
-	limiter := newRateLimiter(100)
+	dedup := NewEventDeduplicator(100)
+	limiter := newRequestLimiter(100, 0)
 
 	handler := w.handleAuditRequest(ch, dedup, limiter)
 
@@ -210,8 +181,8 @@ func TestHandleAuditRequest_BodyTooLarge(t *testing.T) {
 func TestHandleAuditRequest_RateLimited(t *testing.T) {
 	w := &WebhookIngestor{MaxRequestBodyBytes: 1048576}
 	ch := make(chan auditv1.Event, 10)
-	dedup := newDeduplicationCache(100)
-	limiter := newRateLimiter(1) // Allow only 1 request per second.
+	dedup := NewEventDeduplicator(100)
+	limiter := newRequestLimiter(1, 0) // Allow only 1 request per second.
 
 	handler := w.handleAuditRequest(ch, dedup, limiter)
 
@@ -240,8 +211,8 @@ func TestHandleAuditRequest_RateLimited(t *testing.T) {
 func TestHandleAuditRequest_ChannelFull(t *testing.T) {
 	w := &WebhookIngestor{MaxRequestBodyBytes: 1048576}
 	ch := make(chan auditv1.Event, 1) // Only room for 1 event.
-	dedup := newDeduplicationCache(100)
-	limiter := newRateLimiter(100)
+	dedup := NewEventDeduplicator(100)
+	limiter := newRequestLimiter(100, 0)
 
 	handler := w.handleAuditRequest(ch, dedup, limiter)
 
@@ -268,8 +239,8 @@ func TestHandleAuditRequest_ChannelFull(t *testing.T) {
 func TestHandleAuditRequest_EmptyAuditID(t *testing.T) {
 	w := &WebhookIngestor{MaxRequestBodyBytes: 1048576}
 	ch := make(chan auditv1.Event, 10)
-	dedup := newDeduplicationCache(100)
-	limiter := newRateLimiter(100)
+	dedup := NewEventDeduplicator(100)
+	limiter := newRequestLimiter(100, 0)
 
 	handler := w.handleAuditRequest(ch, dedup, limiter)
 
@@ -0,0 +1,95 @@
+package ingestor
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// TLSConfigError reports webhook TLS material the server cannot use: a
+// certificate or key that does not parse, a key that does not match the
+// certificate, or a client CA bundle without certificates. It is returned by
+// Start, before the server listens, rather than on the first handshake.
+type TLSConfigError struct {
+	// File is the file at fault.
+	File string
+
+	// Certificate describes the serving certificate when it parsed: its
+	// SHA-256 fingerprint, subject and expiry.
+	Certificate string
+
+	// Files are all TLS files of the webhook; the material is valid again
+	// once one of them changes.
+	Files []string
+
+	Err error
+}
+
+func (e *TLSConfigError) Error() string {
+	msg := fmt.Sprintf("invalid webhook TLS material in %s: %v", e.File, e.Err)
+	if e.Certificate != "" {
+		msg += " (certificate " + e.Certificate + ")"
+	}
+	return msg
+}
+
+func (e *TLSConfigError) Unwrap() error { return e.Err }
+
+// loadServingCertificate loads the key pair in certFile and keyFile,
+// returning a *TLSConfigError that names the certificate's fingerprint when
+// the key does not match it.
+func loadServingCertificate(certFile, keyFile string, files []string) (tls.Certificate, error) {
+	fail := func(file, cert string, err error) (tls.Certificate, error) {
+		return tls.Certificate{}, &TLSConfigError{File: file, Certificate: cert, Files: files, Err: err}
+	}
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return fail(certFile, "", err)
+	}
+	leaf, err := parseLeaf(certPEM)
+	if err != nil {
+		return fail(certFile, "", err)
+	}
+	desc := describeCertificate(leaf)
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return fail(keyFile, desc, err)
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fail(keyFile, desc, err)
+	}
+	return pair, nil
+}
+
+// parseLeaf parses the first certificate of a PEM bundle.
+func parseLeaf(data []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("no PEM-encoded certificate found")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// describeCertificate returns "sha256 <fingerprint>, subject <dn>, expires
+// <time>" for cert.
+func describeCertificate(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+	return fmt.Sprintf("sha256 %s, subject %s, expires %s",
+		strings.Join(hex, ":"), cert.Subject, cert.NotAfter.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,108 @@
+package ingestor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeKeyPair writes a self-signed certificate and its key to dir and
+// returns their paths.
+func writeKeyPair(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestLoadServingCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeKeyPair(t, dir, "webhook")
+	_, otherKey := writeKeyPair(t, dir, "other")
+	garbage := filepath.Join(dir, "garbage.crt")
+	if err := os.WriteFile(garbage, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadServingCertificate(certFile, keyFile, nil); err != nil {
+		t.Fatalf("matching key pair: %v", err)
+	}
+
+	tests := []struct {
+		name, cert, key, file string
+		withCertificate       bool
+	}{
+		{"key does not match", certFile, otherKey, otherKey, true},
+		{"certificate does not parse", garbage, keyFile, garbage, false},
+		{"certificate missing", filepath.Join(dir, "missing.crt"), keyFile, filepath.Join(dir, "missing.crt"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadServingCertificate(tt.cert, tt.key, nil)
+			var tlsErr *TLSConfigError
+			if !errors.As(err, &tlsErr) {
+				t.Fatalf("err = %v, want a *TLSConfigError", err)
+			}
+			if tlsErr.File != tt.file {
+				t.Errorf("File = %q, want %q", tlsErr.File, tt.file)
+			}
+			if got := strings.HasPrefix(tlsErr.Certificate, "sha256 "); got != tt.withCertificate {
+				t.Errorf("Certificate = %q, want a fingerprint: %v", tlsErr.Certificate, tt.withCertificate)
+			}
+			if tt.withCertificate && !strings.Contains(err.Error(), "subject CN=webhook") {
+				t.Errorf("Error() = %q, want the certificate subject", err)
+			}
+		})
+	}
+}
+
+func TestWebhookIngestor_StartFailsOnInvalidTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, _ := writeKeyPair(t, dir, "webhook")
+	_, otherKey := writeKeyPair(t, dir, "other")
+
+	w := NewWebhookIngestor(0, certFile, otherKey)
+	_, err := w.Start(context.Background())
+	var tlsErr *TLSConfigError
+	if !errors.As(err, &tlsErr) {
+		t.Fatalf("Start() err = %v, want a *TLSConfigError", err)
+	}
+	if len(tlsErr.Files) != 2 || tlsErr.Files[0] != certFile || tlsErr.Files[1] != otherKey {
+		t.Errorf("Files = %v, want the certificate and key", tlsErr.Files)
+	}
+}
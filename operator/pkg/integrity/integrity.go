@@ -0,0 +1,169 @@
+// Package integrity maintains and verifies the hash chain over the observed
+// rules of AudiciaReports, so edits made outside the operator can be
+// detected.
+package integrity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// DefaultHistoryLimit is the number of chain entries kept when
+// spec.integrity omits historyLimit.
+const DefaultHistoryLimit = 20
+
+// ErrNoChain is returned by Verify for reports without a hash chain.
+var ErrNoChain = errors.New("report has no integrity chain")
+
+// RulesHash returns the hex SHA-256 of rules. Nil and empty lists hash the
+// same, so the hash survives a round trip through the API server.
+func RulesHash(rules []audiciav1alpha1.ObservedRule) (string, error) {
+	canonical := make([]audiciav1alpha1.ObservedRule, len(rules))
+	for i, r := range rules {
+		r.APIGroups = nonNil(r.APIGroups)
+		r.Resources = nonNil(r.Resources)
+		r.Verbs = nonNil(r.Verbs)
+		canonical[i] = r
+	}
+	data, err := json.Marshal(canonical)
+	if err != nil {
+		return "", fmt.Errorf("encoding rules: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// entryHash links an entry to the one before it.
+func entryHash(previous string, t metav1.Time, rulesHash string) string {
+	sum := sha256.Sum256([]byte(previous + "\n" + t.UTC().Format(time.RFC3339) + "\n" + rulesHash))
+	return hex.EncodeToString(sum[:])
+}
+
+// Append adds an entry for rules to the chain when they differ from the
+// rules of the last entry, and keeps at most limit entries. previous are the
+// rules the report held before, used to count the change. The returned
+// status may be the one passed in.
+func Append(
+	status *audiciav1alpha1.IntegrityStatus,
+	previous, rules []audiciav1alpha1.ObservedRule,
+	now time.Time,
+	limit int,
+) (*audiciav1alpha1.IntegrityStatus, error) {
+	rulesHash, err := RulesHash(rules)
+	if err != nil {
+		return status, err
+	}
+	if status == nil {
+		status = &audiciav1alpha1.IntegrityStatus{}
+	}
+	prevHash := ""
+	if n := len(status.Entries); n > 0 {
+		last := status.Entries[n-1]
+		if last.RulesHash == rulesHash {
+			return status, nil
+		}
+		prevHash = last.Hash
+	}
+
+	added, removed := countChanges(previous, rules)
+	t := metav1.NewTime(now.UTC().Truncate(time.Second))
+	status.Entries = append(status.Entries, audiciav1alpha1.IntegrityEntry{
+		Time:         t,
+		RulesHash:    rulesHash,
+		PreviousHash: prevHash,
+		Hash:         entryHash(prevHash, t, rulesHash),
+		Added:        int32(added),
+		Removed:      int32(removed),
+	})
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	if extra := len(status.Entries) - limit; extra > 0 {
+		status.Entries = slices.Delete(status.Entries, 0, extra)
+	}
+	return status, nil
+}
+
+// countChanges counts the rules in rules but not in previous and the other
+// way round, by identity (scope, API group, resource or URL and verbs).
+func countChanges(previous, rules []audiciav1alpha1.ObservedRule) (added, removed int) {
+	before := make(map[string]bool, len(previous))
+	for _, r := range previous {
+		before[ruleIdentity(r)] = true
+	}
+	after := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		id := ruleIdentity(r)
+		after[id] = true
+		if !before[id] {
+			added++
+		}
+	}
+	for id := range before {
+		if !after[id] {
+			removed++
+		}
+	}
+	return added, removed
+}
+
+func ruleIdentity(r audiciav1alpha1.ObservedRule) string {
+	return strings.Join([]string{
+		r.Namespace,
+		strings.Join(r.APIGroups, ","),
+		strings.Join(r.Resources, ","),
+		strings.Join(r.NonResourceURLs, ","),
+		strings.Join(r.Verbs, ","),
+	}, "|")
+}
+
+// Verify checks that every entry of the chain links to the one before it and
+// that rules are the rules of the last entry. A non-empty anchor, the Hash
+// of an entry recorded at an earlier review, must still be in the chain, so
+// history since then has not been rewritten.
+func Verify(rules []audiciav1alpha1.ObservedRule, status *audiciav1alpha1.IntegrityStatus, anchor string) error {
+	if status == nil || len(status.Entries) == 0 {
+		return ErrNoChain
+	}
+	anchored := anchor == ""
+	for i, e := range status.Entries {
+		if i > 0 && e.PreviousHash != status.Entries[i-1].Hash {
+			return fmt.Errorf("entry %d does not link to the entry before it", i)
+		}
+		if e.Hash != entryHash(e.PreviousHash, e.Time, e.RulesHash) {
+			return fmt.Errorf("entry %d hash does not match its content", i)
+		}
+		if e.Hash == anchor || (i == 0 && e.PreviousHash == anchor) {
+			anchored = true
+		}
+	}
+	if !anchored {
+		return fmt.Errorf("anchor %s is not in the chain", anchor)
+	}
+
+	rulesHash, err := RulesHash(rules)
+	if err != nil {
+		return err
+	}
+	if last := status.Entries[len(status.Entries)-1]; rulesHash != last.RulesHash {
+		return fmt.Errorf("observed rules were changed after the last chain entry (%s)", last.Time.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package integrity
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func rule(resource, verb string) audiciav1alpha1.ObservedRule {
+	return audiciav1alpha1.ObservedRule{
+		APIGroups: []string{""},
+		Resources: []string{resource},
+		Verbs:     []string{verb},
+		Namespace: "default",
+		LastSeen:  metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Count:     1,
+	}
+}
+
+func TestRulesHash_NilAndEmptyListsMatch(t *testing.T) {
+	a, err := RulesHash([]audiciav1alpha1.ObservedRule{{NonResourceURLs: []string{"/healthz"}, Verbs: []string{"get"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := RulesHash([]audiciav1alpha1.ObservedRule{{
+		NonResourceURLs: []string{"/healthz"}, Verbs: []string{"get"}, APIGroups: []string{}, Resources: []string{},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Error("expected nil and empty lists to hash the same")
+	}
+}
+
+func TestAppend(t *testing.T) {
+	now := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	v1 := []audiciav1alpha1.ObservedRule{rule("pods", "get")}
+	v2 := []audiciav1alpha1.ObservedRule{rule("pods", "list"), rule("services", "get")}
+
+	chain, err := Append(nil, nil, v1, now, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unchanged rules add no entry.
+	if chain, err = Append(chain, v1, v1, now.Add(time.Minute), 0); err != nil {
+		t.Fatal(err)
+	}
+	if chain, err = Append(chain, v1, v2, now.Add(2*time.Minute), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(chain.Entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(chain.Entries))
+	}
+	first, second := chain.Entries[0], chain.Entries[1]
+	if first.PreviousHash != "" || first.Added != 1 || first.Removed != 0 {
+		t.Errorf("first entry = %+v", first)
+	}
+	if second.PreviousHash != first.Hash || second.Added != 2 || second.Removed != 1 {
+		t.Errorf("second entry = %+v", second)
+	}
+	if err := Verify(v2, chain, ""); err != nil {
+		t.Errorf("Verify() = %v", err)
+	}
+}
+
+func TestAppend_HistoryLimit(t *testing.T) {
+	now := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	var chain *audiciav1alpha1.IntegrityStatus
+	var rules []audiciav1alpha1.ObservedRule
+	for i, verb := range []string{"get", "list", "watch", "create"} {
+		rules = append(rules, rule("pods", verb))
+		var err error
+		if chain, err = Append(chain, nil, rules, now.Add(time.Duration(i)*time.Minute), 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(chain.Entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(chain.Entries))
+	}
+	if err := Verify(rules, chain, ""); err != nil {
+		t.Errorf("Verify() after trimming = %v", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	v1 := []audiciav1alpha1.ObservedRule{rule("pods", "get")}
+	v2 := []audiciav1alpha1.ObservedRule{rule("pods", "get"), rule("secrets", "get")}
+	build := func() *audiciav1alpha1.IntegrityStatus {
+		chain, _ := Append(nil, nil, v1, now, 0)
+		chain, _ = Append(chain, v1, v2, now.Add(time.Minute), 0)
+		return chain
+	}
+
+	if err := Verify(v2, nil, ""); !errors.Is(err, ErrNoChain) {
+		t.Errorf("Verify() without chain = %v, want ErrNoChain", err)
+	}
+
+	edited := []audiciav1alpha1.ObservedRule{rule("pods", "get")}
+	if err := Verify(edited, build(), ""); err == nil {
+		t.Error("expected edited rules to fail verification")
+	}
+
+	rewritten := build()
+	rewritten.Entries[0].RulesHash = rewritten.Entries[1].RulesHash
+	if err := Verify(v2, rewritten, ""); err == nil {
+		t.Error("expected a rewritten entry to fail verification")
+	}
+
+	chain := build()
+	if err := Verify(v2, chain, chain.Entries[0].Hash); err != nil {
+		t.Errorf("Verify() with anchor = %v", err)
+	}
+	if err := Verify(v2, chain, "0123"); err == nil {
+		t.Error("expected unknown anchor to fail verification")
+	}
+}
@@ -0,0 +1,14 @@
+package metrics
+
+// ObserveCompliance records the compliance of a report in ComplianceScore
+// and SensitiveExcessCount.
+func ObserveCompliance(namespace, report, subjectKind string, score int32, sensitiveExcess int) {
+	ComplianceScore.WithLabelValues(report, namespace, subjectKind).Set(float64(score))
+	SensitiveExcessCount.WithLabelValues(report, namespace, subjectKind).Set(float64(sensitiveExcess))
+}
+
+// ForgetCompliance removes the compliance series of a deleted report.
+func ForgetCompliance(namespace, report, subjectKind string) {
+	ComplianceScore.DeleteLabelValues(report, namespace, subjectKind)
+	SensitiveExcessCount.DeleteLabelValues(report, namespace, subjectKind)
+}
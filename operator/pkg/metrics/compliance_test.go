@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestObserveCompliance(t *testing.T) {
+	ObserveCompliance("team-a", "report-alice", "User", 42, 3)
+
+	var m dto.Metric
+	if err := ComplianceScore.WithLabelValues("report-alice", "team-a", "User").Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.GetGauge().GetValue(); got != 42 {
+		t.Errorf("compliance_score = %v, want 42", got)
+	}
+	if err := SensitiveExcessCount.WithLabelValues("report-alice", "team-a", "User").Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.GetGauge().GetValue(); got != 3 {
+		t.Errorf("sensitive_excess_count = %v, want 3", got)
+	}
+
+	ForgetCompliance("team-a", "report-alice", "User")
+	if ComplianceScore.DeleteLabelValues("report-alice", "team-a", "User") {
+		t.Error("expected the compliance_score series to be removed")
+	}
+	if SensitiveExcessCount.DeleteLabelValues("report-alice", "team-a", "User") {
+		t.Error("expected the sensitive_excess_count series to be removed")
+	}
+}
@@ -26,6 +26,38 @@ var (
 		[]string{"filter_rule"},
 	)
 
+	// EventsCollapsedTotal is the number of events summarised into housekeeping presets.
+	EventsCollapsedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "events_collapsed_total",
+			Help:      "Events summarised into housekeeping preset rules.",
+		},
+		[]string{"preset"},
+	)
+
+	// EventsByVerbTotal is the number of ingested events per verb class.
+	EventsByVerbTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "events_by_verb_total",
+			Help:      "Ingested audit events by verb class (read, write, delete, other).",
+		},
+		[]string{"source", "verb_class"},
+	)
+
+	// EventsByResourceTotal is the number of ingested events per resource.
+	// The resource label set is bounded to the first resources seen; see
+	// ResourceLabel.
+	EventsByResourceTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "events_by_resource_total",
+			Help:      "Ingested audit events by resource, bounded to the first resources seen.",
+		},
+		[]string{"source", "resource"},
+	)
+
 	// RulesGeneratedTotal is the total number of unique rules generated.
 	RulesGeneratedTotal = prometheus.NewCounter(
 		prometheus.CounterOpts{
@@ -44,6 +76,109 @@ var (
 		},
 	)
 
+	// ReportsExpiredTotal is the number of reports expired by spec.limits.reportTTLDays.
+	ReportsExpiredTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "reports_expired_total",
+			Help:      "Number of AudiciaReports expired after their subject went inactive.",
+		},
+		[]string{"action"},
+	)
+
+	// SubjectsEvictedTotal is the number of subjects evicted by
+	// spec.limits.maxSubjects.
+	SubjectsEvictedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "subjects_evicted_total",
+			Help:      "Number of subjects evicted from memory to stay within spec.limits.maxSubjects.",
+		},
+		[]string{"source"},
+	)
+
+	// BreakGlassActivityTotal is the number of flushes that found new
+	// activity of a break-glass identity.
+	BreakGlassActivityTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "break_glass_activity_total",
+			Help:      "Number of times new activity of a break-glass identity was reported.",
+		},
+		[]string{"subject"},
+	)
+
+	// PolicySinkCommitsTotal is the number of commits pushed by policy sinks.
+	PolicySinkCommitsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "policy_sink_commits_total",
+			Help:      "Total number of policy commits pushed to Git repositories.",
+		},
+	)
+
+	// PolicySinkErrorsTotal is the number of failed policy sink publications.
+	PolicySinkErrorsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "policy_sink_errors_total",
+			Help:      "Total number of failed attempts to publish policies to a policy sink.",
+		},
+	)
+
+	// NotificationsTotal is the number of compliance notifications by sink
+	// and result (sent, failed, dropped).
+	NotificationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "notifications_total",
+			Help:      "Compliance change notifications by sink and result.",
+		},
+		[]string{"sink", "result"},
+	)
+
+	// RuleStreamClients is the number of connected rule stream clients.
+	RuleStreamClients = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "audicia",
+			Name:      "rule_stream_clients",
+			Help:      "Number of clients connected to the gRPC rule stream.",
+		},
+	)
+
+	// RuleStreamObservationsTotal is the number of observations offered to
+	// rule stream clients and the event tap, by result (sent, dropped).
+	RuleStreamObservationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "rule_stream_observations_total",
+			Help:      "Rule observations offered to gRPC rule stream clients and the event tap, by result.",
+		},
+		[]string{"result"},
+	)
+
+	// WebhookThrottledTotal is the number of webhook requests rejected with
+	// 429, by source and scope (global, client, backpressure).
+	WebhookThrottledTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "webhook_throttled_total",
+			Help:      "Webhook requests rejected with 429, by source and the exhausted limit.",
+		},
+		[]string{"source", "scope"},
+	)
+
+	// EventTapRecordsTotal is the number of records the event tap wrote,
+	// by result (written, failed).
+	EventTapRecordsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "event_tap_records_total",
+			Help:      "Records written to the JSON Lines event tap, by result.",
+		},
+		[]string{"result"},
+	)
+
 	// PoliciesUpdatedTotal is the total number of AudiciaPolicy updates.
 	PoliciesUpdatedTotal = prometheus.NewCounter(
 		prometheus.CounterOpts{
@@ -73,6 +208,16 @@ var (
 		[]string{"source"},
 	)
 
+	// IngestionGapSecondsTotal is the estimated time without audit observation.
+	IngestionGapSecondsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "ingestion_gap_seconds_total",
+			Help:      "Estimated seconds of audit activity missed due to ingestion gaps.",
+		},
+		[]string{"source"},
+	)
+
 	// ReportRulesCount is the number of rules in each report.
 	ReportRulesCount = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -83,6 +228,37 @@ var (
 		[]string{"report_name"},
 	)
 
+	// ComplianceScore is the compliance score (0-100) of each report.
+	ComplianceScore = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "audicia",
+			Name:      "compliance_score",
+			Help:      "Compliance score (0-100) of each report's subject.",
+		},
+		[]string{"report", "namespace", "subject_kind"},
+	)
+
+	// SensitiveExcessCount is the number of excess grants on sensitive
+	// resources of each report.
+	SensitiveExcessCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "audicia",
+			Name:      "sensitive_excess_count",
+			Help:      "Number of excess RBAC grants on sensitive resources of each report's subject.",
+		},
+		[]string{"report", "namespace", "subject_kind"},
+	)
+
+	// ComplianceEvaluationsTotal is the number of deferred compliance evaluations.
+	ComplianceEvaluationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "compliance_evaluations_total",
+			Help:      "Compliance evaluations performed by compliance workers.",
+		},
+		[]string{"result"},
+	)
+
 	// ReconcileErrorsTotal is the total number of controller reconciliation errors.
 	ReconcileErrorsTotal = prometheus.NewCounter(
 		prometheus.CounterOpts{
@@ -142,23 +318,149 @@ var (
 		},
 		[]string{"provider"},
 	)
+
+	// CloudEventsRejectedTotal is the number of cloud audit events dropped
+	// by strict cluster identity enforcement.
+	CloudEventsRejectedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "cloud_events_rejected_total",
+			Help:      "Cloud audit events dropped because they were not verified to come from this cluster.",
+		},
+		[]string{"provider", "reason"},
+	)
+
+	// IngestorBytesReadTotal is the number of bytes file and webhook
+	// ingestors read.
+	IngestorBytesReadTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "ingestor_bytes_read_total",
+			Help:      "Bytes of audit log read by file and webhook ingestors.",
+		},
+		[]string{"source"},
+	)
+
+	// IngestorLinesParsedTotal is the number of audit log lines or webhook
+	// payloads parsed.
+	IngestorLinesParsedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "ingestor_lines_parsed_total",
+			Help:      "Audit log lines (file) or request bodies (webhook) parsed successfully.",
+		},
+		[]string{"source"},
+	)
+
+	// IngestorParseFailuresTotal is the number of audit log lines or
+	// webhook payloads that could not be parsed.
+	IngestorParseFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "ingestor_parse_failures_total",
+			Help:      "Audit log lines (file) or request bodies (webhook) that failed to parse.",
+		},
+		[]string{"source"},
+	)
+
+	// IngestorEventsEmittedTotal is the number of events file and webhook
+	// ingestors handed to the pipeline.
+	IngestorEventsEmittedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "audicia",
+			Name:      "ingestor_events_emitted_total",
+			Help:      "Audit events handed to the pipeline by file and webhook ingestors.",
+		},
+		[]string{"source"},
+	)
+
+	// SourceUnreadable is 1 while the last probe of a file source's audit
+	// log failed and 0 once it succeeds.
+	SourceUnreadable = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "audicia",
+			Name:      "source_unreadable",
+			Help:      "1 if the audit log of a file source is missing or unreadable, else 0.",
+		},
+		[]string{"source"},
+	)
+
+	// CRDSchemaCompatible is 1 while the installed CRD of an Audicia resource
+	// version serves every field the operator writes, 0 while it lacks some,
+	// which keeps ingestion pipelines from starting, and -1 while the
+	// operator may not read the CRD.
+	CRDSchemaCompatible = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "audicia",
+			Name:      "crd_schema_compatible",
+			Help:      "1 if the installed CRD of an Audicia resource version is compatible with the operator, 0 if not, -1 if the operator may not read it.",
+		},
+		[]string{"kind", "version"},
+	)
+
+	// StorageBytes is the estimated etcd storage of Audicia objects, by kind
+	// and namespace.
+	StorageBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "audicia",
+			Name:      "storage_bytes",
+			Help:      "Estimated etcd storage used by Audicia objects (serialized size).",
+		},
+		[]string{"kind", "namespace"},
+	)
+
+	// StorageObjects is the number of Audicia objects, by kind and namespace.
+	StorageObjects = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "audicia",
+			Name:      "storage_objects",
+			Help:      "Number of Audicia objects counted by the storage estimate.",
+		},
+		[]string{"kind", "namespace"},
+	)
 )
 
 func init() {
 	metrics.Registry.MustRegister(
 		EventsProcessedTotal,
 		EventsFilteredTotal,
+		EventsCollapsedTotal,
+		EventsByVerbTotal,
+		EventsByResourceTotal,
 		RulesGeneratedTotal,
 		ReportsUpdatedTotal,
+		ReportsExpiredTotal,
+		SubjectsEvictedTotal,
+		BreakGlassActivityTotal,
 		PoliciesUpdatedTotal,
+		PolicySinkCommitsTotal,
+		PolicySinkErrorsTotal,
+		NotificationsTotal,
+		RuleStreamClients,
+		RuleStreamObservationsTotal,
+		EventTapRecordsTotal,
+		WebhookThrottledTotal,
 		PipelineLatencySeconds,
 		CheckpointLagSeconds,
+		IngestionGapSecondsTotal,
 		ReportRulesCount,
+		ComplianceScore,
+		SensitiveExcessCount,
+		ComplianceEvaluationsTotal,
 		ReconcileErrorsTotal,
 		CloudMessagesReceivedTotal,
 		CloudMessagesAckedTotal,
 		CloudReceiveErrorsTotal,
 		CloudLagSeconds,
 		CloudEnvelopeParseErrorsTotal,
+		CloudEventsRejectedTotal,
+		IngestorBytesReadTotal,
+		IngestorLinesParsedTotal,
+		IngestorParseFailuresTotal,
+		IngestorEventsEmittedTotal,
+		SourceUnreadable,
+		CRDSchemaCompatible,
+		StorageBytes,
+		StorageObjects,
 	)
 }
@@ -0,0 +1,254 @@
+package metrics
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/client-go/rest"
+	certutil "k8s.io/client-go/util/cert"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/felixnotka/audicia/operator/pkg/bearer"
+)
+
+var servingLog = ctrl.Log.WithName("metrics")
+
+// metricsPath is where the metrics servers serve metrics.
+const metricsPath = "/metrics"
+
+// reportCollectors are the metrics labeled by report or subject, which
+// expose the security posture of individual subjects.
+var reportCollectors = []prometheus.Collector{
+	ReportRulesCount,
+	ComplianceScore,
+	SensitiveExcessCount,
+	BreakGlassActivityTotal,
+}
+
+// SeparateReportMetrics moves the report-level metrics from the
+// controller-runtime registry to a registry of their own, served by a
+// ReportServer, and returns it.
+func SeparateReportMetrics() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	for _, c := range reportCollectors {
+		metrics.Registry.Unregister(c)
+		reg.MustRegister(c)
+	}
+	return reg
+}
+
+// Serving configures TLS and client authentication of the metrics
+// endpoints.
+type Serving struct {
+	// Secure serves over TLS, with the tls.crt and tls.key in CertDir or,
+	// without them, a self-signed certificate.
+	Secure  bool
+	CertDir string
+
+	// ClientCAFile, when set, requires client certificates signed by one of
+	// its CAs.
+	ClientCAFile string
+
+	// TokenFile, when set, holds the bearer token scrapers present. It is
+	// re-read when it changes, so a rotated token takes effect at once.
+	TokenFile string
+}
+
+// Validate rejects client authentication without TLS, which would send
+// tokens in the clear.
+func (s Serving) Validate() error {
+	if !s.Secure && (s.ClientCAFile != "" || s.TokenFile != "") {
+		return errors.New("metrics authentication requires secure serving")
+	}
+	return nil
+}
+
+// ServerOptions returns the options of the controller-runtime metrics
+// server at bindAddress. Extra handlers of the server share its TLS, but
+// the bearer token in TokenFile guards /metrics only: the extra handlers
+// authenticate their callers themselves, with TokenReview.
+func (s Serving) ServerOptions(bindAddress string) (metricsserver.Options, error) {
+	opts := metricsserver.Options{BindAddress: bindAddress}
+	if !s.Secure {
+		return opts, nil
+	}
+	clientAuth, err := s.clientAuth()
+	if err != nil {
+		return opts, err
+	}
+	opts.SecureServing = true
+	opts.CertDir = s.CertDir
+	opts.TLSOpts = []func(*tls.Config){clientAuth}
+	if s.TokenFile != "" {
+		opts.FilterProvider = func(*rest.Config, *http.Client) (metricsserver.Filter, error) {
+			return func(_ logr.Logger, handler http.Handler) (http.Handler, error) {
+				return s.authenticateMetrics(handler), nil
+			}, nil
+		}
+	}
+	return opts, nil
+}
+
+// clientAuth returns the TLS option enforcing TLS 1.2 and, with
+// ClientCAFile, verified client certificates.
+func (s Serving) clientAuth() (func(*tls.Config), error) {
+	var pool *x509.CertPool
+	if s.ClientCAFile != "" {
+		pem, err := os.ReadFile(s.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading metrics client CA: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in metrics client CA %s", s.ClientCAFile)
+		}
+	}
+	return func(cfg *tls.Config) {
+		cfg.MinVersion = tls.VersionTLS12
+		if pool != nil {
+			cfg.ClientCAs = pool
+			cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}, nil
+}
+
+// authenticateMetrics requires the bearer token in TokenFile on /metrics.
+// controller-runtime wraps every extra handler in the same filter, so other
+// paths pass through to the handler's own authentication.
+func (s Serving) authenticateMetrics(handler http.Handler) http.Handler {
+	authenticated := s.authenticate(handler)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != metricsPath {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		authenticated.ServeHTTP(w, r)
+	})
+}
+
+// authenticate rejects requests without the bearer token in TokenFile.
+func (s Serving) authenticate(handler http.Handler) http.Handler {
+	if s.TokenFile == "" {
+		return handler
+	}
+	tokens := bearer.NewTokenFile(s.TokenFile)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expected, err := tokens.Token()
+		if err != nil {
+			servingLog.Error(err, "reading metrics token", "file", s.TokenFile)
+			http.Error(w, "token unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || !bearer.Equal(token, expected) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// listen opens the listener at addr, over TLS when Secure.
+func (s Serving) listen(ctx context.Context, addr string) (net.Listener, error) {
+	if !s.Secure {
+		return net.Listen("tcp", addr)
+	}
+	clientAuth, err := s.clientAuth()
+	if err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{NextProtos: []string{"h2", "http/1.1"}}
+	clientAuth(cfg)
+
+	certPath, keyPath := filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key")
+	if _, err := os.Stat(certPath); s.CertDir != "" && err == nil {
+		watcher, err := certwatcher.New(certPath, keyPath)
+		if err != nil {
+			return nil, err
+		}
+		cfg.GetCertificate = watcher.GetCertificate
+		go func() {
+			if err := watcher.Start(ctx); err != nil {
+				servingLog.Error(err, "certificate watcher error")
+			}
+		}()
+	} else {
+		cert, key, err := certutil.GenerateSelfSignedCertKey("localhost", []net.IP{{127, 0, 0, 1}}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("generating self-signed metrics certificate: %w", err)
+		}
+		pair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{pair}
+	}
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return tls.NewListener(l, cfg), nil
+}
+
+// ReportServer serves the report-level metrics on a port of their own, so
+// access to the per-subject security posture can be restricted separately
+// from the operational metrics. It is a manager Runnable.
+type ReportServer struct {
+	Addr     string
+	Serving  Serving
+	Gatherer prometheus.Gatherer
+}
+
+// NeedLeaderElection implements LeaderElectionRunnable: every replica
+// serves the metrics it records.
+func (s *ReportServer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves /metrics until ctx is done.
+func (s *ReportServer) Start(ctx context.Context) error {
+	listener, err := s.Serving.listen(ctx, s.Addr)
+	if err != nil {
+		return fmt.Errorf("listening for report metrics: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, s.Serving.authenticate(promhttp.HandlerFor(s.Gatherer, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.HTTPErrorOnError,
+	})))
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			servingLog.Error(err, "error shutting down report metrics server")
+		}
+	}()
+
+	servingLog.Info("serving report metrics", "address", listener.Addr().String(), "secure", s.Serving.Secure)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
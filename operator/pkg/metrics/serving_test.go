@@ -0,0 +1,244 @@
+package metrics
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func TestServing_Validate(t *testing.T) {
+	if err := (Serving{TokenFile: "/token"}).Validate(); err == nil {
+		t.Error("Validate() accepted a token without TLS")
+	}
+	if err := (Serving{ClientCAFile: "/ca.crt"}).Validate(); err == nil {
+		t.Error("Validate() accepted mTLS without TLS")
+	}
+	if err := (Serving{Secure: true, TokenFile: "/token"}).Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestServing_Authenticate(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := Serving{Secure: true, TokenFile: tokenFile}.authenticate(ok)
+
+	tests := map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"s3cret":        http.StatusUnauthorized,
+		"Bearer s3cret": http.StatusOK,
+	}
+	for header, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("Authorization %q = %d, want %d", header, rec.Code, want)
+		}
+	}
+
+	if err := os.Remove(tokenFile); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("without token file = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+// writeClientCA writes a self-signed CA to dir and returns its path and a
+// client certificate: the CA itself, which may also authenticate clients.
+func writeClientCA(t *testing.T, dir string) (string, tls.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "scraper"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "ca.crt")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path, tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestReportServer_MutualTLS(t *testing.T) {
+	caFile, clientCert := writeClientCA(t, t.TempDir())
+	serving := Serving{Secure: true, ClientCAFile: caFile}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	listener, err := serving.listen(ctx, "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	reg := separateForTest(t)
+	ComplianceScore.WithLabelValues("report-alice", "prod", "User").Set(42)
+	server := &ReportServer{Addr: addr, Serving: serving, Gatherer: reg}
+	done := make(chan error, 1)
+	go func() { done <- server.Start(ctx) }()
+
+	get := func(certs ...tls.Certificate) (*http.Response, error) {
+		client := &http.Client{
+			Timeout: 5 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true, //nolint:gosec // self-signed test certificate
+				Certificates:       certs,
+			}},
+		}
+		return client.Get("https://" + addr + "/metrics")
+	}
+
+	// The server starts listening asynchronously.
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		if resp, err = get(clientCert); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("GET with client certificate: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET with client certificate = %d, want 200", resp.StatusCode)
+	}
+
+	if resp, err := get(); err == nil {
+		_ = resp.Body.Close()
+		t.Error("GET without client certificate succeeded")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Start() = %v", err)
+	}
+}
+
+func TestSeparateReportMetrics(t *testing.T) {
+	reg := separateForTest(t)
+	SensitiveExcessCount.WithLabelValues("report-alice", "prod", "User").Set(3)
+
+	if n, err := testutil.GatherAndCount(reg, "audicia_sensitive_excess_count"); err != nil || n != 1 {
+		t.Errorf("report registry has %d sensitive excess series (%v), want 1", n, err)
+	}
+	if n, _ := testutil.GatherAndCount(metrics.Registry, "audicia_sensitive_excess_count"); n != 0 {
+		t.Errorf("controller-runtime registry still has %d sensitive excess series", n)
+	}
+	if n, _ := testutil.GatherAndCount(reg, "audicia_events_processed_total"); n != 0 {
+		t.Error("report registry serves operational metrics")
+	}
+}
+
+// separateForTest separates the report-level metrics and moves
+// them back to the controller-runtime registry when the test ends.
+func separateForTest(t *testing.T) *prometheus.Registry {
+	t.Helper()
+	reg := SeparateReportMetrics()
+	t.Cleanup(func() {
+		for _, c := range reportCollectors {
+			reg.Unregister(c)
+			metrics.Registry.MustRegister(c)
+		}
+		ComplianceScore.Reset()
+		SensitiveExcessCount.Reset()
+	})
+	return reg
+}
+
+func TestServing_TokenGuardsMetricsOnly(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("s3cret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	opts, err := Serving{Secure: true, TokenFile: tokenFile}.ServerOptions(":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter, err := opts.FilterProvider(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An extra handler that authenticates callers with a Kubernetes token
+	// of its own, as the self-test and report manifest endpoints do.
+	extra, err := filter(logr.Discard(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer kube-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/selftest", nil)
+	req.Header.Set("Authorization", "Bearer kube-token")
+	rec := httptest.NewRecorder()
+	extra.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("extra handler with its own token = %d, want 200", rec.Code)
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	metricsHandler, err := filter(logr.Discard(), ok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for header, want := range map[string]int{
+		"Bearer kube-token": http.StatusUnauthorized,
+		"Bearer s3cret":     http.StatusOK,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Authorization", header)
+		rec := httptest.NewRecorder()
+		metricsHandler.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("/metrics with %q = %d, want %d", header, rec.Code, want)
+		}
+	}
+}
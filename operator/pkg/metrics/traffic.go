@@ -0,0 +1,78 @@
+package metrics
+
+import "sync"
+
+// MaxResourceLabels bounds the distinct resource label values of
+// EventsByResourceTotal. Resources seen after the limit is reached are
+// counted under OtherResourceLabel.
+const MaxResourceLabels = 50
+
+// OtherResourceLabel is the resource label for resources beyond the limit.
+const OtherResourceLabel = "other"
+
+// NonResourceLabel is the resource label for non-resource URL requests.
+const NonResourceLabel = "nonresource"
+
+// resourceLabels holds the resources that have their own label. It only
+// grows until MaxResourceLabels, so the per-event lookup takes the read lock.
+var resourceLabels = struct {
+	sync.RWMutex
+	seen map[string]bool
+}{seen: make(map[string]bool)}
+
+// VerbClass groups an audit verb into read, write, delete or other.
+func VerbClass(verb string) string {
+	switch verb {
+	case "get", "list", "watch":
+		return "read"
+	case "create", "update", "patch":
+		return "write"
+	case "delete", "deletecollection":
+		return "delete"
+	default:
+		return "other"
+	}
+}
+
+// ResourceLabel returns the label for a resource in "resource.group" form
+// (core resources have no group suffix). The first MaxResourceLabels distinct
+// resources seen since the operator started keep their own label, whatever
+// their later traffic; resources first seen after that map to
+// OtherResourceLabel so that CRD-heavy clusters cannot grow the series count
+// without bound.
+func ResourceLabel(resource, apiGroup string) string {
+	if resource == "" {
+		return NonResourceLabel
+	}
+	label := resource
+	if apiGroup != "" {
+		label = resource + "." + apiGroup
+	}
+
+	resourceLabels.RLock()
+	seen, full := resourceLabels.seen[label], len(resourceLabels.seen) >= MaxResourceLabels
+	resourceLabels.RUnlock()
+	if seen {
+		return label
+	}
+	if full {
+		return OtherResourceLabel
+	}
+
+	resourceLabels.Lock()
+	defer resourceLabels.Unlock()
+	if resourceLabels.seen[label] {
+		return label
+	}
+	if len(resourceLabels.seen) >= MaxResourceLabels {
+		return OtherResourceLabel
+	}
+	resourceLabels.seen[label] = true
+	return label
+}
+
+// ObserveEventTraffic counts an ingested event by verb class and resource.
+func ObserveEventTraffic(source, verb, resource, apiGroup string) {
+	EventsByVerbTotal.WithLabelValues(source, VerbClass(verb)).Inc()
+	EventsByResourceTotal.WithLabelValues(source, ResourceLabel(resource, apiGroup)).Inc()
+}
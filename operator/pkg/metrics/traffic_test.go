@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestVerbClass(t *testing.T) {
+	tests := map[string]string{
+		"get":              "read",
+		"watch":            "read",
+		"patch":            "write",
+		"deletecollection": "delete",
+		"impersonate":      "other",
+	}
+	for verb, want := range tests {
+		if got := VerbClass(verb); got != want {
+			t.Errorf("VerbClass(%q) = %q, want %q", verb, got, want)
+		}
+	}
+}
+
+func TestResourceLabel_Bounded(t *testing.T) {
+	if got := ResourceLabel("", ""); got != NonResourceLabel {
+		t.Errorf("non-resource label = %q", got)
+	}
+	if got := ResourceLabel("deployments", "apps"); got != "deployments.apps" {
+		t.Errorf("grouped label = %q", got)
+	}
+
+	for i := 0; i < MaxResourceLabels; i++ {
+		ResourceLabel(fmt.Sprintf("widgets%d", i), "example.com")
+	}
+	if got := ResourceLabel("gadgets", "example.com"); got != OtherResourceLabel {
+		t.Errorf("label beyond limit = %q, want %q", got, OtherResourceLabel)
+	}
+	if got := ResourceLabel("deployments", "apps"); got != "deployments.apps" {
+		t.Errorf("previously seen label = %q, want it kept", got)
+	}
+}
+
+func TestResourceLabel_ConcurrentStaysBounded(t *testing.T) {
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 2 * MaxResourceLabels {
+				ResourceLabel(fmt.Sprintf("things%d-%d", g, i), "example.com")
+			}
+		}()
+	}
+	wg.Wait()
+
+	resourceLabels.RLock()
+	defer resourceLabels.RUnlock()
+	if len(resourceLabels.seen) > MaxResourceLabels {
+		t.Errorf("labelled %d resources, want at most %d", len(resourceLabels.seen), MaxResourceLabels)
+	}
+}
@@ -0,0 +1,90 @@
+package normalizer
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// maxResolvedAliases bounds the per-username resolution cache. When it is
+// full the cache is reset; real clusters have far fewer distinct usernames.
+const maxResolvedAliases = 4096
+
+// compiledAlias is a pre-compiled subject alias.
+type compiledAlias struct {
+	pattern *regexp.Regexp
+	subject audiciav1alpha1.Subject
+}
+
+// SubjectAliases resolves raw usernames to logical subjects. First match wins.
+// A nil *SubjectAliases resolves nothing.
+type SubjectAliases struct {
+	aliases []compiledAlias
+
+	// resolved caches Resolve results by username, so the regexes run and
+	// capture groups are expanded once per user rather than once per event.
+	mu       sync.Mutex
+	resolved map[string]resolvedAlias
+}
+
+// resolvedAlias is a cached Resolve result.
+type resolvedAlias struct {
+	subject audiciav1alpha1.Subject
+	ok      bool
+}
+
+// NewSubjectAliases compiles the alias rules.
+func NewSubjectAliases(rules []audiciav1alpha1.SubjectAlias) (*SubjectAliases, error) {
+	compiled := make([]compiledAlias, 0, len(rules))
+	for _, r := range rules {
+		re, err := regexp.Compile(r.UserPattern)
+		if err != nil {
+			return nil, fmt.Errorf("subject alias %q: %w", r.UserPattern, err)
+		}
+		if r.Subject.Name == "" {
+			return nil, fmt.Errorf("subject alias %q: subject name is required", r.UserPattern)
+		}
+		compiled = append(compiled, compiledAlias{pattern: re, subject: r.Subject})
+	}
+	return &SubjectAliases{aliases: compiled, resolved: make(map[string]resolvedAlias)}, nil
+}
+
+// Resolve returns the logical subject for username, expanding capture-group
+// references in the subject's name and namespace. It returns false if no
+// alias matches or the expanded name is empty.
+func (a *SubjectAliases) Resolve(username string) (audiciav1alpha1.Subject, bool) {
+	if a == nil || len(a.aliases) == 0 {
+		return audiciav1alpha1.Subject{}, false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if r, ok := a.resolved[username]; ok {
+		return r.subject, r.ok
+	}
+	subject, ok := a.resolve(username)
+	if len(a.resolved) >= maxResolvedAliases {
+		clear(a.resolved)
+	}
+	a.resolved[username] = resolvedAlias{subject: subject, ok: ok}
+	return subject, ok
+}
+
+// resolve matches username against the aliases in order.
+func (a *SubjectAliases) resolve(username string) (audiciav1alpha1.Subject, bool) {
+	for _, alias := range a.aliases {
+		match := alias.pattern.FindStringSubmatchIndex(username)
+		if match == nil {
+			continue
+		}
+		subject := alias.subject
+		subject.Name = string(alias.pattern.ExpandString(nil, alias.subject.Name, username, match))
+		subject.Namespace = string(alias.pattern.ExpandString(nil, alias.subject.Namespace, username, match))
+		if subject.Name == "" {
+			return audiciav1alpha1.Subject{}, false
+		}
+		return subject, true
+	}
+	return audiciav1alpha1.Subject{}, false
+}
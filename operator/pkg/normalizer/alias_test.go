@@ -0,0 +1,129 @@
+package normalizer
+
+import (
+	"fmt"
+	"testing"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func TestSubjectAliases_Resolve(t *testing.T) {
+	aliases, err := NewSubjectAliases([]audiciav1alpha1.SubjectAlias{
+		{
+			UserPattern: `^(?:cluster-[a-z]+:)?system:serviceaccount:payments:api$`,
+			Subject:     audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Namespace: "payments", Name: "api"},
+		},
+		{
+			UserPattern: `^cluster-[a-z]+:system:serviceaccount:(?P<ns>[^:]+):(?P<name>[^:]+)$`,
+			Subject:     audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Namespace: "${ns}", Name: "${name}"},
+		},
+		{
+			UserPattern: `^oidc:(.+)@example\.com$`,
+			Subject:     audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "$1"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSubjectAliases() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		want     audiciav1alpha1.Subject
+		ok       bool
+	}{
+		{
+			name:     "literal alias from any cluster",
+			username: "cluster-east:system:serviceaccount:payments:api",
+			want:     audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Namespace: "payments", Name: "api"},
+			ok:       true,
+		},
+		{
+			name:     "first match wins",
+			username: "system:serviceaccount:payments:api",
+			want:     audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Namespace: "payments", Name: "api"},
+			ok:       true,
+		},
+		{
+			name:     "named capture groups",
+			username: "cluster-west:system:serviceaccount:shop:cart",
+			want:     audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Namespace: "shop", Name: "cart"},
+			ok:       true,
+		},
+		{
+			name:     "numbered capture group",
+			username: "oidc:alice@example.com",
+			want:     audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "alice"},
+			ok:       true,
+		},
+		{
+			name:     "no match",
+			username: "bob",
+			ok:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := aliases.Resolve(tt.username)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Resolve(%q) = %+v, %v; want %+v, %v", tt.username, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSubjectAliases_NilResolvesNothing(t *testing.T) {
+	var aliases *SubjectAliases
+	if _, ok := aliases.Resolve("anyone"); ok {
+		t.Error("expected nil aliases to resolve nothing")
+	}
+}
+
+func TestSubjectAliases_CachesResolution(t *testing.T) {
+	a, err := NewSubjectAliases([]audiciav1alpha1.SubjectAlias{{
+		UserPattern: "^oidc:(.+)$",
+		Subject:     audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "$1"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if s, ok := a.Resolve("oidc:alice"); !ok || s.Name != "alice" {
+			t.Errorf("Resolve(oidc:alice) = %+v, %v", s, ok)
+		}
+		if _, ok := a.Resolve("bob"); ok {
+			t.Error("bob should not resolve")
+		}
+	}
+	if len(a.resolved) != 2 {
+		t.Errorf("expected 2 cached results, got %d", len(a.resolved))
+	}
+
+	for i := range maxResolvedAliases + 10 {
+		a.Resolve(fmt.Sprintf("oidc:user-%d", i))
+	}
+	if len(a.resolved) > maxResolvedAliases {
+		t.Errorf("cache grew to %d entries, limit %d", len(a.resolved), maxResolvedAliases)
+	}
+	if s, ok := a.Resolve("oidc:alice"); !ok || s.Name != "alice" {
+		t.Errorf("Resolve after reset = %+v, %v", s, ok)
+	}
+}
+
+func TestNewSubjectAliases_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		alias audiciav1alpha1.SubjectAlias
+	}{
+		{"invalid regex", audiciav1alpha1.SubjectAlias{UserPattern: "(", Subject: audiciav1alpha1.Subject{Name: "x"}}},
+		{"empty name", audiciav1alpha1.SubjectAlias{UserPattern: "x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSubjectAliases([]audiciav1alpha1.SubjectAlias{tt.alias}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -11,11 +11,33 @@ type CanonicalRule struct {
 	// Verb is the API verb (e.g., "get", "list", "create").
 	Verb string
 
+	// ResourceName is the name of the targeted object, empty for collection
+	// requests (list, watch, create) and non-resource URLs.
+	ResourceName string
+
 	// NonResourceURL is the non-resource URL (e.g., "/metrics"). Mutually exclusive with Resource.
 	NonResourceURL string
 
 	// Namespace is the target namespace (empty for cluster-scoped).
 	Namespace string
+
+	// Preset is set when the rule was collapsed into a housekeeping preset.
+	Preset string
+
+	// Incomplete is set when the event was at the ResponseStarted or Panic
+	// stage rather than ResponseComplete.
+	Incomplete bool
+
+	// Denied is set when the API server rejected the request (401 or 403).
+	Denied bool
+
+	// AdmissionDenied is set when RBAC allowed the request but admission
+	// control rejected it.
+	AdmissionDenied bool
+
+	// TimestampFallback is set when the event carried no timestamp and the
+	// time it was processed stands in.
+	TimestampFallback bool
 }
 
 // apiGroupMigrations maps deprecated API groups to their stable replacements.
@@ -23,6 +45,22 @@ var apiGroupMigrations = map[string]string{
 	"extensions": "apps",
 }
 
+// websocketSubresources are the subresources clients stream over a
+// websocket upgrade. The upgrade is a GET, so the audit log records "get",
+// but since Kubernetes 1.31 the API server authorizes it as "create", like
+// the SPDY requests it replaces.
+var websocketSubresources = map[string]bool{
+	"pods/exec":        true,
+	"pods/attach":      true,
+	"pods/portforward": true,
+}
+
+// IsWebsocketSubresource reports whether resource ("pods/exec") is reached
+// through a websocket upgrade, so an observed "get" needs "create" as well.
+func IsWebsocketSubresource(resource string) bool {
+	return websocketSubresources[resource]
+}
+
 // NormalizeEvent converts raw audit event fields into a CanonicalRule.
 func NormalizeEvent(resource, subresource, apiGroup, verb, namespace, requestURI string, hasObjectRef bool) CanonicalRule {
 	// Non-resource URLs: objectRef is nil, use requestURI.
@@ -0,0 +1,64 @@
+package normalizer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// GroupTracker derives Group subjects from an event's user.groups.
+// A nil *GroupTracker tracks no groups.
+type GroupTracker struct {
+	patterns          []*regexp.Regexp
+	ignoreSystemUsers bool
+}
+
+// NewGroupTracker compiles the group tracking configuration. It returns nil
+// when group tracking is disabled.
+func NewGroupTracker(cfg *audiciav1alpha1.SubjectTrackingConfig, ignoreSystemUsers bool) (*GroupTracker, error) {
+	if cfg == nil || !cfg.Groups {
+		return nil, nil
+	}
+	t := &GroupTracker{ignoreSystemUsers: ignoreSystemUsers}
+	for _, p := range cfg.GroupPatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("group pattern %q: %w", p, err)
+		}
+		t.patterns = append(t.patterns, re)
+	}
+	return t, nil
+}
+
+// Subjects returns a Group subject for every tracked group.
+func (t *GroupTracker) Subjects(groups []string) []audiciav1alpha1.Subject {
+	if t == nil {
+		return nil
+	}
+	var out []audiciav1alpha1.Subject
+	for _, g := range groups {
+		if t.tracks(g) {
+			out = append(out, audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindGroup, Name: g})
+		}
+	}
+	return out
+}
+
+func (t *GroupTracker) tracks(group string) bool {
+	switch {
+	case group == "" || group == "system:authenticated" || group == "system:unauthenticated":
+		return false
+	case t.ignoreSystemUsers && strings.HasPrefix(group, "system:"):
+		return false
+	case len(t.patterns) == 0:
+		return true
+	}
+	for _, re := range t.patterns {
+		if re.MatchString(group) {
+			return true
+		}
+	}
+	return false
+}
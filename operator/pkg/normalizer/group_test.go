@@ -0,0 +1,73 @@
+package normalizer
+
+import (
+	"reflect"
+	"testing"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func groupNames(subjects []audiciav1alpha1.Subject) []string {
+	var names []string
+	for _, s := range subjects {
+		if s.Kind != audiciav1alpha1.SubjectKindGroup {
+			return []string{"unexpected kind " + string(s.Kind)}
+		}
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func TestGroupTracker_Subjects(t *testing.T) {
+	groups := []string{"system:authenticated", "system:masters", "oidc:devs", "admins"}
+
+	tests := []struct {
+		name              string
+		cfg               *audiciav1alpha1.SubjectTrackingConfig
+		ignoreSystemUsers bool
+		want              []string
+	}{
+		{
+			name: "all groups except authenticated",
+			cfg:  &audiciav1alpha1.SubjectTrackingConfig{Groups: true},
+			want: []string{"system:masters", "oidc:devs", "admins"},
+		},
+		{
+			name:              "system groups skipped with ignoreSystemUsers",
+			cfg:               &audiciav1alpha1.SubjectTrackingConfig{Groups: true},
+			ignoreSystemUsers: true,
+			want:              []string{"oidc:devs", "admins"},
+		},
+		{
+			name: "patterns restrict tracked groups",
+			cfg:  &audiciav1alpha1.SubjectTrackingConfig{Groups: true, GroupPatterns: []string{"^oidc:", "^admins$"}},
+			want: []string{"oidc:devs", "admins"},
+		},
+		{
+			name: "disabled",
+			cfg:  &audiciav1alpha1.SubjectTrackingConfig{GroupPatterns: []string{".*"}},
+		},
+		{
+			name: "nil config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker, err := NewGroupTracker(tt.cfg, tt.ignoreSystemUsers)
+			if err != nil {
+				t.Fatalf("NewGroupTracker() error = %v", err)
+			}
+			if got := groupNames(tracker.Subjects(groups)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Subjects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewGroupTracker_InvalidPattern(t *testing.T) {
+	cfg := &audiciav1alpha1.SubjectTrackingConfig{Groups: true, GroupPatterns: []string{"("}}
+	if _, err := NewGroupTracker(cfg, false); err == nil {
+		t.Error("expected error")
+	}
+}
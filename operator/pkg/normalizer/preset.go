@@ -0,0 +1,61 @@
+package normalizer
+
+import "fmt"
+
+// Housekeeping preset names.
+const (
+	PresetEvents         = "events"
+	PresetLeaderElection = "leader-election"
+)
+
+// housekeepingPreset describes routine controller traffic that is summarised
+// into a single well-known rule per API group and namespace.
+type housekeepingPreset struct {
+	name     string
+	apiGroup string
+	resource string
+	verbs    []string
+}
+
+// housekeepingPresets lists the built-in presets. Verbs are the match set;
+// the collapsed rule carries only those actually observed. Other verbs (e.g.,
+// list/watch on events) are not housekeeping and stay as-is.
+var housekeepingPresets = []housekeepingPreset{
+	{name: PresetEvents, apiGroup: "", resource: "events", verbs: []string{"create", "patch", "update"}},
+	{name: PresetEvents, apiGroup: "events.k8s.io", resource: "events", verbs: []string{"create", "patch", "update"}},
+	{name: PresetLeaderElection, apiGroup: "coordination.k8s.io", resource: "leases", verbs: []string{"create", "get", "update"}},
+}
+
+// PresetsFingerprint describes the built-in presets, so that configuration
+// hashes change when a release changes what the presets cover.
+func PresetsFingerprint() string {
+	return fmt.Sprint(housekeepingPresets)
+}
+
+// CollapseHousekeeping marks rule with its housekeeping preset, if any.
+// Collapsed rules share one aggregation entry regardless of verb.
+func CollapseHousekeeping(rule CanonicalRule) CanonicalRule {
+	if p := matchPreset(rule); p != nil {
+		rule.Preset = p.name
+	}
+	return rule
+}
+
+// matchPreset returns the preset covering the rule's group, resource and verb.
+func matchPreset(rule CanonicalRule) *housekeepingPreset {
+	if rule.NonResourceURL != "" {
+		return nil
+	}
+	for i := range housekeepingPresets {
+		p := &housekeepingPresets[i]
+		if p.apiGroup != rule.APIGroup || p.resource != rule.Resource {
+			continue
+		}
+		for _, v := range p.verbs {
+			if v == rule.Verb {
+				return p
+			}
+		}
+	}
+	return nil
+}
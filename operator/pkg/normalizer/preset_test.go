@@ -0,0 +1,31 @@
+package normalizer
+
+import "testing"
+
+func TestCollapseHousekeeping(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       CanonicalRule
+		wantPreset string
+	}{
+		{"core event create", CanonicalRule{Resource: "events", Verb: "create", Namespace: "a"}, PresetEvents},
+		{"events.k8s.io patch", CanonicalRule{APIGroup: "events.k8s.io", Resource: "events", Verb: "patch"}, PresetEvents},
+		{"event list is not housekeeping", CanonicalRule{Resource: "events", Verb: "list"}, ""},
+		{"lease update", CanonicalRule{APIGroup: "coordination.k8s.io", Resource: "leases", Verb: "update"}, PresetLeaderElection},
+		{"lease delete is not housekeeping", CanonicalRule{APIGroup: "coordination.k8s.io", Resource: "leases", Verb: "delete"}, ""},
+		{"pods untouched", CanonicalRule{Resource: "pods", Verb: "get"}, ""},
+		{"non-resource URL untouched", CanonicalRule{NonResourceURL: "/events", Verb: "get"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CollapseHousekeeping(tt.rule)
+			if got.Preset != tt.wantPreset {
+				t.Errorf("Preset = %q, want %q", got.Preset, tt.wantPreset)
+			}
+			if got.Verb != tt.rule.Verb || got.Resource != tt.rule.Resource || got.APIGroup != tt.rule.APIGroup {
+				t.Errorf("CollapseHousekeeping must not rewrite rule identity: %+v", got)
+			}
+		})
+	}
+}
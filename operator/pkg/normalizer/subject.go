@@ -20,17 +20,19 @@ func NormalizeSubject(username string, ignoreSystemUsers bool) (audiciav1alpha1.
 
 	// Service accounts: system:serviceaccount:<namespace>:<name>
 	if strings.HasPrefix(username, serviceAccountPrefix) {
-		parts := strings.SplitN(strings.TrimPrefix(username, serviceAccountPrefix), ":", 2)
-		if len(parts) == 2 {
-			if parts[1] == "" {
+		// Cut rather than Split: the results share username's memory, so
+		// the per-event hot path does not allocate.
+		namespace, name, ok := strings.Cut(strings.TrimPrefix(username, serviceAccountPrefix), ":")
+		if ok {
+			if name == "" {
 				// Malformed SA with empty name (e.g., "system:serviceaccount:ns:").
 				// Cannot produce a valid report name — skip unconditionally.
 				return audiciav1alpha1.Subject{}, false
 			}
 			return audiciav1alpha1.Subject{
 				Kind:      audiciav1alpha1.SubjectKindServiceAccount,
-				Namespace: parts[0],
-				Name:      parts[1],
+				Namespace: namespace,
+				Name:      name,
 			}, true
 		}
 	}
@@ -0,0 +1,279 @@
+// Package notify delivers changes of AudiciaReports to outbound sinks:
+// compliance changes to a generic JSON webhook and Slack incoming webhooks,
+// and material report changes to the signed webhooks of a source.
+// Notifications are queued and sent by a single worker, so a slow or
+// unreachable receiver never stalls the pipeline that produced them.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+)
+
+// Kind is the cause of a notification.
+type Kind string
+
+const (
+	// KindSeverityChanged is sent when a report's compliance severity changes,
+	// in either direction.
+	KindSeverityChanged Kind = "SeverityChanged"
+
+	// KindSensitiveExcess is sent when excess grants on sensitive resources
+	// are detected that the previous evaluation did not report.
+	KindSensitiveExcess Kind = "SensitiveExcess"
+)
+
+const (
+	// queueSize bounds the notifications waiting to be sent. Further ones
+	// are dropped and counted.
+	queueSize = 256
+
+	// sendTimeout bounds one delivery attempt.
+	sendTimeout = 10 * time.Second
+
+	// sendAttempts is the number of delivery attempts per sink, spaced by a
+	// doubling backoff starting at retryBackoff.
+	sendAttempts = 3
+)
+
+// retryBackoff is the delay before the second delivery attempt.
+var retryBackoff = time.Second
+
+// Notification describes one compliance change of a report.
+type Notification struct {
+	Kind             Kind                               `json:"kind"`
+	Namespace        string                             `json:"namespace"`
+	Report           string                             `json:"report"`
+	Subject          audiciav1alpha1.Subject            `json:"subject"`
+	PreviousSeverity audiciav1alpha1.ComplianceSeverity `json:"previousSeverity,omitempty"`
+	Severity         audiciav1alpha1.ComplianceSeverity `json:"severity"`
+	Score            int32                              `json:"score"`
+	ExcessCount      int32                              `json:"excessCount"`
+	UncoveredCount   int32                              `json:"uncoveredCount"`
+
+	// SensitiveExcess lists the newly detected sensitive excess grants for
+	// KindSensitiveExcess, and all of them otherwise.
+	SensitiveExcess []string  `json:"sensitiveExcess,omitempty"`
+	Time            time.Time `json:"time"`
+}
+
+// Summary returns a one-line human-readable description.
+func (n Notification) Summary() string {
+	who := fmt.Sprintf("%s %s (report %s/%s)", n.Subject.Kind, n.Subject.Name, n.Namespace, n.Report)
+	switch n.Kind {
+	case KindSensitiveExcess:
+		return fmt.Sprintf("Excess sensitive permissions detected for %s: %s", who, strings.Join(n.SensitiveExcess, ", "))
+	default:
+		return fmt.Sprintf("Compliance of %s changed from %s to %s (score=%d, excess=%d, uncovered=%d)",
+			who, n.PreviousSeverity, n.Severity, n.Score, n.ExcessCount, n.UncoveredCount)
+	}
+}
+
+// Changes returns the notifications for a report whose compliance was just
+// evaluated, given the compliance it had before. A report evaluated for the
+// first time has no severity transition, but its sensitive excess counts as
+// newly detected.
+func Changes(report *audiciav1alpha1.AudiciaReport, previous *audiciav1alpha1.ComplianceReport) []Notification {
+	current := report.Status.Compliance
+	if current == nil {
+		return nil
+	}
+	base := Notification{
+		Namespace:       report.Namespace,
+		Report:          report.Name,
+		Subject:         report.Spec.Subject,
+		Severity:        current.Severity,
+		Score:           current.Score,
+		ExcessCount:     current.ExcessCount,
+		UncoveredCount:  current.UncoveredCount,
+		SensitiveExcess: current.SensitiveExcess,
+		Time:            time.Now(),
+	}
+	var out []Notification
+	if previous != nil && previous.Severity != "" && previous.Severity != current.Severity {
+		n := base
+		n.Kind = KindSeverityChanged
+		n.PreviousSeverity = previous.Severity
+		out = append(out, n)
+	}
+	var known []string
+	if previous != nil {
+		known = previous.SensitiveExcess
+	}
+	var detected []string
+	for _, grant := range current.SensitiveExcess {
+		if !slices.Contains(known, grant) {
+			detected = append(detected, grant)
+		}
+	}
+	if len(detected) > 0 {
+		n := base
+		n.Kind = KindSensitiveExcess
+		if previous != nil {
+			n.PreviousSeverity = previous.Severity
+		}
+		n.SensitiveExcess = detected
+		out = append(out, n)
+	}
+	return out
+}
+
+// Sink delivers notifications to one receiver.
+type Sink interface {
+	// Name identifies the sink in logs and metrics.
+	Name() string
+	Send(ctx context.Context, n Notification) error
+}
+
+// Webhook POSTs each notification as a JSON object.
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// Name implements Sink.
+func (w *Webhook) Name() string { return "webhook" }
+
+// Send implements Sink.
+func (w *Webhook) Send(ctx context.Context, n Notification) error {
+	return postJSON(ctx, w.Client, w.URL, n)
+}
+
+// Slack posts each notification to a Slack incoming webhook as a message.
+type Slack struct {
+	URL    string
+	Client *http.Client
+}
+
+// Name implements Sink.
+func (s *Slack) Name() string { return "slack" }
+
+// Send implements Sink.
+func (s *Slack) Send(ctx context.Context, n Notification) error {
+	icon := ":warning:"
+	if n.Kind == KindSeverityChanged && severityRank(n.Severity) < severityRank(n.PreviousSeverity) {
+		icon = ":white_check_mark:"
+	}
+	return postJSON(ctx, s.Client, s.URL, map[string]string{"text": icon + " " + n.Summary()})
+}
+
+// severityRank orders severities from Green (0) to Red (2).
+func severityRank(s audiciav1alpha1.ComplianceSeverity) int {
+	switch s {
+	case audiciav1alpha1.ComplianceSeverityYellow:
+		return 1
+	case audiciav1alpha1.ComplianceSeverityRed:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// Dispatcher queues notifications and sends each to every sink. It is a
+// manager Runnable: queued notifications are sent once the manager starts
+// it. A nil *Dispatcher discards notifications.
+type Dispatcher struct {
+	sinks []Sink
+	queue chan Notification
+}
+
+// NewDispatcher returns a dispatcher for sinks, or nil if there are none.
+func NewDispatcher(sinks ...Sink) *Dispatcher {
+	if len(sinks) == 0 {
+		return nil
+	}
+	return &Dispatcher{sinks: sinks, queue: make(chan Notification, queueSize)}
+}
+
+// Notify queues notifications without blocking. Notifications that do not
+// fit the queue are dropped.
+func (d *Dispatcher) Notify(ns ...Notification) {
+	if d == nil {
+		return
+	}
+	for _, n := range ns {
+		select {
+		case d.queue <- n:
+		default:
+			for _, s := range d.sinks {
+				metrics.NotificationsTotal.WithLabelValues(s.Name(), "dropped").Inc()
+			}
+		}
+	}
+}
+
+// Start sends queued notifications until ctx is cancelled.
+func (d *Dispatcher) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-d.queue:
+			for _, s := range d.sinks {
+				d.send(ctx, s, n)
+			}
+		}
+	}
+}
+
+// send delivers n to s, retrying failed attempts with backoff.
+func (d *Dispatcher) send(ctx context.Context, s Sink, n Notification) {
+	logger := ctrl.Log.WithName("notify").WithValues("sink", s.Name(), "kind", n.Kind, "report", n.Namespace+"/"+n.Report)
+	backoff := retryBackoff
+	var err error
+	for attempt := 1; attempt <= sendAttempts; attempt++ {
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err = s.Send(sendCtx, n)
+		cancel()
+		if err == nil {
+			metrics.NotificationsTotal.WithLabelValues(s.Name(), "sent").Inc()
+			return
+		}
+		if attempt == sendAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	logger.Error(err, "failed to send notification")
+	metrics.NotificationsTotal.WithLabelValues(s.Name(), "failed").Inc()
+}
@@ -0,0 +1,186 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func testReport(severity audiciav1alpha1.ComplianceSeverity, sensitive ...string) *audiciav1alpha1.AudiciaReport {
+	return &audiciav1alpha1.AudiciaReport{
+		Spec: audiciav1alpha1.AudiciaReportSpec{
+			Subject: audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "alice"},
+		},
+		Status: audiciav1alpha1.AudiciaReportStatus{
+			Compliance: &audiciav1alpha1.ComplianceReport{
+				Severity:           severity,
+				HasSensitiveExcess: len(sensitive) > 0,
+				SensitiveExcess:    sensitive,
+			},
+		},
+	}
+}
+
+func kinds(ns []Notification) []Kind {
+	var out []Kind
+	for _, n := range ns {
+		out = append(out, n.Kind)
+	}
+	return out
+}
+
+func TestChanges(t *testing.T) {
+	green := &audiciav1alpha1.ComplianceReport{Severity: audiciav1alpha1.ComplianceSeverityGreen}
+	red := testReport(audiciav1alpha1.ComplianceSeverityRed, "secrets").Status.Compliance
+
+	tests := []struct {
+		name     string
+		report   *audiciav1alpha1.AudiciaReport
+		previous *audiciav1alpha1.ComplianceReport
+		want     []Kind
+	}{
+		{"not evaluated", &audiciav1alpha1.AudiciaReport{}, green, nil},
+		{"unchanged", testReport(audiciav1alpha1.ComplianceSeverityGreen), green, nil},
+		{"first evaluation", testReport(audiciav1alpha1.ComplianceSeverityRed), nil, nil},
+		{"first evaluation with sensitive excess", testReport(audiciav1alpha1.ComplianceSeverityRed, "secrets"), nil, []Kind{KindSensitiveExcess}},
+		{"degraded", testReport(audiciav1alpha1.ComplianceSeverityRed), green, []Kind{KindSeverityChanged}},
+		{"improved", testReport(audiciav1alpha1.ComplianceSeverityGreen), red, []Kind{KindSeverityChanged}},
+		{"sensitive excess detected", testReport(audiciav1alpha1.ComplianceSeverityRed, "secrets"), green, []Kind{KindSeverityChanged, KindSensitiveExcess}},
+		{"sensitive excess already known", testReport(audiciav1alpha1.ComplianceSeverityRed, "secrets"), red, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := kinds(Changes(tt.report, tt.previous)); !slices.Equal(got, tt.want) {
+				t.Errorf("Changes() kinds = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChanges_OnlyNewSensitiveExcess(t *testing.T) {
+	previous := testReport(audiciav1alpha1.ComplianceSeverityRed, "secrets").Status.Compliance
+	ns := Changes(testReport(audiciav1alpha1.ComplianceSeverityRed, "secrets", "nodes"), previous)
+	if len(ns) != 1 || !slices.Equal(ns[0].SensitiveExcess, []string{"nodes"}) {
+		t.Errorf("Changes() = %+v, want only nodes", ns)
+	}
+}
+
+func TestWebhook_Send(t *testing.T) {
+	var got Notification
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q", r.Header.Get("Content-Type"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	n := Changes(testReport(audiciav1alpha1.ComplianceSeverityRed, "secrets"), nil)[0]
+	if err := (&Webhook{URL: srv.URL}).Send(context.Background(), n); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got.Kind != KindSensitiveExcess || got.Subject.Name != "alice" || got.Severity != audiciav1alpha1.ComplianceSeverityRed {
+		t.Errorf("received %+v", got)
+	}
+}
+
+func TestSlack_Send(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	previous := &audiciav1alpha1.ComplianceReport{Severity: audiciav1alpha1.ComplianceSeverityGreen}
+	n := Changes(testReport(audiciav1alpha1.ComplianceSeverityRed), previous)[0]
+	if err := (&Slack{URL: srv.URL}).Send(context.Background(), n); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if !strings.Contains(got["text"], "changed from Green to Red") {
+		t.Errorf("text = %q", got["text"])
+	}
+}
+
+func TestWebhook_SendRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	if err := (&Webhook{URL: srv.URL}).Send(context.Background(), Notification{}); err == nil {
+		t.Error("expected an error for a 403 response")
+	}
+}
+
+type recordingSink struct {
+	mu       sync.Mutex
+	failures int
+	sent     []Notification
+}
+
+func (s *recordingSink) Name() string { return "test" }
+
+func (s *recordingSink) Send(_ context.Context, n Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("unavailable")
+	}
+	s.sent = append(s.sent, n)
+	return nil
+}
+
+func (s *recordingSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sent)
+}
+
+func TestDispatcher_RetriesFailedSends(t *testing.T) {
+	defer func(b time.Duration) { retryBackoff = b }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	sink := &recordingSink{failures: 2}
+	d := NewDispatcher(sink)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = d.Start(ctx) }()
+
+	d.Notify(Notification{Kind: KindSeverityChanged}, Notification{Kind: KindSensitiveExcess})
+	deadline := time.Now().Add(5 * time.Second)
+	for sink.count() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("sent %d notifications, want 2", sink.count())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDispatcher_Nil(t *testing.T) {
+	d := NewDispatcher()
+	if d != nil {
+		t.Fatal("expected no dispatcher without sinks")
+	}
+	d.Notify(Notification{}) // must not panic
+}
+
+func TestDispatcher_DropsWhenFull(t *testing.T) {
+	sink := &recordingSink{}
+	d := NewDispatcher(sink)
+	for range queueSize + 10 {
+		d.Notify(Notification{})
+	}
+	if len(d.queue) != queueSize {
+		t.Errorf("queued %d, want %d", len(d.queue), queueSize)
+	}
+}
@@ -0,0 +1,301 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+)
+
+// ReportWebhookKeyDir is where the Helm chart mounts the Secret holding the
+// signing keys of report webhooks (notifications.reportWebhookSecretName).
+const ReportWebhookKeyDir = "/etc/audicia/report-webhook-keys"
+
+// reportSink is the sink label of report webhook deliveries in
+// audicia_notifications_total.
+const reportSink = "report-webhook"
+
+// Headers of report webhook deliveries.
+const (
+	// DeliveryHeader carries an ID that stays the same across the retries of
+	// one delivery, so receivers can drop duplicates.
+	DeliveryHeader = "X-Audicia-Delivery"
+
+	// TimestampHeader carries the Unix time the delivery was signed at.
+	TimestampHeader = "X-Audicia-Timestamp"
+
+	// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the
+	// timestamp, a ".", and the body, keyed with the webhook's signing key.
+	SignatureHeader = "X-Audicia-Signature"
+)
+
+// ReportDiff is the material change of a report between two flushes.
+type ReportDiff struct {
+	AddedRules         []audiciav1alpha1.ObservedRule `json:"addedRules,omitempty"`
+	RemovedRules       []audiciav1alpha1.ObservedRule `json:"removedRules,omitempty"`
+	AddedDeniedRules   []audiciav1alpha1.ObservedRule `json:"addedDeniedRules,omitempty"`
+	RemovedDeniedRules []audiciav1alpha1.ObservedRule `json:"removedDeniedRules,omitempty"`
+
+	// PreviousCompliance and Compliance are set when the compliance
+	// severity, score or findings changed.
+	PreviousCompliance *audiciav1alpha1.ComplianceReport `json:"previousCompliance,omitempty"`
+	Compliance         *audiciav1alpha1.ComplianceReport `json:"compliance,omitempty"`
+}
+
+// empty reports whether the diff holds no change.
+func (d ReportDiff) empty() bool {
+	return len(d.AddedRules) == 0 && len(d.RemovedRules) == 0 &&
+		len(d.AddedDeniedRules) == 0 && len(d.RemovedDeniedRules) == 0 &&
+		d.Compliance == nil && d.PreviousCompliance == nil
+}
+
+// ReportUpdate is the body of a report webhook delivery.
+type ReportUpdate struct {
+	// Source is the AudiciaSource that wrote the report, namespace/name.
+	Source    string                  `json:"source"`
+	Namespace string                  `json:"namespace"`
+	Report    string                  `json:"report"`
+	Subject   audiciav1alpha1.Subject `json:"subject"`
+
+	// Created is set on the first flush of the report.
+	Created bool       `json:"created,omitempty"`
+	Diff    ReportDiff `json:"diff"`
+
+	// Object is the updated report, for Full payloads.
+	Object *audiciav1alpha1.AudiciaReport `json:"object,omitempty"`
+	Time   time.Time                      `json:"time"`
+}
+
+// DiffReport returns the material change of a report's status, and false if
+// there is none. Rules are compared by what they permit, so new counts and
+// timestamps of known rules are not a change.
+func DiffReport(before, after *audiciav1alpha1.AudiciaReportStatus) (ReportDiff, bool) {
+	var d ReportDiff
+	d.AddedRules, d.RemovedRules = diffRules(before.ObservedRules, after.ObservedRules)
+	d.AddedDeniedRules, d.RemovedDeniedRules = diffRules(before.DeniedRules, after.DeniedRules)
+	if !sameCompliance(before.Compliance, after.Compliance) {
+		d.PreviousCompliance, d.Compliance = before.Compliance, after.Compliance
+	}
+	return d, !d.empty()
+}
+
+// diffRules returns the rules of after missing from before, and those of
+// before missing from after.
+func diffRules(before, after []audiciav1alpha1.ObservedRule) (added, removed []audiciav1alpha1.ObservedRule) {
+	index := func(rules []audiciav1alpha1.ObservedRule) map[string]bool {
+		m := make(map[string]bool, len(rules))
+		for _, r := range rules {
+			m[ruleIdentity(r)] = true
+		}
+		return m
+	}
+	had, has := index(before), index(after)
+	for _, r := range after {
+		if !had[ruleIdentity(r)] {
+			added = append(added, r)
+		}
+	}
+	for _, r := range before {
+		if !has[ruleIdentity(r)] {
+			removed = append(removed, r)
+		}
+	}
+	return added, removed
+}
+
+// ruleIdentity identifies a rule by the requests it covers.
+func ruleIdentity(r audiciav1alpha1.ObservedRule) string {
+	return strings.Join([]string{
+		r.Namespace,
+		strings.Join(r.APIGroups, ","),
+		strings.Join(r.Resources, ","),
+		strings.Join(r.ResourceNames, ","),
+		strings.Join(r.NonResourceURLs, ","),
+		strings.Join(r.Verbs, ","),
+	}, "|")
+}
+
+// sameCompliance compares the results of two compliance evaluations,
+// ignoring when they were made.
+func sameCompliance(a, b *audiciav1alpha1.ComplianceReport) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Severity == b.Severity && a.Score == b.Score &&
+		a.ExcessCount == b.ExcessCount && a.UncoveredCount == b.UncoveredCount &&
+		slices.Equal(a.SensitiveExcess, b.SensitiveExcess)
+}
+
+// reportDelivery is a queued report webhook POST.
+type reportDelivery struct {
+	hook   audiciav1alpha1.ReportWebhook
+	source string
+	id     string
+	body   []byte
+}
+
+// ReportDispatcher queues report updates and POSTs them to the webhooks of
+// their source. Like Dispatcher, it is a manager Runnable and sends from a
+// single worker, so slow receivers never stall a pipeline. A nil
+// *ReportDispatcher discards updates.
+type ReportDispatcher struct {
+	// KeyDir holds the signing keys, one file per key and source.
+	KeyDir string
+
+	// Client sends the deliveries. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	queue chan reportDelivery
+}
+
+// NewReportDispatcher returns a dispatcher reading signing keys from keyDir.
+func NewReportDispatcher(keyDir string) *ReportDispatcher {
+	return &ReportDispatcher{KeyDir: keyDir, queue: make(chan reportDelivery, queueSize)}
+}
+
+// Notify queues update for each of hooks without blocking. The report is
+// only included for Full payloads. Deliveries that do not fit the queue
+// are dropped.
+func (d *ReportDispatcher) Notify(source types.NamespacedName, hooks []audiciav1alpha1.ReportWebhook, update ReportUpdate, report *audiciav1alpha1.AudiciaReport) {
+	if d == nil || len(hooks) == 0 {
+		return
+	}
+	update.Source = source.String()
+	logger := ctrl.Log.WithName("notify").WithValues("source", update.Source, "report", update.Namespace+"/"+update.Report)
+	for _, hook := range hooks {
+		body := update
+		if hook.Payload != audiciav1alpha1.ReportWebhookPayloadDiff {
+			object := report.DeepCopy()
+			object.ManagedFields = nil
+			object.SetGroupVersionKind(audiciav1alpha1.SchemeGroupVersion.WithKind("AudiciaReport"))
+			body.Object = object
+		}
+		data, err := json.Marshal(body)
+		if err != nil {
+			logger.Error(err, "failed to encode report update", "webhook", hook.Name)
+			continue
+		}
+		select {
+		case d.queue <- reportDelivery{hook: hook, source: update.Source, id: string(uuid.NewUUID()), body: data}:
+		default:
+			metrics.NotificationsTotal.WithLabelValues(reportSink, "dropped").Inc()
+		}
+	}
+}
+
+// Start sends queued deliveries until ctx is cancelled.
+func (d *ReportDispatcher) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case delivery := <-d.queue:
+			d.send(ctx, delivery)
+		}
+	}
+}
+
+// send POSTs delivery, retrying failed attempts with backoff.
+func (d *ReportDispatcher) send(ctx context.Context, delivery reportDelivery) {
+	logger := ctrl.Log.WithName("notify").WithValues("sink", reportSink, "source", delivery.source,
+		"webhook", delivery.hook.Name, "delivery", delivery.id)
+	backoff := retryBackoff
+	var err error
+	for attempt := 1; attempt <= sendAttempts; attempt++ {
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err = d.post(sendCtx, delivery, time.Now())
+		cancel()
+		if err == nil {
+			metrics.NotificationsTotal.WithLabelValues(reportSink, "sent").Inc()
+			return
+		}
+		if attempt == sendAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	logger.Error(err, "failed to deliver report update")
+	metrics.NotificationsTotal.WithLabelValues(reportSink, "failed").Inc()
+}
+
+// post makes one delivery attempt, signed at now.
+func (d *ReportDispatcher) post(ctx context.Context, delivery reportDelivery, now time.Time) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.hook.URL, bytes.NewReader(delivery.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryHeader, delivery.id)
+	if name := delivery.hook.SigningKey; name != "" {
+		// The key is read on every attempt, so a rotated Secret takes
+		// effect without a restart.
+		path, err := d.signingKeyPath(delivery.source, name)
+		if err != nil {
+			return err
+		}
+		key, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading signing key: %w", err)
+		}
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, Sign(bytes.TrimSpace(key), timestamp, delivery.body))
+	}
+
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// signingKeyPath returns the file of the signing key name of source,
+// namespace/name. Keys are scoped to their source, as
+// <namespace>_<name>_<key> in KeyDir, so a source cannot sign with the key of
+// another. Neither Kubernetes names nor namespaces contain "_".
+func (d *ReportDispatcher) signingKeyPath(source, name string) (string, error) {
+	if !filepath.IsLocal(name) || filepath.Base(name) != name {
+		return "", fmt.Errorf("invalid signing key name %q", name)
+	}
+	namespace, sourceName, _ := strings.Cut(source, "/")
+	return filepath.Join(d.KeyDir, namespace+"_"+sourceName+"_"+name), nil
+}
+
+// Sign returns the SignatureHeader value of body sent at timestamp.
+func Sign(key []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,190 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func observed(resource, verb string, count int64) audiciav1alpha1.ObservedRule {
+	return audiciav1alpha1.ObservedRule{
+		APIGroups: []string{""},
+		Resources: []string{resource},
+		Verbs:     []string{verb},
+		Namespace: "default",
+		LastSeen:  metav1.NewTime(time.Unix(count, 0)),
+		Count:     count,
+	}
+}
+
+func TestDiffReport(t *testing.T) {
+	before := &audiciav1alpha1.AudiciaReportStatus{
+		ObservedRules: []audiciav1alpha1.ObservedRule{observed("pods", "get", 1), observed("secrets", "get", 1)},
+		Compliance:    &audiciav1alpha1.ComplianceReport{Severity: audiciav1alpha1.ComplianceSeverityGreen, Score: 90},
+	}
+
+	// New counts and timestamps of known rules are not a change.
+	same := before.DeepCopy()
+	same.ObservedRules[0] = observed("pods", "get", 7)
+	same.Compliance.LastEvaluatedTime = metav1.Now()
+	if d, changed := DiffReport(before, same); changed {
+		t.Errorf("DiffReport() = %+v, want no change", d)
+	}
+
+	after := before.DeepCopy()
+	after.ObservedRules = []audiciav1alpha1.ObservedRule{observed("pods", "get", 2), observed("configmaps", "list", 1)}
+	after.DeniedRules = []audiciav1alpha1.ObservedRule{observed("nodes", "delete", 1)}
+	after.Compliance = &audiciav1alpha1.ComplianceReport{Severity: audiciav1alpha1.ComplianceSeverityRed, Score: 40}
+	d, changed := DiffReport(before, after)
+	if !changed {
+		t.Fatal("DiffReport() reported no change")
+	}
+	if len(d.AddedRules) != 1 || d.AddedRules[0].Resources[0] != "configmaps" {
+		t.Errorf("AddedRules = %v, want configmaps", d.AddedRules)
+	}
+	if len(d.RemovedRules) != 1 || d.RemovedRules[0].Resources[0] != "secrets" {
+		t.Errorf("RemovedRules = %v, want secrets", d.RemovedRules)
+	}
+	if len(d.AddedDeniedRules) != 1 || len(d.RemovedDeniedRules) != 0 {
+		t.Errorf("denied rules: added %v, removed %v", d.AddedDeniedRules, d.RemovedDeniedRules)
+	}
+	if d.PreviousCompliance.Severity != audiciav1alpha1.ComplianceSeverityGreen || d.Compliance.Severity != audiciav1alpha1.ComplianceSeverityRed {
+		t.Errorf("compliance = %v -> %v, want Green -> Red", d.PreviousCompliance, d.Compliance)
+	}
+}
+
+func TestReportDispatcher_SignsAndRetries(t *testing.T) {
+	oldBackoff := retryBackoff
+	retryBackoff = time.Millisecond
+	t.Cleanup(func() { retryBackoff = oldBackoff })
+
+	keyDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(keyDir, "audicia-system_audit_cmdb"), []byte("k3y\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var attempts atomic.Int32
+	deliveries := make(chan *http.Request, 4)
+	bodies := make(chan []byte, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- r
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	d := NewReportDispatcher(keyDir)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = d.Start(ctx) }()
+
+	report := &audiciav1alpha1.AudiciaReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "report-alice", Namespace: "default"},
+		Spec:       audiciav1alpha1.AudiciaReportSpec{Subject: audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "alice"}},
+	}
+	hooks := []audiciav1alpha1.ReportWebhook{{Name: "cmdb", URL: srv.URL, SigningKey: "cmdb"}}
+	d.Notify(types.NamespacedName{Namespace: "audicia-system", Name: "audit"}, hooks, ReportUpdate{
+		Namespace: "default",
+		Report:    "report-alice",
+		Subject:   report.Spec.Subject,
+		Created:   true,
+		Diff:      ReportDiff{AddedRules: []audiciav1alpha1.ObservedRule{observed("pods", "get", 1)}},
+	}, report)
+
+	var req *http.Request
+	var body []byte
+	select {
+	case req = <-deliveries:
+		body = <-bodies
+	case <-time.After(5 * time.Second):
+		t.Fatal("no delivery")
+	}
+	if attempts.Load() != 2 {
+		t.Errorf("attempts = %d, want 2", attempts.Load())
+	}
+	if req.Header.Get(DeliveryHeader) == "" {
+		t.Error("missing delivery ID")
+	}
+	if want := Sign([]byte("k3y"), req.Header.Get(TimestampHeader), body); req.Header.Get(SignatureHeader) != want {
+		t.Errorf("signature = %q, want %q", req.Header.Get(SignatureHeader), want)
+	}
+
+	var got ReportUpdate
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Source != "audicia-system/audit" || !got.Created || len(got.Diff.AddedRules) != 1 {
+		t.Errorf("update = %+v", got)
+	}
+	if got.Object == nil || got.Object.Kind != "AudiciaReport" || got.Object.Name != "report-alice" {
+		t.Errorf("Full payload object = %+v, want the report", got.Object)
+	}
+}
+
+func TestReportDispatcher_DiffPayloadUnsigned(t *testing.T) {
+	var got ReportUpdate
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	d := NewReportDispatcher(t.TempDir())
+	hooks := []audiciav1alpha1.ReportWebhook{{Name: "tickets", URL: srv.URL, Payload: audiciav1alpha1.ReportWebhookPayloadDiff}}
+	d.Notify(types.NamespacedName{Namespace: "audicia-system", Name: "audit"}, hooks, ReportUpdate{Report: "report-alice"}, &audiciav1alpha1.AudiciaReport{})
+	d.send(context.Background(), <-d.queue)
+
+	if got.Report != "report-alice" || got.Object != nil {
+		t.Errorf("update = %+v, want the diff without the report", got)
+	}
+	if header.Get(SignatureHeader) != "" {
+		t.Errorf("unsigned delivery carries signature %q", header.Get(SignatureHeader))
+	}
+}
+
+func TestReportDispatcher_MissingKeyFails(t *testing.T) {
+	d := NewReportDispatcher(t.TempDir())
+	delivery := reportDelivery{hook: audiciav1alpha1.ReportWebhook{Name: "cmdb", URL: "http://127.0.0.1:1", SigningKey: "absent"}, source: "audicia-system/audit"}
+	if err := d.post(context.Background(), delivery, time.Now()); err == nil {
+		t.Error("post succeeded without the signing key")
+	}
+}
+
+func TestReportDispatcher_SigningKeyScopedToSource(t *testing.T) {
+	keyDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(keyDir, "team-a_audit_cmdb"), []byte("k3y"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	d := NewReportDispatcher(keyDir)
+	if path, err := d.signingKeyPath("team-a/audit", "cmdb"); err != nil || path != filepath.Join(keyDir, "team-a_audit_cmdb") {
+		t.Errorf("signingKeyPath() = %q, %v", path, err)
+	}
+	for _, name := range []string{"../team-a_audit_cmdb", "/etc/passwd", "a/b", ".."} {
+		if _, err := d.signingKeyPath("team-b/audit", name); err == nil {
+			t.Errorf("signingKeyPath(%q) accepted", name)
+		}
+	}
+
+	// Another source naming the same key does not get it.
+	delivery := reportDelivery{hook: audiciav1alpha1.ReportWebhook{Name: "cmdb", URL: "http://127.0.0.1:1", SigningKey: "cmdb"}, source: "team-b/audit"}
+	if err := d.post(context.Background(), delivery, time.Now()); err == nil {
+		t.Error("post signed with the key of another source")
+	}
+}
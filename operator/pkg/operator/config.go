@@ -7,6 +7,28 @@ type Config struct {
 	// MetricsBindAddress is the address the metrics endpoint binds to.
 	MetricsBindAddress string `env:"METRICS_BIND_ADDRESS" envDefault:":8080"`
 
+	// MetricsSecure serves the metrics endpoints over TLS, with the tls.crt
+	// and tls.key in MetricsCertDir or, without them, a self-signed
+	// certificate.
+	MetricsSecure bool `env:"METRICS_SECURE" envDefault:"false"`
+
+	// MetricsCertDir holds the metrics endpoints' tls.crt and tls.key.
+	MetricsCertDir string `env:"METRICS_CERT_DIR" envDefault:"/etc/audicia/metrics-tls"`
+
+	// MetricsClientCAFile, when set, requires metrics clients to present a
+	// certificate signed by one of its CAs. Requires MetricsSecure.
+	MetricsClientCAFile string `env:"METRICS_CLIENT_CA_FILE"`
+
+	// MetricsTokenFile, when set, holds the bearer token metrics clients
+	// present. Requires MetricsSecure.
+	MetricsTokenFile string `env:"METRICS_TOKEN_FILE"`
+
+	// ReportMetricsBindAddress, when set, moves the metrics labeled by report
+	// or subject (compliance score, sensitive excess, rule counts,
+	// break-glass activity) off the metrics endpoint to one of their own at
+	// this address, with the same TLS and authentication.
+	ReportMetricsBindAddress string `env:"REPORT_METRICS_BIND_ADDRESS"`
+
 	// HealthProbeBindAddress is the address the health probe endpoint binds to.
 	HealthProbeBindAddress string `env:"HEALTH_PROBE_BIND_ADDRESS" envDefault:":8081"`
 
@@ -22,9 +44,104 @@ type Config struct {
 	// ConcurrentReconciles is the number of concurrent reconcile loops.
 	ConcurrentReconciles int `env:"CONCURRENT_RECONCILES" envDefault:"1"`
 
+	// PipelineWorkers is the number of event workers of each source's
+	// pipeline.
+	PipelineWorkers int `env:"PIPELINE_WORKERS" envDefault:"1"`
+
+	// FlushConcurrency is the number of subjects of a source flushed in
+	// parallel.
+	FlushConcurrency int `env:"FLUSH_CONCURRENCY" envDefault:"4"`
+
+	// FlushQPS limits subject flushes per second across all sources. 0
+	// disables the limit.
+	FlushQPS int `env:"FLUSH_QPS" envDefault:"20"`
+
+	// StorageEstimateInterval is the time between estimates of the etcd
+	// storage used by Audicia objects. 0 disables the estimate.
+	StorageEstimateInterval time.Duration `env:"STORAGE_ESTIMATE_INTERVAL" envDefault:"10m"`
+
+	// VerbDiscoveryInterval is the time between discoveries of the verbs
+	// each resource supports, which suggested policies are limited to. 0
+	// disables the check.
+	VerbDiscoveryInterval time.Duration `env:"VERB_DISCOVERY_INTERVAL" envDefault:"10m"`
+
+	// AutodiscoveryConfigMap, when set, names a ConfigMap in
+	// LeaderElectionNamespace that receives a suggested AudiciaSource after
+	// the operator inspects its environment at startup.
+	AutodiscoveryConfigMap string `env:"AUTODISCOVERY_CONFIGMAP"`
+
+	// PolicySinkGitURLs are the repositories of spec.policySink.git the
+	// mounted Git credentials may be sent to, comma-separated. A source
+	// naming another repository fails to publish rather than receiving them.
+	PolicySinkGitURLs []string `env:"POLICY_SINK_GIT_URLS" envSeparator:","`
+
 	// LogLevel is the log verbosity (0=info, 1=debug, 2=trace).
 	LogLevel int `env:"LOG_LEVEL" envDefault:"0"`
 
 	// SyncPeriod is the minimum interval between full reconciliations.
 	SyncPeriod time.Duration `env:"SYNC_PERIOD" envDefault:"10m"`
+
+	// Role selects which controllers this process runs: "all" (ingestion with
+	// inline compliance), "ingest" (ingestion only, compliance deferred to
+	// workers) or "compliance" (compliance worker only).
+	Role string `env:"OPERATOR_ROLE" envDefault:"all"`
+
+	// ComplianceShards is the number of compliance worker replicas sharing
+	// the report queue. Each worker evaluates the reports hashed to its shard.
+	ComplianceShards int `env:"COMPLIANCE_SHARDS" envDefault:"1"`
+
+	// ComplianceImplicitGroups counts the permissions bound to the groups
+	// Kubernetes adds to every request (system:authenticated,
+	// system:serviceaccounts and system:serviceaccounts:<namespace>) as
+	// effective permissions of users and ServiceAccounts.
+	ComplianceImplicitGroups bool `env:"COMPLIANCE_IMPLICIT_GROUPS" envDefault:"true"`
+
+	// PodName is the name of this pod. Compliance workers derive their shard
+	// index from its StatefulSet ordinal suffix.
+	PodName string `env:"POD_NAME"`
+
+	// PolicyPlansEnabled runs the AudiciaPolicyPlan controller, which writes
+	// approved Roles and Bindings to the cluster. It requires the extra RBAC
+	// granted by the Helm value policyPlans.enabled.
+	PolicyPlansEnabled bool `env:"POLICY_PLANS_ENABLED" envDefault:"false"`
+
+	// LocalIngestionEnabled permits Local AudiciaSources, which receive audit
+	// events over a UNIX socket or localhost HTTP without TLS. For kind and
+	// e2e environments only.
+	LocalIngestionEnabled bool `env:"LOCAL_INGESTION_ENABLED" envDefault:"false"`
+
+	// NotifyWebhookURL receives a JSON POST whenever a report's compliance
+	// severity changes or sensitive excess grants are first detected.
+	NotifyWebhookURL string `env:"NOTIFY_WEBHOOK_URL"`
+
+	// NotifySlackWebhookURL is a Slack incoming webhook that receives the
+	// same notifications as messages.
+	NotifySlackWebhookURL string `env:"NOTIFY_SLACK_WEBHOOK_URL"`
+
+	// RuleStreamBindAddress is the address of the gRPC rule stream, which
+	// serves normalized rules as they are observed. Empty disables it.
+	RuleStreamBindAddress string `env:"RULE_STREAM_BIND_ADDRESS"`
+
+	// RuleStreamCertDir holds the rule stream's tls.crt and tls.key.
+	RuleStreamCertDir string `env:"RULE_STREAM_CERT_DIR" envDefault:"/etc/audicia/rulestream-tls"`
+
+	// RuleStreamTokenFile holds the bearer token rule stream clients present.
+	RuleStreamTokenFile string `env:"RULE_STREAM_TOKEN_FILE" envDefault:"/etc/audicia/rulestream-token/token"`
+
+	// EventTapDir is the directory the event tap writes JSON Lines files of
+	// normalized rules to. Empty disables it.
+	EventTapDir string `env:"EVENT_TAP_DIR"`
+
+	// EventTapMaxBytes is the size at which an event tap file is closed.
+	EventTapMaxBytes int `env:"EVENT_TAP_MAX_BYTES" envDefault:"104857600"`
+
+	// EventTapMaxFiles is the number of event tap files kept; 0 keeps all.
+	EventTapMaxFiles int `env:"EVENT_TAP_MAX_FILES" envDefault:"10"`
 }
+
+// Operator roles.
+const (
+	RoleAll        = "all"
+	RoleIngest     = "ingest"
+	RoleCompliance = "compliance"
+)
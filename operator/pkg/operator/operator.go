@@ -3,20 +3,32 @@ package operator
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
-	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/autodiscovery"
+	"github.com/felixnotka/audicia/operator/pkg/controller/audiciapolicyplan"
 	"github.com/felixnotka/audicia/operator/pkg/controller/audiciasource"
+	"github.com/felixnotka/audicia/operator/pkg/eventtap"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+	"github.com/felixnotka/audicia/operator/pkg/notify"
+	"github.com/felixnotka/audicia/operator/pkg/rbac"
+	"github.com/felixnotka/audicia/operator/pkg/reportapi"
+	"github.com/felixnotka/audicia/operator/pkg/rulestream"
+	"github.com/felixnotka/audicia/operator/pkg/schema"
+	"github.com/felixnotka/audicia/operator/pkg/strategy"
 )
 
 var scheme = runtime.NewScheme()
@@ -33,6 +45,11 @@ type BuildInfo struct {
 	Date    string
 }
 
+// generator returns the build identity stamped on generated artifacts.
+func (b BuildInfo) generator() audiciav1alpha1.GeneratorInfo {
+	return audiciav1alpha1.GeneratorInfo{Version: b.Version, Commit: b.Commit}
+}
+
 // Start initializes and runs the operator.
 func Start(ctx context.Context, buildInfo BuildInfo, config Config) error {
 	logger := zap.New(zap.UseDevMode(config.LogLevel > 0))
@@ -45,13 +62,27 @@ func Start(ctx context.Context, buildInfo BuildInfo, config Config) error {
 		"date", buildInfo.Date,
 	)
 
+	// Compliance workers are sharded rather than leader-elected: every
+	// replica is active and evaluates its own slice of the report queue.
+	leaderElection := config.LeaderElectionEnabled
+	if config.Role == RoleCompliance {
+		leaderElection = false
+	}
+
+	serving := metricsServing(config)
+	if err := serving.Validate(); err != nil {
+		return err
+	}
+	metricsOptions, err := serving.ServerOptions(config.MetricsBindAddress)
+	if err != nil {
+		return err
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme: scheme,
-		Metrics: metricsserver.Options{
-			BindAddress: config.MetricsBindAddress,
-		},
+		Scheme:                  scheme,
+		Metrics:                 metricsOptions,
 		HealthProbeBindAddress:  config.HealthProbeBindAddress,
-		LeaderElection:          config.LeaderElectionEnabled,
+		LeaderElection:          leaderElection,
 		LeaderElectionID:        config.LeaderElectionID,
 		LeaderElectionNamespace: config.LeaderElectionNamespace,
 		Cache: cache.Options{
@@ -62,10 +93,23 @@ func Start(ctx context.Context, buildInfo BuildInfo, config Config) error {
 		return fmt.Errorf("unable to create manager: %w", err)
 	}
 
+	// Report-level metrics on their own endpoint, so access to the posture
+	// of individual subjects can be restricted separately.
+	if config.ReportMetricsBindAddress != "" {
+		if err := mgr.Add(&metrics.ReportServer{
+			Addr:     config.ReportMetricsBindAddress,
+			Serving:  serving,
+			Gatherer: metrics.SeparateReportMetrics(),
+		}); err != nil {
+			return fmt.Errorf("unable to add report metrics server: %w", err)
+		}
+	}
+
 	// Register controllers.
-	if err := audiciasource.SetupWithManager(mgr, config.ConcurrentReconciles); err != nil {
-		return fmt.Errorf("unable to create AudiciaSource controller: %w", err)
+	if err := registerControllers(mgr, config, buildInfo); err != nil {
+		return err
 	}
+	setupLog.Info("controllers registered", "role", config.Role)
 
 	// Prime RBAC informer caches so the compliance resolver has warm data
 	// on its first evaluation. GetInformer registers the type with the cache
@@ -91,6 +135,15 @@ func Start(ctx context.Context, buildInfo BuildInfo, config Config) error {
 		return fmt.Errorf("unable to set up ready check: %w", err)
 	}
 
+	// Read-only endpoints for report consumers: the CRD schemas and the
+	// suggested manifests of each report.
+	if err := mgr.AddMetricsServerExtraHandler(schema.HandlerPath, schema.Handler()); err != nil {
+		return fmt.Errorf("unable to register schema endpoint: %w", err)
+	}
+	if err := mgr.AddMetricsServerExtraHandler(reportapi.PathPrefix, reportapi.NewHandler(mgr.GetClient())); err != nil {
+		return fmt.Errorf("unable to register report manifests endpoint: %w", err)
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctx); err != nil {
 		return fmt.Errorf("manager exited with error: %w", err)
@@ -98,3 +151,153 @@ func Start(ctx context.Context, buildInfo BuildInfo, config Config) error {
 
 	return nil
 }
+
+// complianceResolver returns the RBAC resolver of compliance evaluation.
+func complianceResolver(mgr ctrl.Manager, config Config) *rbac.Resolver {
+	resolver := rbac.NewResolver(mgr.GetClient())
+	resolver.ImplicitGroups = config.ComplianceImplicitGroups
+	return resolver
+}
+
+// registerControllers sets up the controllers for the configured role.
+func registerControllers(mgr ctrl.Manager, config Config, buildInfo BuildInfo) error {
+	notifier := notify.NewDispatcher(notificationSinks(config)...)
+	if notifier != nil {
+		if err := mgr.Add(notifier); err != nil {
+			return fmt.Errorf("unable to add notification dispatcher: %w", err)
+		}
+	}
+
+	switch config.Role {
+	case RoleAll, RoleIngest:
+		deferCompliance := config.Role == RoleIngest
+		reportHooks := notify.NewReportDispatcher(notify.ReportWebhookKeyDir)
+		if err := mgr.Add(reportHooks); err != nil {
+			return fmt.Errorf("unable to add report webhook dispatcher: %w", err)
+		}
+		var ruleStream *rulestream.Hub
+		if config.RuleStreamBindAddress != "" || config.EventTapDir != "" {
+			ruleStream = rulestream.NewHub()
+		}
+		if config.RuleStreamBindAddress != "" {
+			if err := mgr.Add(&rulestream.Server{
+				Hub:       ruleStream,
+				Addr:      config.RuleStreamBindAddress,
+				CertDir:   config.RuleStreamCertDir,
+				TokenFile: config.RuleStreamTokenFile,
+			}); err != nil {
+				return fmt.Errorf("unable to add rule stream server: %w", err)
+			}
+		}
+		if config.EventTapDir != "" {
+			if err := mgr.Add(&eventtap.Writer{
+				Hub:      ruleStream,
+				Dir:      config.EventTapDir,
+				MaxBytes: int64(config.EventTapMaxBytes),
+				MaxFiles: config.EventTapMaxFiles,
+			}); err != nil {
+				return fmt.Errorf("unable to add event tap: %w", err)
+			}
+		}
+		var verbs strategy.VerbCatalog
+		if config.VerbDiscoveryInterval > 0 {
+			dc, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+			if err != nil {
+				return fmt.Errorf("unable to create discovery client: %w", err)
+			}
+			verbDiscovery := &rbac.VerbDiscovery{Client: dc, Interval: config.VerbDiscoveryInterval}
+			if err := mgr.Add(verbDiscovery); err != nil {
+				return fmt.Errorf("unable to add verb discovery: %w", err)
+			}
+			verbs = verbDiscovery
+		}
+		if err := audiciasource.SetupWithManager(mgr, audiciasource.SetupOptions{
+			MaxConcurrentReconciles: config.ConcurrentReconciles,
+			DeferCompliance:         deferCompliance,
+			LocalIngestion:          config.LocalIngestionEnabled,
+			Generator:               buildInfo.generator(),
+			Notifier:                notifier,
+			ReportHooks:             reportHooks,
+			RuleStream:              ruleStream,
+			PipelineWorkers:         config.PipelineWorkers,
+			FlushConcurrency:        config.FlushConcurrency,
+			FlushQPS:                config.FlushQPS,
+			Verbs:                   verbs,
+			Resolver:                complianceResolver(mgr, config),
+			PolicySinkGitURLs:       config.PolicySinkGitURLs,
+		}); err != nil {
+			return fmt.Errorf("unable to create AudiciaSource controller: %w", err)
+		}
+		if err := audiciasource.SetupStorageEstimatorWithManager(mgr, config.StorageEstimateInterval); err != nil {
+			return fmt.Errorf("unable to add storage estimator: %w", err)
+		}
+		if config.AutodiscoveryConfigMap != "" {
+			if err := mgr.Add(&autodiscovery.Publisher{
+				Client:    mgr.GetClient(),
+				Namespace: config.LeaderElectionNamespace,
+				Name:      config.AutodiscoveryConfigMap,
+			}); err != nil {
+				return fmt.Errorf("unable to add autodiscovery: %w", err)
+			}
+		}
+		if config.PolicyPlansEnabled {
+			if err := audiciapolicyplan.SetupWithManager(mgr, config.ConcurrentReconciles); err != nil {
+				return fmt.Errorf("unable to create AudiciaPolicyPlan controller: %w", err)
+			}
+		}
+	case RoleCompliance:
+		shard, err := shardIndex(config.PodName, config.ComplianceShards)
+		if err != nil {
+			return err
+		}
+		if err := audiciasource.SetupComplianceWorkerWithManager(mgr, config.ConcurrentReconciles, shard, config.ComplianceShards, notifier, complianceResolver(mgr, config)); err != nil {
+			return fmt.Errorf("unable to create compliance worker: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown operator role %q (want %s, %s or %s)", config.Role, RoleAll, RoleIngest, RoleCompliance)
+	}
+	return nil
+}
+
+// metricsServing returns the TLS and authentication of the metrics
+// endpoints configured in config.
+func metricsServing(config Config) metrics.Serving {
+	return metrics.Serving{
+		Secure:       config.MetricsSecure,
+		CertDir:      config.MetricsCertDir,
+		ClientCAFile: config.MetricsClientCAFile,
+		TokenFile:    config.MetricsTokenFile,
+	}
+}
+
+// notificationSinks returns the notification sinks configured in config.
+func notificationSinks(config Config) []notify.Sink {
+	var sinks []notify.Sink
+	if config.NotifyWebhookURL != "" {
+		sinks = append(sinks, &notify.Webhook{URL: config.NotifyWebhookURL})
+	}
+	if config.NotifySlackWebhookURL != "" {
+		sinks = append(sinks, &notify.Slack{URL: config.NotifySlackWebhookURL})
+	}
+	return sinks
+}
+
+// shardIndex derives a compliance worker's shard from the ordinal suffix of
+// its StatefulSet pod name (e.g., "audicia-compliance-worker-2" → 2).
+func shardIndex(podName string, shards int) (int, error) {
+	if shards <= 1 {
+		return 0, nil
+	}
+	i := strings.LastIndex(podName, "-")
+	if i < 0 {
+		return 0, fmt.Errorf("cannot derive shard from pod name %q: no ordinal suffix", podName)
+	}
+	ordinal, err := strconv.Atoi(podName[i+1:])
+	if err != nil {
+		return 0, fmt.Errorf("cannot derive shard from pod name %q: %w", podName, err)
+	}
+	if ordinal >= shards {
+		return 0, fmt.Errorf("pod ordinal %d is outside %d compliance shards", ordinal, shards)
+	}
+	return ordinal, nil
+}
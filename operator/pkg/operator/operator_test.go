@@ -39,6 +39,17 @@ func TestSchemeRegistration_AudiciaPolicy(t *testing.T) {
 	}
 }
 
+func TestSchemeRegistration_AudiciaPolicyPlan(t *testing.T) {
+	gvk := audiciav1alpha1.SchemeGroupVersion.WithKind("AudiciaPolicyPlan")
+	obj, err := scheme.New(gvk)
+	if err != nil {
+		t.Fatalf("AudiciaPolicyPlan not registered in scheme: %v", err)
+	}
+	if _, ok := obj.(*audiciav1alpha1.AudiciaPolicyPlan); !ok {
+		t.Errorf("scheme returned %T, expected *AudiciaPolicyPlan", obj)
+	}
+}
+
 func TestSchemeRegistration_AudiciaSourceList(t *testing.T) {
 	gvk := audiciav1alpha1.SchemeGroupVersion.WithKind("AudiciaSourceList")
 	obj, err := scheme.New(gvk)
@@ -70,3 +81,37 @@ func TestConfig_FieldDefaults(t *testing.T) {
 		t.Errorf("expected SyncPeriod=0, got %v", cfg.SyncPeriod)
 	}
 }
+
+func TestShardIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		podName string
+		shards  int
+		want    int
+		wantErr bool
+	}{
+		{"single shard ignores pod name", "", 1, 0, false},
+		{"ordinal suffix", "audicia-operator-compliance-worker-2", 3, 2, false},
+		{"first replica", "audicia-operator-compliance-worker-0", 3, 0, false},
+		{"ordinal outside shards", "audicia-operator-compliance-worker-3", 3, 0, true},
+		{"no ordinal", "audicia-operator-7f9c", 3, 0, true},
+		{"no dash", "worker", 2, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := shardIndex(tt.podName, tt.shards)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("shardIndex() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("shardIndex() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterControllers_UnknownRole(t *testing.T) {
+	if err := registerControllers(nil, Config{Role: "sidecar"}, BuildInfo{}); err == nil {
+		t.Error("expected error for unknown role")
+	}
+}
@@ -0,0 +1,252 @@
+// Package policysink publishes the suggested policies of a source outside the
+// cluster. The Git sink commits their manifests to a repository, so they flow
+// through the review of GitOps tooling such as Argo CD or Flux instead of
+// being applied from an AudiciaPolicy.
+package policysink
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	// CredentialsMountPath is where the Helm chart mounts the Secret named in
+	// spec.policySink.git.secretName, like the webhook's TLS and token
+	// Secrets.
+	CredentialsMountPath = "/etc/audicia/git-credentials"
+
+	// authorName and authorEmail sign the commits.
+	authorName  = "Audicia"
+	authorEmail = "audicia@localhost"
+)
+
+// Git commits files to a branch of a Git repository with the git binary.
+type Git struct {
+	// URL is the repository, over HTTPS or SSH.
+	URL string
+
+	// Branch receives the commits. It is created if missing.
+	Branch string
+
+	// Path is the directory in the repository the sink owns: files under it
+	// that are not synced are removed.
+	Path string
+
+	// Dir holds the local working copy.
+	Dir string
+
+	// CredentialsDir holds either username and password (an access token)
+	// for HTTPS, or ssh-privatekey and known_hosts for SSH. Empty for none.
+	CredentialsDir string
+
+	// CredentialURLs are the repositories the credentials may be sent to.
+	// The credentials are shared by every source, so a URL not listed
+	// exactly fails instead of receiving them.
+	CredentialURLs []string
+
+	// synced is the digest of the files last pushed.
+	synced string
+}
+
+// Sync makes Path hold exactly files, keyed by slash-separated paths relative
+// to Path, and pushes the result to Branch in one commit. It returns the
+// commit, or "" when the branch already held the files. Files unchanged
+// since the last successful Sync are not compared with the repository again.
+func (g *Git) Sync(ctx context.Context, files map[string][]byte, message string) (string, error) {
+	if !filepath.IsLocal(g.Path) || filepath.Clean(g.Path) == "." {
+		return "", fmt.Errorf("path %q must be a directory within the repository", g.Path)
+	}
+	for name := range files {
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return "", fmt.Errorf("file %q must stay within %s", name, g.Path)
+		}
+	}
+	digest := digestFiles(files)
+	if digest == g.synced {
+		return "", nil
+	}
+
+	if err := g.init(ctx); err != nil {
+		return "", err
+	}
+	env, err := g.env()
+	if err != nil {
+		return "", err
+	}
+	if err := g.checkout(ctx, env); err != nil {
+		return "", err
+	}
+
+	root := filepath.Join(g.Dir, g.Path)
+	if err := os.RemoveAll(root); err != nil {
+		return "", fmt.Errorf("clearing %s: %w", g.Path, err)
+	}
+	for name, data := range files {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(file, data, 0o644); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := g.run(ctx, env, "add", "--all", "--", g.Path); err != nil {
+		return "", err
+	}
+	status, err := g.run(ctx, env, "status", "--porcelain", "--", g.Path)
+	if err != nil {
+		return "", err
+	}
+	if status == "" {
+		g.synced = digest
+		return "", nil
+	}
+	if _, err := g.run(ctx, env, "commit", "--quiet", "--message", message); err != nil {
+		return "", err
+	}
+	if _, err := g.run(ctx, env, "push", "--quiet", "origin", "HEAD:refs/heads/"+g.Branch); err != nil {
+		return "", err
+	}
+	commit, err := g.run(ctx, env, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	g.synced = digest
+	return commit, nil
+}
+
+// init creates the working copy on first use.
+func (g *Git) init(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(g.Dir, ".git")); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(g.Dir, 0o700); err != nil {
+		return fmt.Errorf("creating working copy: %w", err)
+	}
+	env := os.Environ()
+	if _, err := g.run(ctx, env, "init", "--quiet"); err != nil {
+		return err
+	}
+	_, err := g.run(ctx, env, "remote", "add", "origin", g.URL)
+	return err
+}
+
+// checkout resets the working copy to the tip of Branch, or to an empty
+// branch if the remote has none yet.
+func (g *Git) checkout(ctx context.Context, env []string) error {
+	heads, err := g.run(ctx, env, "ls-remote", "--heads", "origin", "refs/heads/"+g.Branch)
+	if err != nil {
+		return err
+	}
+	if heads != "" {
+		if _, err := g.run(ctx, env, "fetch", "--quiet", "--depth=1", "origin", "refs/heads/"+g.Branch); err != nil {
+			return err
+		}
+		if _, err := g.run(ctx, env, "checkout", "--quiet", "--force", "-B", g.Branch, "FETCH_HEAD"); err != nil {
+			return err
+		}
+	} else {
+		if _, err := g.run(ctx, env, "checkout", "--quiet", "--force", "--orphan", g.Branch); err != nil {
+			return err
+		}
+		if _, err := g.run(ctx, env, "read-tree", "--empty"); err != nil {
+			return err
+		}
+	}
+	_, err = g.run(ctx, env, "clean", "--quiet", "--force", "-d", "-x")
+	return err
+}
+
+// env returns the environment of git commands: the commit identity and the
+// credentials, which are passed as an HTTP header or an SSH key so they
+// never appear in the remote URL.
+func (g *Git) env() ([]string, error) {
+	env := append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_AUTHOR_NAME="+authorName, "GIT_AUTHOR_EMAIL="+authorEmail,
+		"GIT_COMMITTER_NAME="+authorName, "GIT_COMMITTER_EMAIL="+authorEmail,
+	)
+	if g.CredentialsDir == "" {
+		return env, nil
+	}
+	if !slices.Contains(g.CredentialURLs, g.URL) {
+		return nil, fmt.Errorf("git credentials may not be sent to %s: it is not among the allowed repository URLs", g.URL)
+	}
+
+	password, err := os.ReadFile(filepath.Join(g.CredentialsDir, "password"))
+	switch {
+	case err == nil:
+		username, err := os.ReadFile(filepath.Join(g.CredentialsDir, "username"))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("reading Git username: %w", err)
+		}
+		user := cmp.Or(strings.TrimSpace(string(username)), "git")
+		auth := base64.StdEncoding.EncodeToString([]byte(user + ":" + strings.TrimSpace(string(password))))
+		return append(env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+auth,
+		), nil
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("reading Git password: %w", err)
+	}
+
+	key, err := os.ReadFile(filepath.Join(g.CredentialsDir, "ssh-privatekey"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("git credentials need a password or an ssh-privatekey")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading Git SSH key: %w", err)
+	}
+	knownHosts := filepath.Join(g.CredentialsDir, "known_hosts")
+	if _, err := os.Stat(knownHosts); err != nil {
+		return nil, fmt.Errorf("git SSH credentials need known_hosts: %w", err)
+	}
+	// ssh refuses keys readable by others, which Secret volumes are, so a
+	// private copy is kept inside .git, out of every commit.
+	keyFile := filepath.Join(g.Dir, ".git", "audicia-ssh-key")
+	if err := os.WriteFile(keyFile, key, 0o600); err != nil {
+		return nil, fmt.Errorf("writing Git SSH key: %w", err)
+	}
+	return append(env, fmt.Sprintf(
+		"GIT_SSH_COMMAND=ssh -i %s -o IdentitiesOnly=yes -o UserKnownHostsFile=%s -o StrictHostKeyChecking=yes",
+		keyFile, knownHosts)), nil
+}
+
+// run executes git in the working copy and returns its trimmed output.
+func (g *Git) run(ctx context.Context, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", g.Dir}, args...)...)
+	cmd.Env = env
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// digestFiles hashes the names and contents of files in name order.
+func digestFiles(files map[string][]byte) string {
+	h := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write(files[name])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,139 @@
+package policysink
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// newRemote creates a bare repository to push to.
+func newRemote(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := filepath.Join(t.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "init", "--quiet", "--bare", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	return dir
+}
+
+// remoteFiles lists the files of branch in the remote.
+func remoteFiles(t *testing.T, remote, branch string) []string {
+	t.Helper()
+	out, err := exec.Command("git", "-C", remote, "ls-tree", "-r", "--name-only", branch).Output()
+	if err != nil {
+		t.Fatalf("git ls-tree: %v", err)
+	}
+	return strings.Fields(string(out))
+}
+
+func TestGitSync(t *testing.T) {
+	remote := newRemote(t)
+	g := &Git{URL: remote, Branch: "audicia", Path: "policies/prod", Dir: t.TempDir()}
+	ctx := context.Background()
+
+	files := map[string][]byte{
+		"team/policy-backend.yaml":  []byte("kind: Role\n"),
+		"team/policy-frontend.yaml": []byte("kind: Role\n"),
+	}
+	first, err := g.Sync(ctx, files, "first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == "" {
+		t.Fatal("expected a commit on the new branch")
+	}
+	want := []string{"policies/prod/team/policy-backend.yaml", "policies/prod/team/policy-frontend.yaml"}
+	if got := remoteFiles(t, remote, "audicia"); !slices.Equal(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+
+	// Nothing changed: no commit, even from a fresh working copy.
+	fresh := &Git{URL: remote, Branch: "audicia", Path: "policies/prod", Dir: t.TempDir()}
+	if commit, err := fresh.Sync(ctx, files, "noop"); err != nil || commit != "" {
+		t.Errorf("Sync without changes = %q, %v; want no commit", commit, err)
+	}
+
+	// A dropped policy is removed, files outside Path are kept.
+	if err := os.WriteFile(filepath.Join(fresh.Dir, "README.md"), []byte("hi\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "-C", fresh.Dir, "add", "README.md").CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v: %s", err, out)
+	}
+	if out, err := exec.Command("git", "-C", fresh.Dir, "-c", "user.name=t", "-c", "user.email=t@localhost",
+		"commit", "--quiet", "-m", "readme").CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v: %s", err, out)
+	}
+	if out, err := exec.Command("git", "-C", fresh.Dir, "push", "--quiet", "origin", "HEAD:refs/heads/audicia").CombinedOutput(); err != nil {
+		t.Fatalf("git push: %v: %s", err, out)
+	}
+
+	delete(files, "team/policy-frontend.yaml")
+	second, err := g.Sync(ctx, files, "second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second == "" || second == first {
+		t.Errorf("expected a new commit, got %q", second)
+	}
+	want = []string{"README.md", "policies/prod/team/policy-backend.yaml"}
+	if got := remoteFiles(t, remote, "audicia"); !slices.Equal(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+	msg, _ := exec.Command("git", "-C", remote, "log", "-1", "--format=%an %s", "audicia").Output()
+	if strings.TrimSpace(string(msg)) != "Audicia second" {
+		t.Errorf("last commit = %q", msg)
+	}
+}
+
+func TestGitSync_RejectsPaths(t *testing.T) {
+	for _, tc := range []struct {
+		path, file string
+	}{
+		{"", "a.yaml"},
+		{".", "a.yaml"},
+		{"../outside", "a.yaml"},
+		{"/abs", "a.yaml"},
+		{"policies", "../a.yaml"},
+	} {
+		g := &Git{URL: "unused", Branch: "main", Path: tc.path, Dir: t.TempDir()}
+		if _, err := g.Sync(context.Background(), map[string][]byte{tc.file: nil}, "x"); err == nil {
+			t.Errorf("Sync(path %q, file %q) succeeded", tc.path, tc.file)
+		}
+	}
+}
+
+func TestGitEnv_Credentials(t *testing.T) {
+	creds := t.TempDir()
+	g := &Git{URL: "https://git.example.com/policies.git", Dir: t.TempDir(), CredentialsDir: creds, CredentialURLs: []string{"https://git.example.com/policies.git"}}
+	if _, err := g.env(); err == nil {
+		t.Error("expected an error without credentials")
+	}
+
+	if err := os.WriteFile(filepath.Join(creds, "password"), []byte("token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The credentials are never sent to a repository not allowed.
+	other := *g
+	other.URL = "https://attacker.example.com/policies.git"
+	if _, err := other.env(); err == nil {
+		t.Error("credentials sent to a repository not allowed")
+	}
+
+	env, err := g.env()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// base64("git:token")
+	if !slices.Contains(env, "GIT_CONFIG_VALUE_0=Authorization: Basic Z2l0OnRva2Vu") {
+		t.Errorf("missing basic auth header in %v", env[len(env)-3:])
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
@@ -17,12 +18,57 @@ import (
 type ScopedRule struct {
 	rbacv1.PolicyRule
 	Namespace string
+
+	// Grant is the binding and role the rule comes from, shared by all rules
+	// of that binding, or nil when unknown (e.g., candidate rules read from a
+	// file). RuleIndex is the position of the rule in Grant.RoleRules.
+	Grant     *Grant
+	RuleIndex int
+}
+
+// ObjectRef identifies an RBAC object.
+type ObjectRef struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// Grant is a binding of the subject to a role.
+type Grant struct {
+	// Binding is the RoleBinding or ClusterRoleBinding naming the subject.
+	Binding ObjectRef
+
+	// Subject is the entry of the binding's subjects that matched, at
+	// SubjectIndex of Subjects entries.
+	Subject      rbacv1.Subject
+	SubjectIndex int
+	Subjects     int
+
+	// Role is the Role or ClusterRole the binding refers to, with its rules.
+	Role      ObjectRef
+	RoleRules []rbacv1.PolicyRule
+
+	// RoleBindings is the number of bindings in the cluster referring to Role.
+	RoleBindings int
+
+	// Reconciled is set for roles whose rules Kubernetes rewrites: bootstrap
+	// roles with auto-update and aggregated ClusterRoles. Edits to their
+	// rules do not stick.
+	Reconciled bool
+
+	// Via is the implicit group the binding names instead of the subject,
+	// e.g. "system:authenticated", or "" for a direct binding.
+	Via string
 }
 
 // Resolver resolves the effective RBAC permissions for a subject by querying
 // bindings and roles from the Kubernetes API (via a caching client).
 type Resolver struct {
 	client client.Reader
+
+	// ImplicitGroups also resolves the bindings of the groups Kubernetes
+	// adds to every request of the subject; see ImplicitGroups.
+	ImplicitGroups bool
 }
 
 // NewResolver creates a Resolver. The client should be a caching reader (e.g.,
@@ -36,120 +82,176 @@ func NewResolver(c client.Reader) *Resolver {
 // (from ClusterRoleBindings) have Namespace="".
 //
 // Roles/ClusterRoles that cannot be resolved (e.g., deleted) are silently skipped.
-// Aggregated ClusterRoles (label-selector-based aggregation) are NOT resolved.
+// Aggregated ClusterRoles are read with the rules the aggregation controller
+// filled in. Every rule records the binding and role granting it.
 func (r *Resolver) EffectiveRules(ctx context.Context, subject audiciav1alpha1.Subject) ([]ScopedRule, error) {
+	var crbList rbacv1.ClusterRoleBindingList
+	if err := r.client.List(ctx, &crbList); err != nil {
+		return nil, fmt.Errorf("listing ClusterRoleBindings: %w", err)
+	}
+	var rbList rbacv1.RoleBindingList
+	if err := r.client.List(ctx, &rbList); err != nil {
+		return nil, fmt.Errorf("listing RoleBindings: %w", err)
+	}
+	refs := roleReferences(crbList.Items, rbList.Items)
+	identities := []audiciav1alpha1.Subject{subject}
+	if r.ImplicitGroups {
+		for _, group := range ImplicitGroups(subject) {
+			identities = append(identities, audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindGroup, Name: group})
+		}
+	}
+
 	var result []ScopedRule
 
 	// 1. ClusterRoleBindings → cluster-wide scope.
-	clusterRules, err := r.rulesFromClusterRoleBindings(ctx, subject)
-	if err != nil {
-		return nil, err
+	for i := range crbList.Items {
+		crb := &crbList.Items[i]
+		binding := ObjectRef{Kind: "ClusterRoleBinding", Name: crb.Name}
+		result = append(result, r.grantRules(ctx, binding, "", crb.Subjects, crb.RoleRef, identities, refs)...)
 	}
-	result = append(result, clusterRules...)
 
 	// 2. RoleBindings → scoped to the RoleBinding's namespace.
-	nsRules, err := r.rulesFromRoleBindings(ctx, subject)
-	if err != nil {
-		return nil, err
+	for i := range rbList.Items {
+		rb := &rbList.Items[i]
+		binding := ObjectRef{Kind: "RoleBinding", Namespace: rb.Namespace, Name: rb.Name}
+		result = append(result, r.grantRules(ctx, binding, rb.Namespace, rb.Subjects, rb.RoleRef, identities, refs)...)
 	}
-	result = append(result, nsRules...)
 
 	return result, nil
 }
 
-// rulesFromClusterRoleBindings collects cluster-wide rules from matching ClusterRoleBindings.
-func (r *Resolver) rulesFromClusterRoleBindings(ctx context.Context, subject audiciav1alpha1.Subject) ([]ScopedRule, error) {
-	var crbList rbacv1.ClusterRoleBindingList
-	if err := r.client.List(ctx, &crbList); err != nil {
-		return nil, fmt.Errorf("listing ClusterRoleBindings: %w", err)
+// roleReferences counts the bindings referring to each role.
+func roleReferences(crbs []rbacv1.ClusterRoleBinding, rbs []rbacv1.RoleBinding) map[ObjectRef]int {
+	refs := make(map[ObjectRef]int)
+	for i := range crbs {
+		refs[roleObject("", crbs[i].RoleRef)]++
 	}
-
-	var result []ScopedRule
-	for i := range crbList.Items {
-		crb := &crbList.Items[i]
-		if !matchesSubject(crb.Subjects, subject) {
-			continue
-		}
-		rules, err := r.resolveClusterRole(ctx, crb.RoleRef.Name)
-		if err != nil {
-			continue // Role may have been deleted; skip.
-		}
-		for _, pr := range rules {
-			result = append(result, ScopedRule{PolicyRule: pr, Namespace: ""})
-		}
+	for i := range rbs {
+		refs[roleObject(rbs[i].Namespace, rbs[i].RoleRef)]++
 	}
-	return result, nil
+	return refs
 }
 
-// rulesFromRoleBindings collects namespace-scoped rules from matching RoleBindings.
-func (r *Resolver) rulesFromRoleBindings(ctx context.Context, subject audiciav1alpha1.Subject) ([]ScopedRule, error) {
-	var rbList rbacv1.RoleBindingList
-	if err := r.client.List(ctx, &rbList); err != nil {
-		return nil, fmt.Errorf("listing RoleBindings: %w", err)
+// roleObject returns the role a binding in namespace refers to.
+func roleObject(namespace string, ref rbacv1.RoleRef) ObjectRef {
+	if ref.Kind == "ClusterRole" {
+		return ObjectRef{Kind: "ClusterRole", Name: ref.Name}
 	}
+	return ObjectRef{Kind: "Role", Namespace: namespace, Name: ref.Name}
+}
 
-	var result []ScopedRule
-	for i := range rbList.Items {
-		rb := &rbList.Items[i]
-		if !matchesSubject(rb.Subjects, subject) {
-			continue
-		}
-		rules := r.resolveRoleRef(ctx, rb.Namespace, rb.RoleRef)
-		for _, pr := range rules {
-			result = append(result, ScopedRule{PolicyRule: pr, Namespace: rb.Namespace})
+// grantRules returns the rules a binding grants in namespace to the first
+// of identities it names — the subject, then its implicit groups — or nil
+// if it names none. Roles that cannot be resolved (e.g., deleted) grant
+// nothing.
+func (r *Resolver) grantRules(ctx context.Context, binding ObjectRef, namespace string, subjects []rbacv1.Subject,
+	ref rbacv1.RoleRef, identities []audiciav1alpha1.Subject, refs map[ObjectRef]int) []ScopedRule {
+	idx, via := -1, ""
+	for i, identity := range identities {
+		if idx = subjectIndex(subjects, identity); idx >= 0 {
+			if i > 0 {
+				via = identity.Name
+			}
+			break
 		}
 	}
-	return result, nil
-}
-
-// resolveRoleRef resolves a RoleRef to its PolicyRules, returning nil on error.
-func (r *Resolver) resolveRoleRef(ctx context.Context, namespace string, ref rbacv1.RoleRef) []rbacv1.PolicyRule {
-	var rules []rbacv1.PolicyRule
-	var err error
-	if ref.Kind == "ClusterRole" {
-		rules, err = r.resolveClusterRole(ctx, ref.Name)
-	} else {
-		rules, err = r.resolveRole(ctx, namespace, ref.Name)
+	if idx < 0 {
+		return nil
 	}
+	role := roleObject(binding.Namespace, ref)
+	rules, reconciled, err := r.resolveRole(ctx, role)
 	if err != nil {
 		return nil // Role may have been deleted; skip.
 	}
-	return rules
+	grant := &Grant{
+		Binding:      binding,
+		Subject:      subjects[idx],
+		SubjectIndex: idx,
+		Subjects:     len(subjects),
+		Role:         role,
+		RoleRules:    rules,
+		RoleBindings: refs[role],
+		Reconciled:   reconciled,
+		Via:          via,
+	}
+	result := make([]ScopedRule, 0, len(rules))
+	for i, pr := range rules {
+		result = append(result, ScopedRule{PolicyRule: pr, Namespace: namespace, Grant: grant, RuleIndex: i})
+	}
+	return result
 }
 
-func (r *Resolver) resolveClusterRole(ctx context.Context, name string) ([]rbacv1.PolicyRule, error) {
-	var cr rbacv1.ClusterRole
-	if err := r.client.Get(ctx, client.ObjectKey{Name: name}, &cr); err != nil {
-		return nil, err
+// resolveRole returns the rules of a Role or ClusterRole, and whether
+// Kubernetes reconciles them.
+func (r *Resolver) resolveRole(ctx context.Context, role ObjectRef) ([]rbacv1.PolicyRule, bool, error) {
+	if role.Kind == "ClusterRole" {
+		var cr rbacv1.ClusterRole
+		if err := r.client.Get(ctx, client.ObjectKey{Name: role.Name}, &cr); err != nil {
+			return nil, false, err
+		}
+		return cr.Rules, cr.AggregationRule != nil || autoUpdated(cr.ObjectMeta), nil
+	}
+	var ro rbacv1.Role
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: role.Namespace, Name: role.Name}, &ro); err != nil {
+		return nil, false, err
 	}
-	return cr.Rules, nil
+	return ro.Rules, autoUpdated(ro.ObjectMeta), nil
+}
+
+// autoUpdated reports whether the API server restores a bootstrap role at
+// startup, which it does unless the role opts out.
+func autoUpdated(meta metav1.ObjectMeta) bool {
+	return meta.Labels["kubernetes.io/bootstrapping"] == "rbac-defaults" &&
+		meta.Annotations["rbac.authorization.kubernetes.io/autoupdate"] != "false"
 }
 
-func (r *Resolver) resolveRole(ctx context.Context, namespace, name string) ([]rbacv1.PolicyRule, error) {
-	var role rbacv1.Role
-	if err := r.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &role); err != nil {
-		return nil, err
+// ImplicitGroups returns the groups the API server adds to every request of
+// subject, which bindings may name instead of the subject itself:
+// system:authenticated for users and ServiceAccounts, and for
+// ServiceAccounts also system:serviceaccounts and
+// system:serviceaccounts:<namespace>. The anonymous user is in
+// system:unauthenticated instead. Groups have none; the further groups of a
+// user come from the authenticator and are not known.
+func ImplicitGroups(subject audiciav1alpha1.Subject) []string {
+	switch subject.Kind {
+	case audiciav1alpha1.SubjectKindServiceAccount:
+		return []string{
+			"system:serviceaccounts",
+			"system:serviceaccounts:" + subject.Namespace,
+			"system:authenticated",
+		}
+	case audiciav1alpha1.SubjectKindUser:
+		if subject.Name == "system:anonymous" {
+			return []string{"system:unauthenticated"}
+		}
+		return []string{"system:authenticated"}
 	}
-	return role.Rules, nil
+	return nil
 }
 
 // matchesSubject checks if any of the binding's subjects match the given Audicia subject.
 func matchesSubject(subjects []rbacv1.Subject, target audiciav1alpha1.Subject) bool {
-	for _, s := range subjects {
+	return subjectIndex(subjects, target) >= 0
+}
+
+// subjectIndex returns the position of the first of the binding's subjects
+// matching the given Audicia subject, or -1.
+func subjectIndex(subjects []rbacv1.Subject, target audiciav1alpha1.Subject) int {
+	for i, s := range subjects {
 		switch target.Kind {
 		case audiciav1alpha1.SubjectKindServiceAccount:
 			if s.Kind == "ServiceAccount" && s.Name == target.Name && s.Namespace == target.Namespace {
-				return true
+				return i
 			}
 		case audiciav1alpha1.SubjectKindUser:
 			if s.Kind == "User" && s.Name == target.Name {
-				return true
+				return i
 			}
 		case audiciav1alpha1.SubjectKindGroup:
 			if s.Kind == "Group" && s.Name == target.Name {
-				return true
+				return i
 			}
 		}
 	}
-	return false
+	return -1
 }
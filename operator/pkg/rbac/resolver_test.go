@@ -2,6 +2,7 @@ package rbac
 
 import (
 	"context"
+	"slices"
 	"testing"
 
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -445,3 +446,130 @@ func TestEffectiveRules_MultipleRulesInRole(t *testing.T) {
 		t.Fatalf("got %d rules, want 3 (all PolicyRules from ClusterRole)", len(rules))
 	}
 }
+
+// --- EffectiveRules: grants ---
+
+func TestEffectiveRules_Grant(t *testing.T) {
+	aggregated := makeClusterRole("monitoring", podReadRules)
+	aggregated.AggregationRule = &rbacv1.AggregationRule{}
+	c := fake.NewClientBuilder().WithScheme(testScheme()).WithObjects(
+		aggregated,
+		makeRole("secret-reader", "prod", secretReadRules),
+		makeCRB("monitoring-binding", "monitoring", []rbacv1.Subject{
+			{Kind: "User", Name: "bob"},
+			{Kind: "User", Name: "alice"},
+		}),
+		makeRB("monitoring-prod", "prod", "ClusterRole", "monitoring", []rbacv1.Subject{
+			{Kind: "User", Name: "bob"},
+		}),
+		makeRB("secret-binding", "prod", "Role", "secret-reader", []rbacv1.Subject{
+			{Kind: "User", Name: "alice"},
+		}),
+	).Build()
+
+	rules, err := NewResolver(c).EffectiveRules(context.Background(), audiciav1alpha1.Subject{
+		Kind: audiciav1alpha1.SubjectKindUser, Name: "alice",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("got %d rules, want 2", len(rules))
+	}
+
+	g := rules[0].Grant
+	if g == nil || g.Binding != (ObjectRef{Kind: "ClusterRoleBinding", Name: "monitoring-binding"}) ||
+		g.Role != (ObjectRef{Kind: "ClusterRole", Name: "monitoring"}) {
+		t.Fatalf("first grant = %+v, want monitoring-binding to ClusterRole monitoring", g)
+	}
+	if g.SubjectIndex != 1 || g.Subjects != 2 || g.Subject.Name != "alice" {
+		t.Errorf("subject = %d of %d (%+v), want alice at 1 of 2", g.SubjectIndex, g.Subjects, g.Subject)
+	}
+	if g.RoleBindings != 2 || !g.Reconciled {
+		t.Errorf("RoleBindings = %d, Reconciled = %v, want 2 and an aggregated role", g.RoleBindings, g.Reconciled)
+	}
+
+	g = rules[1].Grant
+	if g == nil || g.Role != (ObjectRef{Kind: "Role", Namespace: "prod", Name: "secret-reader"}) ||
+		g.RoleBindings != 1 || g.Reconciled || rules[1].RuleIndex != 0 {
+		t.Errorf("second grant = %+v, want an exclusive Role secret-reader", g)
+	}
+}
+
+func TestAutoUpdated(t *testing.T) {
+	bootstrap := map[string]string{"kubernetes.io/bootstrapping": "rbac-defaults"}
+	tests := []struct {
+		meta metav1.ObjectMeta
+		want bool
+	}{
+		{metav1.ObjectMeta{}, false},
+		{metav1.ObjectMeta{Labels: bootstrap}, true},
+		{metav1.ObjectMeta{Labels: bootstrap, Annotations: map[string]string{"rbac.authorization.kubernetes.io/autoupdate": "false"}}, false},
+	}
+	for _, tt := range tests {
+		if got := autoUpdated(tt.meta); got != tt.want {
+			t.Errorf("autoUpdated(%+v) = %v, want %v", tt.meta, got, tt.want)
+		}
+	}
+}
+
+// --- EffectiveRules: implicit groups ---
+
+func TestEffectiveRules_ImplicitGroups(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(testScheme()).WithObjects(
+		makeClusterRole("discovery", podReadRules),
+		makeRole("secret-reader", "prod", secretReadRules),
+		makeCRB("discovery-binding", "discovery", []rbacv1.Subject{
+			{Kind: "Group", Name: "system:authenticated"},
+		}),
+		makeRB("prod-sas", "prod", "Role", "secret-reader", []rbacv1.Subject{
+			{Kind: "Group", Name: "system:serviceaccounts:prod"},
+		}),
+		makeRB("dev-sas", "dev", "ClusterRole", "discovery", []rbacv1.Subject{
+			{Kind: "Group", Name: "system:serviceaccounts:dev"},
+		}),
+	).Build()
+	sa := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "backend", Namespace: "prod"}
+
+	resolver := NewResolver(c)
+	rules, err := resolver.EffectiveRules(context.Background(), sa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 0 {
+		t.Fatalf("got %d rules without implicit groups, want 0", len(rules))
+	}
+
+	resolver.ImplicitGroups = true
+	rules, err = resolver.EffectiveRules(context.Background(), sa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("got %d rules, want the system:authenticated and prod ServiceAccount grants", len(rules))
+	}
+	if rules[0].Namespace != "" || rules[0].Grant.Via != "system:authenticated" {
+		t.Errorf("first rule = %s via %q, want cluster-wide via system:authenticated", rules[0].Namespace, rules[0].Grant.Via)
+	}
+	if rules[1].Namespace != "prod" || rules[1].Grant.Via != "system:serviceaccounts:prod" {
+		t.Errorf("second rule = %s via %q, want prod via system:serviceaccounts:prod", rules[1].Namespace, rules[1].Grant.Via)
+	}
+}
+
+func TestImplicitGroups(t *testing.T) {
+	tests := []struct {
+		subject audiciav1alpha1.Subject
+		want    []string
+	}{
+		{audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "backend", Namespace: "prod"},
+			[]string{"system:serviceaccounts", "system:serviceaccounts:prod", "system:authenticated"}},
+		{audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "alice"}, []string{"system:authenticated"}},
+		{audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "system:anonymous"}, []string{"system:unauthenticated"}},
+		{audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindGroup, Name: "devs"}, nil},
+	}
+	for _, tt := range tests {
+		if got := ImplicitGroups(tt.subject); !slices.Equal(got, tt.want) {
+			t.Errorf("ImplicitGroups(%+v) = %v, want %v", tt.subject, got, tt.want)
+		}
+	}
+}
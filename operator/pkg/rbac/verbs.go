@@ -0,0 +1,112 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// VerbDiscovery knows the verbs each resource of the API server supports,
+// from its discovery documents. The discovery client fetches them with a
+// single aggregated discovery request (apidiscovery.k8s.io/v2) where the API
+// server serves it, and falls back to one request per group version
+// otherwise.
+type VerbDiscovery struct {
+	Client discovery.DiscoveryInterface
+
+	// Interval is the time between refreshes, which pick up CRDs and
+	// aggregated APIs installed since.
+	Interval time.Duration
+
+	mu    sync.RWMutex
+	verbs map[schema.GroupResource][]string
+}
+
+// Start refreshes the verbs once and then every Interval until ctx is done.
+func (d *VerbDiscovery) Start(ctx context.Context) error {
+	logger := ctrl.Log.WithName("verb-discovery")
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+	for {
+		if err := d.Refresh(); err != nil {
+			logger.Error(err, "failed to discover resource verbs")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh fetches the discovery documents. Resources of groups that fail
+// discovery, such as an unavailable aggregated API, are kept from the last
+// refresh.
+func (d *VerbDiscovery) Refresh() error {
+	_, lists, err := d.Client.ServerGroupsAndResources()
+	if lists == nil {
+		return err
+	}
+	verbs := make(map[schema.GroupResource][]string)
+	for _, list := range lists {
+		gv, parseErr := schema.ParseGroupVersion(list.GroupVersion)
+		if parseErr != nil {
+			continue
+		}
+		addResourceVerbs(verbs, gv.Group, list.APIResources)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var failed *discovery.ErrGroupDiscoveryFailed
+	if errors.As(err, &failed) {
+		failedGroups := make(map[string]bool, len(failed.Groups))
+		for gv := range failed.Groups {
+			failedGroups[gv.Group] = true
+		}
+		for gr, v := range d.verbs {
+			if _, ok := verbs[gr]; !ok && failedGroups[gr.Group] {
+				verbs[gr] = v
+			}
+		}
+		err = nil
+	}
+	d.verbs = verbs
+	return err
+}
+
+// addResourceVerbs adds the verbs of resources in group to verbs. A
+// resource served in several versions supports the verbs of all of them,
+// as RBAC rules apply to every version. Subresources are listed under the
+// group of their parent, as RBAC authorizes them, even where discovery
+// names another group for the subresource's kind.
+func addResourceVerbs(verbs map[schema.GroupResource][]string, group string, resources []metav1.APIResource) {
+	for _, res := range resources {
+		gr := schema.GroupResource{Group: group, Resource: res.Name}
+		merged := verbs[gr]
+		for _, v := range res.Verbs {
+			if !slices.Contains(merged, v) {
+				merged = append(merged, v)
+			}
+		}
+		slices.Sort(merged)
+		verbs[gr] = merged
+	}
+}
+
+// SupportedVerbs returns the verbs resource supports in apiGroup, and false
+// while the resource is unknown. Subresources are named like in RBAC rules,
+// e.g. "pods/log".
+func (d *VerbDiscovery) SupportedVerbs(apiGroup, resource string) ([]string, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	v, ok := d.verbs[schema.GroupResource{Group: apiGroup, Resource: resource}]
+	return v, ok
+}
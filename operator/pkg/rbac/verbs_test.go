@@ -0,0 +1,95 @@
+package rbac
+
+import (
+	"slices"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// failingDiscovery serves lists and fails discovery of failedGroup.
+type failingDiscovery struct {
+	*fakediscovery.FakeDiscovery
+	lists       []*metav1.APIResourceList
+	failedGroup schema.GroupVersion
+}
+
+func (d *failingDiscovery) ServerGroupsAndResources() ([]*metav1.APIGroup, []*metav1.APIResourceList, error) {
+	return nil, d.lists, &discovery.ErrGroupDiscoveryFailed{
+		Groups: map[schema.GroupVersion]error{d.failedGroup: errServiceUnavailable{}},
+	}
+}
+
+type errServiceUnavailable struct{}
+
+func (errServiceUnavailable) Error() string {
+	return "the server is currently unable to handle the request"
+}
+
+func TestVerbDiscovery(t *testing.T) {
+	fake := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "pods", Verbs: []string{"create", "delete", "deletecollection", "get", "list", "patch", "update", "watch"}},
+			{Name: "pods/log", Verbs: []string{"get"}},
+		}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments/scale", Group: "autoscaling", Version: "v1", Verbs: []string{"get", "patch", "update"}},
+		}},
+		{GroupVersion: "example.com/v1", APIResources: []metav1.APIResource{
+			{Name: "widgets", Verbs: []string{"get", "list"}},
+		}},
+		{GroupVersion: "example.com/v2", APIResources: []metav1.APIResource{
+			{Name: "widgets", Verbs: []string{"get", "watch"}},
+		}},
+	}}}
+	d := &VerbDiscovery{Client: fake}
+	if _, ok := d.SupportedVerbs("", "pods"); ok {
+		t.Error("resource known before the first refresh")
+	}
+	if err := d.Refresh(); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	for _, tc := range []struct {
+		group, resource string
+		want            []string
+	}{
+		{"", "pods/log", []string{"get"}},
+		{"apps", "deployments/scale", []string{"get", "patch", "update"}},
+		{"example.com", "widgets", []string{"get", "list", "watch"}},
+	} {
+		got, ok := d.SupportedVerbs(tc.group, tc.resource)
+		if !ok || !slices.Equal(got, tc.want) {
+			t.Errorf("SupportedVerbs(%q, %q) = %v, %v; want %v", tc.group, tc.resource, got, ok, tc.want)
+		}
+	}
+	if _, ok := d.SupportedVerbs("", "secrets"); ok {
+		t.Error("undiscovered resource is known")
+	}
+
+	// A group whose discovery fails keeps its resources; the others are
+	// replaced.
+	d.Client = &failingDiscovery{
+		FakeDiscovery: fake,
+		lists: []*metav1.APIResourceList{{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "pods", Verbs: []string{"get"}},
+		}}},
+		failedGroup: schema.GroupVersion{Group: "example.com", Version: "v2"},
+	}
+	if err := d.Refresh(); err != nil {
+		t.Fatalf("Refresh with a failed group: %v", err)
+	}
+	if _, ok := d.SupportedVerbs("example.com", "widgets"); !ok {
+		t.Error("resource of the failed group was dropped")
+	}
+	if _, ok := d.SupportedVerbs("", "pods/log"); ok {
+		t.Error("resource no longer served is still known")
+	}
+	if got, _ := d.SupportedVerbs("", "pods"); !slices.Equal(got, []string{"get"}) {
+		t.Errorf("pods verbs = %v, want [get]", got)
+	}
+}
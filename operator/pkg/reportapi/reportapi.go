@@ -0,0 +1,109 @@
+// Package reportapi serves the suggested manifests of AudiciaReports over
+// HTTP, so CI pipelines and GitOps tooling can fetch them with curl instead of
+// reading the AudiciaPolicy status.
+package reportapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/controller/audiciasource"
+	"github.com/felixnotka/audicia/operator/pkg/ingestor"
+)
+
+const (
+	// PathPrefix is served on the operator's metrics server:
+	// GET /api/v1/reports/<namespace>/<report>/manifests.
+	PathPrefix = "/api/v1/reports/"
+
+	// PolicyStateHeader carries the state of the AudiciaPolicy served.
+	PolicyStateHeader = "X-Audicia-Policy-State"
+)
+
+// Handler serves the manifests of the AudiciaPolicy suggested for a report.
+// Callers authenticate with a bearer token and need "get" on the report.
+type Handler struct {
+	client client.Client
+
+	// authenticator returns the Authenticator guarding a report. Callers
+	// must hold "get" on that AudiciaReport.
+	authenticator func(report types.NamespacedName) ingestor.Authenticator
+}
+
+// NewHandler returns a Handler reading reports and policies through c.
+func NewHandler(c client.Client) *Handler {
+	return &Handler{
+		client: c,
+		authenticator: func(report types.NamespacedName) ingestor.Authenticator {
+			a := ingestor.NewTokenReviewAuthenticator(c, report.Namespace, report.Name, nil)
+			a.Attributes.Verb = "get"
+			a.Attributes.Resource = "audiciareports"
+			a.Attributes.Subresource = ""
+			return a
+		},
+	}
+}
+
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		rw.Header().Set("Allow", "GET, HEAD")
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, PathPrefix), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] != "manifests" {
+		http.NotFound(rw, req)
+		return
+	}
+	key := types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+	if err := h.authenticator(key).Authenticate(req.Context(), req); err != nil {
+		ingestor.WriteAuthError(rw, err)
+		return
+	}
+
+	var report audiciav1alpha1.AudiciaReport
+	if err := h.client.Get(req.Context(), key, &report); err != nil {
+		writeLookupError(rw, err, "report")
+		return
+	}
+	policyKey := types.NamespacedName{Namespace: report.Namespace, Name: audiciasource.PolicyName(report.Spec.Subject)}
+
+	var policy audiciav1alpha1.AudiciaPolicy
+	if err := h.client.Get(req.Context(), policyKey, &policy); err != nil {
+		writeLookupError(rw, err, "policy")
+		return
+	}
+	rw.Header().Set("Content-Type", "application/yaml")
+	rw.Header().Set(PolicyStateHeader, string(policy.Status.State))
+	_, _ = fmt.Fprint(rw, RenderPolicy(policy))
+}
+
+func writeLookupError(rw http.ResponseWriter, err error, kind string) {
+	if errors.IsNotFound(err) {
+		http.Error(rw, kind+" not found", http.StatusNotFound)
+		return
+	}
+	http.Error(rw, fmt.Sprintf("reading %s: %v", kind, err), http.StatusServiceUnavailable)
+}
+
+// RenderPolicy joins a policy's manifests into one multi-document YAML
+// stream, each headed by a comment identifying the policy.
+func RenderPolicy(p audiciav1alpha1.AudiciaPolicy) string {
+	var b strings.Builder
+	for _, m := range p.Spec.Manifests {
+		b.WriteString("---\n")
+		fmt.Fprintf(&b, "# AudiciaPolicy %s/%s (%s %s, state %s)\n",
+			p.Namespace, p.Name, p.Spec.Subject.Kind, p.Spec.Subject.Name, p.Status.State)
+		b.WriteString(m)
+		if !strings.HasSuffix(m, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
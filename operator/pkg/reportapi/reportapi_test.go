@@ -0,0 +1,94 @@
+package reportapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/ingestor"
+)
+
+type authFunc func(context.Context, *http.Request) error
+
+func (f authFunc) Authenticate(ctx context.Context, req *http.Request) error { return f(ctx, req) }
+
+func TestHandler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := audiciav1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Namespace: "team", Name: "backend"}
+	report := &audiciav1alpha1.AudiciaReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "report-backend", Namespace: "team"},
+		Spec:       audiciav1alpha1.AudiciaReportSpec{Subject: subject},
+	}
+	orphan := &audiciav1alpha1.AudiciaReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "report-orphan", Namespace: "team"},
+		Spec:       audiciav1alpha1.AudiciaReportSpec{Subject: audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "orphan"}},
+	}
+	policy := &audiciav1alpha1.AudiciaPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy-backend", Namespace: "team"},
+		Spec: audiciav1alpha1.AudiciaPolicySpec{
+			Subject:   subject,
+			Manifests: []string{"kind: Role\n", "kind: RoleBinding"},
+		},
+		Status: audiciav1alpha1.AudiciaPolicyStatus{State: audiciav1alpha1.PolicyStateApproved},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(report, orphan, policy).
+		WithStatusSubresource(policy).Build()
+
+	var authorized types.NamespacedName
+	allow := func(key types.NamespacedName) ingestor.Authenticator {
+		authorized = key
+		return authFunc(func(context.Context, *http.Request) error { return nil })
+	}
+	deny := func(types.NamespacedName) ingestor.Authenticator {
+		return authFunc(func(context.Context, *http.Request) error { return ingestor.ErrForbidden })
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		auth       func(types.NamespacedName) ingestor.Authenticator
+		wantStatus int
+	}{
+		{"manifests", http.MethodGet, "team/report-backend/manifests", allow, http.StatusOK},
+		{"wrong method", http.MethodPost, "team/report-backend/manifests", allow, http.StatusMethodNotAllowed},
+		{"unknown path", http.MethodGet, "team/report-backend", allow, http.StatusNotFound},
+		{"forbidden", http.MethodGet, "team/report-backend/manifests", deny, http.StatusForbidden},
+		{"missing report", http.MethodGet, "team/report-missing/manifests", allow, http.StatusNotFound},
+		{"missing policy", http.MethodGet, "team/report-orphan/manifests", allow, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{client: c, authenticator: tt.auth}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, PathPrefix+tt.path, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if authorized != (types.NamespacedName{Namespace: "team", Name: "report-backend"}) {
+				t.Errorf("authorized against %v, want the report", authorized)
+			}
+			if got := rec.Header().Get(PolicyStateHeader); got != "Approved" {
+				t.Errorf("%s = %q, want Approved", PolicyStateHeader, got)
+			}
+			body := rec.Body.String()
+			if strings.Count(body, "---\n") != 2 || !strings.Contains(body, "kind: RoleBinding\n") {
+				t.Errorf("unexpected body:\n%s", body)
+			}
+		})
+	}
+}
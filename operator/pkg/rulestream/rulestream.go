@@ -0,0 +1,238 @@
+// Package rulestream serves the normalized rules observed by the operator's
+// pipelines over an authenticated gRPC stream (RuleStream.Watch), so
+// consumers can build their own analytics without waiting for the periodic
+// flush or polling AudiciaReports.
+package rulestream
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/bearer"
+	"github.com/felixnotka/audicia/operator/pkg/metrics"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+	rulestreamv1 "github.com/felixnotka/audicia/operator/pkg/rulestream/v1"
+)
+
+// subscriberBuffer is the number of observations buffered per client. A
+// client that falls further behind loses observations.
+const subscriberBuffer = 1024
+
+var log = ctrl.Log.WithName("rulestream")
+
+// Observation is a rule a source observed for a subject.
+type Observation struct {
+	Source  types.NamespacedName
+	Subject audiciav1alpha1.Subject
+	Rule    normalizer.CanonicalRule
+	Time    time.Time
+}
+
+// Hub fans observations out to the connected Watch streams. A nil *Hub
+// discards them.
+type Hub struct {
+	rulestreamv1.UnimplementedRuleStreamServer
+
+	mu   sync.RWMutex
+	subs map[*subscriber]struct{}
+	taps map[chan Observation]struct{}
+}
+
+type subscriber struct {
+	req *rulestreamv1.WatchRequest
+	ch  chan *rulestreamv1.Observation
+}
+
+// matches reports whether o is selected by the subscriber's request.
+func (s *subscriber) matches(o Observation) bool {
+	return (s.req.GetSourceNamespace() == "" || s.req.GetSourceNamespace() == o.Source.Namespace) &&
+		(s.req.GetSourceName() == "" || s.req.GetSourceName() == o.Source.Name) &&
+		(s.req.GetSubjectKind() == "" || s.req.GetSubjectKind() == string(o.Subject.Kind))
+}
+
+// NewHub returns a hub without clients.
+func NewHub() *Hub {
+	return &Hub{subs: make(map[*subscriber]struct{}), taps: make(map[chan Observation]struct{})}
+}
+
+// Subscribe returns a channel receiving every observation, for consumers in
+// the operator process, and a function that ends the subscription. Like
+// Watch clients, a subscriber that falls more than buffer observations
+// behind loses observations.
+func (h *Hub) Subscribe(buffer int) (<-chan Observation, func()) {
+	ch := make(chan Observation, buffer)
+	h.mu.Lock()
+	h.taps[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.taps, ch)
+		h.mu.Unlock()
+	}
+}
+
+// Publish sends o to the subscribers and matching clients without blocking.
+func (h *Hub) Publish(o Observation) {
+	if h == nil {
+		return
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.taps {
+		select {
+		case ch <- o:
+			metrics.RuleStreamObservationsTotal.WithLabelValues("sent").Inc()
+		default:
+			metrics.RuleStreamObservationsTotal.WithLabelValues("dropped").Inc()
+		}
+	}
+	var msg *rulestreamv1.Observation
+	for s := range h.subs {
+		if !s.matches(o) {
+			continue
+		}
+		if msg == nil {
+			msg = toProto(o)
+		}
+		select {
+		case s.ch <- msg:
+			metrics.RuleStreamObservationsTotal.WithLabelValues("sent").Inc()
+		default:
+			metrics.RuleStreamObservationsTotal.WithLabelValues("dropped").Inc()
+		}
+	}
+}
+
+// Watch implements rulestreamv1.RuleStreamServer.
+func (h *Hub) Watch(req *rulestreamv1.WatchRequest, stream grpc.ServerStreamingServer[rulestreamv1.Observation]) error {
+	s := &subscriber{req: req, ch: make(chan *rulestreamv1.Observation, subscriberBuffer)}
+	h.mu.Lock()
+	h.subs[s] = struct{}{}
+	h.mu.Unlock()
+	metrics.RuleStreamClients.Inc()
+	defer func() {
+		h.mu.Lock()
+		delete(h.subs, s)
+		h.mu.Unlock()
+		metrics.RuleStreamClients.Dec()
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case msg := <-s.ch:
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toProto(o Observation) *rulestreamv1.Observation {
+	return &rulestreamv1.Observation{
+		SourceNamespace: o.Source.Namespace,
+		SourceName:      o.Source.Name,
+		Subject: &rulestreamv1.Subject{
+			Kind:      string(o.Subject.Kind),
+			Name:      o.Subject.Name,
+			Namespace: o.Subject.Namespace,
+		},
+		Rule: &rulestreamv1.Rule{
+			ApiGroup:        o.Rule.APIGroup,
+			Resource:        o.Rule.Resource,
+			Verb:            o.Rule.Verb,
+			ResourceName:    o.Rule.ResourceName,
+			NonResourceUrl:  o.Rule.NonResourceURL,
+			Namespace:       o.Rule.Namespace,
+			Preset:          o.Rule.Preset,
+			Incomplete:      o.Rule.Incomplete,
+			Denied:          o.Rule.Denied,
+			AdmissionDenied: o.Rule.AdmissionDenied,
+		},
+		Time: timestamppb.New(o.Time),
+	}
+}
+
+// Server serves a Hub over gRPC with TLS, authenticating clients with a
+// static bearer token. It is a manager Runnable.
+type Server struct {
+	Hub *Hub
+
+	// Addr is the address to listen on (e.g., ":9090").
+	Addr string
+
+	// CertDir holds tls.crt and tls.key.
+	CertDir string
+
+	// TokenFile holds the bearer token clients present in the
+	// "authorization" metadata. It is re-read when it changes, so a rotated
+	// Secret takes effect without a restart.
+	TokenFile string
+}
+
+// Start serves until ctx is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	cert, err := tls.LoadX509KeyPair(filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key"))
+	if err != nil {
+		return fmt.Errorf("loading rule stream TLS certificate: %w", err)
+	}
+	listener, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", s.Addr, err)
+	}
+	creds := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{cert}})
+	return s.serve(ctx, listener, grpc.Creds(creds))
+}
+
+// serve runs the gRPC server on listener until ctx is cancelled.
+func (s *Server) serve(ctx context.Context, listener net.Listener, opts ...grpc.ServerOption) error {
+	srv := grpc.NewServer(append(opts, grpc.StreamInterceptor(authenticate(bearer.NewTokenFile(s.TokenFile))))...)
+	rulestreamv1.RegisterRuleStreamServer(srv, s.Hub)
+	stop := context.AfterFunc(ctx, srv.Stop)
+	defer stop()
+
+	log.Info("starting rule stream server", "address", listener.Addr().String())
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return fmt.Errorf("serving rule stream: %w", err)
+	}
+	return nil
+}
+
+// authenticate returns the interceptor rejecting calls without the bearer
+// token in tokens.
+func authenticate(tokens *bearer.TokenFile) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		expected, err := tokens.Token()
+		if err != nil {
+			log.Error(err, "reading rule stream token")
+			return status.Error(codes.Unavailable, "token unavailable")
+		}
+		md, _ := metadata.FromIncomingContext(stream.Context())
+		var token string
+		if values := md.Get("authorization"); len(values) > 0 {
+			token, _ = strings.CutPrefix(values[0], "Bearer ")
+		}
+		if !bearer.Equal(token, expected) {
+			return status.Error(codes.Unauthenticated, "invalid bearer token")
+		}
+		return handler(srv, stream)
+	}
+}
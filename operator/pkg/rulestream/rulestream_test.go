@@ -0,0 +1,156 @@
+package rulestream
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"k8s.io/apimachinery/pkg/types"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
+	rulestreamv1 "github.com/felixnotka/audicia/operator/pkg/rulestream/v1"
+)
+
+// startServer serves hub over an in-memory listener and returns a client.
+func startServer(t *testing.T, hub *Hub) rulestreamv1.RuleStreamClient {
+	t.Helper()
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	listener := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	srv := &Server{Hub: hub, TokenFile: tokenFile}
+	go func() { _ = srv.serve(ctx, listener) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return rulestreamv1.NewRuleStreamClient(conn)
+}
+
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+// waitForClients blocks until n clients are subscribed to hub.
+func waitForClients(t *testing.T, hub *Hub, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		hub.mu.RLock()
+		got := len(hub.subs)
+		hub.mu.RUnlock()
+		if got == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d clients subscribed, want %d", got, n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func observation(source, user string) Observation {
+	return Observation{
+		Source:  types.NamespacedName{Namespace: "audicia-system", Name: source},
+		Subject: audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: user},
+		Rule:    normalizer.CanonicalRule{Resource: "pods", Verb: "get", Namespace: "default"},
+		Time:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestWatch_StreamsMatchingObservations(t *testing.T) {
+	hub := NewHub()
+	client := startServer(t, hub)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := client.Watch(withToken(ctx, "s3cret"), &rulestreamv1.WatchRequest{SourceName: "audit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForClients(t, hub, 1)
+
+	hub.Publish(observation("other", "bob"))
+	hub.Publish(observation("audit", "alice"))
+
+	got, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv() error = %v", err)
+	}
+	if got.GetSourceName() != "audit" || got.GetSubject().GetName() != "alice" {
+		t.Errorf("received %v, want the observation of alice by audit", got)
+	}
+	if got.GetRule().GetResource() != "pods" || got.GetRule().GetVerb() != "get" || got.GetRule().GetNamespace() != "default" {
+		t.Errorf("rule = %v", got.GetRule())
+	}
+	if !got.GetTime().AsTime().Equal(observation("audit", "alice").Time) {
+		t.Errorf("time = %v", got.GetTime().AsTime())
+	}
+}
+
+func TestWatch_RejectsInvalidToken(t *testing.T) {
+	client := startServer(t, NewHub())
+	for name, ctx := range map[string]context.Context{
+		"missing": context.Background(),
+		"wrong":   withToken(context.Background(), "guess"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			stream, err := client.Watch(ctx, &rulestreamv1.WatchRequest{})
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			if status.Code(err) != codes.Unauthenticated {
+				t.Errorf("error = %v, want Unauthenticated", err)
+			}
+		})
+	}
+}
+
+func TestPublish_DropsForSlowClients(t *testing.T) {
+	hub := NewHub()
+	s := &subscriber{req: &rulestreamv1.WatchRequest{}, ch: make(chan *rulestreamv1.Observation, 1)}
+	hub.subs[s] = struct{}{}
+
+	hub.Publish(observation("audit", "alice"))
+	hub.Publish(observation("audit", "bob")) // must not block
+
+	if len(s.ch) != 1 {
+		t.Errorf("buffered %d observations, want 1", len(s.ch))
+	}
+	var nilHub *Hub
+	nilHub.Publish(observation("audit", "alice")) // must not panic
+}
+
+func TestSubscribe_ReceivesUntilCancelled(t *testing.T) {
+	hub := NewHub()
+	ch, cancel := hub.Subscribe(1)
+
+	hub.Publish(observation("audit", "alice"))
+	hub.Publish(observation("audit", "bob")) // dropped, must not block
+	if got := (<-ch).Subject.Name; got != "alice" {
+		t.Errorf("received %q, want alice", got)
+	}
+
+	cancel()
+	hub.Publish(observation("audit", "carol"))
+	if len(ch) != 0 {
+		t.Error("received an observation after cancelling")
+	}
+}
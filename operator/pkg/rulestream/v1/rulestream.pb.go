@@ -0,0 +1,454 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11-devel
+// 	protoc        (unknown)
+// source: pkg/rulestream/v1/rulestream.proto
+
+package rulestreamv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// WatchRequest selects the observations to stream. Empty fields match all.
+type WatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Namespace of the AudiciaSources to watch.
+	SourceNamespace string `protobuf:"bytes,1,opt,name=source_namespace,json=sourceNamespace,proto3" json:"source_namespace,omitempty"`
+	// Name of the AudiciaSource to watch.
+	SourceName string `protobuf:"bytes,2,opt,name=source_name,json=sourceName,proto3" json:"source_name,omitempty"`
+	// Kind of the subjects to watch: User, ServiceAccount or Group.
+	SubjectKind   string `protobuf:"bytes,3,opt,name=subject_kind,json=subjectKind,proto3" json:"subject_kind,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_pkg_rulestream_v1_rulestream_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_rulestream_v1_rulestream_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_rulestream_v1_rulestream_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *WatchRequest) GetSourceNamespace() string {
+	if x != nil {
+		return x.SourceNamespace
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetSourceName() string {
+	if x != nil {
+		return x.SourceName
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetSubjectKind() string {
+	if x != nil {
+		return x.SubjectKind
+	}
+	return ""
+}
+
+// Subject is the identity a rule was observed for.
+type Subject struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Kind is User, ServiceAccount or Group.
+	Kind string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Name of the subject.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Namespace of a ServiceAccount.
+	Namespace     string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Subject) Reset() {
+	*x = Subject{}
+	mi := &file_pkg_rulestream_v1_rulestream_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subject) ProtoMessage() {}
+
+func (x *Subject) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_rulestream_v1_rulestream_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subject.ProtoReflect.Descriptor instead.
+func (*Subject) Descriptor() ([]byte, []int) {
+	return file_pkg_rulestream_v1_rulestream_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Subject) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Subject) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Subject) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+// Rule is a normalized API request that passed the source's filters.
+type Rule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// API group, "" for the core group.
+	ApiGroup string `protobuf:"bytes,1,opt,name=api_group,json=apiGroup,proto3" json:"api_group,omitempty"`
+	// Resource, including the subresource (e.g., "pods/exec").
+	Resource string `protobuf:"bytes,2,opt,name=resource,proto3" json:"resource,omitempty"`
+	// API verb (e.g., "get", "list", "create").
+	Verb string `protobuf:"bytes,3,opt,name=verb,proto3" json:"verb,omitempty"`
+	// Name of the targeted object, empty for collection requests.
+	ResourceName string `protobuf:"bytes,4,opt,name=resource_name,json=resourceName,proto3" json:"resource_name,omitempty"`
+	// Non-resource URL (e.g., "/metrics"). Mutually exclusive with resource.
+	NonResourceUrl string `protobuf:"bytes,5,opt,name=non_resource_url,json=nonResourceUrl,proto3" json:"non_resource_url,omitempty"`
+	// Target namespace, empty for cluster-scoped requests.
+	Namespace string `protobuf:"bytes,6,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Housekeeping preset the rule was collapsed into, if any.
+	Preset string `protobuf:"bytes,7,opt,name=preset,proto3" json:"preset,omitempty"`
+	// Set when the event was at the ResponseStarted or Panic stage.
+	Incomplete bool `protobuf:"varint,8,opt,name=incomplete,proto3" json:"incomplete,omitempty"`
+	// Set when the authorizer denied the request.
+	Denied bool `protobuf:"varint,9,opt,name=denied,proto3" json:"denied,omitempty"`
+	// Set when RBAC allowed the request but admission control rejected it.
+	AdmissionDenied bool `protobuf:"varint,10,opt,name=admission_denied,json=admissionDenied,proto3" json:"admission_denied,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Rule) Reset() {
+	*x = Rule{}
+	mi := &file_pkg_rulestream_v1_rulestream_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rule) ProtoMessage() {}
+
+func (x *Rule) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_rulestream_v1_rulestream_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rule.ProtoReflect.Descriptor instead.
+func (*Rule) Descriptor() ([]byte, []int) {
+	return file_pkg_rulestream_v1_rulestream_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Rule) GetApiGroup() string {
+	if x != nil {
+		return x.ApiGroup
+	}
+	return ""
+}
+
+func (x *Rule) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *Rule) GetVerb() string {
+	if x != nil {
+		return x.Verb
+	}
+	return ""
+}
+
+func (x *Rule) GetResourceName() string {
+	if x != nil {
+		return x.ResourceName
+	}
+	return ""
+}
+
+func (x *Rule) GetNonResourceUrl() string {
+	if x != nil {
+		return x.NonResourceUrl
+	}
+	return ""
+}
+
+func (x *Rule) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *Rule) GetPreset() string {
+	if x != nil {
+		return x.Preset
+	}
+	return ""
+}
+
+func (x *Rule) GetIncomplete() bool {
+	if x != nil {
+		return x.Incomplete
+	}
+	return false
+}
+
+func (x *Rule) GetDenied() bool {
+	if x != nil {
+		return x.Denied
+	}
+	return false
+}
+
+func (x *Rule) GetAdmissionDenied() bool {
+	if x != nil {
+		return x.AdmissionDenied
+	}
+	return false
+}
+
+// Observation is one rule observed for a subject by an AudiciaSource.
+type Observation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Namespace of the AudiciaSource.
+	SourceNamespace string `protobuf:"bytes,1,opt,name=source_namespace,json=sourceNamespace,proto3" json:"source_namespace,omitempty"`
+	// Name of the AudiciaSource.
+	SourceName string   `protobuf:"bytes,2,opt,name=source_name,json=sourceName,proto3" json:"source_name,omitempty"`
+	Subject    *Subject `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	Rule       *Rule    `protobuf:"bytes,4,opt,name=rule,proto3" json:"rule,omitempty"`
+	// Time the API server received the request.
+	Time          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Observation) Reset() {
+	*x = Observation{}
+	mi := &file_pkg_rulestream_v1_rulestream_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Observation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Observation) ProtoMessage() {}
+
+func (x *Observation) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_rulestream_v1_rulestream_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Observation.ProtoReflect.Descriptor instead.
+func (*Observation) Descriptor() ([]byte, []int) {
+	return file_pkg_rulestream_v1_rulestream_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Observation) GetSourceNamespace() string {
+	if x != nil {
+		return x.SourceNamespace
+	}
+	return ""
+}
+
+func (x *Observation) GetSourceName() string {
+	if x != nil {
+		return x.SourceName
+	}
+	return ""
+}
+
+func (x *Observation) GetSubject() *Subject {
+	if x != nil {
+		return x.Subject
+	}
+	return nil
+}
+
+func (x *Observation) GetRule() *Rule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+func (x *Observation) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+var File_pkg_rulestream_v1_rulestream_proto protoreflect.FileDescriptor
+
+const file_pkg_rulestream_v1_rulestream_proto_rawDesc = "" +
+	"\n" +
+	"\"pkg/rulestream/v1/rulestream.proto\x12\x15audicia.rulestream.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"}\n" +
+	"\fWatchRequest\x12)\n" +
+	"\x10source_namespace\x18\x01 \x01(\tR\x0fsourceNamespace\x12\x1f\n" +
+	"\vsource_name\x18\x02 \x01(\tR\n" +
+	"sourceName\x12!\n" +
+	"\fsubject_kind\x18\x03 \x01(\tR\vsubjectKind\"O\n" +
+	"\aSubject\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\"\xbb\x02\n" +
+	"\x04Rule\x12\x1b\n" +
+	"\tapi_group\x18\x01 \x01(\tR\bapiGroup\x12\x1a\n" +
+	"\bresource\x18\x02 \x01(\tR\bresource\x12\x12\n" +
+	"\x04verb\x18\x03 \x01(\tR\x04verb\x12#\n" +
+	"\rresource_name\x18\x04 \x01(\tR\fresourceName\x12(\n" +
+	"\x10non_resource_url\x18\x05 \x01(\tR\x0enonResourceUrl\x12\x1c\n" +
+	"\tnamespace\x18\x06 \x01(\tR\tnamespace\x12\x16\n" +
+	"\x06preset\x18\a \x01(\tR\x06preset\x12\x1e\n" +
+	"\n" +
+	"incomplete\x18\b \x01(\bR\n" +
+	"incomplete\x12\x16\n" +
+	"\x06denied\x18\t \x01(\bR\x06denied\x12)\n" +
+	"\x10admission_denied\x18\n" +
+	" \x01(\bR\x0fadmissionDenied\"\xf4\x01\n" +
+	"\vObservation\x12)\n" +
+	"\x10source_namespace\x18\x01 \x01(\tR\x0fsourceNamespace\x12\x1f\n" +
+	"\vsource_name\x18\x02 \x01(\tR\n" +
+	"sourceName\x128\n" +
+	"\asubject\x18\x03 \x01(\v2\x1e.audicia.rulestream.v1.SubjectR\asubject\x12/\n" +
+	"\x04rule\x18\x04 \x01(\v2\x1b.audicia.rulestream.v1.RuleR\x04rule\x12.\n" +
+	"\x04time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04time2`\n" +
+	"\n" +
+	"RuleStream\x12R\n" +
+	"\x05Watch\x12#.audicia.rulestream.v1.WatchRequest\x1a\".audicia.rulestream.v1.Observation0\x01BGZEgithub.com/felixnotka/audicia/operator/pkg/rulestream/v1;rulestreamv1b\x06proto3"
+
+var (
+	file_pkg_rulestream_v1_rulestream_proto_rawDescOnce sync.Once
+	file_pkg_rulestream_v1_rulestream_proto_rawDescData []byte
+)
+
+func file_pkg_rulestream_v1_rulestream_proto_rawDescGZIP() []byte {
+	file_pkg_rulestream_v1_rulestream_proto_rawDescOnce.Do(func() {
+		file_pkg_rulestream_v1_rulestream_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_rulestream_v1_rulestream_proto_rawDesc), len(file_pkg_rulestream_v1_rulestream_proto_rawDesc)))
+	})
+	return file_pkg_rulestream_v1_rulestream_proto_rawDescData
+}
+
+var file_pkg_rulestream_v1_rulestream_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_pkg_rulestream_v1_rulestream_proto_goTypes = []any{
+	(*WatchRequest)(nil),          // 0: audicia.rulestream.v1.WatchRequest
+	(*Subject)(nil),               // 1: audicia.rulestream.v1.Subject
+	(*Rule)(nil),                  // 2: audicia.rulestream.v1.Rule
+	(*Observation)(nil),           // 3: audicia.rulestream.v1.Observation
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+}
+var file_pkg_rulestream_v1_rulestream_proto_depIdxs = []int32{
+	1, // 0: audicia.rulestream.v1.Observation.subject:type_name -> audicia.rulestream.v1.Subject
+	2, // 1: audicia.rulestream.v1.Observation.rule:type_name -> audicia.rulestream.v1.Rule
+	4, // 2: audicia.rulestream.v1.Observation.time:type_name -> google.protobuf.Timestamp
+	0, // 3: audicia.rulestream.v1.RuleStream.Watch:input_type -> audicia.rulestream.v1.WatchRequest
+	3, // 4: audicia.rulestream.v1.RuleStream.Watch:output_type -> audicia.rulestream.v1.Observation
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_pkg_rulestream_v1_rulestream_proto_init() }
+func file_pkg_rulestream_v1_rulestream_proto_init() {
+	if File_pkg_rulestream_v1_rulestream_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_rulestream_v1_rulestream_proto_rawDesc), len(file_pkg_rulestream_v1_rulestream_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_rulestream_v1_rulestream_proto_goTypes,
+		DependencyIndexes: file_pkg_rulestream_v1_rulestream_proto_depIdxs,
+		MessageInfos:      file_pkg_rulestream_v1_rulestream_proto_msgTypes,
+	}.Build()
+	File_pkg_rulestream_v1_rulestream_proto = out.File
+	file_pkg_rulestream_v1_rulestream_proto_goTypes = nil
+	file_pkg_rulestream_v1_rulestream_proto_depIdxs = nil
+}
@@ -0,0 +1,136 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: pkg/rulestream/v1/rulestream.proto
+
+package rulestreamv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	RuleStream_Watch_FullMethodName = "/audicia.rulestream.v1.RuleStream/Watch"
+)
+
+// RuleStreamClient is the client API for RuleStream service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// RuleStream serves the rules observed by the operator's pipelines as they are
+// aggregated, ahead of the periodic flush to AudiciaReports.
+type RuleStreamClient interface {
+	// Watch streams the observations matching the request until the client
+	// cancels the call or the operator shuts down. Observations are dropped
+	// for clients that do not keep up.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Observation], error)
+}
+
+type ruleStreamClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRuleStreamClient(cc grpc.ClientConnInterface) RuleStreamClient {
+	return &ruleStreamClient{cc}
+}
+
+func (c *ruleStreamClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Observation], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RuleStream_ServiceDesc.Streams[0], RuleStream_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, Observation]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RuleStream_WatchClient = grpc.ServerStreamingClient[Observation]
+
+// RuleStreamServer is the server API for RuleStream service.
+// All implementations must embed UnimplementedRuleStreamServer
+// for forward compatibility.
+//
+// RuleStream serves the rules observed by the operator's pipelines as they are
+// aggregated, ahead of the periodic flush to AudiciaReports.
+type RuleStreamServer interface {
+	// Watch streams the observations matching the request until the client
+	// cancels the call or the operator shuts down. Observations are dropped
+	// for clients that do not keep up.
+	Watch(*WatchRequest, grpc.ServerStreamingServer[Observation]) error
+	mustEmbedUnimplementedRuleStreamServer()
+}
+
+// UnimplementedRuleStreamServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRuleStreamServer struct{}
+
+func (UnimplementedRuleStreamServer) Watch(*WatchRequest, grpc.ServerStreamingServer[Observation]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedRuleStreamServer) mustEmbedUnimplementedRuleStreamServer() {}
+func (UnimplementedRuleStreamServer) testEmbeddedByValue()                    {}
+
+// UnsafeRuleStreamServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RuleStreamServer will
+// result in compilation errors.
+type UnsafeRuleStreamServer interface {
+	mustEmbedUnimplementedRuleStreamServer()
+}
+
+func RegisterRuleStreamServer(s grpc.ServiceRegistrar, srv RuleStreamServer) {
+	// If the following call pancis, it indicates UnimplementedRuleStreamServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RuleStream_ServiceDesc, srv)
+}
+
+func _RuleStream_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RuleStreamServer).Watch(m, &grpc.GenericServerStream[WatchRequest, Observation]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RuleStream_WatchServer = grpc.ServerStreamingServer[Observation]
+
+// RuleStream_ServiceDesc is the grpc.ServiceDesc for RuleStream service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RuleStream_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "audicia.rulestream.v1.RuleStream",
+	HandlerType: (*RuleStreamServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _RuleStream_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/rulestream/v1/rulestream.proto",
+}
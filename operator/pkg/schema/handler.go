@@ -0,0 +1,57 @@
+package schema
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// HandlerPath is where the operator serves the schemas on its metrics server:
+// an index at HandlerPath and each schema at HandlerPath + Schema.Path(),
+// e.g. /schemas/audicia.io/v1alpha1/audiciareports.json.
+const HandlerPath = "/schemas/"
+
+// indexEntry lists one schema in the index.
+type indexEntry struct {
+	Schema
+	Path string `json:"path"`
+}
+
+type handler struct{}
+
+// Handler serves the schemas under HandlerPath.
+func Handler() http.Handler {
+	return handler{}
+}
+
+func (handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		rw.Header().Set("Allow", "GET, HEAD")
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	all, err := All()
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	path := strings.TrimPrefix(req.URL.Path, HandlerPath)
+	if path == "" {
+		index := make([]indexEntry, 0, len(all))
+		for _, s := range all {
+			index = append(index, indexEntry{Schema: s, Path: HandlerPath + s.Path()})
+		}
+		_ = json.NewEncoder(rw).Encode(index)
+		return
+	}
+	for _, s := range all {
+		if s.Path() == path {
+			_, _ = rw.Write(s.OpenAPIV3)
+			return
+		}
+	}
+	rw.Header().Del("Content-Type")
+	http.NotFound(rw, req)
+}
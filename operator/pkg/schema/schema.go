@@ -0,0 +1,138 @@
+// Package schema publishes the structural OpenAPI v3 schemas of the Audicia
+// CRDs, status included, so report consumers can validate resources and
+// generate clients without reading the CRDs from a live cluster. The schemas
+// are copies of deploy/helm/crds, kept in sync by `make generate`.
+package schema
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed crds/*.yaml
+var crds embed.FS
+
+// Schema is the OpenAPI v3 schema of one version of an Audicia resource.
+type Schema struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	Plural  string `json:"plural"`
+
+	// OpenAPIV3 is the CRD's openAPIV3Schema for this version.
+	OpenAPIV3 json.RawMessage `json:"-"`
+}
+
+// Path is where the schema is served, relative to the schema endpoint.
+func (s Schema) Path() string {
+	return fmt.Sprintf("%s/%s/%s.json", s.Group, s.Version, s.Plural)
+}
+
+// crd is the part of a CustomResourceDefinition the schemas are read from.
+type crd struct {
+	Spec struct {
+		Group string `json:"group"`
+		Names struct {
+			Kind       string   `json:"kind"`
+			Plural     string   `json:"plural"`
+			ShortNames []string `json:"shortNames"`
+		} `json:"names"`
+		Versions []struct {
+			Name   string `json:"name"`
+			Schema struct {
+				OpenAPIV3Schema json.RawMessage `json:"openAPIV3Schema"`
+			} `json:"schema"`
+		} `json:"versions"`
+	} `json:"spec"`
+}
+
+var (
+	loadOnce   sync.Once
+	loaded     []Schema
+	shortNames map[string]string // short name → plural
+	loadErr    error
+)
+
+func load() ([]Schema, error) {
+	loadOnce.Do(func() {
+		shortNames = make(map[string]string)
+		loadErr = fs.WalkDir(crds, "crds", func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			data, err := crds.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			var c crd
+			if err := yaml.Unmarshal(data, &c); err != nil {
+				return fmt.Errorf("parsing %s: %w", path, err)
+			}
+			for _, short := range c.Spec.Names.ShortNames {
+				shortNames[short] = c.Spec.Names.Plural
+			}
+			for _, v := range c.Spec.Versions {
+				loaded = append(loaded, Schema{
+					Group:     c.Spec.Group,
+					Version:   v.Name,
+					Kind:      c.Spec.Names.Kind,
+					Plural:    c.Spec.Names.Plural,
+					OpenAPIV3: v.Schema.OpenAPIV3Schema,
+				})
+			}
+			return nil
+		})
+		slices.SortFunc(loaded, func(a, b Schema) int { return strings.Compare(a.Path(), b.Path()) })
+	})
+	return loaded, loadErr
+}
+
+// All returns the schemas of every Audicia resource and version, sorted by
+// path.
+func All() ([]Schema, error) {
+	return load()
+}
+
+// Lookup returns the schema of a resource by kind, plural or short name
+// (case-insensitive). An empty version matches any version.
+func Lookup(name, version string) (Schema, error) {
+	all, err := load()
+	if err != nil {
+		return Schema{}, err
+	}
+	name = strings.ToLower(name)
+	if plural, ok := shortNames[name]; ok {
+		name = plural
+	}
+	for _, s := range all {
+		if (strings.ToLower(s.Kind) == name || s.Plural == name) && (version == "" || s.Version == version) {
+			return s, nil
+		}
+	}
+	return Schema{}, fmt.Errorf("no schema for %q", name)
+}
+
+// CRDs returns the CustomResourceDefinition manifests of the Audicia
+// resources, sorted by file name, for installing them without Helm.
+func CRDs() ([][]byte, error) {
+	entries, err := crds.ReadDir("crds")
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]byte, 0, len(entries))
+	for _, e := range entries {
+		data, err := crds.ReadFile("crds/" + e.Name())
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, data)
+	}
+	return out, nil
+}
@@ -0,0 +1,94 @@
+package schema
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEmbeddedCRDsMatchHelmChart(t *testing.T) {
+	chart, err := filepath.Glob("../../../deploy/helm/crds/*.yaml")
+	if err != nil || len(chart) == 0 {
+		t.Fatalf("no CRDs in the Helm chart: %v", err)
+	}
+	for _, path := range chart {
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := crds.ReadFile("crds/" + filepath.Base(path))
+		if err != nil || string(got) != string(want) {
+			t.Errorf("%s is out of date; run make generate", filepath.Base(path))
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, name := range []string{"AudiciaReport", "audiciareports", "ar"} {
+		s, err := Lookup(name, "")
+		if err != nil {
+			t.Fatalf("Lookup(%q) = %v", name, err)
+		}
+		if s.Kind != "AudiciaReport" || s.Path() != "audicia.io/v1alpha1/audiciareports.json" {
+			t.Errorf("Lookup(%q) = %s %s", name, s.Kind, s.Path())
+		}
+	}
+
+	s, err := Lookup("AudiciaSource", "v1alpha1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(s.OpenAPIV3, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Properties["status"] == nil || doc.Properties["spec"] == nil {
+		t.Errorf("schema lacks spec or status: %v", doc.Properties)
+	}
+
+	if _, err := Lookup("AudiciaReport", "v2"); err == nil {
+		t.Error("expected an error for an unknown version")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	srv := httptest.NewServer(Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + HandlerPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var index []indexEntry
+	if err := json.NewDecoder(resp.Body).Decode(&index); err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if len(index) != 4 || index[0].Path != "/schemas/audicia.io/v1alpha1/audiciapolicies.json" {
+		t.Errorf("index = %+v", index)
+	}
+
+	resp, err = http.Get(srv.URL + index[0].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&schema); err != nil || schema["type"] != "object" {
+		t.Errorf("schema = %v, %v", schema, err)
+	}
+	_ = resp.Body.Close()
+
+	resp, err = http.Get(srv.URL + HandlerPath + "audicia.io/v1alpha1/unknown.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown schema: status %d, want 404", resp.StatusCode)
+	}
+}
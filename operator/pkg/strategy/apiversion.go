@@ -0,0 +1,25 @@
+package strategy
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ResolveRBACAPIVersion picks the apiVersion for rendered manifests. An
+// override is used as given. Otherwise the first supported version the
+// cluster serves wins; with no discovery information (served is empty) the
+// stable default is used.
+func ResolveRBACAPIVersion(override string, served []string) (string, error) {
+	if override != "" {
+		return override, nil
+	}
+	if len(served) == 0 {
+		return rbacAPIVersion, nil
+	}
+	for _, v := range SupportedRBACVersions {
+		if slices.Contains(served, v) {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("cluster serves RBAC %v, renderer supports %v", served, SupportedRBACVersions)
+}
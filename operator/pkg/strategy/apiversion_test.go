@@ -0,0 +1,52 @@
+package strategy
+
+import (
+	"strings"
+	"testing"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func TestResolveRBACAPIVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		override string
+		served   []string
+		want     string
+		wantErr  bool
+	}{
+		{name: "no discovery", want: "rbac.authorization.k8s.io/v1"},
+		{name: "served v1", served: []string{"rbac.authorization.k8s.io/v2", "rbac.authorization.k8s.io/v1"}, want: "rbac.authorization.k8s.io/v1"},
+		{name: "override wins", override: "rbac.authorization.k8s.io/v2", served: []string{"rbac.authorization.k8s.io/v1"}, want: "rbac.authorization.k8s.io/v2"},
+		{name: "nothing supported", served: []string{"rbac.authorization.k8s.io/v2"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveRBACAPIVersion(tt.override, tt.served)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateManifests_APIVersionOverride(t *testing.T) {
+	e := NewEngine(audiciav1alpha1.PolicyStrategy{RBACAPIVersion: "rbac.authorization.k8s.io/v2"})
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "app", Namespace: "default"}
+	rules := []audiciav1alpha1.ObservedRule{
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}, Namespace: "default"},
+	}
+
+	manifests, err := e.GenerateManifests(subject, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range manifests {
+		if !strings.Contains(m, "apiVersion: rbac.authorization.k8s.io/v2") {
+			t.Errorf("expected overridden apiVersion in:\n%s", m)
+		}
+	}
+}
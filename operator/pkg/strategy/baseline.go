@@ -0,0 +1,91 @@
+package strategy
+
+import (
+	"encoding/json"
+	"slices"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// BaselineRulesAnnotation is set on rendered roles that contain baseline rules.
+// Its value is a JSON list of the baseline PolicyRules merged into the role, so
+// reviewers can tell org-wide conventions apart from observed usage.
+const BaselineRulesAnnotation = "audicia.io/baseline-rules"
+
+// baselineSet maps rule identities (namespace-less) to the baseline rule that
+// produced them, for provenance tracking at render time.
+type baselineSet map[mergeKey]rbacv1.PolicyRule
+
+// baselineFor expands the baseline rules that apply to subject into
+// single-resource ObservedRules, matching the granularity of observed rules.
+// The rules carry no namespace: ServiceAccounts receive them in their home
+// namespace, Users/Groups alongside every namespace with observed activity.
+func (e *Engine) baselineFor(subject audiciav1alpha1.Subject) ([]audiciav1alpha1.ObservedRule, baselineSet) {
+	var rules []audiciav1alpha1.ObservedRule
+	set := make(baselineSet)
+	add := func(r audiciav1alpha1.ObservedRule, pr rbacv1.PolicyRule) {
+		key := mergeKeyForRule(r)
+		if _, ok := set[key]; ok {
+			return
+		}
+		set[key] = pr
+		rules = append(rules, r)
+	}
+
+	for _, b := range e.Baseline {
+		if len(b.SubjectKinds) > 0 && !slices.Contains(b.SubjectKinds, subject.Kind) {
+			continue
+		}
+		verbs := slices.Clone(b.Verbs)
+		slices.Sort(verbs)
+
+		if len(b.NonResourceURLs) > 0 {
+			for _, url := range b.NonResourceURLs {
+				add(audiciav1alpha1.ObservedRule{NonResourceURLs: []string{url}, Verbs: verbs},
+					rbacv1.PolicyRule{NonResourceURLs: []string{url}, Verbs: verbs})
+			}
+			continue
+		}
+
+		groups := b.APIGroups
+		if len(groups) == 0 {
+			groups = []string{""}
+		}
+		for _, g := range groups {
+			for _, res := range b.Resources {
+				add(audiciav1alpha1.ObservedRule{APIGroups: []string{g}, Resources: []string{res}, Verbs: verbs},
+					rbacv1.PolicyRule{APIGroups: []string{g}, Resources: []string{res}, Verbs: verbs})
+			}
+		}
+	}
+	return rules, set
+}
+
+// annotationsFor returns the provenance annotations for a role built from
+// rules, or nil if none of them originate from the baseline.
+func (s baselineSet) annotationsFor(rules []audiciav1alpha1.ObservedRule) map[string]string {
+	if len(s) == 0 {
+		return nil
+	}
+	seen := make(map[mergeKey]bool)
+	var included []rbacv1.PolicyRule
+	for _, r := range rules {
+		key := mergeKeyForRule(r)
+		key.Namespace = ""
+		pr, ok := s[key]
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		included = append(included, pr)
+	}
+	if len(included) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(included)
+	if err != nil {
+		return nil
+	}
+	return map[string]string{BaselineRulesAnnotation: string(data)}
+}
@@ -0,0 +1,131 @@
+package strategy
+
+import (
+	"strings"
+	"testing"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func baselineEngine(rules ...audiciav1alpha1.BaselineRule) *Engine {
+	return NewEngine(audiciav1alpha1.PolicyStrategy{BaselineRules: rules})
+}
+
+var leaseBaseline = audiciav1alpha1.BaselineRule{
+	APIGroups: []string{"coordination.k8s.io"},
+	Resources: []string{"leases"},
+	Verbs:     []string{"update", "get"},
+}
+
+func TestGenerateManifests_BaselineServiceAccountHomeNamespace(t *testing.T) {
+	e := baselineEngine(leaseBaseline)
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "app", Namespace: "team-a"}
+	rules := []audiciav1alpha1.ObservedRule{makeRule("", "pods", "get", "team-a")}
+
+	manifests, err := e.GenerateManifests(subject, rules)
+	if err != nil {
+		t.Fatalf("GenerateManifests() error = %v", err)
+	}
+	if len(manifests) != 2 {
+		t.Fatalf("expected a single Role+RoleBinding, got %d manifests", len(manifests))
+	}
+	role := manifests[0]
+	if missing := manifestsContainAll([]string{role}, "namespace: team-a", "- leases", "- pods", BaselineRulesAnnotation); len(missing) > 0 {
+		t.Errorf("role missing %v:\n%s", missing, role)
+	}
+	if strings.Contains(role, `"resources":["pods"]`) {
+		t.Errorf("observed rule must not be annotated as baseline:\n%s", role)
+	}
+}
+
+func TestGenerateManifests_BaselineKeepsNonStandardVerbs(t *testing.T) {
+	e := baselineEngine(audiciav1alpha1.BaselineRule{
+		APIGroups: []string{"policy"},
+		Resources: []string{"podsecuritypolicies"},
+		Verbs:     []string{"use"},
+	})
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "app", Namespace: "default"}
+
+	manifests, _ := e.GenerateManifests(subject, []audiciav1alpha1.ObservedRule{makeRule("", "pods", "get", "default")})
+	if !manifestsContain(manifests, "- use") {
+		t.Errorf("expected baseline verb 'use' to be kept:\n%s", strings.Join(manifests, "---\n"))
+	}
+}
+
+func TestGenerateManifests_BaselineSubjectKindFilter(t *testing.T) {
+	b := leaseBaseline
+	b.SubjectKinds = []audiciav1alpha1.SubjectKind{audiciav1alpha1.SubjectKindServiceAccount}
+	e := baselineEngine(b)
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "alice"}
+
+	manifests, _ := e.GenerateManifests(subject, []audiciav1alpha1.ObservedRule{makeRule("", "pods", "get", "default")})
+	if manifestsContain(manifests, "leases") || manifestsContain(manifests, BaselineRulesAnnotation) {
+		t.Errorf("baseline restricted to ServiceAccounts leaked into user policy:\n%s", strings.Join(manifests, "---\n"))
+	}
+}
+
+func TestGenerateManifests_BaselineUserEveryNamespace(t *testing.T) {
+	e := baselineEngine(leaseBaseline)
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "alice"}
+	rules := []audiciav1alpha1.ObservedRule{
+		makeRule("", "pods", "get", "ns-a"),
+		makeRule("", "pods", "get", "ns-b"),
+	}
+
+	manifests, _ := e.GenerateManifests(subject, rules)
+	var roles int
+	for _, m := range manifests {
+		if !strings.Contains(m, "\nkind: Role\n") {
+			continue
+		}
+		roles++
+		if !strings.Contains(m, "- leases") || !strings.Contains(m, BaselineRulesAnnotation) {
+			t.Errorf("role missing baseline rule:\n%s", m)
+		}
+	}
+	if roles != 2 {
+		t.Errorf("expected 2 roles, got %d", roles)
+	}
+}
+
+func TestGenerateManifests_BaselineWithoutObservedRules(t *testing.T) {
+	e := baselineEngine(leaseBaseline)
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "app", Namespace: "default"}
+
+	manifests, err := e.GenerateManifests(subject, nil)
+	if err != nil || manifests != nil {
+		t.Errorf("expected no manifests for unobserved subject, got %v (err %v)", manifests, err)
+	}
+}
+
+func TestGenerateManifests_BaselineWhileRulesBelowThresholds(t *testing.T) {
+	e := baselineEngine(leaseBaseline)
+	e.MinCount = 5
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "app", Namespace: "default"}
+
+	manifests, err := e.GenerateManifests(subject, []audiciav1alpha1.ObservedRule{makeRule("", "pods", "get", "default")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !manifestsContain(manifests, "leases") {
+		t.Errorf("baseline rule missing while the observed rule is held back: %v", manifests)
+	}
+	if manifestsContain(manifests, "pods") {
+		t.Error("pods get below minCount entered the manifests")
+	}
+}
+
+func TestBaselineFor_ExpandsAndDeduplicates(t *testing.T) {
+	e := baselineEngine(
+		audiciav1alpha1.BaselineRule{Resources: []string{"configmaps", "secrets"}, Verbs: []string{"get"}},
+		audiciav1alpha1.BaselineRule{Resources: []string{"configmaps"}, Verbs: []string{"list"}},
+		audiciav1alpha1.BaselineRule{NonResourceURLs: []string{"/healthz"}, Verbs: []string{"get"}},
+	)
+	rules, set := e.baselineFor(audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "app"})
+	if len(rules) != 3 || len(set) != 3 {
+		t.Fatalf("expected 3 expanded rules, got %d rules / %d keys: %+v", len(rules), len(set), rules)
+	}
+	if rules[0].APIGroups[0] != "" || rules[0].Resources[0] != "configmaps" {
+		t.Errorf("expected core group default, got %+v", rules[0])
+	}
+}
@@ -0,0 +1,101 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+const (
+	// ConsolidatedRoleLabel marks the ClusterRoles rendered by role
+	// consolidation, so they can be told apart from cluster-wide grants.
+	ConsolidatedRoleLabel = "audicia.io/consolidated"
+
+	// ConsolidatedNamespacesAnnotation lists, comma-separated, the namespaces
+	// a consolidated ClusterRole is bound in.
+	ConsolidatedNamespacesAnnotation = "audicia.io/namespaces"
+
+	// DefaultConsolidationMinNamespaces applies when
+	// spec.policyStrategy.roleConsolidation omits minNamespaces.
+	DefaultConsolidationMinNamespaces = 3
+)
+
+// consolidates reports whether rules spanning n namespaces are rendered as
+// one consolidated ClusterRole.
+func (e *Engine) consolidates(n int) bool {
+	return e.ConsolidateMinNamespaces > 0 && n >= e.ConsolidateMinNamespaces
+}
+
+// generateConsolidated renders one ClusterRole holding the rules of every
+// namespace in grouped, plus shared, and a RoleBinding to it in each of
+// those namespaces.
+func (e *Engine) generateConsolidated(
+	nameBase string,
+	subject audiciav1alpha1.Subject,
+	grouped map[string][]audiciav1alpha1.ObservedRule,
+	shared []audiciav1alpha1.ObservedRule,
+	baseline baselineSet,
+) []string {
+	namespaces := make([]string, 0, len(grouped))
+	for ns := range grouped {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	var rules []audiciav1alpha1.ObservedRule
+	for _, ns := range namespaces {
+		rules = append(rules, grouped[ns]...)
+	}
+	rules = append(rules, shared...)
+
+	annotations := baseline.annotationsFor(rules)
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[ConsolidatedNamespacesAnnotation] = strings.Join(namespaces, ",")
+	roleName := e.roleName(nameBase)
+	meta := e.objectMeta(roleName, "", annotations)
+	if meta.Labels == nil {
+		meta.Labels = make(map[string]string, 1)
+	}
+	meta.Labels[ConsolidatedRoleLabel] = "true"
+
+	role, err := yaml.Marshal(rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: e.APIVersion, Kind: "ClusterRole"},
+		ObjectMeta: meta,
+		Rules:      toPolicyRules(rules),
+	})
+	if err != nil {
+		return nil
+	}
+	manifests := []string{string(role)}
+	for _, ns := range namespaces {
+		binding, err := yaml.Marshal(rbacv1.RoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: e.APIVersion, Kind: "RoleBinding"},
+			ObjectMeta: e.objectMeta(e.bindingName(nameBase), ns, nil),
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacAPIGroup, Kind: "ClusterRole", Name: roleName},
+			Subjects:   []rbacv1.Subject{rbacSubjectFor(subject)},
+		})
+		if err != nil {
+			return nil
+		}
+		manifests = append(manifests, string(binding))
+	}
+	return manifests
+}
+
+// consolidatedNameBase returns the subject part of the name of a subject's
+// consolidated ClusterRole. ClusterRoles are cluster-scoped, so ServiceAccount
+// roles carry their namespace to keep same-named accounts apart.
+func consolidatedNameBase(subject audiciav1alpha1.Subject) string {
+	if subject.Kind == audiciav1alpha1.SubjectKindServiceAccount {
+		return fmt.Sprintf("%s-%s", sanitizeForName(subject.Namespace), subjectNameForRole(subject))
+	}
+	return subjectNameForRole(subject)
+}
@@ -0,0 +1,106 @@
+package strategy
+
+import (
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/yaml"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func consolidatingEngine(minNamespaces int32) *Engine {
+	return NewEngine(audiciav1alpha1.PolicyStrategy{
+		RoleConsolidation: &audiciav1alpha1.RoleConsolidation{MinNamespaces: minNamespaces},
+	})
+}
+
+func TestNewEngine_RoleConsolidationDefault(t *testing.T) {
+	if e := defaultEngine(); e.ConsolidateMinNamespaces != 0 {
+		t.Errorf("ConsolidateMinNamespaces = %d without roleConsolidation, want 0", e.ConsolidateMinNamespaces)
+	}
+	if e := consolidatingEngine(0); e.ConsolidateMinNamespaces != DefaultConsolidationMinNamespaces {
+		t.Errorf("ConsolidateMinNamespaces = %d, want %d", e.ConsolidateMinNamespaces, DefaultConsolidationMinNamespaces)
+	}
+}
+
+func TestGenerateManifests_User_Consolidated(t *testing.T) {
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "alice"}
+	rules := []audiciav1alpha1.ObservedRule{
+		makeRule("", "pods", "get", "team-a"),
+		makeRule("", "pods", "get", "team-b"),
+		makeRule("apps", "deployments", "list", "team-c"),
+		makeRule("", "nodes", "get", ""),
+	}
+
+	manifests, err := consolidatingEngine(3).GenerateManifests(subject, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 4 {
+		t.Fatalf("got %d manifests, want a ClusterRole and 3 RoleBindings", len(manifests))
+	}
+
+	var role rbacv1.ClusterRole
+	if err := yaml.Unmarshal([]byte(manifests[0]), &role); err != nil {
+		t.Fatal(err)
+	}
+	if role.Kind != "ClusterRole" || role.Name != "suggested-alice-role" {
+		t.Errorf("role = %s %s, want ClusterRole suggested-alice-role", role.Kind, role.Name)
+	}
+	if role.Labels[ConsolidatedRoleLabel] != "true" || role.Annotations[ConsolidatedNamespacesAnnotation] != "team-a,team-b,team-c" {
+		t.Errorf("labels = %v, annotations = %v", role.Labels, role.Annotations)
+	}
+	// Identical rules of several namespaces appear once; cluster-scoped
+	// rules join the union as in per-namespace Roles.
+	if len(role.Rules) != 3 {
+		t.Errorf("rules = %v, want pods, deployments and nodes", role.Rules)
+	}
+
+	for i, ns := range []string{"team-a", "team-b", "team-c"} {
+		var binding rbacv1.RoleBinding
+		if err := yaml.Unmarshal([]byte(manifests[i+1]), &binding); err != nil {
+			t.Fatal(err)
+		}
+		if binding.Kind != "RoleBinding" || binding.Namespace != ns || binding.Name != "suggested-alice-binding" {
+			t.Errorf("binding %d = %s %s/%s", i, binding.Kind, binding.Namespace, binding.Name)
+		}
+		if binding.RoleRef.Kind != "ClusterRole" || binding.RoleRef.Name != role.Name {
+			t.Errorf("binding %d roleRef = %+v", i, binding.RoleRef)
+		}
+	}
+
+	// Below the threshold, per-namespace Roles are kept.
+	manifests, err = consolidatingEngine(4).GenerateManifests(subject, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifestsContain(manifests, ConsolidatedRoleLabel) || len(manifests) != 6 {
+		t.Errorf("got %d manifests, want 3 Role+RoleBinding pairs", len(manifests))
+	}
+}
+
+func TestGenerateManifests_SA_Consolidated(t *testing.T) {
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "backend", Namespace: "prod"}
+	rules := []audiciav1alpha1.ObservedRule{
+		makeRule("", "configmaps", "get", "prod"),
+		makeRule("", "configmaps", "get", "shared"),
+		makeNonResourceRule("/metrics", "get"),
+	}
+
+	manifests, err := consolidatingEngine(2).GenerateManifests(subject, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The non-resource ClusterRole and its binding stay as they are.
+	if missing := manifestsContainAll(manifests,
+		"name: suggested-backend-cluster-role",
+		"name: suggested-prod-backend-role",
+		ConsolidatedRoleLabel,
+	); len(missing) > 0 {
+		t.Errorf("missing %v", missing)
+	}
+	if len(manifests) != 5 {
+		t.Errorf("got %d manifests, want 2 for the non-resource URLs, a ClusterRole and 2 RoleBindings", len(manifests))
+	}
+}
@@ -0,0 +1,81 @@
+package strategy
+
+import (
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+// maxIgnoredVerbs bounds status.ignored of a report.
+const maxIgnoredVerbs = 50
+
+// ignoreReason returns why verb of r is dropped from suggested policies, or
+// "" if it is kept. catalog may be nil.
+func ignoreReason(r audiciav1alpha1.ObservedRule, verb string, catalog VerbCatalog) audiciav1alpha1.IgnoredVerbReason {
+	if !allowedVerbs[verb] {
+		return audiciav1alpha1.IgnoredVerbNonStandard
+	}
+	if !supportsVerb(catalog, r, verb) {
+		return audiciav1alpha1.IgnoredVerbUnsupported
+	}
+	return ""
+}
+
+// IgnoredVerbs returns the verbs of rules that the engine leaves out of
+// suggested policies, with their request counts summed across namespaces,
+// most frequent first.
+func (e *Engine) IgnoredVerbs(rules []audiciav1alpha1.ObservedRule) []audiciav1alpha1.IgnoredVerb {
+	var out []audiciav1alpha1.IgnoredVerb
+	index := make(map[string]int)
+	for _, r := range rules {
+		below := !e.meetsThreshold(r)
+		for _, verb := range r.Verbs {
+			reason := ignoreReason(r, verb, e.Verbs)
+			if reason == "" && below {
+				reason = audiciav1alpha1.IgnoredVerbBelowThreshold
+			}
+			if reason == "" {
+				continue
+			}
+			key := strings.Join([]string{
+				strings.Join(r.APIGroups, ","),
+				strings.Join(r.Resources, ","),
+				strings.Join(r.NonResourceURLs, ","),
+				verb,
+				string(reason),
+			}, "|")
+			i, ok := index[key]
+			if !ok {
+				i = len(out)
+				index[key] = i
+				out = append(out, audiciav1alpha1.IgnoredVerb{
+					APIGroups:       r.APIGroups,
+					Resources:       r.Resources,
+					NonResourceURLs: r.NonResourceURLs,
+					Verb:            verb,
+					Reason:          reason,
+				})
+			}
+			out[i].Count += r.Count
+			if out[i].LastSeen == nil || r.LastSeen.After(out[i].LastSeen.Time) {
+				out[i].LastSeen = &metav1.Time{Time: r.LastSeen.Time}
+			}
+		}
+	}
+	slices.SortStableFunc(out, func(a, b audiciav1alpha1.IgnoredVerb) int {
+		switch {
+		case a.Count > b.Count:
+			return -1
+		case a.Count < b.Count:
+			return 1
+		}
+		return 0
+	})
+	if len(out) > maxIgnoredVerbs {
+		out = out[:maxIgnoredVerbs]
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+)
+
+func TestIgnoredVerbs(t *testing.T) {
+	catalog := verbCatalog{"/pods/log": {"get"}}
+	proxyProd := makeRule("", "services/proxy", "proxy", "prod")
+	proxyProd.Count = 3
+	proxyDev := makeRule("", "services/proxy", "proxy", "dev")
+	proxyDev.Count = 4
+	proxyDev.LastSeen = ts(t0.Add(time.Hour))
+	rules := []audiciav1alpha1.ObservedRule{
+		makeRule("", "pods", "get", "prod"),
+		proxyProd,
+		makeRule("", "pods/log", "deletecollection", "prod"),
+		proxyDev,
+		makeRule("example.com", "widgets", "frobnicate", "prod"),
+	}
+
+	e := defaultEngine()
+	e.Verbs = catalog
+	got := e.IgnoredVerbs(rules)
+	if len(got) != 3 {
+		t.Fatalf("IgnoredVerbs() = %+v, want 3 entries", got)
+	}
+	first := got[0]
+	if first.Verb != "proxy" || first.Resources[0] != "services/proxy" || first.Count != 7 ||
+		first.Reason != audiciav1alpha1.IgnoredVerbNonStandard {
+		t.Errorf("first = %+v, want proxy on services/proxy, counted 7 across namespaces", first)
+	}
+	if !first.LastSeen.Time.Equal(t0.Add(time.Hour)) {
+		t.Errorf("LastSeen = %v, want the latest observation", first.LastSeen)
+	}
+	reasons := map[string]audiciav1alpha1.IgnoredVerbReason{}
+	for _, ignored := range got {
+		reasons[ignored.Verb] = ignored.Reason
+	}
+	if reasons["deletecollection"] != audiciav1alpha1.IgnoredVerbUnsupported {
+		t.Errorf("deletecollection reason = %q, want UnsupportedVerb", reasons["deletecollection"])
+	}
+	if reasons["frobnicate"] != audiciav1alpha1.IgnoredVerbNonStandard {
+		t.Errorf("frobnicate reason = %q, want NonStandardVerb", reasons["frobnicate"])
+	}
+
+	if got := defaultEngine().IgnoredVerbs(rules[:1]); got != nil {
+		t.Errorf("IgnoredVerbs() = %+v for suggested verbs only, want nil", got)
+	}
+}
+
+func TestGenerateManifests_Thresholds(t *testing.T) {
+	frequent := makeRule("", "pods", "get", "prod")
+	frequent.Count = 10
+	longLived := makeRule("", "configmaps", "list", "prod")
+	longLived.LastSeen = ts(t0.Add(8 * 24 * time.Hour))
+	oneOff := makeRule("", "secrets", "delete", "prod")
+	rules := []audiciav1alpha1.ObservedRule{frequent, longLived, oneOff}
+
+	e := NewEngine(audiciav1alpha1.PolicyStrategy{MinCount: 5, MinDays: 7})
+	manifests, err := e.GenerateManifests(audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "alice"}, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing := manifestsContainAll(manifests, "pods", "configmaps"); len(missing) > 0 {
+		t.Errorf("manifests lack %v, which meet a threshold", missing)
+	}
+	if manifestsContain(manifests, "secrets") {
+		t.Error("one-off secrets delete entered the manifests")
+	}
+	ignored := e.IgnoredVerbs(rules)
+	if len(ignored) != 1 || ignored[0].Resources[0] != "secrets" || ignored[0].Reason != audiciav1alpha1.IgnoredVerbBelowThreshold {
+		t.Errorf("IgnoredVerbs() = %+v, want secrets delete below threshold", ignored)
+	}
+
+	manifests, err = e.GenerateManifests(audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "alice"}, rules[2:])
+	if err != nil || manifests != nil {
+		t.Errorf("GenerateManifests() = %v, %v, want nothing while every rule is below the thresholds", manifests, err)
+	}
+}
@@ -2,10 +2,14 @@ package strategy
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/normalizer"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
@@ -16,6 +20,21 @@ const (
 	rbacAPIGroup   = "rbac.authorization.k8s.io"
 )
 
+const (
+	// GeneratorVersionAnnotation records the operator version that rendered a manifest.
+	GeneratorVersionAnnotation = "audicia.io/generator-version"
+	// GeneratorCommitAnnotation records the operator commit that rendered a manifest.
+	GeneratorCommitAnnotation = "audicia.io/generator-commit"
+
+	// DefaultNamePrefix starts the names of rendered manifests unless
+	// spec.policyStrategy.naming sets a prefix.
+	DefaultNamePrefix = "suggested-"
+)
+
+// SupportedRBACVersions lists the RBAC API versions the renderer can emit,
+// most preferred first.
+var SupportedRBACVersions = []string{rbacAPIVersion}
+
 // allowedVerbs is the set of standard Kubernetes verbs that Audicia will emit.
 var allowedVerbs = map[string]bool{
 	"get":              true,
@@ -28,19 +47,66 @@ var allowedVerbs = map[string]bool{
 	"deletecollection": true,
 }
 
+// VerbCatalog reports the verbs the API server supports for a resource.
+type VerbCatalog interface {
+	// SupportedVerbs returns the verbs of resource (e.g. "pods/log") in
+	// apiGroup, and false when the resource is unknown.
+	SupportedVerbs(apiGroup, resource string) ([]string, bool)
+}
+
 // Engine applies policy strategy knobs to shape the final RBAC output.
 type Engine struct {
 	ScopeMode audiciav1alpha1.ScopeMode
 	VerbMerge audiciav1alpha1.VerbMerge
 	Wildcards audiciav1alpha1.WildcardMode
+	Baseline  []audiciav1alpha1.BaselineRule
+
+	// ResourceNames controls whether observed resource names restrict rules.
+	ResourceNames audiciav1alpha1.ResourceNamesMode
+
+	// APIVersion is the apiVersion of rendered RBAC manifests.
+	APIVersion string
+
+	// Labels and Annotations are stamped onto every rendered manifest.
+	Labels      map[string]string
+	Annotations map[string]string
+
+	// NamePrefix and NameSuffix enclose the names of rendered manifests
+	// (spec.policyStrategy.naming).
+	NamePrefix string
+	NameSuffix string
+
+	// Generator identifies the operator build in the generator annotations of
+	// every rendered manifest. Empty fields are omitted.
+	Generator audiciav1alpha1.GeneratorInfo
+
+	// Verbs, when set, drops observed verbs the API server does not support
+	// on a resource (e.g., deletecollection on a resource without it), which
+	// the API server would warn about when the role is applied. Verbs of
+	// resources it does not know are kept.
+	Verbs VerbCatalog
+
+	// MinCount and MinDuration, when positive, keep rules observed fewer
+	// times, or over a shorter span, out of rendered manifests. A rule
+	// meeting either enters (spec.policyStrategy.minCount and minDays).
+	MinCount    int64
+	MinDuration time.Duration
+
+	// ConsolidateMinNamespaces, when positive, renders the namespaced rules
+	// of subjects spanning at least this many namespaces as one ClusterRole
+	// bound in each namespace (spec.policyStrategy.roleConsolidation).
+	ConsolidateMinNamespaces int
 }
 
 // NewEngine creates a strategy engine from an AudiciaSource policy strategy.
 func NewEngine(ps audiciav1alpha1.PolicyStrategy) *Engine {
 	e := &Engine{
-		ScopeMode: ps.ScopeMode,
-		VerbMerge: ps.VerbMerge,
-		Wildcards: ps.Wildcards,
+		ScopeMode:     ps.ScopeMode,
+		VerbMerge:     ps.VerbMerge,
+		Wildcards:     ps.Wildcards,
+		Baseline:      ps.BaselineRules,
+		ResourceNames: ps.ResourceNames,
+		APIVersion:    ps.RBACAPIVersion,
 	}
 
 	// Apply defaults.
@@ -53,6 +119,29 @@ func NewEngine(ps audiciav1alpha1.PolicyStrategy) *Engine {
 	if e.Wildcards == "" {
 		e.Wildcards = audiciav1alpha1.WildcardModeForbidden
 	}
+	if e.ResourceNames == "" {
+		e.ResourceNames = audiciav1alpha1.ResourceNamesOmit
+	}
+	if e.APIVersion == "" {
+		e.APIVersion = rbacAPIVersion
+	}
+	e.NamePrefix = DefaultNamePrefix
+	if n := ps.Naming; n != nil {
+		if n.Prefix != nil {
+			e.NamePrefix = *n.Prefix
+		}
+		e.NameSuffix = n.Suffix
+		e.Labels = n.Labels
+		e.Annotations = n.Annotations
+	}
+	e.MinCount = ps.MinCount
+	e.MinDuration = time.Duration(ps.MinDays) * 24 * time.Hour
+	if c := ps.RoleConsolidation; c != nil {
+		e.ConsolidateMinNamespaces = int(c.MinNamespaces)
+		if e.ConsolidateMinNamespaces <= 0 {
+			e.ConsolidateMinNamespaces = DefaultConsolidationMinNamespaces
+		}
+	}
 
 	return e
 }
@@ -63,8 +152,22 @@ func (e *Engine) GenerateManifests(subject audiciav1alpha1.Subject, rules []audi
 		return nil, nil
 	}
 
+	// Hold back rules not observed often or long enough yet.
+	filteredRules := e.applyThresholds(rules)
+
 	// Filter to allowed verbs only.
-	filteredRules := e.filterVerbs(rules)
+	filteredRules = e.filterVerbs(filteredRules)
+
+	// Drop observed resource names unless they are to be emitted.
+	filteredRules = e.applyResourceNames(filteredRules)
+
+	// Inject baseline rules after verb filtering: they are explicit user
+	// intent, not observations, so non-standard verbs are kept as written.
+	baselineRules, baseline := e.baselineFor(subject)
+	filteredRules = append(filteredRules, baselineRules...)
+	if len(filteredRules) == 0 {
+		return nil, nil
+	}
 
 	// Merge verbs for same resource when in Smart mode.
 	filteredRules = e.mergeVerbs(filteredRules)
@@ -76,12 +179,12 @@ func (e *Engine) GenerateManifests(subject audiciav1alpha1.Subject, rules []audi
 	// Role+RoleBinding pairs. A SA in namespace X may access resources in
 	// namespaces Y and Z, so we need a Role in each target namespace.
 	if subject.Kind == audiciav1alpha1.SubjectKindServiceAccount {
-		return e.generatePerNamespace(subject, filteredRules), nil
+		return e.generatePerNamespace(subject, filteredRules, baseline), nil
 	}
 
 	// ClusterScopeAllowed mode: emit one ClusterRole for everything.
 	if e.ScopeMode == audiciav1alpha1.ScopeModeClusterScopeAllowed {
-		return e.generateSingleScope("ClusterRole", "", subject, filteredRules), nil
+		return e.generateSingleScope("ClusterRole", "", subject, filteredRules, baseline), nil
 	}
 
 	// NamespaceStrict mode for Users/Groups: group rules by namespace and generate
@@ -100,7 +203,7 @@ func (e *Engine) GenerateManifests(subject audiciav1alpha1.Subject, rules []audi
 			if ns == "" {
 				kind = "ClusterRole"
 			}
-			return e.generateSingleScope(kind, ns, subject, nsRules), nil
+			return e.generateSingleScope(kind, ns, subject, nsRules, baseline), nil
 		}
 	}
 
@@ -109,47 +212,50 @@ func (e *Engine) GenerateManifests(subject audiciav1alpha1.Subject, rules []audi
 	clusterRules := grouped[""]
 	delete(grouped, "")
 
+	if e.consolidates(len(grouped)) {
+		return e.generateConsolidated(consolidatedNameBase(subject), subject, grouped, clusterRules, baseline), nil
+	}
+
 	for ns, nsRules := range grouped {
 		// Merge cluster-scoped rules into each namespace Role.
 		// Copy nsRules to avoid mutating the original slice's backing array.
 		allRules := make([]audiciav1alpha1.ObservedRule, 0, len(nsRules)+len(clusterRules))
 		allRules = append(allRules, nsRules...)
 		allRules = append(allRules, clusterRules...)
-		nameBase := fmt.Sprintf("suggested-%s-%s", sanitizeForName(subject.Name), ns)
-		roleName := nameBase + "-role"
+		nameBase := fmt.Sprintf("%s-%s", subjectNameForRole(subject), ns)
 
-		manifests = append(manifests, e.renderRole("Role", roleName, ns, allRules))
-		manifests = append(manifests, e.renderBinding("Role", roleName, ns, subject))
+		manifests = append(manifests, e.renderRole("Role", e.roleName(nameBase), ns, allRules, baseline))
+		manifests = append(manifests, e.renderBinding("Role", nameBase, ns, subject))
 	}
 
 	// Only cluster-scoped rules with no namespaced rules.
 	if len(grouped) == 0 && len(clusterRules) > 0 {
-		return e.generateSingleScope("ClusterRole", "", subject, clusterRules), nil
+		return e.generateSingleScope("ClusterRole", "", subject, clusterRules, baseline), nil
 	}
 
 	return manifests, nil
 }
 
 // generateSingleScope renders a single Role/ClusterRole + Binding pair.
-func (e *Engine) generateSingleScope(kind, namespace string, subject audiciav1alpha1.Subject, rules []audiciav1alpha1.ObservedRule) []string {
-	roleName := fmt.Sprintf("suggested-%s-role", sanitizeForName(subject.Name))
+func (e *Engine) generateSingleScope(kind, namespace string, subject audiciav1alpha1.Subject, rules []audiciav1alpha1.ObservedRule, baseline baselineSet) []string {
+	nameBase := subjectNameForRole(subject)
 	return []string{
-		e.renderRole(kind, roleName, namespace, rules),
-		e.renderBinding(kind, roleName, namespace, subject),
+		e.renderRole(kind, e.roleName(nameBase), namespace, rules, baseline),
+		e.renderBinding(kind, nameBase, namespace, subject),
 	}
 }
 
 // generatePerNamespace groups rules by their observed namespace and generates
 // one Role+RoleBinding per target namespace. Cluster-scoped rules (empty
 // namespace) and non-resource URLs get a ClusterRole.
-func (e *Engine) generatePerNamespace(subject audiciav1alpha1.Subject, rules []audiciav1alpha1.ObservedRule) []string {
+func (e *Engine) generatePerNamespace(subject audiciav1alpha1.Subject, rules []audiciav1alpha1.ObservedRule, baseline baselineSet) []string {
 	grouped := groupByNamespace(rules, subject.Namespace)
 
 	// Single namespace: simple path.
 	if len(grouped) == 1 {
 		for ns, nsRules := range grouped {
 			kind := roleKindForNamespace(ns)
-			return e.generateSingleScope(kind, ns, subject, nsRules)
+			return e.generateSingleScope(kind, ns, subject, nsRules, baseline)
 		}
 	}
 
@@ -157,13 +263,16 @@ func (e *Engine) generatePerNamespace(subject audiciav1alpha1.Subject, rules []a
 
 	// Non-resource URLs (namespace key "") get a ClusterRole.
 	if clusterRules, ok := grouped[""]; ok {
-		nameBase := fmt.Sprintf("suggested-%s-cluster", sanitizeForName(subject.Name))
-		roleName := nameBase + "-role"
-		manifests = append(manifests, e.renderRole("ClusterRole", roleName, "", clusterRules))
-		manifests = append(manifests, e.renderBinding("ClusterRole", roleName, "", subject))
+		nameBase := subjectNameForRole(subject) + "-cluster"
+		manifests = append(manifests, e.renderRole("ClusterRole", e.roleName(nameBase), "", clusterRules, baseline))
+		manifests = append(manifests, e.renderBinding("ClusterRole", nameBase, "", subject))
 		delete(grouped, "")
 	}
 
+	if e.consolidates(len(grouped)) {
+		return append(manifests, e.generateConsolidated(consolidatedNameBase(subject), subject, grouped, nil, baseline)...)
+	}
+
 	// Sort namespace keys for deterministic output.
 	nsKeys := make([]string, 0, len(grouped))
 	for ns := range grouped {
@@ -173,13 +282,12 @@ func (e *Engine) generatePerNamespace(subject audiciav1alpha1.Subject, rules []a
 
 	for _, ns := range nsKeys {
 		nsRules := grouped[ns]
-		nameBase := fmt.Sprintf("suggested-%s", sanitizeForName(subject.Name))
+		nameBase := subjectNameForRole(subject)
 		if ns != subject.Namespace {
-			nameBase = fmt.Sprintf("suggested-%s-%s", sanitizeForName(subject.Name), sanitizeForName(ns))
+			nameBase = fmt.Sprintf("%s-%s", subjectNameForRole(subject), sanitizeForName(ns))
 		}
-		roleName := nameBase + "-role"
-		manifests = append(manifests, e.renderRole("Role", roleName, ns, nsRules))
-		manifests = append(manifests, e.renderBinding("Role", roleName, ns, subject))
+		manifests = append(manifests, e.renderRole("Role", e.roleName(nameBase), ns, nsRules, baseline))
+		manifests = append(manifests, e.renderBinding("Role", nameBase, ns, subject))
 	}
 
 	return manifests
@@ -207,6 +315,15 @@ func roleKindForNamespace(ns string) string {
 	return "Role"
 }
 
+// subjectNameForRole returns the subject part of generated role names.
+// Groups are prefixed so their roles never collide with a same-named user's.
+func subjectNameForRole(subject audiciav1alpha1.Subject) string {
+	if subject.Kind == audiciav1alpha1.SubjectKindGroup {
+		return sanitizeForName("group-" + subject.Name)
+	}
+	return sanitizeForName(subject.Name)
+}
+
 // sanitizeForName produces a Kubernetes-name-safe string.
 func sanitizeForName(name string) string {
 	s := strings.ToLower(name)
@@ -220,16 +337,45 @@ func sanitizeForName(name string) string {
 	return strings.TrimRight(s, "-")
 }
 
+// meetsThreshold reports whether r was observed often or long enough to
+// enter rendered manifests. Without thresholds, every rule does.
+func (e *Engine) meetsThreshold(r audiciav1alpha1.ObservedRule) bool {
+	if e.MinCount <= 0 && e.MinDuration <= 0 {
+		return true
+	}
+	if e.MinCount > 0 && r.Count >= e.MinCount {
+		return true
+	}
+	return e.MinDuration > 0 && r.LastSeen.Sub(r.FirstSeen.Time) >= e.MinDuration
+}
+
+// applyThresholds drops the rules that do not meet the engine's thresholds.
+func (e *Engine) applyThresholds(rules []audiciav1alpha1.ObservedRule) []audiciav1alpha1.ObservedRule {
+	if e.MinCount <= 0 && e.MinDuration <= 0 {
+		return rules
+	}
+	result := make([]audiciav1alpha1.ObservedRule, 0, len(rules))
+	for _, r := range rules {
+		if e.meetsThreshold(r) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
 func (e *Engine) filterVerbs(rules []audiciav1alpha1.ObservedRule) []audiciav1alpha1.ObservedRule {
 	result := make([]audiciav1alpha1.ObservedRule, 0, len(rules))
 	for _, r := range rules {
 		filtered := r
 		var validVerbs []string
 		for _, v := range r.Verbs {
-			if allowedVerbs[v] {
+			if ignoreReason(r, v, e.Verbs) == "" {
 				validVerbs = append(validVerbs, v)
 			}
 		}
+		if needsConnectCreate(r, validVerbs) {
+			validVerbs = append(validVerbs, "create")
+		}
 		if len(validVerbs) > 0 {
 			filtered.Verbs = validVerbs
 			result = append(result, filtered)
@@ -238,12 +384,59 @@ func (e *Engine) filterVerbs(rules []audiciav1alpha1.ObservedRule) []audiciav1al
 	return result
 }
 
+// needsConnectCreate reports whether r covers only websocket subresources
+// (pods/exec, pods/attach, pods/portforward) and was observed with "get" but
+// not "create". Kubernetes 1.31 and later authorize those upgrades as
+// "create", so a role granting only the observed "get" would be denied.
+func needsConnectCreate(r audiciav1alpha1.ObservedRule, verbs []string) bool {
+	if len(r.Resources) == 0 || !slices.Contains(verbs, "get") || slices.Contains(verbs, "create") {
+		return false
+	}
+	for _, resource := range r.Resources {
+		if !normalizer.IsWebsocketSubresource(resource) {
+			return false
+		}
+	}
+	return true
+}
+
+// supportsVerb reports whether every resource of r supports verb according
+// to catalog. Without a catalog, and for non-resource URLs and resources the
+// catalog does not know, every verb is supported.
+func supportsVerb(catalog VerbCatalog, r audiciav1alpha1.ObservedRule, verb string) bool {
+	if catalog == nil {
+		return true
+	}
+	for _, group := range r.APIGroups {
+		for _, resource := range r.Resources {
+			if verbs, ok := catalog.SupportedVerbs(group, resource); ok && !slices.Contains(verbs, verb) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// applyResourceNames clears observed resource names unless the engine is in
+// Explicit mode, in which case they are rendered into the PolicyRules.
+func (e *Engine) applyResourceNames(rules []audiciav1alpha1.ObservedRule) []audiciav1alpha1.ObservedRule {
+	if e.ResourceNames == audiciav1alpha1.ResourceNamesExplicit {
+		return rules
+	}
+	for i := range rules {
+		rules[i].ResourceNames = nil
+	}
+	return rules
+}
+
 // mergeKey groups ObservedRules by identity (everything except verb).
+// Rules restricted to different resource names are kept apart.
 type mergeKey struct {
 	APIGroup       string
 	Resource       string
 	NonResourceURL string
 	Namespace      string
+	ResourceNames  string
 }
 
 // mergedRule tracks a rule being merged with its accumulated verb set.
@@ -277,16 +470,16 @@ func (e *Engine) mergeVerbs(rules []audiciav1alpha1.ObservedRule) []audiciav1alp
 
 // mergeKeyForRule builds the deduplication key for an ObservedRule.
 func mergeKeyForRule(r audiciav1alpha1.ObservedRule) mergeKey {
-	key := mergeKey{Namespace: r.Namespace}
+	key := mergeKey{Namespace: r.Namespace, ResourceNames: strings.Join(r.ResourceNames, ",")}
 	if len(r.NonResourceURLs) > 0 {
 		key.NonResourceURL = r.NonResourceURLs[0]
 	} else {
 		if len(r.APIGroups) > 0 {
 			key.APIGroup = r.APIGroups[0]
 		}
-		if len(r.Resources) > 0 {
-			key.Resource = r.Resources[0]
-		}
+		// Rules consolidated with their subresources only merge with rules
+		// on the same resources, so no verb spreads to a subresource.
+		key.Resource = strings.Join(r.Resources, ",")
 	}
 	return key
 }
@@ -370,43 +563,18 @@ func hasAllStandardVerbs(verbs []string) bool {
 	return true
 }
 
-func (e *Engine) renderRole(kind, name, namespace string, rules []audiciav1alpha1.ObservedRule) string {
-	// Convert ObservedRules into RBAC PolicyRules, deduplicating rules that
-	// are identical after dropping the namespace (which PolicyRule doesn't have).
-	seen := make(map[string]bool)
-	var policyRules []rbacv1.PolicyRule
-	for _, r := range rules {
-		var pr rbacv1.PolicyRule
-		if len(r.NonResourceURLs) > 0 {
-			pr = rbacv1.PolicyRule{
-				NonResourceURLs: r.NonResourceURLs,
-				Verbs:           r.Verbs,
-			}
-		} else {
-			pr = rbacv1.PolicyRule{
-				APIGroups: r.APIGroups,
-				Resources: r.Resources,
-				Verbs:     r.Verbs,
-			}
-		}
-		key := policyRuleKey(pr)
-		if seen[key] {
-			continue
-		}
-		seen[key] = true
-		policyRules = append(policyRules, pr)
-	}
+func (e *Engine) renderRole(kind, name, namespace string, rules []audiciav1alpha1.ObservedRule, baseline baselineSet) string {
+	policyRules := toPolicyRules(rules)
+	annotations := baseline.annotationsFor(rules)
 
 	if kind == "ClusterRole" {
 		obj := rbacv1.ClusterRole{
 			TypeMeta: metav1.TypeMeta{
-				APIVersion: rbacAPIVersion,
+				APIVersion: e.APIVersion,
 				Kind:       "ClusterRole",
 			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name: name,
-			},
-			Rules: policyRules,
+			ObjectMeta: e.objectMeta(name, "", annotations),
+			Rules:      policyRules,
 		}
 		data, err := yaml.Marshal(obj)
 		if err != nil {
@@ -417,14 +585,11 @@ func (e *Engine) renderRole(kind, name, namespace string, rules []audiciav1alpha
 
 	obj := rbacv1.Role{
 		TypeMeta: metav1.TypeMeta{
-			APIVersion: rbacAPIVersion,
+			APIVersion: e.APIVersion,
 			Kind:       "Role",
 		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: namespace,
-		},
-		Rules: policyRules,
+		ObjectMeta: e.objectMeta(name, namespace, annotations),
+		Rules:      policyRules,
 	}
 	data, err := yaml.Marshal(obj)
 	if err != nil {
@@ -433,30 +598,48 @@ func (e *Engine) renderRole(kind, name, namespace string, rules []audiciav1alpha
 	return string(data)
 }
 
-func (e *Engine) renderBinding(kind, roleName, namespace string, subject audiciav1alpha1.Subject) string {
-	bindingName := strings.Replace(roleName, "-role", "-binding", 1)
-
-	// Build the RBAC subject.
-	rbacSubject := rbacv1.Subject{
-		Kind: string(subject.Kind),
-		Name: subject.Name,
-	}
-	switch subject.Kind {
-	case audiciav1alpha1.SubjectKindServiceAccount:
-		rbacSubject.Namespace = subject.Namespace
-	case audiciav1alpha1.SubjectKindUser, audiciav1alpha1.SubjectKindGroup:
-		rbacSubject.APIGroup = rbacAPIGroup
+// toPolicyRules converts ObservedRules into RBAC PolicyRules, deduplicating
+// rules that are identical after dropping the namespace (which PolicyRule
+// doesn't have).
+func toPolicyRules(rules []audiciav1alpha1.ObservedRule) []rbacv1.PolicyRule {
+	seen := make(map[string]bool)
+	var policyRules []rbacv1.PolicyRule
+	for _, r := range rules {
+		var pr rbacv1.PolicyRule
+		if len(r.NonResourceURLs) > 0 {
+			pr = rbacv1.PolicyRule{
+				NonResourceURLs: r.NonResourceURLs,
+				Verbs:           r.Verbs,
+			}
+		} else {
+			pr = rbacv1.PolicyRule{
+				APIGroups:     r.APIGroups,
+				Resources:     r.Resources,
+				ResourceNames: r.ResourceNames,
+				Verbs:         r.Verbs,
+			}
+		}
+		key := policyRuleKey(pr)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		policyRules = append(policyRules, pr)
 	}
+	return policyRules
+}
+
+func (e *Engine) renderBinding(kind, nameBase, namespace string, subject audiciav1alpha1.Subject) string {
+	roleName, bindingName := e.roleName(nameBase), e.bindingName(nameBase)
+	rbacSubject := rbacSubjectFor(subject)
 
 	if kind == "ClusterRole" {
 		obj := rbacv1.ClusterRoleBinding{
 			TypeMeta: metav1.TypeMeta{
-				APIVersion: rbacAPIVersion,
+				APIVersion: e.APIVersion,
 				Kind:       "ClusterRoleBinding",
 			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name: bindingName,
-			},
+			ObjectMeta: e.objectMeta(bindingName, "", nil),
 			RoleRef: rbacv1.RoleRef{
 				APIGroup: rbacAPIGroup,
 				Kind:     "ClusterRole",
@@ -473,13 +656,10 @@ func (e *Engine) renderBinding(kind, roleName, namespace string, subject audicia
 
 	obj := rbacv1.RoleBinding{
 		TypeMeta: metav1.TypeMeta{
-			APIVersion: rbacAPIVersion,
+			APIVersion: e.APIVersion,
 			Kind:       "RoleBinding",
 		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      bindingName,
-			Namespace: namespace,
-		},
+		ObjectMeta: e.objectMeta(bindingName, namespace, nil),
 		RoleRef: rbacv1.RoleRef{
 			APIGroup: rbacAPIGroup,
 			Kind:     "Role",
@@ -494,10 +674,74 @@ func (e *Engine) renderBinding(kind, roleName, namespace string, subject audicia
 	return string(data)
 }
 
+// roleName returns the name of the role generated for nameBase, the
+// subject part of the name.
+func (e *Engine) roleName(nameBase string) string {
+	return e.NamePrefix + nameBase + "-role" + e.NameSuffix
+}
+
+// bindingName returns the name of the binding of the role generated for
+// nameBase.
+func (e *Engine) bindingName(nameBase string) string {
+	return e.NamePrefix + nameBase + "-binding" + e.NameSuffix
+}
+
+// rbacSubjectFor converts a subject into the subject of a binding.
+func rbacSubjectFor(subject audiciav1alpha1.Subject) rbacv1.Subject {
+	rbacSubject := rbacv1.Subject{
+		Kind: string(subject.Kind),
+		Name: subject.Name,
+	}
+	switch subject.Kind {
+	case audiciav1alpha1.SubjectKindServiceAccount:
+		rbacSubject.Namespace = subject.Namespace
+	case audiciav1alpha1.SubjectKindUser, audiciav1alpha1.SubjectKindGroup:
+		rbacSubject.APIGroup = rbacAPIGroup
+	}
+	return rbacSubject
+}
+
+// objectMeta builds manifest metadata carrying the engine's labels and
+// annotations. Engine-computed annotations (e.g., baseline rules) take
+// precedence over user-provided ones with the same key.
+func (e *Engine) objectMeta(name, namespace string, annotations map[string]string) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{Name: name, Namespace: namespace}
+	if len(e.Labels) > 0 {
+		meta.Labels = maps.Clone(e.Labels)
+	}
+	generator := e.generatorAnnotations()
+	if n := len(e.Annotations) + len(annotations) + len(generator); n > 0 {
+		meta.Annotations = make(map[string]string, n)
+		maps.Copy(meta.Annotations, e.Annotations)
+		maps.Copy(meta.Annotations, annotations)
+		maps.Copy(meta.Annotations, generator)
+	}
+	return meta
+}
+
+// generatorAnnotations returns the generator annotations for the set fields
+// of e.Generator.
+func (e *Engine) generatorAnnotations() map[string]string {
+	var out map[string]string
+	add := func(key, value string) {
+		if value == "" {
+			return
+		}
+		if out == nil {
+			out = make(map[string]string, 2)
+		}
+		out[key] = value
+	}
+	add(GeneratorVersionAnnotation, e.Generator.Version)
+	add(GeneratorCommitAnnotation, e.Generator.Commit)
+	return out
+}
+
 // policyRuleKey returns a stable string key for deduplicating PolicyRules.
 func policyRuleKey(pr rbacv1.PolicyRule) string {
 	return strings.Join(pr.APIGroups, ",") + "|" +
 		strings.Join(pr.Resources, ",") + "|" +
 		strings.Join(pr.Verbs, ",") + "|" +
-		strings.Join(pr.NonResourceURLs, ",")
+		strings.Join(pr.NonResourceURLs, ",") + "|" +
+		strings.Join(pr.ResourceNames, ",")
 }
@@ -1,12 +1,16 @@
 package strategy
 
 import (
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 )
 
 // --- helpers ---
@@ -82,6 +86,17 @@ func TestSanitizeForName_Basic(t *testing.T) {
 	}
 }
 
+func TestSubjectNameForRole_GroupPrefixed(t *testing.T) {
+	user := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "oidc:devs"}
+	group := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindGroup, Name: "oidc:devs"}
+	if got := subjectNameForRole(user); got != "oidc-devs" {
+		t.Errorf("user: got %q, want %q", got, "oidc-devs")
+	}
+	if got := subjectNameForRole(group); got != "group-oidc-devs" {
+		t.Errorf("group: got %q, want %q", got, "group-oidc-devs")
+	}
+}
+
 func TestSanitizeForName_Truncation(t *testing.T) {
 	long := strings.Repeat("a", 100)
 	got := sanitizeForName(long)
@@ -653,6 +668,57 @@ func TestGenerateManifests_BindingNameConvention(t *testing.T) {
 	}
 }
 
+func TestGenerateManifests_Naming(t *testing.T) {
+	prefix := "rbac-"
+	e := NewEngine(audiciav1alpha1.PolicyStrategy{Naming: &audiciav1alpha1.PolicyNaming{
+		Prefix:      &prefix,
+		Suffix:      "-v1",
+		Labels:      map[string]string{"team": "payments"},
+		Annotations: map[string]string{"ticket": "SEC-42"},
+	}})
+	subject := audiciav1alpha1.Subject{
+		Kind: audiciav1alpha1.SubjectKindUser, Name: "the-role-owner",
+	}
+	rules := []audiciav1alpha1.ObservedRule{
+		makeRule("", "pods", "get", "prod"),
+	}
+
+	manifests, err := e.GenerateManifests(subject, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var role rbacv1.Role
+	var binding rbacv1.RoleBinding
+	if err := yaml.Unmarshal([]byte(manifests[0]), &role); err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal([]byte(manifests[1]), &binding); err != nil {
+		t.Fatal(err)
+	}
+	if role.Name != "rbac-the-role-owner-role-v1" {
+		t.Errorf("role name = %q, want rbac-the-role-owner-role-v1", role.Name)
+	}
+	// A subject name containing "-role" must not leak into the binding name.
+	if binding.Name != "rbac-the-role-owner-binding-v1" || binding.RoleRef.Name != role.Name {
+		t.Errorf("binding %q refs %q, want rbac-the-role-owner-binding-v1 refs %q", binding.Name, binding.RoleRef.Name, role.Name)
+	}
+	for _, meta := range []metav1.ObjectMeta{role.ObjectMeta, binding.ObjectMeta} {
+		if meta.Labels["team"] != "payments" || meta.Annotations["ticket"] != "SEC-42" {
+			t.Errorf("%s: labels %v, annotations %v, want the naming metadata", meta.Name, meta.Labels, meta.Annotations)
+		}
+	}
+
+	empty := ""
+	e = NewEngine(audiciav1alpha1.PolicyStrategy{Naming: &audiciav1alpha1.PolicyNaming{Prefix: &empty}})
+	manifests, err = e.GenerateManifests(subject, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !manifestsContain(manifests, "name: the-role-owner-role\n") {
+		t.Errorf("expected the unprefixed role name the-role-owner-role:\n%s", manifests[0])
+	}
+}
+
 // --- RoleBinding references correct Role ---
 
 func TestGenerateManifests_BindingRefsCorrectRole(t *testing.T) {
@@ -1010,6 +1076,74 @@ func TestFilterVerbs_NonStandardVerbOnNonResourceURL(t *testing.T) {
 	}
 }
 
+// --- filterVerbs: websocket subresources also need create ---
+
+func TestFilterVerbs_WebsocketSubresourceAddsCreate(t *testing.T) {
+	e := defaultEngine()
+	rules := []audiciav1alpha1.ObservedRule{
+		makeRule("", "pods/exec", "get", "default"),
+		makeRule("", "pods/log", "get", "default"),
+		{
+			APIGroups: []string{""},
+			Resources: []string{"pods", "pods/exec"},
+			Verbs:     []string{"get"},
+			Namespace: "default",
+		},
+	}
+	result := e.filterVerbs(rules)
+	if want := []string{"get", "create"}; !slices.Equal(result[0].Verbs, want) {
+		t.Errorf("pods/exec verbs = %v, want %v", result[0].Verbs, want)
+	}
+	if !slices.Equal(result[1].Verbs, []string{"get"}) {
+		t.Errorf("pods/log verbs = %v, want [get]", result[1].Verbs)
+	}
+	// A rule consolidated with its parent would spread create to pods.
+	if !slices.Equal(result[2].Verbs, []string{"get"}) {
+		t.Errorf("consolidated rule verbs = %v, want [get]", result[2].Verbs)
+	}
+
+	manifests, err := e.GenerateManifests(audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "alice"}, rules[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing := manifestsContainAll(manifests, "- pods/exec", "- pods/log", "- create"); len(missing) > 0 {
+		t.Errorf("manifests missing %v", missing)
+	}
+}
+
+// --- filterVerbs: verbs the API server does not support are dropped ---
+
+type verbCatalog map[string][]string
+
+func (c verbCatalog) SupportedVerbs(apiGroup, resource string) ([]string, bool) {
+	v, ok := c[apiGroup+"/"+resource]
+	return v, ok
+}
+
+func TestFilterVerbs_VerbCatalog(t *testing.T) {
+	e := defaultEngine()
+	e.Verbs = verbCatalog{
+		"/pods/log":              {"get"},
+		"apps/deployments/scale": {"get", "patch", "update"},
+	}
+	rules := []audiciav1alpha1.ObservedRule{
+		makeRule("", "pods/log", "get", "prod"),
+		makeRule("", "pods/log", "deletecollection", "prod"),
+		makeRule("apps", "deployments/scale", "update", "prod"),
+		makeRule("example.com", "widgets", "deletecollection", "prod"),
+		makeNonResourceRule("/metrics", "get"),
+	}
+	result := e.filterVerbs(rules)
+	var got []string
+	for _, r := range result {
+		got = append(got, strings.Join(append(append(r.Resources, r.NonResourceURLs...), r.Verbs...), ":"))
+	}
+	want := []string{"pods/log:get", "deployments/scale:update", "widgets:deletecollection", "/metrics:get"}
+	if !slices.Equal(got, want) {
+		t.Errorf("rules = %v, want %v", got, want)
+	}
+}
+
 // --- mergeVerbs: Exact mode is no-op ---
 
 func TestMergeVerbs_ExactMode_NoOp(t *testing.T) {
@@ -1040,6 +1174,19 @@ func TestMergeVerbs_DifferentResourcesNotMerged(t *testing.T) {
 	}
 }
 
+// --- mergeVerbs: consolidated subresources keep their own verbs ---
+
+func TestMergeVerbs_ConsolidatedSubresourcesNotMerged(t *testing.T) {
+	e := defaultEngine()
+	consolidated := makeRule("", "pods", "get", "default")
+	consolidated.Resources = []string{"pods", "pods/status"}
+	rules := []audiciav1alpha1.ObservedRule{consolidated, makeRule("", "pods", "list", "default")}
+	result := e.mergeVerbs(rules)
+	if len(result) != 2 {
+		t.Errorf("list on pods must not spread to pods/status: got %d rules, want 2", len(result))
+	}
+}
+
 // --- mergeVerbs: different namespaces stay separate ---
 
 func TestMergeVerbs_DifferentNamespacesNotMerged(t *testing.T) {
@@ -1164,7 +1311,7 @@ func TestRenderRole_CrossNamespaceDedup(t *testing.T) {
 		makeRule("", "pods", "get", "staging"),
 	}
 
-	yaml := e.renderRole("Role", "test-role", "prod", rules)
+	yaml := e.renderRole("Role", "test-role", "prod", rules, nil)
 	count := strings.Count(yaml, "- apiGroups:")
 	if count != 1 {
 		t.Errorf("expected 1 PolicyRule after dedup, got %d.\nYAML:\n%s", count, yaml)
@@ -1209,3 +1356,116 @@ func TestApplyWildcards_SafeMode_PartialVerbsNoWildcard(t *testing.T) {
 		t.Errorf("partial verb set should not be collapsed: got %v", result[0].Verbs)
 	}
 }
+
+// --- Output metadata ---
+
+func TestGenerateManifests_OutputMetadata(t *testing.T) {
+	e := NewEngine(audiciav1alpha1.PolicyStrategy{BaselineRules: []audiciav1alpha1.BaselineRule{{
+		APIGroups: []string{"coordination.k8s.io"},
+		Resources: []string{"leases"},
+		Verbs:     []string{"get"},
+	}}})
+	e.Labels = map[string]string{"env": "prod"}
+	e.Annotations = map[string]string{"cluster": "eu-1", BaselineRulesAnnotation: "user-value"}
+
+	subject := audiciav1alpha1.Subject{
+		Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "backend", Namespace: "prod",
+	}
+	manifests, err := e.GenerateManifests(subject, []audiciav1alpha1.ObservedRule{makeRule("", "pods", "get", "prod")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range manifests {
+		if missing := manifestsContainAll([]string{m}, "env: prod", "cluster: eu-1"); len(missing) > 0 {
+			t.Errorf("manifest missing %v:\n%s", missing, m)
+		}
+		if strings.Contains(m, "\nkind: Role\n") && strings.Contains(m, "user-value") {
+			t.Errorf("user annotation overrode engine annotation:\n%s", m)
+		}
+	}
+}
+
+func TestGenerateManifests_GeneratorAnnotations(t *testing.T) {
+	subject := audiciav1alpha1.Subject{Kind: audiciav1alpha1.SubjectKindUser, Name: "alice"}
+	rules := []audiciav1alpha1.ObservedRule{makeRule("", "pods", "get", "prod")}
+
+	e := NewEngine(audiciav1alpha1.PolicyStrategy{})
+	e.Generator = audiciav1alpha1.GeneratorInfo{Version: "v0.6.0", Commit: "abc1234"}
+	manifests, err := e.GenerateManifests(subject, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range manifests {
+		want := []string{GeneratorVersionAnnotation + ": v0.6.0", GeneratorCommitAnnotation + ": abc1234"}
+		if missing := manifestsContainAll([]string{m}, want...); len(missing) > 0 {
+			t.Errorf("manifest missing %v:\n%s", missing, m)
+		}
+	}
+
+	// Without build information, no generator annotations are rendered.
+	manifests, err = NewEngine(audiciav1alpha1.PolicyStrategy{}).GenerateManifests(subject, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range manifests {
+		if strings.Contains(m, "audicia.io/generator") {
+			t.Errorf("unexpected generator annotation:\n%s", m)
+		}
+	}
+}
+
+// --- resourceNames ---
+
+func namedRule(verb, name string) audiciav1alpha1.ObservedRule {
+	r := makeRule("", "configmaps", verb, "prod")
+	if name != "" {
+		r.ResourceNames = []string{name}
+	}
+	return r
+}
+
+func TestGenerateManifests_ResourceNames(t *testing.T) {
+	subject := audiciav1alpha1.Subject{
+		Kind: audiciav1alpha1.SubjectKindServiceAccount, Name: "controller", Namespace: "prod",
+	}
+	rules := []audiciav1alpha1.ObservedRule{
+		namedRule("get", "app-config"),
+		namedRule("update", "app-config"),
+		namedRule("list", ""),
+	}
+
+	tests := []struct {
+		mode audiciav1alpha1.ResourceNamesMode
+		want []rbacv1.PolicyRule
+	}{
+		{
+			mode: "",
+			want: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get", "list", "update"}},
+			},
+		},
+		{
+			mode: audiciav1alpha1.ResourceNamesExplicit,
+			want: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"app-config"}, Verbs: []string{"get", "update"}},
+				{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"list"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			e := NewEngine(audiciav1alpha1.PolicyStrategy{ResourceNames: tt.mode})
+			manifests, err := e.GenerateManifests(subject, rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var role rbacv1.Role
+			if err := yaml.Unmarshal([]byte(manifests[0]), &role); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(role.Rules, tt.want) {
+				t.Errorf("rules = %+v, want %+v", role.Rules, tt.want)
+			}
+		})
+	}
+}
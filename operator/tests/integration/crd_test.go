@@ -0,0 +1,126 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/tests/integration/harness"
+)
+
+func TestCRDValidation_RejectsInvalidSources(t *testing.T) {
+	ctx := context.Background()
+	ns := env.Namespace(t)
+	log := harness.NewAuditLog(t)
+
+	tests := []struct {
+		name   string
+		mutate func(*audiciav1alpha1.AudiciaSource)
+	}{
+		{"unknown source type", func(s *audiciav1alpha1.AudiciaSource) { s.Spec.SourceType = "Syslogd" }},
+		{"checkpoint interval below minimum", func(s *audiciav1alpha1.AudiciaSource) { s.Spec.Checkpoint.IntervalSeconds = 1 }},
+		{"batch size below minimum", func(s *audiciav1alpha1.AudiciaSource) { s.Spec.Checkpoint.BatchSize = -1 }},
+		{"role consolidation below two namespaces", func(s *audiciav1alpha1.AudiciaSource) {
+			s.Spec.PolicyStrategy.RoleConsolidation = &audiciav1alpha1.RoleConsolidation{MinNamespaces: 1}
+		}},
+		{"cloud settings of another provider", func(s *audiciav1alpha1.AudiciaSource) {
+			s.Spec.SourceType = audiciav1alpha1.SourceTypeCloudAuditLog
+			s.Spec.Location = nil
+			s.Spec.Cloud = &audiciav1alpha1.CloudConfig{
+				Provider:        audiciav1alpha1.CloudProviderGCPPubSub,
+				ClusterIdentity: "projects/p/locations/l/clusters/c",
+				GCP:             &audiciav1alpha1.GCPPubSubConfig{ProjectID: "p", SubscriptionID: "s"},
+				Azure:           &audiciav1alpha1.AzureEventHubConfig{EventHubNamespace: "ns.servicebus.windows.net", EventHubName: "audit"},
+			}
+		}},
+		{"agent summaries without authentication", func(s *audiciav1alpha1.AudiciaSource) {
+			s.Spec.SourceType = audiciav1alpha1.SourceTypeWebhook
+			s.Spec.Location = nil
+			s.Spec.Webhook = &audiciav1alpha1.WebhookConfig{TLSSecretName: "webhook-tls", AgentSummaries: true}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := harness.FileSource(ns, "invalid", log)
+			tt.mutate(source)
+			err := env.Client.Create(ctx, source)
+			if err == nil {
+				_ = env.Client.Delete(ctx, source)
+				t.Fatal("create succeeded, want the API server to reject the source")
+			}
+			if !apierrors.IsInvalid(err) {
+				t.Errorf("error = %v, want Invalid", err)
+			}
+		})
+	}
+}
+
+func TestCRDValidation_AppliesDefaults(t *testing.T) {
+	ctx := context.Background()
+	ns := env.Namespace(t)
+
+	source := harness.FileSource(ns, "defaults", harness.NewAuditLog(t))
+	source.Spec.Checkpoint = audiciav1alpha1.CheckpointConfig{}
+	if err := env.Client.Create(ctx, source); err != nil {
+		t.Fatal(err)
+	}
+
+	var got audiciav1alpha1.AudiciaSource
+	if err := env.Client.Get(ctx, client.ObjectKeyFromObject(source), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Spec.Checkpoint.IntervalSeconds != 30 || got.Spec.Checkpoint.BatchSize != 500 {
+		t.Errorf("checkpoint = %+v, want the CRD defaults 30s and 500", got.Spec.Checkpoint)
+	}
+}
+
+func TestStatusSubresource(t *testing.T) {
+	ctx := context.Background()
+	ns := env.Namespace(t)
+
+	source := harness.FileSource(ns, "status", harness.NewAuditLog(t))
+	if err := env.Client.Create(ctx, source); err != nil {
+		t.Fatal(err)
+	}
+	key := types.NamespacedName{Namespace: ns, Name: "status"}
+	generation := source.Generation
+
+	// Status written through the main resource is dropped.
+	source.Status.FileOffset = 42
+	if err := env.Client.Update(ctx, source); err != nil {
+		t.Fatal(err)
+	}
+	var got audiciav1alpha1.AudiciaSource
+	if err := env.Client.Get(ctx, key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.FileOffset != 0 {
+		t.Errorf("status.fileOffset = %d after a spec update, want 0", got.Status.FileOffset)
+	}
+
+	// Spec written through the status subresource is dropped, and status
+	// writes leave the generation alone.
+	got.Status.FileOffset = 42
+	meta.SetStatusCondition(&got.Status.Conditions, metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Test"})
+	got.Spec.Checkpoint.IntervalSeconds = 60
+	if err := env.Client.Status().Update(ctx, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.Client.Get(ctx, key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.FileOffset != 42 || !meta.IsStatusConditionTrue(got.Status.Conditions, "Ready") {
+		t.Errorf("status = %+v, want the status update applied", got.Status)
+	}
+	if got.Spec.Checkpoint.IntervalSeconds != 5 || got.Generation != generation {
+		t.Errorf("spec interval = %d, generation = %d, want 5 and %d", got.Spec.Checkpoint.IntervalSeconds, got.Generation, generation)
+	}
+}
@@ -0,0 +1,304 @@
+// Package harness runs the Audicia controllers against a real API server
+// started by envtest, for integration tests that need CRD validation, status
+// subresources, owner references or optimistic concurrency but not a kind
+// cluster. Nodes, kubelets and the garbage collector are absent: pipelines
+// read audit logs written by the test instead.
+//
+// The API server and etcd binaries are found through KUBEBUILDER_ASSETS, as
+// installed by `make envtest`. Without them, Available reports false and
+// suites skip.
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/yaml"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/pkg/controller/audiciasource"
+	"github.com/felixnotka/audicia/operator/pkg/schema"
+)
+
+// Timeouts of the wait helpers. Flushes follow the checkpoint interval of
+// the source, at least 5 seconds.
+const (
+	DefaultTimeout = 60 * time.Second
+	PollInterval   = 250 * time.Millisecond
+)
+
+// defaultBinaryDir is where envtest looks for its binaries without
+// KUBEBUILDER_ASSETS.
+const defaultBinaryDir = "/usr/local/kubebuilder/bin"
+
+// Available reports whether the envtest binaries are installed, or an
+// existing cluster was requested with USE_EXISTING_CLUSTER=true.
+func Available() bool {
+	if os.Getenv("USE_EXISTING_CLUSTER") == "true" {
+		return true
+	}
+	dir := os.Getenv("KUBEBUILDER_ASSETS")
+	if dir == "" {
+		dir = defaultBinaryDir
+	}
+	_, err := os.Stat(filepath.Join(dir, "kube-apiserver"))
+	return err == nil
+}
+
+// Env is a running API server with the Audicia CRDs installed.
+type Env struct {
+	Config *rest.Config
+	Scheme *runtime.Scheme
+
+	// Client talks to the API server directly, without a cache, so tests
+	// read their own writes.
+	Client client.Client
+
+	env *envtest.Environment
+}
+
+// Start starts the API server and installs the CRDs embedded in the
+// operator, exactly as the Helm chart ships them.
+func Start() (*Env, error) {
+	crds, err := CRDs()
+	if err != nil {
+		return nil, err
+	}
+	env := &envtest.Environment{
+		CRDInstallOptions: envtest.CRDInstallOptions{CRDs: crds},
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		return nil, fmt.Errorf("starting envtest: %w", err)
+	}
+
+	s := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(s))
+	utilruntime.Must(audiciav1alpha1.AddToScheme(s))
+	c, err := client.New(cfg, client.Options{Scheme: s})
+	if err != nil {
+		_ = env.Stop()
+		return nil, err
+	}
+	return &Env{Config: cfg, Scheme: s, Client: c, env: env}, nil
+}
+
+// Stop stops the API server.
+func (e *Env) Stop() error {
+	return e.env.Stop()
+}
+
+// CRDs decodes the CRDs embedded in the operator.
+func CRDs() ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	manifests, err := schema.CRDs()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*apiextensionsv1.CustomResourceDefinition, 0, len(manifests))
+	for _, data := range manifests {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := yaml.Unmarshal(data, crd); err != nil {
+			return nil, fmt.Errorf("decoding CRD: %w", err)
+		}
+		out = append(out, crd)
+	}
+	return out, nil
+}
+
+var namespaceSeq struct {
+	sync.Mutex
+	n int
+}
+
+// Namespace creates a namespace for the test and deletes it at cleanup.
+// Without a namespace controller, deleted namespaces stay Terminating, so
+// every test gets a fresh name.
+func (e *Env) Namespace(t testing.TB) string {
+	t.Helper()
+	namespaceSeq.Lock()
+	namespaceSeq.n++
+	name := fmt.Sprintf("it-%d-%d", os.Getpid()%10000, namespaceSeq.n)
+	namespaceSeq.Unlock()
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if err := e.Client.Create(context.Background(), ns); err != nil {
+		t.Fatalf("create namespace %s: %v", name, err)
+	}
+	t.Cleanup(func() { _ = e.Client.Delete(context.Background(), ns) })
+	return name
+}
+
+// OperatorOptions configures StartOperator.
+type OperatorOptions struct {
+	// DeferCompliance leaves compliance evaluation to compliance workers,
+	// which StartOperator does not run.
+	DeferCompliance bool
+
+	// Generator is stamped on generated artifacts.
+	Generator audiciav1alpha1.GeneratorInfo
+}
+
+// StartOperator runs a manager with the AudiciaSource controller until the
+// test ends. Only one operator should run at a time, or both reconcile the
+// same sources.
+func (e *Env) StartOperator(t testing.TB, opts OperatorOptions) {
+	t.Helper()
+	mgr, err := ctrl.NewManager(e.Config, ctrl.Options{
+		Scheme:  e.Scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		// Every test starts its own manager with the same controllers.
+		Controller: config.Controller{SkipNameValidation: ptr.To(true)},
+	})
+	if err != nil {
+		t.Fatalf("create manager: %v", err)
+	}
+	if err := audiciasource.SetupWithManager(mgr, audiciasource.SetupOptions{
+		DeferCompliance: opts.DeferCompliance,
+		Generator:       opts.Generator,
+	}); err != nil {
+		t.Fatalf("set up AudiciaSource controller: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- mgr.Start(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("manager: %v", err)
+		}
+	})
+}
+
+// AuditLog is an audit log file the pipelines of File sources tail.
+type AuditLog struct {
+	Path string
+}
+
+// NewAuditLog creates an empty audit log in the test's temp directory.
+func NewAuditLog(t testing.TB) *AuditLog {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return &AuditLog{Path: path}
+}
+
+// Append writes events to the log, one JSON line each, as the API server's
+// log backend does.
+func (l *AuditLog) Append(t testing.TB, events ...auditv1.Event) {
+	t.Helper()
+	f, err := os.OpenFile(l.Path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	enc := json.NewEncoder(f)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// FileSource returns an AudiciaSource tailing log, checkpointing at the
+// minimum interval so tests see flushes quickly.
+func FileSource(namespace, name string, log *AuditLog) *audiciav1alpha1.AudiciaSource {
+	return &audiciav1alpha1.AudiciaSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: audiciav1alpha1.AudiciaSourceSpec{
+			SourceType: audiciav1alpha1.SourceTypeK8sAuditLog,
+			Location:   &audiciav1alpha1.FileLocation{Path: log.Path},
+			Checkpoint: audiciav1alpha1.CheckpointConfig{IntervalSeconds: 5, BatchSize: 500},
+		},
+	}
+}
+
+// Event returns a completed, allowed audit event of user acting on a
+// namespaced resource.
+func Event(user, verb, resource, namespace string) auditv1.Event {
+	now := metav1.NewMicroTime(time.Now())
+	return auditv1.Event{
+		TypeMeta:                 metav1.TypeMeta{Kind: "Event", APIVersion: "audit.k8s.io/v1"},
+		Level:                    auditv1.LevelMetadata,
+		AuditID:                  types.UID(fmt.Sprintf("%s-%s-%s-%d", user, verb, resource, now.UnixNano())),
+		Stage:                    auditv1.StageResponseComplete,
+		Verb:                     verb,
+		User:                     authnv1.UserInfo{Username: user},
+		ObjectRef:                &auditv1.ObjectReference{Resource: resource, Namespace: namespace, APIVersion: "v1"},
+		ResponseStatus:           &metav1.Status{Code: 200},
+		RequestReceivedTimestamp: now,
+		StageTimestamp:           now,
+	}
+}
+
+// Eventually polls cond until it returns true, failing the test after
+// timeout. Errors from cond are retried; the last one is reported.
+func Eventually(t testing.TB, timeout time.Duration, cond func(ctx context.Context) (bool, error)) {
+	t.Helper()
+	var last error
+	err := wait.PollUntilContextTimeout(context.Background(), PollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		ok, err := cond(ctx)
+		if err != nil {
+			last = err
+			return false, nil
+		}
+		return ok, nil
+	})
+	if err != nil {
+		t.Fatalf("condition not met within %s: %v", timeout, errors.Join(err, last))
+	}
+}
+
+// WaitForReport waits for the AudiciaReport key to exist and satisfy cond,
+// and returns it. A nil cond accepts any report.
+func (e *Env) WaitForReport(t testing.TB, key types.NamespacedName, cond func(*audiciav1alpha1.AudiciaReport) bool) *audiciav1alpha1.AudiciaReport {
+	t.Helper()
+	report := &audiciav1alpha1.AudiciaReport{}
+	Eventually(t, DefaultTimeout, func(ctx context.Context) (bool, error) {
+		if err := e.Client.Get(ctx, key, report); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		return cond == nil || cond(report), nil
+	})
+	return report
+}
+
+// WaitForSource waits for the AudiciaSource key to satisfy cond, and returns
+// it.
+func (e *Env) WaitForSource(t testing.TB, key types.NamespacedName, cond func(*audiciav1alpha1.AudiciaSource) bool) *audiciav1alpha1.AudiciaSource {
+	t.Helper()
+	source := &audiciav1alpha1.AudiciaSource{}
+	Eventually(t, DefaultTimeout, func(ctx context.Context) (bool, error) {
+		if err := e.Client.Get(ctx, key, source); err != nil {
+			return false, err
+		}
+		return cond(source), nil
+	})
+	return source
+}
@@ -0,0 +1,126 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	audiciav1alpha1 "github.com/felixnotka/audicia/operator/pkg/apis/audicia.io/v1alpha1"
+	"github.com/felixnotka/audicia/operator/tests/integration/harness"
+)
+
+func TestPipeline_FileSourceWritesOwnedReport(t *testing.T) {
+	ctx := context.Background()
+	ns := env.Namespace(t)
+	env.StartOperator(t, harness.OperatorOptions{})
+
+	log := harness.NewAuditLog(t)
+	log.Append(t,
+		harness.Event("alice", "get", "pods", ns),
+		harness.Event("alice", "list", "configmaps", ns),
+	)
+	source := harness.FileSource(ns, "file", log)
+	if err := env.Client.Create(ctx, source); err != nil {
+		t.Fatal(err)
+	}
+
+	report := env.WaitForReport(t, types.NamespacedName{Namespace: ns, Name: "report-alice"}, func(r *audiciav1alpha1.AudiciaReport) bool {
+		return len(r.Status.ObservedRules) >= 2
+	})
+	if report.Spec.Subject.Name != "alice" || report.Spec.Subject.Kind != audiciav1alpha1.SubjectKindUser {
+		t.Errorf("subject = %+v, want User alice", report.Spec.Subject)
+	}
+	owners := report.GetOwnerReferences()
+	if len(owners) != 1 || owners[0].UID != source.UID || owners[0].Controller == nil || !*owners[0].Controller {
+		t.Errorf("owner references = %+v, want the source as controller", owners)
+	}
+
+	got := env.WaitForSource(t, client.ObjectKeyFromObject(source), func(s *audiciav1alpha1.AudiciaSource) bool {
+		return s.Status.LastCheckpointTime != nil
+	})
+	if !meta.IsStatusConditionTrue(got.Status.Conditions, "Ready") {
+		t.Errorf("conditions = %+v, want Ready", got.Status.Conditions)
+	}
+	if got.Status.FileOffset == 0 {
+		t.Error("status.fileOffset = 0 after the checkpoint, want the log read")
+	}
+
+	// Events appended later reach the same report.
+	log.Append(t, harness.Event("alice", "delete", "secrets", ns))
+	env.WaitForReport(t, types.NamespacedName{Namespace: ns, Name: "report-alice"}, func(r *audiciav1alpha1.AudiciaReport) bool {
+		return len(r.Status.ObservedRules) >= 3
+	})
+}
+
+// TestPipeline_StatusWritesRetryOnConflict changes the source and its report
+// while the pipeline checkpoints and flushes, so the operator's status writes
+// race with other writers and must retry on conflicts.
+func TestPipeline_StatusWritesRetryOnConflict(t *testing.T) {
+	ctx := context.Background()
+	ns := env.Namespace(t)
+	env.StartOperator(t, harness.OperatorOptions{})
+
+	log := harness.NewAuditLog(t)
+	log.Append(t, harness.Event("bob", "get", "pods", ns))
+	source := harness.FileSource(ns, "contended", log)
+	if err := env.Client.Create(ctx, source); err != nil {
+		t.Fatal(err)
+	}
+	sourceKey := client.ObjectKeyFromObject(source)
+	reportKey := types.NamespacedName{Namespace: ns, Name: "report-bob"}
+	env.WaitForReport(t, reportKey, nil)
+
+	// Label updates bump the resourceVersion without changing the
+	// generation, so the pipeline keeps running on stale copies.
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, obj := range []client.Object{&audiciav1alpha1.AudiciaSource{}, &audiciav1alpha1.AudiciaReport{}} {
+		key := sourceKey
+		if _, ok := obj.(*audiciav1alpha1.AudiciaReport); ok {
+			key = reportKey
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				case <-time.After(20 * time.Millisecond):
+				}
+				_ = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+					if err := env.Client.Get(ctx, key, obj); err != nil {
+						return err
+					}
+					obj.SetLabels(map[string]string{"integration.audicia.io/touch": strconv.Itoa(i)})
+					return env.Client.Update(ctx, obj)
+				})
+			}
+		}()
+	}
+	defer func() {
+		close(stop)
+		wg.Wait()
+	}()
+
+	before := env.WaitForSource(t, sourceKey, func(s *audiciav1alpha1.AudiciaSource) bool {
+		return s.Status.LastCheckpointTime != nil
+	}).Status.LastCheckpointTime.Time
+
+	log.Append(t, harness.Event("bob", "list", "secrets", ns))
+	env.WaitForReport(t, reportKey, func(r *audiciav1alpha1.AudiciaReport) bool {
+		return len(r.Status.ObservedRules) >= 2
+	})
+	env.WaitForSource(t, sourceKey, func(s *audiciav1alpha1.AudiciaSource) bool {
+		return s.Status.LastCheckpointTime != nil && s.Status.LastCheckpointTime.After(before)
+	})
+}
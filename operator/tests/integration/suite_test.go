@@ -0,0 +1,38 @@
+//go:build integration
+
+package integration
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/felixnotka/audicia/operator/tests/integration/harness"
+)
+
+// env is the API server shared by the tests of the suite. Tests isolate
+// themselves by namespace.
+var env *harness.Env
+
+func TestMain(m *testing.M) {
+	if !harness.Available() {
+		fmt.Println("envtest binaries not found, skipping integration tests; run `make envtest` and set KUBEBUILDER_ASSETS")
+		os.Exit(0)
+	}
+	ctrl.SetLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(os.Stderr)))
+
+	var err error
+	env, err = harness.Start()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	if err := env.Stop(); err != nil {
+		fmt.Fprintln(os.Stderr, "stopping envtest:", err)
+	}
+	os.Exit(code)
+}
@@ -48,9 +48,13 @@ export const DOCS_NAV: NavSection[] = [
     pages: [
       { slug: "audit-policy", title: "Audit Policy" },
       { slug: "webhook-setup", title: "Webhook Setup" },
+      { slug: "forward-setup", title: "Forward Setup (Fluent Bit / syslog)" },
+      { slug: "grpc-setup", title: "gRPC Push Setup" },
+      { slug: "agent-setup", title: "Edge Agent Setup" },
       { slug: "aks-setup", title: "AKS Setup (Event Hub)" },
       { slug: "eks-setup", title: "EKS Setup (CloudWatch Logs)" },
       { slug: "gke-setup", title: "GKE Setup (Pub/Sub)" },
+      { slug: "kafka-setup", title: "Kafka Setup" },
     ],
   },
   {
@@ -58,6 +62,7 @@ export const DOCS_NAV: NavSection[] = [
     slug: "guides",
     pages: [
       { slug: "filter-recipes", title: "Filter Recipes" },
+      { slug: "sizing", title: "Sizing and Benchmarks" },
       { slug: "demo-walkthrough", title: "Demo Walkthrough" },
       { slug: "upgrading-to-0.5", title: "Upgrading to 0.5.0" },
     ],